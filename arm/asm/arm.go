@@ -0,0 +1,844 @@
+package asm
+
+import (
+	"fmt"
+	"math/bits"
+	"ndsemu/arm/disasm"
+	"strings"
+)
+
+// armInsn is an ARM instruction being assembled
+type armInsn struct {
+	name string // mnemonic without condition code
+	cond uint32
+	ops  []string
+	addr uint32
+	arch disasm.Arch
+}
+
+type armEncoder func(i *armInsn) (uint32, error)
+
+var armOps = map[string]armEncoder{}
+
+var dpOpcodes = [16]string{
+	"and", "eor", "sub", "rsb", "add", "adc", "sbc", "rsc",
+	"tst", "teq", "cmp", "cmn", "orr", "mov", "bic", "mvn",
+}
+
+var shiftTypes = map[string]uint32{"lsl": 0, "lsr": 1, "asr": 2, "ror": 3}
+
+func init() {
+	for opc, name := range dpOpcodes {
+		armOps[name] = armDataProc(uint32(opc), false)
+		if opc < 8 || opc > 11 {
+			armOps[name+"s"] = armDataProc(uint32(opc), true)
+		}
+	}
+	for name, typ := range shiftTypes {
+		armOps[name] = armShiftAlias(typ, false)
+		armOps[name+"s"] = armShiftAlias(typ, true)
+	}
+	for _, name := range []string{"mul", "mla", "umull", "umlal", "smull", "smlal"} {
+		armOps[name] = armMul
+		armOps[name+"s"] = armMul
+	}
+	for _, x := range []string{"b", "t"} {
+		names := []string{"smulw" + x, "smlaw" + x}
+		for _, y := range []string{"b", "t"} {
+			names = append(names, "smul"+x+y, "smla"+x+y, "smlal"+x+y)
+		}
+		for _, name := range names {
+			armOps[name] = armDspMul
+			armV5Only[name] = true
+		}
+	}
+	for _, name := range []string{"ldr", "str", "ldrb", "strb", "ldrt", "strt", "ldrbt", "strbt"} {
+		armOps[name] = armLoadStore
+	}
+	for _, name := range []string{"ldrh", "strh", "ldrsb", "ldrsh", "ldrd", "strd"} {
+		armOps[name] = armLoadStoreExtra
+	}
+	for _, mode := range []string{"", "ia", "ib", "da", "db", "fd", "fa", "ed", "ea"} {
+		armOps["ldm"+mode] = armBlock
+		armOps["stm"+mode] = armBlock
+	}
+	for _, name := range []string{"qadd", "qsub", "qdadd", "qdsub"} {
+		armOps[name] = armSatArith
+	}
+	armOps["push"] = armBlock
+	armOps["pop"] = armBlock
+	armOps["b"] = armBranch
+	armOps["bl"] = armBranch
+	armOps["blx"] = armBranch
+	armOps["bx"] = armBranch
+	armOps["swi"] = armSwi
+	armOps["svc"] = armSwi
+	armOps["bkpt"] = armBkpt
+	armOps["clz"] = armClz
+	armOps["swp"] = armSwp
+	armOps["swpb"] = armSwp
+	armOps["mrs"] = armMrs
+	armOps["msr"] = armMsr
+	armOps["mcr"] = armCoproc
+	armOps["mrc"] = armCoproc
+	armOps["cdp"] = armCoproc
+	armOps["nop"] = armNop
+}
+
+// armV5Only names the instructions that only exist on ARMv5 (the ARM9),
+// besides the DSP multiplies (added by init)
+var armV5Only = map[string]bool{
+	"clz": true, "qadd": true, "qsub": true, "qdadd": true, "qdsub": true,
+	"ldrd": true, "strd": true, "bkpt": true,
+}
+
+// Arm assembles a 32-bit ARM instruction located at addr
+func Arm(text string, addr uint32, arch disasm.Arch) (uint32, error) {
+	mn, ops, err := split(text)
+	if err != nil {
+		return 0, err
+	}
+	name, cond, ok := splitCond(mn, func(s string) bool { return armOps[s] != nil })
+	if !ok {
+		return 0, fmt.Errorf("unknown instruction: %q", mn)
+	}
+	if arch < disasm.ARMv5 && armV5Only[name] {
+		return 0, fmt.Errorf("%s: not supported on ARMv4", name)
+	}
+	i := &armInsn{name: name, cond: cond, ops: ops, addr: addr, arch: arch}
+	op, err := armOps[name](i)
+	if err != nil {
+		return 0, err
+	}
+	return op | i.cond<<28, nil
+}
+
+// nops checks that the instruction has n operands
+func (i *armInsn) nops(n int) error {
+	if len(i.ops) != n {
+		return fmt.Errorf("%s: expected %d operands, got %d", i.name, n, len(i.ops))
+	}
+	return nil
+}
+
+// regs parses all the operands as registers
+func (i *armInsn) regs(n int) ([]uint32, error) {
+	if err := i.nops(n); err != nil {
+		return nil, err
+	}
+	regs := make([]uint32, n)
+	for j, s := range i.ops {
+		var err error
+		if regs[j], err = parseReg(s); err != nil {
+			return nil, err
+		}
+	}
+	return regs, nil
+}
+
+// unconditional checks that no condition code was specified, for the
+// instructions that must always be executed
+func (i *armInsn) unconditional() error {
+	if i.cond != 14 {
+		return fmt.Errorf("%s: cannot be conditional", i.name)
+	}
+	return nil
+}
+
+// armEncodeImm encodes an immediate as an 8-bit value rotated by an even
+// amount, as used by data processing instructions
+func armEncodeImm(v uint32) (uint32, bool) {
+	for rot := uint32(0); rot < 16; rot++ {
+		if x := bits.RotateLeft32(v, int(rot*2)); x < 0x100 {
+			return rot<<8 | x, true
+		}
+	}
+	return 0, false
+}
+
+// armShift encodes a shift operand ("lsl #2", "asr r3", "rrx") applied to
+// register rm. If reg is false, shifts by register are rejected.
+func armShift(rm uint32, shift []string, reg bool) (uint32, error) {
+	switch len(shift) {
+	case 0:
+		return rm, nil
+	case 1:
+	default:
+		return 0, fmt.Errorf("too many operands")
+	}
+
+	s := shift[0]
+	if s == "rrx" {
+		return rm | 3<<5, nil
+	}
+	idx := strings.IndexAny(s, " \t")
+	if idx < 0 {
+		return 0, fmt.Errorf("invalid shift: %q", s)
+	}
+	typ, ok := shiftTypes[s[:idx]]
+	if !ok {
+		return 0, fmt.Errorf("invalid shift: %q", s)
+	}
+	arg := strings.TrimSpace(s[idx:])
+	if !strings.HasPrefix(arg, "#") {
+		if !reg {
+			return 0, fmt.Errorf("shift by register not allowed: %q", s)
+		}
+		rs, err := parseReg(arg)
+		if err != nil {
+			return 0, err
+		}
+		return rm | rs<<8 | typ<<5 | 0x10, nil
+	}
+
+	min, max := uint32(1), uint32(32)
+	switch typ {
+	case 0:
+		min, max = 0, 31
+	case 3:
+		max = 31
+	}
+	amt, err := parseImmRange(arg, min, max)
+	if err != nil {
+		return 0, err
+	}
+	return rm | (amt&31)<<7 | typ<<5, nil
+}
+
+// armOperand2 encodes the second operand of data processing instructions:
+// an immediate, or a register with an optional shift
+func armOperand2(ops []string) (uint32, error) {
+	if strings.HasPrefix(ops[0], "#") {
+		if len(ops) > 1 {
+			return 0, fmt.Errorf("too many operands")
+		}
+		v, err := parseImm(ops[0])
+		if err != nil {
+			return 0, err
+		}
+		enc, ok := armEncodeImm(v)
+		if !ok {
+			return 0, fmt.Errorf("immediate cannot be encoded: %q", ops[0])
+		}
+		return 1<<25 | enc, nil
+	}
+	rm, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	return armShift(rm, ops[1:], true)
+}
+
+// armImmAlternates lists the pairs of data processing opcodes that can be
+// swapped to encode an immediate that doesn't fit, by complementing (eg:
+// mov/mvn) or negating (eg: add/sub) it
+var armImmAlternates = map[uint32]struct {
+	opc    uint32
+	negate bool
+}{
+	0x0: {0xE, false}, 0xE: {0x0, false}, // and, bic
+	0xD: {0xF, false}, 0xF: {0xD, false}, // mov, mvn
+	0x5: {0x6, false}, 0x6: {0x5, false}, // adc, sbc
+	0x2: {0x4, true}, 0x4: {0x2, true}, // sub, add
+	0xA: {0xB, true}, 0xB: {0xA, true}, // cmp, cmn
+}
+
+func armDataProc(opc uint32, s bool) armEncoder {
+	return func(i *armInsn) (uint32, error) {
+		opc, s := opc, s
+		var rd, rn uint32
+		var err error
+		ops := i.ops
+		nregs := 2
+		if opc >= 8 && opc <= 11 || opc == 13 || opc == 15 {
+			nregs = 1
+		}
+		if len(ops) <= nregs {
+			return 0, fmt.Errorf("%s: missing operands", i.name)
+		}
+		if rd, err = parseReg(ops[0]); err != nil {
+			return 0, err
+		}
+		switch {
+		case opc >= 8 && opc <= 11:
+			// Test instructions always set flags, and have no destination
+			rd, rn, s = 0, rd, true
+		case nregs == 2:
+			if rn, err = parseReg(ops[1]); err != nil {
+				return 0, err
+			}
+		}
+
+		op2, err := armOperand2(ops[nregs:])
+		if err != nil {
+			if alt, ok := armImmAlternates[opc]; ok && len(ops) == nregs+1 && strings.HasPrefix(ops[nregs], "#") {
+				if v, err2 := parseImm(ops[nregs]); err2 == nil {
+					if alt.negate {
+						v = -v
+					} else {
+						v = ^v
+					}
+					if enc, ok := armEncodeImm(v); ok {
+						opc, op2, err = alt.opc, 1<<25|enc, nil
+					}
+				}
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		op := opc<<21 | rn<<16 | rd<<12 | op2
+		if s {
+			op |= 1 << 20
+		}
+		return op, nil
+	}
+}
+
+// armShiftAlias assembles the shift pseudo-instructions (eg: "lsl r0, r1,
+// #2"), which are moves with a shifted operand
+func armShiftAlias(typ uint32, s bool) armEncoder {
+	return func(i *armInsn) (uint32, error) {
+		if err := i.nops(3); err != nil {
+			return 0, err
+		}
+		rd, err := parseReg(i.ops[0])
+		if err != nil {
+			return 0, err
+		}
+		rm, err := parseReg(i.ops[1])
+		if err != nil {
+			return 0, err
+		}
+		op2, err := armShift(rm, []string{strings.TrimSuffix(i.name, "s") + " " + i.ops[2]}, true)
+		if err != nil {
+			return 0, err
+		}
+		op := 0xD<<21 | rd<<12 | op2
+		if s {
+			op |= 1 << 20
+		}
+		return op, nil
+	}
+}
+
+func armNop(i *armInsn) (uint32, error) {
+	if err := i.nops(0); err != nil {
+		return 0, err
+	}
+	return 0x01A00000, nil // mov r0, r0
+}
+
+func armMul(i *armInsn) (uint32, error) {
+	name := i.name
+	var op uint32
+	if strings.HasSuffix(name, "s") {
+		op |= 1 << 20
+		name = name[:len(name)-1]
+	}
+	switch name {
+	case "mul":
+		r, err := i.regs(3)
+		if err != nil {
+			return 0, err
+		}
+		return op | 0x90 | r[0]<<16 | r[2]<<8 | r[1], nil
+	case "mla":
+		r, err := i.regs(4)
+		if err != nil {
+			return 0, err
+		}
+		return op | 1<<21 | 0x90 | r[0]<<16 | r[3]<<12 | r[2]<<8 | r[1], nil
+	default:
+		r, err := i.regs(4)
+		if err != nil {
+			return 0, err
+		}
+		kind := map[string]uint32{"umull": 4, "umlal": 5, "smull": 6, "smlal": 7}[name]
+		return op | kind<<21 | 0x90 | r[1]<<16 | r[0]<<12 | r[3]<<8 | r[2], nil
+	}
+}
+
+// armDspMul assembles the signed halfword multiplies of ARMv5TE
+func armDspMul(i *armInsn) (uint32, error) {
+	name := i.name
+	var kind, nregs uint32
+	switch {
+	case strings.HasPrefix(name, "smlal"):
+		kind, nregs, name = 2, 4, name[5:]
+	case strings.HasPrefix(name, "smulw"):
+		kind, nregs, name = 1, 3, "t"+name[5:]
+	case strings.HasPrefix(name, "smlaw"):
+		kind, nregs, name = 1, 4, "b"+name[5:]
+	case strings.HasPrefix(name, "smul"):
+		kind, nregs, name = 3, 3, name[4:]
+	default:
+		kind, nregs, name = 0, 4, name[4:]
+	}
+	r, err := i.regs(int(nregs))
+	if err != nil {
+		return 0, err
+	}
+
+	op := 0x01000080 | kind<<21
+	if name[0] == 't' {
+		op |= 1 << 5
+	}
+	if name[1] == 't' {
+		op |= 1 << 6
+	}
+	if kind == 2 {
+		// smlalxy rdlo, rdhi, rm, rs
+		return op | r[1]<<16 | r[0]<<12 | r[3]<<8 | r[2], nil
+	}
+	op |= r[0]<<16 | r[2]<<8 | r[1]
+	if nregs == 4 {
+		op |= r[3] << 12
+	}
+	return op, nil
+}
+
+func armSatArith(i *armInsn) (uint32, error) {
+	r, err := i.regs(3)
+	if err != nil {
+		return 0, err
+	}
+	kind := map[string]uint32{"qadd": 0, "qsub": 1, "qdadd": 2, "qdsub": 3}[i.name]
+	return 0x01000050 | kind<<21 | r[2]<<16 | r[0]<<12 | r[1], nil
+}
+
+func armClz(i *armInsn) (uint32, error) {
+	r, err := i.regs(2)
+	if err != nil {
+		return 0, err
+	}
+	return 0x016F0F10 | r[0]<<12 | r[1], nil
+}
+
+func armSwp(i *armInsn) (uint32, error) {
+	if err := i.nops(3); err != nil {
+		return 0, err
+	}
+	rd, err := parseReg(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	rm, err := parseReg(i.ops[1])
+	if err != nil {
+		return 0, err
+	}
+	m, err := parseMem(i.ops[2:])
+	if err != nil {
+		return 0, err
+	}
+	if !m.pre || m.wb || m.hasReg || m.off != 0 {
+		return 0, fmt.Errorf("%s: invalid memory operand: %q", i.name, i.ops[2])
+	}
+	op := 0x01000090 | m.rn<<16 | rd<<12 | rm
+	if i.name == "swpb" {
+		op |= 1 << 22
+	}
+	return op, nil
+}
+
+func armBranch(i *armInsn) (uint32, error) {
+	if err := i.nops(1); err != nil {
+		return 0, err
+	}
+	if rm, err := parseReg(i.ops[0]); err == nil {
+		switch i.name {
+		case "bx":
+			return 0x012FFF10 | rm, nil
+		case "blx":
+			if i.arch < disasm.ARMv5 {
+				return 0, fmt.Errorf("blx: not supported on ARMv4")
+			}
+			return 0x012FFF30 | rm, nil
+		}
+		return 0, fmt.Errorf("%s: invalid branch target: %q", i.name, i.ops[0])
+	}
+	if i.name == "bx" {
+		return 0, fmt.Errorf("bx: invalid register: %q", i.ops[0])
+	}
+
+	off, err := parseTarget(i.ops[0], i.addr+8)
+	if err != nil {
+		return 0, err
+	}
+	if off < -1<<25 || off >= 1<<25 {
+		return 0, fmt.Errorf("%s: branch target out of range: %s", i.name, i.ops[0])
+	}
+
+	if i.name == "blx" {
+		// The destination is Thumb code, so it can be halfword-aligned
+		if i.arch < disasm.ARMv5 {
+			return 0, fmt.Errorf("blx: not supported on ARMv4")
+		}
+		if err := i.unconditional(); err != nil {
+			return 0, err
+		}
+		if off&1 != 0 {
+			return 0, fmt.Errorf("blx: misaligned branch target: %s", i.ops[0])
+		}
+		// Encoded with condition NV, like the other unconditional instructions
+		i.cond = 15
+		return 0x0A000000 | uint32(off>>1&1)<<24 | uint32(off>>2)&0xFFFFFF, nil
+	}
+
+	if off&3 != 0 {
+		return 0, fmt.Errorf("%s: misaligned branch target: %s", i.name, i.ops[0])
+	}
+	op := 0x0A000000 | uint32(off>>2)&0xFFFFFF
+	if i.name == "bl" {
+		op |= 1 << 24
+	}
+	return op, nil
+}
+
+func armSwi(i *armInsn) (uint32, error) {
+	if err := i.nops(1); err != nil {
+		return 0, err
+	}
+	v, err := parseImmRange(i.ops[0], 0, 0xFFFFFF)
+	if err != nil {
+		return 0, err
+	}
+	return 0x0F000000 | v, nil
+}
+
+func armBkpt(i *armInsn) (uint32, error) {
+	if err := i.nops(1); err != nil {
+		return 0, err
+	}
+	if err := i.unconditional(); err != nil {
+		return 0, err
+	}
+	v, err := parseImmRange(i.ops[0], 0, 0xFFFF)
+	if err != nil {
+		return 0, err
+	}
+	return 0x01200070 | v>>4<<8 | v&0xF, nil
+}
+
+func armMrs(i *armInsn) (uint32, error) {
+	if err := i.nops(2); err != nil {
+		return 0, err
+	}
+	rd, err := parseReg(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	switch i.ops[1] {
+	case "cpsr":
+		return 0x010F0000 | rd<<12, nil
+	case "spsr":
+		return 0x014F0000 | rd<<12, nil
+	}
+	return 0, fmt.Errorf("mrs: invalid status register: %q", i.ops[1])
+}
+
+func armMsr(i *armInsn) (uint32, error) {
+	if err := i.nops(2); err != nil {
+		return 0, err
+	}
+
+	psr := i.ops[0]
+	var op uint32
+	switch {
+	case strings.HasPrefix(psr, "cpsr"):
+	case strings.HasPrefix(psr, "spsr"):
+		op |= 1 << 22
+	default:
+		return 0, fmt.Errorf("msr: invalid status register: %q", psr)
+	}
+	switch fields := psr[4:]; {
+	case fields == "":
+		// Like other assemblers, the plain register means control and flags
+		op |= 9 << 16
+	case fields[0] == '_' && len(fields) > 1:
+		for _, f := range fields[1:] {
+			idx := strings.IndexRune("cxsf", f)
+			if idx < 0 || op&(1<<(16+uint(idx))) != 0 {
+				return 0, fmt.Errorf("msr: invalid status register: %q", psr)
+			}
+			op |= 1 << (16 + uint(idx))
+		}
+	default:
+		return 0, fmt.Errorf("msr: invalid status register: %q", psr)
+	}
+
+	if strings.HasPrefix(i.ops[1], "#") {
+		v, err := parseImm(i.ops[1])
+		if err != nil {
+			return 0, err
+		}
+		enc, ok := armEncodeImm(v)
+		if !ok {
+			return 0, fmt.Errorf("immediate cannot be encoded: %q", i.ops[1])
+		}
+		return 0x0320F000 | op | enc, nil
+	}
+	rm, err := parseReg(i.ops[1])
+	if err != nil {
+		return 0, err
+	}
+	return 0x0120F000 | op | rm, nil
+}
+
+// armAddrMode2 encodes the memory operand of word and byte loads/stores
+func armAddrMode2(m memRef) (uint32, error) {
+	var op uint32
+	if m.pre {
+		op |= 1 << 24
+	}
+	if m.up {
+		op |= 1 << 23
+	}
+	if m.wb {
+		op |= 1 << 21
+	}
+	op |= m.rn << 16
+	if !m.hasReg {
+		if m.off > 0xFFF {
+			return 0, fmt.Errorf("offset out of range: %#x", m.off)
+		}
+		return op | m.off, nil
+	}
+	off, err := armShift(m.off, m.shift, false)
+	if err != nil {
+		return 0, err
+	}
+	return op | 1<<25 | off, nil
+}
+
+func armLoadStore(i *armInsn) (uint32, error) {
+	if len(i.ops) < 2 {
+		return 0, fmt.Errorf("%s: missing operands", i.name)
+	}
+	rd, err := parseReg(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	m, err := parseMem(i.ops[1:])
+	if err != nil {
+		return 0, err
+	}
+
+	name := i.name
+	op := uint32(0x04000000)
+	if strings.HasSuffix(name, "t") {
+		// Access with user privileges: post-indexed with W set
+		if m.pre {
+			return 0, fmt.Errorf("%s: post-indexed addressing required", name)
+		}
+		m.wb = true
+		name = name[:len(name)-1]
+	}
+	if name[0] == 'l' {
+		op |= 1 << 20
+	}
+	if strings.HasSuffix(name, "b") {
+		op |= 1 << 22
+	}
+	am, err := armAddrMode2(m)
+	if err != nil {
+		return 0, err
+	}
+	return op | am | rd<<12, nil
+}
+
+func armLoadStoreExtra(i *armInsn) (uint32, error) {
+	if len(i.ops) < 2 {
+		return 0, fmt.Errorf("%s: missing operands", i.name)
+	}
+	rd, err := parseReg(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	ops := i.ops[1:]
+
+	var op uint32
+	switch i.name {
+	case "strh":
+		op = 1 << 5
+	case "ldrh":
+		op = 1<<20 | 1<<5
+	case "ldrsb":
+		op = 1<<20 | 2<<5
+	case "ldrsh":
+		op = 1<<20 | 3<<5
+	case "ldrd", "strd":
+		// Transfer a pair of registers, whose second one can be omitted
+		op = 2 << 5
+		if i.name == "strd" {
+			op = 3 << 5
+		}
+		if rd&1 != 0 || rd == 14 {
+			return 0, fmt.Errorf("%s: invalid register pair: %q", i.name, i.ops[0])
+		}
+		if !strings.HasPrefix(ops[0], "[") {
+			rd2, err := parseReg(ops[0])
+			if err != nil {
+				return 0, err
+			}
+			if rd2 != rd+1 {
+				return 0, fmt.Errorf("%s: invalid register pair: %q, %q", i.name, i.ops[0], ops[0])
+			}
+			ops = ops[1:]
+			if len(ops) == 0 {
+				return 0, fmt.Errorf("%s: missing operands", i.name)
+			}
+		}
+	}
+
+	m, err := parseMem(ops)
+	if err != nil {
+		return 0, err
+	}
+	if m.pre {
+		op |= 1 << 24
+	}
+	if m.up {
+		op |= 1 << 23
+	}
+	if m.wb {
+		op |= 1 << 21
+	}
+	if m.hasReg {
+		if len(m.shift) != 0 {
+			return 0, fmt.Errorf("%s: shifted offsets are not supported", i.name)
+		}
+		op |= m.off
+	} else {
+		if m.off > 0xFF {
+			return 0, fmt.Errorf("offset out of range: %#x", m.off)
+		}
+		op |= 1<<22 | m.off>>4<<8 | m.off&0xF
+	}
+	return op | 0x90 | m.rn<<16 | rd<<12, nil
+}
+
+// ldmModes and stmModes map the addressing modes of block transfers
+// (including the stack-oriented aliases) to the P and U bits
+var ldmModes = map[string]uint32{
+	"da": 0, "": 1, "ia": 1, "db": 2, "ib": 3,
+	"fa": 0, "fd": 1, "ea": 2, "ed": 3,
+}
+
+var stmModes = map[string]uint32{
+	"da": 0, "": 1, "ia": 1, "db": 2, "ib": 3,
+	"ed": 0, "ea": 1, "fd": 2, "fa": 3,
+}
+
+func armBlock(i *armInsn) (uint32, error) {
+	switch i.name {
+	case "push", "pop":
+		if err := i.nops(1); err != nil {
+			return 0, err
+		}
+		list, err := parseRegList(i.ops[0])
+		if err != nil {
+			return 0, err
+		}
+		if i.name == "pop" {
+			return 0x08BD0000 | list, nil
+		}
+		return 0x092D0000 | list, nil
+	}
+
+	if err := i.nops(2); err != nil {
+		return 0, err
+	}
+	op := uint32(0x08000000)
+	mode := stmModes[i.name[3:]]
+	if i.name[0] == 'l' {
+		op |= 1 << 20
+		mode = ldmModes[i.name[3:]]
+	}
+	op |= mode << 23
+
+	base := i.ops[0]
+	if strings.HasSuffix(base, "!") {
+		op |= 1 << 21
+		base = strings.TrimSpace(base[:len(base)-1])
+	}
+	rn, err := parseReg(base)
+	if err != nil {
+		return 0, err
+	}
+
+	list := i.ops[1]
+	if strings.HasSuffix(list, "^") {
+		// User bank transfer (or return from exception, if PC is loaded)
+		op |= 1 << 22
+		list = strings.TrimSpace(list[:len(list)-1])
+	}
+	mask, err := parseRegList(list)
+	if err != nil {
+		return 0, err
+	}
+	return op | rn<<16 | mask, nil
+}
+
+// parseCoprocNum parses a bare number (like the opcodes of coprocessor
+// instructions) in the range [0, max]
+func parseCoprocNum(s string, prefix string, max uint32) (uint32, error) {
+	if !strings.HasPrefix(s, prefix) {
+		return 0, fmt.Errorf("invalid operand: %q", s)
+	}
+	v, err := parseNum(s[len(prefix):])
+	if err != nil || v > max {
+		return 0, fmt.Errorf("invalid operand: %q", s)
+	}
+	return v, nil
+}
+
+// armCoproc assembles mcr/mrc ("p15, 0, r0, c1, c0, 0") and cdp
+// ("p15, 0, c0, c1, c2, 0")
+func armCoproc(i *armInsn) (uint32, error) {
+	if err := i.nops(6); err != nil {
+		return 0, err
+	}
+	cp, err := parseCoprocNum(i.ops[0], "p", 15)
+	if err != nil {
+		return 0, err
+	}
+	opc1, err := parseCoprocNum(i.ops[1], "", 15)
+	if err != nil {
+		return 0, err
+	}
+	crn, err := parseCoprocNum(i.ops[3], "c", 15)
+	if err != nil {
+		return 0, err
+	}
+	crm, err := parseCoprocNum(i.ops[4], "c", 15)
+	if err != nil {
+		return 0, err
+	}
+	opc2, err := parseCoprocNum(i.ops[5], "", 7)
+	if err != nil {
+		return 0, err
+	}
+
+	if i.name == "cdp" {
+		crd, err := parseCoprocNum(i.ops[2], "c", 15)
+		if err != nil {
+			return 0, err
+		}
+		return 0x0E000000 | opc1<<20 | crn<<16 | crd<<12 | cp<<8 | opc2<<5 | crm, nil
+	}
+
+	if opc1 > 7 {
+		return 0, fmt.Errorf("%s: invalid opcode: %q", i.name, i.ops[1])
+	}
+	rd, err := parseReg(i.ops[2])
+	if err != nil {
+		return 0, err
+	}
+	op := 0x0E000010 | opc1<<21 | crn<<16 | rd<<12 | cp<<8 | opc2<<5 | crm
+	if i.name == "mrc" {
+		op |= 1 << 20
+	}
+	return op, nil
+}
@@ -0,0 +1,273 @@
+// Package asm assembles single ARM and Thumb instructions, written in the
+// syntax produced by package disasm, so that disassembled code can be
+// edited and assembled back. It is meant for live patching in the debugger:
+// there are no labels, directives or literal pools, and the destinations of
+// branches are absolute addresses (eg: "bl 0x02000100").
+//
+// Only the UAL ordering of suffixes is accepted (eg: "addseq", not
+// "addeqs").
+package asm
+
+import (
+	"fmt"
+	"ndsemu/arm/disasm"
+	"strconv"
+	"strings"
+)
+
+// Assemble assembles the instruction located at addr, returning its encoding
+// in little-endian byte order. Thumb instructions are 2 bytes long, except
+// for BL/BLX which are assembled as a pair (4 bytes).
+func Assemble(text string, addr uint32, thumb bool, arch disasm.Arch) ([]byte, error) {
+	if thumb {
+		ops, err := Thumb(text, addr, arch)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 0, len(ops)*2)
+		for _, op := range ops {
+			buf = append(buf, byte(op), byte(op>>8))
+		}
+		return buf, nil
+	}
+
+	op, err := Arm(text, addr, arch)
+	if err != nil {
+		return nil, err
+	}
+	return []byte{byte(op), byte(op >> 8), byte(op >> 16), byte(op >> 24)}, nil
+}
+
+// split parses an instruction into mnemonic and operands. Commas within
+// memory operands and register lists don't separate operands, and comments
+// (like the literal values added by disasm) are ignored.
+func split(text string) (string, []string, error) {
+	if idx := strings.IndexByte(text, ';'); idx >= 0 {
+		text = text[:idx]
+	}
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return "", nil, fmt.Errorf("empty instruction")
+	}
+
+	mn, rest := text, ""
+	if idx := strings.IndexAny(text, " \t"); idx >= 0 {
+		mn, rest = text[:idx], strings.TrimSpace(text[idx:])
+	}
+	if rest == "" {
+		return mn, nil, nil
+	}
+
+	var ops []string
+	depth, start := 0, 0
+	for i, c := range rest {
+		switch c {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				ops = append(ops, strings.TrimSpace(rest[start:i]))
+				start = i + 1
+			}
+		}
+		if depth < 0 {
+			return "", nil, fmt.Errorf("unbalanced brackets")
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("unbalanced brackets")
+	}
+	ops = append(ops, strings.TrimSpace(rest[start:]))
+	for _, op := range ops {
+		if op == "" {
+			return "", nil, fmt.Errorf("empty operand")
+		}
+	}
+	return mn, ops, nil
+}
+
+var condCodes = map[string]uint32{
+	"eq": 0, "ne": 1, "hs": 2, "cs": 2, "lo": 3, "cc": 3, "mi": 4, "pl": 5,
+	"vs": 6, "vc": 7, "hi": 8, "ls": 9, "ge": 10, "lt": 11, "gt": 12, "le": 13,
+	"al": 14,
+}
+
+// splitCond separates the condition code from a mnemonic, given the set of
+// known mnemonics without condition.
+func splitCond(mn string, known func(string) bool) (string, uint32, bool) {
+	if known(mn) {
+		return mn, 14, true
+	}
+	if n := len(mn) - 2; n > 0 {
+		if cc, ok := condCodes[mn[n:]]; ok && known(mn[:n]) {
+			return mn[:n], cc, true
+		}
+	}
+	return "", 0, false
+}
+
+var regAliases = map[string]uint32{
+	"sp": 13, "lr": 14, "pc": 15, "sb": 9, "sl": 10, "fp": 11, "ip": 12,
+}
+
+func parseReg(s string) (uint32, error) {
+	if r, ok := regAliases[s]; ok {
+		return r, nil
+	}
+	if len(s) >= 2 && s[0] == 'r' {
+		if n, err := strconv.ParseUint(s[1:], 10, 8); err == nil && n < 16 {
+			return uint32(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid register: %q", s)
+}
+
+// parseLowReg parses one of the registers r0-r7, which are the only ones
+// accessible by most Thumb instructions
+func parseLowReg(s string) (uint32, error) {
+	r, err := parseReg(s)
+	if err == nil && r >= 8 {
+		err = fmt.Errorf("invalid register: %q (only r0-r7 can be used)", s)
+	}
+	return r, err
+}
+
+// parseNum parses a (possibly negative) number, in any base supported by
+// Go literals; the result is truncated to 32 bits.
+func parseNum(s string) (uint32, error) {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil || v < -1<<31 || v >= 1<<32 {
+		return 0, fmt.Errorf("invalid number: %q", s)
+	}
+	return uint32(v), nil
+}
+
+// parseImm parses an immediate operand (eg: "#0x10")
+func parseImm(s string) (uint32, error) {
+	if !strings.HasPrefix(s, "#") {
+		return 0, fmt.Errorf("invalid immediate: %q", s)
+	}
+	return parseNum(s[1:])
+}
+
+// parseImmRange parses an immediate that must be in the range [min, max]
+func parseImmRange(s string, min, max uint32) (uint32, error) {
+	v, err := parseImm(s)
+	if err == nil && (v < min || v > max) {
+		err = fmt.Errorf("immediate out of range: %q (%#x-%#x)", s, min, max)
+	}
+	return v, err
+}
+
+// parseTarget parses the destination of a branch, and returns its offset
+// from base
+func parseTarget(s string, base uint32) (int32, error) {
+	addr, err := parseNum(strings.TrimPrefix(s, "#"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid branch target: %q", s)
+	}
+	return int32(addr - base), nil
+}
+
+// parseRegList parses a register list like "{r0-r3, lr}" into a bitmask
+func parseRegList(s string) (uint32, error) {
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return 0, fmt.Errorf("invalid register list: %q", s)
+	}
+	var mask uint32
+	for _, part := range strings.Split(s[1:len(s)-1], ",") {
+		part = strings.TrimSpace(part)
+		from, to := part, part
+		if idx := strings.IndexByte(part, '-'); idx >= 0 {
+			from, to = strings.TrimSpace(part[:idx]), strings.TrimSpace(part[idx+1:])
+		}
+		r1, err := parseReg(from)
+		if err != nil {
+			return 0, err
+		}
+		r2, err := parseReg(to)
+		if err != nil {
+			return 0, err
+		}
+		if r2 < r1 {
+			return 0, fmt.Errorf("invalid register range: %q", part)
+		}
+		for r := r1; r <= r2; r++ {
+			mask |= 1 << r
+		}
+	}
+	return mask, nil
+}
+
+// memRef is a parsed memory operand
+type memRef struct {
+	rn     uint32
+	pre    bool // pre-indexed (offset within brackets)
+	wb     bool // writeback ("!")
+	up     bool // offset is added (rather than subtracted)
+	hasReg bool // register offset
+	off    uint32
+	shift  []string // shift applied to the register offset, if any
+}
+
+// parseMem parses a memory operand, including the offset that follows it
+// for post-indexed addressing ("[r0], #0x4"). ops is the list of operands
+// starting from the memory one.
+func parseMem(ops []string) (m memRef, err error) {
+	s := ops[0]
+	if strings.HasSuffix(s, "!") {
+		m.wb = true
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return m, fmt.Errorf("invalid memory operand: %q", ops[0])
+	}
+	parts := strings.Split(s[1:len(s)-1], ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if m.rn, err = parseReg(parts[0]); err != nil {
+		return m, err
+	}
+
+	m.pre, m.up = true, true
+	offs := parts[1:]
+	if len(ops) > 1 {
+		if len(offs) != 0 || m.wb {
+			return m, fmt.Errorf("invalid memory operand: %q", strings.Join(ops, ", "))
+		}
+		m.pre, offs = false, ops[1:]
+	}
+	if len(offs) == 0 {
+		return m, nil
+	}
+
+	off := offs[0]
+	if strings.HasPrefix(off, "#") {
+		if len(offs) > 1 {
+			return m, fmt.Errorf("invalid memory offset: %q", strings.Join(offs, ", "))
+		}
+		v := off[1:]
+		if strings.HasPrefix(v, "-") {
+			m.up, v = false, v[1:]
+		}
+		if m.off, err = parseNum(v); err != nil {
+			return m, err
+		}
+		return m, nil
+	}
+
+	m.hasReg = true
+	if strings.HasPrefix(off, "-") {
+		m.up, off = false, off[1:]
+	} else if strings.HasPrefix(off, "+") {
+		off = off[1:]
+	}
+	if m.off, err = parseReg(off); err != nil {
+		return m, err
+	}
+	m.shift = offs[1:]
+	return m, nil
+}
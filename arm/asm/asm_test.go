@@ -0,0 +1,138 @@
+package asm
+
+import (
+	"bytes"
+	"ndsemu/arm/disasm"
+	"testing"
+)
+
+func TestArm(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		arch disasm.Arch
+		want uint32
+	}{
+		{"mov r0, #1", disasm.ARMv4, 0xE3A00001},
+		{"mov r0, #-1", disasm.ARMv4, 0xE3E00000},
+		{"add r0, r1, #-4", disasm.ARMv4, 0xE2410004},
+		{"adds      r0, r1, r2", disasm.ARMv4, 0xE0910002},
+		{"mov       r0, r2, lsl #2", disasm.ARMv4, 0xE1A00102},
+		{"lsl r0, r2, #2", disasm.ARMv4, 0xE1A00102},
+		{"cmpeq     r0, r1, lsl r3", disasm.ARMv4, 0x01500311},
+		{"bne       0x00002008", disasm.ARMv4, 0x1A000000},
+		{"bl        0x00002000", disasm.ARMv4, 0xEBFFFFFE},
+		{"blx       0x0000200a", disasm.ARMv5, 0xFB000000},
+		{"ldr       r0, [pc, #0x4] ; =0xcafebabe", disasm.ARMv4, 0xE59F0004},
+		{"ldr       r0, [r1], #0x4", disasm.ARMv4, 0xE4910004},
+		{"ldr       r0, [r1, -r2, lsl #2]!", disasm.ARMv4, 0xE7310102},
+		{"push      {r4, lr}", disasm.ARMv4, 0xE92D4010},
+		{"pop       {r0-r3, pc}", disasm.ARMv4, 0xE8BD800F},
+		{"ldmfd     r0, {r1, r2}^", disasm.ARMv4, 0xE8D00006},
+		{"bx        lr", disasm.ARMv4, 0xE12FFF1E},
+		{"clz       r0, r1", disasm.ARMv5, 0xE16F0F11},
+		{"mrc       p15, 0, r0, c1, c0, 0", disasm.ARMv5, 0xEE110F10},
+		{"msr       cpsr_c, r0", disasm.ARMv4, 0xE121F000},
+		{"ldrd      r2, r3, [r0, #0x4]", disasm.ARMv5, 0xE1C020D4},
+		{"ldrh      r0, [r0, #0x2]", disasm.ARMv4, 0xE1D000B2},
+		{"smull     r0, r1, r2, r3", disasm.ARMv4, 0xE0C10392},
+		{"smulbt    r0, r1, r2", disasm.ARMv5, 0xE16002C1},
+		{"swi       #0x5", disasm.ARMv4, 0xEF000005},
+	} {
+		got, err := Arm(tc.text, 0x2000, tc.arch)
+		if err != nil || got != tc.want {
+			t.Errorf("%q: got %08x (%v), want %08x", tc.text, got, err, tc.want)
+		}
+	}
+
+	for _, text := range []string{
+		"mov r0, #0x101",       // immediate cannot be encoded
+		"movs r0, r1, lsl #32", // shift out of range
+		"addeqs r0, r1, r2",    // pre-UAL syntax
+		"b 0x2002",             // misaligned target
+		"clz r0, r1",           // ARMv5 only
+		"ldr r0, [r1], #4, lsl #2",
+		"foo r0",
+	} {
+		if op, err := Arm(text, 0x2000, disasm.ARMv4); err == nil {
+			t.Errorf("%q: expected error, got %08x", text, op)
+		}
+	}
+}
+
+func TestThumb(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		arch disasm.Arch
+		want []uint16
+	}{
+		{"movs      r0, #0x1", disasm.ARMv4, []uint16{0x2001}},
+		{"movs      r0, r0", disasm.ARMv4, []uint16{0x0000}},
+		{"mov       r0, r8", disasm.ARMv4, []uint16{0x4640}},
+		{"lsrs      r0, r1, #2", disasm.ARMv4, []uint16{0x0888}},
+		{"adds      r0, r1, #0x0", disasm.ARMv4, []uint16{0x1C08}},
+		{"negs      r0, r0", disasm.ARMv4, []uint16{0x4240}},
+		{"ldr       r0, [pc, #0x4] ; =0x12345678", disasm.ARMv4, []uint16{0x4801}},
+		{"ldrh      r0, [r1, #0x2]", disasm.ARMv4, []uint16{0x8848}},
+		{"ldrb      r0, [r1, r2]", disasm.ARMv4, []uint16{0x5C88}},
+		{"add       sp, #0x10", disasm.ARMv4, []uint16{0xB004}},
+		{"push      {r4, lr}", disasm.ARMv4, []uint16{0xB510}},
+		{"pop       {r4, pc}", disasm.ARMv4, []uint16{0xBD10}},
+		{"ldmia     r1, {r0, r1}", disasm.ARMv4, []uint16{0xC903}},
+		{"beq       0x00002000", disasm.ARMv4, []uint16{0xD0FE}},
+		{"b         0x00002000", disasm.ARMv4, []uint16{0xE7FE}},
+		{"bl        0x00002008", disasm.ARMv4, []uint16{0xF000, 0xF802}},
+		{"blx       0x00002008", disasm.ARMv5, []uint16{0xF000, 0xE802}},
+		{"bx        lr", disasm.ARMv4, []uint16{0x4770}},
+		{"blx       r1", disasm.ARMv5, []uint16{0x4788}},
+		{"swi       #0x1", disasm.ARMv4, []uint16{0xDF01}},
+	} {
+		got, err := Thumb(tc.text, 0x2000, tc.arch)
+		if err != nil || len(got) != len(tc.want) || got[0] != tc.want[0] || len(got) > 1 && got[1] != tc.want[1] {
+			t.Errorf("%q: got %04x (%v), want %04x", tc.text, got, err, tc.want)
+		}
+	}
+
+	for _, text := range []string{
+		"movs r8, #0x1",       // high register
+		"mov r0, #0x1",        // no immediate for the high-register version
+		"ldr r0, [r1, #0x2]",  // misaligned offset
+		"ldmia r1!, {r0, r1}", // base is loaded, so no writeback
+		"beq 0x00003000",      // out of range
+		"blx 0x00002006",      // misaligned ARM target
+		"bkpt #0x0",           // ARMv5 only
+	} {
+		if op, err := Thumb(text, 0x2000, disasm.ARMv4); err == nil {
+			t.Errorf("%q: expected error, got %04x", text, op)
+		}
+	}
+}
+
+// Disassembled code can be assembled back
+func TestRoundTrip(t *testing.T) {
+	code := []byte{
+		0x00, 0xF0, 0x06, 0xE8, // blx 0x2010
+		0x14, 0x4A, // ldr r2, [pc, #0x50]
+		0x13, 0x60, // str r3, [r2]
+		0xF9, 0xD1, // bne 0x2000
+	}
+	mem := testMem(code)
+	for addr := uint32(0x2000); addr < 0x2000+uint32(len(code)); {
+		insn := disasm.Decode(mem, addr, true, disasm.ARMv5)
+		buf, err := Assemble(insn.String(), addr, true, disasm.ARMv5)
+		if off := addr - 0x2000; err != nil || !bytes.Equal(buf, code[off:off+uint32(len(buf))]) {
+			t.Errorf("%08x %q: got % x (%v)", addr, insn.String(), buf, err)
+			return
+		}
+		addr += uint32(len(buf))
+	}
+}
+
+// testMem is a memory area starting at address 0x2000
+type testMem []byte
+
+func (m testMem) Peek8(addr uint32) uint8 {
+	if addr-0x2000 < uint32(len(m)) {
+		return m[addr-0x2000]
+	}
+	return 0
+}
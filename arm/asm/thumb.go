@@ -0,0 +1,477 @@
+package asm
+
+import (
+	"fmt"
+	"ndsemu/arm/disasm"
+	"strings"
+)
+
+var thumbAluOps = map[string]uint16{
+	"ands": 0, "eors": 1, "lsls": 2, "lsrs": 3, "asrs": 4, "adcs": 5, "sbcs": 6, "rors": 7,
+	"tst": 8, "negs": 9, "cmp": 10, "cmn": 11, "orrs": 12, "muls": 13, "bics": 14, "mvns": 15,
+}
+
+var thumbLoadStoreOps = map[string]uint16{
+	"str": 0, "strh": 1, "strb": 2, "ldrsb": 3, "ldr": 4, "ldrh": 5, "ldrb": 6, "ldrsh": 7,
+}
+
+// thumbInsn is a Thumb instruction being assembled
+type thumbInsn struct {
+	name string
+	ops  []string
+	addr uint32
+	arch disasm.Arch
+}
+
+// Thumb assembles a Thumb instruction located at addr. Like in package
+// disasm, the instructions that set flags must have the "s" suffix (eg:
+// "movs r0, #0x1"), while "add", "mov" and "cmp" without suffix are the
+// versions that can access high registers.
+//
+// BL and BLX are returned as a pair of halfwords; all the other
+// instructions are a single halfword.
+func Thumb(text string, addr uint32, arch disasm.Arch) ([]uint16, error) {
+	mn, ops, err := split(text)
+	if err != nil {
+		return nil, err
+	}
+	i := &thumbInsn{name: mn, ops: ops, addr: addr, arch: arch}
+
+	if mn == "bl" || mn == "blx" && !i.isReg(0) {
+		return i.call()
+	}
+	op, err := i.assemble()
+	if err != nil {
+		return nil, err
+	}
+	return []uint16{op}, nil
+}
+
+func (i *thumbInsn) nops(n int) error {
+	if len(i.ops) != n {
+		return fmt.Errorf("%s: expected %d operands, got %d", i.name, n, len(i.ops))
+	}
+	return nil
+}
+
+// lowRegs parses the first n operands as registers r0-r7
+func (i *thumbInsn) lowRegs(n int) ([]uint16, error) {
+	regs := make([]uint16, n)
+	for j := 0; j < n; j++ {
+		r, err := parseLowReg(i.ops[j])
+		if err != nil {
+			return nil, err
+		}
+		regs[j] = uint16(r)
+	}
+	return regs, nil
+}
+
+func (i *thumbInsn) isImm(n int) bool {
+	return n >= 0 && len(i.ops) > n && strings.HasPrefix(i.ops[n], "#")
+}
+
+func (i *thumbInsn) isReg(n int) bool {
+	if n >= len(i.ops) {
+		return false
+	}
+	_, err := parseReg(i.ops[n])
+	return err == nil
+}
+
+func (i *thumbInsn) assemble() (uint16, error) {
+	switch i.name {
+	case "lsls", "lsrs", "asrs":
+		if i.isImm(2) {
+			return i.shiftImm()
+		}
+		return i.alu()
+	case "movs":
+		if err := i.nops(2); err != nil {
+			return 0, err
+		}
+		r, err := i.lowRegs(1)
+		if err != nil {
+			return 0, err
+		}
+		if i.isImm(1) {
+			v, err := parseImmRange(i.ops[1], 0, 0xFF)
+			return 0x2000 | r[0]<<8 | uint16(v), err
+		}
+		// lsls rd, rs, #0
+		rs, err := parseLowReg(i.ops[1])
+		return r[0] | uint16(rs)<<3, err
+	case "adds", "subs":
+		return i.addSub()
+	case "cmp":
+		if err := i.nops(2); err != nil {
+			return 0, err
+		}
+		if i.isImm(1) {
+			r, err := i.lowRegs(1)
+			if err != nil {
+				return 0, err
+			}
+			v, err := parseImmRange(i.ops[1], 0, 0xFF)
+			return 0x2800 | r[0]<<8 | uint16(v), err
+		}
+		if _, err := i.lowRegs(2); err == nil {
+			return i.alu()
+		}
+		return i.hiReg(1)
+	case "add":
+		return i.add()
+	case "sub":
+		if err := i.spAdjust(); err != nil {
+			return 0, err
+		}
+		v, err := parseImmRange(i.ops[len(i.ops)-1], 0, 508)
+		if err == nil && v&3 != 0 {
+			err = fmt.Errorf("sub: offset must be a multiple of 4: %q", i.ops[len(i.ops)-1])
+		}
+		return 0xB080 | uint16(v/4), err
+	case "mov":
+		return i.hiReg(2)
+	case "nop":
+		if err := i.nops(0); err != nil {
+			return 0, err
+		}
+		return 0x46C0, nil // mov r8, r8
+	case "bx", "blx":
+		if err := i.nops(1); err != nil {
+			return 0, err
+		}
+		rs, err := parseReg(i.ops[0])
+		if err != nil {
+			return 0, err
+		}
+		if i.name == "blx" {
+			if i.arch < disasm.ARMv5 {
+				return 0, fmt.Errorf("blx: not supported on ARMv4")
+			}
+			return 0x4780 | uint16(rs)<<3, nil
+		}
+		return 0x4700 | uint16(rs)<<3, nil
+	case "push", "pop":
+		return i.pushPop()
+	case "ldmia", "stmia", "ldm", "stm":
+		return i.block()
+	case "b":
+		return i.branch(14)
+	case "swi", "svc", "bkpt":
+		if err := i.nops(1); err != nil {
+			return 0, err
+		}
+		v, err := parseImmRange(i.ops[0], 0, 0xFF)
+		if i.name == "bkpt" {
+			if i.arch < disasm.ARMv5 {
+				return 0, fmt.Errorf("bkpt: not supported on ARMv4")
+			}
+			return 0xBE00 | uint16(v), err
+		}
+		return 0xDF00 | uint16(v), err
+	}
+
+	if _, ok := thumbAluOps[i.name]; ok {
+		return i.alu()
+	}
+	if _, ok := thumbLoadStoreOps[i.name]; ok {
+		return i.loadStore()
+	}
+	if len(i.name) == 3 && i.name[0] == 'b' {
+		if cc, ok := condCodes[i.name[1:]]; ok && cc != 14 {
+			return i.branch(cc)
+		}
+	}
+	return 0, fmt.Errorf("unknown instruction: %q", i.name)
+}
+
+func (i *thumbInsn) shiftImm() (uint16, error) {
+	if err := i.nops(3); err != nil {
+		return 0, err
+	}
+	r, err := i.lowRegs(2)
+	if err != nil {
+		return 0, err
+	}
+	kind := map[string]uint16{"lsls": 0, "lsrs": 1, "asrs": 2}[i.name]
+	min, max := uint32(1), uint32(32)
+	if kind == 0 {
+		min, max = 0, 31
+	}
+	amt, err := parseImmRange(i.ops[2], min, max)
+	return kind<<11 | uint16(amt&31)<<6 | r[1]<<3 | r[0], err
+}
+
+// alu assembles the two-operand ALU operations on low registers
+func (i *thumbInsn) alu() (uint16, error) {
+	if err := i.nops(2); err != nil {
+		return 0, err
+	}
+	r, err := i.lowRegs(2)
+	if err != nil {
+		return 0, err
+	}
+	return 0x4000 | thumbAluOps[i.name]<<6 | r[1]<<3 | r[0], nil
+}
+
+func (i *thumbInsn) addSub() (uint16, error) {
+	var sub uint16
+	if i.name == "subs" {
+		sub = 1
+	}
+	switch len(i.ops) {
+	case 2:
+		r, err := i.lowRegs(1)
+		if err != nil {
+			return 0, err
+		}
+		v, err := parseImmRange(i.ops[1], 0, 0xFF)
+		return 0x3000 | sub<<11 | r[0]<<8 | uint16(v), err
+	case 3:
+		r, err := i.lowRegs(2)
+		if err != nil {
+			return 0, err
+		}
+		op := 0x1800 | sub<<9 | r[1]<<3 | r[0]
+		if i.isImm(2) {
+			v, err := parseImmRange(i.ops[2], 0, 7)
+			return op | 1<<10 | uint16(v)<<6, err
+		}
+		rn, err := parseLowReg(i.ops[2])
+		return op | uint16(rn)<<6, err
+	}
+	return 0, i.nops(3)
+}
+
+// hiReg assembles add/cmp/mov (selected by kind) on any register
+func (i *thumbInsn) hiReg(kind uint16) (uint16, error) {
+	if err := i.nops(2); err != nil {
+		return 0, err
+	}
+	rd, err := parseReg(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	rs, err := parseReg(i.ops[1])
+	if err != nil {
+		return 0, err
+	}
+	return 0x4400 | kind<<8 | uint16(rd&8)<<4 | uint16(rs)<<3 | uint16(rd&7), nil
+}
+
+// spAdjust checks the operands of the instructions that adjust the stack
+// pointer ("add sp, #0x10" or "add sp, sp, #0x10")
+func (i *thumbInsn) spAdjust() error {
+	switch {
+	case len(i.ops) == 2 && i.ops[0] == "sp":
+	case len(i.ops) == 3 && i.ops[0] == "sp" && i.ops[1] == "sp":
+	default:
+		return fmt.Errorf("%s: invalid operands: %q", i.name, strings.Join(i.ops, ", "))
+	}
+	return nil
+}
+
+func (i *thumbInsn) add() (uint16, error) {
+	if i.isImm(len(i.ops) - 1) {
+		if i.ops[0] == "sp" {
+			if err := i.spAdjust(); err != nil {
+				return 0, err
+			}
+			v, err := parseImmRange(i.ops[len(i.ops)-1], 0, 508)
+			if err == nil && v&3 != 0 {
+				err = fmt.Errorf("add: offset must be a multiple of 4: %q", i.ops[len(i.ops)-1])
+			}
+			return 0xB000 | uint16(v/4), err
+		}
+
+		// add rd, pc/sp, #imm
+		if err := i.nops(3); err != nil {
+			return 0, err
+		}
+		r, err := i.lowRegs(1)
+		if err != nil {
+			return 0, err
+		}
+		op := 0xA000 | r[0]<<8
+		switch i.ops[1] {
+		case "pc":
+		case "sp":
+			op |= 1 << 11
+		default:
+			return 0, fmt.Errorf("add: invalid base register: %q (use adds)", i.ops[1])
+		}
+		v, err := parseImmRange(i.ops[2], 0, 1020)
+		if err == nil && v&3 != 0 {
+			err = fmt.Errorf("add: offset must be a multiple of 4: %q", i.ops[2])
+		}
+		return op | uint16(v/4), err
+	}
+	return i.hiReg(0)
+}
+
+func (i *thumbInsn) loadStore() (uint16, error) {
+	if len(i.ops) < 2 {
+		return 0, fmt.Errorf("%s: missing operands", i.name)
+	}
+	rd, err := parseLowReg(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	m, err := parseMem(i.ops[1:])
+	if err != nil {
+		return 0, err
+	}
+	if !m.pre || m.wb || !m.up || len(m.shift) != 0 {
+		return 0, fmt.Errorf("%s: invalid memory operand: %q", i.name, strings.Join(i.ops[1:], ", "))
+	}
+
+	if m.hasReg {
+		if m.rn >= 8 || m.off >= 8 {
+			return 0, fmt.Errorf("%s: only r0-r7 can be used", i.name)
+		}
+		return 0x5000 | thumbLoadStoreOps[i.name]<<9 | uint16(m.off)<<6 | uint16(m.rn)<<3 | uint16(rd), nil
+	}
+
+	load := i.name[0] == 'l'
+	var op uint16
+	var scale, max uint32
+	switch {
+	case (m.rn == 15 || m.rn == 13) && (i.name == "ldr" || i.name == "str"):
+		// PC-relative loads (from a literal pool) and SP-relative accesses
+		if m.off > 1020 || m.off&3 != 0 {
+			return 0, fmt.Errorf("%s: invalid offset: %#x", i.name, m.off)
+		}
+		op = 0x9000
+		switch {
+		case m.rn == 15 && !load:
+			return 0, fmt.Errorf("str: cannot store relative to pc")
+		case m.rn == 15:
+			op = 0x4800
+		case load:
+			op |= 1 << 11
+		}
+		return op | uint16(rd)<<8 | uint16(m.off/4), nil
+	case m.rn >= 8:
+		return 0, fmt.Errorf("%s: only r0-r7 can be used", i.name)
+	case i.name == "ldr" || i.name == "str":
+		op |= 0x6000
+		scale, max = 4, 124
+	case i.name == "ldrb" || i.name == "strb":
+		op |= 0x7000
+		scale, max = 1, 31
+	case i.name == "ldrh" || i.name == "strh":
+		op |= 0x8000
+		scale, max = 2, 62
+	default:
+		return 0, fmt.Errorf("%s: immediate offset not supported", i.name)
+	}
+	if m.off > max || m.off%scale != 0 {
+		return 0, fmt.Errorf("%s: invalid offset: %#x", i.name, m.off)
+	}
+	if load {
+		op |= 1 << 11
+	}
+	return op | uint16(m.off/scale)<<6 | uint16(m.rn)<<3 | uint16(rd), nil
+}
+
+func (i *thumbInsn) pushPop() (uint16, error) {
+	if err := i.nops(1); err != nil {
+		return 0, err
+	}
+	list, err := parseRegList(i.ops[0])
+	if err != nil {
+		return 0, err
+	}
+	op, extra := uint16(0xB400), uint32(1<<14)
+	if i.name == "pop" {
+		op, extra = 0xBC00, 1<<15
+	}
+	if list&extra != 0 {
+		op |= 1 << 8
+		list &^= extra
+	}
+	if list > 0xFF {
+		return 0, fmt.Errorf("%s: invalid register list: %q", i.name, i.ops[0])
+	}
+	return op | uint16(list), nil
+}
+
+func (i *thumbInsn) block() (uint16, error) {
+	if err := i.nops(2); err != nil {
+		return 0, err
+	}
+	load := i.name[0] == 'l'
+	base := i.ops[0]
+	wb := strings.HasSuffix(base, "!")
+	rb, err := parseLowReg(strings.TrimSpace(strings.TrimSuffix(base, "!")))
+	if err != nil {
+		return 0, err
+	}
+	list, err := parseRegList(i.ops[1])
+	if err != nil {
+		return 0, err
+	}
+	if list > 0xFF || list == 0 {
+		return 0, fmt.Errorf("%s: invalid register list: %q", i.name, i.ops[1])
+	}
+	// Writeback is implicit, and it's ignored when the base is loaded
+	if wb == (load && list&(1<<rb) != 0) {
+		return 0, fmt.Errorf("%s: invalid writeback: %q", i.name, base)
+	}
+	op := 0xC000 | uint16(rb)<<8 | uint16(list)
+	if load {
+		op |= 1 << 11
+	}
+	return op, nil
+}
+
+func (i *thumbInsn) branch(cond uint32) (uint16, error) {
+	if err := i.nops(1); err != nil {
+		return 0, err
+	}
+	off, err := parseTarget(i.ops[0], i.addr+4)
+	if err != nil {
+		return 0, err
+	}
+	if off&1 != 0 {
+		return 0, fmt.Errorf("%s: misaligned branch target: %s", i.name, i.ops[0])
+	}
+	if cond == 14 {
+		if off < -2048 || off >= 2048 {
+			return 0, fmt.Errorf("%s: branch target out of range: %s", i.name, i.ops[0])
+		}
+		return 0xE000 | uint16(off>>1)&0x7FF, nil
+	}
+	if off < -256 || off >= 256 {
+		return 0, fmt.Errorf("%s: branch target out of range: %s", i.name, i.ops[0])
+	}
+	return 0xD000 | uint16(cond)<<8 | uint16(off>>1)&0xFF, nil
+}
+
+// call assembles the BL/BLX pair: the first half sets the high part of the
+// offset in LR, the second one adds the low part and branches
+func (i *thumbInsn) call() ([]uint16, error) {
+	if err := i.nops(1); err != nil {
+		return nil, err
+	}
+	lo, base := uint16(0xF800), i.addr+4
+	if i.name == "blx" {
+		// Switch to ARM: the offset is relative to the word-aligned PC
+		if i.arch < disasm.ARMv5 {
+			return nil, fmt.Errorf("blx: not supported on ARMv4")
+		}
+		lo, base = 0xE800, base&^3
+	}
+	off, err := parseTarget(i.ops[0], base)
+	if err != nil {
+		return nil, err
+	}
+	if off&1 != 0 || i.name == "blx" && off&3 != 0 {
+		return nil, fmt.Errorf("%s: misaligned branch target: %s", i.name, i.ops[0])
+	}
+	if off < -1<<22 || off >= 1<<22 {
+		return nil, fmt.Errorf("%s: branch target out of range: %s", i.name, i.ops[0])
+	}
+	return []uint16{0xF000 | uint16(off>>12)&0x7FF, lo | uint16(off>>1)&0x7FF}, nil
+}
@@ -242,6 +242,12 @@ func (c *Cp15) ConfigureTcm(itcmSize int, dtcmSize int) {
 	c.dtcmSizeMask = uint32(dtcmSize - 1)
 }
 
+// DCacheEnabled returns whether the data cache is enabled in the control
+// register. The cache itself is not emulated.
+func (c *Cp15) DCacheEnabled() bool {
+	return c.regControl.Bit(2)
+}
+
 // Configure the CP15 Control Register. Value is the initial value of the register,
 // while rwmask specifies which bits can be modified at runtime, and which bits
 // are fixed.
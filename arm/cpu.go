@@ -3,6 +3,7 @@ package arm
 import (
 	"ndsemu/emu"
 	"ndsemu/emu/debugger"
+	"ndsemu/emu/hwio"
 	"ndsemu/emu/jit"
 	log "ndsemu/emu/logger"
 )
@@ -41,14 +42,20 @@ type Cpu struct {
 
 	arch  Arch
 	bus   emu.Bus
+	table *hwio.Table // bus, if it's a hwio.Table (to access it without interface calls)
 	pc    reg
 	cp15  *Cp15
 	cops  [16]Coprocessor
 	lines Line
 	jit   *jit.Jit
 
-	// Optional HLE implementation of SWIs
-	swiHle [256]func(cpu *Cpu) int64
+	// Number of cycles executed by JIT-compiled code
+	JitCycles int64
+
+	// Optional HLE implementation of SWIs, and the calls whose duration
+	// has not been fully accounted yet (see chargeSwiHle)
+	swiHle     [256]func(cpu *Cpu) int64
+	swiPending []swiHleCall
 
 	// Store the previous PC, used for debugging (eg: jumping into nowhere)
 	prevpc reg
@@ -56,8 +63,22 @@ type Cpu struct {
 	// Number of cycles consumed when accessing the external bus
 	memCycles    int64
 	targetCycles int64
+	runTarget    int64 // target of Run (targetCycles can be lower, see irqAt)
 	tightExit    bool
 
+	// IRQ latency (see SetIrqLatency), and earliest clock at which the
+	// pending IRQ can be taken
+	irqLatency int64
+	irqAt      int64
+
+	// Wait states of code fetches (see SetFetchWaitStates), and whether
+	// the next fetch is non-sequential
+	fetchWait   *[256]fetchRegion
+	fetchNonSeq bool
+
+	// Extra cycles of data accesses to the bus (see SetMemPenalty)
+	memPenalty func(addr uint32) int64
+
 	// manual tracing support
 	DebugTrace int
 	dbg        debugger.CpuDebugger
@@ -65,6 +86,7 @@ type Cpu struct {
 
 func NewCpu(arch Arch, bus emu.Bus, dojit bool) *Cpu {
 	cpu := &Cpu{bus: bus, arch: arch}
+	cpu.table, _ = bus.(*hwio.Table)
 	cpu.Cpsr._mode = 0x13 // mode supervisor
 	cpu.memCycles = int64(bus.WaitStates() + 1)
 	if dojit {
@@ -163,7 +185,13 @@ func (cpu *Cpu) Exception(exc Exception) {
 	// installed for this. If so, run it and then immediately exit,
 	// without triggering a real exception in the ARM core.
 	if exc == ExceptionSwi {
+		if cpu.resumeSwiHle(pc) {
+			return
+		}
 		num := cpu.Read16(uint32(pc-2)) & 0xFF
+		if cpu.dbg != nil {
+			cpu.dbg.Exception(debugger.ExcSwi, uint8(num))
+		}
 		if hle := cpu.swiHle[num]; hle != nil {
 			// cpu.breakpoint("hle")
 			log.ModCpu.InfoZ("SWI - HLE emulation").
@@ -171,7 +199,7 @@ func (cpu *Cpu) Exception(exc Exception) {
 				Uint16("exc", uint16(exc)).
 				End()
 			delay := hle(cpu)
-			cpu.Clock += delay + 3
+			cpu.chargeSwiHle(pc, delay+3)
 			return
 		}
 		log.ModCpu.InfoZ("SWI").
@@ -183,6 +211,9 @@ func (cpu *Cpu) Exception(exc Exception) {
 			Hex32("LR", uint32(pc)).
 			Int("arch", int(cpu.arch)).
 			End()
+		if cpu.dbg != nil {
+			cpu.dbg.Exception(debugger.ExcKind(exc), 0)
+		}
 	}
 
 	oldcpsr := cpu.Cpsr.Uint32()
@@ -210,6 +241,82 @@ func (cpu *Cpu) Exception(exc Exception) {
 	cpu.Clock += 3
 }
 
+// Maximum number of cycles that a HLE SWI call runs without allowing
+// interrupts. The BIOS executes SWIs with the interrupt flags of the
+// caller, so a long call (eg: a big CpuSet) can be interrupted anywhere.
+const cSwiHleSlice = 512
+
+// A HLE SWI call still in progress: the SWI opcode at pc-size is
+// re-executed until its duration is fully accounted.
+type swiHleCall struct {
+	pc        reg
+	sp        reg
+	mode      CpuMode
+	remaining int64
+}
+
+// chargeSwiHle advances the clock for the duration of a HLE SWI call. The
+// effects of the call are immediate, but if it's longer than a slice, the
+// CPU is rewound to the SWI opcode and the rest of the duration is spent
+// in further slices (see resumeSwiHle), so that interrupts raised in the
+// meantime are serviced with a realistic latency instead of at the end of
+// the call.
+func (cpu *Cpu) chargeSwiHle(pc reg, cycles int64) {
+	if cycles <= cSwiHleSlice {
+		cpu.Clock += cycles
+		return
+	}
+	cpu.Clock += cSwiHleSlice
+	if len(cpu.swiPending) >= 16 {
+		// Calls never resumed (eg: interrupted by a thread switch, and the
+		// thread was destroyed); drop the oldest
+		cpu.swiPending = append(cpu.swiPending[:0], cpu.swiPending[1:]...)
+	}
+	cpu.swiPending = append(cpu.swiPending, swiHleCall{
+		pc:        pc,
+		sp:        cpu.Regs[13],
+		mode:      cpu.Cpsr.GetMode(),
+		remaining: cycles - cSwiHleSlice,
+	})
+	cpu.rewindSwi(pc)
+}
+
+// resumeSwiHle checks whether the SWI being executed is the continuation of
+// a HLE call in progress and, if so, charges the next slice. Calls are
+// matched by address and stack pointer, so that the same SWI called by an
+// interrupt handler (or another thread) while the first call is in progress
+// is run as a new call.
+func (cpu *Cpu) resumeSwiHle(pc reg) bool {
+	mode := cpu.Cpsr.GetMode()
+	for i := len(cpu.swiPending) - 1; i >= 0; i-- {
+		call := &cpu.swiPending[i]
+		if call.pc != pc || call.sp != cpu.Regs[13] || call.mode != mode {
+			continue
+		}
+		if call.remaining > cSwiHleSlice {
+			cpu.Clock += cSwiHleSlice
+			call.remaining -= cSwiHleSlice
+			cpu.rewindSwi(pc)
+		} else {
+			cpu.Clock += call.remaining
+			cpu.swiPending = append(cpu.swiPending[:i], cpu.swiPending[i+1:]...)
+		}
+		return true
+	}
+	return false
+}
+
+// rewindSwi moves the PC back to the SWI opcode preceding pc, exiting the
+// tight loop so that pending interrupts are checked before re-executing it.
+func (cpu *Cpu) rewindSwi(pc reg) {
+	if cpu.Cpsr.T() {
+		cpu.pc = pc - 2
+	} else {
+		cpu.pc = pc - 4
+	}
+	cpu.tightExit = true
+}
+
 // Install a high-level emulation function for a specific SWI call.
 // This function can be used to simulate specific SWI calls (usually
 // implemented by the BIOS/OS) replacing them with code within code
@@ -226,7 +333,8 @@ func (cpu *Cpu) Exception(exc Exception) {
 // access the cpu.Regs array anyway.
 // The return value is the number of cycles that we should advance the CPU
 // clock of; it should correspond to a value closer to the time the real
-// function would have taken, were it fully interpreted.
+// function would have taken, were it fully interpreted. Long calls are
+// split into slices, allowing interrupts in between (see chargeSwiHle).
 func (cpu *Cpu) SetSwiHle(swi uint8, hle func(cpu *Cpu) int64) {
 	cpu.swiHle[swi] = hle
 }
@@ -245,6 +353,9 @@ func (cpu *Cpu) SetLine(line Line, val bool) {
 		if cpu.lines^line != 0 {
 			cpu.tightExit = true
 		}
+		if line&LineIrq != 0 && cpu.lines&LineIrq == 0 {
+			cpu.irqAt = cpu.Clock + cpu.irqLatency
+		}
 		cpu.lines |= line
 		// Asserting IRQ/FIQ line immediately releases the HALT
 		// status (even if interrupts are masked in CPSR flags)
@@ -256,9 +367,37 @@ func (cpu *Cpu) SetLine(line Line, val bool) {
 	}
 }
 
+// SetIrqLatency configures the number of cycles between the assertion of the
+// IRQ line and the moment the CPU takes the interrupt (the synchronization
+// delay of the interrupt controller). When it's not zero, clearing the I bit
+// in CPSR also has the effect of the pipeline: the next opcode is always
+// executed before a pending IRQ is taken. The default is zero: IRQs are taken
+// as soon as the current opcode is finished.
+func (cpu *Cpu) SetIrqLatency(cycles int64) {
+	cpu.irqLatency = cycles
+}
+
+// SetMemPenalty installs a function returning the cycles to add to a data
+// access to the bus at the specified address, on top of the fixed wait
+// states of the bus (a negative value makes the access faster). It is not
+// called for TCM accesses, nor for code fetches (see SetFetchWaitStates).
+// Pass nil to remove it.
+func (cpu *Cpu) SetMemPenalty(fn func(addr uint32) int64) {
+	cpu.memPenalty = fn
+}
+
+// irqUnmasked is called when the I bit of CPSR is cleared
+func (cpu *Cpu) irqUnmasked() {
+	if cpu.irqLatency != 0 && cpu.irqAt <= cpu.Clock {
+		cpu.irqAt = cpu.Clock + 1
+	}
+}
+
 func (cpu *Cpu) Reset() {
 	cpu.pc = 0
 	cpu.prevpc = 0
 	cpu.Clock = 0
+	cpu.irqAt = 0
+	cpu.swiPending = nil
 	cpu.Exception(ExceptionReset)
 }
@@ -0,0 +1,237 @@
+package arm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSwiHleSlices(t *testing.T) {
+	bus := &debugBus{RandData: make([]uint32, 256)}
+	for i := range bus.RandData {
+		bus.RandData[i] = 0x12
+	}
+	cpu := NewCpu(ARMv4, bus, false)
+
+	calls := 0
+	cpu.SetSwiHle(0x12, func(cpu *Cpu) int64 {
+		calls++
+		return 3*cSwiHleSlice - 3
+	})
+
+	// Execute the SWI at 0x100 (the only bus access is reading its number)
+	// until the CPU moves past it
+	swi := func() int {
+		n := 0
+		cpu.pc = 0x104
+		for {
+			n++
+			cpu.Exception(ExceptionSwi)
+			if cpu.pc != 0x100 {
+				return n
+			}
+			cpu.pc = 0x104
+		}
+	}
+
+	cpu.Regs[13] = 0x1000
+	if n := swi(); n != 3 || calls != 1 || cpu.Clock != 3*cSwiHleSlice+cpu.memCycles {
+		t.Errorf("got %d slices, %d calls, %d cycles", n, calls, cpu.Clock)
+	}
+
+	// Interrupt the call after the first slice, and call the same SWI again
+	// from the interrupt handler (different stack)
+	cpu.pc = 0x104
+	cpu.Exception(ExceptionSwi)
+	cpu.Regs[13] = 0x800
+	swi()
+	cpu.Regs[13] = 0x1000
+	if n := swi(); n != 2 || calls != 3 || len(cpu.swiPending) != 0 {
+		t.Errorf("resumed call: got %d slices, %d calls, %d pending", n, calls, len(cpu.swiPending))
+	}
+}
+
+func TestThumbLongBranchFusion(t *testing.T) {
+	// Cycles of the two halves: without wait states, and with sequential
+	// wait states (S=2), where on ARMv5 the second half (at a word-aligned
+	// address) is fetched together with the first one.
+	for _, tc := range []struct {
+		arch   Arch
+		wait   FetchWait
+		c1, c2 int64
+	}{
+		{ARMv5, FetchWait{}, 1, 1},
+		{ARMv4, FetchWait{N: 2, S: 2}, 3, 3},
+		{ARMv5, FetchWait{N: 2, S: 2}, 3, 1},
+	} {
+		for _, op2 := range []uint16{0xF80A, 0xE80A} { // BL, BLX
+			mem := []byte{0x01, 0xF0, byte(op2), byte(op2 >> 8)} // offset 0x1014
+			cpu := NewCpu(tc.arch, &debugBus{LinearMem: mem}, false)
+			cpu.Cpsr.SetT(true, cpu)
+			cpu.SetFetchWaitStates(0x0, 0xFFFFFF, FetchWait{}, tc.wait)
+			cpu.SetPC(0x1000)
+			cpu.Run(1)
+
+			// Run the two halves separately, as the tight loop would do
+			// without fusion
+			exp := NewCpu(tc.arch, &debugBus{}, false)
+			exp.Cpsr.SetT(true, exp)
+			exp.pc, exp.Regs[15] = 0x1002, 0x1004
+			exp.Clock += tc.c1
+			exp.opThumbF0(0xF001)
+			exp.pc, exp.Regs[15] = 0x1004, 0x1006
+			exp.Clock += tc.c2
+			opThumbTable[op2>>8](exp, op2)
+
+			if cpu.pc != exp.pc || cpu.Regs[14] != exp.Regs[14] || cpu.Clock != exp.Clock || cpu.Cpsr.T() != exp.Cpsr.T() {
+				t.Errorf("ARMv%d %+v %04x: got pc=%v lr=%v clk=%d t=%v, want pc=%v lr=%v clk=%d t=%v",
+					tc.arch, tc.wait, op2,
+					cpu.pc, cpu.Regs[14], cpu.Clock, cpu.Cpsr.T(),
+					exp.pc, exp.Regs[14], exp.Clock, exp.Cpsr.T())
+			}
+		}
+	}
+}
+
+func TestFetchWaitStates(t *testing.T) {
+	nops := []byte{0xC0, 0x46, 0xC0, 0x46, 0xC0, 0x46, 0xC0, 0x46} // mov r8, r8
+
+	// Non-sequential fetch (N=5), then sequential ones (S=2): on ARMv5,
+	// opcodes at odd halfwords come for free with the previous fetch.
+	for _, tc := range []struct {
+		arch Arch
+		clk  int64
+	}{{ARMv4, 6 + 3 + 3 + 3}, {ARMv5, 6 + 1 + 3 + 1}} {
+		cpu := NewCpu(tc.arch, &debugBus{LinearMem: nops}, false)
+		cpu.Cpsr.SetT(true, cpu)
+		cpu.SetFetchWaitStates(0x0, 0xFFFFFF, FetchWait{}, FetchWait{N: 5, S: 2})
+		cpu.SetPC(0x1000)
+		cpu.fetchNonSeq = true
+		cpu.Run(tc.clk)
+
+		if cpu.pc != 0x1008 || cpu.Clock != tc.clk {
+			t.Errorf("ARMv%d: got pc=%v clk=%d, want pc=00001008 clk=%d", tc.arch, cpu.pc, cpu.Clock, tc.clk)
+		}
+	}
+}
+
+func TestIrqLatency(t *testing.T) {
+	nops := make([]byte, 4096)
+	for i := 0; i < len(nops); i += 4 {
+		copy(nops[i:], []byte{0x00, 0x00, 0xA0, 0xE1}) // mov r0, r0
+	}
+	cpu := NewCpu(ARMv4, &debugBus{LinearMem: nops}, false)
+	cpu.SetIrqLatency(5)
+	cpu.SetPC(0x1000)
+
+	// The IRQ is taken only after the latency
+	cpu.SetLine(LineIrq, true)
+	cpu.Run(5)
+	if cpu.Cpsr.GetMode() == CpuModeIrq || cpu.Clock != 5 {
+		t.Fatalf("IRQ taken too early (clk=%d)", cpu.Clock)
+	}
+	cpu.Run(6)
+	if cpu.Cpsr.GetMode() != CpuModeIrq {
+		t.Fatalf("IRQ not taken")
+	}
+
+	// Unmasking a pending IRQ lets one more opcode run
+	cpu.Cpsr.SetMode(CpuModeSupervisor, cpu)
+	cpu.Run(cpu.Clock + 10)
+	cpu.Cpsr.SetI(false, cpu)
+	cpu.Run(cpu.Clock + 1)
+	if cpu.Cpsr.GetMode() == CpuModeIrq {
+		t.Fatalf("IRQ taken right after unmasking")
+	}
+	cpu.Run(cpu.Clock + 1)
+	if cpu.Cpsr.GetMode() != CpuModeIrq {
+		t.Fatalf("IRQ not taken after unmasking")
+	}
+}
+
+func TestCpuState(t *testing.T) {
+	cpu := NewCpu(ARMv5, &debugBus{}, false)
+	cpu.EnableCp15()
+	for i := range cpu.Regs {
+		cpu.Regs[i] = reg(i * 0x11)
+	}
+	cpu.Cpsr.SetMode(CpuModeFiq, cpu)
+	cpu.Regs[8] = 0xF8
+	cpu.Cpsr.SetT(true, cpu)
+	cpu.SetPC(0x02000100)
+	cpu.Clock = 12345
+	cpu.SetLine(LineHalt, true)
+
+	other := NewCpu(ARMv5, &debugBus{}, false)
+	other.EnableCp15()
+	other.SetState(cpu.State())
+
+	var h1, h2 bytes.Buffer
+	cpu.HashState(&h1)
+	other.HashState(&h2)
+	if !bytes.Equal(h1.Bytes(), h2.Bytes()) {
+		t.Errorf("state not restored")
+	}
+
+	// Banks are restored as they were, so switching back to supervisor
+	// mode (the initial one) must work
+	other.Cpsr.SetMode(CpuModeSupervisor, other)
+	if other.Regs[8] != 0x88 || other.Regs[13] != 0xDD {
+		t.Errorf("invalid banks after mode switch: r8=%x sp=%x", uint32(other.Regs[8]), uint32(other.Regs[13]))
+	}
+}
+
+func TestCpuStateSwiHleSlice(t *testing.T) {
+	newCpu := func(calls *int) *Cpu {
+		bus := &debugBus{RandData: make([]uint32, 256)}
+		for i := range bus.RandData {
+			bus.RandData[i] = 0x12
+		}
+		cpu := NewCpu(ARMv4, bus, false)
+		cpu.SetSwiHle(0x12, func(cpu *Cpu) int64 {
+			*calls++
+			return 3*cSwiHleSlice - 3
+		})
+		return cpu
+	}
+
+	// Save the state after the first slice of the call
+	var calls, calls2 int
+	cpu := newCpu(&calls)
+	cpu.Regs[13] = 0x1000
+	cpu.pc = 0x104
+	cpu.Exception(ExceptionSwi)
+	if cpu.pc != 0x100 || len(cpu.swiPending) != 1 {
+		t.Fatalf("call not sliced: pc=%v, %d pending", cpu.pc, len(cpu.swiPending))
+	}
+
+	other := newCpu(&calls2)
+	other.SetState(cpu.State())
+
+	var h1, h2 bytes.Buffer
+	cpu.HashState(&h1)
+	other.HashState(&h2)
+	if !bytes.Equal(h1.Bytes(), h2.Bytes()) {
+		t.Errorf("state not restored")
+	}
+
+	// The restored CPU must complete the call in the remaining slices,
+	// without running it again
+	n := 0
+	for other.pc == 0x100 {
+		n++
+		other.pc = 0x104
+		other.Exception(ExceptionSwi)
+	}
+	if n != 2 || calls2 != 0 || len(other.swiPending) != 0 {
+		t.Errorf("got %d slices, %d calls, %d pending", n, calls2, len(other.swiPending))
+	}
+
+	// The pending call is part of the hash
+	h1.Reset()
+	cpu.HashState(&h1)
+	h2.Reset()
+	other.HashState(&h2)
+	if bytes.Equal(h1.Bytes(), h2.Bytes()) {
+		t.Errorf("pending calls not hashed")
+	}
+}
@@ -2,8 +2,10 @@ package arm
 
 import (
 	"bytes"
-	"encoding/binary"
 	"fmt"
+	"ndsemu/arm/asm"
+	"ndsemu/arm/disasm"
+	"ndsemu/emu"
 	"ndsemu/emu/debugger"
 	log "ndsemu/emu/logger"
 	"strconv"
@@ -118,11 +120,99 @@ func (cpu *Cpu) GetSpecialRegs() []string {
 	}
 }
 
+// Halted reports whether the CPU is halted, waiting for an interrupt
+func (cpu *Cpu) Halted() bool {
+	return cpu.lines&LineHalt != 0
+}
+
 func (cpu *Cpu) GetPc() uint32 {
 	//return uint32(cpu.Regs[15])
 	return uint32(cpu.GetPC())
 }
 
+// Peek8 reads a byte from the CPU address space (including TCM) for debugging
+// purposes: no cycles are consumed, and the access has no side effects on
+// the hardware (see emu.DebugBus).
+func (cpu *Cpu) Peek8(addr uint32) uint8 {
+	if cpu.cp15 != nil {
+		if ptr := cpu.cp15.CheckITcm(addr); ptr != nil {
+			return ptr[0]
+		}
+		if ptr := cpu.cp15.CheckDTcm(addr); ptr != nil {
+			return ptr[0]
+		}
+	}
+	if bus, ok := cpu.bus.(emu.DebugBus); ok {
+		return bus.Peek8(addr)
+	}
+	return 0
+}
+
+// ReadSensitive reports whether reading the specified address has side
+// effects on emulation (see hwio.Table.ReadSensitive). Peek8 still returns
+// the register contents without triggering them, but the value is the last
+// one stored, which might differ from what the CPU would read.
+func (cpu *Cpu) ReadSensitive(addr uint32) bool {
+	if cpu.cp15 != nil && (cpu.cp15.CheckITcm(addr) != nil || cpu.cp15.CheckDTcm(addr) != nil) {
+		return false
+	}
+	if cpu.table != nil {
+		return cpu.table.ReadSensitive(addr)
+	}
+	return false
+}
+
+// Poke8 writes a byte into the CPU address space for debugging purposes,
+// like Peek8. It returns false if the address is not mapped.
+func (cpu *Cpu) Poke8(addr uint32, val uint8) bool {
+	if cpu.jit != nil {
+		cpu.jit.Invalidate(addr)
+	}
+	if cpu.cp15 != nil {
+		if ptr := cpu.cp15.CheckITcm(addr); ptr != nil {
+			ptr[0] = val
+			return true
+		}
+		if ptr := cpu.cp15.CheckDTcm(addr); ptr != nil {
+			ptr[0] = val
+			return true
+		}
+	}
+	if bus, ok := cpu.bus.(emu.DebugBus); ok {
+		return bus.Poke8(addr, val)
+	}
+	return false
+}
+
+// Patch writes code at the specified address like Poke8, and invalidates
+// any JIT block that includes it.
+func (cpu *Cpu) Patch(addr uint32, code []byte) error {
+	for i, b := range code {
+		if !cpu.Poke8(addr+uint32(i), b) {
+			return fmt.Errorf("address %08x is not mapped", addr+uint32(i))
+		}
+	}
+	// Poke8 only invalidates the blocks starting at the patched addresses
+	if cpu.jit != nil {
+		cpu.jit.InvalidateRange(addr, len(code))
+	}
+	return nil
+}
+
+// Assemble assembles an instruction (in the syntax of package disasm) in
+// the current CPU mode (ARM or Thumb), and writes it at the specified
+// address (see Patch). It returns the encoded instruction.
+func (cpu *Cpu) Assemble(addr uint32, text string) ([]byte, error) {
+	buf, err := asm.Assemble(text, addr, cpu.Cpsr.T(), cpu.DisasmArch())
+	if err != nil {
+		return nil, err
+	}
+	if err := cpu.Patch(addr, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
 func (cpu *Cpu) SetDebugger(dbg debugger.CpuDebugger) {
 	cpu.dbg = dbg
 }
@@ -136,85 +226,38 @@ func (cpu *Cpu) breakpoint(msg string, args ...interface{}) {
 	}
 }
 
-var condName = [16]string{
-	"eq", "ne", "hs", "lo", "mi", "pl", "vs", "vc",
-	"hi", "ls", "ge", "lt", "gt", "le", "", ".ERROR",
-}
-
-func (cpu *Cpu) disasmAddCond(opname string, op uint32) string {
-	suff := condName[(op>>28)&0xF]
-	return opname + suff
-}
-
-var op2Shift = [4]string{"lsl", "lsr", "asr", "ror"}
-
-func (cpu *Cpu) disasmOp2(op uint32) string {
-	rmx := RegNames[op&0xF]
-	shift := op2Shift[(op>>5)&3]
-	if op&0x10 != 0 {
-		// shift by reg
-		return rmx + " " + shift + " " + RegNames[(op>>8)&0xF]
-	} else {
-		// shift by imm
-		shiftimm := int64((op >> 7) & 0x1F)
-		if shiftimm == 0 {
-			switch shift {
-			case "lsl":
-				return rmx
-			case "lsr":
-				return rmx + " lsr #32"
-			case "asr":
-				return rmx + " asr #32"
-			case "ror":
-				return rmx + " rrx #1"
-			}
-		}
-		return rmx + " " + shift + " #" + strconv.FormatInt(shiftimm, 10)
+// DisasmArch returns the architecture to use to disassemble the code run by
+// the CPU
+func (cpu *Cpu) DisasmArch() disasm.Arch {
+	if cpu.arch >= ARMv5 {
+		return disasm.ARMv5
 	}
+	return disasm.ARMv4
 }
 
-func (cpu *Cpu) disasmSpsrName() string {
-	switch cpu.Cpsr.GetMode() {
-	case CpuModeUser, CpuModeSystem:
-		return "spsr_ERR"
-	case CpuModeFiq:
-		return "spsr_fiq"
-	case CpuModeSupervisor:
-		return "spsr_svc"
-	case CpuModeAbort:
-		return "spsr_abt"
-	case CpuModeIrq:
-		return "spsr_irq"
-	case CpuModeUndefined:
-		return "spsr_und"
-	default:
-		panic("unreachable")
+// DisasmInsn decodes the instruction at the specified address, in the
+// current CPU mode (ARM or Thumb). Memory is read without side effects
+// (see Peek8). ok is false if the address is not mapped.
+func (cpu *Cpu) DisasmInsn(pc uint32) (insn disasm.Insn, ok bool) {
+	if cpu.opFetchPointer(pc) == nil {
+		return disasm.Insn{}, false
 	}
+	return disasm.Decode(cpu, pc, cpu.Cpsr.T(), cpu.DisasmArch()), true
 }
 
 func (cpu *Cpu) Disasm(pc uint32) (string, []byte) {
-	thumb := cpu.Cpsr.T()
-	if !thumb {
-		var buf [4]byte
-		mem := cpu.opFetchPointer(uint32(pc))
-		if mem == nil {
-			return "<unmapped memory>", []byte{0, 0, 0, 0}
-		}
-		op := uint32(mem[0]) | uint32(mem[1])<<8 | uint32(mem[2])<<16 | uint32(mem[3])<<24
-		n := disasmArmTable[((op>>16)&0xFF0)|((op>>4)&0xF)](cpu, op, pc)
-		binary.LittleEndian.PutUint32(buf[:], op)
-		return n, buf[:]
-	} else {
-		var buf [2]byte
-		mem := cpu.opFetchPointer(uint32(pc))
-		if mem == nil {
+	insn, ok := cpu.DisasmInsn(pc)
+	if !ok {
+		if cpu.Cpsr.T() {
 			return "<unmapped memory>", []byte{0, 0}
 		}
-		op := uint16(mem[0]) | uint16(mem[1])<<8
-		n := disasmThumbTable[(op>>8)&0xFF](cpu, op, pc)
-		binary.LittleEndian.PutUint16(buf[:], op)
-		return n, buf[:]
+		return "<unmapped memory>", []byte{0, 0, 0, 0}
+	}
+	buf := make([]byte, insn.Size)
+	for i := range buf {
+		buf[i] = byte(insn.Raw >> (8 * uint(i)))
 	}
+	return insn.String(), buf
 }
 
 func (cpu *Cpu) DumpStatus() {
@@ -0,0 +1,465 @@
+package disasm
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+var dpNames = [16]string{
+	"and", "eor", "sub", "rsb", "add", "adc", "sbc", "rsc",
+	"tst", "teq", "cmp", "cmn", "orr", "mov", "bic", "mvn",
+}
+
+var shiftNames = [4]string{"lsl", "lsr", "asr", "ror"}
+
+// Arm decodes a 32-bit ARM instruction located at addr
+func Arm(op uint32, addr uint32, arch Arch, mem Memory) Insn {
+	i := Insn{Addr: addr, Raw: op, Size: 4}
+	if op>>28 == 0xF {
+		return armUncond(i, arch)
+	}
+	cc := condNames[op>>28]
+
+	switch op >> 25 & 7 {
+	case 0:
+		return armGroup0(i, cc, arch, mem)
+	case 1:
+		if op&0x0FB00000 == 0x03200000 {
+			return armMsr(i, cc, imm(armImm(op)))
+		}
+		if op&0x01900000 == 0x01000000 {
+			return undefined(i)
+		}
+		return armDataProc(i, cc, []string{imm(armImm(op))})
+	case 2, 3:
+		if op&0x02000010 == 0x02000010 {
+			return undefined(i)
+		}
+		return armLoadStore(i, cc, mem)
+	case 4:
+		return armBlock(i, cc)
+	case 5:
+		i.Mnemonic = "b" + cc
+		if op&(1<<24) != 0 {
+			i.Mnemonic = "bl" + cc
+			i.Link = true
+		}
+		return armBranch(i, int32(op<<8)>>6)
+	case 6:
+		if arch >= ARMv5 && op&0x0FE00000 == 0x0C400000 {
+			name := "mcrr"
+			if op&(1<<20) != 0 {
+				name = "mrrc"
+			}
+			i.Mnemonic = name + cc
+			i.Operands = []string{fmt.Sprintf("p%d", op>>8&15), fmt.Sprint(op >> 4 & 15),
+				reg(op >> 12), reg(op >> 16), fmt.Sprintf("c%d", op&15)}
+			return i
+		}
+		return armCoproc(i, cc)
+	default:
+		if op&(1<<24) != 0 {
+			i.Mnemonic = "swi" + cc
+			i.Operands = []string{imm(op & 0xFFFFFF)}
+			return i
+		}
+		return armCoproc(i, cc)
+	}
+}
+
+// armUncond decodes the instructions with condition code NV, that are
+// only defined from ARMv5
+func armUncond(i Insn, arch Arch) Insn {
+	op := i.Raw
+	if arch < ARMv5 {
+		return undefined(i)
+	}
+	switch {
+	case op&0x0E000000 == 0x0A000000:
+		i.Mnemonic = "blx"
+		i.Link = true
+		i.ThumbTarget = true
+		return armBranch(i, int32(op<<8)>>6+int32(op>>24&1)*2)
+	case op&0x0D70F000 == 0x0550F000:
+		i.Mnemonic = "pld"
+		i.Operands = []string{armAddrMode2(op)}
+		return i
+	case op>>25&7 == 6, op>>24&15 == 0xE:
+		i = armCoproc(i, "")
+		i.Mnemonic += "2"
+		return i
+	}
+	return undefined(i)
+}
+
+func armBranch(i Insn, off int32) Insn {
+	i.HasTarget = true
+	i.Target = i.Addr + 8 + uint32(off)
+	i.Operands = []string{address(i.Target)}
+	return i
+}
+
+// armImm decodes the immediate operand of data processing instructions
+func armImm(op uint32) uint32 {
+	return bits.RotateLeft32(op&0xFF, -int(op>>8&15)*2)
+}
+
+// armShift decodes a shifted register operand, returning the register and
+// the shift (if any) as separate operands
+func armShift(op uint32) []string {
+	rm := reg(op)
+	typ := op >> 5 & 3
+	if op&0x10 != 0 {
+		return []string{rm, shiftNames[typ] + " " + reg(op>>8)}
+	}
+	amt := op >> 7 & 31
+	if amt == 0 {
+		switch typ {
+		case 0:
+			return []string{rm}
+		case 3:
+			return []string{rm, "rrx"}
+		default:
+			amt = 32
+		}
+	}
+	return []string{rm, fmt.Sprintf("%s #%d", shiftNames[typ], amt)}
+}
+
+// armGroup0 decodes the instructions with bits 27-25 clear. Like the CPU,
+// it ignores the bits that should be one or zero in each encoding.
+func armGroup0(i Insn, cc string, arch Arch, mem Memory) Insn {
+	op := i.Raw
+	v5 := arch >= ARMv5
+	switch {
+	case op&0x0FF000D0 == 0x01200010:
+		i.Mnemonic = "bx" + cc
+		if op&0x20 != 0 {
+			if !v5 {
+				return undefined(i)
+			}
+			i.Mnemonic = "blx" + cc
+			i.Link = true
+		}
+		i.Operands = []string{reg(op)}
+	case op&0x0FF000F0 == 0x01600010 && v5:
+		i.Mnemonic = "clz" + cc
+		i.Operands = []string{reg(op >> 12), reg(op)}
+	case op&0x0F9000F0 == 0x01000050 && v5:
+		i.Mnemonic = [4]string{"qadd", "qsub", "qdadd", "qdsub"}[op>>21&3] + cc
+		i.Operands = []string{reg(op >> 12), reg(op), reg(op >> 16)}
+	case op&0x0FF000F0 == 0x01200070 && v5:
+		i.Mnemonic = "bkpt"
+		i.Operands = []string{imm(op>>4&0xFFF0 | op&0xF)}
+	case op&0x0F900090 == 0x01000080 && v5:
+		return armDspMul(i, cc)
+	case op&0x0FB000F0 == 0x01000090:
+		i.Mnemonic = "swp" + cc
+		if op&(1<<22) != 0 {
+			i.Mnemonic = "swpb" + cc
+		}
+		i.Operands = []string{reg(op >> 12), reg(op), "[" + reg(op>>16) + "]"}
+	case op&0x0F0000F0 == 0x00000090:
+		return armMul(i, cc)
+	case op&0x0E000090 == 0x00000090 && op&0x60 != 0:
+		return armLoadStoreExtra(i, cc, arch, mem)
+	case op&0x0FB000F0 == 0x01000000:
+		i.Mnemonic = "mrs" + cc
+		i.Operands = []string{reg(op >> 12), "cpsr"}
+		if op&(1<<22) != 0 {
+			i.Operands[1] = "spsr"
+		}
+	case op&0x0FB000F0 == 0x01200000:
+		return armMsr(i, cc, reg(op))
+	case op&0x01900000 == 0x01000000, op&0x90 == 0x90:
+		return undefined(i)
+	default:
+		return armDataProc(i, cc, armShift(op))
+	}
+	return i
+}
+
+func armDataProc(i Insn, cc string, op2 []string) Insn {
+	op := i.Raw
+	opc := op >> 21 & 15
+	name := dpNames[opc]
+	rd, rn := reg(op>>12), reg(op>>16)
+	switch {
+	case opc >= 8 && opc <= 11:
+		// Test instructions always set flags, and have no destination
+		i.Mnemonic = name + cc
+		i.Operands = append([]string{rn}, op2...)
+		return i
+	case op&(1<<20) != 0:
+		name += "s"
+	}
+	i.Mnemonic = name + cc
+	if opc == 13 || opc == 15 {
+		i.Operands = append([]string{rd}, op2...)
+	} else {
+		i.Operands = append([]string{rd, rn}, op2...)
+	}
+	return i
+}
+
+func armMsr(i Insn, cc string, src string) Insn {
+	op := i.Raw
+	psr := "cpsr_"
+	if op&(1<<22) != 0 {
+		psr = "spsr_"
+	}
+	for n, f := range "cxsf" {
+		if op&(1<<(16+uint(n))) != 0 {
+			psr += string(f)
+		}
+	}
+	i.Mnemonic = "msr" + cc
+	i.Operands = []string{psr, src}
+	return i
+}
+
+func armMul(i Insn, cc string) Insn {
+	op := i.Raw
+	s := ""
+	if op&(1<<20) != 0 {
+		s = "s"
+	}
+	rd, rn, rs, rm := reg(op>>16), reg(op>>12), reg(op>>8), reg(op)
+	switch op >> 21 & 7 {
+	case 0:
+		i.Mnemonic = "mul" + s + cc
+		i.Operands = []string{rd, rm, rs}
+	case 1:
+		i.Mnemonic = "mla" + s + cc
+		i.Operands = []string{rd, rm, rs, rn}
+	case 4, 5, 6, 7:
+		name := [4]string{"umull", "umlal", "smull", "smlal"}[op>>21&3]
+		i.Mnemonic = name + s + cc
+		i.Operands = []string{rn, rd, rm, rs}
+	default:
+		return undefined(i)
+	}
+	return i
+}
+
+// armDspMul decodes the signed halfword multiplies of ARMv5TE
+func armDspMul(i Insn, cc string) Insn {
+	op := i.Raw
+	x, y := "b", "b"
+	if op&(1<<5) != 0 {
+		x = "t"
+	}
+	if op&(1<<6) != 0 {
+		y = "t"
+	}
+	rd, rn, rs, rm := reg(op>>16), reg(op>>12), reg(op>>8), reg(op)
+	switch op >> 21 & 3 {
+	case 0:
+		i.Mnemonic = "smla" + x + y + cc
+		i.Operands = []string{rd, rm, rs, rn}
+	case 1:
+		if x == "b" {
+			i.Mnemonic = "smlaw" + y + cc
+			i.Operands = []string{rd, rm, rs, rn}
+		} else {
+			i.Mnemonic = "smulw" + y + cc
+			i.Operands = []string{rd, rm, rs}
+		}
+	case 2:
+		i.Mnemonic = "smlal" + x + y + cc
+		i.Operands = []string{rn, rd, rm, rs}
+	default:
+		i.Mnemonic = "smul" + x + y + cc
+		i.Operands = []string{rd, rm, rs}
+	}
+	return i
+}
+
+// memOperand formats a memory operand, with pre or post indexing
+func memOperand(rn string, off string, pre, wb bool) string {
+	switch {
+	case !pre:
+		return "[" + rn + "], " + off
+	case off == "":
+		rn = "[" + rn + "]"
+	default:
+		rn = "[" + rn + ", " + off + "]"
+	}
+	if wb {
+		rn += "!"
+	}
+	return rn
+}
+
+// armAddrMode2 formats the memory operand of word and byte loads/stores
+func armAddrMode2(op uint32) string {
+	pre, up, wb := op&(1<<24) != 0, op&(1<<23) != 0, op&(1<<21) != 0
+	var off string
+	if op&(1<<25) == 0 {
+		if v := op & 0xFFF; v != 0 || !pre {
+			off = offset(v, up)
+		}
+	} else {
+		shift := armShift(op &^ 0x10)
+		if !up {
+			shift[0] = "-" + shift[0]
+		}
+		off = shift[0]
+		if len(shift) > 1 {
+			off += ", " + shift[1]
+		}
+	}
+	return memOperand(reg(op>>16), off, pre, wb && pre)
+}
+
+func armLoadStore(i Insn, cc string, mem Memory) Insn {
+	op := i.Raw
+	load, bytes := op&(1<<20) != 0, op&(1<<22) != 0
+	name := "str"
+	if load {
+		name = "ldr"
+	}
+	size := 4
+	if bytes {
+		name += "b"
+		size = 1
+	}
+	if op&(1<<24) == 0 && op&(1<<21) != 0 {
+		// Post-indexed with W: access with user privileges
+		name += "t"
+	}
+	i.Mnemonic = name + cc
+	i.Operands = []string{reg(op >> 12), armAddrMode2(op)}
+
+	// PC-relative load from a literal pool
+	if load && op&0x032F0000 == 0x010F0000 {
+		addr := i.Addr + 8 - op&0xFFF
+		if op&(1<<23) != 0 {
+			addr = i.Addr + 8 + op&0xFFF
+		}
+		i.Literal = literal(mem, addr, size)
+	}
+	return i
+}
+
+func armLoadStoreExtra(i Insn, cc string, arch Arch, mem Memory) Insn {
+	op := i.Raw
+	load := op&(1<<20) != 0
+	sh := op >> 5 & 3
+	var name string
+	var size int
+	switch {
+	case load:
+		name = [4]string{"", "ldrh", "ldrsb", "ldrsh"}[sh]
+		size = [4]int{0, 2, 1, 2}[sh]
+	case sh == 1:
+		name = "strh"
+	case arch < ARMv5:
+		return undefined(i)
+	case sh == 2:
+		name = "ldrd"
+	default:
+		name = "strd"
+	}
+
+	pre, up, wb := op&(1<<24) != 0, op&(1<<23) != 0, op&(1<<21) != 0
+	var off string
+	if op&(1<<22) != 0 {
+		if v := op>>4&0xF0 | op&0xF; v != 0 || !pre {
+			off = offset(v, up)
+		}
+	} else {
+		off = reg(op)
+		if !up {
+			off = "-" + off
+		}
+	}
+
+	i.Mnemonic = name + cc
+	i.Operands = []string{reg(op >> 12)}
+	if sh >= 2 && !load {
+		i.Operands = append(i.Operands, reg(op>>12+1))
+	}
+	i.Operands = append(i.Operands, memOperand(reg(op>>16), off, pre, wb && pre))
+
+	if load && op&0x016F0000 == 0x014F0000 {
+		v := op>>4&0xF0 | op&0xF
+		addr := i.Addr + 8 - v
+		if up {
+			addr = i.Addr + 8 + v
+		}
+		i.Literal = literal(mem, addr, size)
+	}
+	return i
+}
+
+func armBlock(i Insn, cc string) Insn {
+	op := i.Raw
+	load := op&(1<<20) != 0
+	wb := op&(1<<21) != 0
+	rn := op >> 16 & 15
+	mode := [4]string{"da", "", "db", "ib"}[op>>23&3]
+
+	list := regList(op & 0xFFFF)
+	if op&(1<<22) != 0 {
+		list += "^"
+	} else if rn == 13 && wb && (load && mode == "" || !load && mode == "db") {
+		i.Mnemonic = "push" + cc
+		if load {
+			i.Mnemonic = "pop" + cc
+		}
+		i.Operands = []string{list}
+		return i
+	}
+
+	i.Mnemonic = "stm" + mode + cc
+	if load {
+		i.Mnemonic = "ldm" + mode + cc
+	}
+	base := reg(rn)
+	if wb {
+		base += "!"
+	}
+	i.Operands = []string{base, list}
+	return i
+}
+
+// armCoproc decodes the coprocessor instructions (cdp, mcr/mrc, ldc/stc)
+func armCoproc(i Insn, cc string) Insn {
+	op := i.Raw
+	cp := fmt.Sprintf("p%d", op>>8&15)
+	crd, crn, crm := fmt.Sprintf("c%d", op>>12&15), fmt.Sprintf("c%d", op>>16&15), fmt.Sprintf("c%d", op&15)
+	opc2 := fmt.Sprint(op >> 5 & 7)
+
+	if op>>25&7 == 6 {
+		name := "stc"
+		if op&(1<<20) != 0 {
+			name = "ldc"
+		}
+		if op&(1<<22) != 0 {
+			name += "l"
+		}
+		pre, up, wb := op&(1<<24) != 0, op&(1<<23) != 0, op&(1<<21) != 0
+		var addr string
+		if !pre && !wb {
+			addr = "[" + reg(op>>16) + "], " + fmt.Sprintf("{%d}", op&0xFF)
+		} else {
+			addr = memOperand(reg(op>>16), offset((op&0xFF)*4, up), pre, wb && pre)
+		}
+		i.Mnemonic = name + cc
+		i.Operands = []string{cp, crd, addr}
+		return i
+	}
+
+	if op&0x10 == 0 {
+		i.Mnemonic = "cdp" + cc
+		i.Operands = []string{cp, fmt.Sprint(op >> 20 & 15), crd, crn, crm, opc2}
+		return i
+	}
+	i.Mnemonic = "mcr" + cc
+	if op&(1<<20) != 0 {
+		i.Mnemonic = "mrc" + cc
+	}
+	i.Operands = []string{cp, fmt.Sprint(op >> 21 & 7), reg(op >> 12), crn, crm, opc2}
+	return i
+}
@@ -0,0 +1,150 @@
+// Package disasm decodes ARM and Thumb instructions, as implemented by the
+// two CPUs of the NDS (ARMv4T for the ARM7, ARMv5TE for the ARM9), into a
+// structured form: mnemonic and operands, plus the information that can be
+// statically derived from the instruction, like the destination of direct
+// branches and the value of the literals loaded through PC-relative loads.
+//
+// The package is independent from the CPU emulation: memory (for literal
+// pools and Thumb BL pairs) is read through a Memory, which must not have
+// side effects on the emulated hardware.
+package disasm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Arch is the version of the ARM architecture to decode
+type Arch int
+
+const (
+	ARMv4 Arch = iota // ARM7TDMI (NDS ARM7, GBA)
+	ARMv5             // ARM946E-S (NDS ARM9): BLX, CLZ, DSP extensions...
+)
+
+// Memory gives access to the memory being disassembled. It can be nil, in
+// which case literals are not resolved.
+type Memory interface {
+	Peek8(addr uint32) uint8
+}
+
+// Literal is a value referenced by a PC-relative load
+type Literal struct {
+	Addr  uint32
+	Size  int // 1, 2 or 4 bytes
+	Value uint32
+}
+
+// Insn is a decoded instruction
+type Insn struct {
+	Addr  uint32
+	Raw   uint32 // opcode (16-bit for Thumb)
+	Size  int    // 2 (Thumb) or 4 (ARM) bytes
+	Thumb bool
+
+	Mnemonic string   // including condition code and suffixes (eg: "addseq")
+	Operands []string // in assembler syntax (eg: "r0", "#0x10", "[r1, #0x4]!")
+	Invalid  bool     // undefined instruction (Mnemonic is "undefined")
+
+	// Destination of direct branches (b, bl, blx); ThumbTarget is true if
+	// the branch switches to (or stays in) Thumb mode.
+	HasTarget   bool
+	Target      uint32
+	ThumbTarget bool
+	Link        bool // the branch is a call (LR is set)
+
+	Literal *Literal // value loaded by a PC-relative load, if any
+}
+
+// String formats the instruction in assembler syntax. Branch targets are
+// shown as absolute addresses, and literals as a trailing comment.
+func (i Insn) String() string {
+	if len(i.Operands) == 0 {
+		return i.Mnemonic
+	}
+	s := fmt.Sprintf("%-10s%s", i.Mnemonic, strings.Join(i.Operands, ", "))
+	if l := i.Literal; l != nil {
+		s += fmt.Sprintf(" ; =0x%0*x", l.Size*2, l.Value)
+	}
+	return s
+}
+
+// Decode decodes the instruction at the specified address, reading it from
+// memory
+func Decode(mem Memory, addr uint32, thumb bool, arch Arch) Insn {
+	if thumb {
+		return Thumb(uint16(peek(mem, addr, 2)), addr, arch, mem)
+	}
+	return Arm(peek(mem, addr, 4), addr, arch, mem)
+}
+
+var regNames = [16]string{
+	"r0", "r1", "r2", "r3", "r4", "r5", "r6", "r7",
+	"r8", "r9", "r10", "r11", "r12", "sp", "lr", "pc",
+}
+
+var condNames = [16]string{
+	"eq", "ne", "hs", "lo", "mi", "pl", "vs", "vc",
+	"hi", "ls", "ge", "lt", "gt", "le", "", "nv",
+}
+
+func reg(n uint32) string { return regNames[n&15] }
+
+// regList formats a register list, collapsing ranges (eg: "{r0-r3, lr}")
+func regList(mask uint32) string {
+	var parts []string
+	for i := uint32(0); i < 16; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		j := i
+		for j < 15 && mask&(1<<(j+1)) != 0 {
+			j++
+		}
+		switch {
+		case j == i:
+			parts = append(parts, reg(i))
+		case j == i+1:
+			parts = append(parts, reg(i), reg(j))
+		default:
+			parts = append(parts, reg(i)+"-"+reg(j))
+		}
+		i = j
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func imm(v uint32) string { return fmt.Sprintf("#0x%x", v) }
+
+// offset formats a signed immediate offset, as used in memory operands
+func offset(v uint32, up bool) string {
+	if up {
+		return fmt.Sprintf("#0x%x", v)
+	}
+	return fmt.Sprintf("#-0x%x", v)
+}
+
+func address(addr uint32) string { return fmt.Sprintf("0x%08x", addr) }
+
+func peek(mem Memory, addr uint32, size int) uint32 {
+	var val uint32
+	for i := 0; i < size; i++ {
+		val |= uint32(mem.Peek8(addr+uint32(i))) << (8 * uint(i))
+	}
+	return val
+}
+
+// literal reads the value of a literal, if memory is available
+func literal(mem Memory, addr uint32, size int) *Literal {
+	if mem == nil {
+		return nil
+	}
+	return &Literal{Addr: addr, Size: size, Value: peek(mem, addr, size)}
+}
+
+func undefined(i Insn) Insn {
+	i.Mnemonic = "undefined"
+	i.Operands = []string{imm(i.Raw)}
+	i.Invalid = true
+	return i
+}
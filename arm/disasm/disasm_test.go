@@ -0,0 +1,108 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// testMem is a memory area starting at address 0x2000
+type testMem []byte
+
+func (m testMem) Peek8(addr uint32) uint8 {
+	if addr-0x2000 < uint32(len(m)) {
+		return m[addr-0x2000]
+	}
+	return 0
+}
+
+func TestArm(t *testing.T) {
+	mem := make(testMem, 0x20)
+	binary.LittleEndian.PutUint32(mem[0xC:], 0xCAFEBABE)
+
+	for _, tc := range []struct {
+		op   uint32
+		arch Arch
+		want string
+	}{
+		{0xE3A00001, ARMv4, "mov       r0, #0x1"},
+		{0xE0910002, ARMv4, "adds      r0, r1, r2"},
+		{0xE1A00102, ARMv4, "mov       r0, r2, lsl #2"},
+		{0x01500311, ARMv4, "cmpeq     r0, r1, lsl r3"},
+		{0x1A000000, ARMv4, "bne       0x00002008"},
+		{0xEBFFFFFE, ARMv4, "bl        0x00002000"},
+		{0xFA000000, ARMv5, "blx       0x00002008"},
+		{0xFB000000, ARMv5, "blx       0x0000200a"},
+		{0xFA000000, ARMv4, "undefined #0xfa000000"},
+		{0xE59F0004, ARMv4, "ldr       r0, [pc, #0x4] ; =0xcafebabe"},
+		{0xE4910004, ARMv4, "ldr       r0, [r1], #0x4"},
+		{0xE7310102, ARMv4, "ldr       r0, [r1, -r2, lsl #2]!"},
+		{0xE92D4010, ARMv4, "push      {r4, lr}"},
+		{0xE8BD800F, ARMv4, "pop       {r0-r3, pc}"},
+		{0xE8D00006, ARMv4, "ldm       r0, {r1, r2}^"},
+		{0xE12FFF1E, ARMv4, "bx        lr"},
+		{0xE16F0F11, ARMv5, "clz       r0, r1"},
+		{0xEE110F10, ARMv5, "mrc       p15, 0, r0, c1, c0, 0"},
+		{0xE10F0000, ARMv4, "mrs       r0, cpsr"},
+		{0xE121F000, ARMv4, "msr       cpsr_c, r0"},
+		{0xE1C020D4, ARMv5, "ldrd      r2, r3, [r0, #0x4]"},
+		{0xE1D000B2, ARMv4, "ldrh      r0, [r0, #0x2]"},
+		{0xE0000291, ARMv4, "mul       r0, r1, r2"},
+		{0xE0C10392, ARMv4, "smull     r0, r1, r2, r3"},
+		{0xE16002C1, ARMv5, "smulbt    r0, r1, r2"},
+		{0xEF000005, ARMv4, "swi       #0x5"},
+	} {
+		if got := Arm(tc.op, 0x2000, tc.arch, mem).String(); got != tc.want {
+			t.Errorf("%08x: got %q, want %q", tc.op, got, tc.want)
+		}
+	}
+}
+
+func TestThumb(t *testing.T) {
+	mem := make(testMem, 0x20)
+	binary.LittleEndian.PutUint32(mem[0x8:], 0x12345678)
+	binary.LittleEndian.PutUint16(mem[0x2:], 0xF802) // second half of BL
+
+	for _, tc := range []struct {
+		op   uint16
+		arch Arch
+		want string
+	}{
+		{0x2001, ARMv4, "movs      r0, #0x1"},
+		{0x0000, ARMv4, "movs      r0, r0"},
+		{0x0888, ARMv4, "lsrs      r0, r1, #2"},
+		{0x1C08, ARMv4, "adds      r0, r1, #0x0"},
+		{0x4240, ARMv4, "negs      r0, r0"},
+		{0x4801, ARMv4, "ldr       r0, [pc, #0x4] ; =0x12345678"},
+		{0x8848, ARMv4, "ldrh      r0, [r1, #0x2]"},
+		{0x5C88, ARMv4, "ldrb      r0, [r1, r2]"},
+		{0xB510, ARMv4, "push      {r4, lr}"},
+		{0xBD10, ARMv4, "pop       {r4, pc}"},
+		{0xC903, ARMv4, "ldmia     r1, {r0, r1}"},
+		{0xD0FE, ARMv4, "beq       0x00002000"},
+		{0xE7FE, ARMv4, "b         0x00002000"},
+		{0xF000, ARMv4, "bl        0x00002008"},
+		{0xF802, ARMv4, "bl        lr, #0x4"},
+		{0x4770, ARMv4, "bx        lr"},
+		{0x4788, ARMv4, "undefined #0x4788"},
+		{0x4788, ARMv5, "blx       r1"},
+		{0xDE00, ARMv4, "undefined #0xde00"},
+		{0xDF01, ARMv4, "swi       #0x1"},
+	} {
+		if got := Thumb(tc.op, 0x2000, tc.arch, mem).String(); got != tc.want {
+			t.Errorf("%04x: got %q, want %q", tc.op, got, tc.want)
+		}
+	}
+
+	// Without memory, BL prefixes can only be decoded partially
+	if i := Thumb(0xF7FF, 0x2000, ARMv4, nil); i.String() != "sub       lr, pc, #0x1000" || i.HasTarget {
+		t.Errorf("BL prefix: got %q", i.String())
+	}
+}
+
+func TestDecodeTarget(t *testing.T) {
+	mem := testMem{0x00, 0xF0, 0x00, 0xE8} // blx +0
+	i := Decode(mem, 0x2000, true, ARMv5)
+	if !i.HasTarget || !i.Link || i.ThumbTarget || i.Target != 0x2004 {
+		t.Errorf("invalid blx target: %+v", i)
+	}
+}
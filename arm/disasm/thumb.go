@@ -0,0 +1,237 @@
+package disasm
+
+import (
+	"fmt"
+)
+
+var thumbAluNames = [16]string{
+	"ands", "eors", "lsls", "lsrs", "asrs", "adcs", "sbcs", "rors",
+	"tst", "negs", "cmp", "cmn", "orrs", "muls", "bics", "mvns",
+}
+
+// Thumb decodes a 16-bit Thumb instruction located at addr. The first half
+// of a BL/BLX pair is decoded together with the second one (read from
+// memory), and reports the destination of the call; the second half alone
+// is shown as a branch relative to LR.
+func Thumb(op uint16, addr uint32, arch Arch, mem Memory) Insn {
+	i := Insn{Addr: addr, Raw: uint32(op), Size: 2, Thumb: true}
+	rd, rs := reg(uint32(op)&7), reg(uint32(op)>>3&7)
+	imm8 := uint32(op) & 0xFF
+
+	switch op >> 11 {
+	case 0x00, 0x01, 0x02:
+		amt := uint32(op) >> 6 & 31
+		if amt == 0 && op>>11 != 0 {
+			amt = 32
+		}
+		i.Mnemonic = [3]string{"lsls", "lsrs", "asrs"}[op>>11]
+		i.Operands = []string{rd, rs, fmt.Sprintf("#%d", amt)}
+		if amt == 0 {
+			i.Mnemonic = "movs"
+			i.Operands = i.Operands[:2]
+		}
+	case 0x03:
+		i.Mnemonic = "adds"
+		if op&(1<<9) != 0 {
+			i.Mnemonic = "subs"
+		}
+		rn := reg(uint32(op) >> 6 & 7)
+		if op&(1<<10) != 0 {
+			rn = imm(uint32(op) >> 6 & 7)
+		}
+		i.Operands = []string{rd, rs, rn}
+	case 0x04, 0x05, 0x06, 0x07:
+		i.Mnemonic = [4]string{"movs", "cmp", "adds", "subs"}[op>>11&3]
+		i.Operands = []string{reg(uint32(op) >> 8 & 7), imm(imm8)}
+	case 0x08:
+		if op&(1<<10) == 0 {
+			i.Mnemonic = thumbAluNames[op>>6&15]
+			i.Operands = []string{rd, rs}
+			return i
+		}
+		return thumbHiReg(i, arch)
+	case 0x09:
+		pc := (addr + 4) &^ 2
+		i.Mnemonic = "ldr"
+		i.Operands = []string{reg(uint32(op) >> 8 & 7), memOperand("pc", imm(imm8*4), true, false)}
+		i.Literal = literal(mem, pc+imm8*4, 4)
+	case 0x0A, 0x0B:
+		ro := reg(uint32(op) >> 6 & 7)
+		i.Mnemonic = [8]string{"str", "strh", "strb", "ldrsb", "ldr", "ldrh", "ldrb", "ldrsh"}[op>>9&7]
+		i.Operands = []string{rd, "[" + rs + ", " + ro + "]"}
+	case 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11:
+		off := uint32(op) >> 6 & 31
+		switch op >> 11 {
+		case 0x0C, 0x0D:
+			i.Mnemonic = "str"
+			off *= 4
+		case 0x0E, 0x0F:
+			i.Mnemonic = "strb"
+		default:
+			i.Mnemonic = "strh"
+			off *= 2
+		}
+		if op&(1<<11) != 0 {
+			i.Mnemonic = "ldr" + i.Mnemonic[3:]
+		}
+		var offs string
+		if off != 0 {
+			offs = imm(off)
+		}
+		i.Operands = []string{rd, memOperand(rs, offs, true, false)}
+	case 0x12, 0x13:
+		i.Mnemonic = "str"
+		if op&(1<<11) != 0 {
+			i.Mnemonic = "ldr"
+		}
+		i.Operands = []string{reg(uint32(op) >> 8 & 7), memOperand("sp", imm(imm8*4), true, false)}
+	case 0x14, 0x15:
+		i.Mnemonic = "add"
+		base := "pc"
+		if op&(1<<11) != 0 {
+			base = "sp"
+		}
+		i.Operands = []string{reg(uint32(op) >> 8 & 7), base, imm(imm8 * 4)}
+	case 0x16, 0x17:
+		return thumbMisc(i, arch)
+	case 0x18, 0x19:
+		rb := uint32(op) >> 8 & 7
+		i.Mnemonic = "stmia"
+		base := reg(rb) + "!"
+		if op&(1<<11) != 0 {
+			i.Mnemonic = "ldmia"
+			if op&(1<<rb) != 0 {
+				// The loaded value wins over the writeback
+				base = reg(rb)
+			}
+		}
+		i.Operands = []string{base, regList(imm8)}
+	case 0x1A, 0x1B:
+		cond := op >> 8 & 15
+		switch cond {
+		case 14:
+			return undefined(i)
+		case 15:
+			i.Mnemonic = "swi"
+			i.Operands = []string{imm(imm8)}
+			return i
+		}
+		i.Mnemonic = "b" + condNames[cond]
+		return thumbBranch(i, int32(int8(imm8))*2)
+	case 0x1C:
+		i.Mnemonic = "b"
+		return thumbBranch(i, int32(uint32(op)<<21)>>20)
+	case 0x1D, 0x1F:
+		if op>>11 == 0x1D && (arch < ARMv5 || op&1 != 0) {
+			return undefined(i)
+		}
+		i.Mnemonic = "bl"
+		if op>>11 == 0x1D {
+			i.Mnemonic = "blx"
+		}
+		i.Operands = []string{"lr", imm(uint32(op&0x7FF) * 2)}
+	default:
+		return thumbBlPrefix(i, arch, mem)
+	}
+	return i
+}
+
+func thumbBranch(i Insn, off int32) Insn {
+	i.HasTarget = true
+	i.ThumbTarget = true
+	i.Target = i.Addr + 4 + uint32(off)
+	i.Operands = []string{address(i.Target)}
+	return i
+}
+
+// thumbBlPrefix decodes the first half of a BL/BLX pair, which sets the
+// high part of the offset in LR
+func thumbBlPrefix(i Insn, arch Arch, mem Memory) Insn {
+	hi := int32(i.Raw<<21) >> 9
+	if mem != nil {
+		op2 := uint16(peek(mem, i.Addr+2, 2))
+		switch {
+		case op2>>11 == 0x1F:
+			i.Mnemonic = "bl"
+		case op2>>11 == 0x1D && arch >= ARMv5 && op2&1 == 0:
+			i.Mnemonic = "blx"
+		}
+		if i.Mnemonic != "" {
+			i = thumbBranch(i, hi+int32(op2&0x7FF)*2)
+			i.Link = true
+			if i.Mnemonic == "blx" {
+				i.ThumbTarget = false
+				i.Target &^= 3
+				i.Operands = []string{address(i.Target)}
+			}
+			return i
+		}
+	}
+
+	// Not followed by the second half: only LR is modified
+	i.Mnemonic = "add"
+	i.Operands = []string{"lr", "pc", imm(uint32(hi))}
+	if hi < 0 {
+		i.Mnemonic = "sub"
+		i.Operands[2] = imm(uint32(-hi))
+	}
+	return i
+}
+
+// thumbHiReg decodes the operations on high registers, and bx/blx
+func thumbHiReg(i Insn, arch Arch) Insn {
+	op := uint32(i.Raw)
+	rd := reg(op&7 | op>>4&8)
+	rs := reg(op >> 3 & 15)
+	switch op >> 8 & 3 {
+	case 0:
+		i.Mnemonic = "add"
+	case 1:
+		i.Mnemonic = "cmp"
+	case 2:
+		i.Mnemonic = "mov"
+	default:
+		i.Mnemonic = "bx"
+		if op&0x80 != 0 {
+			if arch < ARMv5 {
+				return undefined(i)
+			}
+			i.Mnemonic = "blx"
+			i.Link = true
+		}
+		i.Operands = []string{rs}
+		return i
+	}
+	i.Operands = []string{rd, rs}
+	return i
+}
+
+// thumbMisc decodes the instructions with prefix 1011: stack adjustment,
+// push/pop and bkpt
+func thumbMisc(i Insn, arch Arch) Insn {
+	op := uint32(i.Raw)
+	switch {
+	case op&0xFF00 == 0xB000:
+		i.Mnemonic = "add"
+		if op&0x80 != 0 {
+			i.Mnemonic = "sub"
+		}
+		i.Operands = []string{"sp", imm((op & 0x7F) * 4)}
+	case op&0xF600 == 0xB400:
+		list := op & 0xFF
+		i.Mnemonic = "push"
+		if op&(1<<11) != 0 {
+			i.Mnemonic = "pop"
+			list |= op >> 8 & 1 << 15
+		} else {
+			list |= op >> 8 & 1 << 14
+		}
+		i.Operands = []string{regList(list)}
+	case op&0xFF00 == 0xBE00 && arch >= ARMv5:
+		i.Mnemonic = "bkpt"
+		i.Operands = []string{imm(op & 0xFF)}
+	default:
+		return undefined(i)
+	}
+	return i
+}
@@ -0,0 +1,87 @@
+package arm
+
+// FetchWait holds the wait states of code fetches from a memory region, that
+// is the cycles spent on top of the single cycle accounted for each opcode.
+type FetchWait struct {
+	N int64 // non-sequential fetch (first opcode after a branch)
+	S int64 // sequential fetch
+}
+
+// Wait states of code fetches, for each 16 MiB region of the address space
+type fetchRegion struct {
+	arm, thumb FetchWait
+}
+
+// SetFetchWaitStates configures the wait states of the code fetches from the
+// memory region [begin, end] (with a granularity of 16 MiB), for ARM and
+// Thumb opcodes. By default, code fetches have no wait states: all opcodes
+// take a single cycle, plus the pipeline refill after a branch.
+//
+// On ARMv5, Thumb opcodes are fetched in pairs with a single 32-bit access,
+// so only opcodes at word-aligned addresses pay the sequential wait states;
+// moreover, fetches are considered free from ITCM and while the instruction
+// cache is enabled. The cache itself is not emulated, so this assumes a 100%
+// hit rate: the line fills that misses cause on hardware (a non-sequential
+// access followed by sequential ones, for the 32-byte line) are not billed,
+// and code running from main RAM with the cache enabled is faster than on
+// hardware, mostly the first time it runs.
+//
+// Timings are applied by the interpreter only: JIT-compiled blocks keep
+// accounting a single cycle per opcode.
+func (cpu *Cpu) SetFetchWaitStates(begin, end uint32, arm, thumb FetchWait) {
+	if cpu.fetchWait == nil {
+		cpu.fetchWait = new([256]fetchRegion)
+	}
+	for r := begin >> 24; r <= end>>24; r++ {
+		cpu.fetchWait[r] = fetchRegion{arm, thumb}
+	}
+}
+
+// ResetFetchWaitStates restores the default timings (no wait states)
+func (cpu *Cpu) ResetFetchWaitStates() {
+	cpu.fetchWait = nil
+}
+
+// fetchCycles returns the cycles taken by each opcode fetched sequentially
+// from the current PC, respectively for opcodes at addresses which are not
+// and are word-aligned (they only differ for Thumb opcodes on ARMv5). If the
+// last fetch was not sequential (because of a branch), it also accounts the
+// wait states of the non-sequential fetch.
+func (cpu *Cpu) fetchCycles() (cycles [2]int64) {
+	cycles = [2]int64{1, 1}
+	nonseq := cpu.fetchNonSeq
+	cpu.fetchNonSeq = false
+	if cpu.fetchWait == nil {
+		return
+	}
+
+	pc := uint32(cpu.pc)
+	if cpu.cp15 != nil {
+		// I-cache enabled (assumed to always hit), or ITCM
+		if cpu.cp15.regControl.Bit(12) || cpu.cp15.CheckITcm(pc) != nil {
+			return
+		}
+	}
+
+	thumb := cpu.Cpsr.T()
+	w := &cpu.fetchWait[pc>>24].arm
+	if thumb {
+		w = &cpu.fetchWait[pc>>24].thumb
+	}
+	cycles[0] += w.S
+	cycles[1] += w.S
+	if thumb && cpu.arch >= ARMv5 {
+		cycles[0] = 1
+	}
+
+	if nonseq {
+		// The first opcode is billed as a sequential one by the caller,
+		// so only account the difference.
+		first := cycles[1]
+		if thumb && pc&2 != 0 {
+			first = cycles[0]
+		}
+		cpu.Clock += 1 + w.N - first
+	}
+	return
+}
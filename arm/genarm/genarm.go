@@ -1154,7 +1154,6 @@ func main() {
 		out := Generator{g}
 		out.Prefix = "Arm"
 		out.OpSize = "uint32"
-		out.GenDisasm = true
 		out.PcRelOff = 8
 		out.TableBits = 12
 		out.WriteHeader()
@@ -27,11 +27,13 @@ func (g *Generator) WriteHeader() {
 	}
 	fmt.Fprintf(g, "}\n")
 
-	fmt.Fprintf(g, "var disasmThumbAluTable = [16]func(*Cpu, uint16, uint32) string {\n")
-	for i := 0; i < 16; i++ {
-		fmt.Fprintf(g, "(*Cpu).disasmThumbAlu%02X,\n", i)
+	if g.GenDisasm {
+		fmt.Fprintf(g, "var disasmThumbAluTable = [16]func(*Cpu, uint16, uint32) string {\n")
+		for i := 0; i < 16; i++ {
+			fmt.Fprintf(g, "(*Cpu).disasmThumbAlu%02X,\n", i)
+		}
+		fmt.Fprintf(g, "}\n")
 	}
-	fmt.Fprintf(g, "}\n")
 }
 
 func (g *Generator) writeOpAluHeader(op uint16) {
@@ -40,7 +42,7 @@ func (g *Generator) writeOpAluHeader(op uint16) {
 }
 func (g *Generator) writeOpAluFooter(op uint16) {
 	fmt.Fprintf(g, "}\n\n")
-	if g.Disasm.Len() == 0 {
+	if !g.GenDisasm || g.Disasm.Len() == 0 {
 		// panic(fmt.Sprintf("disasm not implemented for op %04x", op))
 		return
 	}
@@ -794,7 +796,6 @@ func main() {
 		out := Generator{g}
 		out.Prefix = "Thumb"
 		out.OpSize = "uint16"
-		out.GenDisasm = true
 		out.PcRelOff = 4
 		out.TableBits = 8
 		out.WriteHeader()
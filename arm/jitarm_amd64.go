@@ -819,7 +819,37 @@ func (j *jitArm) emitOpSwp(op uint32) {
 	j.AddCycles(1)
 }
 
+// jitSwiHle is called by compiled blocks for SWIs that had a HLE function
+// installed at compile time. It skips the generic exception path (the SWI
+// number is already known, so the opcode isn't fetched again), and returns
+// to the block, which can continue past the SWI. It falls back to Exception
+// if the function was removed in the meantime, or when the debugger must
+// see the call.
+func (cpu *Cpu) jitSwiHle(num uint32) {
+	hle := cpu.swiHle[num]
+	if hle == nil || cpu.dbg != nil {
+		cpu.Exception(ExceptionSwi)
+		return
+	}
+	pc := cpu.pc + reg(excPcOffsetArm[ExceptionSwi])
+	if cpu.resumeSwiHle(pc) {
+		return
+	}
+	delay := hle(cpu)
+	cpu.chargeSwiHle(pc, delay+3)
+}
+
 func (j *jitArm) emitOpSwi(op uint32) {
+	if num := (op >> 16) & 0xFF; j.Cpu.swiHle[num] != nil {
+		j.CallBlock(0x10, func() {
+			j.Mov(jitRegCpu, j.CallSlot(0x0, 64))
+			j.Mov(a.Imm{int32(num)}, j.CallSlot(0x8, 64))
+			j.CallFuncGo((*Cpu).jitSwiHle)
+		})
+		j.AddCycles(2)
+		return
+	}
+
 	j.CallBlock(0x10, func() {
 		j.Mov(jitRegCpu, j.CallSlot(0x0, 64))
 		j.Mov(a.Imm{int32(ExceptionSwi)}, j.CallSlot(0x8, 64))
@@ -1618,6 +1648,11 @@ func (j *jitArm) IsBlockTerminator(pc uint32, op uint32) bool {
 		// BX(L) is used for call/ret
 		return true
 
+	case opTypeSwi:
+		// A SWI jumps into the BIOS, unless it's emulated with a HLE
+		// function, which is called inline (see jitSwiHle)
+		return j.Cpu.swiHle[(op>>16)&0xFF] == nil
+
 	case opTypeAlu:
 		rdx := (op >> 12) & 0xF
 		if rdx == 15 {
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/rand"
+	"ndsemu/arm/disasm"
 	"runtime/debug"
 	"testing"
 
@@ -107,7 +108,7 @@ func TestAlu(t *testing.T) {
 
 		t.Logf("Testing ARM Opcodes (ARMv%d): ------------------------------", cpu1.arch)
 		for i, op := range []uint32{op} {
-			n := disasmArmTable[((op>>16)&0xFF0)|((op>>4)&0xF)](&cpu1, op, PC+uint32(i)*4)
+			n := disasm.Arm(op, PC+uint32(i)*4, cpu1.DisasmArch(), nil)
 			t.Logf("%08x\t%08x  %s", PC+uint32(i)*4, op, n)
 		}
 		t.Logf("x86 translation: ------------------------------------")
@@ -427,3 +428,45 @@ func TestAlu(t *testing.T) {
 		testf(0x010073d5, "ldrble    r0, [r3, #0x-1]!")
 	}
 }
+
+func TestJitSwiHle(t *testing.T) {
+	jita, err := a.NewGoABI(64 * 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := &debugBus{RandData: make([]uint32, 256)}
+	cpu := NewCpu(ARMv4, bus, false)
+	calls := 0
+	cpu.SetSwiHle(0x12, func(cpu *Cpu) int64 {
+		calls++
+		cpu.Regs[1] = 0x1234
+		return 10
+	})
+
+	jit := &jitArm{Assembler: jita, Cpu: cpu, StartPc: 0x1000}
+	ops := []uint32{
+		0xEF120000, // swi 0x12 (HLE)
+		0xE2800001, // add r0, r0, #1
+	}
+	if jit.IsBlockTerminator(0x1000, ops[0]) || !jit.IsBlockTerminator(0x1000, 0xEF130000) {
+		t.Errorf("SWI with HLE should not terminate the block (and without HLE should)")
+	}
+	f, err := jit.EmitBlock(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu.pc = 0x1000
+	cpu.targetCycles = 1000
+	f(cpu)
+	if calls != 1 || cpu.Regs[1] != 0x1234 || cpu.Regs[0] != 1 || cpu.pc != 0x1008 {
+		t.Errorf("calls=%d r0=%x r1=%x pc=%x", calls, cpu.Regs[0], cpu.Regs[1], cpu.pc)
+	}
+	if cpu.Clock != 2+1+10+3+1 {
+		t.Errorf("invalid clock: %d", cpu.Clock)
+	}
+	if len(bus.Accesses) != 0 {
+		t.Errorf("unexpected bus accesses: %v", bus.Accesses)
+	}
+}
@@ -11,6 +11,11 @@ import (
 // 	2) Check if the address is misaligned, and handle it the way the CPU does
 // 	3) Check if the address falls within DTCM or ITCM (if there is a CP15 and
 // 	they are active).
+// 	4) Account the wait states, including the optional penalty installed
+// 	with SetMemPenalty.
+// 	5) Look up the address in the page tables of the bus (if it's a
+// 	hwio.Table): plain memory is accessed inline, and only I/O goes through
+// 	an actual function call.
 //
 // 	The code isn't pretty because it is manually optimized.
 // 	DO NOT REFACTOR WITHOUT RUNNING MICRO-BENCHMARKS
@@ -49,6 +54,15 @@ func (cpu *Cpu) Read32(addr uint32) uint32 {
 
 nodtcm:
 	cpu.Clock += cpu.memCycles
+	if cpu.memPenalty != nil {
+		cpu.Clock += cpu.memPenalty(addr)
+	}
+	if cpu.table != nil {
+		if val, ok := cpu.table.FastRead32(addr); ok {
+			return val
+		}
+		return cpu.table.Read32(addr)
+	}
 	return cpu.bus.Read32(addr)
 }
 
@@ -76,7 +90,16 @@ func (cpu *Cpu) Write32(addr uint32, val uint32) {
 
 nodtcm:
 	cpu.Clock += cpu.memCycles
-	cpu.bus.Write32(addr, val)
+	if cpu.memPenalty != nil {
+		cpu.Clock += cpu.memPenalty(addr)
+	}
+	if cpu.table != nil {
+		if !cpu.table.FastWrite32(addr, val) {
+			cpu.table.Write32(addr, val)
+		}
+	} else {
+		cpu.bus.Write32(addr, val)
+	}
 	if cpu.jit != nil {
 		cpu.jit.Invalidate(addr)
 	}
@@ -106,6 +129,15 @@ func (cpu *Cpu) Read16(addr uint32) uint16 {
 
 nodtcm:
 	cpu.Clock += cpu.memCycles
+	if cpu.memPenalty != nil {
+		cpu.Clock += cpu.memPenalty(addr)
+	}
+	if cpu.table != nil {
+		if val, ok := cpu.table.FastRead16(addr); ok {
+			return val
+		}
+		return cpu.table.Read16(addr)
+	}
 	return cpu.bus.Read16(addr)
 }
 
@@ -131,7 +163,16 @@ func (cpu *Cpu) Write16(addr uint32, val uint16) {
 	}
 nodtcm:
 	cpu.Clock += cpu.memCycles
-	cpu.bus.Write16(addr, val)
+	if cpu.memPenalty != nil {
+		cpu.Clock += cpu.memPenalty(addr)
+	}
+	if cpu.table != nil {
+		if !cpu.table.FastWrite16(addr, val) {
+			cpu.table.Write16(addr, val)
+		}
+	} else {
+		cpu.bus.Write16(addr, val)
+	}
 	if cpu.jit != nil {
 		cpu.jit.Invalidate(addr)
 	}
@@ -155,6 +196,15 @@ func (cpu *Cpu) Read8(addr uint32) uint8 {
 	}
 nodtcm:
 	cpu.Clock += cpu.memCycles
+	if cpu.memPenalty != nil {
+		cpu.Clock += cpu.memPenalty(addr)
+	}
+	if cpu.table != nil {
+		if val, ok := cpu.table.FastRead8(addr); ok {
+			return val
+		}
+		return cpu.table.Read8(addr)
+	}
 	return cpu.bus.Read8(addr)
 }
 
@@ -177,7 +227,16 @@ func (cpu *Cpu) Write8(addr uint32, val uint8) {
 	}
 nodtcm:
 	cpu.Clock += cpu.memCycles
-	cpu.bus.Write8(addr, val)
+	if cpu.memPenalty != nil {
+		cpu.Clock += cpu.memPenalty(addr)
+	}
+	if cpu.table != nil {
+		if !cpu.table.FastWrite8(addr, val) {
+			cpu.table.Write8(addr, val)
+		}
+	} else {
+		cpu.bus.Write8(addr, val)
+	}
 	if cpu.jit != nil {
 		cpu.jit.Invalidate(addr)
 	}
@@ -25,38 +25,32 @@ func (cpu *Cpu) InvalidOpThumb(op uint16, msg string) {
 	cpu.breakpoint("invalid thumb opcode at %v (%04X): %s", cpu.pc-2, op, msg)
 }
 
+// armCondTable has a bit set for each combination of the NZCV flags (see
+// regCpsr.NZCV) for which the condition code is satisfied. Evaluating the
+// condition with a lookup is branch-free; a switch over the condition
+// code was one of the top costs of the interpreter, as conditional
+// opcodes are frequent and their conditions hard to predict.
+var armCondTable = [16]uint16{
+	0xF0F0, // EQ: Z
+	0x0F0F, // NE: !Z
+	0xCCCC, // CS: C
+	0x3333, // CC: !C
+	0xFF00, // MI: N
+	0x00FF, // PL: !N
+	0xAAAA, // VS: V
+	0x5555, // VC: !V
+	0x0C0C, // HI: C && !Z
+	0xF3F3, // LS: !C || Z
+	0xAA55, // GE: N == V
+	0x55AA, // LT: N != V
+	0x0A05, // GT: !Z && N == V
+	0xF5FA, // LE: Z || N != V
+	0xFFFF, // AL
+	0xFFFF, // NV (unconditional opcodes on ARMv5)
+}
+
 func (cpu *Cpu) opArmCond(cond uint) bool {
-	switch cond {
-	case 0:
-		return cpu.Cpsr.Z
-	case 1:
-		return !cpu.Cpsr.Z
-	case 2:
-		return cpu.Cpsr.C
-	case 3:
-		return !cpu.Cpsr.C
-	case 4:
-		return cpu.Cpsr.N
-	case 5:
-		return !cpu.Cpsr.N
-	case 6:
-		return cpu.Cpsr.V
-	case 7:
-		return !cpu.Cpsr.V
-	case 8:
-		return cpu.Cpsr.C && !cpu.Cpsr.Z
-	case 9:
-		return !cpu.Cpsr.C || cpu.Cpsr.Z
-	case 10:
-		return cpu.Cpsr.N == cpu.Cpsr.V
-	case 11:
-		return cpu.Cpsr.N != cpu.Cpsr.V
-	case 12:
-		return !cpu.Cpsr.Z && cpu.Cpsr.N == cpu.Cpsr.V
-	case 13:
-		return cpu.Cpsr.Z || cpu.Cpsr.N != cpu.Cpsr.V
-	}
-	panic("unreachable")
+	return armCondTable[cond&0xF]>>cpu.Cpsr.NZCV()&1 != 0
 }
 
 func (cpu *Cpu) opCopRead(copnum uint32, op uint32, cn, cm, cp uint32) uint32 {
@@ -113,6 +107,7 @@ const (
 func (cpu *Cpu) branch(newpc reg, reason BranchType) {
 	cpu.Clock += 2
 	cpu.tightExit = true
+	cpu.fetchNonSeq = true
 	cpu.prevpc = cpu.pc
 	cpu.pc = newpc
 	if cpu.Cpsr.T() {
@@ -126,10 +121,35 @@ func (cpu *Cpu) branch(newpc reg, reason BranchType) {
 	}
 }
 
+// thumbLongBranch runs both halves of a Thumb BL/BLX pair (op1 is the high
+// part of the offset, op2 the low part), with the same effects and timings
+// as running them in sequence. cycles are the fetch costs computed by the
+// tight loop (see fetchCycles), also used to bill the second half.
+func (cpu *Cpu) thumbLongBranch(op1, op2 uint16, cycles [2]int64) {
+	cpu.Regs[14] = cpu.Regs[15] + reg(int32(uint32(op1&0x7FF)<<21)>>9)
+
+	// Move to the second half
+	cpu.Regs[15] += 2
+	cpu.pc += 2
+	cpu.Clock += cycles[(cpu.pc>>1)&1]
+
+	newpc := cpu.Regs[14] + reg((op2&0x7FF)<<1)
+	cpu.Regs[14] = (cpu.Regs[15] - 2) | 1
+	if op2&0x1000 == 0 {
+		// BLX: switch to ARM
+		newpc &^= 2
+		cpu.Cpsr.SetT(false, cpu)
+	}
+	cpu.branch(newpc, BranchCall)
+}
+
 func (cpu *Cpu) Retarget(until int64) {
 	if cpu.targetCycles > until {
 		cpu.targetCycles = until
 	}
+	if cpu.runTarget > until {
+		cpu.runTarget = until
+	}
 }
 
 func (cpu *Cpu) Run(until int64) {
@@ -137,13 +157,15 @@ func (cpu *Cpu) Run(until int64) {
 	var lastBranchMem []byte
 
 	cpu.targetCycles = until
+	cpu.runTarget = until
 
 	var trace func(uint32)
 	if cpu.dbg != nil {
 		trace = cpu.dbg.Trace
 	}
 
-	for cpu.Clock < cpu.targetCycles {
+	for cpu.Clock < cpu.runTarget {
+		cpu.targetCycles = cpu.runTarget
 		lines := cpu.lines
 		if lines&LineHalt != 0 {
 			cpu.Clock = cpu.targetCycles
@@ -165,8 +187,15 @@ func (cpu *Cpu) Run(until int64) {
 			continue
 		}
 		if lines&LineIrq != 0 && !cpu.Cpsr.I() {
-			cpu.Exception(ExceptionIrq)
-			continue
+			if cpu.Clock >= cpu.irqAt {
+				cpu.Exception(ExceptionIrq)
+				continue
+			}
+			// The IRQ is still being synchronized (see SetIrqLatency):
+			// keep running until it's due.
+			if cpu.targetCycles > cpu.irqAt {
+				cpu.targetCycles = cpu.irqAt
+			}
 		}
 
 		// Fetch the pointer to the memory PC is pointing to.
@@ -224,11 +253,14 @@ func (cpu *Cpu) Run(until int64) {
 					if trace != nil {
 						trace(uint32(cpu.pc - 4))
 					}
+					clk := cpu.Clock
 					fcode()
+					cpu.JitCycles += cpu.Clock - clk
 					continue
 				}
 			}
 
+			cycles := cpu.fetchCycles()[1]
 			for i := 0; i < len(mem)-3; i += 4 {
 				cpu.Regs[15] = cpu.pc + 8 // simulate pipeline with prefetch
 				cpu.pc += 4
@@ -238,7 +270,7 @@ func (cpu *Cpu) Run(until int64) {
 				}
 
 				op := binary.LittleEndian.Uint32(mem[i:])
-				cpu.Clock++
+				cpu.Clock += cycles
 
 				// Check the condition flags on each instruction (bits 28-31).
 				// * 0xE means always, and is by far the most common occurrence.
@@ -254,6 +286,7 @@ func (cpu *Cpu) Run(until int64) {
 				}
 			}
 		} else {
+			cycles := cpu.fetchCycles()
 			for i := 0; i < len(mem)-1; i += 2 {
 				cpu.Regs[15] = cpu.pc + 4 // simulate pipeline with prefetch
 				cpu.pc += 2
@@ -263,7 +296,18 @@ func (cpu *Cpu) Run(until int64) {
 				}
 
 				op := binary.LittleEndian.Uint16(mem[i:])
-				cpu.Clock++
+				cpu.Clock += cycles[(cpu.pc>>1)&1]
+
+				// BL/BLX is a pair of opcodes: when both halves are in
+				// this chunk of memory, run them with a single dispatch.
+				// Otherwise (or while tracing), they run separately, as
+				// the first half leaves the partial target in LR.
+				if op&0xF800 == 0xF000 && i+3 < len(mem) && trace == nil {
+					if op2 := binary.LittleEndian.Uint16(mem[i+2:]); op2&0xE800 == 0xE800 {
+						cpu.thumbLongBranch(op, op2, cycles)
+						break
+					}
+				}
 
 				opThumbTable[op>>8](cpu, op)
 
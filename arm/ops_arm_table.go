@@ -1,9 +1,6 @@
-// Generated on 2017-06-18 01:20:56.24527106 +0200 CEST
+// Generated on 2026-10-16 14:23:20.519937584 +0000 UTC m=+0.000539895
 package arm
 
-import "bytes"
-import "strconv"
-
 func (cpu *Cpu) opArm000(op uint32) {
 	// and
 	rnx := (op >> 16) & 0xF
@@ -28,21 +25,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm000(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("and", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm001(op uint32) {
 	// and
 	rnx := (op >> 16) & 0xF
@@ -240,21 +222,6 @@ func (cpu *Cpu) opArm009(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm009(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mul", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm00B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -273,23 +240,6 @@ func (cpu *Cpu) opArm00B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm00B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm00D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -313,26 +263,6 @@ func (cpu *Cpu) opArm00D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm00D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := "-" + RegNames[op&0xF]
-	out.WriteString(arg3)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm00F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -352,26 +282,6 @@ func (cpu *Cpu) opArm00F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm00F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := "-" + RegNames[op&0xF]
-	out.WriteString(arg3)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm010(op uint32) {
 	// ands
 	rnx := (op >> 16) & 0xF
@@ -402,21 +312,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm010(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ands", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm011(op uint32) {
 	// ands
 	rnx := (op >> 16) & 0xF
@@ -656,21 +551,6 @@ func (cpu *Cpu) opArm019(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm019(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("muls", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm01B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -697,23 +577,6 @@ func (cpu *Cpu) opArm01B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm01B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm01D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -737,23 +600,6 @@ func (cpu *Cpu) opArm01D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm01D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm01F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -780,23 +626,6 @@ func (cpu *Cpu) opArm01F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm01F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm020(op uint32) {
 	// eor
 	rnx := (op >> 16) & 0xF
@@ -821,21 +650,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm020(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("eor", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm021(op uint32) {
 	// eor
 	rnx := (op >> 16) & 0xF
@@ -1035,24 +849,6 @@ func (cpu *Cpu) opArm029(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm029(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mla", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm030(op uint32) {
 	// eors
 	rnx := (op >> 16) & 0xF
@@ -1083,21 +879,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm030(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("eors", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm031(op uint32) {
 	// eors
 	rnx := (op >> 16) & 0xF
@@ -1339,24 +1120,6 @@ func (cpu *Cpu) opArm039(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm039(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mlas", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm040(op uint32) {
 	// sub
 	rnx := (op >> 16) & 0xF
@@ -1381,21 +1144,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm040(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("sub", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm041(op uint32) {
 	// sub
 	rnx := (op >> 16) & 0xF
@@ -1577,10 +1325,6 @@ func (cpu *Cpu) opArm049(op uint32) {
 	cpu.InvalidOpArm(op, "invalid opcode decoded as LD/STR half-word")
 }
 
-func (cpu *Cpu) disasmArm049(op uint32, pc uint32) string {
-	return "dw " + strconv.FormatInt(int64(op), 16)
-}
-
 func (cpu *Cpu) opArm04B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -1594,24 +1338,6 @@ func (cpu *Cpu) opArm04B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm04B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-(op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm04D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -1630,27 +1356,6 @@ func (cpu *Cpu) opArm04D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm04D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := int64(-(op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg3, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm04F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -1665,27 +1370,6 @@ func (cpu *Cpu) opArm04F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm04F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := int64(-(op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg3, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm050(op uint32) {
 	// subs
 	rnx := (op >> 16) & 0xF
@@ -1720,21 +1404,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm050(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("subs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm051(op uint32) {
 	// subs
 	rnx := (op >> 16) & 0xF
@@ -2002,24 +1671,6 @@ func (cpu *Cpu) opArm05B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm05B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-(op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm05D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -2038,24 +1689,6 @@ func (cpu *Cpu) opArm05D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm05D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-(op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm05F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -2077,24 +1710,6 @@ func (cpu *Cpu) opArm05F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm05F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-(op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm060(op uint32) {
 	// rsb
 	rnx := (op >> 16) & 0xF
@@ -2119,21 +1734,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm060(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm061(op uint32) {
 	// rsb
 	rnx := (op >> 16) & 0xF
@@ -2345,21 +1945,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm070(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rsbs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm071(op uint32) {
 	// rsbs
 	rnx := (op >> 16) & 0xF
@@ -2630,21 +2215,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm080(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("add", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm081(op uint32) {
 	// add
 	rnx := (op >> 16) & 0xF
@@ -2845,24 +2415,6 @@ func (cpu *Cpu) opArm089(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm089(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("umull", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm08B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -2881,23 +2433,6 @@ func (cpu *Cpu) opArm08B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm08B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm08D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -2921,26 +2456,6 @@ func (cpu *Cpu) opArm08D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm08D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := RegNames[op&0xF]
-	out.WriteString(arg3)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm08F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -2960,26 +2475,6 @@ func (cpu *Cpu) opArm08F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm08F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := RegNames[op&0xF]
-	out.WriteString(arg3)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm090(op uint32) {
 	// adds
 	rnx := (op >> 16) & 0xF
@@ -3014,21 +2509,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm090(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("adds", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm091(op uint32) {
 	// adds
 	rnx := (op >> 16) & 0xF
@@ -3299,24 +2779,6 @@ func (cpu *Cpu) opArm099(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm099(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("umulls", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm09B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -3343,23 +2805,6 @@ func (cpu *Cpu) opArm09B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm09B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm09D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -3383,23 +2828,6 @@ func (cpu *Cpu) opArm09D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm09D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm09F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -3426,23 +2854,6 @@ func (cpu *Cpu) opArm09F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm09F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := RegNames[op&0xF]
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0A0(op uint32) {
 	// adc
 	rnx := (op >> 16) & 0xF
@@ -3468,21 +2879,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm0A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("adc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0A1(op uint32) {
 	// adc
 	rnx := (op >> 16) & 0xF
@@ -3692,24 +3088,6 @@ func (cpu *Cpu) opArm0A9(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm0A9(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("umlal", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0B0(op uint32) {
 	// adcs
 	rnx := (op >> 16) & 0xF
@@ -3749,21 +3127,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm0B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("adcs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0B1(op uint32) {
 	// adcs
 	rnx := (op >> 16) & 0xF
@@ -4071,24 +3434,6 @@ func (cpu *Cpu) opArm0B9(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm0B9(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("umlals", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0C0(op uint32) {
 	// sbc
 	rnx := (op >> 16) & 0xF
@@ -4114,21 +3459,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm0C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("sbc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0C1(op uint32) {
 	// sbc
 	rnx := (op >> 16) & 0xF
@@ -4336,24 +3666,6 @@ func (cpu *Cpu) opArm0C9(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm0C9(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smull", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0CB(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -4367,24 +3679,6 @@ func (cpu *Cpu) opArm0CB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm0CB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64((op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0CD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -4403,27 +3697,6 @@ func (cpu *Cpu) opArm0CD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm0CD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := int64((op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg3, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0CF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -4438,27 +3711,6 @@ func (cpu *Cpu) opArm0CF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm0CF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg3 := int64((op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg3, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0D0(op uint32) {
 	// sbcs
 	rnx := (op >> 16) & 0xF
@@ -4498,21 +3750,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm0D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("sbcs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0D1(op uint32) {
 	// sbcs
 	rnx := (op >> 16) & 0xF
@@ -4818,24 +4055,6 @@ func (cpu *Cpu) opArm0D9(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm0D9(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smulls", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0DB(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -4857,24 +4076,6 @@ func (cpu *Cpu) opArm0DB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm0DB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64((op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0DD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -4893,24 +4094,6 @@ func (cpu *Cpu) opArm0DD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm0DD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64((op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0DF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -4932,24 +4115,6 @@ func (cpu *Cpu) opArm0DF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm0DF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64((op & 0xF) | ((op & 0xF00) >> 4))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0E0(op uint32) {
 	// rsc
 	rnx := (op >> 16) & 0xF
@@ -4976,21 +4141,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm0E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rsc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0E1(op uint32) {
 	// rsc
 	rnx := (op >> 16) & 0xF
@@ -5207,24 +4357,6 @@ func (cpu *Cpu) opArm0E9(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm0E9(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlal", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0F0(op uint32) {
 	// rscs
 	rnx := (op >> 16) & 0xF
@@ -5265,21 +4397,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm0F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rscs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm0F1(op uint32) {
 	// rscs
 	rnx := (op >> 16) & 0xF
@@ -5594,24 +4711,6 @@ func (cpu *Cpu) opArm0F9(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm0F9(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlals", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm100(op uint32) {
 	if op&0x0F900FF0 != 0x01000000 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as PSR_reg")
@@ -5631,17 +4730,6 @@ func (cpu *Cpu) opArm100(op uint32) {
 	cpu.Regs[rdx] = reg(cpu.Cpsr.Uint32())
 }
 
-func (cpu *Cpu) disasmArm100(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mrs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	out.WriteString("cpsr")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm101(op uint32) {
 	cpu.InvalidOpArm(op, "invalid ALU test function without flags")
 }
@@ -5666,24 +4754,6 @@ func (cpu *Cpu) opArm108(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm108(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlabb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm109(op uint32) {
 	if op&0x0FB00FF0 != 0x01000090 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as SWP")
@@ -5705,23 +4775,6 @@ func (cpu *Cpu) opArm109(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm109(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("swp", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm10A(op uint32) {
 	// smlatb
 	if cpu.arch < ARMv5 {
@@ -5742,24 +4795,6 @@ func (cpu *Cpu) opArm10A(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm10A(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlatb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm10B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -5777,23 +4812,6 @@ func (cpu *Cpu) opArm10B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm10B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm10C(op uint32) {
 	// smlabt
 	if cpu.arch < ARMv5 {
@@ -5814,24 +4832,6 @@ func (cpu *Cpu) opArm10C(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm10C(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlabt", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm10D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -5854,26 +4854,6 @@ func (cpu *Cpu) opArm10D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm10D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm10E(op uint32) {
 	// smlatt
 	if cpu.arch < ARMv5 {
@@ -5894,24 +4874,6 @@ func (cpu *Cpu) opArm10E(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm10E(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlatt", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm10F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -5930,26 +4892,6 @@ func (cpu *Cpu) opArm10F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm10F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm110(op uint32) {
 	// tsts
 	rnx := (op >> 16) & 0xF
@@ -5979,18 +4921,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm110(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("tst", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm111(op uint32) {
 	// tsts
 	rnx := (op >> 16) & 0xF
@@ -6227,23 +5157,6 @@ func (cpu *Cpu) opArm11B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm11B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm11D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6266,23 +5179,6 @@ func (cpu *Cpu) opArm11D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm11D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm11F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6308,23 +5204,6 @@ func (cpu *Cpu) opArm11F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm11F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm120(op uint32) {
 	if op&0x0F900FF0 != 0x01000000 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as PSR_reg")
@@ -6349,31 +5228,6 @@ func (cpu *Cpu) opArm120(op uint32) {
 	cpu.Cpsr.SetWithMask(val, mask, cpu)
 }
 
-func (cpu *Cpu) disasmArm120(op uint32, pc uint32) string {
-	dst := "cpsr_"
-	if (op>>19)&1 != 0 {
-		dst += "f"
-	}
-	if (op>>18)&1 != 0 {
-		dst += "s"
-	}
-	if (op>>17)&1 != 0 {
-		dst += "x"
-	}
-	if (op>>16)&1 != 0 {
-		dst += "c"
-	}
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("msr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := dst
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := op & 0xF
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm121(op uint32) {
 	// bx reg
 	if op&0x0FFFFFD0 != 0x012FFF10 {
@@ -6391,15 +5245,6 @@ func (cpu *Cpu) opArm121(op uint32) {
 	cpu.branch(rn, BranchJump)
 }
 
-func (cpu *Cpu) disasmArm121(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bx", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := op & 0xF
-	out.WriteString(RegNames[arg0])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm123(op uint32) {
 	// blx reg
 	if op&0x0FFFFFD0 != 0x012FFF10 {
@@ -6418,15 +5263,6 @@ func (cpu *Cpu) opArm123(op uint32) {
 	cpu.branch(rn, BranchCall)
 }
 
-func (cpu *Cpu) disasmArm123(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("blx", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := op & 0xF
-	out.WriteString(RegNames[arg0])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm128(op uint32) {
 	// smlawb
 	if cpu.arch < ARMv5 {
@@ -6446,24 +5282,6 @@ func (cpu *Cpu) opArm128(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm128(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlawb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm12A(op uint32) {
 	// smulwb
 	if cpu.arch < ARMv5 {
@@ -6480,21 +5298,6 @@ func (cpu *Cpu) opArm12A(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm12A(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smulwb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm12B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6513,24 +5316,6 @@ func (cpu *Cpu) opArm12B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm12B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm12C(op uint32) {
 	// smlawt
 	if cpu.arch < ARMv5 {
@@ -6550,24 +5335,6 @@ func (cpu *Cpu) opArm12C(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm12C(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smlawt", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg3])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm12D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6591,27 +5358,6 @@ func (cpu *Cpu) opArm12D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm12D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm12E(op uint32) {
 	// smulwt
 	if cpu.arch < ARMv5 {
@@ -6628,21 +5374,6 @@ func (cpu *Cpu) opArm12E(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm12E(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smulwt", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm12F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -6662,27 +5393,6 @@ func (cpu *Cpu) opArm12F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm12F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm130(op uint32) {
 	// teqs
 	rnx := (op >> 16) & 0xF
@@ -6712,18 +5422,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm130(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("teq", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm131(op uint32) {
 	// teqs
 	rnx := (op >> 16) & 0xF
@@ -6961,24 +5659,6 @@ func (cpu *Cpu) opArm13B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm13B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm13D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7002,24 +5682,6 @@ func (cpu *Cpu) opArm13D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm13D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm13F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7046,24 +5708,6 @@ func (cpu *Cpu) opArm13F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm13F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm140(op uint32) {
 	if op&0x0F900FF0 != 0x01000000 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as PSR_reg")
@@ -7083,18 +5727,6 @@ func (cpu *Cpu) opArm140(op uint32) {
 	cpu.Regs[rdx] = reg(*cpu.RegSpsr())
 }
 
-func (cpu *Cpu) disasmArm140(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mrs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmSpsrName()
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm148(op uint32) {
 	cpu.InvalidOpArm(op, "unhandled mul-type (10)")
 }
@@ -7115,23 +5747,6 @@ func (cpu *Cpu) opArm149(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm149(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("swpb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm14B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7144,30 +5759,6 @@ func (cpu *Cpu) opArm14B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm14B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm14D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7185,33 +5776,6 @@ func (cpu *Cpu) opArm14D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm14D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !false {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm14F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7225,33 +5789,6 @@ func (cpu *Cpu) opArm14F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm14F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !false {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm150(op uint32) {
 	// cmps
 	rnx := (op >> 16) & 0xF
@@ -7285,18 +5822,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm150(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("cmp", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm151(op uint32) {
 	// cmps
 	rnx := (op >> 16) & 0xF
@@ -7556,30 +6081,6 @@ func (cpu *Cpu) opArm15B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm15B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm15D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7597,30 +6098,6 @@ func (cpu *Cpu) opArm15D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm15D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm15F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7641,30 +6118,6 @@ func (cpu *Cpu) opArm15F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm15F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm160(op uint32) {
 	if op&0x0F900FF0 != 0x01000000 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as PSR_reg")
@@ -7689,31 +6142,6 @@ func (cpu *Cpu) opArm160(op uint32) {
 	cpu.RegSpsr().SetWithMask(val, mask)
 }
 
-func (cpu *Cpu) disasmArm160(op uint32, pc uint32) string {
-	dst := cpu.disasmSpsrName() + "_"
-	if (op>>19)&1 != 0 {
-		dst += "f"
-	}
-	if (op>>18)&1 != 0 {
-		dst += "s"
-	}
-	if (op>>17)&1 != 0 {
-		dst += "x"
-	}
-	if (op>>16)&1 != 0 {
-		dst += "c"
-	}
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("msr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := dst
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := op & 0xF
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm161(op uint32) {
 	// clz
 	if op&0x0FFF0FF0 != 0x016F0F10 {
@@ -7736,18 +6164,6 @@ func (cpu *Cpu) opArm161(op uint32) {
 	cpu.Regs[rdx] = reg(lz)
 }
 
-func (cpu *Cpu) disasmArm161(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("clz", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := op & 0xF
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm168(op uint32) {
 	// smulbb
 	if cpu.arch < ARMv5 {
@@ -7765,21 +6181,6 @@ func (cpu *Cpu) opArm168(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm168(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smulbb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm16A(op uint32) {
 	// smultb
 	if cpu.arch < ARMv5 {
@@ -7797,21 +6198,6 @@ func (cpu *Cpu) opArm16A(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm16A(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smultb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm16B(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7825,31 +6211,6 @@ func (cpu *Cpu) opArm16B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm16B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm16C(op uint32) {
 	// smulbt
 	if cpu.arch < ARMv5 {
@@ -7867,21 +6228,6 @@ func (cpu *Cpu) opArm16C(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm16C(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smulbt", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm16D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7900,34 +6246,6 @@ func (cpu *Cpu) opArm16D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm16D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !true {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm16E(op uint32) {
 	// smultt
 	if cpu.arch < ARMv5 {
@@ -7945,21 +6263,6 @@ func (cpu *Cpu) opArm16E(op uint32) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmArm16E(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("smultt", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 0) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 8) & 0xF
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opArm16F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -7974,34 +6277,6 @@ func (cpu *Cpu) opArm16F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm16F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !true {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm170(op uint32) {
 	// cmns
 	rnx := (op >> 16) & 0xF
@@ -8035,18 +6310,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm170(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("cmn", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm171(op uint32) {
 	// cmns
 	rnx := (op >> 16) & 0xF
@@ -8307,31 +6570,6 @@ func (cpu *Cpu) opArm17B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm17B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm17D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -8350,31 +6588,6 @@ func (cpu *Cpu) opArm17D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm17D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm17F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -8396,31 +6609,6 @@ func (cpu *Cpu) opArm17F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm17F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm180(op uint32) {
 	// orr
 	rnx := (op >> 16) & 0xF
@@ -8445,21 +6633,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm180(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("orr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm181(op uint32) {
 	// orr
 	rnx := (op >> 16) & 0xF
@@ -8654,23 +6827,6 @@ func (cpu *Cpu) opArm18B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm18B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm18D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -8693,26 +6849,6 @@ func (cpu *Cpu) opArm18D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm18D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm18F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -8731,26 +6867,6 @@ func (cpu *Cpu) opArm18F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm18F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm190(op uint32) {
 	// orrs
 	rnx := (op >> 16) & 0xF
@@ -8781,21 +6897,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm190(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("orrs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm191(op uint32) {
 	// orrs
 	rnx := (op >> 16) & 0xF
@@ -9039,23 +7140,6 @@ func (cpu *Cpu) opArm19B(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm19B(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm19D(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -9078,23 +7162,6 @@ func (cpu *Cpu) opArm19D(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm19D(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm19F(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -9120,23 +7187,6 @@ func (cpu *Cpu) opArm19F(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm19F(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1A0(op uint32) {
 	// mov
 	rnx := (op >> 16) & 0xF
@@ -9165,18 +7215,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm1A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mov", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1A1(op uint32) {
 	// mov
 	rnx := (op >> 16) & 0xF
@@ -9400,24 +7438,6 @@ func (cpu *Cpu) opArm1AB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1AB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1AD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -9441,27 +7461,6 @@ func (cpu *Cpu) opArm1AD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1AD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1AF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -9481,27 +7480,6 @@ func (cpu *Cpu) opArm1AF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1AF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg2a])
-	out.WriteString(", ")
-	out.WriteString(arg2b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1B0(op uint32) {
 	// movs
 	rnx := (op >> 16) & 0xF
@@ -9536,18 +7514,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm1B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("movs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1B1(op uint32) {
 	// movs
 	rnx := (op >> 16) & 0xF
@@ -9820,24 +7786,6 @@ func (cpu *Cpu) opArm1BB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1BB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1BD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -9861,24 +7809,6 @@ func (cpu *Cpu) opArm1BD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1BD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1BF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -9905,24 +7835,6 @@ func (cpu *Cpu) opArm1BF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1BF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := RegNames[op&0xF]
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1C0(op uint32) {
 	// bic
 	rnx := (op >> 16) & 0xF
@@ -9947,21 +7859,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm1C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bic", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1C1(op uint32) {
 	// bic
 	rnx := (op >> 16) & 0xF
@@ -10151,30 +8048,6 @@ func (cpu *Cpu) opArm1CB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1CB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1CD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -10192,33 +8065,6 @@ func (cpu *Cpu) opArm1CD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1CD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !false {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1CF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -10232,33 +8078,6 @@ func (cpu *Cpu) opArm1CF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1CF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !false {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1D0(op uint32) {
 	// bics
 	rnx := (op >> 16) & 0xF
@@ -10289,21 +8108,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm1D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bics", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1D1(op uint32) {
 	// bics
 	rnx := (op >> 16) & 0xF
@@ -10542,30 +8346,6 @@ func (cpu *Cpu) opArm1DB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1DB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1DD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -10583,30 +8363,6 @@ func (cpu *Cpu) opArm1DD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1DD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1DF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -10627,30 +8383,6 @@ func (cpu *Cpu) opArm1DF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1DF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1E0(op uint32) {
 	// mvn
 	rnx := (op >> 16) & 0xF
@@ -10675,18 +8407,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm1E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mvn", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1E1(op uint32) {
 	// mvn
 	rnx := (op >> 16) & 0xF
@@ -10877,31 +8597,6 @@ func (cpu *Cpu) opArm1EB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1EB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1ED(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -10920,34 +8615,6 @@ func (cpu *Cpu) opArm1ED(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1ED(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !true {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1EF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -10962,34 +8629,6 @@ func (cpu *Cpu) opArm1EF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1EF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strd", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 12) & 0xF) + 1
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2a := (op >> 16) & 0xF
-	arg2b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg2a] == "pc" && !true {
-		arg2c := uint32(arg2b) + uint32((pc+8)&^2)
-		arg2v := cpu.Read32(arg2c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg2v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg2a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg2b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1F0(op uint32) {
 	// mvns
 	rnx := (op >> 16) & 0xF
@@ -11020,18 +8659,6 @@ op2end:
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm1F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mvns", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := cpu.disasmOp2(op)
-	out.WriteString(arg1)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1F1(op uint32) {
 	// mvns
 	rnx := (op >> 16) & 0xF
@@ -11271,31 +8898,6 @@ func (cpu *Cpu) opArm1FB(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1FB(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1FD(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -11314,31 +8916,6 @@ func (cpu *Cpu) opArm1FD(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1FD(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm1FF(op uint32) {
 	rnx := (op >> 16) & 0xF
 	rdx := (op >> 12) & 0xF
@@ -11360,31 +8937,6 @@ func (cpu *Cpu) opArm1FF(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm1FF(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrsh", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32((op & 0xF) | ((op & 0xF00) >> 4))
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm200(op uint32) {
 	// and
 	rnx := (op >> 16) & 0xF
@@ -11403,22 +8955,6 @@ func (cpu *Cpu) opArm200(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm200(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("and", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm210(op uint32) {
 	// ands
 	rnx := (op >> 16) & 0xF
@@ -11444,22 +8980,6 @@ func (cpu *Cpu) opArm210(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm210(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ands", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm220(op uint32) {
 	// eor
 	rnx := (op >> 16) & 0xF
@@ -11478,22 +8998,6 @@ func (cpu *Cpu) opArm220(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm220(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("eor", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm230(op uint32) {
 	// eors
 	rnx := (op >> 16) & 0xF
@@ -11519,22 +9023,6 @@ func (cpu *Cpu) opArm230(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm230(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("eors", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm240(op uint32) {
 	// sub
 	rnx := (op >> 16) & 0xF
@@ -11553,22 +9041,6 @@ func (cpu *Cpu) opArm240(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm240(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("sub", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm250(op uint32) {
 	// subs
 	rnx := (op >> 16) & 0xF
@@ -11598,22 +9070,6 @@ func (cpu *Cpu) opArm250(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm250(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("subs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm260(op uint32) {
 	// rsb
 	rnx := (op >> 16) & 0xF
@@ -11632,22 +9088,6 @@ func (cpu *Cpu) opArm260(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm260(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rsb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm270(op uint32) {
 	// rsbs
 	rnx := (op >> 16) & 0xF
@@ -11677,22 +9117,6 @@ func (cpu *Cpu) opArm270(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm270(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rsbs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm280(op uint32) {
 	// add
 	rnx := (op >> 16) & 0xF
@@ -11711,22 +9135,6 @@ func (cpu *Cpu) opArm280(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm280(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("add", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm290(op uint32) {
 	// adds
 	rnx := (op >> 16) & 0xF
@@ -11756,22 +9164,6 @@ func (cpu *Cpu) opArm290(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm290(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("adds", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm2A0(op uint32) {
 	// adc
 	rnx := (op >> 16) & 0xF
@@ -11791,22 +9183,6 @@ func (cpu *Cpu) opArm2A0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm2A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("adc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm2B0(op uint32) {
 	// adcs
 	rnx := (op >> 16) & 0xF
@@ -11841,22 +9217,6 @@ func (cpu *Cpu) opArm2B0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm2B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("adcs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm2C0(op uint32) {
 	// sbc
 	rnx := (op >> 16) & 0xF
@@ -11876,22 +9236,6 @@ func (cpu *Cpu) opArm2C0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm2C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("sbc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm2D0(op uint32) {
 	// sbcs
 	rnx := (op >> 16) & 0xF
@@ -11926,22 +9270,6 @@ func (cpu *Cpu) opArm2D0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm2D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("sbcs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm2E0(op uint32) {
 	// rsc
 	rnx := (op >> 16) & 0xF
@@ -11962,22 +9290,6 @@ func (cpu *Cpu) opArm2E0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm2E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rsc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm2F0(op uint32) {
 	// rscs
 	rnx := (op >> 16) & 0xF
@@ -12013,22 +9325,6 @@ func (cpu *Cpu) opArm2F0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm2F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("rscs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm310(op uint32) {
 	// tsts
 	rnx := (op >> 16) & 0xF
@@ -12053,19 +9349,6 @@ func (cpu *Cpu) opArm310(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm310(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("tst", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm320(op uint32) {
 	if op&0x0FB00000 != 0x03200000 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as PSR_imm")
@@ -12091,32 +9374,6 @@ func (cpu *Cpu) opArm320(op uint32) {
 	cpu.Cpsr.SetWithMask(val, mask, cpu)
 }
 
-func (cpu *Cpu) disasmArm320(op uint32, pc uint32) string {
-	dst := "cpsr_"
-	if (op>>19)&1 != 0 {
-		dst += "f"
-	}
-	if (op>>18)&1 != 0 {
-		dst += "s"
-	}
-	if (op>>17)&1 != 0 {
-		dst += "x"
-	}
-	if (op>>16)&1 != 0 {
-		dst += "c"
-	}
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("msr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := dst
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> uint(((op>>8)&0xF)*2)) | ((op & 0xFF) << (32 - uint(((op>>8)&0xF)*2))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm330(op uint32) {
 	// teqs
 	rnx := (op >> 16) & 0xF
@@ -12141,19 +9398,6 @@ func (cpu *Cpu) opArm330(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm330(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("teq", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm350(op uint32) {
 	// cmps
 	rnx := (op >> 16) & 0xF
@@ -12182,19 +9426,6 @@ func (cpu *Cpu) opArm350(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm350(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("cmp", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm360(op uint32) {
 	if op&0x0FB00000 != 0x03200000 {
 		cpu.InvalidOpArm(op, "invalid opcode decoded as PSR_imm")
@@ -12220,32 +9451,6 @@ func (cpu *Cpu) opArm360(op uint32) {
 	cpu.RegSpsr().SetWithMask(val, mask)
 }
 
-func (cpu *Cpu) disasmArm360(op uint32, pc uint32) string {
-	dst := cpu.disasmSpsrName() + "_"
-	if (op>>19)&1 != 0 {
-		dst += "f"
-	}
-	if (op>>18)&1 != 0 {
-		dst += "s"
-	}
-	if (op>>17)&1 != 0 {
-		dst += "x"
-	}
-	if (op>>16)&1 != 0 {
-		dst += "c"
-	}
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("msr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := dst
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> uint(((op>>8)&0xF)*2)) | ((op & 0xFF) << (32 - uint(((op>>8)&0xF)*2))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm370(op uint32) {
 	// cmns
 	rnx := (op >> 16) & 0xF
@@ -12274,19 +9479,6 @@ func (cpu *Cpu) opArm370(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm370(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("cmn", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm380(op uint32) {
 	// orr
 	rnx := (op >> 16) & 0xF
@@ -12305,22 +9497,6 @@ func (cpu *Cpu) opArm380(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm380(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("orr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm390(op uint32) {
 	// orrs
 	rnx := (op >> 16) & 0xF
@@ -12346,22 +9522,6 @@ func (cpu *Cpu) opArm390(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm390(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("orrs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm3A0(op uint32) {
 	// mov
 	rnx := (op >> 16) & 0xF
@@ -12384,19 +9544,6 @@ func (cpu *Cpu) opArm3A0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm3A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mov", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm3B0(op uint32) {
 	// movs
 	rnx := (op >> 16) & 0xF
@@ -12426,19 +9573,6 @@ func (cpu *Cpu) opArm3B0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm3B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("movs", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm3C0(op uint32) {
 	// bic
 	rnx := (op >> 16) & 0xF
@@ -12457,22 +9591,6 @@ func (cpu *Cpu) opArm3C0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm3C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bic", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm3D0(op uint32) {
 	// bics
 	rnx := (op >> 16) & 0xF
@@ -12498,22 +9616,6 @@ func (cpu *Cpu) opArm3D0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm3D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bics", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm3E0(op uint32) {
 	// mvn
 	rnx := (op >> 16) & 0xF
@@ -12532,19 +9634,6 @@ func (cpu *Cpu) opArm3E0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm3E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mvn", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm3F0(op uint32) {
 	// mvns
 	rnx := (op >> 16) & 0xF
@@ -12570,19 +9659,6 @@ func (cpu *Cpu) opArm3F0(op uint32) {
 	_ = cf
 }
 
-func (cpu *Cpu) disasmArm3F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mvns", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(((op & 0xFF) >> ((op >> 7) & 0x1E)) | ((op & 0xFF) << (32 - ((op >> 7) & 0x1E))))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm400(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -12601,24 +9677,6 @@ func (cpu *Cpu) opArm400(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm400(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm410(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -12650,24 +9708,6 @@ func (cpu *Cpu) opArm410(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm410(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm420(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -12737,24 +9777,6 @@ func (cpu *Cpu) opArm440(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm440(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm450(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -12782,24 +9804,6 @@ func (cpu *Cpu) opArm450(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm450(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(-int64(op & 0xFFF))
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm460(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -12865,24 +9869,6 @@ func (cpu *Cpu) opArm480(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm480(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(op & 0xFFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm490(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -12914,24 +9900,6 @@ func (cpu *Cpu) opArm490(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm490(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(op & 0xFFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm4A0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13001,24 +9969,6 @@ func (cpu *Cpu) opArm4C0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm4C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(op & 0xFFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm4D0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13046,24 +9996,6 @@ func (cpu *Cpu) opArm4D0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm4D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := int64(op & 0xFFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArm4E0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13128,30 +10060,6 @@ func (cpu *Cpu) opArm500(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm500(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm510(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13182,30 +10090,6 @@ func (cpu *Cpu) opArm510(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm510(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm520(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13224,31 +10108,6 @@ func (cpu *Cpu) opArm520(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm520(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm530(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13280,31 +10139,6 @@ func (cpu *Cpu) opArm530(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm530(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm540(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13322,30 +10156,6 @@ func (cpu *Cpu) opArm540(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm540(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm550(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13372,30 +10182,6 @@ func (cpu *Cpu) opArm550(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm550(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm560(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13414,31 +10200,6 @@ func (cpu *Cpu) opArm560(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm560(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm570(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13466,31 +10227,6 @@ func (cpu *Cpu) opArm570(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm570(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := -int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm580(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13508,30 +10244,6 @@ func (cpu *Cpu) opArm580(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm580(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm590(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13562,30 +10274,6 @@ func (cpu *Cpu) opArm590(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm590(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm5A0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13604,31 +10292,6 @@ func (cpu *Cpu) opArm5A0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm5A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm5B0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13660,31 +10323,6 @@ func (cpu *Cpu) opArm5B0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm5B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm5C0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13702,30 +10340,6 @@ func (cpu *Cpu) opArm5C0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm5C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm5D0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13752,30 +10366,6 @@ func (cpu *Cpu) opArm5D0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm5D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm5E0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13794,31 +10384,6 @@ func (cpu *Cpu) opArm5E0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm5E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm5F0(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13846,31 +10411,6 @@ func (cpu *Cpu) opArm5F0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm5F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := int32(op & 0xFFF)
-	if RegNames[arg1a] == "pc" && !true {
-		arg1c := uint32(arg1b) + uint32((pc+8)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-		out.WriteString("!")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opArm600(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -13899,23 +10439,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm600(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm601(op uint32) {
 	// undefined
 	cpu.Exception(ExceptionUndefined)
@@ -14046,23 +10569,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm610(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm612(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -14498,23 +11004,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm640(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm642(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -14636,23 +11125,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm650(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := "-" + cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm652(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -15060,23 +11532,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm680(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm682(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -15202,23 +11657,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm690(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm692(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -15654,23 +12092,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm6C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm6C2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -15792,23 +12213,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm6D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 16) & 0xF
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1])
-	out.WriteString("]")
-	out.WriteString(", ")
-	arg2 := cpu.disasmOp2(op)
-	out.WriteString(arg2)
-	return out.String()
-}
-
 func (cpu *Cpu) opArm6D2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -16215,23 +12619,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm700(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm702(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -16353,23 +12740,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm710(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm712(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -16518,24 +12888,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm720(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm722(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -16661,24 +13013,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm730(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm732(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -16829,23 +13163,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm740(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm742(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -16963,23 +13280,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm750(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm752(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -17116,24 +13416,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm760(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm762(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -17255,24 +13537,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm770(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := "-" + cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm772(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -17411,23 +13675,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm780(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm782(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -17549,23 +13796,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm790(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm792(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -17714,24 +13944,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm7A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("str", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm7A2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -17857,24 +14069,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm7B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm7B2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -18025,23 +14219,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm7C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm7C2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -18159,23 +14336,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm7D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm7D2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -18312,24 +14472,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm7E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("strb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm7E2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -18451,24 +14593,6 @@ op2end:
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm7F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldrb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 16) & 0xF
-	arg1b := cpu.disasmOp2(op)
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(arg1b)
-	out.WriteString("]")
-	out.WriteString("!")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm7F2(op uint32) {
 	if (op >> 28) == 0xF {
 		cpu.InvalidOpArm(op, "PLD not supported")
@@ -18607,30 +14731,6 @@ func (cpu *Cpu) opArm800(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm800(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm810(op uint32) {
 	// ldmda
 	rnx := (op >> 16) & 0xF
@@ -18670,30 +14770,6 @@ func (cpu *Cpu) opArm810(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm810(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm820(op uint32) {
 	// stmda
 	rnx := (op >> 16) & 0xF
@@ -18743,31 +14819,6 @@ func (cpu *Cpu) opArm820(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm820(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm830(op uint32) {
 	// ldmda
 	rnx := (op >> 16) & 0xF
@@ -18830,31 +14881,6 @@ func (cpu *Cpu) opArm830(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm830(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm840(op uint32) {
 	// stmda
 	rnx := (op >> 16) & 0xF
@@ -18890,31 +14916,6 @@ func (cpu *Cpu) opArm840(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm840(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm850(op uint32) {
 	// ldmda
 	rnx := (op >> 16) & 0xF
@@ -18964,31 +14965,6 @@ func (cpu *Cpu) opArm850(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm850(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm860(op uint32) {
 	// stmda
 	rnx := (op >> 16) & 0xF
@@ -19046,32 +15022,6 @@ func (cpu *Cpu) opArm860(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm860(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm870(op uint32) {
 	// ldmda
 	rnx := (op >> 16) & 0xF
@@ -19144,32 +15094,6 @@ func (cpu *Cpu) opArm870(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm870(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmda", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm880(op uint32) {
 	// stm
 	rnx := (op >> 16) & 0xF
@@ -19196,30 +15120,6 @@ func (cpu *Cpu) opArm880(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm880(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm890(op uint32) {
 	// ldm
 	rnx := (op >> 16) & 0xF
@@ -19258,30 +15158,6 @@ func (cpu *Cpu) opArm890(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm890(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm8A0(op uint32) {
 	// stm
 	rnx := (op >> 16) & 0xF
@@ -19329,31 +15205,6 @@ func (cpu *Cpu) opArm8A0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm8A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm8B0(op uint32) {
 	// ldm
 	rnx := (op >> 16) & 0xF
@@ -19414,31 +15265,6 @@ func (cpu *Cpu) opArm8B0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm8B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm8C0(op uint32) {
 	// stm
 	rnx := (op >> 16) & 0xF
@@ -19473,31 +15299,6 @@ func (cpu *Cpu) opArm8C0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm8C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm8D0(op uint32) {
 	// ldm
 	rnx := (op >> 16) & 0xF
@@ -19546,31 +15347,6 @@ func (cpu *Cpu) opArm8D0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm8D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm8E0(op uint32) {
 	// stm
 	rnx := (op >> 16) & 0xF
@@ -19626,32 +15402,6 @@ func (cpu *Cpu) opArm8E0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm8E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm8F0(op uint32) {
 	// ldm
 	rnx := (op >> 16) & 0xF
@@ -19722,32 +15472,6 @@ func (cpu *Cpu) opArm8F0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm8F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldm", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm900(op uint32) {
 	// stmdb
 	rnx := (op >> 16) & 0xF
@@ -19775,30 +15499,6 @@ func (cpu *Cpu) opArm900(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm900(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm910(op uint32) {
 	// ldmdb
 	rnx := (op >> 16) & 0xF
@@ -19838,30 +15538,6 @@ func (cpu *Cpu) opArm910(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm910(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm920(op uint32) {
 	// stmdb
 	rnx := (op >> 16) & 0xF
@@ -19911,31 +15587,6 @@ func (cpu *Cpu) opArm920(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm920(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm930(op uint32) {
 	// ldmdb
 	rnx := (op >> 16) & 0xF
@@ -19998,31 +15649,6 @@ func (cpu *Cpu) opArm930(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm930(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm940(op uint32) {
 	// stmdb
 	rnx := (op >> 16) & 0xF
@@ -20058,31 +15684,6 @@ func (cpu *Cpu) opArm940(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm940(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm950(op uint32) {
 	// ldmdb
 	rnx := (op >> 16) & 0xF
@@ -20132,31 +15733,6 @@ func (cpu *Cpu) opArm950(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm950(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm960(op uint32) {
 	// stmdb
 	rnx := (op >> 16) & 0xF
@@ -20214,32 +15790,6 @@ func (cpu *Cpu) opArm960(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm960(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm970(op uint32) {
 	// ldmdb
 	rnx := (op >> 16) & 0xF
@@ -20312,32 +15862,6 @@ func (cpu *Cpu) opArm970(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm970(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmdb", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm980(op uint32) {
 	// stmib
 	rnx := (op >> 16) & 0xF
@@ -20364,30 +15888,6 @@ func (cpu *Cpu) opArm980(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm980(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm990(op uint32) {
 	// ldmib
 	rnx := (op >> 16) & 0xF
@@ -20426,30 +15926,6 @@ func (cpu *Cpu) opArm990(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm990(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm9A0(op uint32) {
 	// stmib
 	rnx := (op >> 16) & 0xF
@@ -20497,31 +15973,6 @@ func (cpu *Cpu) opArm9A0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm9A0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm9B0(op uint32) {
 	// ldmib
 	rnx := (op >> 16) & 0xF
@@ -20582,31 +16033,6 @@ func (cpu *Cpu) opArm9B0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm9B0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm9C0(op uint32) {
 	// stmib
 	rnx := (op >> 16) & 0xF
@@ -20641,31 +16067,6 @@ func (cpu *Cpu) opArm9C0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm9C0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm9D0(op uint32) {
 	// ldmib
 	rnx := (op >> 16) & 0xF
@@ -20714,31 +16115,6 @@ func (cpu *Cpu) opArm9D0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm9D0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm9E0(op uint32) {
 	// stmib
 	rnx := (op >> 16) & 0xF
@@ -20794,32 +16170,6 @@ func (cpu *Cpu) opArm9E0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm9E0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("stmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArm9F0(op uint32) {
 	// ldmib
 	rnx := (op >> 16) & 0xF
@@ -20890,32 +16240,6 @@ func (cpu *Cpu) opArm9F0(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArm9F0(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("ldmib", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := (op >> 16) & 0xF
-	out.WriteString(RegNames[arg0])
-	out.WriteString("!")
-	out.WriteString(", ")
-	arg1 := uint16(op & 0xFFFF)
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	out.WriteString("^")
-	return out.String()
-}
-
 func (cpu *Cpu) opArmA00(op uint32) {
 	if op>>28 == 0xF {
 		// BLX_imm
@@ -20932,24 +16256,6 @@ func (cpu *Cpu) opArmA00(op uint32) {
 	cpu.branch(cpu.Regs[15], BranchCall)
 }
 
-func (cpu *Cpu) disasmArmA00(op uint32, pc uint32) string {
-	if op>>28 == 0xF {
-		var out bytes.Buffer
-		out.WriteString("blx       ")
-		arg0 := int32(int32(op<<8) >> 6)
-		arg0x := pc + 8 + uint32(arg0)
-		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-		return out.String()
-	}
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("b", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := int32(int32(op<<8) >> 6)
-	arg0x := pc + 8 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArmB00(op uint32) {
 	if op>>28 == 0xF {
 		// BLX_imm
@@ -20968,24 +16274,6 @@ func (cpu *Cpu) opArmB00(op uint32) {
 	cpu.branch(cpu.Regs[15], BranchCall)
 }
 
-func (cpu *Cpu) disasmArmB00(op uint32, pc uint32) string {
-	if op>>28 == 0xF {
-		var out bytes.Buffer
-		out.WriteString("blx       ")
-		arg0 := int32(int32(op<<8) >> 6)
-		arg0x := pc + 8 + uint32(arg0)
-		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-		return out.String()
-	}
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("bl", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := int32(int32(op<<8) >> 6)
-	arg0x := pc + 8 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opArmC00(op uint32) {
 	cpu.InvalidOpArm(op, "unimplemented")
 }
@@ -21001,32 +16289,6 @@ func (cpu *Cpu) opArmE00(op uint32) {
 	cpu.opCopExec(copnum, opc, cn, cm, cp, rdx)
 }
 
-func (cpu *Cpu) disasmArmE00(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("cdp", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := int64((op >> 21) & 0x7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg1, 10))
-	out.WriteString(", ")
-	arg2 := "c" + strconv.FormatInt(int64(op>>12)&0xF, 10)
-	out.WriteString(arg2)
-	out.WriteString(", ")
-	arg3 := "c" + strconv.FormatInt(int64(op>>16)&0xF, 10)
-	out.WriteString(arg3)
-	out.WriteString(", ")
-	arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
-	out.WriteString(arg4)
-	out.WriteString(", ")
-	arg5 := int64((op >> 5) & 0x7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg5, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opArmE01(op uint32) {
 	// MCR
 	opc := (op >> 21) & 0x7
@@ -21041,32 +16303,6 @@ func (cpu *Cpu) opArmE01(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArmE01(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mcr", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := int64((op >> 21) & 0x7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg1, 10))
-	out.WriteString(", ")
-	arg2 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := "c" + strconv.FormatInt(int64(op>>16)&0xF, 10)
-	out.WriteString(arg3)
-	out.WriteString(", ")
-	arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
-	out.WriteString(arg4)
-	out.WriteString(", ")
-	arg5 := int64((op >> 5) & 0x7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg5, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opArmE11(op uint32) {
 	// MRC
 	opc := (op >> 21) & 0x7
@@ -21084,47 +16320,11 @@ func (cpu *Cpu) opArmE11(op uint32) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmArmE11(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("mrc", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := "p" + strconv.FormatInt(int64(op>>8)&0xF, 10)
-	out.WriteString(arg0)
-	out.WriteString(", ")
-	arg1 := int64((op >> 21) & 0x7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg1, 10))
-	out.WriteString(", ")
-	arg2 := (op >> 12) & 0xF
-	out.WriteString(RegNames[arg2])
-	out.WriteString(", ")
-	arg3 := "c" + strconv.FormatInt(int64(op>>16)&0xF, 10)
-	out.WriteString(arg3)
-	out.WriteString(", ")
-	arg4 := "c" + strconv.FormatInt(int64(op>>0)&0xF, 10)
-	out.WriteString(arg4)
-	out.WriteString(", ")
-	arg5 := int64((op >> 5) & 0x7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg5, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opArmF00(op uint32) {
 	cpu.Exception(ExceptionSwi)
 	cpu.Clock += 2
 }
 
-func (cpu *Cpu) disasmArmF00(op uint32, pc uint32) string {
-	var out bytes.Buffer
-	opcode := cpu.disasmAddCond("swi", op)
-	out.WriteString((opcode + "                ")[:10])
-	arg0 := int64(op & 0xFFFFFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg0, 16))
-	return out.String()
-}
-
 var opArmTable = [4096]func(*Cpu, uint32){
 	(*Cpu).opArm000, (*Cpu).opArm001, (*Cpu).opArm002, (*Cpu).opArm003,
 	(*Cpu).opArm004, (*Cpu).opArm005, (*Cpu).opArm006, (*Cpu).opArm007,
@@ -22151,1029 +17351,3 @@ var opArmTable = [4096]func(*Cpu, uint32){
 	(*Cpu).opArmF00, (*Cpu).opArmF00, (*Cpu).opArmF00, (*Cpu).opArmF00,
 	(*Cpu).opArmF00, (*Cpu).opArmF00, (*Cpu).opArmF00, (*Cpu).opArmF00,
 }
-var disasmArmTable = [4096]func(*Cpu, uint32, uint32) string{
-	(*Cpu).disasmArm000, (*Cpu).disasmArm000, (*Cpu).disasmArm000, (*Cpu).disasmArm000,
-	(*Cpu).disasmArm000, (*Cpu).disasmArm000, (*Cpu).disasmArm000, (*Cpu).disasmArm000,
-	(*Cpu).disasmArm000, (*Cpu).disasmArm009, (*Cpu).disasmArm000, (*Cpu).disasmArm00B,
-	(*Cpu).disasmArm000, (*Cpu).disasmArm00D, (*Cpu).disasmArm000, (*Cpu).disasmArm00F,
-	(*Cpu).disasmArm010, (*Cpu).disasmArm010, (*Cpu).disasmArm010, (*Cpu).disasmArm010,
-	(*Cpu).disasmArm010, (*Cpu).disasmArm010, (*Cpu).disasmArm010, (*Cpu).disasmArm010,
-	(*Cpu).disasmArm010, (*Cpu).disasmArm019, (*Cpu).disasmArm010, (*Cpu).disasmArm01B,
-	(*Cpu).disasmArm010, (*Cpu).disasmArm01D, (*Cpu).disasmArm010, (*Cpu).disasmArm01F,
-	(*Cpu).disasmArm020, (*Cpu).disasmArm020, (*Cpu).disasmArm020, (*Cpu).disasmArm020,
-	(*Cpu).disasmArm020, (*Cpu).disasmArm020, (*Cpu).disasmArm020, (*Cpu).disasmArm020,
-	(*Cpu).disasmArm020, (*Cpu).disasmArm029, (*Cpu).disasmArm020, (*Cpu).disasmArm00B,
-	(*Cpu).disasmArm020, (*Cpu).disasmArm00D, (*Cpu).disasmArm020, (*Cpu).disasmArm00F,
-	(*Cpu).disasmArm030, (*Cpu).disasmArm030, (*Cpu).disasmArm030, (*Cpu).disasmArm030,
-	(*Cpu).disasmArm030, (*Cpu).disasmArm030, (*Cpu).disasmArm030, (*Cpu).disasmArm030,
-	(*Cpu).disasmArm030, (*Cpu).disasmArm039, (*Cpu).disasmArm030, (*Cpu).disasmArm01B,
-	(*Cpu).disasmArm030, (*Cpu).disasmArm01D, (*Cpu).disasmArm030, (*Cpu).disasmArm01F,
-	(*Cpu).disasmArm040, (*Cpu).disasmArm040, (*Cpu).disasmArm040, (*Cpu).disasmArm040,
-	(*Cpu).disasmArm040, (*Cpu).disasmArm040, (*Cpu).disasmArm040, (*Cpu).disasmArm040,
-	(*Cpu).disasmArm040, (*Cpu).disasmArm049, (*Cpu).disasmArm040, (*Cpu).disasmArm04B,
-	(*Cpu).disasmArm040, (*Cpu).disasmArm04D, (*Cpu).disasmArm040, (*Cpu).disasmArm04F,
-	(*Cpu).disasmArm050, (*Cpu).disasmArm050, (*Cpu).disasmArm050, (*Cpu).disasmArm050,
-	(*Cpu).disasmArm050, (*Cpu).disasmArm050, (*Cpu).disasmArm050, (*Cpu).disasmArm050,
-	(*Cpu).disasmArm050, (*Cpu).disasmArm049, (*Cpu).disasmArm050, (*Cpu).disasmArm05B,
-	(*Cpu).disasmArm050, (*Cpu).disasmArm05D, (*Cpu).disasmArm050, (*Cpu).disasmArm05F,
-	(*Cpu).disasmArm060, (*Cpu).disasmArm060, (*Cpu).disasmArm060, (*Cpu).disasmArm060,
-	(*Cpu).disasmArm060, (*Cpu).disasmArm060, (*Cpu).disasmArm060, (*Cpu).disasmArm060,
-	(*Cpu).disasmArm060, (*Cpu).disasmArm049, (*Cpu).disasmArm060, (*Cpu).disasmArm04B,
-	(*Cpu).disasmArm060, (*Cpu).disasmArm04D, (*Cpu).disasmArm060, (*Cpu).disasmArm04F,
-	(*Cpu).disasmArm070, (*Cpu).disasmArm070, (*Cpu).disasmArm070, (*Cpu).disasmArm070,
-	(*Cpu).disasmArm070, (*Cpu).disasmArm070, (*Cpu).disasmArm070, (*Cpu).disasmArm070,
-	(*Cpu).disasmArm070, (*Cpu).disasmArm049, (*Cpu).disasmArm070, (*Cpu).disasmArm05B,
-	(*Cpu).disasmArm070, (*Cpu).disasmArm05D, (*Cpu).disasmArm070, (*Cpu).disasmArm05F,
-	(*Cpu).disasmArm080, (*Cpu).disasmArm080, (*Cpu).disasmArm080, (*Cpu).disasmArm080,
-	(*Cpu).disasmArm080, (*Cpu).disasmArm080, (*Cpu).disasmArm080, (*Cpu).disasmArm080,
-	(*Cpu).disasmArm080, (*Cpu).disasmArm089, (*Cpu).disasmArm080, (*Cpu).disasmArm08B,
-	(*Cpu).disasmArm080, (*Cpu).disasmArm08D, (*Cpu).disasmArm080, (*Cpu).disasmArm08F,
-	(*Cpu).disasmArm090, (*Cpu).disasmArm090, (*Cpu).disasmArm090, (*Cpu).disasmArm090,
-	(*Cpu).disasmArm090, (*Cpu).disasmArm090, (*Cpu).disasmArm090, (*Cpu).disasmArm090,
-	(*Cpu).disasmArm090, (*Cpu).disasmArm099, (*Cpu).disasmArm090, (*Cpu).disasmArm09B,
-	(*Cpu).disasmArm090, (*Cpu).disasmArm09D, (*Cpu).disasmArm090, (*Cpu).disasmArm09F,
-	(*Cpu).disasmArm0A0, (*Cpu).disasmArm0A0, (*Cpu).disasmArm0A0, (*Cpu).disasmArm0A0,
-	(*Cpu).disasmArm0A0, (*Cpu).disasmArm0A0, (*Cpu).disasmArm0A0, (*Cpu).disasmArm0A0,
-	(*Cpu).disasmArm0A0, (*Cpu).disasmArm0A9, (*Cpu).disasmArm0A0, (*Cpu).disasmArm08B,
-	(*Cpu).disasmArm0A0, (*Cpu).disasmArm08D, (*Cpu).disasmArm0A0, (*Cpu).disasmArm08F,
-	(*Cpu).disasmArm0B0, (*Cpu).disasmArm0B0, (*Cpu).disasmArm0B0, (*Cpu).disasmArm0B0,
-	(*Cpu).disasmArm0B0, (*Cpu).disasmArm0B0, (*Cpu).disasmArm0B0, (*Cpu).disasmArm0B0,
-	(*Cpu).disasmArm0B0, (*Cpu).disasmArm0B9, (*Cpu).disasmArm0B0, (*Cpu).disasmArm09B,
-	(*Cpu).disasmArm0B0, (*Cpu).disasmArm09D, (*Cpu).disasmArm0B0, (*Cpu).disasmArm09F,
-	(*Cpu).disasmArm0C0, (*Cpu).disasmArm0C0, (*Cpu).disasmArm0C0, (*Cpu).disasmArm0C0,
-	(*Cpu).disasmArm0C0, (*Cpu).disasmArm0C0, (*Cpu).disasmArm0C0, (*Cpu).disasmArm0C0,
-	(*Cpu).disasmArm0C0, (*Cpu).disasmArm0C9, (*Cpu).disasmArm0C0, (*Cpu).disasmArm0CB,
-	(*Cpu).disasmArm0C0, (*Cpu).disasmArm0CD, (*Cpu).disasmArm0C0, (*Cpu).disasmArm0CF,
-	(*Cpu).disasmArm0D0, (*Cpu).disasmArm0D0, (*Cpu).disasmArm0D0, (*Cpu).disasmArm0D0,
-	(*Cpu).disasmArm0D0, (*Cpu).disasmArm0D0, (*Cpu).disasmArm0D0, (*Cpu).disasmArm0D0,
-	(*Cpu).disasmArm0D0, (*Cpu).disasmArm0D9, (*Cpu).disasmArm0D0, (*Cpu).disasmArm0DB,
-	(*Cpu).disasmArm0D0, (*Cpu).disasmArm0DD, (*Cpu).disasmArm0D0, (*Cpu).disasmArm0DF,
-	(*Cpu).disasmArm0E0, (*Cpu).disasmArm0E0, (*Cpu).disasmArm0E0, (*Cpu).disasmArm0E0,
-	(*Cpu).disasmArm0E0, (*Cpu).disasmArm0E0, (*Cpu).disasmArm0E0, (*Cpu).disasmArm0E0,
-	(*Cpu).disasmArm0E0, (*Cpu).disasmArm0E9, (*Cpu).disasmArm0E0, (*Cpu).disasmArm0CB,
-	(*Cpu).disasmArm0E0, (*Cpu).disasmArm0CD, (*Cpu).disasmArm0E0, (*Cpu).disasmArm0CF,
-	(*Cpu).disasmArm0F0, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0F0,
-	(*Cpu).disasmArm0F0, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0F0,
-	(*Cpu).disasmArm0F0, (*Cpu).disasmArm0F9, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0DB,
-	(*Cpu).disasmArm0F0, (*Cpu).disasmArm0DD, (*Cpu).disasmArm0F0, (*Cpu).disasmArm0DF,
-	(*Cpu).disasmArm100, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm108, (*Cpu).disasmArm109, (*Cpu).disasmArm10A, (*Cpu).disasmArm10B,
-	(*Cpu).disasmArm10C, (*Cpu).disasmArm10D, (*Cpu).disasmArm10E, (*Cpu).disasmArm10F,
-	(*Cpu).disasmArm110, (*Cpu).disasmArm110, (*Cpu).disasmArm110, (*Cpu).disasmArm110,
-	(*Cpu).disasmArm110, (*Cpu).disasmArm110, (*Cpu).disasmArm110, (*Cpu).disasmArm110,
-	(*Cpu).disasmArm110, (*Cpu).disasmArm049, (*Cpu).disasmArm110, (*Cpu).disasmArm11B,
-	(*Cpu).disasmArm110, (*Cpu).disasmArm11D, (*Cpu).disasmArm110, (*Cpu).disasmArm11F,
-	(*Cpu).disasmArm120, (*Cpu).disasmArm121, (*Cpu).disasmArm049, (*Cpu).disasmArm123,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm128, (*Cpu).disasmArm049, (*Cpu).disasmArm12A, (*Cpu).disasmArm12B,
-	(*Cpu).disasmArm12C, (*Cpu).disasmArm12D, (*Cpu).disasmArm12E, (*Cpu).disasmArm12F,
-	(*Cpu).disasmArm130, (*Cpu).disasmArm130, (*Cpu).disasmArm130, (*Cpu).disasmArm130,
-	(*Cpu).disasmArm130, (*Cpu).disasmArm130, (*Cpu).disasmArm130, (*Cpu).disasmArm130,
-	(*Cpu).disasmArm130, (*Cpu).disasmArm049, (*Cpu).disasmArm130, (*Cpu).disasmArm13B,
-	(*Cpu).disasmArm130, (*Cpu).disasmArm13D, (*Cpu).disasmArm130, (*Cpu).disasmArm13F,
-	(*Cpu).disasmArm140, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm149, (*Cpu).disasmArm049, (*Cpu).disasmArm14B,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm14D, (*Cpu).disasmArm049, (*Cpu).disasmArm14F,
-	(*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150,
-	(*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150, (*Cpu).disasmArm150,
-	(*Cpu).disasmArm150, (*Cpu).disasmArm049, (*Cpu).disasmArm150, (*Cpu).disasmArm15B,
-	(*Cpu).disasmArm150, (*Cpu).disasmArm15D, (*Cpu).disasmArm150, (*Cpu).disasmArm15F,
-	(*Cpu).disasmArm160, (*Cpu).disasmArm161, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm168, (*Cpu).disasmArm049, (*Cpu).disasmArm16A, (*Cpu).disasmArm16B,
-	(*Cpu).disasmArm16C, (*Cpu).disasmArm16D, (*Cpu).disasmArm16E, (*Cpu).disasmArm16F,
-	(*Cpu).disasmArm170, (*Cpu).disasmArm170, (*Cpu).disasmArm170, (*Cpu).disasmArm170,
-	(*Cpu).disasmArm170, (*Cpu).disasmArm170, (*Cpu).disasmArm170, (*Cpu).disasmArm170,
-	(*Cpu).disasmArm170, (*Cpu).disasmArm049, (*Cpu).disasmArm170, (*Cpu).disasmArm17B,
-	(*Cpu).disasmArm170, (*Cpu).disasmArm17D, (*Cpu).disasmArm170, (*Cpu).disasmArm17F,
-	(*Cpu).disasmArm180, (*Cpu).disasmArm180, (*Cpu).disasmArm180, (*Cpu).disasmArm180,
-	(*Cpu).disasmArm180, (*Cpu).disasmArm180, (*Cpu).disasmArm180, (*Cpu).disasmArm180,
-	(*Cpu).disasmArm180, (*Cpu).disasmArm049, (*Cpu).disasmArm180, (*Cpu).disasmArm18B,
-	(*Cpu).disasmArm180, (*Cpu).disasmArm18D, (*Cpu).disasmArm180, (*Cpu).disasmArm18F,
-	(*Cpu).disasmArm190, (*Cpu).disasmArm190, (*Cpu).disasmArm190, (*Cpu).disasmArm190,
-	(*Cpu).disasmArm190, (*Cpu).disasmArm190, (*Cpu).disasmArm190, (*Cpu).disasmArm190,
-	(*Cpu).disasmArm190, (*Cpu).disasmArm049, (*Cpu).disasmArm190, (*Cpu).disasmArm19B,
-	(*Cpu).disasmArm190, (*Cpu).disasmArm19D, (*Cpu).disasmArm190, (*Cpu).disasmArm19F,
-	(*Cpu).disasmArm1A0, (*Cpu).disasmArm1A0, (*Cpu).disasmArm1A0, (*Cpu).disasmArm1A0,
-	(*Cpu).disasmArm1A0, (*Cpu).disasmArm1A0, (*Cpu).disasmArm1A0, (*Cpu).disasmArm1A0,
-	(*Cpu).disasmArm1A0, (*Cpu).disasmArm049, (*Cpu).disasmArm1A0, (*Cpu).disasmArm1AB,
-	(*Cpu).disasmArm1A0, (*Cpu).disasmArm1AD, (*Cpu).disasmArm1A0, (*Cpu).disasmArm1AF,
-	(*Cpu).disasmArm1B0, (*Cpu).disasmArm1B0, (*Cpu).disasmArm1B0, (*Cpu).disasmArm1B0,
-	(*Cpu).disasmArm1B0, (*Cpu).disasmArm1B0, (*Cpu).disasmArm1B0, (*Cpu).disasmArm1B0,
-	(*Cpu).disasmArm1B0, (*Cpu).disasmArm049, (*Cpu).disasmArm1B0, (*Cpu).disasmArm1BB,
-	(*Cpu).disasmArm1B0, (*Cpu).disasmArm1BD, (*Cpu).disasmArm1B0, (*Cpu).disasmArm1BF,
-	(*Cpu).disasmArm1C0, (*Cpu).disasmArm1C0, (*Cpu).disasmArm1C0, (*Cpu).disasmArm1C0,
-	(*Cpu).disasmArm1C0, (*Cpu).disasmArm1C0, (*Cpu).disasmArm1C0, (*Cpu).disasmArm1C0,
-	(*Cpu).disasmArm1C0, (*Cpu).disasmArm049, (*Cpu).disasmArm1C0, (*Cpu).disasmArm1CB,
-	(*Cpu).disasmArm1C0, (*Cpu).disasmArm1CD, (*Cpu).disasmArm1C0, (*Cpu).disasmArm1CF,
-	(*Cpu).disasmArm1D0, (*Cpu).disasmArm1D0, (*Cpu).disasmArm1D0, (*Cpu).disasmArm1D0,
-	(*Cpu).disasmArm1D0, (*Cpu).disasmArm1D0, (*Cpu).disasmArm1D0, (*Cpu).disasmArm1D0,
-	(*Cpu).disasmArm1D0, (*Cpu).disasmArm049, (*Cpu).disasmArm1D0, (*Cpu).disasmArm1DB,
-	(*Cpu).disasmArm1D0, (*Cpu).disasmArm1DD, (*Cpu).disasmArm1D0, (*Cpu).disasmArm1DF,
-	(*Cpu).disasmArm1E0, (*Cpu).disasmArm1E0, (*Cpu).disasmArm1E0, (*Cpu).disasmArm1E0,
-	(*Cpu).disasmArm1E0, (*Cpu).disasmArm1E0, (*Cpu).disasmArm1E0, (*Cpu).disasmArm1E0,
-	(*Cpu).disasmArm1E0, (*Cpu).disasmArm049, (*Cpu).disasmArm1E0, (*Cpu).disasmArm1EB,
-	(*Cpu).disasmArm1E0, (*Cpu).disasmArm1ED, (*Cpu).disasmArm1E0, (*Cpu).disasmArm1EF,
-	(*Cpu).disasmArm1F0, (*Cpu).disasmArm1F0, (*Cpu).disasmArm1F0, (*Cpu).disasmArm1F0,
-	(*Cpu).disasmArm1F0, (*Cpu).disasmArm1F0, (*Cpu).disasmArm1F0, (*Cpu).disasmArm1F0,
-	(*Cpu).disasmArm1F0, (*Cpu).disasmArm049, (*Cpu).disasmArm1F0, (*Cpu).disasmArm1FB,
-	(*Cpu).disasmArm1F0, (*Cpu).disasmArm1FD, (*Cpu).disasmArm1F0, (*Cpu).disasmArm1FF,
-	(*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200,
-	(*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200,
-	(*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200,
-	(*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200, (*Cpu).disasmArm200,
-	(*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210,
-	(*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210,
-	(*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210,
-	(*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210, (*Cpu).disasmArm210,
-	(*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220,
-	(*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220,
-	(*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220,
-	(*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220, (*Cpu).disasmArm220,
-	(*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230,
-	(*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230,
-	(*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230,
-	(*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230, (*Cpu).disasmArm230,
-	(*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240,
-	(*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240,
-	(*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240,
-	(*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240, (*Cpu).disasmArm240,
-	(*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250,
-	(*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250,
-	(*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250,
-	(*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250, (*Cpu).disasmArm250,
-	(*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260,
-	(*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260,
-	(*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260,
-	(*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260, (*Cpu).disasmArm260,
-	(*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270,
-	(*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270,
-	(*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270,
-	(*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270, (*Cpu).disasmArm270,
-	(*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280,
-	(*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280,
-	(*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280,
-	(*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280, (*Cpu).disasmArm280,
-	(*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290,
-	(*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290,
-	(*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290,
-	(*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290, (*Cpu).disasmArm290,
-	(*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0,
-	(*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0,
-	(*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0,
-	(*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0, (*Cpu).disasmArm2A0,
-	(*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0,
-	(*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0,
-	(*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0,
-	(*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0, (*Cpu).disasmArm2B0,
-	(*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0,
-	(*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0,
-	(*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0,
-	(*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0, (*Cpu).disasmArm2C0,
-	(*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0,
-	(*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0,
-	(*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0,
-	(*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0, (*Cpu).disasmArm2D0,
-	(*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0,
-	(*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0,
-	(*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0,
-	(*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0, (*Cpu).disasmArm2E0,
-	(*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0,
-	(*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0,
-	(*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0,
-	(*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0, (*Cpu).disasmArm2F0,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310,
-	(*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310,
-	(*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310,
-	(*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310, (*Cpu).disasmArm310,
-	(*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320,
-	(*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320,
-	(*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320,
-	(*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320, (*Cpu).disasmArm320,
-	(*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330,
-	(*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330,
-	(*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330,
-	(*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330, (*Cpu).disasmArm330,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350,
-	(*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350,
-	(*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350,
-	(*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350, (*Cpu).disasmArm350,
-	(*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360,
-	(*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360,
-	(*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360,
-	(*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360, (*Cpu).disasmArm360,
-	(*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370,
-	(*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370,
-	(*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370,
-	(*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370, (*Cpu).disasmArm370,
-	(*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380,
-	(*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380,
-	(*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380,
-	(*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380, (*Cpu).disasmArm380,
-	(*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390,
-	(*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390,
-	(*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390,
-	(*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390, (*Cpu).disasmArm390,
-	(*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0,
-	(*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0,
-	(*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0,
-	(*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0, (*Cpu).disasmArm3A0,
-	(*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0,
-	(*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0,
-	(*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0,
-	(*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0, (*Cpu).disasmArm3B0,
-	(*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0,
-	(*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0,
-	(*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0,
-	(*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0, (*Cpu).disasmArm3C0,
-	(*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0,
-	(*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0,
-	(*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0,
-	(*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0, (*Cpu).disasmArm3D0,
-	(*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0,
-	(*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0,
-	(*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0,
-	(*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0, (*Cpu).disasmArm3E0,
-	(*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0,
-	(*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0,
-	(*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0,
-	(*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0, (*Cpu).disasmArm3F0,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400, (*Cpu).disasmArm400,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410, (*Cpu).disasmArm410,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440, (*Cpu).disasmArm440,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450, (*Cpu).disasmArm450,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480, (*Cpu).disasmArm480,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490, (*Cpu).disasmArm490,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0, (*Cpu).disasmArm4C0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0, (*Cpu).disasmArm4D0,
-	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
-	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
-	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
-	(*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500, (*Cpu).disasmArm500,
-	(*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510,
-	(*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510,
-	(*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510,
-	(*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510, (*Cpu).disasmArm510,
-	(*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520,
-	(*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520,
-	(*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520,
-	(*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520, (*Cpu).disasmArm520,
-	(*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530,
-	(*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530,
-	(*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530,
-	(*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530, (*Cpu).disasmArm530,
-	(*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540,
-	(*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540,
-	(*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540,
-	(*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540, (*Cpu).disasmArm540,
-	(*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550,
-	(*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550,
-	(*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550,
-	(*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550, (*Cpu).disasmArm550,
-	(*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560,
-	(*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560,
-	(*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560,
-	(*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560, (*Cpu).disasmArm560,
-	(*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570,
-	(*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570,
-	(*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570,
-	(*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570, (*Cpu).disasmArm570,
-	(*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580,
-	(*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580,
-	(*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580,
-	(*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580, (*Cpu).disasmArm580,
-	(*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590,
-	(*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590,
-	(*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590,
-	(*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590, (*Cpu).disasmArm590,
-	(*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0,
-	(*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0,
-	(*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0,
-	(*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0, (*Cpu).disasmArm5A0,
-	(*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0,
-	(*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0,
-	(*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0,
-	(*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0, (*Cpu).disasmArm5B0,
-	(*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0,
-	(*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0,
-	(*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0,
-	(*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0, (*Cpu).disasmArm5C0,
-	(*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0,
-	(*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0,
-	(*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0,
-	(*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0, (*Cpu).disasmArm5D0,
-	(*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0,
-	(*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0,
-	(*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0,
-	(*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0, (*Cpu).disasmArm5E0,
-	(*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0,
-	(*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0,
-	(*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0,
-	(*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0, (*Cpu).disasmArm5F0,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm600, (*Cpu).disasmArm049, (*Cpu).disasmArm600, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm610, (*Cpu).disasmArm049, (*Cpu).disasmArm610, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm640, (*Cpu).disasmArm049, (*Cpu).disasmArm640, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm650, (*Cpu).disasmArm049, (*Cpu).disasmArm650, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm680, (*Cpu).disasmArm049, (*Cpu).disasmArm680, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm690, (*Cpu).disasmArm049, (*Cpu).disasmArm690, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6C0, (*Cpu).disasmArm049, (*Cpu).disasmArm6C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm6D0, (*Cpu).disasmArm049, (*Cpu).disasmArm6D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm700, (*Cpu).disasmArm049, (*Cpu).disasmArm700, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm710, (*Cpu).disasmArm049, (*Cpu).disasmArm710, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm710, (*Cpu).disasmArm049, (*Cpu).disasmArm710, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm710, (*Cpu).disasmArm049, (*Cpu).disasmArm710, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm710, (*Cpu).disasmArm049, (*Cpu).disasmArm710, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm720, (*Cpu).disasmArm049, (*Cpu).disasmArm720, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm720, (*Cpu).disasmArm049, (*Cpu).disasmArm720, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm720, (*Cpu).disasmArm049, (*Cpu).disasmArm720, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm720, (*Cpu).disasmArm049, (*Cpu).disasmArm720, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm730, (*Cpu).disasmArm049, (*Cpu).disasmArm730, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm730, (*Cpu).disasmArm049, (*Cpu).disasmArm730, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm730, (*Cpu).disasmArm049, (*Cpu).disasmArm730, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm730, (*Cpu).disasmArm049, (*Cpu).disasmArm730, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm740, (*Cpu).disasmArm049, (*Cpu).disasmArm740, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm740, (*Cpu).disasmArm049, (*Cpu).disasmArm740, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm740, (*Cpu).disasmArm049, (*Cpu).disasmArm740, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm740, (*Cpu).disasmArm049, (*Cpu).disasmArm740, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm750, (*Cpu).disasmArm049, (*Cpu).disasmArm750, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm750, (*Cpu).disasmArm049, (*Cpu).disasmArm750, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm750, (*Cpu).disasmArm049, (*Cpu).disasmArm750, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm750, (*Cpu).disasmArm049, (*Cpu).disasmArm750, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm760, (*Cpu).disasmArm049, (*Cpu).disasmArm760, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm760, (*Cpu).disasmArm049, (*Cpu).disasmArm760, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm760, (*Cpu).disasmArm049, (*Cpu).disasmArm760, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm760, (*Cpu).disasmArm049, (*Cpu).disasmArm760, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm770, (*Cpu).disasmArm049, (*Cpu).disasmArm770, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm770, (*Cpu).disasmArm049, (*Cpu).disasmArm770, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm770, (*Cpu).disasmArm049, (*Cpu).disasmArm770, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm770, (*Cpu).disasmArm049, (*Cpu).disasmArm770, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm780, (*Cpu).disasmArm049, (*Cpu).disasmArm780, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm780, (*Cpu).disasmArm049, (*Cpu).disasmArm780, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm780, (*Cpu).disasmArm049, (*Cpu).disasmArm780, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm780, (*Cpu).disasmArm049, (*Cpu).disasmArm780, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm790, (*Cpu).disasmArm049, (*Cpu).disasmArm790, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm790, (*Cpu).disasmArm049, (*Cpu).disasmArm790, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm790, (*Cpu).disasmArm049, (*Cpu).disasmArm790, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm790, (*Cpu).disasmArm049, (*Cpu).disasmArm790, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7A0, (*Cpu).disasmArm049, (*Cpu).disasmArm7A0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7A0, (*Cpu).disasmArm049, (*Cpu).disasmArm7A0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7A0, (*Cpu).disasmArm049, (*Cpu).disasmArm7A0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7A0, (*Cpu).disasmArm049, (*Cpu).disasmArm7A0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7B0, (*Cpu).disasmArm049, (*Cpu).disasmArm7B0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7B0, (*Cpu).disasmArm049, (*Cpu).disasmArm7B0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7B0, (*Cpu).disasmArm049, (*Cpu).disasmArm7B0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7B0, (*Cpu).disasmArm049, (*Cpu).disasmArm7B0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7C0, (*Cpu).disasmArm049, (*Cpu).disasmArm7C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7C0, (*Cpu).disasmArm049, (*Cpu).disasmArm7C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7C0, (*Cpu).disasmArm049, (*Cpu).disasmArm7C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7C0, (*Cpu).disasmArm049, (*Cpu).disasmArm7C0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7D0, (*Cpu).disasmArm049, (*Cpu).disasmArm7D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7D0, (*Cpu).disasmArm049, (*Cpu).disasmArm7D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7D0, (*Cpu).disasmArm049, (*Cpu).disasmArm7D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7D0, (*Cpu).disasmArm049, (*Cpu).disasmArm7D0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7E0, (*Cpu).disasmArm049, (*Cpu).disasmArm7E0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7E0, (*Cpu).disasmArm049, (*Cpu).disasmArm7E0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7E0, (*Cpu).disasmArm049, (*Cpu).disasmArm7E0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7E0, (*Cpu).disasmArm049, (*Cpu).disasmArm7E0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7F0, (*Cpu).disasmArm049, (*Cpu).disasmArm7F0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7F0, (*Cpu).disasmArm049, (*Cpu).disasmArm7F0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7F0, (*Cpu).disasmArm049, (*Cpu).disasmArm7F0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm7F0, (*Cpu).disasmArm049, (*Cpu).disasmArm7F0, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800, (*Cpu).disasmArm800,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810, (*Cpu).disasmArm810,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820, (*Cpu).disasmArm820,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830, (*Cpu).disasmArm830,
-	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
-	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
-	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
-	(*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840, (*Cpu).disasmArm840,
-	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
-	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
-	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
-	(*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850, (*Cpu).disasmArm850,
-	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
-	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
-	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
-	(*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860, (*Cpu).disasmArm860,
-	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
-	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
-	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
-	(*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870, (*Cpu).disasmArm870,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880, (*Cpu).disasmArm880,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890, (*Cpu).disasmArm890,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0, (*Cpu).disasmArm8A0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0, (*Cpu).disasmArm8B0,
-	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
-	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
-	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
-	(*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0, (*Cpu).disasmArm8C0,
-	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
-	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
-	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
-	(*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0, (*Cpu).disasmArm8D0,
-	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
-	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
-	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
-	(*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0, (*Cpu).disasmArm8E0,
-	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
-	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
-	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
-	(*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0, (*Cpu).disasmArm8F0,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900, (*Cpu).disasmArm900,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910, (*Cpu).disasmArm910,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920, (*Cpu).disasmArm920,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930, (*Cpu).disasmArm930,
-	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
-	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
-	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
-	(*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940, (*Cpu).disasmArm940,
-	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
-	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
-	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
-	(*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950, (*Cpu).disasmArm950,
-	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
-	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
-	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
-	(*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960, (*Cpu).disasmArm960,
-	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
-	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
-	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
-	(*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970, (*Cpu).disasmArm970,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980, (*Cpu).disasmArm980,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990, (*Cpu).disasmArm990,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0, (*Cpu).disasmArm9A0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0, (*Cpu).disasmArm9B0,
-	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
-	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
-	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
-	(*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0, (*Cpu).disasmArm9C0,
-	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
-	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
-	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
-	(*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0, (*Cpu).disasmArm9D0,
-	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
-	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
-	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
-	(*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0, (*Cpu).disasmArm9E0,
-	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
-	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
-	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
-	(*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0, (*Cpu).disasmArm9F0,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00, (*Cpu).disasmArmA00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00, (*Cpu).disasmArmB00,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049, (*Cpu).disasmArm049,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE01, (*Cpu).disasmArmE00, (*Cpu).disasmArmE01,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmE00, (*Cpu).disasmArmE11, (*Cpu).disasmArmE00, (*Cpu).disasmArmE11,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-	(*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00, (*Cpu).disasmArmF00,
-}
@@ -0,0 +1,81 @@
+package arm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Reference implementation of the condition codes
+func condReference(r *regCpsr, cond uint) bool {
+	switch cond {
+	case 0:
+		return r.Z
+	case 1:
+		return !r.Z
+	case 2:
+		return r.C
+	case 3:
+		return !r.C
+	case 4:
+		return r.N
+	case 5:
+		return !r.N
+	case 6:
+		return r.V
+	case 7:
+		return !r.V
+	case 8:
+		return r.C && !r.Z
+	case 9:
+		return !r.C || r.Z
+	case 10:
+		return r.N == r.V
+	case 11:
+		return r.N != r.V
+	case 12:
+		return !r.Z && r.N == r.V
+	case 13:
+		return r.Z || r.N != r.V
+	}
+	return true
+}
+
+func TestArmCond(t *testing.T) {
+	var cpu Cpu
+	for flags := 0; flags < 16; flags++ {
+		cpu.Cpsr.N, cpu.Cpsr.Z = flags&8 != 0, flags&4 != 0
+		cpu.Cpsr.C, cpu.Cpsr.V = flags&2 != 0, flags&1 != 0
+		for cond := uint(0); cond < 16; cond++ {
+			if got, exp := cpu.opArmCond(cond), condReference(&cpu.Cpsr, cond); got != exp {
+				t.Errorf("cond %x, flags %04b: got %v, want %v", cond, flags, got, exp)
+			}
+		}
+	}
+}
+
+func benchmarkCond(b *testing.B, eval func(*Cpu, uint) bool) {
+	// Random conditions and flags, so that branches are not predictable
+	var cpus [256]Cpu
+	var conds [256]uint
+	for i := range cpus {
+		f := rand.Intn(16)
+		cpus[i].Cpsr.N, cpus[i].Cpsr.Z = f&8 != 0, f&4 != 0
+		cpus[i].Cpsr.C, cpus[i].Cpsr.V = f&2 != 0, f&1 != 0
+		conds[i] = uint(rand.Intn(14))
+	}
+	b.ResetTimer()
+	n := 0
+	for i := 0; i < b.N; i++ {
+		if eval(&cpus[i&255], conds[i&255]) {
+			n++
+		}
+	}
+}
+
+func BenchmarkArmCond(b *testing.B) {
+	benchmarkCond(b, (*Cpu).opArmCond)
+}
+
+func BenchmarkArmCondSwitch(b *testing.B) {
+	benchmarkCond(b, func(cpu *Cpu, cond uint) bool { return condReference(&cpu.Cpsr, cond) })
+}
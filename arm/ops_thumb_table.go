@@ -1,9 +1,6 @@
-// Generated on 2017-06-18 01:20:56.902016754 +0200 CEST
+// Generated on 2026-10-16 14:23:47.807016971 +0000 UTC m=+0.000881221
 package arm
 
-import "bytes"
-import "strconv"
-
 var opThumbAluTable = [16]func(*Cpu, uint16){
 	(*Cpu).opThumbAlu00,
 	(*Cpu).opThumbAlu01,
@@ -22,24 +19,6 @@ var opThumbAluTable = [16]func(*Cpu, uint16){
 	(*Cpu).opThumbAlu0E,
 	(*Cpu).opThumbAlu0F,
 }
-var disasmThumbAluTable = [16]func(*Cpu, uint16, uint32) string{
-	(*Cpu).disasmThumbAlu00,
-	(*Cpu).disasmThumbAlu01,
-	(*Cpu).disasmThumbAlu02,
-	(*Cpu).disasmThumbAlu03,
-	(*Cpu).disasmThumbAlu04,
-	(*Cpu).disasmThumbAlu05,
-	(*Cpu).disasmThumbAlu06,
-	(*Cpu).disasmThumbAlu07,
-	(*Cpu).disasmThumbAlu08,
-	(*Cpu).disasmThumbAlu09,
-	(*Cpu).disasmThumbAlu0A,
-	(*Cpu).disasmThumbAlu0B,
-	(*Cpu).disasmThumbAlu0C,
-	(*Cpu).disasmThumbAlu0D,
-	(*Cpu).disasmThumbAlu0E,
-	(*Cpu).disasmThumbAlu0F,
-}
 
 func (cpu *Cpu) opThumb00(op uint16) {
 	// lsl
@@ -55,21 +34,6 @@ func (cpu *Cpu) opThumb00(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb00(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("lsl       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op >> 6) & 0x1F)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg2, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb08(op uint16) {
 	// lsr
 	rsx := (op >> 3) & 7
@@ -85,21 +49,6 @@ func (cpu *Cpu) opThumb08(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb08(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("lsr       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op >> 6) & 0x1F)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg2, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb10(op uint16) {
 	// asr
 	rsx := (op >> 3) & 7
@@ -115,21 +64,6 @@ func (cpu *Cpu) opThumb10(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb10(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("asr       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op >> 6) & 0x1F)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg2, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb18(op uint16) {
 	// add
 	rsx := (op >> 3) & 7
@@ -144,20 +78,6 @@ func (cpu *Cpu) opThumb18(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb18(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("add       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 6) & 7
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb1A(op uint16) {
 	// sub
 	rsx := (op >> 3) & 7
@@ -172,20 +92,6 @@ func (cpu *Cpu) opThumb1A(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb1A(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("sub       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := (op >> 6) & 7
-	out.WriteString(RegNames[arg2])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb1C(op uint16) {
 	// add #nn
 	rsx := (op >> 3) & 7
@@ -199,21 +105,6 @@ func (cpu *Cpu) opThumb1C(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb1C(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("add       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op >> 6) & 7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg2, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb1E(op uint16) {
 	// sub #nn
 	rsx := (op >> 3) & 7
@@ -227,21 +118,6 @@ func (cpu *Cpu) opThumb1E(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb1E(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("sub       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op >> 6) & 7)
-	out.WriteString("#")
-	out.WriteString(strconv.FormatInt(arg2, 10))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb20(op uint16) {
 	// mov
 	imm := uint32(op & 0xFF)
@@ -250,18 +126,6 @@ func (cpu *Cpu) opThumb20(op uint16) {
 	cpu.Regs[0] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb20(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("mov       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(op & 0xFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb21(op uint16) {
 	// mov
 	imm := uint32(op & 0xFF)
@@ -328,18 +192,6 @@ func (cpu *Cpu) opThumb28(op uint16) {
 	cpu.Cpsr.SetNZ(res)
 }
 
-func (cpu *Cpu) disasmThumb28(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("cmp       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(op & 0xFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb29(op uint16) {
 	// cmp
 	imm := uint32(op & 0xFF)
@@ -421,18 +273,6 @@ func (cpu *Cpu) opThumb30(op uint16) {
 	cpu.Regs[0] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb30(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("add       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(op & 0xFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb31(op uint16) {
 	// add
 	imm := uint32(op & 0xFF)
@@ -521,18 +361,6 @@ func (cpu *Cpu) opThumb38(op uint16) {
 	cpu.Regs[0] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumb38(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("sub       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := int64(op & 0xFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg1, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb39(op uint16) {
 	// sub
 	imm := uint32(op & 0xFF)
@@ -614,10 +442,6 @@ func (cpu *Cpu) opThumb40(op uint16) {
 	opThumbAluTable[(op>>6)&0xF](cpu, op)
 }
 
-func (cpu *Cpu) disasmThumb40(op uint16, pc uint32) string {
-	return disasmThumbAluTable[(op>>6)&0xF](cpu, op, pc)
-}
-
 func (cpu *Cpu) opThumb44(op uint16) {
 	// add(h)
 	rdx := (op & 7) | (op&0x80)>>4
@@ -630,17 +454,6 @@ func (cpu *Cpu) opThumb44(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumb44(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("add       ")
-	arg0 := (op & 7) | (op&0x80)>>4
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 3) & 0xF)
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb45(op uint16) {
 	// cmp(h)
 	rdx := (op & 7) | (op&0x80)>>4
@@ -653,17 +466,6 @@ func (cpu *Cpu) opThumb45(op uint16) {
 	cpu.Cpsr.SetVSub(rd, rs, res)
 }
 
-func (cpu *Cpu) disasmThumb45(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("cmp       ")
-	arg0 := (op & 7) | (op&0x80)>>4
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 3) & 0xF)
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb46(op uint16) {
 	// mov(h)
 	rdx := (op & 7) | (op&0x80)>>4
@@ -675,17 +477,6 @@ func (cpu *Cpu) opThumb46(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumb46(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("mov       ")
-	arg0 := (op & 7) | (op&0x80)>>4
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := ((op >> 3) & 0xF)
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb47(op uint16) {
 	// bx/blx
 	rdx := (op & 7) | (op&0x80)>>4
@@ -703,22 +494,6 @@ func (cpu *Cpu) opThumb47(op uint16) {
 	_ = rdx
 }
 
-func (cpu *Cpu) disasmThumb47(op uint16, pc uint32) string {
-	if op&0x80 != 0 {
-		var out bytes.Buffer
-		out.WriteString("blx       ")
-		arg0 := ((op >> 3) & 0xF)
-		out.WriteString(RegNames[arg0])
-		return out.String()
-	} else {
-		var out bytes.Buffer
-		out.WriteString("bx        ")
-		arg0 := ((op >> 3) & 0xF)
-		out.WriteString(RegNames[arg0])
-		return out.String()
-	}
-}
-
 func (cpu *Cpu) opThumb48(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
@@ -727,20 +502,6 @@ func (cpu *Cpu) opThumb48(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb48(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldr       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := uint32((op & 0xFF) * 4)
-	arg1 += uint32((pc + 4) &^ 2)
-	arg1v := cpu.Read32(arg1)
-	out.WriteString("= 0x")
-	out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb49(op uint16) {
 	// ldr pc
 	pc := uint32(cpu.Regs[15]) &^ 2
@@ -807,22 +568,6 @@ func (cpu *Cpu) opThumb50(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb50(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("str       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb52(op uint16) {
 	// strh
 	rox := (op >> 6) & 7
@@ -833,22 +578,6 @@ func (cpu *Cpu) opThumb52(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb52(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("strh      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb54(op uint16) {
 	// strb
 	rox := (op >> 6) & 7
@@ -859,22 +588,6 @@ func (cpu *Cpu) opThumb54(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb54(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("strb      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb56(op uint16) {
 	// ldsb
 	rox := (op >> 6) & 7
@@ -885,22 +598,6 @@ func (cpu *Cpu) opThumb56(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb56(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldsb      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb58(op uint16) {
 	// ldr
 	rox := (op >> 6) & 7
@@ -911,22 +608,6 @@ func (cpu *Cpu) opThumb58(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb58(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldr       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb5A(op uint16) {
 	// ldrh
 	rox := (op >> 6) & 7
@@ -937,22 +618,6 @@ func (cpu *Cpu) opThumb5A(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb5A(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldrh      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb5C(op uint16) {
 	// ldrb
 	rox := (op >> 6) & 7
@@ -963,22 +628,6 @@ func (cpu *Cpu) opThumb5C(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb5C(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldrb      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb5E(op uint16) {
 	// ldsh
 	rox := (op >> 6) & 7
@@ -989,22 +638,6 @@ func (cpu *Cpu) opThumb5E(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb5E(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldsh      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 7
-	arg1b := (op >> 6) & 7
-	out.WriteString("[")
-	out.WriteString(RegNames[arg1a])
-	out.WriteString(", ")
-	out.WriteString(RegNames[arg1b])
-	out.WriteString("]")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb60(op uint16) {
 	// str #nn
 	offset := uint32((op >> 6) & 0x1F)
@@ -1017,29 +650,6 @@ func (cpu *Cpu) opThumb60(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb60(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("str       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 0x7
-	arg1b := ((op >> 6) & 0x1F) * 4
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb68(op uint16) {
 	// ldr #nn
 	offset := uint32((op >> 6) & 0x1F)
@@ -1051,29 +661,6 @@ func (cpu *Cpu) opThumb68(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb68(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldr       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 0x7
-	arg1b := ((op >> 6) & 0x1F) * 4
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb70(op uint16) {
 	// strb #nn
 	offset := uint32((op >> 6) & 0x1F)
@@ -1085,29 +672,6 @@ func (cpu *Cpu) opThumb70(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb70(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("strb      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 0x7
-	arg1b := (op >> 6) & 0x1F
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb78(op uint16) {
 	// ldrb #nn
 	offset := uint32((op >> 6) & 0x1F)
@@ -1118,29 +682,6 @@ func (cpu *Cpu) opThumb78(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb78(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldrb      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 0x7
-	arg1b := (op >> 6) & 0x1F
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb80(op uint16) {
 	// strh #nn
 	offset := uint32((op >> 6) & 0x1F)
@@ -1153,29 +694,6 @@ func (cpu *Cpu) opThumb80(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb80(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("strh      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 0x7
-	arg1b := ((op >> 6) & 0x1F) * 2
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb88(op uint16) {
 	// ldrh #nn
 	offset := uint32((op >> 6) & 0x1F)
@@ -1187,29 +705,6 @@ func (cpu *Cpu) opThumb88(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb88(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldrh      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := (op >> 3) & 0x7
-	arg1b := ((op >> 6) & 0x1F) * 2
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb90(op uint16) {
 	// str [sp+nn]
 	offset := (op & 0xFF) * 4
@@ -1218,29 +713,6 @@ func (cpu *Cpu) opThumb90(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb90(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("str       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := 13
-	arg1b := (op & 0xFF) * 4
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb91(op uint16) {
 	// str [sp+nn]
 	offset := (op & 0xFF) * 4
@@ -1305,29 +777,6 @@ func (cpu *Cpu) opThumb98(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumb98(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldr       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1a := 13
-	arg1b := (op & 0xFF) * 4
-	if RegNames[arg1a] == "pc" && !false {
-		arg1c := uint32(arg1b) + uint32((pc+4)&^2)
-		arg1v := cpu.Read32(arg1c)
-		out.WriteString("= 0x")
-		out.WriteString(strconv.FormatInt(int64(arg1v), 16))
-	} else {
-		out.WriteString("[")
-		out.WriteString(RegNames[arg1a])
-		out.WriteString(", #0x")
-		out.WriteString(strconv.FormatInt(int64(arg1b), 16))
-		out.WriteString("]")
-	}
-	return out.String()
-}
-
 func (cpu *Cpu) opThumb99(op uint16) {
 	// ldr [sp+nn]
 	offset := (op & 0xFF) * 4
@@ -1390,21 +839,6 @@ func (cpu *Cpu) opThumbA0(op uint16) {
 	cpu.Regs[0] = (cpu.Regs[15] &^ 2) + reg(offset)
 }
 
-func (cpu *Cpu) disasmThumbA0(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("add       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := 15
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op & 0xFF) * 4)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbA1(op uint16) {
 	// add pc
 	offset := (op & 0xFF) * 4
@@ -1453,21 +887,6 @@ func (cpu *Cpu) opThumbA8(op uint16) {
 	cpu.Regs[0] = cpu.Regs[13] + reg(offset)
 }
 
-func (cpu *Cpu) disasmThumbA8(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("add       ")
-	arg0 := (op >> 8) & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := 13
-	out.WriteString(RegNames[arg1])
-	out.WriteString(", ")
-	arg2 := int64((op & 0xFF) * 4)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg2, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbA9(op uint16) {
 	// add sp
 	offset := (op & 0xFF) * 4
@@ -1520,38 +939,10 @@ func (cpu *Cpu) opThumbB0(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbB0(op uint16, pc uint32) string {
-	if op&0x80 == 0 {
-		var out bytes.Buffer
-		out.WriteString("add       ")
-		arg0 := 13
-		out.WriteString(RegNames[arg0])
-		out.WriteString(", ")
-		arg1 := int64((op & 0x7f) * 4)
-		out.WriteString("#0x")
-		out.WriteString(strconv.FormatInt(arg1, 16))
-		return out.String()
-	} else {
-		var out bytes.Buffer
-		out.WriteString("sub       ")
-		arg0 := 13
-		out.WriteString(RegNames[arg0])
-		out.WriteString(", ")
-		arg1 := int64((op & 0x7f) * 4)
-		out.WriteString("#0x")
-		out.WriteString(strconv.FormatInt(arg1, 16))
-		return out.String()
-	}
-}
-
 func (cpu *Cpu) opThumbB1(op uint16) {
 	cpu.InvalidOpThumb(op, "not implemented")
 }
 
-func (cpu *Cpu) disasmThumbB1(op uint16, pc uint32) string {
-	return "dw " + strconv.FormatInt(int64(op), 16)
-}
-
 func (cpu *Cpu) opThumbB4(op uint16) {
 	// push
 	count := popcount16(op & 0x1FF)
@@ -1597,46 +988,6 @@ func (cpu *Cpu) opThumbB4(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumbB4(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("push      ")
-	arg0 := op & 0xff
-	out.WriteString("{")
-	for i := 0; arg0 != 0; i++ {
-		if arg0&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg0 >>= 1
-			if arg0 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg0 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
-func (cpu *Cpu) disasmThumbB5(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("push      ")
-	arg0 := op&0xff | 0x4000
-	out.WriteString("{")
-	for i := 0; arg0 != 0; i++ {
-		if arg0&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg0 >>= 1
-			if arg0 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg0 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbBC(op uint16) {
 	// pop
 	sp := uint32(cpu.Regs[13])
@@ -1695,46 +1046,6 @@ func (cpu *Cpu) opThumbBC(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumbBC(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("pop       ")
-	arg0 := op & 0xff
-	out.WriteString("{")
-	for i := 0; arg0 != 0; i++ {
-		if arg0&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg0 >>= 1
-			if arg0 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg0 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
-func (cpu *Cpu) disasmThumbBD(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("pop       ")
-	arg0 := op&0xff | 0x8000
-	out.WriteString("{")
-	for i := 0; arg0 != 0; i++ {
-		if arg0&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg0 >>= 1
-			if arg0 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg0 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbC0(op uint16) {
 	// stm
 	if op&(1<<0) != 0 {
@@ -1794,33 +1105,6 @@ func (cpu *Cpu) opThumbC0(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumbC0(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("stm       ")
-	arg0r := (op >> 8) & 7
-	arg0w := (op >> ((op >> 8) & 7)) & 1
-	out.WriteString(RegNames[arg0r])
-	if arg0w != 0 {
-		out.WriteString("!")
-	}
-	out.WriteString(", ")
-	arg1 := op & 0xFF
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbC1(op uint16) {
 	// stm
 	if op&(1<<1) != 0 {
@@ -2290,33 +1574,6 @@ func (cpu *Cpu) opThumbC8(op uint16) {
 	cpu.Clock += 1
 }
 
-func (cpu *Cpu) disasmThumbC8(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ldm       ")
-	arg0r := (op >> 8) & 7
-	arg0w := (op >> ((op >> 8) & 7)) & 1
-	out.WriteString(RegNames[arg0r])
-	if arg0w != 0 {
-		out.WriteString("!")
-	}
-	out.WriteString(", ")
-	arg1 := op & 0xFF
-	out.WriteString("{")
-	for i := 0; arg1 != 0; i++ {
-		if arg1&1 != 0 {
-			out.WriteString(RegNames[i])
-			arg1 >>= 1
-			if arg1 != 0 {
-				out.WriteString(", ")
-			}
-		} else {
-			arg1 >>= 1
-		}
-	}
-	out.WriteString("}")
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbC9(op uint16) {
 	// ldm
 	ptr := uint32(cpu.Regs[1])
@@ -2718,15 +1975,6 @@ func (cpu *Cpu) opThumbD0(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD0(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("beq       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD1(op uint16) {
 	// bne
 	if !cpu.Cpsr.Z {
@@ -2736,15 +1984,6 @@ func (cpu *Cpu) opThumbD1(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD1(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bne       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD2(op uint16) {
 	// bhs
 	if cpu.Cpsr.C {
@@ -2754,15 +1993,6 @@ func (cpu *Cpu) opThumbD2(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD2(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bhs       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD3(op uint16) {
 	// blo
 	if !cpu.Cpsr.C {
@@ -2772,15 +2002,6 @@ func (cpu *Cpu) opThumbD3(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD3(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("blo       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD4(op uint16) {
 	// bmi
 	if cpu.Cpsr.N {
@@ -2790,15 +2011,6 @@ func (cpu *Cpu) opThumbD4(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD4(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bmi       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD5(op uint16) {
 	// bpl
 	if !cpu.Cpsr.N {
@@ -2808,15 +2020,6 @@ func (cpu *Cpu) opThumbD5(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD5(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bpl       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD6(op uint16) {
 	// bvs
 	if cpu.Cpsr.V {
@@ -2826,15 +2029,6 @@ func (cpu *Cpu) opThumbD6(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD6(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bvs       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD7(op uint16) {
 	// bvc
 	if !cpu.Cpsr.V {
@@ -2844,15 +2038,6 @@ func (cpu *Cpu) opThumbD7(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD7(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bvc       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD8(op uint16) {
 	// bhi
 	if cpu.Cpsr.C && !cpu.Cpsr.Z {
@@ -2862,15 +2047,6 @@ func (cpu *Cpu) opThumbD8(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD8(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bhi       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbD9(op uint16) {
 	// bls
 	if !cpu.Cpsr.C || cpu.Cpsr.Z {
@@ -2880,15 +2056,6 @@ func (cpu *Cpu) opThumbD9(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbD9(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bls       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbDA(op uint16) {
 	// bge
 	if cpu.Cpsr.N == cpu.Cpsr.V {
@@ -2898,15 +2065,6 @@ func (cpu *Cpu) opThumbDA(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbDA(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bge       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbDB(op uint16) {
 	// blt
 	if cpu.Cpsr.N != cpu.Cpsr.V {
@@ -2916,15 +2074,6 @@ func (cpu *Cpu) opThumbDB(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbDB(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("blt       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbDC(op uint16) {
 	// bgt
 	if !cpu.Cpsr.Z && cpu.Cpsr.N == cpu.Cpsr.V {
@@ -2934,15 +2083,6 @@ func (cpu *Cpu) opThumbDC(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbDC(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bgt       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbDD(op uint16) {
 	// ble
 	if cpu.Cpsr.Z || cpu.Cpsr.N != cpu.Cpsr.V {
@@ -2952,15 +2092,6 @@ func (cpu *Cpu) opThumbDD(op uint16) {
 	}
 }
 
-func (cpu *Cpu) disasmThumbDD(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ble       ")
-	arg0 := int32(int32(int8(uint8(op&0xFF))) * 2)
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbDE(op uint16) {
 	// b undef
 	cpu.InvalidOpThumb(op, "invalid F16 with opcode==14")
@@ -2971,30 +2102,12 @@ func (cpu *Cpu) opThumbDF(op uint16) {
 	cpu.Exception(ExceptionSwi)
 }
 
-func (cpu *Cpu) disasmThumbDF(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("swi       ")
-	arg0 := int64(op & 0xFF)
-	out.WriteString("#0x")
-	out.WriteString(strconv.FormatInt(arg0, 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbE0(op uint16) {
 	// b
 	pc := cpu.Regs[15] + reg(int32(int16(op<<5)>>4))
 	cpu.branch(pc, BranchJump)
 }
 
-func (cpu *Cpu) disasmThumbE0(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("b         ")
-	arg0 := int32(int32(int16(op<<5) >> 4))
-	arg0x := pc + 4 + uint32(arg0)
-	out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbE8(op uint16) {
 	// blx step 2
 	newpc := cpu.Regs[14] + reg((op&0x7FF)<<1)
@@ -3004,36 +2117,11 @@ func (cpu *Cpu) opThumbE8(op uint16) {
 	cpu.branch(newpc, BranchCall)
 }
 
-func (cpu *Cpu) disasmThumbE8(op uint16, pc uint32) string {
-	return "[continued]"
-}
-
 func (cpu *Cpu) opThumbF0(op uint16) {
 	// bl/blx step 1
 	cpu.Regs[14] = cpu.Regs[15] + reg(int32(uint32(op&0x7FF)<<21)>>9)
 }
 
-func (cpu *Cpu) disasmThumbF0(op uint16, pc uint32) string {
-	mem := cpu.opFetchPointer(pc + 2)
-	op2 := uint16(mem[0]) | uint16(mem[1])<<8
-	nextpc := (int32(uint32(op&0x7FF)<<21) >> 9) + int32((op2&0x7FF)<<1)
-	if (op2>>12)&1 == 0 {
-		var out bytes.Buffer
-		out.WriteString("blx       ")
-		arg0 := int32(nextpc)
-		arg0x := pc + 4 + uint32(arg0)
-		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-		return out.String()
-	} else {
-		var out bytes.Buffer
-		out.WriteString("bl        ")
-		arg0 := int32(nextpc)
-		arg0x := pc + 4 + uint32(arg0)
-		out.WriteString(strconv.FormatInt(int64(arg0x), 16))
-		return out.String()
-	}
-}
-
 func (cpu *Cpu) opThumbF8(op uint16) {
 	// bl step 2
 	newpc := cpu.Regs[14] + reg((op&0x7FF)<<1)
@@ -3052,17 +2140,6 @@ func (cpu *Cpu) opThumbAlu00(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu00(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("ands      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu01(op uint16) {
 	// eors
 	rsx := (op >> 3) & 0x7
@@ -3074,17 +2151,6 @@ func (cpu *Cpu) opThumbAlu01(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu01(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("eors      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu02(op uint16) {
 	// lsls
 	rsx := (op >> 3) & 0x7
@@ -3100,17 +2166,6 @@ func (cpu *Cpu) opThumbAlu02(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu02(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("lsls      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu03(op uint16) {
 	// lsrs
 	rsx := (op >> 3) & 0x7
@@ -3126,17 +2181,6 @@ func (cpu *Cpu) opThumbAlu03(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu03(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("lsrs      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu04(op uint16) {
 	// asrs
 	rsx := (op >> 3) & 0x7
@@ -3152,17 +2196,6 @@ func (cpu *Cpu) opThumbAlu04(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu04(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("asrs      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu05(op uint16) {
 	// adcs
 	rsx := (op >> 3) & 0x7
@@ -3182,17 +2215,6 @@ func (cpu *Cpu) opThumbAlu05(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu05(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("adcs      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu06(op uint16) {
 	// sbcs
 	rsx := (op >> 3) & 0x7
@@ -3212,17 +2234,6 @@ func (cpu *Cpu) opThumbAlu06(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu06(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("sbcs      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu07(op uint16) {
 	// rors
 	rsx := (op >> 3) & 0x7
@@ -3238,17 +2249,6 @@ func (cpu *Cpu) opThumbAlu07(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu07(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("rors      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu08(op uint16) {
 	// tst
 	rsx := (op >> 3) & 0x7
@@ -3259,17 +2259,6 @@ func (cpu *Cpu) opThumbAlu08(op uint16) {
 	cpu.Cpsr.SetNZ(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu08(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("tst       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu09(op uint16) {
 	// negs
 	rsx := (op >> 3) & 0x7
@@ -3282,17 +2271,6 @@ func (cpu *Cpu) opThumbAlu09(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu09(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("negs      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu0A(op uint16) {
 	// cmp
 	rsx := (op >> 3) & 0x7
@@ -3305,17 +2283,6 @@ func (cpu *Cpu) opThumbAlu0A(op uint16) {
 	cpu.Cpsr.SetNZ(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu0A(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("cmp       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu0B(op uint16) {
 	// cmn
 	rsx := (op >> 3) & 0x7
@@ -3328,17 +2295,6 @@ func (cpu *Cpu) opThumbAlu0B(op uint16) {
 	cpu.Cpsr.SetNZ(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu0B(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("cmn       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu0C(op uint16) {
 	// orrs
 	rsx := (op >> 3) & 0x7
@@ -3350,17 +2306,6 @@ func (cpu *Cpu) opThumbAlu0C(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu0C(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("orrs      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu0D(op uint16) {
 	// muls
 	rsx := (op >> 3) & 0x7
@@ -3375,17 +2320,6 @@ func (cpu *Cpu) opThumbAlu0D(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu0D(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("muls      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu0E(op uint16) {
 	// bics
 	rsx := (op >> 3) & 0x7
@@ -3397,17 +2331,6 @@ func (cpu *Cpu) opThumbAlu0E(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu0E(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("bics      ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 func (cpu *Cpu) opThumbAlu0F(op uint16) {
 	// mvn
 	rsx := (op >> 3) & 0x7
@@ -3418,17 +2341,6 @@ func (cpu *Cpu) opThumbAlu0F(op uint16) {
 	cpu.Regs[rdx] = reg(res)
 }
 
-func (cpu *Cpu) disasmThumbAlu0F(op uint16, pc uint32) string {
-	var out bytes.Buffer
-	out.WriteString("mvn       ")
-	arg0 := op & 7
-	out.WriteString(RegNames[arg0])
-	out.WriteString(", ")
-	arg1 := (op >> 3) & 7
-	out.WriteString(RegNames[arg1])
-	return out.String()
-}
-
 var opThumbTable = [256]func(*Cpu, uint16){
 	(*Cpu).opThumb00, (*Cpu).opThumb00, (*Cpu).opThumb00, (*Cpu).opThumb00,
 	(*Cpu).opThumb00, (*Cpu).opThumb00, (*Cpu).opThumb00, (*Cpu).opThumb00,
@@ -3495,69 +2407,3 @@ var opThumbTable = [256]func(*Cpu, uint16){
 	(*Cpu).opThumbF8, (*Cpu).opThumbF8, (*Cpu).opThumbF8, (*Cpu).opThumbF8,
 	(*Cpu).opThumbF8, (*Cpu).opThumbF8, (*Cpu).opThumbF8, (*Cpu).opThumbF8,
 }
-var disasmThumbTable = [256]func(*Cpu, uint16, uint32) string{
-	(*Cpu).disasmThumb00, (*Cpu).disasmThumb00, (*Cpu).disasmThumb00, (*Cpu).disasmThumb00,
-	(*Cpu).disasmThumb00, (*Cpu).disasmThumb00, (*Cpu).disasmThumb00, (*Cpu).disasmThumb00,
-	(*Cpu).disasmThumb08, (*Cpu).disasmThumb08, (*Cpu).disasmThumb08, (*Cpu).disasmThumb08,
-	(*Cpu).disasmThumb08, (*Cpu).disasmThumb08, (*Cpu).disasmThumb08, (*Cpu).disasmThumb08,
-	(*Cpu).disasmThumb10, (*Cpu).disasmThumb10, (*Cpu).disasmThumb10, (*Cpu).disasmThumb10,
-	(*Cpu).disasmThumb10, (*Cpu).disasmThumb10, (*Cpu).disasmThumb10, (*Cpu).disasmThumb10,
-	(*Cpu).disasmThumb18, (*Cpu).disasmThumb18, (*Cpu).disasmThumb1A, (*Cpu).disasmThumb1A,
-	(*Cpu).disasmThumb1C, (*Cpu).disasmThumb1C, (*Cpu).disasmThumb1E, (*Cpu).disasmThumb1E,
-	(*Cpu).disasmThumb20, (*Cpu).disasmThumb20, (*Cpu).disasmThumb20, (*Cpu).disasmThumb20,
-	(*Cpu).disasmThumb20, (*Cpu).disasmThumb20, (*Cpu).disasmThumb20, (*Cpu).disasmThumb20,
-	(*Cpu).disasmThumb28, (*Cpu).disasmThumb28, (*Cpu).disasmThumb28, (*Cpu).disasmThumb28,
-	(*Cpu).disasmThumb28, (*Cpu).disasmThumb28, (*Cpu).disasmThumb28, (*Cpu).disasmThumb28,
-	(*Cpu).disasmThumb30, (*Cpu).disasmThumb30, (*Cpu).disasmThumb30, (*Cpu).disasmThumb30,
-	(*Cpu).disasmThumb30, (*Cpu).disasmThumb30, (*Cpu).disasmThumb30, (*Cpu).disasmThumb30,
-	(*Cpu).disasmThumb38, (*Cpu).disasmThumb38, (*Cpu).disasmThumb38, (*Cpu).disasmThumb38,
-	(*Cpu).disasmThumb38, (*Cpu).disasmThumb38, (*Cpu).disasmThumb38, (*Cpu).disasmThumb38,
-	(*Cpu).disasmThumb40, (*Cpu).disasmThumb40, (*Cpu).disasmThumb40, (*Cpu).disasmThumb40,
-	(*Cpu).disasmThumb44, (*Cpu).disasmThumb45, (*Cpu).disasmThumb46, (*Cpu).disasmThumb47,
-	(*Cpu).disasmThumb48, (*Cpu).disasmThumb48, (*Cpu).disasmThumb48, (*Cpu).disasmThumb48,
-	(*Cpu).disasmThumb48, (*Cpu).disasmThumb48, (*Cpu).disasmThumb48, (*Cpu).disasmThumb48,
-	(*Cpu).disasmThumb50, (*Cpu).disasmThumb50, (*Cpu).disasmThumb52, (*Cpu).disasmThumb52,
-	(*Cpu).disasmThumb54, (*Cpu).disasmThumb54, (*Cpu).disasmThumb56, (*Cpu).disasmThumb56,
-	(*Cpu).disasmThumb58, (*Cpu).disasmThumb58, (*Cpu).disasmThumb5A, (*Cpu).disasmThumb5A,
-	(*Cpu).disasmThumb5C, (*Cpu).disasmThumb5C, (*Cpu).disasmThumb5E, (*Cpu).disasmThumb5E,
-	(*Cpu).disasmThumb60, (*Cpu).disasmThumb60, (*Cpu).disasmThumb60, (*Cpu).disasmThumb60,
-	(*Cpu).disasmThumb60, (*Cpu).disasmThumb60, (*Cpu).disasmThumb60, (*Cpu).disasmThumb60,
-	(*Cpu).disasmThumb68, (*Cpu).disasmThumb68, (*Cpu).disasmThumb68, (*Cpu).disasmThumb68,
-	(*Cpu).disasmThumb68, (*Cpu).disasmThumb68, (*Cpu).disasmThumb68, (*Cpu).disasmThumb68,
-	(*Cpu).disasmThumb70, (*Cpu).disasmThumb70, (*Cpu).disasmThumb70, (*Cpu).disasmThumb70,
-	(*Cpu).disasmThumb70, (*Cpu).disasmThumb70, (*Cpu).disasmThumb70, (*Cpu).disasmThumb70,
-	(*Cpu).disasmThumb78, (*Cpu).disasmThumb78, (*Cpu).disasmThumb78, (*Cpu).disasmThumb78,
-	(*Cpu).disasmThumb78, (*Cpu).disasmThumb78, (*Cpu).disasmThumb78, (*Cpu).disasmThumb78,
-	(*Cpu).disasmThumb80, (*Cpu).disasmThumb80, (*Cpu).disasmThumb80, (*Cpu).disasmThumb80,
-	(*Cpu).disasmThumb80, (*Cpu).disasmThumb80, (*Cpu).disasmThumb80, (*Cpu).disasmThumb80,
-	(*Cpu).disasmThumb88, (*Cpu).disasmThumb88, (*Cpu).disasmThumb88, (*Cpu).disasmThumb88,
-	(*Cpu).disasmThumb88, (*Cpu).disasmThumb88, (*Cpu).disasmThumb88, (*Cpu).disasmThumb88,
-	(*Cpu).disasmThumb90, (*Cpu).disasmThumb90, (*Cpu).disasmThumb90, (*Cpu).disasmThumb90,
-	(*Cpu).disasmThumb90, (*Cpu).disasmThumb90, (*Cpu).disasmThumb90, (*Cpu).disasmThumb90,
-	(*Cpu).disasmThumb98, (*Cpu).disasmThumb98, (*Cpu).disasmThumb98, (*Cpu).disasmThumb98,
-	(*Cpu).disasmThumb98, (*Cpu).disasmThumb98, (*Cpu).disasmThumb98, (*Cpu).disasmThumb98,
-	(*Cpu).disasmThumbA0, (*Cpu).disasmThumbA0, (*Cpu).disasmThumbA0, (*Cpu).disasmThumbA0,
-	(*Cpu).disasmThumbA0, (*Cpu).disasmThumbA0, (*Cpu).disasmThumbA0, (*Cpu).disasmThumbA0,
-	(*Cpu).disasmThumbA8, (*Cpu).disasmThumbA8, (*Cpu).disasmThumbA8, (*Cpu).disasmThumbA8,
-	(*Cpu).disasmThumbA8, (*Cpu).disasmThumbA8, (*Cpu).disasmThumbA8, (*Cpu).disasmThumbA8,
-	(*Cpu).disasmThumbB0, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
-	(*Cpu).disasmThumbB4, (*Cpu).disasmThumbB5, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
-	(*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
-	(*Cpu).disasmThumbBC, (*Cpu).disasmThumbBD, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbB1,
-	(*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0,
-	(*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0, (*Cpu).disasmThumbC0,
-	(*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8,
-	(*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8, (*Cpu).disasmThumbC8,
-	(*Cpu).disasmThumbD0, (*Cpu).disasmThumbD1, (*Cpu).disasmThumbD2, (*Cpu).disasmThumbD3,
-	(*Cpu).disasmThumbD4, (*Cpu).disasmThumbD5, (*Cpu).disasmThumbD6, (*Cpu).disasmThumbD7,
-	(*Cpu).disasmThumbD8, (*Cpu).disasmThumbD9, (*Cpu).disasmThumbDA, (*Cpu).disasmThumbDB,
-	(*Cpu).disasmThumbDC, (*Cpu).disasmThumbDD, (*Cpu).disasmThumbB1, (*Cpu).disasmThumbDF,
-	(*Cpu).disasmThumbE0, (*Cpu).disasmThumbE0, (*Cpu).disasmThumbE0, (*Cpu).disasmThumbE0,
-	(*Cpu).disasmThumbE0, (*Cpu).disasmThumbE0, (*Cpu).disasmThumbE0, (*Cpu).disasmThumbE0,
-	(*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8,
-	(*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8,
-	(*Cpu).disasmThumbF0, (*Cpu).disasmThumbF0, (*Cpu).disasmThumbF0, (*Cpu).disasmThumbF0,
-	(*Cpu).disasmThumbF0, (*Cpu).disasmThumbF0, (*Cpu).disasmThumbF0, (*Cpu).disasmThumbF0,
-	(*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8,
-	(*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8, (*Cpu).disasmThumbE8,
-}
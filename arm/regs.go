@@ -65,6 +65,14 @@ func (r *regCpsr) SetVSub(s1, s2, res uint32) {
 	r.V = (s1^s2)&(s1^res)&0x80000000 != 0
 }
 
+// NZCV returns the condition flags packed in 4 bits (N is bit 3, V is bit 0).
+// The flags are stored as separate bools, so that opcodes can update them
+// without repacking the CPSR.
+func (r *regCpsr) NZCV() uint {
+	return uint(boolToReg(r.N))<<3 | uint(boolToReg(r.Z))<<2 |
+		uint(boolToReg(r.C))<<1 | uint(boolToReg(r.V))
+}
+
 func (r *regCpsr) GetMode() CpuMode {
 	return CpuMode(r._mode)
 }
@@ -103,6 +111,9 @@ func (r *regCpsr) Set(val uint32, cpu *Cpu) {
 
 	r._t = val&(1<<5) != 0
 	r._f = val&(1<<6) != 0
+	if r._i && val&(1<<7) == 0 {
+		cpu.irqUnmasked()
+	}
 	r._i = val&(1<<7) != 0
 	r.Q = val&(1<<27) != 0
 	r.V = val&(1<<28) != 0
@@ -128,6 +139,9 @@ func (r *regCpsr) SetT(val bool, cpu *Cpu) {
 }
 
 func (r *regCpsr) SetI(val bool, cpu *Cpu) {
+	if r._i && !val {
+		cpu.irqUnmasked()
+	}
 	r._i = val
 	cpu.tightExit = true
 }
@@ -0,0 +1,162 @@
+package arm
+
+// CpuState is a snapshot of the state of the CPU, as stored in savestates.
+// It covers the same state as HashState: the registers of all modes, the
+// clock, the status of the input lines, the HLE SWI calls still in progress,
+// and the CP15 configuration together with the contents of the TCMs.
+type CpuState struct {
+	Regs     [16]uint32
+	Cpsr     uint32
+	UsrBank  [2]uint32
+	FiqBank  [2]uint32
+	SvcBank  [2]uint32
+	AbtBank  [2]uint32
+	IrqBank  [2]uint32
+	UndBank  [2]uint32
+	SpsrBank [5]uint32
+	UsrBank2 [5]uint32
+	FiqBank2 [5]uint32
+	Pc       uint32
+	Clock    int64
+	Lines    uint32
+	IrqAt    int64
+
+	SwiPending []SwiHleState // HLE SWI calls in progress (see chargeSwiHle)
+
+	Cp15 *Cp15State // nil if the CPU has no CP15
+}
+
+// Cp15State is the state of CP15, within CpuState
+type Cp15State struct {
+	Control    uint32
+	DtcmVsize  uint32
+	ItcmVsize  uint32
+	AccessPerm uint32
+	Itcm       []byte
+	Dtcm       []byte
+}
+
+// SwiHleState is a HLE SWI call whose duration has not been fully accounted
+// yet, within CpuState. Its effects have already been applied, so it must be
+// restored to avoid running the call again when the CPU re-executes the SWI
+// opcode.
+type SwiHleState struct {
+	Pc        uint32
+	Sp        uint32
+	Mode      uint8
+	Remaining int64
+}
+
+func regsToUint32(dst []uint32, src []reg) {
+	for i := range src {
+		dst[i] = uint32(src[i])
+	}
+}
+
+func regsFromUint32(dst []reg, src []uint32) {
+	for i := range src {
+		dst[i] = reg(src[i])
+	}
+}
+
+// State returns a snapshot of the state of the CPU
+func (cpu *Cpu) State() *CpuState {
+	st := &CpuState{
+		Cpsr:  cpu.Cpsr.Uint32(),
+		Pc:    uint32(cpu.pc),
+		Clock: cpu.Clock,
+		Lines: uint32(cpu.lines),
+		IrqAt: cpu.irqAt,
+	}
+	regsToUint32(st.Regs[:], cpu.Regs[:])
+	regsToUint32(st.UsrBank[:], cpu.UsrBank[:])
+	regsToUint32(st.FiqBank[:], cpu.FiqBank[:])
+	regsToUint32(st.SvcBank[:], cpu.SvcBank[:])
+	regsToUint32(st.AbtBank[:], cpu.AbtBank[:])
+	regsToUint32(st.IrqBank[:], cpu.IrqBank[:])
+	regsToUint32(st.UndBank[:], cpu.UndBank[:])
+	regsToUint32(st.SpsrBank[:], cpu.SpsrBank[:])
+	regsToUint32(st.UsrBank2[:], cpu.UsrBank2[:])
+	regsToUint32(st.FiqBank2[:], cpu.FiqBank2[:])
+	for _, call := range cpu.swiPending {
+		st.SwiPending = append(st.SwiPending, SwiHleState{
+			Pc:        uint32(call.pc),
+			Sp:        uint32(call.sp),
+			Mode:      uint8(call.mode),
+			Remaining: call.remaining,
+		})
+	}
+
+	if c := cpu.cp15; c != nil {
+		st.Cp15 = &Cp15State{
+			Control:    uint32(c.regControl),
+			DtcmVsize:  uint32(c.regDtcmVsize),
+			ItcmVsize:  uint32(c.regItcmVsize),
+			AccessPerm: c.accessPerm,
+			Itcm:       append([]byte(nil), c.itcm...),
+			Dtcm:       append([]byte(nil), c.dtcm...),
+		}
+	}
+	return st
+}
+
+// SetState restores a snapshot of the state of the CPU (see State). The
+// CPU must have the same configuration (CP15 and TCM sizes) as the one the
+// snapshot was taken from. Code compiled by the JIT is discarded.
+func (cpu *Cpu) SetState(st *CpuState) {
+	// Restore CPSR without switching register banks, as all the banks
+	// are restored below as they were
+	cpu.Cpsr = regCpsr{
+		N:     st.Cpsr&(1<<31) != 0,
+		Z:     st.Cpsr&(1<<30) != 0,
+		C:     st.Cpsr&(1<<29) != 0,
+		V:     st.Cpsr&(1<<28) != 0,
+		Q:     st.Cpsr&(1<<27) != 0,
+		_i:    st.Cpsr&(1<<7) != 0,
+		_f:    st.Cpsr&(1<<6) != 0,
+		_t:    st.Cpsr&(1<<5) != 0,
+		_mode: uint8(st.Cpsr & 0x1F),
+	}
+	regsFromUint32(cpu.Regs[:], st.Regs[:])
+	regsFromUint32(cpu.UsrBank[:], st.UsrBank[:])
+	regsFromUint32(cpu.FiqBank[:], st.FiqBank[:])
+	regsFromUint32(cpu.SvcBank[:], st.SvcBank[:])
+	regsFromUint32(cpu.AbtBank[:], st.AbtBank[:])
+	regsFromUint32(cpu.IrqBank[:], st.IrqBank[:])
+	regsFromUint32(cpu.UndBank[:], st.UndBank[:])
+	regsFromUint32(cpu.SpsrBank[:], st.SpsrBank[:])
+	regsFromUint32(cpu.UsrBank2[:], st.UsrBank2[:])
+	regsFromUint32(cpu.FiqBank2[:], st.FiqBank2[:])
+	cpu.pc = reg(st.Pc)
+	cpu.prevpc = cpu.pc
+	cpu.Clock = st.Clock
+	cpu.targetCycles = st.Clock
+	cpu.runTarget = st.Clock
+	cpu.lines = Line(st.Lines)
+	cpu.irqAt = st.IrqAt
+	cpu.swiPending = nil
+	for _, call := range st.SwiPending {
+		cpu.swiPending = append(cpu.swiPending, swiHleCall{
+			pc:        reg(call.Pc),
+			sp:        reg(call.Sp),
+			mode:      CpuMode(call.Mode),
+			remaining: call.Remaining,
+		})
+	}
+	cpu.fetchNonSeq = true
+	cpu.tightExit = true
+
+	if c := cpu.cp15; c != nil && st.Cp15 != nil {
+		c.regControl = reg(st.Cp15.Control)
+		c.regDtcmVsize = reg(st.Cp15.DtcmVsize)
+		c.regItcmVsize = reg(st.Cp15.ItcmVsize)
+		c.accessPerm = st.Cp15.AccessPerm
+		copy(c.itcm, st.Cp15.Itcm)
+		copy(c.dtcm, st.Cp15.Dtcm)
+		c.updateTcmConfig()
+	}
+
+	if cpu.jit != nil {
+		cpu.jit.InvalidateAll()
+	}
+}
@@ -0,0 +1,41 @@
+package arm
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// HashState writes the state of the CPU (the registers of all modes, the
+// clock, the status of the input lines, the HLE SWI calls in progress, and the
+// CP15 configuration together with the contents of the TCMs) to w, in a stable binary format. It is meant
+// to be fed to a hash function, to check whether two runs of the emulation
+// reached the same state; internal state that is derived from the above or
+// doesn't affect the emulation (like the JIT caches) is not included.
+func (cpu *Cpu) HashState(w io.Writer) {
+	le := binary.LittleEndian
+	binary.Write(w, le, cpu.Regs)
+	binary.Write(w, le, cpu.Cpsr.Uint32())
+	for _, bank := range [][]reg{
+		cpu.UsrBank[:], cpu.FiqBank[:], cpu.SvcBank[:], cpu.AbtBank[:],
+		cpu.IrqBank[:], cpu.UndBank[:], cpu.SpsrBank[:],
+		cpu.UsrBank2[:], cpu.FiqBank2[:],
+	} {
+		binary.Write(w, le, bank)
+	}
+	binary.Write(w, le, cpu.pc)
+	binary.Write(w, le, cpu.Clock)
+	binary.Write(w, le, uint32(cpu.lines))
+	binary.Write(w, le, cpu.irqAt)
+	binary.Write(w, le, uint32(len(cpu.swiPending)))
+	for _, call := range cpu.swiPending {
+		binary.Write(w, le, [3]uint32{uint32(call.pc), uint32(call.sp), uint32(call.mode)})
+		binary.Write(w, le, call.remaining)
+	}
+
+	if c := cpu.cp15; c != nil {
+		binary.Write(w, le, [3]reg{c.regControl, c.regDtcmVsize, c.regItcmVsize})
+		binary.Write(w, le, c.accessPerm)
+		w.Write(c.itcm)
+		w.Write(c.dtcm)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	log "ndsemu/emu/logger"
+	"strings"
+)
+
+var modHle7 = log.NewModule("hle7")
+
+// Tags of the PXI protocol, used by the official SDK to route IPC FIFO
+// messages to the subsystems running on the other CPU. Each message is a
+// 32-bit word: bits 0-4 are the tag, bit 5 is an error flag, and bits 6-31
+// are the payload.
+var pxiTagNames = [...]string{
+	"ex", "user0", "user1", "system", "nvram", "rtc", "touchpanel", "sound",
+	"pm", "mic", "wm", "fs", "os", "ctrdg", "card", "wvr", "ctrdg-ex", "ctrdg-phi",
+}
+
+const (
+	pxiTagRtc        = 5
+	pxiTagTouchPanel = 6
+	pxiTagSound      = 7
+	pxiTagPm         = 8
+)
+
+// Offsets in main RAM of the fields of the system work area (at 0x027FFC00)
+// that are shared between the SDK libraries running on the two CPUs.
+const (
+	cSysWorkRtc        = 0x3FFDE8 // real_time_clock: last date and time read
+	cSysWorkPxiHandler = 0x3FFF8C // pxiHandleChecker[ARM7]: tags with a handler
+	cSysWorkButtonXY   = 0x3FFFA8 // button_XY: buttons read from EXTKEYIN
+	cSysWorkTouchPanel = 0x3FFFAA // touch_panel: last touchscreen sample
+)
+
+// Reply codes of the RTC and SPI (touch panel, power management) services.
+// Replies echo the command in bits 8-14, with bit 15 set.
+const (
+	pxiResultSuccess        = 0
+	pxiResultInvalidCommand = 1
+)
+
+// Commands of the SPI services. Requests are split in packets: bit 25 of
+// the payload marks the first packet, bit 24 the last one, and bits 16-19
+// are the packet index; the command is in bits 8-15 of the first packet.
+const (
+	spiCmdTpSampling   = 0x00
+	spiCmdTpAutoOn     = 0x01
+	spiCmdTpAutoOff    = 0x02
+	spiCmdTpStability  = 0x03
+	spiCmdTpAutoSample = 0x10 // notification sent for each auto sample
+)
+
+// Sound commands are not sent through the FIFO: the payload is the address
+// of a list of 24-byte SNDCommand structures in main RAM ({next, id,
+// arg[4]}). The ARM9 knows that a list was processed when the ARM7 increments
+// the counter at the beginning of the shared work area.
+const (
+	sndCmdSharedWork = 29
+	sndCmdSize       = 24
+	sndCmdMaxList    = 1024 // guard against corrupted (looping) lists
+)
+
+func pxiTagName(tag uint32) string {
+	if tag < uint32(len(pxiTagNames)) {
+		return pxiTagNames[tag]
+	}
+	return fmt.Sprintf("tag%d", tag)
+}
+
+// Arm7Hle is an EXPERIMENTAL high-level emulation of the ARM7 side of games
+// built with the official SDK. When enabled, the ARM7 CPU is not emulated
+// at all: IPC messages sent by the ARM9 are consumed by the HLE, which speaks
+// the PXI protocol in place of the ARM7 SDK libraries.
+//
+// The IPCSYNC handshake is acknowledged, and FIFO messages are decoded by
+// PXI tag and dispatched to these services:
+//
+//   - RTC: date and time are read from the emulated RTC into the system
+//     work area; writes are acknowledged but ignored, as the RTC follows the
+//     host clock.
+//   - Touch panel: samples are taken from the touchscreen, both on request
+//     and (in auto-sampling mode) at each VBlank. The X/Y buttons and the
+//     hinge are also published at each VBlank, as the SPI library does.
+//   - Power management: commands are acknowledged, with no effect.
+//   - Sound: command lists are walked and marked as processed, but nothing
+//     is played (there is no sequencer), so games are silent.
+//
+// Requests for other tags (NVRAM, microphone, wireless, cartridge and card
+// access...) are bounced back to the ARM9 with the error flag set, which is
+// what the PXI library does for tags without a registered handler, and
+// logged (once per tag). Games that need those services to boot still hang.
+// Since the ARM7 also takes care of loading the game from the cartridge,
+// this mode requires direct boot.
+type Arm7Hle struct {
+	ipc *HwIpc
+	rtc *HwRtc
+	tsc *HwTouchScreen
+	key *HwKey
+	ram []byte
+
+	pending map[uint32]bool // tags of the unhandled requests already reported
+	sndCmds map[uint32]bool // unimplemented sound commands already reported
+	spiBuf  [3][]uint16     // packets of the pending SPI requests, per tag
+	tpAuto  bool            // touch panel auto-sampling enabled
+	sndWork uint32          // address of the sound shared work area (0: none)
+}
+
+func NewArm7Hle(hw *NDSHardware, ram []byte) *Arm7Hle {
+	h := &Arm7Hle{
+		ipc:     hw.Ipc,
+		rtc:     hw.Rtc,
+		tsc:     hw.Tsc,
+		key:     hw.Key,
+		ram:     ram,
+		pending: make(map[uint32]bool),
+		sndCmds: make(map[uint32]bool),
+	}
+	// Enable the ARM7 FIFO, and register the handlers, as the SDK would do
+	// during initialization
+	h.ipc.writeIPCFIFOCNT(CpuNds7, 1<<15|1<<14|1<<3)
+	h.registerHandlers()
+	// The SDK switches the RTC to 24-hour mode, and expects hours in it
+	h.rtc.regStatus1 |= 2
+	return h
+}
+
+// registerHandlers marks the tags of the implemented services as handled in
+// the system work area, which the ARM9 polls before sending requests
+func (h *Arm7Hle) registerHandlers() {
+	mask := binary.LittleEndian.Uint32(h.ram[cSysWorkPxiHandler:])
+	mask |= 1<<pxiTagRtc | 1<<pxiTagTouchPanel | 1<<pxiTagSound | 1<<pxiTagPm
+	binary.LittleEndian.PutUint32(h.ram[cSysWorkPxiHandler:], mask)
+}
+
+// VBlank must be called at the beginning of each VBlank, where the SDK runs
+// the periodic tasks of the ARM7
+func (h *Arm7Hle) VBlank() {
+	// The ARM9 can clear the system work area (eg: during OS_Init)
+	h.registerHandlers()
+
+	xy := h.key.ReadEXTKEYIN(h.key.ExtKeyIn.Value)
+	binary.LittleEndian.PutUint16(h.ram[cSysWorkButtonXY:], xy)
+
+	if h.tpAuto {
+		h.sampleTouchPanel()
+		h.reply(pxiTagTouchPanel, 1<<15|spiCmdTpAutoSample<<8|pxiResultSuccess)
+	}
+}
+
+func (h *Arm7Hle) reply(tag uint32, data uint32) {
+	h.ipc.writeIPCFIFOSEND(CpuNds7, data<<6|tag)
+}
+
+// IpcSync is called when the ARM9 writes a new value into its IPCSYNC
+// output. The SDK uses it for handshakes, where each CPU waits for the other
+// to acknowledge a value, so the value is echoed back.
+func (h *Arm7Hle) IpcSync(val uint8) {
+	h.ipc.WriteIPC7SYNC(0, uint16(val&0xF)<<8)
+}
+
+// IpcRecv is called for each word sent by the ARM9 through the IPC FIFO
+func (h *Arm7Hle) IpcRecv(val uint32) {
+	tag, errf, data := val&0x1F, val&0x20 != 0, val>>6
+	if !errf {
+		switch tag {
+		case pxiTagRtc:
+			h.rtcRequest(data)
+			return
+		case pxiTagTouchPanel, pxiTagPm:
+			h.spiPacket(tag, data)
+			return
+		case pxiTagSound:
+			h.soundRequest(data)
+			return
+		}
+		// Report the request as unhandled
+		h.ipc.writeIPCFIFOSEND(CpuNds7, val|0x20)
+	}
+	if !h.pending[tag] {
+		h.pending[tag] = true
+		modHle7.WarnZ("unimplemented PXI request").
+			String("tag", pxiTagName(tag)).
+			Bool("err", errf).
+			Hex32("data", data).
+			End()
+	}
+}
+
+func (h *Arm7Hle) rtcRequest(data uint32) {
+	cmd := (data >> 8) & 0x7F
+	result := uint32(pxiResultSuccess)
+	switch {
+	case cmd == 0x00 || cmd == 0x01: // reset, set hour format
+	case cmd >= 0x10 && cmd <= 0x12: // read date and time, date, time
+		dt := h.rtc.DateTime()
+		buf := h.ram[cSysWorkRtc : cSysWorkRtc+8]
+		if cmd != 0x12 {
+			copy(buf[0:4], dt[0:4])
+		}
+		if cmd != 0x11 {
+			copy(buf[4:7], dt[4:7])
+		}
+	case cmd >= 0x20 && cmd <= 0x2F: // writes
+		modHle7.InfoZ("RTC write ignored").Hex8("cmd", uint8(cmd)).End()
+	default:
+		modHle7.WarnZ("unimplemented RTC command").Hex8("cmd", uint8(cmd)).End()
+		result = pxiResultInvalidCommand
+	}
+	h.reply(pxiTagRtc, 1<<15|cmd<<8|result)
+}
+
+// spiPacket collects the packets of a request to a SPI service, and runs it
+// once the last packet is received
+func (h *Arm7Hle) spiPacket(tag uint32, data uint32) {
+	buf := &h.spiBuf[tag-pxiTagTouchPanel]
+	if data&(1<<25) != 0 {
+		*buf = (*buf)[:0]
+	}
+	if idx := int(data>>16) & 0xF; idx != len(*buf) {
+		modHle7.ErrorZ("out of sequence SPI packet").
+			String("tag", pxiTagName(tag)).
+			Int("idx", idx).
+			End()
+		*buf = (*buf)[:0]
+		return
+	}
+	*buf = append(*buf, uint16(data))
+	if data&(1<<24) == 0 {
+		return
+	}
+
+	cmd := uint32((*buf)[0] >> 8)
+	result := uint32(pxiResultSuccess)
+	if tag == pxiTagTouchPanel {
+		switch cmd {
+		case spiCmdTpSampling:
+			h.sampleTouchPanel()
+		case spiCmdTpAutoOn:
+			h.tpAuto = true
+		case spiCmdTpAutoOff:
+			h.tpAuto = false
+		case spiCmdTpStability:
+		default:
+			modHle7.WarnZ("unimplemented touch panel command").Hex8("cmd", uint8(cmd)).End()
+			result = pxiResultInvalidCommand
+		}
+	}
+	h.reply(tag, 1<<15|cmd<<8|result)
+}
+
+// sampleTouchPanel writes a touchscreen sample into the system work area,
+// in the format of the SPI library (X in bits 0-11, Y in bits 12-23, pen
+// down in bit 24, and validity flags in bits 25-26, always valid).
+func (h *Arm7Hle) sampleTouchPanel() {
+	x, y, down := h.tsc.Sample()
+	v := uint32(x)&0xFFF | (uint32(y)&0xFFF)<<12
+	if down {
+		v |= 1 << 24
+	}
+	binary.LittleEndian.PutUint16(h.ram[cSysWorkTouchPanel:], uint16(v))
+	binary.LittleEndian.PutUint16(h.ram[cSysWorkTouchPanel+2:], uint16(v>>16))
+}
+
+func (h *Arm7Hle) soundRequest(data uint32) {
+	addr := data
+	for n := 0; addr != 0; n++ {
+		off := addr & 0x3FFFFF
+		if addr>>24 != 0x02 || int(off)+sndCmdSize > len(h.ram) || n == sndCmdMaxList {
+			modHle7.ErrorZ("invalid sound command list").Hex32("addr", addr).End()
+			break
+		}
+		cmd := h.ram[off : off+sndCmdSize]
+		id := binary.LittleEndian.Uint32(cmd[4:])
+		switch id {
+		case sndCmdSharedWork:
+			h.sndWork = binary.LittleEndian.Uint32(cmd[8:])
+		default:
+			if !h.sndCmds[id] {
+				h.sndCmds[id] = true
+				modHle7.WarnZ("unimplemented sound command").Uint32("id", id).End()
+			}
+		}
+		addr = binary.LittleEndian.Uint32(cmd[0:])
+	}
+
+	if off := h.sndWork & 0x3FFFFF; h.sndWork>>24 == 0x02 && int(off)+4 <= len(h.ram) {
+		tag := binary.LittleEndian.Uint32(h.ram[off:])
+		binary.LittleEndian.PutUint32(h.ram[off:], tag+1)
+	}
+}
+
+// MatchArm7Hle checks whether ARM7 HLE should be enabled for the game with
+// the specified game code, given a specification that is either "all", or a
+// comma-separated list of game codes (eg: "ASME,AMCE").
+func MatchArm7Hle(spec string, gamecode string) bool {
+	for _, s := range strings.Split(spec, ",") {
+		s = strings.TrimSpace(s)
+		if s == "all" || strings.EqualFold(s, gamecode) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/binary"
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
+	"testing"
+	"time"
+)
+
+func newTestArm7Hle() (*HwIpc, *Arm7Hle, []byte) {
+	irq9 := NewHwIrq("irq9", arm.NewCpu(arm.ARMv5, hwio.NewTable("bus9"), false))
+	irq7 := NewHwIrq("irq7", arm.NewCpu(arm.ARMv4, hwio.NewTable("bus7"), false))
+	us := make(testUserSettings, cFwUserSettingsCrc)
+	binary.LittleEndian.PutUint16(us[0x58:], 0x200) // adc x1
+	binary.LittleEndian.PutUint16(us[0x5A:], 0x300) // adc y1
+	us[0x5C], us[0x5D] = 0x20, 0x20                 // scr x1,y1
+	binary.LittleEndian.PutUint16(us[0x5E:], 0xE00) // adc x2
+	binary.LittleEndian.PutUint16(us[0x60:], 0xD00) // adc y2
+	us[0x62], us[0x63] = 0xE0, 0xA0                 // scr x2,y2
+
+	hw := &NDSHardware{
+		Ipc: NewHwIpc(irq9, irq7, nil),
+		Rtc: NewHwRtc(),
+		Tsc: NewHwTouchScreen(us, nil),
+		Key: NewHwKey(),
+	}
+	ram := make([]byte, 4*1024*1024)
+	hw.Ipc.Hle7 = NewArm7Hle(hw, ram)
+	hw.Ipc.WriteIPC9FIFOCNT(0, 1<<15|1<<3)
+	return hw.Ipc, hw.Ipc.Hle7, ram
+}
+
+func TestArm7HleBounce(t *testing.T) {
+	ipc, _, _ := newTestArm7Hle()
+
+	// Wireless request (tag 10): returned with the error flag
+	ipc.WriteIPC9FIFOSEND(0, 0x1234<<6|10)
+	if v := ipc.ReadIPC9FIFORECV(0); v != 0x1234<<6|0x20|10 {
+		t.Errorf("got %08x", v)
+	}
+
+	// Errors are not bounced again
+	ipc.WriteIPC9FIFOSEND(0, 0x20|10)
+	if ipc.ReadIPC9FIFOCNT(0)&(1<<8) == 0 {
+		t.Errorf("unexpected reply")
+	}
+}
+
+func TestArm7HleRtc(t *testing.T) {
+	ipc, h, ram := newTestArm7Hle()
+	h.rtc.Clock = func() time.Time { return time.Date(2024, 3, 9, 15, 4, 5, 0, time.UTC) }
+
+	if mask := binary.LittleEndian.Uint32(ram[cSysWorkPxiHandler:]); mask&(1<<pxiTagRtc) == 0 {
+		t.Errorf("RTC handler not registered: %08x", mask)
+	}
+
+	// Read date and time
+	ipc.WriteIPC9FIFOSEND(0, 0x10<<14|pxiTagRtc)
+	if v := ipc.ReadIPC9FIFORECV(0); v != (1<<15|0x10<<8)<<6|pxiTagRtc {
+		t.Errorf("got reply %08x", v)
+	}
+	want := []byte{0x24, 0x03, 0x09, 0x06, 0x15, 0x04, 0x05}
+	if got := ram[cSysWorkRtc : cSysWorkRtc+7]; string(got) != string(want) {
+		t.Errorf("got datetime % x, want % x", got, want)
+	}
+
+	// Unknown command
+	ipc.WriteIPC9FIFOSEND(0, 0x7F<<14|pxiTagRtc)
+	if v := ipc.ReadIPC9FIFORECV(0); v != (1<<15|0x7F<<8|pxiResultInvalidCommand)<<6|pxiTagRtc {
+		t.Errorf("got reply %08x", v)
+	}
+}
+
+func TestArm7HleTouchPanel(t *testing.T) {
+	ipc, h, ram := newTestArm7Hle()
+	h.tsc.SetPen(true, 0x10, 0x20)
+	x, y, _ := h.tsc.Sample()
+
+	// Single-packet sampling request
+	ipc.WriteIPC9FIFOSEND(0, (1<<25|1<<24|spiCmdTpSampling<<8)<<6|pxiTagTouchPanel)
+	if v := ipc.ReadIPC9FIFORECV(0); v != (1<<15|spiCmdTpSampling<<8)<<6|pxiTagTouchPanel {
+		t.Errorf("got reply %08x", v)
+	}
+	tp := uint32(binary.LittleEndian.Uint16(ram[cSysWorkTouchPanel:])) |
+		uint32(binary.LittleEndian.Uint16(ram[cSysWorkTouchPanel+2:]))<<16
+	if want := uint32(x) | uint32(y)<<12 | 1<<24; tp != want {
+		t.Errorf("got sample %08x, want %08x", tp, want)
+	}
+
+	// Two-packet auto-sampling request: no reply before the last packet
+	ipc.WriteIPC9FIFOSEND(0, (1<<25|spiCmdTpAutoOn<<8)<<6|pxiTagTouchPanel)
+	if ipc.ReadIPC9FIFOCNT(0)&(1<<8) == 0 {
+		t.Fatalf("unexpected reply")
+	}
+	ipc.WriteIPC9FIFOSEND(0, (1<<24|1<<16|1)<<6|pxiTagTouchPanel)
+	if v := ipc.ReadIPC9FIFORECV(0); v != (1<<15|spiCmdTpAutoOn<<8)<<6|pxiTagTouchPanel {
+		t.Errorf("got reply %08x", v)
+	}
+
+	// Auto samples are notified at VBlank
+	h.tsc.SetPen(false, 0, 0)
+	h.VBlank()
+	if v := ipc.ReadIPC9FIFORECV(0); v != (1<<15|spiCmdTpAutoSample<<8)<<6|pxiTagTouchPanel {
+		t.Errorf("got notification %08x", v)
+	}
+	if ram[cSysWorkTouchPanel+3]&1 != 0 {
+		t.Errorf("pen still down")
+	}
+}
+
+func TestArm7HleSound(t *testing.T) {
+	ipc, _, ram := newTestArm7Hle()
+
+	// Two commands: set the shared work area, then an unimplemented one
+	const list, work = 0x1000, 0x2000
+	binary.LittleEndian.PutUint32(ram[list:], 0x02000000+list+sndCmdSize)
+	binary.LittleEndian.PutUint32(ram[list+4:], sndCmdSharedWork)
+	binary.LittleEndian.PutUint32(ram[list+8:], 0x02000000+work)
+	binary.LittleEndian.PutUint32(ram[list+sndCmdSize+4:], 0)
+
+	ipc.WriteIPC9FIFOSEND(0, (0x02000000+list)<<6|pxiTagSound)
+	ipc.WriteIPC9FIFOSEND(0, (0x02000000+list+sndCmdSize)<<6|pxiTagSound)
+	if tag := binary.LittleEndian.Uint32(ram[work:]); tag != 2 {
+		t.Errorf("got finished command tag %d, want 2", tag)
+	}
+	if ipc.ReadIPC9FIFOCNT(0)&(1<<8) == 0 {
+		t.Errorf("unexpected reply")
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	log "ndsemu/emu/logger"
+	"os"
+	"path/filepath"
+)
+
+// wavWriter writes a 16-bit stereo PCM WAV file. Sizes in the header are
+// filled in when the file is closed.
+type wavWriter struct {
+	f   *os.File
+	w   *bufio.Writer
+	len uint32 // number of bytes of sample data
+}
+
+func createWav(fn string) (*wavWriter, error) {
+	f, err := os.Create(fn)
+	if err != nil {
+		return nil, err
+	}
+	wav := &wavWriter{f: f, w: bufio.NewWriter(f)}
+	if err := wav.header(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return wav, nil
+}
+
+func (wav *wavWriter) header() error {
+	const channels, bits = 2, 16
+	var hdr [44]byte
+	copy(hdr[0:], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:], 36+wav.len)
+	copy(hdr[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(hdr[16:], 16)
+	binary.LittleEndian.PutUint16(hdr[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(hdr[22:], channels)
+	binary.LittleEndian.PutUint32(hdr[24:], cAudioFreq)
+	binary.LittleEndian.PutUint32(hdr[28:], cAudioFreq*channels*bits/8)
+	binary.LittleEndian.PutUint16(hdr[32:], channels*bits/8)
+	binary.LittleEndian.PutUint16(hdr[34:], bits)
+	copy(hdr[36:], "data")
+	binary.LittleEndian.PutUint32(hdr[40:], wav.len)
+	_, err := wav.w.Write(hdr[:])
+	return err
+}
+
+func (wav *wavWriter) write(l, r int16) {
+	var buf [4]byte
+	binary.LittleEndian.PutUint16(buf[0:], uint16(l))
+	binary.LittleEndian.PutUint16(buf[2:], uint16(r))
+	wav.w.Write(buf[:])
+	wav.len += 4
+}
+
+func (wav *wavWriter) Close() error {
+	if err := wav.w.Flush(); err != nil {
+		wav.f.Close()
+		return err
+	}
+	if _, err := wav.f.Seek(0, 0); err != nil {
+		wav.f.Close()
+		return err
+	}
+	wav.header()
+	if err := wav.w.Flush(); err != nil {
+		wav.f.Close()
+		return err
+	}
+	return wav.f.Close()
+}
+
+// AudioDump records the audio output of a session into WAV files: the final
+// mix (as heard by the user), and/or each SPU channel separately. Channels
+// are dumped after volume and panning, but before master volume, so that
+// they are usable for ripping even if the game lowers the global volume.
+// All files have the same length, with channels that are not playing
+// recorded as silence, so that they can be lined up in an audio editor.
+type AudioDump struct {
+	mix *wavWriter
+	ch  [16]*wavWriter
+	cur [16][2]int16 // samples of each channel for the current tick
+}
+
+// NewAudioDump creates the dump files: the final mix is written to mixfn,
+// and each channel to a separate file in chdir (named ch00.wav ... ch15.wav).
+// Either can be empty to disable that part of the dump.
+func NewAudioDump(mixfn string, chdir string) (*AudioDump, error) {
+	d := &AudioDump{}
+	var err error
+	if mixfn != "" {
+		if d.mix, err = createWav(mixfn); err != nil {
+			return nil, err
+		}
+	}
+	if chdir != "" {
+		if err = os.MkdirAll(chdir, 0777); err != nil {
+			d.Close()
+			return nil, err
+		}
+		for i := range d.ch {
+			if d.ch[i], err = createWav(filepath.Join(chdir, fmt.Sprintf("ch%02d.wav", i))); err != nil {
+				d.Close()
+				return nil, err
+			}
+		}
+	}
+	return d, nil
+}
+
+// channel saves the output of a channel (in the fixed point format used by
+// the mixer) for the current tick
+func (d *AudioDump) channel(idx int, l, r int64) {
+	d.cur[idx] = [2]int16{clamp16(l >> 8), clamp16(r >> 8)}
+}
+
+// endTick writes the samples of all the channels for the current tick
+func (d *AudioDump) endTick() {
+	if d.ch[0] == nil {
+		return
+	}
+	for i := range d.ch {
+		d.ch[i].write(d.cur[i][0], d.cur[i][1])
+		d.cur[i] = [2]int16{}
+	}
+}
+
+func (d *AudioDump) writeMix(buf []int16) {
+	if d.mix == nil {
+		return
+	}
+	for i := 0; i < len(buf); i += 2 {
+		d.mix.write(buf[i], buf[i+1])
+	}
+}
+
+// Close finalizes all the dump files
+func (d *AudioDump) Close() error {
+	var err error
+	for _, wav := range append([]*wavWriter{d.mix}, d.ch[:]...) {
+		if wav == nil {
+			continue
+		}
+		if cerr := wav.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func clamp16(v int64) int16 {
+	if v > 0x7FFF {
+		return 0x7FFF
+	} else if v < -0x8000 {
+		return -0x8000
+	}
+	return int16(v)
+}
+
+// StartAudioDump starts dumping audio (see AudioDump). A dump already in
+// progress is stopped first.
+func (emu *NDSEmulator) StartAudioDump(mixfn string, chdir string) error {
+	emu.StopAudioDump()
+	d, err := NewAudioDump(mixfn, chdir)
+	if err != nil {
+		return err
+	}
+	emu.audioDump = d
+	emu.Hw.Snd.Dump = d
+	log.ModSound.InfoZ("audio dump started").String("mix", mixfn).String("channels", chdir).End()
+	return nil
+}
+
+// StopAudioDump stops dumping audio, finalizing the files
+func (emu *NDSEmulator) StopAudioDump() {
+	if emu.audioDump == nil {
+		return
+	}
+	if err := emu.audioDump.Close(); err != nil {
+		log.ModSound.ErrorZ("cannot write audio dump").Error("err", err).End()
+	}
+	emu.audioDump = nil
+	emu.Hw.Snd.Dump = nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"ndsemu/emu/hw"
+)
+
+// AudioSink receives the audio output of the emulator at the end of each
+// frame, as interleaved stereo samples at cAudioFreq. The buffer is only
+// valid during the call. Like VideoSink, it decouples the SPU from the
+// presentation: audio can be played, written to a file, or discarded.
+type AudioSink interface {
+	SubmitAudio(samples []int16)
+}
+
+// NullAudioSink discards all audio, for headless runs
+type NullAudioSink struct{}
+
+func (NullAudioSink) SubmitAudio(samples []int16) {}
+
+// SdlAudioSink plays audio through SDL. The emulation speed is paced on the
+// audio device, unless speed is not enforced.
+type SdlAudioSink struct {
+	Out *hw.Output
+}
+
+func (s *SdlAudioSink) SubmitAudio(samples []int16) {
+	s.Out.SubmitAudio(samples)
+}
+
+// WavAudioSink writes all audio into a WAV file. Since it doesn't depend on
+// the host audio device, the output is deterministic for a given input
+// sequence, and can be compared across runs. The file is finalized by Close.
+type WavAudioSink struct {
+	wav *wavWriter
+}
+
+func NewWavAudioSink(fn string) (*WavAudioSink, error) {
+	wav, err := createWav(fn)
+	if err != nil {
+		return nil, err
+	}
+	return &WavAudioSink{wav: wav}, nil
+}
+
+func (s *WavAudioSink) SubmitAudio(samples []int16) {
+	for i := 0; i+1 < len(samples); i += 2 {
+		s.wav.write(samples[i], samples[i+1])
+	}
+}
+
+func (s *WavAudioSink) Close() error {
+	return s.wav.Close()
+}
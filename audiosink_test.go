@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestWavAudioSink(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "out.wav")
+	sink, err := NewWavAudioSink(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for frame := 0; frame < 60; frame++ {
+		sink.SubmitAudio(make([]int16, audioFrameSamples(frame)*2))
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One second of audio, at exactly the output frequency
+	if n := binary.LittleEndian.Uint32(data[40:]); n != cAudioFreq*4 || len(data) != 44+int(n) {
+		t.Errorf("invalid data size: %d (file: %d bytes)", n, len(data))
+	}
+}
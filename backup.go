@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"io"
 	log "ndsemu/emu/logger"
 	"ndsemu/emu/spi"
 	"os"
@@ -10,6 +12,11 @@ import (
 
 var modBackup = log.NewModule("backup")
 
+// Duration of the write cycle of the save memory (in bus cycles), during
+// which the status register reports a write in progress. This is the
+// typical maximum write time of SPI EEPROMs (5ms).
+const cBackupWriteCycles = cBusClock * 5 / 1000
+
 // HwBackupRam implements the save ram presents in most cartridge.
 // It implements the spi.Device interface
 type HwBackupRam struct {
@@ -22,13 +29,20 @@ type HwBackupRam struct {
 	autodetect     bool
 	auxCntrWritten bool
 
+	// Emulate the write cycle (see QuirkSaveTiming)
+	AccurateTiming bool
+	writing        bool  // a write command is being transferred
+	busyUntil      int64 // end of the current write cycle
+	sched          Scheduler
+
 	fn string
 	f  *os.File
 }
 
-func NewHwBackupRam() *HwBackupRam {
+func NewHwBackupRam(sched Scheduler) *HwBackupRam {
 	b := &HwBackupRam{
 		autodetect: true,
+		sched:      sched,
 	}
 	for idx := range b.sram {
 		b.sram[idx] = 0xFF
@@ -36,11 +50,85 @@ func NewHwBackupRam() *HwBackupRam {
 	return b
 }
 
+// Reset aborts any pending command, as happens when the console is reset.
+// The contents of the memory (and its autodetected type) are preserved.
+func (b *HwBackupRam) Reset() {
+	b.addr = 0
+	b.wbuf = nil
+	b.writeEnabled = false
+	b.auxCntrWritten = false
+	b.writing = false
+	b.busyUntil = 0
+}
+
+// Close flushes the contents of the memory to the save file, and releases it
+func (b *HwBackupRam) Close() {
+	if b.sram != nil {
+		b.sram.Flush()
+		b.sram.Unmap()
+		b.sram = nil
+	}
+	if b.f != nil {
+		b.f.Close()
+		b.f = nil
+	}
+}
+
 func (b *HwBackupRam) MapSaveFile(fn string) error {
 	b.fn = fn
 	return nil
 }
 
+// mapExisting maps the save file if it exists but was not accessed yet
+func (b *HwBackupRam) mapExisting() {
+	if b.sram != nil || b.fn == "" {
+		return
+	}
+	if fi, err := os.Stat(b.fn); err == nil && fi.Size() > 0 {
+		b.checkSize(0)
+	}
+}
+
+// ReadAt reads the contents of the save memory, for tools that decode the
+// game data (eg: save editors). It bypasses the SPI protocol, so it has no
+// side effects on the emulated chip.
+func (b *HwBackupRam) ReadAt(buf []byte, off int64) (int, error) {
+	b.mapExisting()
+	if off < 0 || off >= int64(len(b.sram)) {
+		return 0, io.EOF
+	}
+	n := copy(buf, b.sram[off:])
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Size returns the size of the save memory, as known so far from the save
+// file and the accesses of the game (0 if the game didn't access it yet)
+func (b *HwBackupRam) Size() int {
+	b.mapExisting()
+	return len(b.sram)
+}
+
+// WriteAt modifies the contents of the save memory, growing it if required
+// (see ReadAt). The save file is updated immediately.
+func (b *HwBackupRam) WriteAt(buf []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if b.fn == "" {
+		return 0, errors.New("no save file")
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	b.mapExisting()
+	b.checkSize(int(off) + len(buf) - 1)
+	n := copy(b.sram[off:], buf)
+	return n, b.sram.Flush()
+}
+
 func (b *HwBackupRam) checkSize(addr int) {
 	size := len(b.sram)
 	if addr < size {
@@ -76,6 +164,28 @@ func (b *HwBackupRam) checkSize(addr int) {
 	}
 }
 
+// jedecID returns the reply to the RDID command, which games use to detect
+// the second chip of the cartridge (the backup memory) before accessing it.
+// Only flash memories (256 KiB and bigger, with 3-byte addresses) implement
+// it: they return the manufacturer, the memory type and the capacity (log2
+// of the size); EEPROMs and FRAMs don't drive the bus, so 0xFF is read.
+func (b *HwBackupRam) jedecID() []byte {
+	b.mapExisting()
+	size := len(b.sram)
+	if !b.autodetect && b.addrSize == 3 && size < 256*1024 {
+		// The save file is grown lazily, while the game writes it
+		size = 256 * 1024
+	}
+	if size < 256*1024 {
+		return []byte{0xFF, 0xFF, 0xFF}
+	}
+	capacity := byte(0)
+	for 1<<capacity < size {
+		capacity++
+	}
+	return []byte{0x20, 0x40, capacity} // ST/Micron M25PE series
+}
+
 func (b *HwBackupRam) tryAutoDetect(data []byte) bool {
 	if len(data) == 1 {
 		b.auxCntrWritten = false
@@ -112,8 +222,18 @@ func (b *HwBackupRam) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 		if b.writeEnabled {
 			sr |= 2
 		}
+		if b.busy() {
+			// Write in progress; the write enable latch is reset at
+			// the end of the write cycle
+			sr |= 3
+		}
 		return []byte{sr}, spi.ReqFinish
 
+	case 0x9F: // RDID
+		id := b.jedecID()
+		modBackup.InfoZ("cmd RDID").Blob("id", id).End()
+		return id, spi.ReqFinish
+
 	case 0x4: // WRDI
 		modBackup.InfoZ("cmd WRDI").End()
 		b.writeEnabled = false
@@ -162,6 +282,10 @@ func (b *HwBackupRam) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 		if b.autodetect {
 			modBackup.FatalZ("writing while autodetecting size").End()
 		}
+		if b.busy() {
+			modBackup.WarnZ("writing during write cycle").End()
+		}
+		b.writing = true
 
 		if len(data) < 1+b.addrSize {
 			return nil, spi.ReqContinue
@@ -210,4 +334,15 @@ func (b *HwBackupRam) SpiBegin() {
 func (b *HwBackupRam) SpiEnd() {
 	modBackup.InfoZ("end transfer").End()
 	b.sram.Flush()
+
+	// The write cycle begins when the chip is deselected
+	if b.writing && b.AccurateTiming {
+		b.busyUntil = b.sched.Cycles() + cBackupWriteCycles
+		b.writeEnabled = false
+	}
+	b.writing = false
+}
+
+func (b *HwBackupRam) busy() bool {
+	return b.AccurateTiming && b.sched.Cycles() < b.busyUntil
 }
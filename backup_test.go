@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupRamReadWriteAt(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "game.sav")
+	b := NewHwBackupRam(nil)
+	b.MapSaveFile(fn)
+
+	buf := make([]byte, 4)
+	if _, err := b.ReadAt(buf, 0); err != io.EOF {
+		t.Errorf("read from empty save: got err %v", err)
+	}
+	if _, err := b.WriteAt([]byte("ABCD"), 0x3FC); err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+
+	// The save is grown to a power of two, and persisted
+	b = NewHwBackupRam(nil)
+	b.MapSaveFile(fn)
+	if b.Size() != 0x400 {
+		t.Errorf("invalid size: %d", b.Size())
+	}
+	if n, err := b.ReadAt(buf, 0x3FE); n != 2 || err != io.EOF || !bytes.Equal(buf[:2], []byte("CD")) {
+		t.Errorf("read across the end: got %d bytes (%q), err %v", n, buf[:n], err)
+	}
+	if _, err := b.ReadAt(buf, 0x400); err != io.EOF {
+		t.Errorf("read after the end: got err %v", err)
+	}
+	b.Close()
+}
+
+func TestBackupRamJedecID(t *testing.T) {
+	for _, tc := range []struct {
+		size     int
+		addrSize int // 0: not autodetected yet
+		id       []byte
+	}{
+		{0, 0, []byte{0xFF, 0xFF, 0xFF}},
+		{0, 2, []byte{0xFF, 0xFF, 0xFF}},
+		{64 * 1024, 2, []byte{0xFF, 0xFF, 0xFF}},
+		{0, 3, []byte{0x20, 0x40, 0x12}},
+		{256 * 1024, 0, []byte{0x20, 0x40, 0x12}},
+		{512 * 1024, 3, []byte{0x20, 0x40, 0x13}},
+		{1024 * 1024, 3, []byte{0x20, 0x40, 0x14}},
+		{8 * 1024 * 1024, 3, []byte{0x20, 0x40, 0x17}},
+	} {
+		b := NewHwBackupRam(nil)
+		b.MapSaveFile(filepath.Join(t.TempDir(), "game.sav"))
+		if tc.size > 0 {
+			b.WriteAt([]byte{0}, int64(tc.size-1))
+		}
+		if tc.addrSize != 0 {
+			b.autodetect, b.addrSize = false, tc.addrSize
+		}
+		if id, _ := b.SpiTransfer([]byte{0x9F}); !bytes.Equal(id, tc.id) {
+			t.Errorf("size %d, addr size %d: got id % x, want % x", tc.size, tc.addrSize, id, tc.id)
+		}
+		b.Close()
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"math"
+	"ndsemu/arm"
+	log "ndsemu/emu/logger"
+)
+
+var modBios7 = log.NewModule("bios7")
+
+// Approximate duration (in ARM7 cycles) of the BIOS implementations of the
+// calls emulated by ActivateBios7Hle. Sound engines often time these calls
+// (eg: the bias ramp at startup), so the CPU clock is advanced as if the
+// BIOS code had run. No hardware measurements are available: the values are
+// counted from the instructions the BIOS code executes, and
+// TestBiosHleTimings checks them (within 25%) against the BIOS code run by
+// the interpreter, when the BIOS images are present in bios/. The costs of
+// the stores of RegisterRamReset follow the bus widths and waitstates of
+// the GBA memory map, as documented in GBATEK.
+const (
+	cBios7SoundBiasStep   = 12  // per step, excluding the delay loop
+	cBios7WaitLoop        = 4   // per iteration of the delay loop (WaitByLoop)
+	cBios7TableLookup     = 10  // range check and table read
+	cBiosGbaSoundBiasStep = 44  // per step, including a fixed delay loop
+	cBiosGbaMidiKey2Freq  = 180 // table lookup and 64-bit multiplication
+	cBiosGbaRamResetBase  = 80  // flag decoding and I/O registers
+)
+
+// ActivateBios7Hle installs on the ARM7 a high-level emulation of the sound
+// related BIOS calls, in place of the BIOS code:
+//
+//	NDS mode: 08h SoundBias, 1Ah GetSineTable, 1Bh GetPitchTable,
+//	          1Ch GetVolumeTable
+//	GBA mode: 01h RegisterRamReset, 19h SoundBias, 1Fh MidiKey2Freq
+//
+// They are not required to run games (the real BIOS is always present), but
+// they are executed much faster, and can be traced through the "bios7" log
+// module. Each call advances the CPU clock by the estimated duration of the
+// BIOS code (see the constants above); the sound tables are computed rather
+// than copied from the BIOS, so their entries may differ slightly from it.
+//
+// The SWI numbers depend on the mode, so this must be called again after
+// switching to GBA; the functions of the other mode are removed.
+func ActivateBios7Hle(cpu *arm.Cpu, mode EmuMode) {
+	switch mode {
+	case ModeNds:
+		cpu.SetSwiHle(0x01, nil)
+		cpu.SetSwiHle(0x19, nil)
+		cpu.SetSwiHle(0x1F, nil)
+		cpu.SetSwiHle(0x08, bios7SoundBias)
+		cpu.SetSwiHle(0x1A, bios7GetSineTable)
+		cpu.SetSwiHle(0x1B, bios7GetPitchTable)
+		cpu.SetSwiHle(0x1C, bios7GetVolumeTable)
+	case ModeGba:
+		cpu.SetSwiHle(0x08, nil)
+		cpu.SetSwiHle(0x1A, nil)
+		cpu.SetSwiHle(0x1B, nil)
+		cpu.SetSwiHle(0x1C, nil)
+		cpu.SetSwiHle(0x01, biosGbaRegisterRamReset)
+		cpu.SetSwiHle(0x19, biosGbaSoundBias)
+		cpu.SetSwiHle(0x1F, biosGbaMidiKey2Freq)
+	}
+}
+
+// rampSoundBias moves the bias level (bits 0-9 of the SOUNDBIAS register at
+// the specified address) towards the target, one step at a time, like the
+// BIOS does to avoid clicks; since the CPU is blocked during the ramp, only
+// the final value is written. Returns the number of steps.
+func rampSoundBias(cpu *arm.Cpu, addr uint32, target uint32) int64 {
+	bias := cpu.Read32(addr)
+	level := bias & 0x3FF
+	steps := int64(level) - int64(target)
+	if steps < 0 {
+		steps = -steps
+	}
+	cpu.Write32(addr, bias&^0x3FF|target)
+	return steps
+}
+
+// SWI 08h (NDS7) - SoundBias
+//
+//	r0: 0=decrease bias level to 0, else increase it to 0x200
+//	r1: delay per step (in WaitByLoop units)
+func bios7SoundBias(cpu *arm.Cpu) int64 {
+	target := uint32(0x200)
+	if cpu.Regs[0] == 0 {
+		target = 0
+	}
+	delay := int64(cpu.Regs[1])
+	steps := rampSoundBias(cpu, 0x4000504, target)
+	modBios7.InfoZ("SoundBias").Hex32("target", target).Int64("steps", steps).End()
+	return steps * (cBios7SoundBiasStep + delay*cBios7WaitLoop)
+}
+
+// SWI 1Ah (NDS7) - GetSineTable
+//
+//	r0: index (0-3Fh), a quarter of a period in 64 steps
+//
+// Returns in r0 the sine of the angle, as 1.15 fixed point (0-7FF5h).
+func bios7GetSineTable(cpu *arm.Cpu) int64 {
+	idx := uint32(cpu.Regs[0]) & 0x3F
+	cpu.SetReg(0, uint32(math.Sin(float64(idx)*math.Pi/128)*0x7FFF))
+	return cBios7TableLookup
+}
+
+// SWI 1Bh (NDS7) - GetPitchTable
+//
+//	r0: index (0-2FFh), an octave in 768 steps
+//
+// Returns in r0 the fractional part of the frequency multiplier for the
+// pitch offset, as 0.16 fixed point (0-FF8Ah): 2^(index/768) - 1.
+func bios7GetPitchTable(cpu *arm.Cpu) int64 {
+	idx := uint32(cpu.Regs[0]) % 0x300
+	cpu.SetReg(0, uint32(math.Floor((math.Exp2(float64(idx)/768)-1)*0x10000+0.5)))
+	return cBios7TableLookup
+}
+
+// SWI 1Ch (NDS7) - GetVolumeTable
+//
+//	r0: index (0-2D3h), an attenuation of (2D3h-index)/10 dB
+//
+// Returns in r0 the channel volume (0-7Fh) for the attenuation. Below -6, -12
+// and -24 dB, the volume is meant to be used with a divider of respectively
+// 2, 4 and 16, so it is scaled up to keep the resolution.
+func bios7GetVolumeTable(cpu *arm.Cpu) int64 {
+	idx := uint32(cpu.Regs[0])
+	if idx > 0x2D3 {
+		idx = 0x2D3
+	}
+	db := int(idx) - 0x2D3 // in 1/10 dB
+	scale := 1.0
+	switch {
+	case db < -240:
+		scale = 16
+	case db < -120:
+		scale = 4
+	case db < -60:
+		scale = 2
+	}
+	vol := uint32(math.Floor(127*math.Pow(10, float64(db)/200)*scale + 0.5))
+	if vol > 0x7F {
+		vol = 0x7F
+	}
+	cpu.SetReg(0, vol)
+	return cBios7TableLookup
+}
+
+// SWI 19h (GBA) - SoundBias
+//
+//	r0: 0=decrease bias level to 0, else increase it to 0x200
+func biosGbaSoundBias(cpu *arm.Cpu) int64 {
+	target := uint32(0x200)
+	if cpu.Regs[0] == 0 {
+		target = 0
+	}
+	steps := rampSoundBias(cpu, 0x4000088, target)
+	modBios7.InfoZ("SoundBias").Hex32("target", target).Int64("steps", steps).End()
+	return steps * cBiosGbaSoundBiasStep
+}
+
+// SWI 1Fh (GBA) - MidiKey2Freq
+//
+//	r0: pointer to WaveData (sample frequency at offset 4)
+//	r1: MIDI key (0-127)
+//	r2: fine adjustment (0-255, in 1/256 of semitone)
+//
+// Returns in r0 the frequency to play the sample at, so that it sounds like
+// the specified key: freq / 2^((180-key-fine/256)/12).
+func biosGbaMidiKey2Freq(cpu *arm.Cpu) int64 {
+	freq := cpu.Read32(uint32(cpu.Regs[0]) + 4)
+	key := float64(cpu.Regs[1]) + float64(cpu.Regs[2]&0xFF)/256
+	cpu.SetReg(0, uint32(float64(freq)/math.Exp2((180-key)/12)))
+	return cBiosGbaMidiKey2Freq
+}
+
+// GBA memory areas cleared by RegisterRamReset, with the cost (in cycles)
+// of each 32-bit store, which depends on the bus width and waitstates
+var biosGbaRamResetAreas = [...]struct {
+	addr, size uint32
+	cost       int64
+}{
+	{0x02000000, 256 * 1024, 6},      // EWRAM (16-bit bus, 2 waitstates)
+	{0x03000000, 32*1024 - 0x200, 1}, // IWRAM (except the stack/BIOS area)
+	{0x05000000, 1024, 2},            // palette
+	{0x06000000, 96 * 1024, 2},       // VRAM
+	{0x07000000, 1024, 1},            // OAM
+}
+
+// SWI 01h (GBA) - RegisterRamReset
+//
+//	r0: bitmask of the areas to reset:
+//	    0-4: EWRAM, IWRAM, palette, VRAM, OAM
+//	    5: serial registers, 6: sound registers, 7: other registers
+func biosGbaRegisterRamReset(cpu *arm.Cpu) int64 {
+	flags := uint32(cpu.Regs[0])
+	delay := int64(cBiosGbaRamResetBase)
+
+	// The display is always forced blank
+	cpu.Write16(0x4000000, 0x80)
+
+	for i, area := range biosGbaRamResetAreas {
+		if flags&(1<<uint(i)) == 0 {
+			continue
+		}
+		for off := uint32(0); off < area.size; off += 4 {
+			cpu.Write32(area.addr+off, 0)
+		}
+		delay += int64(area.size/4) * area.cost
+	}
+
+	// Sound registers (serial registers are not emulated at all)
+	if flags&(1<<6) != 0 {
+		for _, off := range []uint32{0x60, 0x62, 0x64, 0x68, 0x6C, 0x70, 0x72, 0x74, 0x78, 0x7C, 0x80, 0x82, 0x84} {
+			cpu.Write16(0x4000000+off, 0)
+		}
+		cpu.Write32(0x4000088, 0x200)
+	}
+	if flags&(1<<7) != 0 {
+		// Display, DMA, timers, and interrupts
+		for addr := uint32(0x4000002); addr < 0x4000056; addr += 2 {
+			cpu.Write16(addr, 0)
+		}
+		for addr := uint32(0x40000B0); addr < 0x40000E0; addr += 4 {
+			cpu.Write32(addr, 0)
+		}
+		for addr := uint32(0x4000100); addr < 0x4000110; addr += 4 {
+			cpu.Write32(addr, 0)
+		}
+		cpu.Write16(0x4000200, 0)
+		cpu.Write16(0x4000202, 0xFFFF)
+		cpu.Write16(0x4000208, 0)
+	}
+
+	modBios7.InfoZ("RegisterRamReset").Hex8("flags", uint8(flags)).End()
+	return delay
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"io/ioutil"
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
+	"testing"
+)
+
+func TestBios7SoundTables(t *testing.T) {
+	cpu := arm.NewCpu(arm.ARMv4, hwio.NewTable("bus7"), false)
+
+	// Entries documented for the BIOS tables
+	for _, tc := range []struct {
+		name string
+		swi  func(cpu *arm.Cpu) int64
+		idx  uint32
+		want uint32
+	}{
+		{"sine", bios7GetSineTable, 0, 0},
+		{"sine", bios7GetSineTable, 0x3F, 0x7FF5},
+		{"pitch", bios7GetPitchTable, 0, 0},
+		{"pitch", bios7GetPitchTable, 0x2FF, 0xFF8A},
+		{"volume", bios7GetVolumeTable, 0, 0},
+		{"volume", bios7GetVolumeTable, 0x2D3, 0x7F},
+	} {
+		cpu.SetReg(0, tc.idx)
+		if cycles := tc.swi(cpu); cycles != cBios7TableLookup {
+			t.Errorf("%s[%x]: %d cycles", tc.name, tc.idx, cycles)
+		}
+		if v := uint32(cpu.Regs[0]); v != tc.want {
+			t.Errorf("%s[%x] = %x, want %x", tc.name, tc.idx, v, tc.want)
+		}
+	}
+
+	// The volume table restarts from the top at every divider step (-6,
+	// -12 and -24 dB), and never decreases within each range
+	prev := uint32(0)
+	for idx := uint32(0); idx <= 0x2D3; idx++ {
+		cpu.SetReg(0, idx)
+		bios7GetVolumeTable(cpu)
+		v := uint32(cpu.Regs[0])
+		db := int(idx) - 0x2D3
+		if v < prev && db != -240 && db != -120 && db != -60 {
+			t.Errorf("volume[%x] = %x, lower than the previous entry (%x)", idx, v, prev)
+		}
+		prev = v
+	}
+}
+
+// measureBiosSwi runs a SWI from Thumb code, with the BIOS mapped at address
+// 0, and returns the cycles taken until the call returns. If hle is true,
+// the call is served by ActivateBios7Hle instead of the BIOS code. bias is
+// the initial value of the SOUNDBIAS register.
+func measureBiosSwi(t *testing.T, bios []byte, mode EmuMode, hle bool, bias uint32, swi uint8, args ...uint32) int64 {
+	bus := hwio.NewTable("bus7")
+	bus.MapMemorySlice(0x00000000, 0x00003FFF, bios, true)
+	base, biasAddr, ram := uint32(0x03800000), uint32(0x4000504), make([]byte, 64*1024)
+	if mode == ModeGba {
+		base, biasAddr, ram = 0x03000000, 0x4000088, ram[:32*1024]
+	}
+	bus.MapMemorySlice(base, base+uint32(len(ram))-1, ram, false)
+	bus.MapReg32(biasAddr, &hwio.Reg32{Name: "SOUNDBIAS", Value: bias})
+
+	// swi N; b .
+	ram[0], ram[1], ram[2], ram[3] = swi, 0xDF, 0xFE, 0xE7
+
+	cpu := arm.NewCpu(arm.ARMv4, bus, false)
+	if hle {
+		ActivateBios7Hle(cpu, mode)
+	}
+	cpu.SetReg(13, base+uint32(len(ram))-0x100) // supervisor stack
+	cpu.Cpsr.SetMode(arm.CpuModeSystem, cpu)
+	cpu.SetReg(13, base+uint32(len(ram))-0x200)
+	cpu.Cpsr.SetT(true, cpu)
+	for i, v := range args {
+		cpu.SetReg(i, v)
+	}
+	cpu.SetPC(base)
+
+	for uint32(cpu.Regs[15]) != base+2 {
+		if cpu.Clock > 10000000 {
+			t.Fatalf("SWI %02x did not return (pc=%v)", swi, cpu.Regs[15])
+		}
+		cpu.Run(cpu.Clock + 1)
+	}
+	return cpu.Clock
+}
+
+// The HLE timings are estimates: check them against the BIOS code run by
+// the interpreter, when the BIOS images are available.
+func TestBiosHleTimings(t *testing.T) {
+	type call struct {
+		name string
+		bias uint32
+		swi  uint8
+		args []uint32
+	}
+	for _, bc := range []struct {
+		file  string
+		mode  EmuMode
+		calls []call
+	}{
+		{"bios/biosnds7.rom", ModeNds, []call{
+			{"SoundBias", 0, 0x08, []uint32{1, 8}},
+			{"SoundBias", 0x200, 0x08, []uint32{0, 1}},
+			{"GetSineTable", 0, 0x1A, []uint32{0x20}},
+			{"GetPitchTable", 0, 0x1B, []uint32{0x180}},
+			{"GetVolumeTable", 0, 0x1C, []uint32{0x180}},
+		}},
+		{"bios/biosgba.rom", ModeGba, []call{
+			{"SoundBias", 0, 0x19, []uint32{1}},
+			{"SoundBias", 0x200, 0x19, []uint32{0}},
+			{"MidiKey2Freq", 0, 0x1F, []uint32{0x03000100, 60, 0x80}},
+		}},
+	} {
+		bios, err := ioutil.ReadFile(bc.file)
+		if err != nil {
+			t.Log("BIOS not available:", err)
+			continue
+		}
+		for _, c := range bc.calls {
+			lle := measureBiosSwi(t, bios, bc.mode, false, c.bias, c.swi, c.args...)
+			hle := measureBiosSwi(t, bios, bc.mode, true, c.bias, c.swi, c.args...)
+			t.Logf("%s: %s: BIOS %d cycles, HLE %d cycles", bc.file, c.name, lle, hle)
+			if hle < lle*3/4 || hle > lle*5/4 {
+				t.Errorf("%s: %s: HLE takes %d cycles, BIOS %d", bc.file, c.name, hle, lle)
+			}
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"net"
+)
+
+var modBridge = log.NewModule("bridge")
+
+// Maximum number of bytes that can be read/written with a single request
+const cBridgeMaxData = 64 * 1024
+
+// Bridge exposes a small RPC protocol over TCP, meant for external tools like
+// disassembler plugins (Ghidra, IDA) that want to inspect the live state of
+// the emulator.
+//
+// The protocol is newline-delimited JSON. Each request is an object with an
+// optional "id" (echoed in the reply), a "method", and method-specific
+// parameters; each reply contains either a "result" or an "error":
+//
+//	{"id":1, "method":"read", "cpu":"arm9", "addr":33554432, "size":16}
+//	{"id":1, "result":{"data":"00112233..."}}
+//
+// Supported methods:
+//
+//	info                    emulator mode and current frame
+//	regs    cpu             CPU registers
+//	setreg  cpu, reg, val   change a CPU register (reg is its index in regs)
+//	read    cpu, addr, size read memory (hex-encoded data)
+//	write   cpu, addr, data write memory (hex-encoded data)
+//	devices                 names of the hardware devices with I/O registers
+//	ioregs  [device]        I/O registers of the device (or all devices)
+//	regmap  cpu             map of the I/O registers on the bus of the CPU
+//	break   addr            add a breakpoint (requires the debugger)
+//	watch   addr            add a watchpoint (requires the debugger)
+//	reset   [hard]          reset the console (soft reset, unless hard is true)
+//	load    file            switch to another ROM (see NDSEmulator.LoadRom)
+//	audio   interp, lowpass, output
+//	                        change audio quality (all optional; see SoundQuality)
+//	audiodump file, dir     dump the audio mix to file and channels to dir (none: stop)
+//	jitstat                 JIT counters of both CPUs (null if the JIT is disabled)
+//	stop                    shut down the emulator cleanly (saves are flushed)
+//
+// Memory accesses (including I/O registers through "read" and "ioregs") have
+// no side effects on emulation: for instance, they don't pop values from FIFOs
+// (see hwio.Table.Peek8). The reply to "read" lists in "sensitive" the offsets
+// of the bytes that belong to such registers (see hwio.Table.ReadSensitive).
+//
+// Requests are received in background, but executed on the emulation thread
+// between frames (see Poll), so that they always see a consistent state.
+type Bridge struct {
+	// Stop is invoked by the "stop" request; it should make the main loop
+	// exit at the end of the current frame.
+	Stop func()
+
+	srv *rpcServer
+}
+
+type bridgeRequest struct {
+	Method  string `json:"method"`
+	Cpu     string `json:"cpu,omitempty"`
+	Addr    uint32 `json:"addr,omitempty"`
+	Size    int    `json:"size,omitempty"`
+	Data    string `json:"data,omitempty"`
+	Reg     int    `json:"reg,omitempty"`
+	Val     uint32 `json:"val,omitempty"`
+	Device  string `json:"device,omitempty"`
+	Hard    bool   `json:"hard,omitempty"`
+	File    string `json:"file,omitempty"`
+	Interp  string `json:"interp,omitempty"`
+	LowPass *bool  `json:"lowpass,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+}
+
+type bridgeReg struct {
+	Name   string `json:"name"`
+	Bank   int    `json:"bank"`
+	Offset uint32 `json:"offset"`
+	Size   int    `json:"size"`
+	Value  uint64 `json:"value"`
+}
+
+type bridgeRegDesc struct {
+	Name    string        `json:"name"`
+	Addr    uint32        `json:"addr"`
+	Bank    int           `json:"bank"`
+	Offset  uint32        `json:"offset"`
+	Size    int           `json:"size"`
+	RwMask  uint64        `json:"rwmask"`
+	ReadCb  string        `json:"rcb,omitempty"`
+	WriteCb string        `json:"wcb,omitempty"`
+	Flags   hwio.RegFlags `json:"flags,omitempty"`
+	Mem     bool          `json:"mem,omitempty"`
+}
+
+// rpcServer is the transport of the newline-delimited JSON protocols of the
+// bridge and of the remote control (see Control): it serves connections in
+// background, and queues the requests until the emulation thread executes
+// them (see poll).
+type rpcServer struct {
+	mod  log.Module
+	ln   net.Listener
+	reqs chan *rpcCall
+}
+
+type rpcReply struct {
+	ID     interface{} `json:"id,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+type rpcCall struct {
+	req   json.RawMessage
+	reply chan rpcReply
+}
+
+func newRpcServer(ln net.Listener, mod log.Module) *rpcServer {
+	s := &rpcServer{
+		mod:  mod,
+		ln:   ln,
+		reqs: make(chan *rpcCall),
+	}
+	go s.acceptLoop()
+	mod.InfoZ("listening").String("addr", ln.Addr().String()).End()
+	return s
+}
+
+func (s *rpcServer) acceptLoop() {
+	for {
+		conn, err := s.ln.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			s.mod.ErrorZ("accept error").Error("err", err).End()
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *rpcServer) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mod.InfoZ("client connected").String("addr", conn.RemoteAddr().String()).End()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	for {
+		var call rpcCall
+		if err := dec.Decode(&call.req); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				enc.Encode(rpcReply{Error: err.Error()})
+			}
+			s.mod.InfoZ("client disconnected").String("addr", conn.RemoteAddr().String()).End()
+			return
+		}
+		call.reply = make(chan rpcReply, 1)
+		s.reqs <- &call
+		if err := enc.Encode(<-call.reply); err != nil {
+			return
+		}
+	}
+}
+
+// Close stops listening for new connections
+func (s *rpcServer) Close() {
+	s.ln.Close()
+}
+
+// poll executes all the pending requests, decoding each of them into a new
+// value returned by newReq, and passing it to handle
+func (s *rpcServer) poll(newReq func() interface{}, handle func(req interface{}) (interface{}, error)) {
+	for {
+		select {
+		case call := <-s.reqs:
+			var id struct {
+				ID interface{} `json:"id"`
+			}
+			json.Unmarshal(call.req, &id)
+			var res interface{}
+			req := newReq()
+			err := json.Unmarshal(call.req, req)
+			if err == nil {
+				res, err = handle(req)
+			}
+			reply := rpcReply{ID: id.ID, Result: res}
+			if err != nil {
+				reply.Error = err.Error()
+			}
+			call.reply <- reply
+		default:
+			return
+		}
+	}
+}
+
+// NewBridge starts listening for bridge connections on the specified TCP
+// address (eg: "localhost:7777").
+func NewBridge(addr string) (*Bridge, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Bridge{srv: newRpcServer(ln, modBridge)}, nil
+}
+
+// Close stops listening for new connections
+func (b *Bridge) Close() {
+	b.srv.Close()
+}
+
+// Poll executes all the pending requests. It must be called periodically
+// by the emulation thread.
+func (b *Bridge) Poll(emu *NDSEmulator) {
+	b.srv.poll(func() interface{} { return new(bridgeRequest) },
+		func(req interface{}) (interface{}, error) { return b.handle(emu, req.(*bridgeRequest)) })
+}
+
+func bridgeCpu(name string) (*arm.Cpu, error) {
+	switch name {
+	case "arm9":
+		return nds9.Cpu, nil
+	case "arm7":
+		return nds7.Cpu, nil
+	default:
+		return nil, fmt.Errorf("invalid cpu: %q (use arm9 or arm7)", name)
+	}
+}
+
+// peekRange reads memory for the "read" request, without side effects. The
+// offsets of the bytes that are read-sensitive (see arm.Cpu.ReadSensitive)
+// are listed in "sensitive", as their value is the last one stored, rather
+// than what the CPU would read.
+func peekRange(cpu *arm.Cpu, addr uint32, size int) map[string]interface{} {
+	data := make([]byte, size)
+	var sensitive []int
+	for i := range data {
+		data[i] = cpu.Peek8(addr + uint32(i))
+		if cpu.ReadSensitive(addr + uint32(i)) {
+			sensitive = append(sensitive, i)
+		}
+	}
+	res := map[string]interface{}{"data": hex.EncodeToString(data)}
+	if sensitive != nil {
+		res["sensitive"] = sensitive
+	}
+	return res
+}
+
+func (b *Bridge) handle(emu *NDSEmulator, req *bridgeRequest) (interface{}, error) {
+	switch req.Method {
+	case "info":
+		return map[string]interface{}{
+			"mode":  emu.Mode,
+			"frame": emu.framecount,
+		}, nil
+
+	case "regs":
+		cpu, err := bridgeCpu(req.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		regs := make(map[string]interface{})
+		names, vals := cpu.GetRegNames(), cpu.GetRegs()
+		for i := range names {
+			regs[names[i]] = vals[i]
+		}
+		snames, svals := cpu.GetSpecialRegNames(), cpu.GetSpecialRegs()
+		for i := range snames {
+			regs[snames[i]] = svals[i]
+		}
+		return map[string]interface{}{
+			"names": names,
+			"regs":  regs,
+			"pc":    cpu.GetPc(),
+		}, nil
+
+	case "setreg":
+		cpu, err := bridgeCpu(req.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		if req.Reg < 0 || req.Reg >= len(cpu.GetRegNames()) {
+			return nil, fmt.Errorf("invalid register index: %d", req.Reg)
+		}
+		cpu.SetReg(req.Reg, req.Val)
+		return true, nil
+
+	case "read":
+		cpu, err := bridgeCpu(req.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		if req.Size <= 0 || req.Size > cBridgeMaxData {
+			return nil, fmt.Errorf("invalid size: %d", req.Size)
+		}
+		return peekRange(cpu, req.Addr, req.Size), nil
+
+	case "write":
+		cpu, err := bridgeCpu(req.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		data, err := hex.DecodeString(req.Data)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > cBridgeMaxData {
+			return nil, fmt.Errorf("too much data: %d bytes", len(data))
+		}
+		for i, v := range data {
+			if !cpu.Poke8(req.Addr+uint32(i), v) {
+				return nil, fmt.Errorf("address %08x is not mapped", req.Addr+uint32(i))
+			}
+		}
+		return true, nil
+
+	case "devices":
+		var names []string
+		for _, dev := range emu.ioDevices() {
+			names = append(names, dev.name)
+		}
+		return names, nil
+
+	case "ioregs":
+		res := make(map[string][]bridgeReg)
+		for _, dev := range emu.ioDevices() {
+			if req.Device != "" && req.Device != dev.name {
+				continue
+			}
+			regs := []bridgeReg{}
+			for _, r := range hwio.RegList(dev.regs) {
+				regs = append(regs, bridgeReg(r))
+			}
+			res[dev.name] = regs
+		}
+		if len(res) == 0 {
+			return nil, fmt.Errorf("invalid device: %q", req.Device)
+		}
+		return res, nil
+
+	case "regmap":
+		cpu, err := bridgeCpu(req.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		bus := nds9.Bus
+		if cpu == nds7.Cpu {
+			bus = nds7.Bus
+		}
+		regs := []bridgeRegDesc{}
+		for _, r := range bus.RegMap() {
+			regs = append(regs, bridgeRegDesc(r))
+		}
+		return regs, nil
+
+	case "break", "watch":
+		if emu.dbg == nil {
+			return nil, errors.New("debugger not running (use -debug)")
+		}
+		if req.Method == "break" {
+			emu.dbg.AddBreakpoint(req.Addr)
+		} else {
+			emu.dbg.AddWatchpoint(req.Addr)
+		}
+		return true, nil
+
+	case "reset":
+		emu.Reset(req.Hard)
+		return true, nil
+
+	case "load":
+		if err := emu.LoadRom(req.File); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "audio":
+		q := emu.SoundQuality()
+		if req.Interp != "" {
+			interp, err := ParseSoundInterp(req.Interp)
+			if err != nil {
+				return nil, err
+			}
+			q.Interp = interp
+		}
+		if req.LowPass != nil {
+			q.LowPass = *req.LowPass
+		}
+		if req.Output != "" {
+			output, err := ParseSoundOutput(req.Output)
+			if err != nil {
+				return nil, err
+			}
+			q.Output = output
+		}
+		emu.SetSoundQuality(q)
+		return map[string]interface{}{
+			"interp":  q.Interp.String(),
+			"lowpass": q.LowPass,
+			"output":  q.Output.String(),
+		}, nil
+
+	case "audiodump":
+		if req.File == "" && req.Dir == "" {
+			emu.StopAudioDump()
+			return true, nil
+		}
+		if err := emu.StartAudioDump(req.File, req.Dir); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "jitstat":
+		stats := make(map[string]interface{})
+		for _, name := range []string{"arm9", "arm7"} {
+			cpu, _ := bridgeCpu(name)
+			if jit := cpu.Jit(); jit != nil {
+				stats[name] = jit.Stats()
+			} else {
+				stats[name] = nil
+			}
+		}
+		return stats, nil
+
+	case "stop":
+		if b.Stop == nil {
+			return nil, errors.New("stop not supported")
+		}
+		b.Stop()
+		return true, nil
+
+	default:
+		return nil, fmt.Errorf("invalid method: %q", req.Method)
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
-	"bytes"
 	"encoding/binary"
 	"io"
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"os"
 )
 
 type CartHeader struct {
@@ -37,23 +39,16 @@ func (c *CartHeader) Read(r io.Reader) error {
 }
 
 func copyToRam(dst []byte, src io.ReaderAt, dstOff, srcOff, size uint32) error {
-	chunk := make([]byte, size)
-	if _, err := src.ReadAt(chunk, int64(srcOff)); err != nil {
-		return err
-	}
-	copy(dst[dstOff:dstOff+size], chunk)
-	return nil
+	_, err := src.ReadAt(dst[dstOff:dstOff+size], int64(srcOff))
+	return err
 }
 
 func InjectGamecard(gc *Gamecard, mem *NDSMemory) error {
 	// read the cartridge header
-	data := make([]byte, gc.Size)
-	if _, err := gc.ReadAt(data, 0); err != nil {
+	ch := &CartHeader{}
+	if err := ch.Read(io.NewSectionReader(gc, 0, 0x200)); err != nil {
 		return err
 	}
-	buf := bytes.NewBuffer(data)
-	ch := &CartHeader{}
-	ch.Read(buf)
 
 	// copy the gamecard data into memory destinations specified by header
 	err := copyToRam(
@@ -83,7 +78,113 @@ func InjectGamecard(gc *Gamecard, mem *NDSMemory) error {
 	nds7.Cpu.SetPC(ch.Arm7Entry)
 
 	// Header is copied by BIOS to 0x27FFE00
-	copyToRam(mem.Ram[:], gc, 0x3FFE00, 0, 0x180)
+	copyToRam(mem.Ram[:], gc, 0x3FFE00, 0, 0x170)
+
+	return nil
+}
+
+// Initial stack pointers set by the firmware, for each CPU and mode
+var directBootStacks = [2]struct{ sys, irq, svc uint32 }{
+	{0x03002F7C, 0x03003F80, 0x03003FC0}, // ARM9
+	{0x0380FD80, 0x0380FF80, 0x0380FFC0}, // ARM7
+}
 
+// DirectBoot loads the game inserted in slot 1 and starts it directly,
+// skipping the BIOS and the firmware. The state of the console (memory
+// contents, registers, CPU modes) is initialized as the firmware leaves it
+// when it jumps to the game. fwfn is the firmware file, from which the user
+// settings are read if the firmware flash is not mapped yet.
+func (emu *NDSEmulator) DirectBoot(fwfn string) error {
+	gc, ram := emu.Hw.Gc, emu.Mem.Ram[:]
+	if err := InjectGamecard(gc, emu.Mem); err != nil {
+		return err
+	}
+
+	// Cartridge information left by the firmware in two areas of main
+	// RAM: chip ID, header CRC and secure area CRC
+	var crcs [2]uint16
+	var buf [2]byte
+	gc.ReadAt(buf[:], 0x15E)
+	crcs[0] = binary.LittleEndian.Uint16(buf[:])
+	gc.ReadAt(buf[:], 0x6C)
+	crcs[1] = binary.LittleEndian.Uint16(buf[:])
+	chipid := binary.LittleEndian.Uint32(gc.chipid[:])
+	for _, base := range []uint32{0x3FF800, 0x3FFC00} {
+		binary.LittleEndian.PutUint32(ram[base+0x0:], chipid)
+		binary.LittleEndian.PutUint32(ram[base+0x4:], chipid)
+		binary.LittleEndian.PutUint16(ram[base+0x8:], crcs[0])
+		binary.LittleEndian.PutUint16(ram[base+0xA:], crcs[1])
+	}
+	binary.LittleEndian.PutUint16(ram[0x3FF850:], 0x5835)
+	binary.LittleEndian.PutUint16(ram[0x3FFC10:], 0x5835)
+	binary.LittleEndian.PutUint16(ram[0x3FFC30:], 0xFFFF)
+	binary.LittleEndian.PutUint16(ram[0x3FFC40:], 0x0001) // boot from cartridge
+
+	// Copy the user settings to RAM; games read the language (and
+	// touchscreen calibration) from there. If the firmware is already
+	// mapped, they are read through the flash, which applies the language
+	// selected for this session.
+	var fw io.ReaderAt = emu.Hw.Ff
+	if emu.Hw.Ff.f == nil {
+		f, err := os.Open(fwfn)
+		if err != nil {
+			fw = nil
+		} else {
+			defer f.Close()
+			fw = f
+		}
+	}
+	if fw != nil {
+		us, err := ReadFirmwareUserSettings(fw)
+		if err != nil {
+			log.ModEmu.WarnZ("cannot read firmware user settings").Error("err", err).End()
+		} else {
+			copy(emu.Mem.UserSettings(), us)
+		}
+	}
+
+	// Shared wram: map everything to ARM7
+	emu.Hw.Mc.WramCnt.Write8(0, 3)
+
+	// Set post-boot flag to 1
+	nds9.misc.PostFlg.Value = 1
+	nds7.misc7.PostFlg.Value = 1
+
+	// Both screens and 2D/3D engines powered on
+	nds9.misc.PowCnt.Value = 0x820F
+
+	nds9.Irq.Ime.Value = 0x1
+	nds7.Irq.Ime.Value = 0x1
+	nds9.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
+	nds7.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
+
+	// VRAM: map everything in "LCDC mode"
+	for _, cnt := range []*hwio.Reg8{
+		&emu.Hw.Mc.VramCntA, &emu.Hw.Mc.VramCntB, &emu.Hw.Mc.VramCntC,
+		&emu.Hw.Mc.VramCntD, &emu.Hw.Mc.VramCntE, &emu.Hw.Mc.VramCntF,
+		&emu.Hw.Mc.VramCntG, &emu.Hw.Mc.VramCntH, &emu.Hw.Mc.VramCntI,
+	} {
+		cnt.Write8(0, 0x80)
+	}
+
+	// Gamecard: skip directly to key2 status
+	gc.stat = gcStatusKey2
+
+	nds9.Cp15.ConfigureControlReg(0x52078, 0x00FF085)
+
+	// CPUs enter the game in system mode, with the stacks of each mode
+	// set up, and R12/LR pointing to the entry point
+	for i, cpu := range []*arm.Cpu{nds9.Cpu, nds7.Cpu} {
+		st := directBootStacks[i]
+		entry := uint32(cpu.Regs[15])
+		cpu.Cpsr.SetMode(arm.CpuModeIrq, cpu)
+		cpu.SetReg(13, st.irq)
+		cpu.Cpsr.SetMode(arm.CpuModeSupervisor, cpu)
+		cpu.SetReg(13, st.svc)
+		cpu.Cpsr.SetMode(arm.CpuModeSystem, cpu)
+		cpu.SetReg(13, st.sys)
+		cpu.SetReg(12, entry)
+		cpu.SetReg(14, entry)
+	}
 	return nil
 }
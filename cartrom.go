@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+const (
+	cSecureAreaOff  = 0x4000
+	cSecureAreaSize = 0x800
+
+	// Value of the first 8 bytes of a decrypted secure area (two "undefined
+	// instruction" opcodes), which replace the secure area ID
+	cSecureAreaDecryptedId = 0xE7FFDEFF
+)
+
+// cartRom is the ROM of a gamecard, read through an io.ReaderAt (normally a
+// memory-mapped file), so that only the blocks actually accessed by the game
+// are paged in, instead of loading the whole dump in memory.
+//
+// The gamecard emulation works on decrypted ROMs, while raw dumps have the
+// secure area (the first 2KB of the ARM9 binary) encrypted with KEY1. In this
+// case, the secure area is decrypted on first access, and only that chunk is
+// kept in memory.
+type cartRom struct {
+	r          io.ReaderAt
+	key1Tables []byte
+
+	once   sync.Once
+	secure []byte // decrypted secure area (nil if it wasn't encrypted)
+}
+
+func newCartRom(r io.ReaderAt, key1Tables []byte) *cartRom {
+	return &cartRom{r: r, key1Tables: key1Tables}
+}
+
+func (rom *cartRom) ReadAt(buf []byte, off int64) (int, error) {
+	n, err := rom.r.ReadAt(buf, off)
+
+	// Patch the secure area, if this read overlaps it
+	if off < cSecureAreaOff+cSecureAreaSize && off+int64(n) > cSecureAreaOff {
+		rom.once.Do(rom.decryptSecureArea)
+		if rom.secure != nil {
+			if off >= cSecureAreaOff {
+				copy(buf[:n], rom.secure[off-cSecureAreaOff:])
+			} else {
+				copy(buf[cSecureAreaOff-off:n], rom.secure)
+			}
+		}
+	}
+	return n, err
+}
+
+// decryptSecureArea checks whether the secure area is encrypted, by looking
+// for the secure area ID ("encryObj") under the two layers of encryption
+// that protect it, and decrypts it if so.
+func (rom *cartRom) decryptSecureArea() {
+	var gamecode [4]byte
+	if _, err := rom.r.ReadAt(gamecode[:], 0x0C); err != nil {
+		return
+	}
+	data := make([]byte, cSecureAreaSize)
+	if _, err := rom.r.ReadAt(data, cSecureAreaOff); err != nil {
+		return
+	}
+
+	key1 := NewKey1(rom.key1Tables, gamecode[:], false)
+	keyl3 := NewKey1(rom.key1Tables, gamecode[:], true)
+	var id [8]byte
+	key1.DecryptLE(id[:], data[0:8])
+	keyl3.DecryptLE(id[:], id[:])
+	if !bytes.Equal(id[:], []byte("encryObj")) {
+		return
+	}
+
+	for i := 8; i < len(data); i += 8 {
+		keyl3.DecryptLE(data[i:i+8], data[i:i+8])
+	}
+	binary.LittleEndian.PutUint32(data[0:4], cSecureAreaDecryptedId)
+	binary.LittleEndian.PutUint32(data[4:8], cSecureAreaDecryptedId)
+	rom.secure = data
+	modGamecard.InfoZ("secure area decrypted").String("gamecode", string(gamecode[:])).End()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestCartRomSecureArea(t *testing.T) {
+	// Synthetic KEY1 tables (the real ones are in the BIOS)
+	tables := make([]byte, (18+1024)*4)
+	for i := range tables {
+		tables[i] = byte(i*7 + i>>8)
+	}
+
+	plain := make([]byte, 0x8000)
+	copy(plain[0x0C:], "ABCD")
+	for i := cSecureAreaOff; i < len(plain); i++ {
+		plain[i] = byte(i * 13)
+	}
+	binary.LittleEndian.PutUint32(plain[cSecureAreaOff:], cSecureAreaDecryptedId)
+	binary.LittleEndian.PutUint32(plain[cSecureAreaOff+4:], cSecureAreaDecryptedId)
+
+	// Encrypt the secure area as it is found in raw dumps (this is the same
+	// processing done by the gamecard for the secure area command)
+	enc := append([]byte(nil), plain...)
+	secure := enc[cSecureAreaOff : cSecureAreaOff+cSecureAreaSize]
+	copy(secure, "encryObj")
+	keyl3 := NewKey1(tables, []byte("ABCD"), true)
+	for i := 0; i < len(secure); i += 8 {
+		keyl3.EncryptLE(secure[i:i+8], secure[i:i+8])
+	}
+	NewKey1(tables, []byte("ABCD"), false).EncryptLE(secure[0:8], secure[0:8])
+
+	for _, data := range [][]byte{enc, plain} {
+		rom := newCartRom(bytes.NewReader(data), tables)
+		for _, r := range []struct{ off, size int }{
+			{0x3FF0, 0x20},  // across the beginning
+			{0x4100, 0x100}, // within
+			{0x47F8, 0x10},  // across the end
+			{0, 0x8000},     // whole ROM
+			{0x5000, 0x10},  // outside
+		} {
+			buf := make([]byte, r.size)
+			if _, err := rom.ReadAt(buf, int64(r.off)); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, plain[r.off:r.off+r.size]) {
+				t.Errorf("invalid data at %x-%x", r.off, r.off+r.size)
+			}
+		}
+	}
+}
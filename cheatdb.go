@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Cheat databases identify games through the gamecode and a CRC32 of the
+// first 512 bytes of the ROM header (stored inverted).
+type cheatGameID struct {
+	Code string
+	Crc  uint32
+}
+
+func newCheatGameID(header []byte) cheatGameID {
+	return cheatGameID{
+		Code: string(header[0xC:0x10]),
+		Crc:  ^crc32.ChecksumIEEE(header[:0x200]),
+	}
+}
+
+func (id cheatGameID) String() string {
+	return fmt.Sprintf("%s %08X", id.Code, id.Crc)
+}
+
+// A game entry found in a cheat database, before it's matched against
+// the running game.
+type cheatDbGame struct {
+	ID     cheatGameID
+	Title  string
+	Cheats []*Cheat
+}
+
+// LoadCheatDatabase loads the cheats for the game identified by the
+// specified ROM header, from either a R4 "usrcheat.dat" or a DeSmuME
+// "cheats.xml" database. Games are matched by gamecode and header CRC;
+// if no exact match is found, an entry with the same gamecode is accepted
+// as long as it's the only one (CRCs differ between ROM revisions).
+func LoadCheatDatabase(fn string, header []byte) (*CheatList, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	id := newCheatGameID(header)
+	var games []cheatDbGame
+	if bytes.HasPrefix(data, usrcheatMagic) {
+		games, err = parseUsrcheat(data, id.Code)
+	} else {
+		games, err = parseCheatsXml(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn, err)
+	}
+
+	var match *cheatDbGame
+	ncode := 0
+	for i := range games {
+		g := &games[i]
+		if g.ID == id {
+			match = g
+			break
+		}
+		if g.ID.Code == id.Code {
+			ncode++
+			if ncode == 1 {
+				match = g
+			}
+		}
+	}
+	if match == nil || (match.ID != id && ncode > 1) {
+		return nil, fmt.Errorf("no cheats found for game %v", id)
+	}
+	if match.ID != id {
+		modCheat.WarnZ("cheat database CRC mismatch, matching by gamecode").
+			Stringer("game", id).Stringer("db", match.ID).End()
+	}
+
+	modCheat.InfoZ("cheats loaded").String("title", match.Title).Int("num", len(match.Cheats)).End()
+	return &CheatList{Cheats: match.Cheats}, nil
+}
+
+/************************************************************
+ * R4 usrcheat.dat
+ ************************************************************/
+
+var usrcheatMagic = []byte("R4 CheatCode")
+
+const (
+	cUsrcheatIndexOffset = 0x100
+
+	usrcheatFolder    = 1 << 28
+	usrcheatEnabled   = 1 << 24
+	usrcheatCountMask = 0xFFFFFF
+)
+
+// Read a NUL-terminated string at the specified offset, returning the
+// offset just past the terminator.
+func usrcheatString(data []byte, off int) (string, int, error) {
+	if off >= len(data) {
+		return "", 0, errors.New("truncated string")
+	}
+	end := bytes.IndexByte(data[off:], 0)
+	if end < 0 {
+		return "", 0, errors.New("unterminated string")
+	}
+	return string(data[off : off+end]), off + end + 1, nil
+}
+
+func usrcheatWord(data []byte, off int) (uint32, error) {
+	if off+4 > len(data) {
+		return 0, errors.New("truncated entry")
+	}
+	return binary.LittleEndian.Uint32(data[off:]), nil
+}
+
+// parseUsrcheat parses a usrcheat.dat database. As the file is usually
+// large, only games matching the specified gamecode are decoded.
+//
+// The file starts with a header, followed at 0x100 by an index of 16-byte
+// entries (gamecode, CRC, 64-bit offset of the game data), terminated by an
+// entry with a zero offset. Each game data block contains the title, the
+// number of entries plus master codes (36 bytes), and a list of entries,
+// which are either cheats or folders containing cheats:
+//
+//	cheat:  flags+size, name, note, (align 4), numwords, codes...
+//	folder: flags+count, name, note, (align 4), followed by count cheats
+func parseUsrcheat(data []byte, code string) ([]cheatDbGame, error) {
+	var games []cheatDbGame
+
+	for idx := cUsrcheatIndexOffset; idx+16 <= len(data); idx += 16 {
+		addr := binary.LittleEndian.Uint64(data[idx+8:])
+		if addr == 0 {
+			break
+		}
+		if string(data[idx:idx+4]) != code {
+			continue
+		}
+		id := cheatGameID{
+			Code: code,
+			Crc:  binary.LittleEndian.Uint32(data[idx+4:]),
+		}
+		if addr >= uint64(len(data)) {
+			return nil, fmt.Errorf("game %v: invalid offset", id)
+		}
+
+		// The game data ends where the next one begins
+		end := uint64(len(data))
+		if idx+32 <= len(data) {
+			if next := binary.LittleEndian.Uint64(data[idx+24:]); next > addr && next < end {
+				end = next
+			}
+		}
+
+		g, err := parseUsrcheatGame(data[:end], int(addr))
+		if err != nil {
+			return nil, fmt.Errorf("game %v: %v", id, err)
+		}
+		g.ID = id
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+func parseUsrcheatGame(data []byte, off int) (cheatDbGame, error) {
+	var g cheatDbGame
+	var err error
+
+	if g.Title, off, err = usrcheatString(data, off); err != nil {
+		return g, err
+	}
+	off = (off + 3) &^ 3
+
+	// Skip number of entries and master codes
+	off += 36
+
+	folder := ""
+	infolder := 0
+	for off < len(data) {
+		flags, err := usrcheatWord(data, off)
+		if err != nil {
+			return g, err
+		}
+		var name, note string
+		if name, off, err = usrcheatString(data, off+4); err != nil {
+			return g, err
+		}
+		if note, off, err = usrcheatString(data, off); err != nil {
+			return g, err
+		}
+		off = (off + 3) &^ 3
+
+		if flags&usrcheatFolder != 0 {
+			folder, infolder = name, int(flags&usrcheatCountMask)
+			continue
+		}
+
+		nwords, err := usrcheatWord(data, off)
+		if err != nil {
+			return g, err
+		}
+		off += 4
+		if off+int(nwords)*4 > len(data) {
+			return g, errors.New("truncated cheat codes")
+		}
+		codes := make([]uint32, nwords&^1)
+		for i := range codes {
+			codes[i] = binary.LittleEndian.Uint32(data[off+i*4:])
+		}
+		off += int(nwords) * 4
+
+		if infolder > 0 {
+			name = folder + ": " + name
+			infolder--
+		}
+		g.Cheats = append(g.Cheats, &Cheat{
+			Name:    name,
+			Note:    note,
+			Codes:   codes,
+			Enabled: flags&usrcheatEnabled != 0,
+		})
+	}
+	return g, nil
+}
+
+/************************************************************
+ * DeSmuME / R4CCE cheats.xml
+ ************************************************************/
+
+type xmlCheat struct {
+	Name  string `xml:"name"`
+	Note  string `xml:"note"`
+	Codes string `xml:"codes"`
+}
+
+type xmlCheatFolder struct {
+	Name    string           `xml:"name"`
+	Cheats  []xmlCheat       `xml:"cheat"`
+	Folders []xmlCheatFolder `xml:"folder"`
+}
+
+type xmlCheatGame struct {
+	Name   string `xml:"name"`
+	GameID string `xml:"gameid"`
+	xmlCheatFolder
+}
+
+type xmlCheatList struct {
+	Games []xmlCheatGame `xml:"game"`
+}
+
+func (f *xmlCheatFolder) collect(prefix string, out []*Cheat) ([]*Cheat, error) {
+	for _, c := range f.Cheats {
+		codes, err := ParseARCode(c.Codes)
+		if err != nil {
+			return nil, fmt.Errorf("cheat %q: %v", c.Name, err)
+		}
+		out = append(out, &Cheat{
+			Name:  prefix + strings.TrimSpace(c.Name),
+			Note:  strings.TrimSpace(c.Note),
+			Codes: codes,
+		})
+	}
+	for i := range f.Folders {
+		sub := &f.Folders[i]
+		var err error
+		if out, err = sub.collect(prefix+strings.TrimSpace(sub.Name)+": ", out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// parseCheatsXml parses a cheats.xml database, where each game is
+// identified by a "<gameid>CODE CRC32</gameid>" tag.
+func parseCheatsXml(data []byte) ([]cheatDbGame, error) {
+	var list xmlCheatList
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	var games []cheatDbGame
+	for i := range list.Games {
+		xg := &list.Games[i]
+		fields := strings.Fields(xg.GameID)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var id cheatGameID
+		id.Code = fields[0]
+		if len(fields) > 1 {
+			crc, err := strconv.ParseUint(fields[1], 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("game %q: invalid CRC: %q", xg.Name, fields[1])
+			}
+			id.Crc = uint32(crc)
+		}
+
+		cheats, err := xg.collect("", nil)
+		if err != nil {
+			return nil, fmt.Errorf("game %q: %v", xg.Name, err)
+		}
+		games = append(games, cheatDbGame{
+			ID:     id,
+			Title:  strings.TrimSpace(xg.Name),
+			Cheats: cheats,
+		})
+	}
+	return games, nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"strconv"
+	"strings"
+)
+
+var modCheat = log.NewModule("cheat")
+
+// Cheat is a named Action Replay DS code, that can be enabled or disabled
+// at runtime.
+type Cheat struct {
+	Name    string
+	Note    string
+	Codes   []uint32 // pairs of 32-bit words, as displayed by AR (XXXXXXXX YYYYYYYY)
+	Enabled bool
+}
+
+// CheatList is the list of cheats available for the current game. Enabled
+// cheats are executed once per frame, like the real Action Replay does
+// at each VBlank.
+type CheatList struct {
+	Cheats []*Cheat
+}
+
+// Enable enables the cheats selected by the specified comma-separated list
+// of indices (as printed by Dump) or names.
+func (cl *CheatList) Enable(sel string) error {
+	for _, s := range strings.Split(sel, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		found := false
+		if idx, err := strconv.Atoi(s); err == nil {
+			if idx < 0 || idx >= len(cl.Cheats) {
+				return fmt.Errorf("cheat index out of range: %d", idx)
+			}
+			cl.Cheats[idx].Enabled = true
+			found = true
+		} else {
+			for _, c := range cl.Cheats {
+				if strings.EqualFold(c.Name, s) {
+					c.Enabled = true
+					found = true
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("cheat not found: %q", s)
+		}
+	}
+	return nil
+}
+
+// Toggle switches the specified cheat (by index, as printed by Dump) on or
+// off, and returns its new state.
+func (cl *CheatList) Toggle(idx int) (bool, error) {
+	if idx < 0 || idx >= len(cl.Cheats) {
+		return false, fmt.Errorf("cheat index out of range: %d", idx)
+	}
+	c := cl.Cheats[idx]
+	c.Enabled = !c.Enabled
+	modCheat.InfoZ("cheat toggled").String("name", c.Name).Bool("enabled", c.Enabled).End()
+	return c.Enabled, nil
+}
+
+// Dump returns a human-readable list of the available cheats
+func (cl *CheatList) Dump() string {
+	var sb strings.Builder
+	for i, c := range cl.Cheats {
+		mark := " "
+		if c.Enabled {
+			mark = "*"
+		}
+		fmt.Fprintf(&sb, "%s %3d: %s\n", mark, i, c.Name)
+	}
+	return sb.String()
+}
+
+// Run executes all the enabled cheats
+func (cl *CheatList) Run(bus *hwio.Table) {
+	for _, c := range cl.Cheats {
+		if c.Enabled {
+			runARCode(bus, c.Codes)
+		}
+	}
+}
+
+// ParseARCode parses an Action Replay code written as a sequence of
+// hexadecimal 32-bit words, separated by spaces or newlines.
+func ParseARCode(s string) ([]uint32, error) {
+	var codes []uint32
+	for _, w := range strings.Fields(s) {
+		v, err := strconv.ParseUint(w, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AR code word: %q", w)
+		}
+		codes = append(codes, uint32(v))
+	}
+	if len(codes)%2 != 0 {
+		return nil, fmt.Errorf("AR code has an odd number of words")
+	}
+	return codes, nil
+}
+
+// runARCode interprets an Action Replay DS code. Conditionals can be nested
+// (the state of outer blocks is kept in a bit stack), while only one level
+// of loop (C0/D1/D2) is supported, as on the real device.
+func runARCode(bus *hwio.Table, code []uint32) {
+	var offset, data uint32
+	var cond, condstack uint32 = 1, 0
+	var loopstart, loopcount int
+	var loopcond, loopcondstack uint32 = 1, 0
+	var counter uint32
+
+	for pc := 0; pc+1 < len(code); {
+		a, b := code[pc], code[pc+1]
+		pc += 2
+
+		op := a >> 28
+		if op == 0xC || op == 0xD {
+			op = a >> 24
+		} else {
+			op <<= 4
+		}
+		addr := a & 0x0FFFFFFF
+
+		if cond == 0 {
+			// Inside a false conditional block: only track block
+			// nesting and skip inline data.
+			switch {
+			case op >= 0x30 && op <= 0xA0, op == 0xC5:
+				condstack = condstack<<1 | cond
+			case op == 0xD0:
+				cond, condstack = condstack&1, condstack>>1
+			case op == 0xD1, op == 0xD2:
+				if loopcount > 0 {
+					loopcount--
+					pc = loopstart
+				} else if op == 0xD2 {
+					offset, data, cond, condstack = 0, 0, 1, 0
+				} else {
+					cond, condstack = loopcond, loopcondstack
+				}
+			case op == 0xE0:
+				pc += int((b+7)&^7) / 4
+			}
+			continue
+		}
+
+		switch op {
+		case 0x00:
+			bus.Write32(addr+offset, b)
+		case 0x10:
+			bus.Write16(addr+offset, uint16(b))
+		case 0x20:
+			bus.Write8(addr+offset, uint8(b))
+
+		case 0x30, 0x40, 0x50, 0x60:
+			if addr == 0 {
+				addr = offset
+			}
+			val := bus.Read32(addr)
+			condstack = condstack<<1 | cond
+			cond = bool2u32(arCompare(op, b, val))
+
+		case 0x70, 0x80, 0x90, 0xA0:
+			if addr == 0 {
+				addr = offset
+			}
+			val := uint32(^uint16(b>>16) & bus.Read16(addr))
+			condstack = condstack<<1 | cond
+			cond = bool2u32(arCompare(op-0x40, b&0xFFFF, val))
+
+		case 0xB0:
+			offset = bus.Read32(addr + offset)
+
+		case 0xC0:
+			loopstart, loopcount = pc, int(b)
+			loopcond, loopcondstack = cond, condstack
+		case 0xC5:
+			counter++
+			condstack = condstack<<1 | cond
+			cond = bool2u32(counter&(b&0xFFFF) == b>>16)
+		case 0xC6:
+			bus.Write32(b, offset)
+
+		case 0xD0:
+			cond, condstack = condstack&1, condstack>>1
+		case 0xD1, 0xD2:
+			if loopcount > 0 {
+				loopcount--
+				pc = loopstart
+			} else if op == 0xD2 {
+				offset, data, cond, condstack = 0, 0, 1, 0
+			} else {
+				cond, condstack = loopcond, loopcondstack
+			}
+		case 0xD3:
+			offset = b
+		case 0xD4:
+			data += b
+		case 0xD5:
+			data = b
+		case 0xD6:
+			bus.Write32(b+offset, data)
+			offset += 4
+		case 0xD7:
+			bus.Write16(b+offset, uint16(data))
+			offset += 2
+		case 0xD8:
+			bus.Write8(b+offset, uint8(data))
+			offset++
+		case 0xD9:
+			data = bus.Read32(b + offset)
+		case 0xDA:
+			data = uint32(bus.Read16(b + offset))
+		case 0xDB:
+			data = uint32(bus.Read8(b + offset))
+		case 0xDC:
+			offset += b
+
+		case 0xE0:
+			// Copy b bytes of inline data following this line
+			dst := addr + offset
+			for i := uint32(0); i < b; i++ {
+				w := pc + int(i/4)
+				if w >= len(code) {
+					break
+				}
+				bus.Write8(dst+i, uint8(code[w]>>(8*(i&3))))
+			}
+			pc += int((b+7)&^7) / 4
+		case 0xF0:
+			for i := uint32(0); i < b; i++ {
+				bus.Write8(addr+i, bus.Read8(offset+i))
+			}
+
+		default:
+			modCheat.WarnZ("unsupported AR code").Hex32("a", a).Hex32("b", b).End()
+			return
+		}
+	}
+}
+
+func arCompare(op uint32, b, val uint32) bool {
+	switch op {
+	case 0x30:
+		return b > val
+	case 0x40:
+		return b < val
+	case 0x50:
+		return b == val
+	default:
+		return b != val
+	}
+}
+
+func bool2u32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	log "ndsemu/emu/logger"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CompatReport summarizes how a game behaved during a session, to help
+// filing structured bug reports and triaging compatibility issues. Reports
+// are only written if requested (-compat-report), and never leave the
+// local machine.
+type CompatReport struct {
+	GameCode string    `json:"game_code"`
+	Title    string    `json:"title"`
+	Rom      string    `json:"rom"`
+	Date     time.Time `json:"date"`
+	Jit      bool      `json:"jit"`
+
+	// The game is considered booted when the ARM9 is found running its
+	// main binary (as loaded from the ROM) at the end of a frame.
+	Booted    bool `json:"booted"`
+	BootFrame int  `json:"boot_frame,omitempty"`
+
+	Frames int     `json:"frames"`
+	AvgFps float64 `json:"avg_fps"`
+
+	// How the session ended: "quit", "interrupt" (SIGINT), "poweroff",
+	// "crash" or "fatal". Error
+	// is the panic or fatal error message.
+	Exit  string `json:"exit"`
+	Error string `json:"error,omitempty"`
+
+	// Warnings and errors logged during the session (including
+	// unimplemented features), with the number of occurrences
+	Warnings map[string]int `json:"warnings"`
+}
+
+// CompatTracker collects the data of a CompatReport while the emulator runs
+type CompatTracker struct {
+	report CompatReport
+
+	arm9start, arm9end uint32
+	lastFrame          time.Time
+	runTime            time.Duration
+}
+
+// NewCompatTracker starts tracking the session of the game currently inserted
+// in slot 1. It enables the collection of warning statistics in the logger.
+func NewCompatTracker(emu *NDSEmulator, romfn string, title string) *CompatTracker {
+	ct := &CompatTracker{}
+	ct.report.Rom = filepath.Base(romfn)
+	ct.report.Title = title
+	ct.report.Date = time.Now()
+	ct.report.Jit = emu.jit
+
+	var ch CartHeader
+	if err := ch.Read(io.NewSectionReader(emu.Hw.Gc, 0, 0x200)); err == nil {
+		ct.report.GameCode = strings.TrimRight(string(ch.Gamecode[:]), "\x00")
+		ct.arm9start, ct.arm9end = ch.Arm9Ram, ch.Arm9Ram+ch.Arm9Size
+	}
+
+	log.EnableWarningStats()
+	return ct
+}
+
+// Frame must be called after each emulated frame
+func (ct *CompatTracker) Frame(emu *NDSEmulator) {
+	now := time.Now()
+	// Ignore long gaps between frames (pauses, debugger...) for FPS
+	if dt := now.Sub(ct.lastFrame); !ct.lastFrame.IsZero() && dt < time.Second {
+		ct.runTime += dt
+	}
+	ct.lastFrame = now
+	ct.report.Frames++
+
+	if !ct.report.Booted {
+		if pc := nds9.GetPC(); pc >= ct.arm9start && pc < ct.arm9end {
+			ct.report.Booted = true
+			ct.report.BootFrame = emu.framecount
+		}
+	}
+}
+
+// gameCodeFileName returns the game code in a form usable as part of a file
+// name (homebrew often has garbage or zeros there)
+func (r *CompatReport) gameCodeFileName() string {
+	code := strings.Map(func(c rune) rune {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return -1
+	}, r.GameCode)
+	if len(code) != 4 {
+		return "unknown"
+	}
+	return code
+}
+
+// Write finalizes the report and writes it into the specified directory, in
+// a file named after the game code and the session date. It returns the path
+// of the file.
+func (ct *CompatTracker) Write(dir string, exit string, msg string) (string, error) {
+	r := &ct.report
+	r.Exit, r.Error = exit, msg
+	if ct.runTime > 0 {
+		r.AvgFps = float64(r.Frames) / ct.runTime.Seconds()
+	}
+	r.Warnings = log.WarningStats()
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	fn := filepath.Join(dir, fmt.Sprintf("%s-%s.json", r.gameCodeFileName(), r.Date.Format("20060102-150405")))
+	if err := ioutil.WriteFile(fn, append(data, '\n'), 0666); err != nil {
+		return "", err
+	}
+	return fn, nil
+}
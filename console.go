@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConsoleType is the model of the console, as stored in the firmware header
+// (byte 0x1D). The firmware and a few games branch on it, for instance to
+// use the backlight levels of the DS Lite.
+type ConsoleType uint8
+
+const (
+	ConsoleDS       ConsoleType = 0xFF
+	ConsoleDSLite   ConsoleType = 0x20
+	ConsoleDSi      ConsoleType = 0x57 // DSi running DS software (NTR mode)
+	ConsoleIQue     ConsoleType = 0x43
+	ConsoleIQueLite ConsoleType = 0x63
+)
+
+var consoleTypeNames = []struct {
+	name string
+	typ  ConsoleType
+}{
+	{"ds", ConsoleDS},
+	{"dslite", ConsoleDSLite},
+	{"dsi", ConsoleDSi},
+	{"ique", ConsoleIQue},
+	{"iquelite", ConsoleIQueLite},
+}
+
+func (t ConsoleType) String() string {
+	for _, n := range consoleTypeNames {
+		if n.typ == t {
+			return n.name
+		}
+	}
+	return fmt.Sprintf("console%02x", uint8(t))
+}
+
+// Lite returns true if the console has the power management of the DS Lite
+// (backlight levels, see HwPowerMan), which the DSi emulates in NTR mode.
+func (t ConsoleType) Lite() bool {
+	return t == ConsoleDSLite || t == ConsoleDSi || t == ConsoleIQueLite
+}
+
+// ParseConsoleType parses the name of a console type (eg: "dslite")
+func ParseConsoleType(s string) (ConsoleType, error) {
+	var names []string
+	for _, n := range consoleTypeNames {
+		if strings.ToLower(s) == n.name {
+			return n.typ, nil
+		}
+		names = append(names, n.name)
+	}
+	return 0, fmt.Errorf("invalid console type: %q (use one of: %s)", s, strings.Join(names, ", "))
+}
+
+// FirmwareConsoleType returns the console type in the header of the
+// specified firmware file
+func FirmwareConsoleType(fn string) (ConsoleType, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var buf [1]byte
+	if _, err := f.ReadAt(buf[:], 0x1D); err != nil {
+		return 0, err
+	}
+	return ConsoleType(buf[0]), nil
+}
+
+// SetFirmwareConsoleType changes the console type in the header of the
+// specified firmware file. The byte is not covered by any CRC.
+func SetFirmwareConsoleType(fn string, t ConsoleType) error {
+	f, err := os.OpenFile(fn, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteAt([]byte{uint8(t)}, 0x1D); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Region is the set of languages supported by the console (a bitmask with
+// one bit per Language), which is stored in the extended user settings of
+// newer firmwares. The firmware menu offers only these languages, and
+// Chinese and Korean games check that their language is supported.
+type Region uint16
+
+const (
+	RegionWorld Region = 0x3F // japanese to spanish
+	RegionChina Region = 0x7E // iQue: chinese instead of japanese
+	RegionKorea Region = 0xBE // korean instead of japanese
+)
+
+var regionNames = map[string]Region{
+	"world": RegionWorld,
+	"china": RegionChina,
+	"korea": RegionKorea,
+}
+
+func (r Region) String() string {
+	for name, reg := range regionNames {
+		if reg == r {
+			return name
+		}
+	}
+	return fmt.Sprintf("%#02x", uint16(r))
+}
+
+// Supports returns true if the language is supported in the region
+func (r Region) Supports(lang Language) bool {
+	return r&(1<<lang) != 0
+}
+
+// ParseRegion parses the name of a region (world, china, korea), or a
+// numeric bitmask of supported languages
+func ParseRegion(s string) (Region, error) {
+	if r, ok := regionNames[strings.ToLower(s)]; ok {
+		return r, nil
+	}
+	mask, err := strconv.ParseUint(s, 0, 8)
+	if err != nil || mask == 0 {
+		return 0, fmt.Errorf("invalid region: %q (use world, china, korea, or a mask of languages)", s)
+	}
+	return Region(mask), nil
+}
+
+// SetConsoleType selects the model of the console, as seen by the devices
+// that differ between models. The console type in the firmware header must
+// be changed separately (see SetFirmwareConsoleType).
+func (emu *NDSEmulator) SetConsoleType(t ConsoleType) {
+	emu.consoleType = t
+	emu.Hw.Pow.Lite = t.Lite()
+}
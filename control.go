@@ -0,0 +1,353 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	log "ndsemu/emu/logger"
+	"net"
+	"os"
+	"strings"
+)
+
+var modControl = log.NewModule("control")
+
+// Control is a remote-control server listening on a unix socket, meant for
+// scripts and test infrastructure that need to drive a running emulator.
+// While the bridge (see Bridge) exposes the internal state for debugging
+// tools, Control offers what a user sitting in front of the emulator can do.
+//
+// It uses the same transport as the bridge (see rpcServer): newline-delimited
+// JSON. Each request has an optional "id" (echoed in the reply), a "method"
+// (the command), and command-specific parameters; each reply contains either
+// a "result" or an "error":
+//
+//	{"id":1, "method":"press", "buttons":["a","start"], "frames":5}
+//	{"id":1, "result":true}
+//
+// Supported commands:
+//
+//	pause                       pause emulation (requests are still served)
+//	resume                      resume emulation
+//	savestate  file             save a savestate
+//	loadstate  file             load a savestate
+//	screenshot file             save the last frame as PNG
+//	filter     spec             change the output filters (same syntax as -filter)
+//	press      buttons, frames  hold buttons for some frames (default: 1)
+//	read       cpu, addr, size  read memory (hex-encoded data, no side effects;
+//	                            see peekRange)
+//	eject      slot             remove the cartridge from slot 1 or 2
+//	language   lang             switch the firmware language (see SetLanguage)
+//	cheats                      list the cheats loaded for the game (-cheats)
+//	cheat      index            toggle a cheat (returns its new state)
+//	freeze     spec             freeze a memory location (same syntax as -freeze)
+//	unfreeze   addr             stop freezing a memory location
+//	freezes                     list the frozen locations
+//	saveread   addr, size       read the save memory (hex-encoded data)
+//	savewrite  addr, data       write the save memory (hex-encoded data)
+//	saveexport [file]           decode the save memory with the save codec of
+//	                            the game (see SaveCodec), returning the value
+//	                            or writing it as JSON into file
+//	saveimport value | file     encode a value (or the JSON in file) into the
+//	                            save memory with the save codec of the game
+//	stop                        shut down the emulator cleanly
+//
+// The loadstate, screenshot, filter and stop commands need the frontend, and
+// are only available when it configures the corresponding hooks; saveexport
+// and saveimport need the directory of the save codecs. Requests are
+// executed on the emulation thread between frames (see Poll).
+type Control struct {
+	// Hooks to the frontend, invoked by the corresponding commands; commands
+	// whose hook is not set are not available
+	Stop       func()
+	Screenshot func(fn string) error
+	LoadState  func(fn string) error
+	SetFilters func(spec string) error
+
+	// Directory of the save codecs (see SaveCodecFile)
+	SaveCodecs string
+
+	srv *rpcServer
+
+	paused      bool
+	pressed     Buttons
+	pressFrames int
+}
+
+type controlRequest struct {
+	Method  string   `json:"method"`
+	File    string   `json:"file,omitempty"`
+	Buttons []string `json:"buttons,omitempty"`
+	Frames  int      `json:"frames,omitempty"`
+	Cpu     string   `json:"cpu,omitempty"`
+	Addr    uint32   `json:"addr,omitempty"`
+	Size    int      `json:"size,omitempty"`
+	Slot    int      `json:"slot,omitempty"`
+	Data    string   `json:"data,omitempty"`
+	Lang    string   `json:"lang,omitempty"`
+	Index   int      `json:"index,omitempty"`
+	Spec    string   `json:"spec,omitempty"`
+
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// controlCheat is an entry of the reply to the "cheats" command
+type controlCheat struct {
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// NewControl starts listening for control connections on the unix socket at
+// the specified path. A stale socket left by a previous run is removed.
+func NewControl(path string) (*Control, error) {
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Control{srv: newRpcServer(ln, modControl)}, nil
+}
+
+// Close stops listening, removing the socket
+func (c *Control) Close() {
+	c.srv.Close()
+}
+
+// Paused reports whether emulation was paused with the "pause" command. While
+// paused, the caller should keep calling Poll (with a small sleep), but not
+// produce new frames.
+func (c *Control) Paused() bool {
+	return c.paused
+}
+
+// Buttons returns the buttons held through the "press" command. It must be
+// called once per emulated frame.
+func (c *Control) Buttons() Buttons {
+	if c.pressFrames == 0 {
+		return 0
+	}
+	c.pressFrames--
+	return c.pressed
+}
+
+// Poll executes all the pending requests. It must be called periodically
+// by the emulation thread.
+func (c *Control) Poll(emu *NDSEmulator) {
+	c.srv.poll(func() interface{} { return new(controlRequest) },
+		func(req interface{}) (interface{}, error) { return c.handle(emu, req.(*controlRequest)) })
+}
+
+func (c *Control) handle(emu *NDSEmulator, req *controlRequest) (interface{}, error) {
+	if hook, found := map[string]bool{
+		"stop":       c.Stop != nil,
+		"screenshot": c.Screenshot != nil,
+		"loadstate":  c.LoadState != nil,
+		"filter":     c.SetFilters != nil,
+		"saveexport": c.SaveCodecs != "",
+		"saveimport": c.SaveCodecs != "",
+	}[req.Method]; found && !hook {
+		return nil, fmt.Errorf("invalid method: %q", req.Method)
+	}
+
+	switch req.Method {
+	case "pause":
+		c.paused = true
+		return true, nil
+
+	case "resume":
+		c.paused = false
+		return true, nil
+
+	case "savestate":
+		if err := emu.SaveStateFile(req.File); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "loadstate":
+		if err := c.LoadState(req.File); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "screenshot":
+		if err := c.Screenshot(req.File); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "filter":
+		if err := c.SetFilters(req.Spec); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "press":
+		var btn Buttons
+		for _, name := range req.Buttons {
+			b, found := buttonNames[strings.ToLower(name)]
+			if !found {
+				return nil, fmt.Errorf("invalid button: %q", name)
+			}
+			btn |= b
+		}
+		if req.Frames < 0 {
+			return nil, fmt.Errorf("invalid number of frames: %d", req.Frames)
+		}
+		c.pressed, c.pressFrames = btn, req.Frames
+		if c.pressFrames == 0 {
+			c.pressFrames = 1
+		}
+		return true, nil
+
+	case "read":
+		cpu, err := bridgeCpu(req.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		if req.Size <= 0 || req.Size > cBridgeMaxData {
+			return nil, fmt.Errorf("invalid size: %d", req.Size)
+		}
+		return peekRange(cpu, req.Addr, req.Size), nil
+
+	case "saveread":
+		if req.Size <= 0 || req.Size > cBridgeMaxData {
+			return nil, fmt.Errorf("invalid size: %d", req.Size)
+		}
+		data := make([]byte, req.Size)
+		n, err := emu.Hw.Bkp.ReadAt(data, int64(req.Addr))
+		if n == 0 && err != nil {
+			return nil, err
+		}
+		return map[string]string{"data": hex.EncodeToString(data[:n])}, nil
+
+	case "savewrite":
+		data, err := hex.DecodeString(req.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %v", err)
+		}
+		if len(data) > cBridgeMaxData {
+			return nil, fmt.Errorf("invalid size: %d", len(data))
+		}
+		// Writes must not grow the save memory past the size detected
+		// from the game, or create it before the game accesses it
+		if size := emu.Hw.Bkp.Size(); int64(req.Addr)+int64(len(data)) > int64(size) {
+			return nil, fmt.Errorf("write beyond the end of the save memory (%d bytes)", size)
+		}
+		if _, err := emu.Hw.Bkp.WriteAt(data, int64(req.Addr)); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "saveexport":
+		codec, err := c.loadSaveCodec(emu)
+		if err != nil {
+			return nil, err
+		}
+		defer codec.Close()
+		v, err := codec.Decode()
+		if err != nil || req.File == "" {
+			return v, err
+		}
+		data, err := json.MarshalIndent(v, "", "\t")
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(req.File, data, 0666); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "saveimport":
+		data := []byte(req.Value)
+		if req.File != "" {
+			var err error
+			if data, err = ioutil.ReadFile(req.File); err != nil {
+				return nil, err
+			}
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("invalid value: %v", err)
+		}
+		codec, err := c.loadSaveCodec(emu)
+		if err != nil {
+			return nil, err
+		}
+		defer codec.Close()
+		if err := codec.Encode(v); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "eject":
+		if err := emu.RemoveCart(req.Slot); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "language":
+		lang, err := ParseLanguage(req.Lang)
+		if err != nil {
+			return nil, err
+		}
+		if err := emu.SetLanguage(lang); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "cheats":
+		list := []controlCheat{}
+		if emu.Cheats != nil {
+			for i, ch := range emu.Cheats.Cheats {
+				list = append(list, controlCheat{i, ch.Name, ch.Enabled})
+			}
+		}
+		return list, nil
+
+	case "cheat":
+		if emu.Cheats == nil {
+			return nil, fmt.Errorf("no cheats loaded")
+		}
+		return emu.Cheats.Toggle(req.Index)
+
+	case "freeze":
+		fe, err := ParseFreezeEntry(req.Spec)
+		if err != nil {
+			return nil, err
+		}
+		emu.Freeze.Add(fe)
+		return true, nil
+
+	case "unfreeze":
+		if !emu.Freeze.Remove(req.Addr) {
+			return nil, fmt.Errorf("address not frozen: %08x", req.Addr)
+		}
+		return true, nil
+
+	case "freezes":
+		list := []string{}
+		for _, fe := range emu.Freeze.Entries() {
+			list = append(list, fe.String())
+		}
+		return list, nil
+
+	case "stop":
+		c.Stop()
+		return true, nil
+
+	default:
+		return nil, fmt.Errorf("invalid method: %q", req.Method)
+	}
+}
+
+// loadSaveCodec loads the save codec for the running game
+func (c *Control) loadSaveCodec(emu *NDSEmulator) (*SaveCodec, error) {
+	var gamecode [4]byte
+	emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+	return LoadSaveCodec(SaveCodecFile(c.SaveCodecs, string(gamecode[:])), emu.Hw.Bkp)
+}
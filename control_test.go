@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestControl(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctl.sock")
+	c, err := NewControl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Serve requests like the main loop does, between frames
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				c.Poll(nil)
+			}
+		}
+	}()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	call := func(req string) rpcReply {
+		if _, err := conn.Write([]byte(req + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		var reply rpcReply
+		if err := dec.Decode(&reply); err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+
+	if r := call(`{"id":1, "method":"pause"}`); r.Error != "" || r.ID != 1.0 {
+		t.Errorf("pause: %+v", r)
+	}
+	if !c.Paused() {
+		t.Errorf("emulation not paused")
+	}
+	call(`{"method":"resume"}`)
+	if c.Paused() {
+		t.Errorf("emulation not resumed")
+	}
+
+	if r := call(`{"method":"press", "buttons":["a","start"], "frames":2}`); r.Error != "" {
+		t.Errorf("press: %+v", r)
+	}
+	for i, exp := range []Buttons{ButtonA | ButtonStart, ButtonA | ButtonStart, 0} {
+		if btn := c.Buttons(); btn != exp {
+			t.Errorf("frame %d: buttons %x, want %x", i, btn, exp)
+		}
+	}
+
+	for _, req := range []string{
+		`{"method":"press", "buttons":["z"]}`,
+		`{"method":"loadstate", "file":"x.sav"}`,
+		`{"method":"stop"}`,
+		`{"method":"dance"}`,
+	} {
+		if r := call(req); r.Error == "" {
+			t.Errorf("%s: error expected", req)
+		}
+	}
+}
+
+func TestPeekRange(t *testing.T) {
+	newTestEmulator(t)
+
+	// Send a word from the ARM7 to the ARM9 through the IPC FIFO
+	nds7.Bus.Write16(0x4000184, 0x8000)
+	nds9.Bus.Write16(0x4000184, 0x8000)
+	nds7.Bus.Write32(0x4000188, 0x11223344)
+
+	// The FIFO register holds the last value read, not the front of the
+	// FIFO, so it's reported as read-sensitive
+	res := peekRange(nds9.Cpu, 0x40FFFFC, 12)
+	if data, _ := res["data"].(string); len(data) != 24 {
+		t.Errorf("invalid data: %v", res["data"])
+	}
+	sens, _ := res["sensitive"].([]int)
+	if len(sens) != 4 || sens[0] != 4 || sens[3] != 7 {
+		t.Errorf("invalid read-sensitive offsets: %v", res["sensitive"])
+	}
+
+	// The FIFO was not popped
+	if v := nds9.Bus.Read32(0x4100000); v != 0x11223344 {
+		t.Errorf("FIFO popped by peek: read %08x", v)
+	}
+}
+
+func TestControlCheats(t *testing.T) {
+	emu := newTestEmulator(t)
+	c := &Control{}
+	if _, err := c.handle(emu, &controlRequest{Method: "cheat"}); err == nil {
+		t.Errorf("cheat toggled without cheats")
+	}
+
+	emu.Cheats = &CheatList{Cheats: []*Cheat{
+		{Name: "Infinite HP", Codes: []uint32{0x02000000, 99}},
+		{Name: "Max money", Codes: []uint32{0x02000004, 9999}},
+	}}
+	for i, exp := range []bool{true, false} {
+		res, err := c.handle(emu, &controlRequest{Method: "cheat", Index: 1})
+		if err != nil || res != exp {
+			t.Errorf("toggle %d: got %v (%v), want %v", i, res, err, exp)
+		}
+	}
+	if _, err := c.handle(emu, &controlRequest{Method: "cheat", Index: 2}); err == nil {
+		t.Errorf("out of range cheat toggled")
+	}
+
+	c.handle(emu, &controlRequest{Method: "cheat", Index: 0})
+	res, err := c.handle(emu, &controlRequest{Method: "cheats"})
+	list, _ := res.([]controlCheat)
+	if err != nil || len(list) != 2 || !list[0].Enabled || list[1].Enabled || list[1].Name != "Max money" {
+		t.Errorf("invalid cheat list: %+v (%v)", res, err)
+	}
+
+	// The enabled cheat is run at the end of the frame
+	runFrames(emu, 1)
+	if v := nds9.Bus.Read32(0x02000000); v != 99 {
+		t.Errorf("cheat not applied: %d", v)
+	}
+}
+
+func TestControlFreeze(t *testing.T) {
+	emu := newTestEmulator(t)
+	c := &Control{}
+	for _, spec := range []string{"02000000/16=1234", "0x02000010=CAFEBABE"} {
+		if _, err := c.handle(emu, &controlRequest{Method: "freeze", Spec: spec}); err != nil {
+			t.Errorf("freeze %q: %v", spec, err)
+		}
+	}
+	if _, err := c.handle(emu, &controlRequest{Method: "freeze", Spec: "02000000/12=1"}); err == nil {
+		t.Errorf("invalid freeze accepted")
+	}
+
+	runFrames(emu, 1)
+	if v := nds9.Bus.Read16(0x02000000); v != 0x1234 {
+		t.Errorf("location not frozen: %04x", v)
+	}
+
+	if _, err := c.handle(emu, &controlRequest{Method: "unfreeze", Addr: 0x02000000}); err != nil {
+		t.Errorf("unfreeze: %v", err)
+	}
+	if _, err := c.handle(emu, &controlRequest{Method: "unfreeze", Addr: 0x02000000}); err == nil {
+		t.Errorf("unfreeze of a location not frozen")
+	}
+	res, err := c.handle(emu, &controlRequest{Method: "freezes"})
+	if list, _ := res.([]string); err != nil || len(list) != 1 || list[0] != "02000010/32=CAFEBABE" {
+		t.Errorf("invalid freeze list: %v (%v)", res, err)
+	}
+}
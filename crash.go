@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"ndsemu/arm"
+	"ndsemu/e2d"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Number of log lines kept in memory to be included in crash reports
+const cCrashLogLines = 2000
+
+var (
+	// crashHandler is invoked with the value of the first panic caught by
+	// recoverCrash; it's installed by main1 once the emulator is set up
+	crashHandler func(r interface{})
+	crashOnce    sync.Once
+)
+
+// recoverCrash must be deferred by every goroutine that runs emulator code
+// (emulation, audio and video output), so that a panic in any of them goes
+// through crashHandler before the process exits.
+func recoverCrash() {
+	if r := recover(); r != nil {
+		crashOnce.Do(func() {
+			if crashHandler != nil {
+				crashHandler(r)
+			}
+		})
+		panic(r)
+	}
+}
+
+// DumpMemory writes the contents of the main memory areas into the
+// specified directory, one file per area.
+func (emu *NDSEmulator) DumpMemory(dir string) {
+	dump := func(name string, cb func(f *os.File)) {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err == nil {
+			cb(f)
+			f.Close()
+		}
+	}
+
+	dump("ram.dump", func(f *os.File) {
+		f.Write(emu.Mem.Ram[:])
+	})
+	dump("wram.dump", func(f *os.File) {
+		f.Write(emu.Hw.Mc.wram[:])
+		f.Write(emu.Mem.Wram[:])
+	})
+	for i := 0; i < len(emu.Hw.Mc.vram); i++ {
+		vram := emu.Hw.Mc.vram[i][:]
+		dump(fmt.Sprintf("vram-%c.dump", 'a'+i), func(f *os.File) {
+			f.Write(vram)
+		})
+	}
+	dump("vram-bg-a.dump", func(f *os.File) {
+		v := emu.Hw.Mc.VramLinearBank(0, e2d.VramLinearBG, 0)
+		v.Dump(f)
+		v = emu.Hw.Mc.VramLinearBank(0, e2d.VramLinearBG, 256*1024)
+		v.Dump(f)
+	})
+	dump("vram-bg-b.dump", func(f *os.File) {
+		v := emu.Hw.Mc.VramLinearBank(1, e2d.VramLinearBG, 0)
+		v.Dump(f)
+		f.Truncate(128 * 1024)
+	})
+	dump("vram-bgextpal-a.dump", func(f *os.File) {
+		v := emu.Hw.Mc.VramLinearBank(0, e2d.VramLinearBGExtPal, 0)
+		v.Dump(f)
+	})
+	dump("vram-bgextpal-b.dump", func(f *os.File) {
+		v := emu.Hw.Mc.VramLinearBank(1, e2d.VramLinearBGExtPal, 0)
+		v.Dump(f)
+	})
+	dump("oam.dump", func(f *os.File) {
+		f.Write(emu.Mem.OamRam[:])
+	})
+	dump("texture.dump", func(f *os.File) {
+		texbank := emu.Hw.Mc.VramTextureBank()
+		for i := 0; i < 16; i++ {
+			f.Write(texbank.Slots[i])
+		}
+	})
+	dump("texpal.dump", func(f *os.File) {
+		texbank := emu.Hw.Mc.VramTexturePaletteBank()
+		for i := 0; i < 8; i++ {
+			f.Write(texbank.Slots[i])
+		}
+	})
+}
+
+func dumpCpuStatus(w io.Writer, name string, cpu *arm.Cpu) {
+	fmt.Fprintf(w, "%s:\n", name)
+	names, regs := cpu.GetRegNames(), cpu.GetRegs()
+	for i := range regs {
+		fmt.Fprintf(w, "  %-4s %08x", names[i], regs[i])
+		if i%4 == 3 {
+			fmt.Fprintf(w, "\n")
+		}
+	}
+	snames, sregs := cpu.GetSpecialRegNames(), cpu.GetSpecialRegs()
+	for i := range sregs {
+		fmt.Fprintf(w, "  %s: %s\n", snames[i], sregs[i])
+	}
+	text, _ := cpu.Disasm(cpu.GetPc())
+	fmt.Fprintf(w, "  => %08x %s\n", cpu.GetPc(), text)
+}
+
+// ioDevice is a hardware component exposing hwio registers
+type ioDevice struct {
+	name string
+	regs interface{}
+}
+
+// ioDevices returns all the hardware components with I/O registers
+func (emu *NDSEmulator) ioDevices() []ioDevice {
+	var devs []ioDevice
+	bank := func(name string, data interface{}) {
+		devs = append(devs, ioDevice{name, data})
+	}
+
+	bank("misc9", &nds9.misc)
+	bank("irq9", nds9.Irq)
+	for i := range nds9.Timers.Timers {
+		bank(fmt.Sprintf("timer9-%d", i), &nds9.Timers.Timers[i])
+	}
+	for i, dma := range nds9.Dma {
+		bank(fmt.Sprintf("dma9-%d", i), dma)
+	}
+	bank("dmafill", nds9.DmaFill)
+	bank("misc7", &nds7.misc7)
+	bank("irq7", nds7.Irq)
+	for i := range nds7.Timers.Timers {
+		bank(fmt.Sprintf("timer7-%d", i), &nds7.Timers.Timers[i])
+	}
+	for i, dma := range nds7.Dma {
+		bank(fmt.Sprintf("dma7-%d", i), dma)
+	}
+
+	hw := emu.Hw
+	bank("lcd9", hw.Lcd9)
+	bank("lcd7", hw.Lcd7)
+	bank("e2d-a", hw.E2d[0])
+	bank("e2d-b", hw.E2d[1])
+	bank("e3d", hw.E3d)
+	bank("geom", hw.Geom)
+	bank("memcnt", hw.Mc)
+	bank("ipc", hw.Ipc)
+	bank("div", hw.Div)
+	bank("key", hw.Key)
+	bank("gamecard", hw.Gc)
+	bank("spi", hw.Spi)
+	bank("rtc", hw.Rtc)
+	bank("sound", hw.Snd)
+	for i := range hw.Snd.Ch {
+		bank(fmt.Sprintf("sound-ch%d", i), &hw.Snd.Ch[i])
+	}
+	bank("wifi", hw.Wifi)
+	bank("uart", hw.Uart)
+	return devs
+}
+
+// Snapshot of the I/O registers of all hardware components
+func (emu *NDSEmulator) dumpIoRegs(w io.Writer) {
+	for _, dev := range emu.ioDevices() {
+		fmt.Fprintf(w, "%s:\n", dev.name)
+		hwio.DumpRegs(w, dev.regs)
+	}
+}
+
+// WriteCrashReport writes a diagnostic bundle into a new timestamped
+// directory within basedir, and returns its path. The bundle contains
+// the state of both CPUs, the scheduler queue, a snapshot of I/O
+// registers, the last log lines, and dumps of the main memory areas.
+//
+// It is meant to be called after a panic, so each section is written
+// independently: a failure while collecting one (eg: because the crash
+// left the emulator in an inconsistent state) doesn't affect the others.
+func (emu *NDSEmulator) WriteCrashReport(basedir string, reason string, stack []byte) (string, error) {
+	dir := filepath.Join(basedir, "crash-"+time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+
+	section := func(fn string, cb func(w io.Writer)) {
+		f, err := os.Create(filepath.Join(dir, fn))
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		w := bufio.NewWriter(f)
+		defer w.Flush()
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Fprintf(w, "\n*** panic while writing report: %v\n", r)
+			}
+		}()
+		cb(w)
+	}
+
+	section("report.txt", func(w io.Writer) {
+		fmt.Fprintf(w, "reason: %s\n", reason)
+		fmt.Fprintf(w, "mode: %v  frame: %d\n\n", emu.Mode, emu.framecount)
+		dumpCpuStatus(w, "arm9", nds9.Cpu)
+		fmt.Fprintf(w, "\n")
+		dumpCpuStatus(w, "arm7", nds7.Cpu)
+		fmt.Fprintf(w, "\nscheduler:\n")
+		emu.Sync.DumpStatus(w)
+		fmt.Fprintf(w, "\nstack:\n%s", stack)
+	})
+	section("ioregs.txt", emu.dumpIoRegs)
+	section("log.txt", func(w io.Writer) {
+		for _, line := range log.History() {
+			fmt.Fprintln(w, line)
+		}
+	})
+	func() {
+		defer func() { recover() }()
+		emu.DumpMemory(dir)
+	}()
+
+	return dir, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"ndsemu/arm/disasm"
+	"ndsemu/emu/debugger"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DisasmImage is a binary loaded at a fixed address, to be disassembled
+// offline (see disasmMain)
+type DisasmImage struct {
+	Base  uint32
+	Entry uint32
+	Data  []byte
+	Arch  disasm.Arch
+}
+
+func (img *DisasmImage) Peek8(addr uint32) uint8 {
+	if off := addr - img.Base; off < uint32(len(img.Data)) {
+		return img.Data[off]
+	}
+	return 0
+}
+
+// LoadDisasmImage loads the code to be disassembled from a file. For NDS
+// ROMs, this is the binary of the ARM9 (or ARM7, if arm7 is true), at the
+// address where the firmware loads it; GBA ROMs are mapped at 0x08000000.
+// Any other file is a raw ARM9 binary, loaded at the specified address.
+func LoadDisasmImage(fn string, arm7 bool, base uint32) (*DisasmImage, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	img := &DisasmImage{Base: base, Entry: base, Data: data, Arch: disasm.ARMv5}
+	if arm7 {
+		img.Arch = disasm.ARMv4
+	}
+
+	switch {
+	case strings.HasSuffix(fn, ".nds"):
+		var ch CartHeader
+		if err := ch.Read(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("%s: invalid header: %v", fn, err)
+		}
+		off, size := ch.Arm9Offset, ch.Arm9Size
+		img.Base, img.Entry = ch.Arm9Ram, ch.Arm9Entry
+		if arm7 {
+			off, size = ch.Arm7Offset, ch.Arm7Size
+			img.Base, img.Entry = ch.Arm7Ram, ch.Arm7Entry
+		}
+		if uint64(off)+uint64(size) > uint64(len(data)) {
+			return nil, fmt.Errorf("%s: binary out of ROM bounds", fn)
+		}
+		img.Data = data[off : off+size]
+	case strings.HasSuffix(fn, ".gba"):
+		img.Base, img.Entry, img.Arch = 0x08000000, 0x08000000, disasm.ARMv4
+	}
+	return img, nil
+}
+
+// Disassemble writes the disassembly of n instructions starting at addr,
+// one per line. Labels are shown for symbols, and the destinations of
+// branches are annotated with the symbol they point to.
+func (img *DisasmImage) Disassemble(w io.Writer, addr uint32, n int, thumb bool, syms *debugger.Symbols) {
+	for ; n > 0; n-- {
+		if sym, ok := syms.Lookup(addr); ok && sym.Addr == addr {
+			fmt.Fprintf(w, "\n%s:\n", sym.Name)
+		}
+
+		insn := disasm.Decode(img, addr, thumb, img.Arch)
+		var raw [4]byte
+		binary.LittleEndian.PutUint32(raw[:], insn.Raw)
+		text := insn.String()
+		if insn.HasTarget {
+			if s := syms.Format(insn.Target); s != "" {
+				text += " <" + s + ">"
+			}
+		}
+		fmt.Fprintf(w, "%08x  %-8x  %s\n", addr, raw[:insn.Size], text)
+		addr += uint32(insn.Size)
+	}
+}
+
+func disasmMain(args []string) int {
+	fs := flag.NewFlagSet("disasm", flag.ExitOnError)
+	arm7 := fs.Bool("arm7", false, "disassemble ARM7 code (the ARM7 binary of NDS ROMs)")
+	thumb := fs.Bool("thumb", false, "disassemble Thumb code")
+	start := fs.String("start", "", "address of the first instruction (default: entry point)")
+	base := fs.String("base", "0", "load address of raw binaries")
+	count := fs.Int("n", 32, "number of instructions")
+	symfn := fs.String("symbols", "", "load symbols from the specified file (no$gba .sym format)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s disasm [options] file\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Disassembles the ARM9 or ARM7 binary of a NDS ROM, a GBA ROM or a raw\n")
+		fmt.Fprintf(os.Stderr, "binary, annotating branch targets and literal pool values.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	baseaddr, err := strconv.ParseUint(*base, 0, 32)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid base address: %q\n", *base)
+		return 2
+	}
+	img, err := LoadDisasmImage(fs.Arg(0), *arm7, uint32(baseaddr))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	addr := img.Entry
+	if *start != "" {
+		a, err := strconv.ParseUint(*start, 0, 32)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid start address: %q\n", *start)
+			return 2
+		}
+		addr = uint32(a)
+	}
+
+	var syms *debugger.Symbols
+	if *symfn != "" {
+		f, err := os.Open(*symfn)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		syms, err = debugger.LoadSymbols(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *symfn, err)
+			return 1
+		}
+	}
+
+	img.Disassemble(os.Stdout, addr, *count, *thumb, syms)
+	return 0
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"ndsemu/arm/disasm"
+	"ndsemu/emu/debugger"
+	"strings"
+	"testing"
+)
+
+func TestDisasmImage(t *testing.T) {
+	img := &DisasmImage{
+		Base: 0x02000000,
+		Data: []byte{
+			0x00, 0x00, 0x9F, 0xE5, // ldr r0, [pc]
+			0xFD, 0xFF, 0xFF, 0xEB, // bl 0x02000000
+			0x78, 0x56, 0x34, 0x12, // literal
+		},
+		Arch: disasm.ARMv5,
+	}
+	syms, err := debugger.LoadSymbols(strings.NewReader("02000000 main\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	img.Disassemble(&out, 0x02000000, 2, false, syms)
+	want := "\nmain:\n" +
+		"02000000  00009fe5  ldr       r0, [pc] ; =0x12345678\n" +
+		"02000004  fdffffeb  bl        0x02000000 <main>\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
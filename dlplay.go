@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	log "ndsemu/emu/logger"
+	"strings"
+	"unicode/utf16"
+)
+
+var modDlPlay = log.NewModule("dlplay")
+
+const (
+	cDlPlayChunkSize      = 490 // bytes of binary carried by each data frame
+	cDlPlayChunksPerFrame = 4   // data frames sent per emulated frame
+	cDlPlayAdvertSize     = 0x398
+	cDlPlayAdvertFragment = 98 // bytes of the advertisement in each beacon
+	cDlPlayBeaconFrames   = 6  // emulated frames between beacons (~100ms)
+	cDlPlayChannel        = 13
+	cDlPlayMaxPlayers     = 15
+
+	// The client receives the binaries in main RAM: the ARM9 one directly
+	// at its load address, and the ARM7 one in a buffer after it, that ends
+	// where the header is stored (at the end of RAM)
+	cDlPlayArm7Buffer    = 0x022C0000
+	cDlPlayArm7BufferEnd = 0x023FFE00
+	cDlPlayHeaderAddr    = 0x027FFE00 // mirror of cDlPlayArm7BufferEnd
+)
+
+var (
+	dlPlayMacHost  = [6]byte{0x00, 0x09, 0xBF, 0x4E, 0x44, 0x53}
+	dlPlayMacCmd   = [6]byte{0x03, 0x09, 0xBF, 0x00, 0x00, 0x00} // host to clients
+	dlPlayMacReply = [6]byte{0x03, 0x09, 0xBF, 0x00, 0x00, 0x10} // clients to host
+	dlPlayMacBcast = [6]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+// Commands exchanged in data frames, after association
+const (
+	dlPlayCmdPoll  = 0x01 // host: clients must reply
+	dlPlayCmdRsa   = 0x03 // host: load addresses and RSA signature
+	dlPlayCmdData  = 0x04 // host: chunk of the binary (seq + data)
+	dlPlayCmdBoot  = 0x05 // host: transfer complete
+	dlPlayCmdReady = 0x07 // client: ready to receive the binary
+	dlPlayCmdAck   = 0x08 // client: index of the next chunk it needs
+	dlPlayCmdDone  = 0x09 // client: binary received and verified
+)
+
+type dlPlayClient struct {
+	aid  uint16
+	mac  [6]byte
+	next int // next chunk needed (-1: not ready yet)
+	done bool
+}
+
+// DlPlayHost is a high-level emulation of the host side of DS download play
+// (wireless multiboot), for single-cart multiplayer: it serves the child
+// binary embedded in the filesystem of the loaded ROM to other ndsemu
+// instances connected through the wifi link, which can receive it with
+// the download play application of the firmware.
+//
+// The game running in this instance doesn't take part to the protocol: the
+// host answers on its own, and the frames transmitted by the emulated wifi
+// are not forwarded to the link.
+type DlPlayHost struct {
+	advert []byte
+	chunks [][]byte // chunk 0 is the RSA frame, then header, ARM9, ARM7
+
+	clients    []*dlPlayClient
+	streamCode uint16
+	frames     int
+	fragment   int
+	seq        uint16
+}
+
+// NewDlPlayHost prepares the download play host for the child binary found
+// at the specified path in the ROM filesystem (eg: "dl/child.srl"). If path
+// is empty, the first file with the .srl extension is used.
+func NewDlPlayHost(rom io.ReaderAt, path string) (*DlPlayHost, error) {
+	match := func(p string) bool { return strings.EqualFold(p, path) }
+	if path == "" {
+		match = func(p string) bool { return strings.HasSuffix(strings.ToLower(p), ".srl") }
+	}
+	child, name, err := nitroFindFile(rom, match)
+	if err != nil {
+		return nil, err
+	}
+
+	var hdr [0x160]byte
+	if _, err := child.ReadAt(hdr[:], 0); err != nil {
+		return nil, errors.New("invalid download play binary: header not found")
+	}
+	ch := &CartHeader{}
+	ch.Read(bytes.NewReader(hdr[:]))
+	if ch.Arm9Ram < 0x02000000 || ch.Arm9Ram > cDlPlayArm7Buffer || ch.Arm9Size > cDlPlayArm7Buffer-ch.Arm9Ram {
+		return nil, errors.New("invalid download play binary: ARM9 binary doesn't fit in RAM")
+	}
+	if ch.Arm7Size > cDlPlayArm7BufferEnd-cDlPlayArm7Buffer {
+		return nil, errors.New("invalid download play binary: ARM7 binary doesn't fit in RAM")
+	}
+	arm9 := make([]byte, ch.Arm9Size)
+	arm7 := make([]byte, ch.Arm7Size)
+	if _, err := child.ReadAt(arm9, int64(ch.Arm9Offset)); err != nil {
+		return nil, errors.New("invalid download play binary: cannot read ARM9 binary")
+	}
+	if _, err := child.ReadAt(arm7, int64(ch.Arm7Offset)); err != nil {
+		return nil, errors.New("invalid download play binary: cannot read ARM7 binary")
+	}
+
+	// The signature is appended after the used area of the ROM image; the
+	// firmware refuses to boot binaries without a valid one.
+	var sig [0x88]byte
+	child.ReadAt(sig[:], int64(binary.LittleEndian.Uint32(hdr[0x80:])))
+	if sig[0] != 'a' || sig[1] != 'c' {
+		modDlPlay.WarnZ("RSA signature not found, the client will reject the binary").String("file", name).End()
+	}
+
+	var banner [0x840]byte
+	if off := binary.LittleEndian.Uint32(hdr[0x68:]); off != 0 {
+		child.ReadAt(banner[:], int64(off))
+	}
+
+	h := &DlPlayHost{
+		advert:     dlPlayAdvert(banner[:]),
+		streamCode: binary.LittleEndian.Uint16(hdr[0x15E:]), // header CRC
+	}
+
+	rsa := make([]byte, 13*4, 13*4+len(sig))
+	for i, v := range []uint32{
+		ch.Arm9Entry, ch.Arm7Entry, 0,
+		cDlPlayHeaderAddr, cDlPlayHeaderAddr, // header (final and temporary destination)
+		ch.Arm9Ram, ch.Arm9Ram, ch.Arm9Size, 0,
+		ch.Arm7Ram, cDlPlayArm7Buffer, ch.Arm7Size,
+		1,
+	} {
+		binary.LittleEndian.PutUint32(rsa[i*4:], v)
+	}
+	h.chunks = append(h.chunks, append(rsa, sig[:]...))
+	for _, bin := range [][]byte{hdr[:], arm9, arm7} {
+		for len(bin) > 0 {
+			n := len(bin)
+			if n > cDlPlayChunkSize {
+				n = cDlPlayChunkSize
+			}
+			h.chunks = append(h.chunks, bin[:n])
+			bin = bin[n:]
+		}
+	}
+
+	modDlPlay.InfoZ("download play host ready").String("file", name).
+		Int("chunks", len(h.chunks)).End()
+	return h, nil
+}
+
+// dlPlayAdvert builds the advertisement shown in the game list of the
+// download play application (icon, host name, title and description),
+// from the banner of the child binary.
+func dlPlayAdvert(banner []byte) []byte {
+	adv := make([]byte, cDlPlayAdvertSize)
+	copy(adv[0x000:0x020], banner[0x220:0x240]) // icon palette
+	copy(adv[0x020:0x220], banner[0x020:0x220]) // icon bitmap
+
+	putString := func(dst []byte, s string) int {
+		u := utf16.Encode([]rune(s))
+		for i := 0; i < len(u) && i*2 < len(dst); i++ {
+			binary.LittleEndian.PutUint16(dst[i*2:], u[i])
+		}
+		return len(u)
+	}
+	adv[0x221] = byte(putString(adv[0x222:0x236], "ndsemu"))
+	adv[0x236] = cDlPlayMaxPlayers
+
+	// English title: the first line is the name, the rest the description
+	var title []uint16
+	for i := 0x340; i < 0x440; i += 2 {
+		c := binary.LittleEndian.Uint16(banner[i:])
+		if c == 0 {
+			break
+		}
+		title = append(title, c)
+	}
+	lines := strings.SplitN(string(utf16.Decode(title)), "\n", 2)
+	putString(adv[0x238:0x298], lines[0])
+	if len(lines) > 1 {
+		putString(adv[0x298:0x358], strings.Replace(lines[1], "\n", " ", -1))
+	}
+	return adv
+}
+
+func (h *DlPlayHost) header(fc uint16, dst [6]byte) []byte {
+	frame := make([]byte, 24, 256)
+	binary.LittleEndian.PutUint16(frame[0:], fc)
+	copy(frame[4:10], dst[:])
+	copy(frame[10:16], dlPlayMacHost[:])
+	copy(frame[16:22], dlPlayMacHost[:])
+	binary.LittleEndian.PutUint16(frame[22:], h.seq<<4)
+	h.seq = (h.seq + 1) & 0xFFF
+	return frame
+}
+
+// beacon builds the next beacon. Beacons carry the advertisement in the
+// Nintendo vendor element, one fragment at a time.
+func (h *DlPlayHost) beacon() []byte {
+	frame := h.header(0x0080, dlPlayMacBcast)
+	var fixed [12]byte
+	binary.LittleEndian.PutUint16(fixed[8:], cDlPlayBeaconFrames*1000/60)
+	binary.LittleEndian.PutUint16(fixed[10:], 0x0021) // ESS, short preamble
+	frame = append(frame, fixed[:]...)
+	frame = append(frame, 0, 0)                 // SSID (hidden)
+	frame = append(frame, 1, 2, 0x82, 0x84)     // supported rates
+	frame = append(frame, 3, 1, cDlPlayChannel) // DS parameter set
+	frame = append(frame, 5, 4, 0, 2, 0, 0)     // TIM
+
+	nfrags := (len(h.advert) + cDlPlayAdvertFragment - 1) / cDlPlayAdvertFragment
+	data := h.advert[h.fragment*cDlPlayAdvertFragment:]
+	if len(data) > cDlPlayAdvertFragment {
+		data = data[:cDlPlayAdvertFragment]
+	}
+	var sum uint32
+	for i := 0; i < len(data); i += 2 {
+		sum += uint32(binary.LittleEndian.Uint16(data[i:]))
+	}
+	sum = (sum & 0xFFFF) + (sum >> 16)
+
+	payload := make([]byte, 10, 10+len(data))
+	payload[4] = byte(len(h.clients))
+	payload[5] = byte(h.fragment)
+	binary.LittleEndian.PutUint16(payload[6:], ^uint16(sum))
+	payload[8] = byte(nfrags)
+	payload[9] = byte(len(data))
+	payload = append(payload, data...)
+	h.fragment = (h.fragment + 1) % nfrags
+
+	var tag [0x16]byte
+	copy(tag[0:4], []byte{0x00, 0x09, 0xBF, 0x00})
+	binary.LittleEndian.PutUint16(tag[0x04:], 0x000A) // stepping offset
+	binary.LittleEndian.PutUint32(tag[0x08:], 0x00400001)
+	binary.LittleEndian.PutUint16(tag[0x0E:], h.streamCode)
+	tag[0x10] = byte(len(payload))
+	tag[0x11] = 0x0B // multiboot
+	binary.LittleEndian.PutUint16(tag[0x12:], (4+cDlPlayChunkSize+1)/2)
+	binary.LittleEndian.PutUint16(tag[0x14:], 4)
+	frame = append(frame, 0xDD, byte(len(tag)+len(payload)))
+	frame = append(frame, tag[:]...)
+	return append(frame, payload...)
+}
+
+// command builds a data frame with a command for the clients
+func (h *DlPlayHost) command(cmd byte, payload []byte) []byte {
+	frame := h.header(0x0208, dlPlayMacCmd)
+	var mask uint16
+	for _, c := range h.clients {
+		if !c.done {
+			mask |= 1 << c.aid
+		}
+	}
+	frame = append(frame, byte(mask), byte(mask>>8), cmd, 0)
+	return append(frame, payload...)
+}
+
+func (h *DlPlayHost) client(mac [6]byte) *dlPlayClient {
+	for _, c := range h.clients {
+		if c.mac == mac {
+			return c
+		}
+	}
+	return nil
+}
+
+// Recv handles a frame received from the link
+func (h *DlPlayHost) Recv(frame []byte, send func([]byte)) {
+	if len(frame) < 24 {
+		return
+	}
+	var dst, src [6]byte
+	copy(dst[:], frame[4:10])
+	copy(src[:], frame[10:16])
+	if dst != dlPlayMacHost && dst != dlPlayMacReply {
+		return
+	}
+	body := frame[24:]
+
+	switch frame[0] {
+	case 0xB0: // authentication (open system)
+		reply := h.header(0x00B0, src)
+		send(append(reply, 0, 0, 2, 0, 0, 0))
+
+	case 0x00: // association request
+		c := h.client(src)
+		if c == nil {
+			if len(h.clients) == cDlPlayMaxPlayers {
+				modDlPlay.WarnZ("too many clients").End()
+				return
+			}
+			c = &dlPlayClient{aid: uint16(len(h.clients) + 1), mac: src, next: -1}
+			h.clients = append(h.clients, c)
+		}
+		reply := h.header(0x0010, src)
+		reply = append(reply, 0x21, 0, 0, 0, byte(c.aid), 0xC0)
+		send(append(reply, 1, 2, 0x82, 0x84))
+		modDlPlay.InfoZ("client associated").Uint16("aid", c.aid).End()
+
+	case 0x08: // data (client reply)
+		c := h.client(src)
+		if c == nil || len(body) < 1 {
+			return
+		}
+		switch body[0] {
+		case dlPlayCmdReady:
+			if c.next < 0 {
+				c.next = 0
+				modDlPlay.InfoZ("client ready").Uint16("aid", c.aid).End()
+			}
+		case dlPlayCmdAck:
+			if len(body) >= 3 {
+				if next := int(binary.LittleEndian.Uint16(body[1:])); next > c.next {
+					c.next = next
+				}
+			}
+		case dlPlayCmdDone:
+			if !c.done {
+				c.done = true
+				modDlPlay.InfoZ("client booted").Uint16("aid", c.aid).End()
+			}
+		}
+	}
+}
+
+// Poll is called once per frame, to send beacons and the binary to the
+// clients that are ready to receive it.
+func (h *DlPlayHost) Poll(send func([]byte)) {
+	if h.frames++; h.frames%cDlPlayBeaconFrames == 0 {
+		send(h.beacon())
+	}
+
+	// The binary is multicast, so start from the oldest chunk still needed
+	// by any client; clients that are not ready yet are polled.
+	next, poll := len(h.chunks), false
+	for _, c := range h.clients {
+		switch {
+		case c.done:
+		case c.next < 0:
+			poll = true
+		case c.next < next:
+			next = c.next
+		}
+	}
+	if poll {
+		send(h.command(dlPlayCmdPoll, nil))
+	}
+
+	for i := next; i < len(h.chunks) && i < next+cDlPlayChunksPerFrame; i++ {
+		if i == 0 {
+			send(h.command(dlPlayCmdRsa, h.chunks[0]))
+			continue
+		}
+		var seq [2]byte
+		binary.LittleEndian.PutUint16(seq[:], uint16(i-1))
+		send(h.command(dlPlayCmdData, append(seq[:], h.chunks[i]...)))
+	}
+
+	for _, c := range h.clients {
+		if !c.done && c.next >= len(h.chunks) {
+			send(h.command(dlPlayCmdBoot, nil))
+			break
+		}
+	}
+}
+
+// nitroFindFile looks up a file in the NitroFS filesystem of a ROM, walking
+// the directory tree described by the FNT. match is called with the full
+// path of each file (eg: "dl/child.srl"); the first matching file is
+// returned, as a section of the ROM, together with its path.
+func nitroFindFile(rom io.ReaderAt, match func(path string) bool) (*io.SectionReader, string, error) {
+	var hdr [0x50]byte
+	if _, err := rom.ReadAt(hdr[:], 0); err != nil {
+		return nil, "", err
+	}
+	fnt := make([]byte, binary.LittleEndian.Uint32(hdr[0x44:]))
+	fat := make([]byte, binary.LittleEndian.Uint32(hdr[0x4C:]))
+	if _, err := rom.ReadAt(fnt, int64(binary.LittleEndian.Uint32(hdr[0x40:]))); err != nil {
+		return nil, "", errors.New("invalid ROM filesystem: cannot read FNT")
+	}
+	if _, err := rom.ReadAt(fat, int64(binary.LittleEndian.Uint32(hdr[0x48:]))); err != nil {
+		return nil, "", errors.New("invalid ROM filesystem: cannot read FAT")
+	}
+
+	var walk func(dir int, prefix string, depth int) (int, string)
+	walk = func(dir int, prefix string, depth int) (int, string) {
+		idx := (dir & 0xFFF) * 8
+		if depth > 16 || idx+8 > len(fnt) {
+			return -1, ""
+		}
+		off := int(binary.LittleEndian.Uint32(fnt[idx:]))
+		fileID := int(binary.LittleEndian.Uint16(fnt[idx+4:]))
+		for off < len(fnt) && fnt[off] != 0 {
+			t := int(fnt[off])
+			n := t & 0x7F
+			if off+1+n > len(fnt) {
+				break
+			}
+			name := prefix + string(fnt[off+1:off+1+n])
+			off += 1 + n
+			if t&0x80 == 0 {
+				if match(name) {
+					return fileID, name
+				}
+				fileID++
+				continue
+			}
+			if off+2 > len(fnt) {
+				break
+			}
+			sub := int(binary.LittleEndian.Uint16(fnt[off:]))
+			off += 2
+			if id, path := walk(sub, name+"/", depth+1); id >= 0 {
+				return id, path
+			}
+		}
+		return -1, ""
+	}
+
+	id, path := walk(0xF000, "", 0)
+	if id < 0 || (id+1)*8 > len(fat) {
+		return nil, "", errors.New("file not found in ROM filesystem")
+	}
+	start := binary.LittleEndian.Uint32(fat[id*8:])
+	end := binary.LittleEndian.Uint32(fat[id*8+4:])
+	if end < start {
+		return nil, "", errors.New("invalid ROM filesystem: corrupted FAT")
+	}
+	return io.NewSectionReader(rom, int64(start), int64(end-start)), path, nil
+}
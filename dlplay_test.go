@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestNitroFindFile(t *testing.T) {
+	// Root: "readme.txt" (file 0), "dl/" (dir 1: "child.srl", file 1)
+	var fnt []byte
+	fnt = append(fnt, 16, 0, 0, 0, 0, 0, 2, 0) // root, 2 dirs
+	fnt = append(fnt, 0, 0, 0, 0, 1, 0, 0, 0xF0)
+	binary.LittleEndian.PutUint32(fnt[8:], uint32(len(fnt)+16))
+	fnt = append(fnt, 10)
+	fnt = append(fnt, "readme.txt"...)
+	fnt = append(fnt, 0x82, 'd', 'l', 0x01, 0xF0, 0)
+	binary.LittleEndian.PutUint32(fnt[8:], uint32(len(fnt)))
+	fnt = append(fnt, 9)
+	fnt = append(fnt, "child.srl"...)
+	fnt = append(fnt, 0)
+
+	rom := make([]byte, 0x400)
+	copy(rom[0x200:], fnt)
+	fat := []uint32{0x300, 0x310, 0x380, 0x3A0}
+	for i, v := range fat {
+		binary.LittleEndian.PutUint32(rom[0x180+i*4:], v)
+	}
+	binary.LittleEndian.PutUint32(rom[0x40:], 0x200)
+	binary.LittleEndian.PutUint32(rom[0x44:], uint32(len(fnt)))
+	binary.LittleEndian.PutUint32(rom[0x48:], 0x180)
+	binary.LittleEndian.PutUint32(rom[0x4C:], uint32(len(fat)*4))
+	copy(rom[0x380:], "CHILD")
+
+	f, path, err := nitroFindFile(bytes.NewReader(rom), func(p string) bool { return p == "dl/child.srl" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf [5]byte
+	f.ReadAt(buf[:], 0)
+	if path != "dl/child.srl" || f.Size() != 0x20 || string(buf[:]) != "CHILD" {
+		t.Errorf("invalid file: %q, size %d, data %q", path, f.Size(), buf[:])
+	}
+
+	if _, _, err := nitroFindFile(bytes.NewReader(rom), func(p string) bool { return p == "child.srl" }); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+// testDlPlayRom builds a ROM whose filesystem only contains the specified
+// download play binary, as "child.srl"
+func testDlPlayRom(child []byte) []byte {
+	fnt := []byte{8, 0, 0, 0, 0, 0, 1, 0, 9}
+	fnt = append(fnt, "child.srl"...)
+	fnt = append(fnt, 0)
+
+	rom := make([]byte, 0x200, 0x200+len(child))
+	copy(rom[0x100:], fnt)
+	binary.LittleEndian.PutUint32(rom[0x40:], 0x100)
+	binary.LittleEndian.PutUint32(rom[0x44:], uint32(len(fnt)))
+	binary.LittleEndian.PutUint32(rom[0x48:], 0x180)
+	binary.LittleEndian.PutUint32(rom[0x4C:], 8)
+	binary.LittleEndian.PutUint32(rom[0x180:], 0x200)
+	binary.LittleEndian.PutUint32(rom[0x184:], uint32(0x200+len(child)))
+	return append(rom, child...)
+}
+
+func testDlPlayChild(arm9size, arm7size uint32) []byte {
+	child := make([]byte, 0x200+arm9size+arm7size)
+	for i := range child[0x200:] {
+		child[0x200+i] = byte(i * 7)
+	}
+	for i, v := range []uint32{
+		0x200, 0x02000800, 0x02000000, arm9size,
+		0x200 + arm9size, 0x037F8000, 0x037F8000, arm7size,
+	} {
+		binary.LittleEndian.PutUint32(child[0x20+i*4:], v)
+	}
+	binary.LittleEndian.PutUint16(child[0x15E:], 0xBEEF)
+	return child
+}
+
+func TestDlPlayRoundTrip(t *testing.T) {
+	child := testDlPlayChild(3000, 1000)
+	h, err := NewDlPlayHost(bytes.NewReader(testDlPlayRom(child)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A client associates and receives the binary, acknowledging each
+	// chunk as the firmware does
+	mac := [6]byte{0x00, 0x09, 0xBF, 0x11, 0x22, 0x33}
+	clientFrame := func(fc byte, body ...byte) []byte {
+		frame := make([]byte, 24)
+		frame[0] = fc
+		copy(frame[4:], dlPlayMacHost[:])
+		copy(frame[10:], mac[:])
+		return append(frame, body...)
+	}
+	var replies [][]byte
+	h.Recv(clientFrame(0x00), func(f []byte) { replies = append(replies, f) })
+	if len(replies) != 1 || replies[0][0] != 0x10 || replies[0][28] != 1 {
+		t.Fatalf("invalid association reply: % x", replies)
+	}
+	h.Recv(clientFrame(0x08, dlPlayCmdReady), nil)
+
+	var rsa []byte
+	var bin []byte
+	var advert []byte
+	nseq, booted := 0, false
+	for frame := 0; frame < 200 && (!booted || len(advert) < cDlPlayAdvertSize); frame++ {
+		h.Poll(func(f []byte) {
+			if f[0] == 0x80 {
+				// Beacon: collect the fragments of the advertisement
+				payload := f[51+2+0x16:]
+				if int(payload[5]) == len(advert)/cDlPlayAdvertFragment {
+					advert = append(advert, payload[10:10+payload[9]]...)
+				}
+				return
+			}
+			if mask := binary.LittleEndian.Uint16(f[24:]); mask != 1<<1 {
+				t.Errorf("invalid client mask: %x", mask)
+			}
+			switch f[26] {
+			case dlPlayCmdRsa:
+				rsa = f[28:]
+				h.Recv(clientFrame(0x08, dlPlayCmdAck, 1, 0), nil)
+			case dlPlayCmdData:
+				if seq := int(binary.LittleEndian.Uint16(f[28:])); seq == nseq {
+					bin = append(bin, f[30:]...)
+					nseq++
+					h.Recv(clientFrame(0x08, dlPlayCmdAck, byte(nseq+1), byte((nseq+1)>>8)), nil)
+				}
+			case dlPlayCmdBoot:
+				booted = true
+			}
+		})
+	}
+	if !booted {
+		t.Fatal("boot command not received")
+	}
+
+	want := append(append([]byte{}, child[:0x160]...), child[0x200:]...)
+	if !bytes.Equal(bin, want) {
+		t.Errorf("binary received incorrectly (%d bytes, want %d)", len(bin), len(want))
+	}
+	for i, v := range []uint32{0x02000800, 0x037F8000, 0, cDlPlayHeaderAddr, cDlPlayHeaderAddr,
+		0x02000000, 0x02000000, 3000, 0, 0x037F8000, cDlPlayArm7Buffer, 1000, 1} {
+		if got := binary.LittleEndian.Uint32(rsa[i*4:]); got != v {
+			t.Errorf("RSA frame, word %d: got %08x, want %08x", i, got, v)
+		}
+	}
+	if !bytes.Equal(advert, h.advert) || advert[0x221] != 6 || advert[0x222] != 'n' {
+		t.Errorf("advertisement received incorrectly")
+	}
+}
+
+func TestDlPlayInvalidSizes(t *testing.T) {
+	for _, sz := range [][2]uint32{{0x2C0001, 0}, {0x80000000, 0}, {0, 0x13FE01}, {0, 0xFFFFFFFF}} {
+		child := testDlPlayChild(0, 0)
+		binary.LittleEndian.PutUint32(child[0x2C:], sz[0])
+		binary.LittleEndian.PutUint32(child[0x3C:], sz[1])
+		if _, err := NewDlPlayHost(bytes.NewReader(testDlPlayRom(child)), ""); err == nil {
+			t.Errorf("sizes %x: binary accepted", sz)
+		}
+	}
+}
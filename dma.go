@@ -1,13 +1,22 @@
 package main
 
 import (
+	"fmt"
+	"ndsemu/arm"
 	"ndsemu/emu"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"ndsemu/emu/trace"
 )
 
 type DmaEvent int
 
+// DmaTrigger is implemented by the CPUs (NDS9 and NDS7), and lets devices
+// start the DMA channels waiting for a specific event.
+type DmaTrigger interface {
+	TriggerDmaEvent(event DmaEvent)
+}
+
 const (
 	DmaEventInvalid   DmaEvent = iota // invalid event (out-of-band value)
 	DmaEventImmediate                 // immediate event (for immediate channels)
@@ -19,10 +28,11 @@ const (
 )
 
 type HwDmaFill struct {
-	Dma0Fill hwio.Reg32 `hwio:"offset=0x00"`
-	Dma1Fill hwio.Reg32 `hwio:"offset=0x04"`
-	Dma2Fill hwio.Reg32 `hwio:"offset=0x08"`
-	Dma3Fill hwio.Reg32 `hwio:"offset=0x0C"`
+	_        hwio.BankSize `hwio:"size=0x10"`
+	Dma0Fill hwio.Reg32    `hwio:"offset=0x00"`
+	Dma1Fill hwio.Reg32    `hwio:"offset=0x04"`
+	Dma2Fill hwio.Reg32    `hwio:"offset=0x08"`
+	Dma3Fill hwio.Reg32    `hwio:"offset=0x0C"`
 }
 
 func NewHwDmaFill() *HwDmaFill {
@@ -37,10 +47,34 @@ type HwDmaChannel struct {
 	Bus     emu.Bus
 	Irq     *HwIrq
 
-	DmaSad   hwio.Reg32 `hwio:"offset=0x00"`
-	DmaDad   hwio.Reg32 `hwio:"offset=0x04"`
-	DmaCount hwio.Reg16 `hwio:"offset=0x08"`
-	DmaCntrl hwio.Reg16 `hwio:"offset=0x0A,wcb"`
+	_        hwio.BankSize `hwio:"size=0xC"`
+	DmaSad   hwio.Reg32    `hwio:"offset=0x00"`
+	DmaDad   hwio.Reg32    `hwio:"offset=0x04"`
+	DmaCount hwio.Reg16    `hwio:"offset=0x08"`
+	DmaCntrl hwio.Reg16    `hwio:"offset=0x0A,wcb"`
+
+	// Internal copies of the address and count registers, latched when the
+	// channel is enabled. The visible registers are never modified by the
+	// transfer; on repeat, the count (and the destination address, in
+	// reload mode) is reloaded from them.
+	sad, dad, cnt uint32
+
+	// Last value transferred, which is what the DMA reads from invalid
+	// source addresses (eg: BIOS or TCM) instead of the actual memory
+	readLatch uint32
+
+	// CP15 of the ARM9, to exclude its TCM from the DMA sources (nil for
+	// the ARM7 channels)
+	Cp15 *arm.Cp15
+
+	// Called before each transfer, to bring up to date the devices that
+	// read the memory being transferred (nil: none)
+	BeforeXfer func()
+
+	// Called after each transfer that refills a FIFO, with the number of
+	// units transferred, to stall the CPU while the DMA owns the bus (nil:
+	// transfers are instantaneous; see QuirkFifoTiming)
+	Stall func(units uint32)
 
 	debugRepeat  bool
 	inProgress   bool
@@ -113,7 +147,6 @@ func (dma *HwDmaChannel) startEvent() DmaEvent {
 				log.ModDma.ErrorZ("DMA start 3 prohibited on channel 0").End()
 				return DmaEventInvalid
 			case 1, 2:
-				log.ModDma.WarnZ("DMA sound FIFO not implemented").End()
 				return DmaEventGbaSoundFifo
 			case 3:
 				log.ModDma.WarnZ("DMA video capture not implemented").End()
@@ -128,9 +161,51 @@ func (dma *HwDmaChannel) startEvent() DmaEvent {
 	panic("unreachable")
 }
 
+// count returns the number of units to transfer, as programmed in the
+// count register. Zero means the maximum count supported by the channel.
+func (dma *HwDmaChannel) count() uint32 {
+	cnt := uint32(dma.DmaCount.Value)
+	if dma.Cpu == CpuNds9 {
+		cnt |= (uint32(dma.DmaCntrl.Value) & 0x1F) << 16
+		if cnt == 0 {
+			cnt = 0x200000
+		}
+	} else if dma.Channel == 3 {
+		if cnt == 0 {
+			cnt = 0x10000
+		}
+	} else {
+		// Channels 0-2 have a 14-bit count register
+		cnt &= 0x3FFF
+		if cnt == 0 {
+			cnt = 0x4000
+		}
+	}
+	return cnt
+}
+
+// srcValid returns false for source addresses that the DMA cannot read
+// (BIOS and TCM, and anything beyond the address space of the bus).
+func (dma *HwDmaChannel) srcValid(addr uint32) bool {
+	if addr < 0x02000000 || addr >= 0x10000000 {
+		return false
+	}
+	if dma.Cp15 != nil && (dma.Cp15.CheckDTcm(addr) != nil || dma.Cp15.CheckITcm(addr) != nil) {
+		return false
+	}
+	return true
+}
+
 func (dma *HwDmaChannel) WriteDMACNTRL(old, val uint16) {
 	dma.debugRepeat = false
 
+	// Latch the registers when the channel is enabled
+	if old&(1<<15) == 0 && val&(1<<15) != 0 {
+		dma.sad = dma.DmaSad.Value
+		dma.dad = dma.DmaDad.Value
+		dma.cnt = dma.count()
+	}
+
 	// Check if this write activated a DMA channel. If it did,
 	// we might to do something right away, depending on the start
 	// event type.
@@ -156,8 +231,9 @@ func (dma *HwDmaChannel) WriteDMACNTRL(old, val uint16) {
 
 func (dma *HwDmaChannel) xfer() {
 	ctrl := dma.DmaCntrl.Value
-	sad := dma.DmaSad.Value
-	dad := dma.DmaDad.Value
+	sad := dma.sad
+	dad := dma.dad
+	cnt := dma.cnt
 
 	irq := (ctrl>>14)&1 != 0
 	start := (ctrl >> 11) & 7
@@ -166,24 +242,19 @@ func (dma *HwDmaChannel) xfer() {
 	sinc := (ctrl >> 7) & 3
 	dinc := (ctrl >> 5) & 3
 
-	if sinc == 3 {
-		log.ModDma.FatalZ("sinc=3 should not happen").End()
+	if dma.BeforeXfer != nil {
+		dma.BeforeXfer()
 	}
 
-	cnt := uint32(dma.DmaCount.Value)
-	if dma.Cpu == CpuNds9 {
-		cnt |= (uint32(dma.DmaCntrl.Value) & 0x1F) << 16
-		if cnt == 0 {
-			cnt = 0x200000
-		}
-	} else {
-		if cnt == 0 {
-			if dma.Channel == 3 {
-				cnt = 0x10000
-			} else {
-				cnt = 0x4000
-			}
-		}
+	if dma.startEvent() == DmaEventGbaSoundFifo {
+		// Sound FIFO refills always transfer 4 words to the FIFO register,
+		// regardless of count, size and destination increment.
+		cnt, w32, dinc = 4, true, 2
+	}
+
+	if sinc == 3 {
+		// Prohibited; the hardware increments the address
+		log.ModDma.ErrorZ("invalid source increment mode").Int("ch", dma.Channel).End()
 	}
 
 	wordsize := uint32(2)
@@ -212,25 +283,45 @@ func (dma *HwDmaChannel) xfer() {
 		return
 	}
 
-	if dma.Cpu == CpuNds9 && start == 7 {
-		// GFXFIFO dma is different from others because it is technically
-		// a single-transfer, while actually data is flushed in batches
-		// of 112 words. So we need to trick this function into repeat mode
-		// and avoid triggering irq, unless the transfer is really finished.
-		if cnt > 112 {
-			irq = false
-			repeat = true
-			dma.DmaCount.Value = uint16(cnt - 112)
-			cnt = 112
+	// GFXFIFO dma is different from others because it is technically
+	// a single-transfer, while actually data is flushed in batches
+	// of 112 words. So we need to keep the channel enabled and avoid
+	// triggering irq, unless the transfer is really finished.
+	var left uint32
+	if dma.Cpu == CpuNds9 && start == 7 && cnt > 112 {
+		left = cnt - 112
+		cnt = 112
+	}
+
+	if trace.Enabled() {
+		track := "dma9"
+		if dma.Cpu == CpuNds7 {
+			track = "dma7"
 		}
+		trace.Instant(track, fmt.Sprintf("dma%d", dma.Channel), trace.Args{
+			"sad":   fmt.Sprintf("%08x", sad),
+			"dad":   fmt.Sprintf("%08x", dad),
+			"cnt":   cnt,
+			"start": start,
+			"w32":   w32,
+		})
 	}
 
+	units := cnt
 	dma.inProgress = true
 	for ; cnt != 0; cnt-- {
 		if w32 {
-			dma.Bus.Write32(dad, dma.Bus.Read32(sad))
+			if dma.srcValid(sad) {
+				dma.readLatch = dma.Bus.Read32(sad)
+			}
+			dma.Bus.Write32(dad, dma.readLatch)
 		} else {
-			dma.Bus.Write16(dad, dma.Bus.Read16(sad))
+			// Halfwords are latched in both halves of the latch
+			if dma.srcValid(sad) {
+				val := uint32(dma.Bus.Read16(sad))
+				dma.readLatch = val | val<<16
+			}
+			dma.Bus.Write16(dad, uint16(dma.readLatch>>(8*(dad&2))))
 		}
 
 		// Notify jit engine that we wrote to that address
@@ -250,6 +341,16 @@ func (dma *HwDmaChannel) xfer() {
 		}
 	}
 	dma.inProgress = false
+	dma.sad, dma.dad = sad, dad
+
+	if ev := dma.startEvent(); dma.Stall != nil && (ev == DmaEventGxFifo || ev == DmaEventGbaSoundFifo) {
+		dma.Stall(units)
+	}
+
+	if left != 0 {
+		dma.cnt = left
+		return
+	}
 
 	if irq {
 		dma.Irq.Raise(IrqDma0 << uint(dma.Channel))
@@ -258,14 +359,12 @@ func (dma *HwDmaChannel) xfer() {
 	if !repeat {
 		dma.disable()
 	} else {
-		// Update registers for next repeat. Notice that these should be
-		// internal copies of registers, but the external visible registers
-		// are writeonly anyway, so we can reuse those for our own goals.
-		dma.DmaSad.Value = sad
-
-		// dest-increment 3 is "reload each repetition"
-		if dinc != 3 {
-			dma.DmaDad.Value = dad
+		// Prepare for next repeat: the source address continues from where
+		// it stopped, while the count is reloaded. Dest-increment 3 is
+		// "reload each repetition".
+		dma.cnt = dma.count()
+		if dinc == 3 {
+			dma.dad = dma.DmaDad.Value
 		}
 	}
 }
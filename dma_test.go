@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
+	"testing"
+)
+
+func TestDmaSrcValid(t *testing.T) {
+	bus9 := hwio.NewTable("bus9")
+	cpu := arm.NewCpu(arm.ARMv5, bus9, false)
+	cp15 := cpu.EnableCp15()
+	cp15.ConfigureTcm(cItcmPhysicalSize, cDtcmPhysicalSize)
+	cp15.Write(0, 9, 1, 0, 0x027C0000|5<<1) // DTCM: 16K at 0x027C0000
+	cp15.Write(0, 9, 1, 1, 6<<1)            // ITCM: 32K at 0
+	cp15.Write(0, 1, 0, 0, 1<<16|1<<18)     // enable both
+
+	dma9 := NewHwDmaChannel(CpuNds9, 0, bus9, nil)
+	dma9.Cp15 = cp15
+	dma7 := NewHwDmaChannel(CpuNds7, 0, hwio.NewTable("bus7"), nil)
+
+	for _, tc := range []struct {
+		addr       uint32
+		nds9, nds7 bool
+	}{
+		{0x00000000, false, false}, // ITCM / BIOS
+		{0x01FFFFFC, false, false},
+		{0x02000000, true, true}, // main RAM
+		{0x027BFFFC, true, true},
+		{0x027C0000, false, true}, // DTCM
+		{0x027C3FFC, false, true},
+		{0x027C4000, true, true},
+		{0x04000000, true, true}, // I/O
+		{0x0FFFFFFC, true, true},
+		{0x10000000, false, false},
+		{0xFFFF0000, false, false}, // BIOS
+	} {
+		if v := dma9.srcValid(tc.addr); v != tc.nds9 {
+			t.Errorf("dma9 %08x: valid=%v, want %v", tc.addr, v, tc.nds9)
+		}
+		if v := dma7.srcValid(tc.addr); v != tc.nds7 {
+			t.Errorf("dma7 %08x: valid=%v, want %v", tc.addr, v, tc.nds7)
+		}
+	}
+}
+
+func TestDmaCount(t *testing.T) {
+	for _, tc := range []struct {
+		cpu      CpuNum
+		ch       int
+		cnt, ctl uint16
+		exp      uint32
+	}{
+		{CpuNds9, 0, 0, 0, 0x200000}, // zero: maximum count
+		{CpuNds9, 0, 0x1234, 0x1F, 0x1F1234},
+		{CpuNds7, 0, 0, 0, 0x4000},
+		{CpuNds7, 2, 0xC123, 0, 0x0123}, // 14-bit count
+		{CpuNds7, 3, 0, 0, 0x10000},
+		{CpuNds7, 3, 0xC123, 0, 0xC123},
+	} {
+		dma := NewHwDmaChannel(tc.cpu, tc.ch, hwio.NewTable("bus"), nil)
+		dma.DmaCount.Value, dma.DmaCntrl.Value = tc.cnt, tc.ctl
+		if n := dma.count(); n != tc.exp {
+			t.Errorf("cpu %d ch %d cnt %04x: count=%x, want %x", tc.cpu, tc.ch, tc.cnt, n, tc.exp)
+		}
+	}
+}
+
+// startDma programs a DMA channel through its registers, and enables it
+// (disabling it first, so that the registers are latched again)
+func startDma(bus *hwio.Table, ch int, sad, dad uint32, cnt, ctrl uint16) {
+	base := 0x40000B0 + uint32(ch)*12
+	bus.Write16(base+10, 0)
+	bus.Write32(base, sad)
+	bus.Write32(base+4, dad)
+	bus.Write32(base+8, uint32(cnt)|uint32(ctrl|1<<15)<<16)
+}
+
+func TestDmaCountZero(t *testing.T) {
+	emu := newTestEmulator(t)
+	ram := emu.Mem.Ram[:]
+	for i := 0; i < 0x10000; i++ {
+		ram[i] = byte(i)
+	}
+
+	// An immediate halfword transfer with count 0 moves 0x4000 halfwords
+	// on ARM7 channel 0
+	startDma(nds7.Bus, 0, 0x02000000, 0x02100000, 0, 0)
+	if !bytes.Equal(ram[0x100000:0x108000], ram[:0x8000]) {
+		t.Errorf("transfer incomplete")
+	}
+	if ram[0x108000] != 0 {
+		t.Errorf("transfer too long")
+	}
+	if nds7.Dma[0].enabled() {
+		t.Errorf("channel still enabled after the transfer")
+	}
+}
+
+func TestDmaRepeat(t *testing.T) {
+	emu := newTestEmulator(t)
+	ram := emu.Mem.Ram[:]
+	for i := 0; i < 64; i++ {
+		ram[i] = byte(i + 1)
+	}
+
+	// HBlank, repeat, 32-bit, 2 words per transfer. With dest-increment 0,
+	// both addresses continue from where they stopped; with 3, the
+	// destination is reloaded at each repetition.
+	for _, tc := range []struct {
+		dinc uint16
+		dst  [3]uint32 // where each of the three transfers writes
+	}{
+		{0, [3]uint32{0x100000, 0x100008, 0x100010}},
+		{3, [3]uint32{0x100000, 0x100000, 0x100000}},
+	} {
+		for i := 0x100000; i < 0x100020; i++ {
+			ram[i] = 0
+		}
+		startDma(nds9.Bus, 1, 0x02000000, 0x02100000, 2, 2<<11|1<<10|1<<9|tc.dinc<<5)
+		for i, dst := range tc.dst {
+			nds9.Dma[1].TriggerEvent(DmaEventHBlank)
+			if !nds9.Dma[1].enabled() {
+				t.Fatalf("dinc %d: channel disabled after transfer %d", tc.dinc, i)
+			}
+			src := ram[i*8 : i*8+8]
+			if !bytes.Equal(ram[dst:dst+8], src) {
+				t.Errorf("dinc %d: transfer %d: got % x at %x, want % x", tc.dinc, i, ram[dst:dst+8], dst, src)
+			}
+		}
+	}
+
+	// Disabling the channel stops the repetitions
+	nds9.Bus.Write16(0x40000C6, 0)
+	ram[0x100000] = 0xEE
+	nds9.Dma[1].TriggerEvent(DmaEventHBlank)
+	if ram[0x100000] != 0xEE {
+		t.Errorf("disabled channel transferred data")
+	}
+}
+
+func TestDmaReadLatch(t *testing.T) {
+	emu := newTestEmulator(t)
+	ram := emu.Mem.Ram[:]
+	binary.LittleEndian.PutUint16(ram[0:], 0x1234)
+
+	// A halfword transfer latches the value in both halves; a transfer
+	// from the BIOS (invalid source) then writes the latched value
+	// instead of reading memory
+	startDma(nds7.Bus, 3, 0x02000000, 0x02100000, 1, 0)
+	startDma(nds7.Bus, 3, 0x00000000, 0x02100010, 2, 1<<10)
+	for i := 0; i < 2; i++ {
+		if v := binary.LittleEndian.Uint32(ram[0x100010+i*4:]); v != 0x12341234 {
+			t.Errorf("word %d: got %08x, want the latched value", i, v)
+		}
+	}
+
+	// A transfer from a valid address updates the latch
+	binary.LittleEndian.PutUint32(ram[0x20:], 0xCAFEBABE)
+	startDma(nds7.Bus, 3, 0x02000020, 0x02100020, 1, 1<<10)
+	startDma(nds7.Bus, 3, 0x00000000, 0x02100030, 1, 0)
+	if v := binary.LittleEndian.Uint16(ram[0x100030:]); v != 0xBABE {
+		t.Errorf("halfword from the latch: got %04x, want babe", v)
+	}
+	startDma(nds7.Bus, 3, 0x00000000, 0x02100032, 1, 0)
+	if v := binary.LittleEndian.Uint16(ram[0x100032:]); v != 0xCAFE {
+		t.Errorf("halfword from the latch (odd address): got %04x, want cafe", v)
+	}
+}
@@ -0,0 +1,49 @@
+package e2d
+
+import (
+	"encoding/binary"
+	log "ndsemu/emu/logger"
+	"testing"
+)
+
+func TestAffineRefPointLatch(t *testing.T) {
+	log.Disable()
+
+	// 256x256 direct color bitmap on BG2, in which the red component of
+	// each pixel is its row in the bitmap (modulo 32)
+	mc := &testMemCtrl{}
+	for y := 0; y < 256; y++ {
+		for x := 0; x < 256; x++ {
+			binary.LittleEndian.PutUint16(mc.vram[(y*256+x)*2:], 0x8000|uint16(y&31))
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		latch   bool
+		rewrite bool // write the same BG2Y at every line
+		row     func(y int) int
+	}{
+		{"nolatch", false, false, func(y int) int { return y }},
+		{"nolatch-rewrite", false, true, func(y int) int { return y }},
+		{"latch", true, false, func(y int) int { return y }},
+		{"latch-rewrite", true, true, func(y int) int { return 0 }},
+	} {
+		e2d := newTestEngine(mc)
+		e2d.SetRefPointLatch(tc.latch)
+		e2d.DispCnt.Value = 1<<16 | 1<<10 | 5  // mode 5, BG2 on
+		e2d.Bg2Cnt.Value = 1<<14 | 1<<7 | 1<<2 // 256x256, direct color
+		e2d.Bg2PA.Value, e2d.Bg2PD.Value = 0x100, 0x100
+
+		img := renderFrame(e2d, func(y int) {
+			if tc.rewrite {
+				e2d.Bg2PY.Write32(0x400002C, 0)
+			}
+		})
+		for _, y := range []int{0, 1, 10, 31} {
+			if r, want := int(img.RGBAAt(0, y).R>>3), tc.row(y)&31; r != want {
+				t.Errorf("%s: line %d shows row %d, want %d", tc.name, y, r, want)
+			}
+		}
+	}
+}
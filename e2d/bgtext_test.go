@@ -0,0 +1,393 @@
+package e2d
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"ndsemu/emu/gfx"
+	log "ndsemu/emu/logger"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden images in testdata")
+
+// testMemCtrl is a MemoryController with a flat VRAM, which is mapped
+// linearly for both BG and OBJ; extended palettes have their own memory.
+type testMemCtrl struct {
+	pal    [2048]byte
+	oam    [1024]byte
+	vram   [512*1024 + VramSmallestBankSize]byte // padded for unaligned banks
+	bgext  [32 * 1024]byte
+	objext [8 * 1024]byte
+}
+
+func (mc *testMemCtrl) VramPalette(engine int) []byte { return mc.pal[:] }
+func (mc *testMemCtrl) VramOAM(engine int) []byte     { return mc.oam[:] }
+func (mc *testMemCtrl) VramLcdcBank(bank int) []byte  { return nil }
+
+func (mc *testMemCtrl) VramLinearBank(engine int, which VramLinearBankId, baseOffset int) VramLinearBank {
+	var vb VramLinearBank
+	for i := range vb.Ptr {
+		off := baseOffset + i*VramSmallestBankSize
+		switch which {
+		case VramLinearBGExtPal:
+			vb.Ptr[i] = mc.bgext[off%len(mc.bgext):]
+		case VramLinearOBJExtPal:
+			vb.Ptr[i] = mc.objext[:]
+		default:
+			off %= 512 * 1024
+			vb.Ptr[i] = mc.vram[off : off+VramSmallestBankSize]
+		}
+	}
+	return vb
+}
+
+const (
+	testCharBase = 0
+	testMapBase  = 64 * 1024 // selected through DISPCNT
+)
+
+// setupTextBg fills VRAM with a synthetic BG, in which each tile can be told
+// apart by its contents: the tile number selects the fill color, the screen
+// block selects the palette (for 16-color tiles), and some tiles are flipped.
+// A transparent diagonal shows the orientation of each tile.
+func setupTextBg(mc *testMemCtrl, depth256 bool) {
+	for t := 0; t < 1024; t++ {
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				c := 1 + t%14
+				if x == 0 || y == 0 {
+					c = 15
+				} else if x == y {
+					c = 0
+				}
+				if depth256 {
+					if c != 0 && c != 15 {
+						c = 1 + t%250
+					}
+					mc.vram[testCharBase+t*64+y*8+x] = uint8(c)
+				} else {
+					mc.vram[testCharBase+t*32+y*4+x/2] |= uint8(c) << (uint(x&1) * 4)
+				}
+			}
+		}
+	}
+
+	for blk := 0; blk < 4; blk++ {
+		for row := 0; row < 32; row++ {
+			for col := 0; col < 32; col++ {
+				tile := uint16((row*32 + col + blk*37) & 1023)
+				if col%7 == 3 {
+					tile |= 1 << 10 // hflip
+				}
+				if row%5 == 2 {
+					tile |= 1 << 11 // vflip
+				}
+				if !depth256 {
+					tile |= uint16(blk) << 12
+				}
+				off := testMapBase + blk*2048 + (row*32+col)*2
+				binary.LittleEndian.PutUint16(mc.vram[off:], tile)
+			}
+		}
+	}
+
+	// Palettes: a different hue for each 16-color palette, and a gradient
+	// for the 256-color palette. Color 0 is the backdrop.
+	rgb := func(r, g, b int) uint16 { return uint16(r | g<<5 | b<<10) }
+	for i := 1; i < 256; i++ {
+		var c uint16
+		switch {
+		case depth256:
+			c = rgb(i%32, (i/4)%32, 31-i%32)
+		case i%16 == 15:
+			c = rgb(31, 31, 31)
+		default:
+			v := 8 + (i % 16)
+			switch i / 16 {
+			case 0:
+				c = rgb(v+8, v/2, v/2)
+			case 1:
+				c = rgb(v/2, v+8, v/2)
+			case 2:
+				c = rgb(v/2, v/2, v+8)
+			default:
+				c = rgb(v+8, v+8, v/2)
+			}
+		}
+		binary.LittleEndian.PutUint16(mc.pal[i*2:], c)
+	}
+	binary.LittleEndian.PutUint16(mc.pal[0:], rgb(4, 4, 4))
+}
+
+// renderFrame renders a full frame of engine A. setLine is called before each
+// line, to change registers mid-frame.
+func renderFrame(e2d *HwEngine2d, setLine func(y int)) *image.RGBA {
+	w, h := e2d.ScreenWidth(), e2d.ScreenHeight()
+	buf := gfx.NewBufferMem(w, h)
+	e2d.BeginFrame()
+	for y := 0; y < h; y++ {
+		if setLine != nil {
+			setLine(y)
+		}
+		e2d.BeginLine(y, buf.Line(y))
+		e2d.EndLine(y)
+	}
+	e2d.EndFrame()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		line := buf.Line(y)
+		for x := 0; x < w; x++ {
+			pix := line.Get32(x)
+			img.Set(x, y, color.RGBA{uint8(pix), uint8(pix >> 8), uint8(pix >> 16), 0xFF})
+		}
+	}
+	return img
+}
+
+// stackImages concatenates images vertically
+func stackImages(imgs []*image.RGBA) *image.RGBA {
+	w, h := imgs[0].Bounds().Dx(), imgs[0].Bounds().Dy()
+	res := image.NewRGBA(image.Rect(0, 0, w, h*len(imgs)))
+	for i, img := range imgs {
+		for y := 0; y < h; y++ {
+			copy(res.Pix[(i*h+y)*res.Stride:], img.Pix[y*img.Stride:(y+1)*img.Stride])
+		}
+	}
+	return res
+}
+
+// checkGolden compares img with the golden PNG file in testdata (or updates
+// it, if -update is specified)
+func checkGolden(t *testing.T, name string, img *image.RGBA) {
+	fn := filepath.Join("testdata", name+".png")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if *updateGolden {
+		if err := ioutil.WriteFile(fn, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("%s: %v (run with -update to create it)", name, err)
+	}
+	golden, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("%s: size mismatch: got %v, want %v", name, img.Bounds(), golden.Bounds())
+	}
+	bad := 0
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			r0, g0, b0, _ := img.At(x, y).RGBA()
+			r1, g1, b1, _ := golden.At(x, y).RGBA()
+			if r0 != r1 || g0 != g1 || b0 != b1 {
+				if bad < 5 {
+					t.Errorf("%s: pixel (%d,%d) differs", name, x, y)
+				}
+				bad++
+			}
+		}
+	}
+	if bad > 0 {
+		ioutil.WriteFile(filepath.Join("testdata", name+".failed.png"), buf.Bytes(), 0644)
+		t.Errorf("%s: %d pixels differ (output saved as %s.failed.png)", name, bad, name)
+	}
+}
+
+// refExpand converts a 15-bit color to RGB888, with the expansion done by the
+// hardware (5-bit to 6-bit as 2n+1 for non-zero values, then to 8-bit by
+// replicating the top bits).
+func refExpand(c uint16) color.RGBA {
+	ch := func(v uint16) uint8 {
+		v &= 0x1F
+		if v != 0 {
+			v = v*2 + 1
+		}
+		return uint8(v<<2 | v>>4)
+	}
+	return color.RGBA{ch(c), ch(c >> 5), ch(c >> 10), 0xFF}
+}
+
+// refTextBg computes the expected frame of a text BG directly from the
+// contents of VRAM, following GBATEK rather than the engine code: the BG is
+// made of 256x256 screen blocks (arranged horizontally for size 1, vertically
+// for size 2, and 2x2 for size 3), scrolling wraps at the BG size, and each
+// map entry selects the tile, the flips and the 16-color palette.
+func refTextBg(mc *testMemCtrl, size int, depth256 bool, scrollX, scrollY func(y int) uint16) *image.RGBA {
+	bgw, bgh := 256, 256
+	if size&1 != 0 {
+		bgw = 512
+	}
+	if size&2 != 0 {
+		bgh = 512
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 256, 192))
+	for y := 0; y < 192; y++ {
+		for x := 0; x < 256; x++ {
+			bx := (int(scrollX(y)&511) + x) % bgw
+			by := (int(scrollY(y)&511) + y) % bgh
+			block := bx / 256
+			if size == 2 {
+				block = by / 256
+			} else if size == 3 {
+				block = (by/256)*2 + bx/256
+			}
+
+			off := testMapBase + block*2048 + ((by%256)/8*32+(bx%256)/8)*2
+			entry := binary.LittleEndian.Uint16(mc.vram[off:])
+			tile := int(entry & 1023)
+			px, py := bx%8, by%8
+			if entry&(1<<10) != 0 {
+				px = 7 - px
+			}
+			if entry&(1<<11) != 0 {
+				py = 7 - py
+			}
+
+			var idx int
+			if depth256 {
+				idx = int(mc.vram[testCharBase+tile*64+py*8+px])
+			} else {
+				idx = int(mc.vram[testCharBase+tile*32+py*4+px/2]>>(uint(px&1)*4)) & 0xF
+				if idx != 0 {
+					idx += int(entry>>12) * 16
+				}
+			}
+			img.Set(x, y, refExpand(binary.LittleEndian.Uint16(mc.pal[idx*2:])))
+		}
+	}
+	return img
+}
+
+// checkImage compares img with the expected image, pixel by pixel
+func checkImage(t *testing.T, name string, img, want *image.RGBA) {
+	if want.Bounds() != img.Bounds() {
+		t.Fatalf("%s: size mismatch: got %v, want %v", name, img.Bounds(), want.Bounds())
+	}
+	bad := 0
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if got, exp := img.RGBAAt(x, y), want.RGBAAt(x, y); got != exp {
+				if bad < 5 {
+					t.Errorf("%s: pixel (%d,%d) = %v, want %v", name, x, y, got, exp)
+				}
+				bad++
+			}
+		}
+	}
+	if bad > 0 {
+		t.Errorf("%s: %d pixels differ", name, bad)
+	}
+}
+
+// TestRefTextBg checks a few pixels of the reference model, worked out by
+// hand from setupTextBg.
+func TestRefTextBg(t *testing.T) {
+	mc := &testMemCtrl{}
+	setupTextBg(mc, false)
+	fixed := func(v uint16) func(int) uint16 { return func(int) uint16 { return v } }
+
+	for _, tc := range []struct {
+		size             int
+		scrollX, scrollY uint16
+		x, y             int
+		want             color.RGBA
+	}{
+		// Tile 0: top-left border, color 15 (white)
+		{0, 0, 0, 0, 0, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}},
+		// Tile 33 (row 1, col 1): transparent diagonal, backdrop
+		// rgb(4,4,4) -> 9 -> 0x24
+		{0, 0, 0, 9, 9, color.RGBA{0x24, 0x24, 0x24, 0xFF}},
+		// Tile 33: color 6 of palette 0, rgb(22,7,7) -> (45,15,15)
+		{0, 0, 0, 10, 9, color.RGBA{0xB6, 0x3C, 0x3C, 0xFF}},
+		// Size 3 at (511,511): block 3, row 31, col 31 -> tile 110,
+		// hflipped, palette 3; the pixel is its left border (white)
+		{3, 511, 511, 0, 0, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}},
+		// Size 0 wraps at 256: (300,200) shows the same as (44,200)
+		{0, 300, 200, 0, 0, refTextBg(mc, 0, false, fixed(44), fixed(200)).RGBAAt(0, 0)},
+	} {
+		img := refTextBg(mc, tc.size, false, fixed(tc.scrollX), fixed(tc.scrollY))
+		if got := img.RGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("size %d, scroll (%d,%d): pixel (%d,%d) = %v, want %v",
+				tc.size, tc.scrollX, tc.scrollY, tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func newTestEngine(mc *testMemCtrl) *HwEngine2d {
+	e2d := NewHwEngine2d(0, mc, gfx.NullLayer{})
+	// Mode 0, graphics display, BG0 on, screen base 64K
+	e2d.DispCnt.Value = 1<<16 | 1<<8 | (testMapBase/(64*1024))<<27
+	return e2d
+}
+
+// Scroll offsets tested for each BG size; they cover wraparound on both axes,
+// including the 9-bit scroll registers with values beyond the BG size.
+var testScrolls = [][2]uint16{
+	{0, 0}, {100, 37}, {300, 200}, {511, 511}, {7, 250}, {0x1F3, 0x1C0},
+}
+
+func TestTextBgSizes(t *testing.T) {
+	log.Disable()
+
+	for _, depth256 := range []bool{false, true} {
+		mc := &testMemCtrl{}
+		setupTextBg(mc, depth256)
+		e2d := newTestEngine(mc)
+
+		for size := 0; size < 4; size++ {
+			for _, scroll := range testScrolls {
+				e2d.Bg0Cnt.Value = uint16(size)<<14 | (testCharBase/(16*1024))<<2
+				if depth256 {
+					e2d.Bg0Cnt.Value |= 1 << 7
+				}
+				e2d.Bg0XOfs.Value, e2d.Bg0YOfs.Value = scroll[0], scroll[1]
+				img := renderFrame(e2d, nil)
+
+				sx, sy := scroll[0], scroll[1]
+				want := refTextBg(mc, size, depth256,
+					func(int) uint16 { return sx }, func(int) uint16 { return sy })
+				name := fmt.Sprintf("size%d/%dcol/scroll%d,%d", size, 16, sx, sy)
+				if depth256 {
+					name = fmt.Sprintf("size%d/%dcol/scroll%d,%d", size, 256, sx, sy)
+				}
+				checkImage(t, name, img, want)
+			}
+		}
+	}
+}
+
+// TestTextBgRasterScroll changes the scroll registers on each line (like
+// games doing raster effects through HBlank IRQ/DMA), checking that each line
+// uses the current values, including across screen block boundaries.
+func TestTextBgRasterScroll(t *testing.T) {
+	log.Disable()
+
+	mc := &testMemCtrl{}
+	setupTextBg(mc, false)
+	e2d := newTestEngine(mc)
+	e2d.Bg0Cnt.Value = 3 << 14 // 512x512
+
+	scrollX := func(y int) uint16 { return uint16(y * 3) }
+	scrollY := func(y int) uint16 { return uint16(240 + y*2) }
+	img := renderFrame(e2d, func(y int) {
+		e2d.Bg0XOfs.Value, e2d.Bg0YOfs.Value = scrollX(y), scrollY(y)
+	})
+	checkImage(t, "raster", img, refTextBg(mc, 3, false, scrollX, scrollY))
+}
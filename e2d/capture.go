@@ -54,9 +54,9 @@ func (e2d *HwEngine2d) capture_BeginFrame() {
 			Uint32("src", source).
 			Uint32("sa", srca).
 			Uint32("sb", srcb).
-			String("wbank", string(e2d.dispcap.WBank+'A')).
+			String("wbank", string(rune(e2d.dispcap.WBank+'A'))).
 			Uint32("woff", e2d.dispcap.WOffset).
-			String("rbank", string(e2d.dispcap.RBank+'A')).
+			String("rbank", string(rune(e2d.dispcap.RBank+'A'))).
 			Uint32("roff", e2d.dispcap.ROffset).
 			Int("w", e2d.dispcap.Width).
 			Int("h", e2d.dispcap.Height).
@@ -76,9 +76,15 @@ func (e2d *HwEngine2d) capture_BeginLine(y int, screen gfx.Line) {
 
 }
 
+// capture writes a line of the display capture into VRAM. The destination
+// bank is accessed directly (as is any bank later mapped as texture slot), so
+// a captured frame is immediately available as a direct-color texture to the
+// 3D engine, starting from the next frame: the 3D engine latches the texture
+// slot mapping at the start of its rendering (scanline 214), so the game can
+// swap the capture and texture banks during VBlank.
+//
+// Bit 15 of each captured pixel is the alpha bit used by direct textures.
 func (e2d *HwEngine2d) capture(y int) {
-	screen := e2d.curscreen
-
 	vram := e2d.mc.VramLcdcBank(e2d.dispcap.WBank)
 	if vram == nil {
 		// If destination bank is not allocated to LCDC,
@@ -101,44 +107,61 @@ func (e2d *HwEngine2d) capture(y int) {
 	}
 	readbuf := gfx.NewLine(vram)
 
-	var srca gfx.Line
-	if e2d.dispcap.SrcA == 0 {
-		// Source A is the final mixer output
-		srca = e2d.curscreen
-	} else {
-		// Source A is the 3D layer only. See in which
-		// layer it is enabled.
-		if e2d.l3dIdx < 0 {
-			panic("capturing 3D but 3D is disabled")
-		}
+	// Source A is either the final mixer output (always opaque), or
+	// the 3D layer only (opaque where a 3D pixel was drawn). The 3D layer
+	// is always rendered on engine A, even when it's not displayed.
+	srca := e2d.curscreen
+	is3d := e2d.dispcap.SrcA != 0
+	if is3d {
 		srca = e2d.lm.LayerBuffer(e2d.l3dIdx)
 	}
+	pixa := func(i int) uint16 {
+		pix := srca.Get32(i)
+		if is3d && pix == 0 {
+			return 0
+		}
+		return uint16(pix)&0x7FFF | 0x8000
+	}
 
 	switch e2d.dispcap.Mode {
 	case 0:
 		for i := 0; i < e2d.dispcap.Width; i++ {
-			pix := srca.Get32(i)
-			capbuf.Set16(i, uint16(pix)|0x8000)
+			capbuf.Set16(i, pixa(i))
 		}
 	case 1:
 		for i := 0; i < e2d.dispcap.Width; i++ {
-			pix := readbuf.Get16(i)
-			capbuf.Set16(i, uint16(pix)|0x8000)
+			capbuf.Set16(i, readbuf.Get16(i))
 		}
 	case 2, 3:
 		eva := e2d.dispcap.AlphaA
 		evb := e2d.dispcap.AlphaB
 		for i := 0; i < e2d.dispcap.Width; i++ {
-			pix1 := uint16(screen.Get32(i))
-			pix2 := uint16(readbuf.Get16(i))
-			r1, g1, b1 := (pix1 & 0x1F), ((pix1 >> 5) & 0x1F), ((pix1 >> 10) & 0x1F)
-			r2, g2, b2 := (pix2 & 0x1F), ((pix2 >> 5) & 0x1F), ((pix2 >> 10) & 0x1F)
-
-			r := (uint32(r1)*eva + uint32(r2)*evb) >> 4
-			g := (uint32(g1)*eva + uint32(g2)*evb) >> 4
-			b := (uint32(b1)*eva + uint32(b2)*evb) >> 4
-
-			capbuf.Set16(i, 0x8000|uint16(r)|uint16(g)<<5|uint16(b)<<10)
+			pix1 := pixa(i)
+			pix2 := readbuf.Get16(i)
+
+			// Transparent pixels don't contribute to the blending
+			a1, a2 := uint32(pix1>>15), uint32(pix2>>15)
+			r1, g1, b1 := uint32(pix1&0x1F)*a1, uint32((pix1>>5)&0x1F)*a1, uint32((pix1>>10)&0x1F)*a1
+			r2, g2, b2 := uint32(pix2&0x1F)*a2, uint32((pix2>>5)&0x1F)*a2, uint32((pix2>>10)&0x1F)*a2
+
+			r := (r1*eva + r2*evb) >> 4
+			g := (g1*eva + g2*evb) >> 4
+			b := (b1*eva + b2*evb) >> 4
+			if r > 31 {
+				r = 31
+			}
+			if g > 31 {
+				g = 31
+			}
+			if b > 31 {
+				b = 31
+			}
+
+			var alpha uint16
+			if (a1 != 0 && eva != 0) || (a2 != 0 && evb != 0) {
+				alpha = 0x8000
+			}
+			capbuf.Set16(i, alpha|uint16(r)|uint16(g)<<5|uint16(b)<<10)
 		}
 	}
 }
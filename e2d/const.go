@@ -12,5 +12,3 @@ const (
 )
 
 var modLcd = log.ModGfx
-
-var gKeyState []byte
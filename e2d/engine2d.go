@@ -35,14 +35,14 @@ type HwEngine2d struct {
 	Bg2PB    hwio.Reg16 `hwio:"offset=0x22,writeonly"`
 	Bg2PC    hwio.Reg16 `hwio:"offset=0x24,writeonly"`
 	Bg2PD    hwio.Reg16 `hwio:"offset=0x26,writeonly"`
-	Bg2PX    hwio.Reg32 `hwio:"offset=0x28,writeonly"`
-	Bg2PY    hwio.Reg32 `hwio:"offset=0x2C,writeonly"`
+	Bg2PX    hwio.Reg32 `hwio:"offset=0x28,writeonly,wcb"`
+	Bg2PY    hwio.Reg32 `hwio:"offset=0x2C,writeonly,wcb"`
 	Bg3PA    hwio.Reg16 `hwio:"offset=0x30,writeonly"`
 	Bg3PB    hwio.Reg16 `hwio:"offset=0x32,writeonly"`
 	Bg3PC    hwio.Reg16 `hwio:"offset=0x34,writeonly"`
 	Bg3PD    hwio.Reg16 `hwio:"offset=0x36,writeonly"`
-	Bg3PX    hwio.Reg32 `hwio:"offset=0x38,writeonly"`
-	Bg3PY    hwio.Reg32 `hwio:"offset=0x3C,writeonly"`
+	Bg3PX    hwio.Reg32 `hwio:"offset=0x38,writeonly,wcb"`
+	Bg3PY    hwio.Reg32 `hwio:"offset=0x3C,writeonly,wcb"`
 	Win0X    hwio.Reg16 `hwio:"offset=0x40,writeonly"`
 	Win1X    hwio.Reg16 `hwio:"offset=0x42,writeonly"`
 	Win0Y    hwio.Reg16 `hwio:"offset=0x44,writeonly"`
@@ -69,6 +69,16 @@ type HwEngine2d struct {
 	curline   int
 	curscreen gfx.Line
 	hwtype    HwType
+	hidden    uint8 // layers hidden for debugging (see SetLayerHidden)
+	objLimit  bool  // see SetObjLineLimit
+	refLatch  bool  // see SetRefPointLatch
+
+	// Reference points of the affine BGs written since the last line was
+	// drawn (bit 0: X, bit 1: Y)
+	refWritten [4]uint8
+
+	palPatch   *PalettePatch
+	patchedPal [1024]byte
 
 	// Display capture status.
 	dispcap struct {
@@ -206,7 +216,7 @@ func (e2d *HwEngine2d) ScreenHeight() int {
 
 func (e2d *HwEngine2d) WriteDISPCNT(old, val uint32) {
 	modLcd.InfoZ("write dispcnt").
-		String("name", string('A'+e2d.Idx)).
+		String("name", string(rune('A'+e2d.Idx))).
 		Hex32("val", val).
 		End()
 }
@@ -217,6 +227,22 @@ func (e2d *HwEngine2d) WriteDISPMMEMFIFO(old, val uint32) {
 	}
 }
 
+func (e2d *HwEngine2d) WriteBG2PX(_, _ uint32) { e2d.refWritten[2] |= 1 }
+func (e2d *HwEngine2d) WriteBG2PY(_, _ uint32) { e2d.refWritten[2] |= 2 }
+func (e2d *HwEngine2d) WriteBG3PX(_, _ uint32) { e2d.refWritten[3] |= 1 }
+func (e2d *HwEngine2d) WriteBG3PY(_, _ uint32) { e2d.refWritten[3] |= 2 }
+
+// SetRefPointLatch enables the exact emulation of the internal reference
+// points of the affine BGs: they are loaded from BGxX/BGxY at the beginning
+// of the frame and at the first line drawn after each write to the
+// registers (even if the value didn't change), and are otherwise advanced
+// by PB/PD at each line. When disabled, they are reloaded only when the
+// value of the registers changes, so that games rewriting the same value
+// at every line (eg: through HBlank DMA) see the position advance instead.
+func (e2d *HwEngine2d) SetRefPointLatch(enable bool) {
+	e2d.refLatch = enable
+}
+
 func (e2d *HwEngine2d) WriteMBRIGHT(old, val uint32) {
 	if old != val {
 		e2d.masterBrightChanged = true
@@ -247,6 +273,15 @@ func (e2d *HwEngine2d) WriteBLDY(old, val uint32) {
 	}
 }
 
+// RestoreRegs recomputes the internal state derived from the registers, after
+// they were restored without going through the write callbacks (eg: when a
+// savestate is loaded).
+func (e2d *HwEngine2d) RestoreRegs() {
+	e2d.WriteBLDALPHA(0, e2d.BldAlpha.Value)
+	e2d.masterBrightChanged = true
+	e2d.specialEffectsChanged = true
+}
+
 func (e2d *HwEngine2d) updateMasterBrightTable() {
 	// Setup master brightness lookup tables. Do this for every line just to be safe
 	brightMode := (e2d.MBright.Value >> 14) & 3
@@ -0,0 +1,124 @@
+package e2d
+
+import (
+	"encoding/binary"
+	"image/color"
+	log "ndsemu/emu/logger"
+	"testing"
+)
+
+// extPalColor returns a distinct color (and its RGB555 encoding) for each
+// extended palette slot/number pair
+func extPalColor(slot, pal int) (color.RGBA, uint16) {
+	n := (slot*3+pal)%7 + 1
+	c := color.RGBA{A: 0xFF}
+	var rgb uint16
+	if n&1 != 0 {
+		c.R, rgb = 0xFF, rgb|0x1F
+	}
+	if n&2 != 0 {
+		c.G, rgb = 0xFF, rgb|0x1F<<5
+	}
+	if n&4 != 0 {
+		c.B, rgb = 0xFF, rgb|0x1F<<10
+	}
+	return c, rgb
+}
+
+func setupExtPal(mc *testMemCtrl) {
+	// Tile 0 is a 256-color tile filled with color 1
+	for i := 0; i < 64; i++ {
+		mc.vram[i] = 1
+	}
+	for pal := 0; pal < 16; pal++ {
+		for slot := 0; slot < 4; slot++ {
+			_, rgb := extPalColor(slot, pal)
+			binary.LittleEndian.PutUint16(mc.bgext[slot*8192+(pal*256+1)*2:], rgb)
+		}
+		_, rgb := extPalColor(4, pal)
+		binary.LittleEndian.PutUint16(mc.objext[(pal*256+1)*2:], rgb)
+	}
+}
+
+func TestExtPalBgSlots(t *testing.T) {
+	log.Disable()
+
+	for _, tc := range []struct {
+		name   string
+		lidx   int
+		affine bool
+		cnt    uint16
+		slot   int
+	}{
+		{"bg0", 0, false, 0, 0},
+		{"bg0-slot2", 0, false, 1 << 13, 2},
+		{"bg1", 1, false, 0, 1},
+		{"bg1-slot3", 1, false, 1 << 13, 3},
+		{"bg2-affine", 2, true, 0, 2},
+		{"bg3-affine", 3, true, 0, 3},
+	} {
+		mc := &testMemCtrl{}
+		setupExtPal(mc)
+		e2d := newTestEngine(mc)
+		e2d.DispCnt.Value = 1<<16 | 1<<30 | 1<<uint(8+tc.lidx) | (testMapBase/(64*1024))<<27
+
+		// Tile 0 everywhere, with the palette number changing every column
+		for i := 0; i < 32*32; i++ {
+			col := i % 32
+			if tc.affine {
+				col = i % 16 // 128x128 BG, 16 tiles per row
+			}
+			binary.LittleEndian.PutUint16(mc.vram[testMapBase+i*2:], uint16(col%16)<<12)
+		}
+
+		*e2d.bgregs[tc.lidx].Cnt = tc.cnt
+		if tc.affine {
+			// Mode 5: BG2/BG3 are extended affine; with 256-color bit cleared,
+			// they use 16-bit map entries. Identity transformation.
+			e2d.DispCnt.Value |= 5
+			*e2d.bgregs[tc.lidx].PA = 0x100
+			*e2d.bgregs[tc.lidx].PD = 0x100
+		} else {
+			*e2d.bgregs[tc.lidx].Cnt |= 1 << 7
+		}
+		img := renderFrame(e2d, nil)
+
+		ncols := 32
+		if tc.affine {
+			ncols = 16
+		}
+		for col := 0; col < ncols && col*8 < 256; col++ {
+			exp, _ := extPalColor(tc.slot, col%16)
+			if got := img.RGBAAt(col*8+4, 4); got != exp {
+				t.Errorf("%s: column %d: got %v, want %v", tc.name, col, got, exp)
+			}
+		}
+	}
+}
+
+func TestExtPalObj(t *testing.T) {
+	log.Disable()
+
+	mc := &testMemCtrl{}
+	setupExtPal(mc)
+	for i := 0; i < 128; i++ {
+		setObj(mc, i, 0, 0, 2<<8, 0, 0) // hidden
+	}
+	// Two 256-color 8x8 sprites, using tile 0 and different palettes; the
+	// second one is affine (identity transformation).
+	setObj(mc, 0, 8, 0, 1<<13, 0, 5<<12)
+	setObj(mc, 1, 24, 0, 1<<13|1<<8, 0, 9<<12)
+	binary.LittleEndian.PutUint16(mc.oam[0x06:], 0x100)
+	binary.LittleEndian.PutUint16(mc.oam[0x1E:], 0x100)
+
+	e2d := newTestEngine(mc)
+	e2d.DispCnt.Value = 1<<16 | 1<<12 | 1<<4 | 1<<31
+
+	img := renderFrame(e2d, nil)
+	for _, c := range []struct{ x, pal int }{{12, 5}, {28, 9}} {
+		exp, _ := extPalColor(4, c.pal)
+		if got := img.RGBAAt(c.x, 4); got != exp {
+			t.Errorf("obj at %d: got %v, want %v", c.x, got, exp)
+		}
+	}
+}
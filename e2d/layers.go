@@ -22,6 +22,39 @@ const (
 	BgMode3D
 )
 
+// Layers that can be hidden for debugging, to isolate which one is
+// responsible for a glitch
+const (
+	LayerBG0 = iota
+	LayerBG1
+	LayerBG2
+	LayerBG3
+	LayerOBJ
+	Layer3D
+	NumLayers
+)
+
+// SetLayerHidden hides or shows a layer, irrespective of the game settings.
+// The 3D layer only exists on engine A.
+func (e2d *HwEngine2d) SetLayerHidden(lidx int, hidden bool) {
+	if hidden {
+		e2d.hidden |= 1 << uint(lidx)
+	} else {
+		e2d.hidden &^= 1 << uint(lidx)
+	}
+}
+
+// TakeSettings moves the debugging settings (hidden layers) of another
+// engine into this one (used when the hardware is recreated on reset)
+func (e2d *HwEngine2d) TakeSettings(old *HwEngine2d) {
+	e2d.hidden = old.hidden
+}
+
+// LayerHidden reports whether a layer was hidden with SetLayerHidden
+func (e2d *HwEngine2d) LayerHidden(lidx int) bool {
+	return e2d.hidden&(1<<uint(lidx)) != 0
+}
+
 func (e2d *HwEngine2d) layers_BeginFrame() {
 	bgmode := e2d.DispCnt.Value & 7
 	bg3d := (e2d.DispCnt.Value>>3)&1 != 0
@@ -31,6 +64,9 @@ func (e2d *HwEngine2d) layers_BeginFrame() {
 	if e2d.A() {
 		if bg3d || bgmode == 6 {
 			e2d.setBgMode(0, BgMode3D)
+			if e2d.LayerHidden(Layer3D) {
+				e2d.lm.ChangeLayer(0, gfx.NullLayer{})
+			}
 			e2d.lm.ChangeLayer(5, gfx.NullLayer{})
 			e2d.l3dIdx = 0
 		} else {
@@ -103,7 +139,7 @@ func (e2d *HwEngine2d) layers_Debug() {
 	objwinon := (e2d.DispCnt.Value >> 15) & 1
 
 	modLcd.Infof("%s: modes=%v bg=[%d,%d,%d,%d] pri=[%d %d %d %d] obj=%d win=[%d,%d,%d]",
-		string('A'+e2d.Idx), e2d.bgmodes, bg0on, bg1on, bg2on, bg3on,
+		string(rune('A'+e2d.Idx)), e2d.bgmodes, bg0on, bg1on, bg2on, bg3on,
 		e2d.bgregs[0].priority(), e2d.bgregs[1].priority(), e2d.bgregs[2].priority(), e2d.bgregs[3].priority(),
 		objon, win0on, win1on, objwinon)
 	modLcd.Infof("%s: fx: mode=%d 1st=%x 2nd=%x alpha1=%d, alpha2=%d",
@@ -111,7 +147,7 @@ func (e2d *HwEngine2d) layers_Debug() {
 		e2d.effectAlpha1, e2d.effectAlpha2)
 
 	// modLcd.Infof("%s: scroll0=[%d,%d] scroll1=[%d,%d] scroll2=[%d,%d] scroll3=[%d,%d] size0=%d size3=%d",
-	// 	string('A'+e2d.Idx),
+	// 	string(rune('A'+e2d.Idx)),
 	// 	e2d.Bg0XOfs.Value, e2d.Bg0YOfs.Value,
 	// 	e2d.Bg1XOfs.Value, e2d.Bg1YOfs.Value,
 	// 	e2d.Bg2XOfs.Value, e2d.Bg2YOfs.Value,
@@ -125,6 +161,9 @@ func (e2d *HwEngine2d) layers_EndFrame() {
 
 func (e2d *HwEngine2d) layers_BeginLine(y int, screen gfx.Line) {
 	pram := e2d.mc.VramPalette(e2d.Idx)
+	if e2d.palPatch != nil {
+		pram = e2d.patchPalette(pram)
+	}
 	bgPal := pram[:512]
 	objPal := pram[512:]
 
@@ -26,6 +26,10 @@ func (p LayerPixel) Direct() bool        { return int32(p) < 0 }
 func (p LayerPixel) DirectColor() uint16 { return uint16(p & 0x7FFF) }
 func (p LayerPixel) Transparent() bool   { return p == 0 }
 
+// Is3D returns true if the pixel comes from the 3D layer. The 3D layer always
+// replaces BG0, and it's the only one producing direct colors on it.
+func (p LayerPixel) Is3D() bool { return p.Layer() == 0 && p.Direct() }
+
 const (
 	// Initialize the pixel as layer backdrop (5) with lowest priority (3).
 	BackdropPixel LayerPixel = (5<<26 | 3<<29)
@@ -164,6 +168,17 @@ draw:
 		goto exit
 	}
 
+	// A 3D pixel is always blended with the pixel below using its own
+	// alpha, if the latter is marked as target #2, irrespective of the
+	// effect mode and of the 1st target bits in BLDCNT. In this case,
+	// no other effect is applied.
+	if pix1.Is3D() && ((bld>>8)>>pix2.Layer())&1 != 0 {
+		if pix1.Alpha() < 31 {
+			goto alpha
+		}
+		goto exit
+	}
+
 	// If the pixel is forcing alpha blending, obey.
 	// This overrides the settings in registers (both mode
 	// and 1st target bits in BLDCNT)
@@ -203,7 +218,14 @@ alpha:
 		r2, g2, b2 := rgb2&0x1f, (rgb2>>5)&0x1F, (rgb2>>10)&0x1F
 
 		// blend
-		if pix1.HasAlpha() {
+		if pix1.Is3D() {
+			// 3D alpha is 0-31, where 31 is opaque
+			a1 := pix1.Alpha() + 1
+			a2 := 32 - a1
+			r1 = (r1*a1 + r2*a2) >> 5
+			g1 = (g1*a1 + g2*a2) >> 5
+			b1 = (b1*a1 + b2*a2) >> 5
+		} else if pix1.HasAlpha() {
 			a1 := pix1.Alpha()
 			a2 := 31 - a1
 			r1 = (r1*a1 + r2*a2) >> 5
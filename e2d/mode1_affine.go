@@ -2,7 +2,6 @@ package e2d
 
 import (
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
 )
 
 var bmpSize = []struct{ w, h int }{
@@ -20,7 +19,7 @@ func (e2d *HwEngine2d) DrawBGAffine(lidx int) func(gfx.Line) {
 	charBase := -1
 
 	if e2d.DispCnt.Value&onmask != 0 {
-		ch := string('A' + e2d.Idx)
+		ch := string(rune('A' + e2d.Idx))
 		dx := int32(int16(*regs.PA))
 		dy := int32(int16(*regs.PC))
 		dmx := int32(int16(*regs.PB))
@@ -33,14 +32,11 @@ func (e2d *HwEngine2d) DrawBGAffine(lidx int) func(gfx.Line) {
 
 	var origx, origy uint32
 	var startx, starty int32
+	latched := false
 
 	y := 0
 	return func(line gfx.Line) {
-		if e2d.DispCnt.Value&onmask == 0 || gKeyState[hw.SCANCODE_1+lidx] != 0 {
-			y++
-			return
-		}
-		if (e2d.A() && gKeyState[hw.SCANCODE_9] != 0) || (e2d.B() && gKeyState[hw.SCANCODE_8] != 0) {
+		if e2d.DispCnt.Value&onmask == 0 || e2d.LayerHidden(lidx) {
 			y++
 			return
 		}
@@ -80,11 +76,20 @@ func (e2d *HwEngine2d) DrawBGAffine(lidx int) func(gfx.Line) {
 		// If PX/PY was changed between lines, reload it (raster effect)
 		// Notice that PX/PY isn't automatically updated each line by the GPU,
 		// it always retains the last value written by the CPU.
-		if origx != *regs.PX {
+		reloadx, reloady := origx != *regs.PX, origy != *regs.PY
+		if e2d.refLatch {
+			// Reload at the first line of the frame, and after any write
+			written := e2d.refWritten[lidx]
+			reloadx = !latched || written&1 != 0
+			reloady = !latched || written&2 != 0
+		}
+		e2d.refWritten[lidx] = 0
+		latched = true
+		if reloadx {
 			origx = *regs.PX
 			startx = int32(origx<<4) >> 4
 		}
-		if origy != *regs.PY {
+		if reloady {
 			origy = *regs.PY
 			starty = int32(origy<<4) >> 4
 		}
@@ -188,20 +193,23 @@ func (e2d *HwEngine2d) DrawBGAffine(lidx int) func(gfx.Line) {
 					// 256-color tiles only have one palette in normal (GBA) mode, but
 					// can have multiple palettes in extended palette mode.
 					// So we ignore the palette number if extended palette is disabled
-					// (it should be already zero, but better safe than sorry)
+					// (it should be already zero, but better safe than sorry).
+					// The palette is per tile, so it must not be accumulated
+					// into the line attributes.
+					tattrs := attrs
 					if useExtPal {
-						attrs |= uint32(pal<<8) | (1 << 12)
+						tattrs |= uint32(pal<<8) | (1 << 12)
 					}
 
 					if !hflip {
 						p0 := uint32(ch[px&7])
 						if p0 != 0 {
-							line.Set32(0, p0|attrs)
+							line.Set32(0, p0|tattrs)
 						}
 					} else {
 						p0 := uint32(ch[7-(px&7)])
 						if p0 != 0 {
-							line.Set32(0, p0|attrs)
+							line.Set32(0, p0|tattrs)
 						}
 					}
 				}
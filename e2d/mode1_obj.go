@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"ndsemu/emu"
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
 )
 
 const (
@@ -30,6 +29,78 @@ var objWidth = []struct{ w, h int }{
 	{1, 2}, {1, 4}, {2, 4}, {4, 8},
 }
 
+// SetObjLineLimit enables the per-scanline OBJ cycle budget (see
+// objLineCycles). It is disabled by default, as it costs a bit of performance
+// and only matters for the few games that exceed it on purpose.
+func (e2d *HwEngine2d) SetObjLineLimit(enable bool) {
+	e2d.objLimit = enable
+}
+
+// Number of OBJ rendering cycles available for each scanline. The hardware
+// evaluates sprites in OAM order, and stops as soon as the budget is
+// exhausted: sprites that do not fit are not drawn (this is the classic
+// "too many sprites on a line" limit, which some games exploit on purpose,
+// eg: to hide sprites behind dummy ones). The budget is smaller when the
+// OBJ unit is not allowed to access VRAM during H-blank ("H-blank interval
+// free" bit of DISPCNT).
+func (e2d *HwEngine2d) objLineCycles() int {
+	if e2d.hwtype == HwNds {
+		if e2d.DispCnt.Value&(1<<23) != 0 {
+			return 1616
+		}
+		return 2130
+	}
+	if e2d.DispCnt.Value&(1<<5) != 0 {
+		return 954
+	}
+	return 1210
+}
+
+// objLineLimit returns the number of OAM entries that are processed for
+// the specified scanline, before the cycle budget is exhausted (128 if all
+// of them fit, or if the budget is not emulated).
+func (e2d *HwEngine2d) objLineLimit(oam []byte, sy int) int {
+	if !e2d.objLimit {
+		return 128
+	}
+	cScreenHeight := e2d.ScreenHeight()
+	cycles := e2d.objLineCycles()
+	for i := 0; i < 128; i++ {
+		a0 := uint16(oam[i*8+1])<<8 | uint16(oam[i*8+0])
+		a1 := uint16(oam[i*8+3])<<8 | uint16(oam[i*8+2])
+		mode := (a0 >> 8) & 3
+		if mode == objModeHidden {
+			continue
+		}
+
+		y := int(a0 & 0xFF)
+		if y >= cScreenHeight {
+			y -= 0x100
+		}
+		sz := objWidth[((a0>>14)<<2)|(a1>>14)]
+		w, h := sz.w*8, sz.h*8
+		if mode == objModeAffineDouble {
+			w, h = w*2, h*2
+		}
+		if sy < y || sy >= y+h {
+			continue
+		}
+
+		// Normal sprites cost one cycle per pixel, affine sprites two (plus
+		// a fixed setup cost). Sprites are paid in full even if they are
+		// partly (or totally) out of the screen horizontally.
+		if mode == objModeNormal {
+			cycles -= w
+		} else {
+			cycles -= 10 + w*2
+		}
+		if cycles < 0 {
+			return i
+		}
+	}
+	return 128
+}
+
 func objBitmap_CalcAddress_2D128(tilenum int) int {
 	return int((tilenum&0xF)*0x10 + (tilenum & ^0xF)*0x80)
 }
@@ -95,25 +166,24 @@ func (e2d *HwEngine2d) drawOBJ(lidx int, drawWindow bool) func(gfx.Line) {
 			sy++
 			return
 		}
-		if gKeyState[hw.SCANCODE_6] != 0 {
+		if e2d.LayerHidden(LayerOBJ) {
 			sy++
 			return
 		}
 
 		// Reverse sort: higher numbers should be drawn first
-		// (so they get overwritten by lower numbers that have higher priority)
-		// FIXME: This is actually a temporary hack because it will fail once we
-		// emulate the sprite line limits; the correct solution would be
-		// to go through in the correct order, but avoiding writing pixels
-		// that have been already written to.
+		// (so they get overwritten by lower numbers that have higher priority).
+		// Sprites that exceed the line cycle budget are skipped altogether.
 		// NOTE: this code is pretty hot
 		var cnt [4]int
 		var decsprites [4][128 * 3]uint16
 		var any bool
+		limit := e2d.objLineLimit(oam, sy)
 		oidx := 127 * 8
 		for i := 0; i < 128; i++ {
-			// Immediately skip hidden sprites (fast path)
-			if oam[oidx+1]&3 == objModeHidden {
+			// Immediately skip hidden sprites (fast path), and those beyond
+			// the cycle budget
+			if oam[oidx+1]&3 == objModeHidden || 127-i >= limit {
 				oidx -= 8
 				continue
 			}
@@ -275,19 +345,21 @@ func (e2d *HwEngine2d) drawOBJ(lidx int, drawWindow bool) func(gfx.Line) {
 
 					// See if we need to draw in affine mode
 					if mode != objModeNormal {
-						if pixmode == objPixModeBitmap {
-							panic("bitmap mode not supported in affine")
-						}
+						// Rotation/scaling parameters are stored in groups of
+						// four OAM entries, in the otherwise unused 4th attribute
+						// (PA, PB, PC, PD at offsets 0x06, 0x0E, 0x16, 0x1E).
 						parms := ((a1>>9)&0x1F)*0x20 + 0x6
 						dx := int(int16(emu.Read16LE(oam[parms:])))
 						dmx := int(int16(emu.Read16LE(oam[parms+8:])))
 						dy := int(int16(emu.Read16LE(oam[parms+16:])))
 						dmy := int(int16(emu.Read16LE(oam[parms+24:])))
 
+						// The transformation is centered on the middle of the
+						// object; in double-size mode, the drawing area is
+						// twice as big but the source texture is not.
 						sx := (tw*8/2)<<8 - (tws*8/2)*dx - (ths*8/2)*dmx + y0*dmx
 						sy := (th*8/2)<<8 - (tws*8/2)*dy - (ths*8/2)*dmy + y0*dmy
 
-						src := tiles.FetchPointer(vramOffset)
 						dst := line
 
 						attrs := uint32(pri) << 29
@@ -295,7 +367,13 @@ func (e2d *HwEngine2d) drawOBJ(lidx int, drawWindow bool) func(gfx.Line) {
 						if pixmode == objPixModeAlpha {
 							attrs |= 1 << 25
 						}
-						if depth256 {
+						if pixmode == objPixModeBitmap {
+							// "pal" is reused as alpha (see below)
+							if pal == 0 {
+								continue
+							}
+							attrs |= 0x80000000 | 1<<24 | uint32(pal<<1+1)<<16
+						} else if depth256 {
 							if useExtPal {
 								attrs |= uint32(pal<<8) | (1 << 12)
 							}
@@ -303,29 +381,38 @@ func (e2d *HwEngine2d) drawOBJ(lidx int, drawWindow bool) func(gfx.Line) {
 							attrs |= uint32(pal << 4)
 						}
 
+						// Pixels are fetched one by one, as the object can
+						// span multiple VRAM banks.
 						for j := 0; j < tws*8; j++ {
 							if x >= 0 && x < cScreenWidth {
 								isx, isy := sx>>8, sy>>8
 								if isx >= 0 && isx < tw*8 && isy >= 0 && isy < th*8 {
-									ty := isy / 8
-									off := (pitch * charSize) * ty
-									isy &= 7
-
-									tx := isx / 8
-									off += charSize * tx
-									isx &= 7
-
-									if depth256 {
-										pix := uint32(src[off+isy*8+isx])
-										if pix != 0 {
-											dst.Set32(x, pix|attrs)
+									if pixmode == objPixModeBitmap {
+										px := uint32(tiles.Get16(vramOffset/2 + isy*pitch*8 + isx))
+										if px&0x8000 != 0 {
+											dst.Set32(x, px|attrs)
 										}
 									} else {
-										pix := uint32(src[off+isy*4+isx/2])
-										pix >>= 4 * uint(isx&1)
-										pix &= 0xF
-										if pix != 0 {
-											dst.Set32(x, pix|attrs)
+										ty := isy / 8
+										off := vramOffset + (pitch*charSize)*ty
+										isy &= 7
+
+										tx := isx / 8
+										off += charSize * tx
+										isx &= 7
+
+										if depth256 {
+											pix := uint32(tiles.Get8(off + isy*8 + isx))
+											if pix != 0 {
+												dst.Set32(x, pix|attrs)
+											}
+										} else {
+											pix := uint32(tiles.Get8(off + isy*4 + isx/2))
+											pix >>= 4 * uint(isx&1)
+											pix &= 0xF
+											if pix != 0 {
+												dst.Set32(x, pix|attrs)
+											}
 										}
 									}
 								}
@@ -350,7 +437,7 @@ func (e2d *HwEngine2d) drawOBJ(lidx int, drawWindow bool) func(gfx.Line) {
 								// Embed it in every pixel, in case
 								// we need to alpha blend. We extend it to 5 bits.
 								pal = pal<<1 + 1
-								attrs |= 1<<24 | uint32(pal)<<20
+								attrs |= 1<<24 | uint32(pal)<<16
 
 								for j := 0; j < tw*8; j++ {
 									if x >= 0 && x < cScreenWidth {
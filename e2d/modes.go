@@ -3,7 +3,6 @@ package e2d
 import (
 	"ndsemu/emu"
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
 )
 
 /************************************************
@@ -11,10 +10,6 @@ import (
  ************************************************/
 
 func (e2d *HwEngine2d) BeginFrame() {
-	if gKeyState == nil {
-		gKeyState = hw.GetKeyboardState()
-	}
-
 	// Read current display mode once per frame (do not switch between
 	// display modes within a frame)
 	e2d.dispmode = int((e2d.DispCnt.Value >> 16) & 3)
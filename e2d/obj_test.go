@@ -0,0 +1,82 @@
+package e2d
+
+import (
+	"encoding/binary"
+	"image/color"
+	log "ndsemu/emu/logger"
+	"testing"
+)
+
+func setObj(mc *testMemCtrl, idx int, x, y int, a0, a1, a2 uint16) {
+	binary.LittleEndian.PutUint16(mc.oam[idx*8:], a0|uint16(y&0xFF))
+	binary.LittleEndian.PutUint16(mc.oam[idx*8+2:], a1|uint16(x&0x1FF))
+	binary.LittleEndian.PutUint16(mc.oam[idx*8+4:], a2)
+}
+
+func TestObjLineBudget(t *testing.T) {
+	log.Disable()
+
+	const (
+		objSquare64 = 3 << 14 // shape=square, size=64x64 (in a1)
+		objAffine   = 1 << 8
+	)
+
+	for _, tc := range []struct {
+		name    string
+		limit   bool   // budget emulated
+		hblank  bool   // OBJ processing during H-blank disallowed
+		a0      uint16 // extra attributes for the dummy sprites
+		ndummy  int    // number of dummy sprites before the test sprite
+		visible bool
+	}{
+		// 64-pixel wide sprites cost 64 cycles, and the test sprite 8: out
+		// of 2130 cycles, there is room for 33 dummies; with 1616 cycles
+		// (no H-blank access), for 25
+		{"fit", true, false, 0, 33, true},
+		{"exceed", true, false, 0, 34, false},
+		{"hblank-fit", true, true, 0, 25, true},
+		{"hblank-exceed", true, true, 0, 26, false},
+		// affine sprites: 2*64+10 cycles each
+		{"affine-fit", true, false, objAffine, 15, true},
+		{"affine-exceed", true, false, objAffine, 16, false},
+		// without the budget, all sprites are drawn
+		{"nolimit", false, false, objAffine, 127, true},
+	} {
+		mc := &testMemCtrl{}
+		// Tile 1 is filled with color 1 (white), all the others are transparent
+		for i := 32; i < 64; i++ {
+			mc.vram[i] = 0x11
+		}
+		binary.LittleEndian.PutUint16(mc.pal[0x200+2:], 0x7FFF)
+		for i := 0; i < 128; i++ {
+			setObj(mc, i, 0, 0, 2<<8, 0, 0) // hidden
+		}
+
+		// Dummy sprites are fully offscreen horizontally, but still consume
+		// cycles; the test sprite (8x8, tile 1) comes after them.
+		for i := 0; i < tc.ndummy; i++ {
+			setObj(mc, i, 300, 0, tc.a0, objSquare64, 0)
+		}
+		setObj(mc, tc.ndummy, 16, 0, 0, 0, 1)
+
+		e2d := newTestEngine(mc)
+		e2d.SetObjLineLimit(tc.limit)
+		e2d.DispCnt.Value = 1<<16 | 1<<12 | 1<<4 // OBJ on, 1D mapping
+		if tc.hblank {
+			e2d.DispCnt.Value |= 1 << 23
+		}
+		img := renderFrame(e2d, nil)
+
+		// Backdrop is black, so any other color is the sprite
+		black := color.RGBA{0, 0, 0, 0xFF}
+		if got := img.RGBAAt(20, 4) != black; got != tc.visible {
+			t.Errorf("%s: sprite visible=%v, want %v", tc.name, got, tc.visible)
+		}
+		// The budget is per line: below the dummy sprites, the test sprite
+		// would always be visible, but it is only 8 pixels tall, so just
+		// check that other lines are empty.
+		if img.RGBAAt(20, 100) != black {
+			t.Errorf("%s: unexpected sprite pixel", tc.name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package e2d
+
+import (
+	"encoding/binary"
+	"image"
+	"math"
+	log "ndsemu/emu/logger"
+	"testing"
+)
+
+// setAffineParms writes a rotation/scaling parameter group into OAM
+func setAffineParms(mc *testMemCtrl, group int, pa, pb, pc, pd int) {
+	for i, v := range []int{pa, pb, pc, pd} {
+		binary.LittleEndian.PutUint16(mc.oam[group*0x20+i*8+6:], uint16(v))
+	}
+}
+
+// setRotScale writes a parameter group that rotates by the specified angle
+// (in degrees) and scales by the specified factor
+func setRotScale(mc *testMemCtrl, group int, angle, scale float64) {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	fx := func(v float64) int { return int(math.Floor(v*256/scale + 0.5)) }
+	setAffineParms(mc, group, fx(cos), fx(sin), fx(-sin), fx(cos))
+}
+
+func TestObjAffine(t *testing.T) {
+	log.Disable()
+
+	const (
+		objAffine       = 1 << 8
+		objAffineDouble = 3 << 8
+		objBitmap       = 3 << 10
+		obj256          = 1 << 13
+		objSquare       = 0 << 14
+		objWide         = 1 << 14
+		objTall         = 2 << 14
+	)
+
+	var frames []*image.RGBA
+	for _, kind := range []string{"16col", "256col", "bitmap"} {
+		mc := &testMemCtrl{}
+		setupTextBg(mc, kind == "256col")
+		copy(mc.pal[512:1024], mc.pal[:512])
+		binary.LittleEndian.PutUint16(mc.pal[0:], 0)
+
+		// Bitmap data for bitmap sprites, at 128K (tile 512 with 256-byte
+		// boundary); some pixels are transparent.
+		for i := 0; i < 64*64; i++ {
+			px := uint16(i%32|(i/32)%32<<5|(i/64)%32<<10) | 0x8000
+			if i%13 == 0 {
+				px = 0
+			}
+			binary.LittleEndian.PutUint16(mc.vram[128*1024+i*2:], px)
+		}
+
+		for i := 0; i < 128; i++ {
+			setObj(mc, i, 0, 0, 2<<8, 0, 0) // hidden
+		}
+		setAffineParms(mc, 0, 0x100, 0, 0, 0x100)
+		setRotScale(mc, 1, 30, 1)
+		setAffineParms(mc, 2, 0x80, 0, 0, 0x80)
+		setRotScale(mc, 3, 45, 0.75)
+		setAffineParms(mc, 4, 0x100, 0x40, 0, 0x100)
+		setRotScale(mc, 5, -60, 1.5)
+
+		var a0, a2 uint16
+		switch kind {
+		case "16col":
+			a2 = 2 << 12 // palette 2 (blue)
+		case "256col":
+			a0 = obj256
+			a2 = 64 // tile 32 (256-color) on a 32-byte boundary
+		case "bitmap":
+			a0 = objBitmap
+			a2 = 512 | 15<<12 // opaque
+		}
+
+		for _, o := range []struct {
+			x, y   int
+			a0, a1 uint16
+			group  uint16
+		}{
+			{8, 8, objAffine | objSquare, 2 << 14, 0},                // 32x32 identity
+			{56, 8, objAffine | objSquare, 2 << 14, 1},               // 32x32 rotated
+			{104, 8, objAffineDouble | objSquare, 2 << 14, 1},        // 32x32 rotated, double size
+			{176, 8, objAffineDouble | objSquare, 2 << 14, 2},        // 32x32 zoomed 2x, double size
+			{8, 88, objAffine | objWide, 3 << 14, 3},                 // 64x32 rotated and scaled
+			{80, 80, objAffineDouble | objWide, 3 << 14, 3},          // same, double size
+			{216, 88, objAffine | objTall, 2 << 14, 4},               // 16x32 sheared
+			{0x1E2, 150, objAffineDouble | objSquare, 2 << 14, 5},    // clipped left and bottom
+			{100, 240, objAffine | objSquare, 2 << 14, 0},            // wraps from the top
+			{200, 160, objAffineDouble | objSquare, 1 << 14, 1 << 3}, // 16x16, group 8 (unset: all zero)
+		} {
+			for i := 0; i < 128; i++ {
+				if mc.oam[i*8+1]&3 == objModeHidden {
+					setObj(mc, i, o.x, o.y, o.a0|a0, o.a1|o.group<<9, a2)
+					break
+				}
+			}
+		}
+
+		e2d := newTestEngine(mc)
+		// OBJ on, 1D mapping (32-byte boundary), 1D bitmap mapping
+		// (256-byte boundary)
+		e2d.DispCnt.Value = 1<<16 | 1<<12 | 1<<4 | 1<<6 | 1<<22
+		frames = append(frames, renderFrame(e2d, nil))
+	}
+
+	checkGolden(t, "objaffine", stackImages(frames))
+}
@@ -0,0 +1,47 @@
+package e2d
+
+import (
+	"ndsemu/emu"
+)
+
+// PalettePatch overrides colors of the standard BG and OBJ palettes at
+// composition time, without touching palette RAM (so the game can't see
+// it). It's meant to preview romhacks, or to remap colors that are hard to
+// tell apart for colorblind players. Extended palettes are not patched.
+type PalettePatch struct {
+	// Colors replaced wherever they appear in the palettes (RGB555)
+	Remap map[uint16]uint16
+
+	// Entries of the BG/OBJ palettes overridden by index (0-255); they
+	// are applied after Remap.
+	Bg, Obj map[int]uint16
+}
+
+// SetPalettePatch configures the palette overrides for this engine
+// (nil disables them)
+func (e2d *HwEngine2d) SetPalettePatch(p *PalettePatch) {
+	e2d.palPatch = p
+}
+
+// patchPalette returns a copy of the palette RAM of the engine (BG palette
+// followed by OBJ palette) with the palette patch applied.
+func (e2d *HwEngine2d) patchPalette(pram []byte) []byte {
+	p := e2d.palPatch
+	copy(e2d.patchedPal[:], pram)
+
+	if len(p.Remap) != 0 {
+		for i := 0; i < len(e2d.patchedPal); i += 2 {
+			col := emu.Read16LE(e2d.patchedPal[i:]) & 0x7FFF
+			if newcol, found := p.Remap[col]; found {
+				emu.Write16LE(e2d.patchedPal[i:], newcol)
+			}
+		}
+	}
+	for idx, col := range p.Bg {
+		emu.Write16LE(e2d.patchedPal[idx*2:], col)
+	}
+	for idx, col := range p.Obj {
+		emu.Write16LE(e2d.patchedPal[512+idx*2:], col)
+	}
+	return e2d.patchedPal[:]
+}
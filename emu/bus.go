@@ -14,3 +14,10 @@ type Bus interface {
 
 	FetchPointer(address uint32) []uint8
 }
+
+// DebugBus is implemented by buses that can be accessed without side effects,
+// for debugging purposes (see hwio.Table.Peek8 for details).
+type DebugBus interface {
+	Peek8(address uint32) uint8
+	Poke8(address uint32, val uint8) bool
+}
@@ -0,0 +1,241 @@
+// Package config implements layered configuration on top of the standard
+// flag package. Each option is a flag, and its value can come from (in order
+// of increasing precedence):
+//
+//   - the default value of the flag;
+//   - a TOML configuration file, where keys are flag names;
+//   - an environment variable, named after the flag with a prefix, in
+//     uppercase, and with dashes replaced by underscores (eg: with prefix
+//     "NDSEMU_", the flag "screen-gap" is read from NDSEMU_SCREEN_GAP);
+//   - the command line.
+//
+// This way, all options are available with the same names and validation
+// whatever the way they are specified.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Source is where the value of an option comes from
+type Source int
+
+const (
+	SourceDefault Source = iota
+	SourceFile
+	SourceEnv
+	SourceFlag
+)
+
+var sourceNames = [...]string{"default", "file", "env", "flag"}
+
+func (s Source) String() string { return sourceNames[s] }
+
+// Name of the flag used to select the configuration file
+const FileFlag = "config"
+
+type Config struct {
+	fs        *flag.FlagSet
+	envPrefix string
+
+	// Configuration file loaded if none is specified (through FileFlag or
+	// its environment variable). It's not an error if it doesn't exist.
+	DefaultFile string
+
+	file    string // configuration file actually loaded (if any)
+	sources map[string]Source
+	checks  map[string]func(string) error
+}
+
+// New creates a layered configuration for the flags defined in fs.
+func New(fs *flag.FlagSet, envPrefix string) *Config {
+	return &Config{
+		fs:        fs,
+		envPrefix: envPrefix,
+		sources:   make(map[string]Source),
+		checks:    make(map[string]func(string) error),
+	}
+}
+
+// Check registers a validation function for a flag, for constraints that
+// can't be expressed by its type (eg: a list of valid values). It's called
+// on the final value of the flag.
+func (c *Config) Check(name string, fn func(value string) error) {
+	c.checks[name] = fn
+}
+
+// EnvName returns the name of the environment variable for a flag
+func (c *Config) EnvName(name string) string {
+	return c.envPrefix + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// Load parses the command line arguments (excluding the program name), and
+// completes the configuration with the configuration file and the environment.
+// If a flag named FileFlag is defined, it selects the configuration file.
+func (c *Config) Load(args []string) error {
+	if err := c.fs.Parse(args); err != nil {
+		return err
+	}
+	c.fs.Visit(func(f *flag.Flag) { c.sources[f.Name] = SourceFlag })
+
+	if err := c.loadFile(); err != nil {
+		return err
+	}
+
+	var err error
+	c.fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || c.sources[f.Name] == SourceFlag {
+			return
+		}
+		if val, ok := os.LookupEnv(c.EnvName(f.Name)); ok {
+			if serr := c.fs.Set(f.Name, val); serr != nil {
+				err = fmt.Errorf("%s: invalid value %q: %v", c.EnvName(f.Name), val, serr)
+				return
+			}
+			c.sources[f.Name] = SourceEnv
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.validate()
+}
+
+func (c *Config) loadFile() error {
+	// The file can be selected with the flag (already parsed) or the
+	// environment, or we fallback to the default file if it exists
+	fn, explicit := "", true
+	if f := c.fs.Lookup(FileFlag); f != nil && c.sources[FileFlag] == SourceFlag {
+		fn = f.Value.String()
+	} else if val, ok := os.LookupEnv(c.EnvName(FileFlag)); ok {
+		fn = val
+	} else if c.DefaultFile != "" {
+		fn, explicit = c.DefaultFile, false
+	}
+	if fn == "" {
+		return nil
+	}
+
+	var values map[string]interface{}
+	if _, err := toml.DecodeFile(fn, &values); err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	c.file = fn
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if c.fs.Lookup(k) == nil || k == FileFlag {
+			return fmt.Errorf("%s: unknown option %q", fn, k)
+		}
+		if c.sources[k] == SourceFlag {
+			continue
+		}
+		val, err := formatValue(values[k])
+		if err == nil {
+			err = c.fs.Set(k, val)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: invalid value for %q: %v", fn, k, err)
+		}
+		c.sources[k] = SourceFile
+	}
+	return nil
+}
+
+// formatValue converts a TOML value into the string syntax of flags. Arrays
+// are converted into comma-separated lists.
+func formatValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i := range v {
+			s, err := formatValue(v[i])
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", errors.New("unsupported value type")
+}
+
+func (c *Config) validate() error {
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := c.fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if err := c.checks[name](f.Value.String()); err != nil {
+			return fmt.Errorf("invalid %s (from %v): %v", name, c.Source(name), err)
+		}
+	}
+	return nil
+}
+
+// Source returns where the value of a flag comes from
+func (c *Config) Source(name string) Source {
+	return c.sources[name]
+}
+
+// File returns the path of the configuration file that was loaded (or an
+// empty string if none)
+func (c *Config) File() string {
+	return c.file
+}
+
+// Dump writes the final configuration as a TOML file, that can be used as
+// a starting point for a configuration file. Each option is preceded by its
+// description and the source of its value.
+func (c *Config) Dump(w io.Writer) {
+	if c.file != "" {
+		fmt.Fprintf(w, "# configuration file: %s\n\n", c.file)
+	}
+	c.fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == FileFlag {
+			return
+		}
+		fmt.Fprintf(w, "# %s\n# source: %v (env: %s)\n", f.Usage, c.Source(f.Name), c.EnvName(f.Name))
+
+		val := strconv.Quote(f.Value.String())
+		if g, ok := f.Value.(flag.Getter); ok {
+			switch v := g.Get().(type) {
+			case bool, int, int64, uint, uint64, float64:
+				val = fmt.Sprint(v)
+			}
+		}
+		fmt.Fprintf(w, "%s = %s\n\n", f.Name, val)
+	})
+}
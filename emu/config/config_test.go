@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "test.toml")
+	data := "a = \"file\"\nb = \"file\"\nc = \"file\"\nn = 3\nlist = [\"x\", \"y\"]\n"
+	if err := ioutil.WriteFile(fn, []byte(data), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	a := fs.String("a", "default", "")
+	b := fs.String("b", "default", "")
+	c := fs.String("c", "default", "")
+	d := fs.String("d", "default", "")
+	n := fs.Int("n", 0, "")
+	list := fs.String("list", "", "")
+	fs.String(FileFlag, "", "")
+
+	os.Setenv("CFGTEST_B", "env")
+	os.Setenv("CFGTEST_C", "env")
+	defer os.Unsetenv("CFGTEST_B")
+	defer os.Unsetenv("CFGTEST_C")
+
+	cfg := New(fs, "CFGTEST_")
+	if err := cfg.Load([]string{"-config", fn, "-c", "flag", "rom.nds"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		val  string
+		src  Source
+	}{
+		{"a", *a, SourceFile},
+		{"b", *b, SourceEnv},
+		{"c", *c, SourceFlag},
+		{"d", *d, SourceDefault},
+	} {
+		if exp := tc.src.String(); tc.val != exp && !(tc.src == SourceDefault && tc.val == "default") {
+			t.Errorf("%s: got %q, want %q", tc.name, tc.val, exp)
+		}
+		if src := cfg.Source(tc.name); src != tc.src {
+			t.Errorf("%s: got source %v, want %v", tc.name, src, tc.src)
+		}
+	}
+	if *n != 3 || *list != "x,y" {
+		t.Errorf("invalid typed values: n=%d list=%q", *n, *list)
+	}
+	if fs.NArg() != 1 || fs.Arg(0) != "rom.nds" {
+		t.Errorf("invalid positional args: %v", fs.Args())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	newfs := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Int("n", 0, "")
+		fs.String("mode", "a", "")
+		return fs
+	}
+
+	os.Setenv("CFGTEST_N", "notanumber")
+	err := New(newfs(), "CFGTEST_").Load(nil)
+	os.Unsetenv("CFGTEST_N")
+	if err == nil {
+		t.Error("invalid env value not reported")
+	}
+
+	cfg := New(newfs(), "CFGTEST_")
+	cfg.Check("mode", func(v string) error {
+		if v != "a" && v != "b" {
+			return errors.New("invalid mode")
+		}
+		return nil
+	})
+	if err := cfg.Load([]string{"-mode", "c"}); err == nil {
+		t.Error("failed check not reported")
+	}
+}
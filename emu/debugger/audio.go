@@ -0,0 +1,214 @@
+package debugger
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Refresh interval of the audio view while the emulation is running
+const cAudioViewRefresh = 100 * time.Millisecond
+
+// Number of samples analyzed by the spectrum view (must be a power of two)
+const cSpectrumSize = 1024
+
+// audioScope keeps the audio output of the last emulated frame, to show it
+// as an oscilloscope and as a spectrum. Frames are added by the emulation
+// thread, and rendered by the UI.
+type audioScope struct {
+	mu      sync.Mutex
+	samples []int16 // interleaved stereo
+}
+
+// AddAudioFrame records the audio output (interleaved stereo samples) of a
+// frame, for the audio view.
+func (dbg *Debugger) AddAudioFrame(samples []int16) {
+	s := &dbg.scope
+	s.mu.Lock()
+	s.samples = append(s.samples[:0], samples...)
+	s.mu.Unlock()
+}
+
+// isAudioView reports whether the audio view is shown. The flag is toggled by
+// the UI event handlers, and polled by the refresh ticker of runMonitored,
+// so it is accessed atomically.
+func (dbg *Debugger) isAudioView() bool {
+	return atomic.LoadInt32(&dbg.audioView) != 0
+}
+
+func (dbg *Debugger) setAudioView(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&dbg.audioView, v)
+}
+
+// Render draws the audio view in a box of the specified size: a line with
+// peak levels and clipped samples, the waveform (left and right channels
+// mixed), and the spectrum (from 0 to the Nyquist frequency, on a log scale).
+func (s *audioScope) Render(width, height int) []string {
+	s.mu.Lock()
+	samples := make([]float64, len(s.samples)/2)
+	var peak [2]int
+	clipped := 0
+	for i := range samples {
+		l, r := int(s.samples[i*2]), int(s.samples[i*2+1])
+		samples[i] = float64(l+r) / 2 / 32768
+		for ch, v := range [2]int{l, r} {
+			if v == math.MaxInt16 || v == math.MinInt16 {
+				clipped++
+			}
+			if v < 0 {
+				v = -v
+			}
+			if v > peak[ch] {
+				peak[ch] = v
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	db := func(v int) string {
+		if v == 0 {
+			return "-inf"
+		}
+		return fmt.Sprintf("%.1f", 20*math.Log10(float64(v)/32768))
+	}
+	header := fmt.Sprintf("peak L %sdB R %sdB", db(peak[0]), db(peak[1]))
+	if clipped > 0 {
+		header += fmt.Sprintf("  [clipped %d](fg-red)", clipped)
+	}
+
+	if width <= 0 || height < 5 {
+		return []string{header}
+	}
+	rows := (height - 2) / 2
+	lines := []string{header}
+	lines = append(lines, renderWaveform(samples, width, rows)...)
+	lines = append(lines, strings.Repeat("-", width))
+	lines = append(lines, renderSpectrum(samples, width, height-2-rows)...)
+	return lines
+}
+
+// renderWaveform draws the range of values covered by the samples that fall
+// into each column
+func renderWaveform(samples []float64, width, rows int) []string {
+	grid := make([][]rune, rows)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(" ", width))
+	}
+	row := func(v float64) int {
+		y := int((1 - v) / 2 * float64(rows))
+		if y < 0 {
+			y = 0
+		} else if y >= rows {
+			y = rows - 1
+		}
+		return y
+	}
+	for x := 0; x < width && len(samples) > 0; x++ {
+		chunk := samples[x*len(samples)/width : (x+1)*len(samples)/width]
+		if len(chunk) == 0 {
+			continue
+		}
+		min, max := chunk[0], chunk[0]
+		for _, v := range chunk {
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+		for y := row(max); y <= row(min); y++ {
+			grid[y][x] = '█'
+		}
+	}
+
+	lines := make([]string, rows)
+	for y := range grid {
+		lines[y] = string(grid[y])
+	}
+	return lines
+}
+
+var barChars = []rune(" ▁▂▃▄▅▆▇█")
+
+// renderSpectrum draws the magnitude (in dB, from -96 to 0) of the spectrum
+// of the last samples, as vertical bars. Columns are spaced logarithmically
+// in frequency.
+func renderSpectrum(samples []float64, width, rows int) []string {
+	if len(samples) > cSpectrumSize {
+		samples = samples[len(samples)-cSpectrumSize:]
+	}
+	buf := make([]complex128, cSpectrumSize)
+	if len(samples) >= 2 {
+		for i, v := range samples {
+			// Hann window, to reduce leakage
+			w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(len(samples)-1))
+			buf[i] = complex(v*w, 0)
+		}
+	}
+	fft(buf)
+
+	nbins := cSpectrumSize / 2
+	heights := make([]int, width)
+	for x := range heights {
+		b0 := int(math.Pow(float64(nbins), float64(x)/float64(width)))
+		b1 := int(math.Pow(float64(nbins), float64(x+1)/float64(width)))
+		if b1 <= b0 {
+			b1 = b0 + 1
+		}
+		var mag float64
+		for b := b0; b < b1 && b < nbins; b++ {
+			mag = math.Max(mag, cmplx.Abs(buf[b])/float64(nbins/2))
+		}
+		level := (20*math.Log10(mag+1e-10) + 96) / 96
+		heights[x] = int(math.Max(0, math.Min(1, level)) * float64(rows*8))
+	}
+
+	lines := make([]string, rows)
+	for y := range lines {
+		line := make([]rune, width)
+		base := (rows - 1 - y) * 8
+		for x, h := range heights {
+			switch {
+			case h >= base+8:
+				line[x] = barChars[8]
+			case h <= base:
+				line[x] = barChars[0]
+			default:
+				line[x] = barChars[h-base]
+			}
+		}
+		lines[y] = string(line)
+	}
+	return lines
+}
+
+// fft computes the discrete Fourier transform of buf in place (radix-2,
+// the length must be a power of two)
+func fft(buf []complex128) {
+	n := len(buf)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			buf[i], buf[j] = buf[j], buf[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			wk := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := buf[start+k], buf[start+k+size/2]*wk
+				buf[start+k], buf[start+k+size/2] = a+b, a-b
+				wk *= w
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package debugger
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const (
+	cBacktraceMaxFrames   = 32
+	cBacktraceMaxPrologue = 4096 // max distance (in bytes) of the prologue from the PC
+	cBacktraceMaxScan     = 1024 // max number of bytes of stack scanned for a return address
+)
+
+// How a frame of the backtrace was found. Frames found with less reliable
+// heuristics are more likely to be bogus.
+type FrameSource int
+
+const (
+	FramePC       FrameSource = iota // current PC
+	FrameLR                          // link register (leaf function)
+	FramePrologue                    // return address saved by the function prologue
+	FrameScan                        // stack scanning
+)
+
+var frameSourceNames = [...]string{"pc", "lr", "prologue", "scan"}
+
+func (s FrameSource) String() string { return frameSourceNames[s] }
+
+type Frame struct {
+	PC     uint32 // address of the next instruction to execute in this frame
+	SP     uint32 // stack pointer within this frame (best-effort)
+	Thumb  bool
+	Source FrameSource
+}
+
+type btReader struct {
+	cpu Cpu
+}
+
+func (r btReader) read16(addr uint32) uint16 {
+	return uint16(r.cpu.Peek8(addr)) | uint16(r.cpu.Peek8(addr+1))<<8
+}
+
+func (r btReader) read32(addr uint32) uint32 {
+	return uint32(r.read16(addr)) | uint32(r.read16(addr+2))<<16
+}
+
+// isCallSite checks whether the instruction preceding the specified return
+// address is a call (BL, BLX, or the ARMv4 "mov lr,pc" idiom). Bit 0 of the
+// return address selects the Thumb mode, as in the LR register.
+func (r btReader) isCallSite(ret uint32) bool {
+	if ret&1 != 0 {
+		ret &^= 1
+		if ret < 4 {
+			return false
+		}
+		// BL / BLX (immediate) pair
+		hi, lo := r.read16(ret-4), r.read16(ret-2)
+		if hi&0xF800 == 0xF000 && lo&0xE800 == 0xE800 {
+			return true
+		}
+		// BLX Rm
+		return lo&0xFF87 == 0x4780
+	}
+
+	if ret&3 != 0 || ret < 8 {
+		return false
+	}
+	op := r.read32(ret - 4)
+	switch {
+	case op&0x0F000000 == 0x0B000000 && op>>28 != 0xF: // BL
+		return true
+	case op&0xFE000000 == 0xFA000000: // BLX (immediate)
+		return true
+	case op&0x0FFFFFF0 == 0x012FFF30: // BLX Rm
+		return true
+	}
+	// "mov lr, pc" followed by a jump ("bx rN", "ldr pc, [...]", "mov pc, rN")
+	return r.read32(ret-8) == 0xE1A0E00F
+}
+
+// findPrologue looks backward from pc for the prologue of the function
+// containing it, that is an instruction pushing LR on the stack
+// (ARM: "stmdb sp!, {..., lr}"; Thumb: "push {..., lr}"). Scanning stops
+// at the function start (if known from symbols), or at what looks like the
+// end of the previous function. It returns the address of the push and the
+// number of pushed registers.
+func (r btReader) findPrologue(pc uint32, thumb bool, start uint32) (uint32, int, bool) {
+	if thumb {
+		for addr := pc - 2; addr+2 > start && pc-addr <= cBacktraceMaxPrologue; addr -= 2 {
+			op := r.read16(addr)
+			switch {
+			case op&0xFF00 == 0xB500: // push {rlist, lr}
+				return addr, bits.OnesCount8(uint8(op)) + 1, true
+			case op&0xFF00 == 0xBD00, op == 0x4770: // pop {rlist, pc}; bx lr
+				return 0, 0, false
+			}
+		}
+		return 0, 0, false
+	}
+
+	for addr := pc - 4; addr+4 > start && pc-addr <= cBacktraceMaxPrologue; addr -= 4 {
+		op := r.read32(addr)
+		switch {
+		case op&0xFFFF4000 == 0xE92D4000: // stmdb sp!, {rlist, lr}
+			return addr, bits.OnesCount16(uint16(op)), true
+		case op&0xFFFF8000 == 0xE8BD8000, op == 0xE12FFF1E: // ldmia sp!, {rlist, pc}; bx lr
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+// stackAdjust computes how many bytes were allocated on the stack by the
+// instructions following the prologue, up to (but excluding) pc
+func (r btReader) stackAdjust(begin, pc uint32, thumb bool) uint32 {
+	var adj uint32
+	if thumb {
+		for addr := begin; addr < pc; addr += 2 {
+			if op := r.read16(addr); op&0xFF80 == 0xB080 { // sub sp, #imm
+				adj += uint32(op&0x7F) * 4
+			}
+		}
+		return adj
+	}
+	for addr := begin; addr < pc; addr += 4 {
+		if op := r.read32(addr); op&0xFFFFF000 == 0xE24DD000 { // sub sp, sp, #imm
+			rot := (op >> 8 & 0xF) * 2
+			adj += bits.RotateLeft32(op&0xFF, -int(rot))
+		}
+	}
+	return adj
+}
+
+// scanStack looks for the first word in the stack that looks like a return
+// address, and returns it together with the stack pointer of the caller.
+func (r btReader) scanStack(sp uint32) (uint32, uint32, bool) {
+	for addr := sp &^ 3; addr-sp < cBacktraceMaxScan; addr += 4 {
+		if ret := r.read32(addr); ret != 0 && r.isCallSite(ret) {
+			return ret, addr + 4, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Backtrace reconstructs the call stack of the specified CPU. The ARM ABI
+// doesn't mandate frame pointers, so this is a best-effort process based on
+// heuristics:
+//
+//   - the prologue of each function is located, to find out where it saved
+//     the return address, and how much the stack was moved;
+//   - functions without a prologue are assumed to be leaf functions that
+//     still have the return address in LR (only for the innermost frame);
+//   - as a last resort, the stack is scanned for values that look like
+//     return addresses (that is, they point after a call instruction).
+//
+// Symbols (if loaded) are used to bound the search for prologues.
+func (dbg *Debugger) Backtrace(cpuidx int) []Frame {
+	cpu := dbg.cpus[cpuidx]
+	r := btReader{cpu}
+	syms := dbg.syms[cpuidx]
+
+	regs := cpu.GetRegs()
+	pc, sp, lr := cpu.GetPc(), regs[13], regs[14]
+	_, buf := cpu.Disasm(pc)
+	thumb := len(buf) == 2
+
+	frames := []Frame{{PC: pc, SP: sp, Thumb: thumb, Source: FramePC}}
+	for len(frames) < cBacktraceMaxFrames {
+		var ret, callersp uint32
+		var src FrameSource
+		found := false
+
+		var start uint32
+		if sym, ok := syms.Lookup(pc); ok {
+			start = sym.Addr
+		}
+		if push, nregs, ok := r.findPrologue(pc, thumb, start); ok {
+			isz := uint32(4)
+			if thumb {
+				isz = 2
+			}
+			adj := r.stackAdjust(push+isz, pc, thumb)
+			ret = r.read32(sp + adj + uint32(nregs-1)*4)
+			callersp = sp + adj + uint32(nregs)*4
+			src, found = FramePrologue, r.isCallSite(ret)
+		} else if len(frames) == 1 && r.isCallSite(lr) {
+			ret, callersp = lr, sp
+			src, found = FrameLR, true
+		}
+		if !found {
+			ret, callersp, found = r.scanStack(sp)
+			src = FrameScan
+		}
+		if !found || callersp < sp {
+			break
+		}
+
+		pc, sp, thumb = ret&^1, callersp, ret&1 != 0
+		frames = append(frames, Frame{PC: pc, SP: sp, Thumb: thumb, Source: src})
+	}
+	return frames
+}
+
+// FormatBacktrace formats a backtrace of the specified CPU as text, one line
+// per frame, annotated with symbols (if loaded).
+func (dbg *Debugger) FormatBacktrace(cpuidx int, frames []Frame) []string {
+	lines := make([]string, 0, len(frames))
+	for i, f := range frames {
+		mode := "arm"
+		if f.Thumb {
+			mode = "thm"
+		}
+		lines = append(lines, fmt.Sprintf("#%-2d %08x %s sp=%08x %-8s %s",
+			i, f.PC, mode, f.SP, f.Source, dbg.syms[cpuidx].Format(f.PC)))
+	}
+	return lines
+}
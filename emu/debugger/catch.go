@@ -0,0 +1,110 @@
+package debugger
+
+import (
+	"fmt"
+	log "ndsemu/emu/logger"
+	"strings"
+)
+
+// Exception types reported by CPU cores through CpuDebugger.Exception. The
+// numbering follows the ARM exception vectors.
+type ExcKind int
+
+const (
+	ExcReset ExcKind = iota
+	ExcUndefined
+	ExcSwi
+	ExcPrefetchAbort
+	ExcDataAbort
+	ExcAddressOverflow
+	ExcIrq
+	ExcFiq
+	excNumKinds
+)
+
+var excKindNames = [...]string{
+	"reset", "undefined", "swi", "prefetch-abort",
+	"data-abort", "address-overflow", "irq", "fiq",
+}
+
+func (k ExcKind) String() string { return excKindNames[k] }
+
+// ParseExcKind parses the name of an exception type (as returned by String)
+func ParseExcKind(s string) (ExcKind, error) {
+	for k := ExcKind(0); k < excNumKinds; k++ {
+		if strings.EqualFold(s, k.String()) {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid exception type: %q", s)
+}
+
+// Catchpoint stops execution (or just logs) when a CPU enters an exception
+// matching the filter.
+type Catchpoint struct {
+	Exc     ExcKind
+	Swi     int    // SWI number (-1: any); only for ExcSwi
+	Irq     uint32 // mask of IRQ sources (0: any); only for ExcIrq
+	LogOnly bool   // log the exception without breaking
+}
+
+func (c *Catchpoint) match(exc ExcKind, swi uint8, irqs uint32) bool {
+	switch {
+	case c.Exc != exc:
+		return false
+	case exc == ExcSwi && c.Swi >= 0 && c.Swi != int(swi):
+		return false
+	case exc == ExcIrq && c.Irq != 0 && c.Irq&irqs == 0:
+		return false
+	}
+	return true
+}
+
+func (dbg *Debugger) AddCatchpoint(c Catchpoint) {
+	dbg.catches = append(dbg.catches, c)
+}
+
+// SetIrqPending installs a function that returns the mask of the IRQ sources
+// that are pending for the specified CPU. The CPU core can't know it (it just
+// sees the IRQ line), but it's required to filter IRQs by source.
+func (dbg *Debugger) SetIrqPending(cpuidx int, pending func() uint32) {
+	dbg.irqPending[cpuidx] = pending
+}
+
+func (dbg dbgForCpu) Exception(exc ExcKind, swi uint8) {
+	if len(dbg.catches) == 0 || dbg.rev.active() {
+		return
+	}
+
+	var irqs uint32
+	if exc == ExcIrq && dbg.irqPending[dbg.cpuidx] != nil {
+		irqs = dbg.irqPending[dbg.cpuidx]()
+	}
+
+	for i := range dbg.catches {
+		c := &dbg.catches[i]
+		if !c.match(exc, swi, irqs) {
+			continue
+		}
+
+		msg := fmt.Sprintf("exception: %v", exc)
+		switch exc {
+		case ExcSwi:
+			msg = fmt.Sprintf("swi %02x", swi)
+		case ExcIrq:
+			msg = fmt.Sprintf("irq (pending=%08x)", irqs)
+		}
+
+		if c.LogOnly {
+			log.ModEmu.InfoZ("catchpoint").
+				Int("cpu", dbg.cpuidx).
+				String("exc", msg).
+				Hex32("pc", dbg.cpus[dbg.cpuidx].GetPc()).
+				End()
+			continue
+		}
+		dbg.curcpu = dbg.cpuidx
+		dbg.Break(msg)
+		return
+	}
+}
@@ -0,0 +1,198 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	log "ndsemu/emu/logger"
+	"strconv"
+	"strings"
+	"sync"
+
+	ui "github.com/gizak/termui"
+)
+
+// prompt is the command line of the debugger. It is opened with ':' while
+// the CPU is stopped, and the command is executed with ENTER (ESC cancels);
+// results and errors are logged. Supported commands:
+//
+//	asm ADDR "INSN"    assemble an instruction at ADDR, in the current mode
+//	                   (ARM or Thumb) of the CPU; eg: asm 0x02000100 "mov r0, #1"
+//	mem ADDR [SIZE]    dump SIZE bytes (default: 64) of memory at ADDR, without
+//	                   side effects; bytes of read-sensitive I/O registers are
+//	                   marked with "S", as the value shown is the last stored
+//	                   one (see SensitiveReader)
+//	back [N]           run the current CPU backwards by N instructions
+//	                   (default: 1; same as the "p" key), see Rewinder
+//	rcont              run backwards up to the last breakpoint or watchpoint
+//	                   hit, see Rewinder
+//
+// The prompt is edited by an event hook rather than by handlers, because
+// termui runs handlers concurrently and keystrokes could be reordered.
+type prompt struct {
+	mu   sync.Mutex
+	open bool
+	text string
+	ui   *ui.Par
+}
+
+func (p *prompt) isOpen() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.open
+}
+
+func (p *prompt) render() {
+	p.ui.Text = ":" + p.text
+	p.ui.Width = ui.TermWidth()
+	p.ui.Height = 3
+	p.ui.SetY(ui.TermHeight() - 3)
+	ui.Render(p.ui)
+}
+
+// promptHook opens the prompt, and edits it while it's open
+func (dbg *Debugger) promptHook(e ui.Event) {
+	kbd, ok := e.Data.(ui.EvtKbd)
+	if !ok {
+		return
+	}
+	p := &dbg.prompt
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		if kbd.KeyStr == ":" && !dbg.running[dbg.curcpu] {
+			p.open, p.text = true, ""
+			p.render()
+		}
+		return
+	}
+
+	switch key := kbd.KeyStr; {
+	case key == "<escape>":
+		p.open = false
+		ui.Clear()
+		dbg.refreshUi()
+		return
+	case key == "<backspace>" || key == "C-8":
+		if p.text != "" {
+			p.text = p.text[:len(p.text)-1]
+		}
+	case key == "<space>":
+		p.text += " "
+	case len(key) == 1:
+		p.text += key
+	}
+	p.render()
+}
+
+// dumpMem logs a memory dump of the current CPU, 16 bytes per line
+func (dbg *Debugger) dumpMem(addr uint32, size int) {
+	cpu := dbg.cpus[dbg.curcpu]
+	sr, _ := cpu.(SensitiveReader)
+	for off := 0; off < size; off += 16 {
+		row := make([]byte, 16)
+		if size-off < len(row) {
+			row = row[:size-off]
+		}
+		marks := make([]byte, len(row))
+		sensitive := false
+		for i := range row {
+			a := addr + uint32(off+i)
+			row[i] = cpu.Peek8(a)
+			marks[i] = '.'
+			if sr != nil && sr.ReadSensitive(a) {
+				marks[i] = 'S'
+				sensitive = true
+			}
+		}
+		z := log.ModEmu.InfoZ("mem").Hex32("addr", addr+uint32(off)).Blob("data", row)
+		if sensitive {
+			z = z.String("sensitive", string(marks))
+		}
+		z.End()
+	}
+}
+
+// execPrompt executes the command in the prompt, and closes it
+func (dbg *Debugger) execPrompt() {
+	p := &dbg.prompt
+	p.mu.Lock()
+	cmd := p.text
+	p.open = false
+	p.mu.Unlock()
+
+	if err := dbg.Exec(cmd); err != nil {
+		log.ModEmu.ErrorZ("debugger command failed").String("cmd", cmd).Error("err", err).End()
+	}
+
+	// Force refresh of disasm screen, in case code was patched
+	dbg.linepc = nil
+	dbg.lines = nil
+	ui.Clear()
+	dbg.refreshUi()
+}
+
+// Exec executes a debugger command on the current CPU (see prompt)
+func (dbg *Debugger) Exec(cmd string) error {
+	cmd = strings.TrimSpace(cmd)
+	name, args := cmd, ""
+	if idx := strings.IndexByte(cmd, ' '); idx >= 0 {
+		name, args = cmd[:idx], strings.TrimSpace(cmd[idx+1:])
+	}
+
+	switch name {
+	case "":
+		return nil
+	case "asm":
+		idx := strings.IndexByte(args, ' ')
+		if idx < 0 {
+			return errors.New("usage: asm ADDR \"INSN\"")
+		}
+		addr, err := strconv.ParseUint(args[:idx], 0, 32)
+		if err != nil {
+			return fmt.Errorf("invalid address: %q", args[:idx])
+		}
+		insn := strings.Trim(strings.TrimSpace(args[idx+1:]), "\"")
+
+		as, ok := dbg.cpus[dbg.curcpu].(Assembler)
+		if !ok {
+			return errors.New("assembler not supported by this CPU")
+		}
+		buf, err := as.Assemble(uint32(addr), insn)
+		if err != nil {
+			return err
+		}
+		log.ModEmu.InfoZ("code patched").Hex32("addr", uint32(addr)).Blob("code", buf).String("insn", insn).End()
+		return nil
+	case "mem":
+		fields := strings.Fields(args)
+		if len(fields) < 1 || len(fields) > 2 {
+			return errors.New("usage: mem ADDR [SIZE]")
+		}
+		addr, err := strconv.ParseUint(fields[0], 0, 32)
+		if err != nil {
+			return fmt.Errorf("invalid address: %q", fields[0])
+		}
+		size := uint64(64)
+		if len(fields) == 2 {
+			if size, err = strconv.ParseUint(fields[1], 0, 16); err != nil || size == 0 || size > 1024 {
+				return fmt.Errorf("invalid size: %q", fields[1])
+			}
+		}
+		dbg.dumpMem(uint32(addr), int(size))
+		return nil
+	case "back":
+		count := uint64(1)
+		if args != "" {
+			var err error
+			if count, err = strconv.ParseUint(args, 0, 64); err != nil || count == 0 {
+				return fmt.Errorf("invalid count: %q", args)
+			}
+		}
+		return dbg.reverseStep(count)
+	case "rcont":
+		return dbg.reverseContinue()
+	default:
+		return fmt.Errorf("unknown command: %q", name)
+	}
+}
@@ -0,0 +1,169 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type CovFlags uint8
+
+const (
+	CovRead CovFlags = 1 << iota
+	CovWrite
+	CovExecArm
+	CovExecThumb
+
+	CovExec = CovExecArm | CovExecThumb
+)
+
+// Describe the kind of contents of a memory area, given its coverage flags.
+// Code takes precedence over data.
+func (f CovFlags) String() string {
+	switch {
+	case f&CovExecThumb != 0:
+		return "code-thumb"
+	case f&CovExecArm != 0:
+		return "code-arm"
+	case f&(CovRead|CovWrite) == CovRead|CovWrite:
+		return "data-rw"
+	case f&CovRead != 0:
+		return "data-r"
+	case f&CovWrite != 0:
+		return "data-w"
+	default:
+		return "none"
+	}
+}
+
+// Coverage is tracked with a granularity of 32-bit words, which is enough to
+// separate code from data, and avoids depending on the size of each access.
+const covWordsPerRegion = 1 << (20 - 2)
+
+// Coverage tracks which memory addresses have ever been executed, read, or
+// written during a session, for reverse engineering purposes. As with
+// Heatmap, memory is allocated per 1 MiB region on first access.
+type Coverage struct {
+	regions [1 << 12]*[covWordsPerRegion]CovFlags
+}
+
+// Mark adds the specified flags to the word containing addr, and returns
+// the flags that were previously set.
+func (c *Coverage) Mark(addr uint32, flags CovFlags) CovFlags {
+	r := c.regions[addr>>20]
+	if r == nil {
+		r = new([covWordsPerRegion]CovFlags)
+		c.regions[addr>>20] = r
+	}
+	w := &r[(addr>>2)&(covWordsPerRegion-1)]
+	old := *w
+	*w |= flags
+	return old
+}
+
+func (c *Coverage) Get(addr uint32) CovFlags {
+	if r := c.regions[addr>>20]; r != nil {
+		return r[(addr>>2)&(covWordsPerRegion-1)]
+	}
+	return 0
+}
+
+// WriteRanges exports the coverage map as a list of address ranges with
+// homogeneous contents, one per line:
+//
+//	02000000 020001ff code-arm
+//	02000200 0200023f data-r
+//
+// Addresses are inclusive. Lines starting with "#" are comments. The format
+// is meant to be trivially importable by disassembler scripts (eg: to create
+// code/data regions and set the Thumb mode in Ghidra or IDA).
+func (c *Coverage) WriteRanges(w io.Writer, cpu string) error {
+	fmt.Fprintf(w, "# ndsemu coverage for %s\n", cpu)
+
+	var start uint32
+	var cur string
+	flush := func(end uint32) error {
+		if cur == "" {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, "%08x %08x %s\n", start, end-1, cur)
+		cur = ""
+		return err
+	}
+
+	for idx, r := range c.regions {
+		base := uint32(idx) << 20
+		if r == nil {
+			if err := flush(base); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, f := range r {
+			addr := base + uint32(i)<<2
+			kind := ""
+			if f != 0 {
+				kind = f.String()
+			}
+			if kind == cur {
+				continue
+			}
+			if err := flush(addr); err != nil {
+				return err
+			}
+			start, cur = addr, kind
+		}
+	}
+	// The address space ends exactly at 2^32, so the last range can't be
+	// closed through the normal path
+	return flush(0)
+}
+
+// EnableCoverage starts tracking code/data coverage for all CPUs. The coverage
+// maps are saved (press "C") to one file per CPU, named after the specified
+// file with the CPU name appended (eg: "cov.txt" => "cov-arm9.txt").
+func (dbg *Debugger) EnableCoverage(fn string, names []string) {
+	dbg.cov = make([]Coverage, len(dbg.cpus))
+	dbg.covFile = fn
+	dbg.covNames = names
+}
+
+func (dbg dbgForCpu) coverExec(pc uint32) {
+	cov := &dbg.cov[dbg.cpuidx]
+	if cov.Get(pc)&CovExec != 0 {
+		return
+	}
+	// First time this word is executed: find out the instruction size (and
+	// thus the CPU mode) by disassembling it. This is slow, but happens only
+	// once per word.
+	flags := CovExecArm
+	if _, buf := dbg.cpus[dbg.cpuidx].Disasm(pc); len(buf) == 2 {
+		flags = CovExecThumb
+	}
+	cov.Mark(pc, flags)
+}
+
+// SaveCoverage saves the coverage maps of all CPUs
+func (dbg *Debugger) SaveCoverage() error {
+	ext := filepath.Ext(dbg.covFile)
+	base := strings.TrimSuffix(dbg.covFile, ext)
+	for i := range dbg.cov {
+		f, err := os.Create(base + "-" + dbg.covNames[i] + ext)
+		if err != nil {
+			return err
+		}
+		w := bufio.NewWriter(f)
+		err = dbg.cov[i].WriteRanges(w, dbg.covNames[i])
+		if err == nil {
+			err = w.Flush()
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package debugger
 
 import (
 	"fmt"
+	"ndsemu/arm/disasm"
 	"ndsemu/emu"
+	log "ndsemu/emu/logger"
+	"time"
 
 	ui "github.com/gizak/termui"
 )
@@ -25,6 +28,11 @@ type CpuDebugger interface {
 	// Break() can be called by the CPU core to force breaking into the debugger.
 	// It can be used in situations such as invalid opcodes
 	Break(msg string)
+
+	// Exception() must be called when the CPU is about to enter an exception,
+	// before any state is changed. For SWIs, the SWI number is also provided
+	// (even if the SWI is emulated at high-level).
+	Exception(exc ExcKind, swi uint8)
 }
 
 type Cpu interface {
@@ -41,6 +49,31 @@ type Cpu interface {
 
 	GetPc() uint32
 	Disasm(pc uint32) (string, []byte)
+
+	// Peek8 reads memory without side effects on emulation
+	Peek8(addr uint32) uint8
+}
+
+// InsnDecoder is optionally implemented by a Cpu that can decode
+// instructions into structured form; the debugger uses it to annotate the
+// destinations of branches with symbols.
+type InsnDecoder interface {
+	DisasmInsn(pc uint32) (disasm.Insn, bool)
+}
+
+// SensitiveReader is optionally implemented by a Cpu that knows which
+// addresses have side effects when read (eg: FIFOs); the "mem" command marks
+// them, as Peek8 returns their last stored value rather than what the CPU
+// would read.
+type SensitiveReader interface {
+	ReadSensitive(addr uint32) bool
+}
+
+// Assembler is optionally implemented by a Cpu that can assemble
+// instructions into its memory; the debugger uses it for live patching
+// (see the "asm" command).
+type Assembler interface {
+	Assemble(addr uint32, text string) ([]byte, error)
 }
 
 type Debugger struct {
@@ -66,6 +99,31 @@ type Debugger struct {
 	breakch chan string
 
 	log *logReader
+
+	heat      []Heatmap // per-CPU access heatmaps (nil if disabled)
+	heatFile  string
+	heatNames []string
+	heatView  HeatKind // kind of heatmap shown instead of the log (-1: none)
+
+	cov      []Coverage // per-CPU coverage maps (nil if disabled)
+	covFile  string
+	covNames []string
+
+	syms   []*Symbols // per-CPU symbols (nil if not loaded)
+	btView bool       // show backtrace instead of the log
+
+	scope     audioScope
+	audioView int32 // show the audio output instead of the log (atomic, see isAudioView)
+
+	catches    []Catchpoint
+	irqPending []func() uint32
+
+	rw        Rewinder // history for reverse execution (nil if disabled)
+	steps     []uint64 // instructions executed by each CPU
+	lastTrace int      // CPU that called Trace last
+	rev       revState
+
+	prompt prompt
 }
 
 type dbgForCpu struct {
@@ -82,7 +140,11 @@ func New(cpus []Cpu, sync *emu.Sync) *Debugger {
 		running: make([]bool, len(cpus)),
 		pcchain: make([][]uint32, len(cpus)),
 		breakch: make(chan string),
+		steps:   make([]uint64, len(cpus)),
 	}
+	dbg.heatView = -1
+	dbg.syms = make([]*Symbols, len(cpus))
+	dbg.irqPending = make([]func() uint32, len(cpus))
 
 	for idx, cpu := range cpus {
 		dbg.pcchain[idx] = make([]uint32, 1)
@@ -92,22 +154,60 @@ func New(cpus []Cpu, sync *emu.Sync) *Debugger {
 	return dbg
 }
 
+// SetCpus replaces the CPUs being debugged, for instance because they were
+// recreated on reset. The number of CPUs can't change.
+func (dbg *Debugger) SetCpus(cpus []Cpu) {
+	for idx, cpu := range cpus {
+		dbg.cpus[idx] = cpu
+		cpu.SetDebugger(dbgForCpu{dbg, idx})
+	}
+}
+
 func (dbg dbgForCpu) WatchRead(addr uint32) {
-	for _, wa := range dbg.watches {
-		if wa == addr {
-			dbg.curcpu = dbg.cpuidx
-			dbg.Break("watchpoint")
+	if dbg.rev.active() {
+		if dbg.isWatched(addr) {
+			dbg.watchReverse()
 		}
+		return
+	}
+	if dbg.heat != nil {
+		dbg.heat[dbg.cpuidx].Add(HeatRead, addr)
+	}
+	if dbg.cov != nil {
+		dbg.cov[dbg.cpuidx].Mark(addr, CovRead)
+	}
+	if dbg.isWatched(addr) {
+		dbg.curcpu = dbg.cpuidx
+		dbg.Break("watchpoint")
 	}
 }
 
 func (dbg dbgForCpu) WatchWrite(addr uint32, val uint32) {
+	if dbg.rev.active() {
+		if dbg.isWatched(addr) {
+			dbg.watchReverse()
+		}
+		return
+	}
+	if dbg.heat != nil {
+		dbg.heat[dbg.cpuidx].Add(HeatWrite, addr)
+	}
+	if dbg.cov != nil {
+		dbg.cov[dbg.cpuidx].Mark(addr, CovWrite)
+	}
+	if dbg.isWatched(addr) {
+		dbg.curcpu = dbg.cpuidx
+		dbg.Break("watchpoint")
+	}
+}
+
+func (dbg *Debugger) isWatched(addr uint32) bool {
 	for _, wa := range dbg.watches {
 		if wa == addr {
-			dbg.curcpu = dbg.cpuidx
-			dbg.Break("watchpoint")
+			return true
 		}
 	}
+	return false
 }
 
 func (dbg dbgForCpu) updateChain(pc uint32) {
@@ -140,7 +240,18 @@ func (dbg dbgForCpu) updateChain(pc uint32) {
 
 func (dbg dbgForCpu) Trace(pc uint32) {
 	idx := dbg.cpuidx
+	dbg.steps[idx]++
+	dbg.lastTrace = idx
+	if dbg.rev.active() && dbg.traceReverse(pc) {
+		return
+	}
 	dbg.updateChain(pc)
+	if dbg.heat != nil {
+		dbg.heat[idx].Add(HeatExec, pc)
+	}
+	if dbg.cov != nil {
+		dbg.coverExec(pc)
+	}
 
 	if msg, found := dbg.checkBreapoint(idx, pc); found {
 		dbg.curcpu = dbg.cpuidx
@@ -158,10 +269,8 @@ func (dbg *Debugger) checkBreapoint(cpuidx int, pc uint32) (string, bool) {
 		return "", true
 	}
 
-	for _, b := range dbg.userBkps {
-		if b == pc {
-			return fmt.Sprintf("user breakpoint at %08x", pc), true
-		}
+	if msg, found := dbg.checkUserBreakpoint(pc); found {
+		return msg, true
 	}
 	for idx, b := range dbg.ourBkps {
 		if b == pc {
@@ -172,12 +281,30 @@ func (dbg *Debugger) checkBreapoint(cpuidx int, pc uint32) (string, bool) {
 	return "", false
 }
 
+func (dbg *Debugger) checkUserBreakpoint(pc uint32) (string, bool) {
+	for _, b := range dbg.userBkps {
+		if b == pc {
+			return fmt.Sprintf("user breakpoint at %08x", pc), true
+		}
+	}
+	return "", false
+}
+
 func (dbg *Debugger) runMonitored() string {
+	// The audio view is refreshed periodically while running
+	ticker := time.NewTicker(cAudioViewRefresh)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-dbg.log.NewLog:
 			dbg.refreshLog()
 			ui.Render(dbg.uiLog)
+		case <-ticker.C:
+			if dbg.isAudioView() {
+				dbg.refreshLog()
+				ui.Render(dbg.uiLog)
+			}
 		case msg := <-dbg.breakch:
 			return msg
 		}
@@ -259,7 +386,17 @@ func (dbg *Debugger) Run() {
 		dbg.refreshUi()
 	}
 
-	ui.Handle("/sys/kbd/<space>", func(ui.Event) {
+	// Keys are ignored while a command is being typed (see promptHook)
+	handle := func(key string, f func(ui.Event)) {
+		ui.Handle("/sys/kbd/"+key, func(e ui.Event) {
+			if !dbg.prompt.isOpen() {
+				f(e)
+			}
+		})
+	}
+	ui.DefaultEvtStream.Hook(dbg.promptHook)
+
+	handle("<space>", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			run()
 		} else {
@@ -268,26 +405,30 @@ func (dbg *Debugger) Run() {
 	})
 
 	ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
+		if dbg.prompt.isOpen() {
+			dbg.execPrompt()
+			return
+		}
 		if !dbg.running[dbg.curcpu] && dbg.focusline >= 0 {
 			pc := dbg.linepc[dbg.focusline]
 			runto(pc)
 		}
 	})
 
-	ui.Handle("/sys/kbd/<up>", func(ui.Event) {
+	handle("<up>", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			dbg.focusline--
 			dbg.refreshUi()
 		}
 	})
-	ui.Handle("/sys/kbd/<down>", func(ui.Event) {
+	handle("<down>", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			dbg.focusline++
 			dbg.refreshUi()
 		}
 	})
 
-	ui.Handle("/sys/kbd/r", func(ui.Event) {
+	handle("r", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			// force refresh of disasm screen
 			dbg.linepc = nil
@@ -296,7 +437,7 @@ func (dbg *Debugger) Run() {
 		}
 	})
 
-	ui.Handle("/sys/kbd/s", func(ui.Event) {
+	handle("s", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			dbg.resumeEmulation(false, func() {
 				dbg.refreshUi()
@@ -304,7 +445,7 @@ func (dbg *Debugger) Run() {
 		}
 	})
 
-	ui.Handle("/sys/kbd/n", func(ui.Event) {
+	handle("n", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			pc := dbg.cpus[dbg.curcpu].GetPc()
 			if pc != dbg.linepc[dbg.pcline] {
@@ -322,24 +463,72 @@ func (dbg *Debugger) Run() {
 		}
 	})
 
-	ui.Handle("/sys/kbd/1", func(ui.Event) {
+	handle("p", func(ui.Event) {
+		if !dbg.running[dbg.curcpu] {
+			if err := dbg.reverseStep(1); err != nil {
+				log.ModEmu.ErrorZ("cannot step back").Error("err", err).End()
+			}
+		}
+	})
+
+	handle("1", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			switchcpu(0)
 		}
 	})
 
-	ui.Handle("/sys/kbd/2", func(ui.Event) {
+	handle("2", func(ui.Event) {
 		if !dbg.running[dbg.curcpu] {
 			switchcpu(1)
 		}
 	})
 
-	ui.Handle("/sys/kbd/q", func(ui.Event) {
+	handle("h", func(ui.Event) {
+		if dbg.heat != nil && !dbg.running[dbg.curcpu] {
+			if dbg.heatView++; dbg.heatView == heatNumKinds {
+				dbg.heatView = -1
+			}
+			dbg.btView = false
+			dbg.setAudioView(false)
+			dbg.refreshUi()
+		}
+	})
+
+	handle("b", func(ui.Event) {
+		if !dbg.running[dbg.curcpu] {
+			dbg.btView = !dbg.btView
+			dbg.heatView = -1
+			dbg.setAudioView(false)
+			dbg.refreshUi()
+		}
+	})
+
+	handle("a", func(ui.Event) {
+		dbg.setAudioView(!dbg.isAudioView())
+		dbg.btView = false
+		dbg.heatView = -1
+		dbg.refreshLog()
+		ui.Render(dbg.uiLog)
+	})
+
+	handle("H", func(ui.Event) {
+		dbg.saveHeatmap()
+	})
+
+	handle("C", func(ui.Event) {
+		dbg.saveCoverage()
+	})
+
+	handle("q", func(ui.Event) {
 		dbg.stopMonitored()
+		dbg.saveHeatmap()
+		dbg.saveCoverage()
 		ui.StopLoop()
 	})
 	ui.Handle("/sys/kbd/C-c", func(ui.Event) {
 		dbg.stopMonitored()
+		dbg.saveHeatmap()
+		dbg.saveCoverage()
 		ui.StopLoop()
 	})
 
@@ -348,6 +537,28 @@ func (dbg *Debugger) Run() {
 	ui.Loop()
 }
 
+func (dbg *Debugger) saveHeatmap() {
+	if dbg.heat == nil {
+		return
+	}
+	if err := dbg.SaveHeatmap(); err != nil {
+		log.ModEmu.ErrorZ("cannot save heatmap").Error("err", err).End()
+	} else {
+		log.ModEmu.InfoZ("heatmap saved").String("file", dbg.heatFile).End()
+	}
+}
+
+func (dbg *Debugger) saveCoverage() {
+	if dbg.cov == nil {
+		return
+	}
+	if err := dbg.SaveCoverage(); err != nil {
+		log.ModEmu.ErrorZ("cannot save coverage").Error("err", err).End()
+	} else {
+		log.ModEmu.InfoZ("coverage saved").String("file", dbg.covFile).End()
+	}
+}
+
 func (dbg *Debugger) AddBreakpoint(pc uint32) {
 	dbg.userBkps = append(dbg.userBkps, pc)
 }
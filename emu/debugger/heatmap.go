@@ -0,0 +1,174 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// Memory accesses are accounted per page of 4 KiB
+const cHeatPageShift = 12
+
+type HeatKind int
+
+const (
+	HeatRead HeatKind = iota
+	HeatWrite
+	HeatExec
+	heatNumKinds
+)
+
+var heatKindNames = [...]string{"read", "write", "exec"}
+
+func (k HeatKind) String() string { return heatKindNames[k] }
+
+const heatPageMask = 1<<(20-cHeatPageShift) - 1
+
+// Counters for all pages within a 1 MiB region
+type heatRegion [heatPageMask + 1][heatNumKinds]uint64
+
+// Heatmap counts memory accesses (reads, writes, executed opcodes) on a
+// per-page basis, to help locating code and data structures. The address
+// space is sparse, so counters are allocated per 1 MiB region, the first
+// time it is accessed.
+type Heatmap struct {
+	regions [1 << 12]*heatRegion
+}
+
+func (h *Heatmap) Add(kind HeatKind, addr uint32) {
+	r := h.regions[addr>>20]
+	if r == nil {
+		r = new(heatRegion)
+		h.regions[addr>>20] = r
+	}
+	r[(addr>>cHeatPageShift)&heatPageMask][kind]++
+}
+
+// Count returns the number of accesses of the specified kind to the page
+// containing addr
+func (h *Heatmap) Count(kind HeatKind, addr uint32) uint64 {
+	if r := h.regions[addr>>20]; r != nil {
+		return r[(addr>>cHeatPageShift)&heatPageMask][kind]
+	}
+	return 0
+}
+
+func (h *Heatmap) Reset() {
+	for i := range h.regions {
+		h.regions[i] = nil
+	}
+}
+
+// Render draws the heatmap for the specified kind of access as text: one line
+// per accessed 1 MiB region, where each character covers a block of
+// consecutive pages, and the intensity is proportional to the logarithm of
+// the number of accesses.
+func (h *Heatmap) Render(kind HeatKind, width int) []string {
+	const shades = " .:-=+*#%@"
+	if width < 1 {
+		width = 1
+	}
+
+	// Find the maximum per-block value, used to scale the intensities
+	var max uint64
+	ppc := (1 << (20 - cHeatPageShift)) / width // pages per char
+	if ppc == 0 {
+		ppc = 1
+	}
+	block := func(r *heatRegion, i int) (tot uint64) {
+		for p := i * ppc; p < (i+1)*ppc && p < len(r); p++ {
+			tot += r[p][kind]
+		}
+		return
+	}
+	for _, r := range h.regions {
+		if r == nil {
+			continue
+		}
+		for i := 0; i < width; i++ {
+			if v := block(r, i); v > max {
+				max = v
+			}
+		}
+	}
+	if max == 0 {
+		return nil
+	}
+
+	var lines []string
+	scale := float64(len(shades)-1) / math.Log(float64(max)+1)
+	for idx, r := range h.regions {
+		if r == nil {
+			continue
+		}
+		line := make([]byte, width)
+		empty := true
+		for i := range line {
+			v := block(r, i)
+			if v != 0 {
+				empty = false
+			}
+			s := int(math.Ceil(math.Log(float64(v)+1) * scale))
+			if s >= len(shades) {
+				s = len(shades) - 1
+			}
+			line[i] = shades[s]
+		}
+		if !empty {
+			lines = append(lines, fmt.Sprintf("%08x %s", uint32(idx)<<20, line))
+		}
+	}
+	return lines
+}
+
+// WriteCSV writes all the non-zero counters in CSV format, one line per page
+func (h *Heatmap) WriteCSV(w io.Writer, cpu string) error {
+	for idx, r := range h.regions {
+		if r == nil {
+			continue
+		}
+		for p := range r {
+			c := &r[p]
+			if c[HeatRead]|c[HeatWrite]|c[HeatExec] == 0 {
+				continue
+			}
+			addr := uint32(idx)<<20 | uint32(p)<<cHeatPageShift
+			_, err := fmt.Fprintf(w, "%s,%08x,%d,%d,%d\n", cpu, addr,
+				c[HeatRead], c[HeatWrite], c[HeatExec])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EnableHeatmap starts profiling memory accesses of all CPUs (names are
+// used to identify them in the CSV). The result can be viewed in the debugger
+// (press "h" to cycle through the kinds of access), and saved in CSV format
+// to the specified file (press "H", or automatically when quitting).
+func (dbg *Debugger) EnableHeatmap(csvfile string, names []string) {
+	dbg.heat = make([]Heatmap, len(dbg.cpus))
+	dbg.heatFile = csvfile
+	dbg.heatNames = names
+}
+
+// SaveHeatmap saves the heatmaps of all CPUs in CSV format
+func (dbg *Debugger) SaveHeatmap() error {
+	f, err := os.Create(dbg.heatFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "cpu,page,reads,writes,execs")
+	for i := range dbg.heat {
+		if err := dbg.heat[i].WriteCSV(w, dbg.heatNames[i]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
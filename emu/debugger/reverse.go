@@ -0,0 +1,186 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rewinder is implemented by the emulator to support reverse execution
+// (see SetRewinder). It keeps a history of snapshots of the emulation, each
+// tagged with the number of instructions executed by each CPU when it was
+// taken (see Steps), and everything required to replay the emulation
+// deterministically from them.
+//
+// A reverse execution command goes through three phases: the rest of the
+// current frame is run without breaking; at the end of the frame, the
+// emulator restores a snapshot (see RewindRequest and Replay); the emulation
+// is then replayed until the target instruction, where the debugger breaks.
+type Rewinder interface {
+	// CanRewind reports whether the history has a snapshot taken before
+	// the specified CPU executed its n-th instruction
+	CanRewind(cpu int, n uint64) bool
+}
+
+// revPoint identifies an instruction in the execution history, as the
+// number of instructions executed by a CPU up to it (included)
+type revPoint struct {
+	cpu int
+	n   uint64
+	msg string // break message
+}
+
+// revState is the state of an ongoing reverse execution command
+type revState struct {
+	unwind  bool      // running the rest of the frame, before the rewind
+	req     *revPoint // rewind requested to the emulator
+	oldest  bool      // restore the oldest snapshot, rather than the latest before req
+	target  *revPoint // where to break while replaying
+	scan    bool      // reverse-continue: record the breakpoint hits while replaying
+	lastHit *revPoint // last breakpoint hit found while scanning
+}
+
+func (r *revState) active() bool {
+	return r.unwind || r.target != nil
+}
+
+// SetRewinder enables reverse execution, through the specified history
+func (dbg *Debugger) SetRewinder(rw Rewinder) {
+	dbg.rw = rw
+}
+
+// Steps returns the number of instructions executed so far by each CPU
+func (dbg *Debugger) Steps() []uint64 {
+	return append([]uint64(nil), dbg.steps...)
+}
+
+// RewindRequest returns the rewind requested by a reverse execution command,
+// if any. The emulator must check it at the end of each frame, restore the
+// latest snapshot taken before the specified CPU executed its n-th
+// instruction (or the oldest snapshot in the history, if oldest is true),
+// and then call Replay (or RewindFailed).
+func (dbg *Debugger) RewindRequest() (cpu int, n uint64, oldest bool, ok bool) {
+	if dbg.rev.req == nil {
+		return 0, 0, false, false
+	}
+	return dbg.rev.req.cpu, dbg.rev.req.n, dbg.rev.oldest, true
+}
+
+// Replay must be called after restoring the snapshot for a rewind request,
+// with the instruction counters saved in it (see Steps). The debugger then
+// breaks when the replayed emulation reaches the target instruction.
+func (dbg *Debugger) Replay(steps []uint64) {
+	copy(dbg.steps, steps)
+	dbg.rev.target, dbg.rev.req = dbg.rev.req, nil
+	dbg.rev.unwind = false
+}
+
+// RewindFailed must be called if the snapshot for a rewind request can't be
+// restored; the debugger breaks immediately.
+func (dbg *Debugger) RewindFailed(err error) {
+	dbg.rev = revState{}
+	dbg.Break(fmt.Sprintf("cannot rewind: %v", err))
+}
+
+// current returns the number of the instruction that the specified CPU is
+// executing: the CPU that called Trace last is stopped at (or in the middle
+// of) its last instruction, while the other ones are before the next one.
+func (dbg *Debugger) current(cpu int) uint64 {
+	if cpu == dbg.lastTrace {
+		return dbg.steps[cpu]
+	}
+	return dbg.steps[cpu] + 1
+}
+
+// requestRewind starts the first phase of a reverse execution command (see
+// Rewinder)
+func (dbg *Debugger) requestRewind(p revPoint, oldest bool) {
+	dbg.rev.unwind = true
+	dbg.rev.req = &p
+	dbg.rev.oldest = oldest
+}
+
+// reverseStep runs the current CPU backwards by the specified number of
+// instructions
+func (dbg *Debugger) reverseStep(count uint64) error {
+	cpu := dbg.curcpu
+	cur := dbg.current(cpu)
+	if dbg.rw == nil {
+		return errors.New("reverse execution is not enabled")
+	}
+	if count >= cur || !dbg.rw.CanRewind(cpu, cur-count) {
+		return errors.New("not enough history")
+	}
+	dbg.requestRewind(revPoint{cpu, cur - count, "reverse step"}, false)
+	dbg.resumeEmulation(true, func() {
+		dbg.refreshUi()
+	})
+	return nil
+}
+
+// reverseContinue runs backwards until the last breakpoint or watchpoint
+// hit before the current instruction of the current CPU. The history is
+// replayed twice: from the oldest snapshot to find the hit, and then from
+// the snapshot before it.
+func (dbg *Debugger) reverseContinue() error {
+	cpu := dbg.curcpu
+	cur := dbg.current(cpu)
+	if dbg.rw == nil {
+		return errors.New("reverse execution is not enabled")
+	}
+	if !dbg.rw.CanRewind(cpu, cur) {
+		return errors.New("not enough history")
+	}
+	dbg.rev.scan = true
+	dbg.requestRewind(revPoint{cpu, cur, "no earlier breakpoint in the history"}, true)
+	dbg.resumeEmulation(true, func() {
+		dbg.refreshUi()
+	})
+	return nil
+}
+
+// traceReverse is called by Trace while a reverse execution command is
+// ongoing; it returns true if the instruction must not be traced further.
+func (dbg dbgForCpu) traceReverse(pc uint32) bool {
+	if dbg.rev.unwind {
+		return true
+	}
+	idx := dbg.cpuidx
+	t := dbg.rev.target
+	if t == nil {
+		return false
+	}
+	dbg.updateChain(pc)
+
+	at := idx == t.cpu && dbg.steps[idx] == t.n
+	if dbg.rev.scan && !at {
+		if msg, hit := dbg.checkUserBreakpoint(pc); hit {
+			dbg.rev.lastHit = &revPoint{idx, dbg.steps[idx], msg}
+		}
+	}
+	if !at {
+		return true
+	}
+
+	dbg.rev.target = nil
+	if dbg.rev.scan {
+		dbg.rev.scan = false
+		if hit := dbg.rev.lastHit; hit != nil {
+			dbg.rev.lastHit = nil
+			dbg.requestRewind(*hit, false)
+			return true
+		}
+	}
+	dbg.curcpu = idx
+	dbg.Break(t.msg)
+	return true
+}
+
+// watchReverse is called by the watchpoint hooks while a reverse execution
+// command is ongoing. Watchpoints don't break, but while scanning they are
+// recorded as hits on the instruction that does the access.
+func (dbg dbgForCpu) watchReverse() {
+	if dbg.rev.scan && dbg.rev.target != nil {
+		idx := dbg.cpuidx
+		dbg.rev.lastHit = &revPoint{idx, dbg.steps[idx], "watchpoint"}
+	}
+}
@@ -0,0 +1,98 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Symbol struct {
+	Addr uint32
+	Name string
+}
+
+// Symbols is a table of code symbols, used to annotate addresses shown by the
+// debugger. It can be loaded from a no$gba-style .sym file, which contains
+// one symbol per line ("02000800 main"). Comments start with ";", and
+// no$gba directives (like ".arm" or ".byt:0010") are ignored.
+type Symbols struct {
+	syms []Symbol // sorted by address
+}
+
+func LoadSymbols(r io.Reader) (*Symbols, error) {
+	s := &Symbols{}
+	sc := bufio.NewScanner(r)
+	for nline := 1; sc.Scan(); nline++ {
+		line := sc.Text()
+		if idx := strings.IndexByte(line, ';'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: invalid symbol definition", nline)
+		}
+		addr, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "0x"), 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid address: %v", nline, err)
+		}
+		if strings.HasPrefix(fields[1], ".") {
+			continue
+		}
+		s.syms = append(s.syms, Symbol{Addr: uint32(addr), Name: fields[1]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(s.syms, func(i, j int) bool { return s.syms[i].Addr < s.syms[j].Addr })
+	return s, nil
+}
+
+// Lookup returns the symbol containing addr, that is the closest symbol
+// defined at or before it
+func (s *Symbols) Lookup(addr uint32) (Symbol, bool) {
+	if s == nil {
+		return Symbol{}, false
+	}
+	idx := sort.Search(len(s.syms), func(i int) bool { return s.syms[i].Addr > addr })
+	if idx == 0 {
+		return Symbol{}, false
+	}
+	return s.syms[idx-1], true
+}
+
+// Format describes addr as symbol+offset (or an empty string if there is no
+// symbol for it)
+func (s *Symbols) Format(addr uint32) string {
+	sym, ok := s.Lookup(addr)
+	if !ok {
+		return ""
+	}
+	if addr == sym.Addr {
+		return sym.Name
+	}
+	return fmt.Sprintf("%s+0x%x", sym.Name, addr-sym.Addr)
+}
+
+// LoadSymbols loads the symbol file for the specified CPU
+func (dbg *Debugger) LoadSymbols(cpuidx int, fn string) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	syms, err := LoadSymbols(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fn, err)
+	}
+	dbg.syms[cpuidx] = syms
+	return nil
+}
@@ -27,6 +27,10 @@ func (dbg *Debugger) initUi() {
 	dbg.uiLog.Height = 20
 	dbg.log.SetNumLines(20)
 
+	dbg.prompt.ui = ui.NewPar("")
+	dbg.prompt.ui.BorderLabel = "Command"
+	dbg.prompt.ui.BorderFg = ui.ColorYellow
+
 	ui.Body.AddRows(
 		ui.NewRow(
 			ui.NewCol(6, 0, dbg.uiCode),
@@ -55,6 +59,13 @@ func (dbg *Debugger) disasmBlock(pc uint32, sz int, area uint32) {
 			buf = make([]byte, sz)
 		} else {
 			text, buf = dbg.cpus[dbg.curcpu].Disasm(pc)
+			if dec, ok := dbg.cpus[dbg.curcpu].(InsnDecoder); ok {
+				if insn, ok := dec.DisasmInsn(pc); ok && insn.HasTarget {
+					if sym := dbg.syms[dbg.curcpu].Format(insn.Target); sym != "" {
+						text += " <" + sym + ">"
+					}
+				}
+			}
 		}
 		datahex := hex.EncodeToString(buf)
 		dbg.linepc[i] = pc
@@ -154,6 +165,23 @@ func (dbg *Debugger) refreshCall() {
 }
 
 func (dbg *Debugger) refreshLog() {
+	// The backtrace is computed only while the CPU is stopped
+	if dbg.btView && !dbg.running[dbg.curcpu] {
+		dbg.uiLog.BorderLabel = "Backtrace"
+		dbg.uiLog.Items = dbg.FormatBacktrace(dbg.curcpu, dbg.Backtrace(dbg.curcpu))
+		return
+	}
+	if dbg.isAudioView() {
+		dbg.uiLog.BorderLabel = "Audio"
+		dbg.uiLog.Items = dbg.scope.Render(dbg.uiLog.Width-4, dbg.uiLog.Height-2)
+		return
+	}
+	if dbg.heatView >= 0 {
+		dbg.uiLog.BorderLabel = fmt.Sprintf("Heatmap (%v)", dbg.heatView)
+		dbg.uiLog.Items = dbg.heat[dbg.curcpu].Render(dbg.heatView, dbg.uiLog.Width-12)
+		return
+	}
+	dbg.uiLog.BorderLabel = "Logging"
 	dbg.uiLog.Items = dbg.log.Lines()
 }
 
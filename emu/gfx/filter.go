@@ -0,0 +1,357 @@
+package gfx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Filter is a software post-processing filter applied to a whole frame.
+// Filters can upscale the image by an integer factor (eg: pixel-art scalers).
+type Filter interface {
+	// Scale returns the upscaling factor of the filter
+	Scale() int
+
+	// Apply runs the filter on src, writing the result into dst, whose size
+	// must be Scale() times the size of src.
+	Apply(dst, src Buffer)
+}
+
+var filters = map[string]Filter{
+	"scale2x": Scale2x{},
+	"scale3x": Scale3x{},
+	"2xbr":    Xbr2x{},
+}
+
+// FilterByName returns the filter registered with the specified name
+func FilterByName(name string) (Filter, bool) {
+	f, ok := filters[name]
+	return f, ok
+}
+
+// FilterNames returns the names of all the available filters
+func FilterNames() []string {
+	var names []string
+	for n := range filters {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FilterChain applies a sequence of filters to a frame, managing the
+// intermediate buffers.
+type FilterChain struct {
+	filters []Filter
+	bufs    []Buffer
+}
+
+func NewFilterChain(w, h int, filters ...Filter) *FilterChain {
+	fc := &FilterChain{filters: filters}
+	for _, f := range filters {
+		w, h = w*f.Scale(), h*f.Scale()
+		fc.bufs = append(fc.bufs, NewBufferMem(w, h))
+	}
+	return fc
+}
+
+// ParseFilterChain creates a filter chain from a list of filter names
+func ParseFilterChain(w, h int, names []string) (*FilterChain, error) {
+	var fs []Filter
+	for _, n := range names {
+		f, ok := FilterByName(n)
+		if !ok {
+			return nil, fmt.Errorf("unknown filter: %q", n)
+		}
+		fs = append(fs, f)
+	}
+	return NewFilterChain(w, h, fs...), nil
+}
+
+// Scale returns the total upscaling factor of the chain
+func (fc *FilterChain) Scale() int {
+	s := 1
+	for _, f := range fc.filters {
+		s *= f.Scale()
+	}
+	return s
+}
+
+func (fc *FilterChain) Len() int {
+	return len(fc.filters)
+}
+
+// Apply runs all the filters in sequence, and returns the final buffer.
+// The returned buffer is owned by the chain, and is valid until the next
+// call to Apply.
+func (fc *FilterChain) Apply(src Buffer) Buffer {
+	for i, f := range fc.filters {
+		f.Apply(fc.bufs[i], src)
+		src = fc.bufs[i]
+	}
+	return src
+}
+
+// clampLine returns the line at y, clamping y within the buffer
+func (buf *Buffer) clampLine(y int) Line {
+	if y < 0 {
+		y = 0
+	}
+	if y >= buf.Height {
+		y = buf.Height - 1
+	}
+	return buf.Line(y)
+}
+
+// pixel3 fetches the pixels at x-1, x, x+1, clamping at the borders
+func pixel3(l Line, x, w int) (uint32, uint32, uint32) {
+	c := l.Get32(x)
+	a, b := c, c
+	if x > 0 {
+		a = l.Get32(x - 1)
+	}
+	if x < w-1 {
+		b = l.Get32(x + 1)
+	}
+	return a, c, b
+}
+
+// Scale2x implements the Scale2x (aka AdvMAME2x) pixel-art scaler, that
+// smooths diagonal edges without blurring.
+type Scale2x struct{}
+
+func (Scale2x) Scale() int { return 2 }
+
+func (Scale2x) Apply(dst, src Buffer) {
+	w := src.Width
+	for y := 0; y < src.Height; y++ {
+		up := src.clampLine(y - 1)
+		mid := src.Line(y)
+		down := src.clampLine(y + 1)
+		d0 := dst.Line(y * 2)
+		d1 := dst.Line(y*2 + 1)
+
+		for x := 0; x < w; x++ {
+			b := up.Get32(x)
+			h := down.Get32(x)
+			d, e, f := pixel3(mid, x, w)
+
+			e0, e1, e2, e3 := e, e, e, e
+			if b != h && d != f {
+				if d == b {
+					e0 = d
+				}
+				if b == f {
+					e1 = f
+				}
+				if d == h {
+					e2 = d
+				}
+				if h == f {
+					e3 = f
+				}
+			}
+			d0.Set32(x*2, e0)
+			d0.Set32(x*2+1, e1)
+			d1.Set32(x*2, e2)
+			d1.Set32(x*2+1, e3)
+		}
+	}
+}
+
+// Scale3x implements the Scale3x (aka AdvMAME3x) pixel-art scaler.
+type Scale3x struct{}
+
+func (Scale3x) Scale() int { return 3 }
+
+func (Scale3x) Apply(dst, src Buffer) {
+	w := src.Width
+	for y := 0; y < src.Height; y++ {
+		up := src.clampLine(y - 1)
+		mid := src.Line(y)
+		down := src.clampLine(y + 1)
+		d0 := dst.Line(y * 3)
+		d1 := dst.Line(y*3 + 1)
+		d2 := dst.Line(y*3 + 2)
+
+		for x := 0; x < w; x++ {
+			a, b, c := pixel3(up, x, w)
+			d, e, f := pixel3(mid, x, w)
+			g, h, i := pixel3(down, x, w)
+
+			e0, e1, e2 := e, e, e
+			e3, e4, e5 := e, e, e
+			e6, e7, e8 := e, e, e
+			if b != h && d != f {
+				if d == b {
+					e0 = d
+				}
+				if (d == b && e != c) || (b == f && e != a) {
+					e1 = b
+				}
+				if b == f {
+					e2 = f
+				}
+				if (d == b && e != g) || (d == h && e != a) {
+					e3 = d
+				}
+				if (b == f && e != i) || (h == f && e != c) {
+					e5 = f
+				}
+				if d == h {
+					e6 = d
+				}
+				if (d == h && e != i) || (h == f && e != g) {
+					e7 = h
+				}
+				if h == f {
+					e8 = f
+				}
+			}
+			d0.Set32(x*3, e0)
+			d0.Set32(x*3+1, e1)
+			d0.Set32(x*3+2, e2)
+			d1.Set32(x*3, e3)
+			d1.Set32(x*3+1, e4)
+			d1.Set32(x*3+2, e5)
+			d2.Set32(x*3, e6)
+			d2.Set32(x*3+1, e7)
+			d2.Set32(x*3+2, e8)
+		}
+	}
+}
+
+// Xbr2x implements the 2xBR pixel-art scaler (by Hyllian), that detects edges
+// by comparing weighted color distances along the two diagonals of each
+// pixel, and blends the corners crossed by an edge with the color beyond it.
+// Compared to Scale2x, it follows shallow slopes too, and antialiases them.
+type Xbr2x struct{}
+
+func (Xbr2x) Scale() int { return 2 }
+
+// xbrDist is the distance between two colors, as the sum of the absolute
+// differences of their YUV components
+func xbrDist(a, b uint32) int {
+	r := int(a&0xFF) - int(b&0xFF)
+	g := int(a>>8&0xFF) - int(b>>8&0xFF)
+	bl := int(a>>16&0xFF) - int(b>>16&0xFF)
+	y := (299*r + 587*g + 114*bl) / 1000
+	u := (-169*r - 331*g + 500*bl) / 1000
+	v := (500*r - 419*g - 81*bl) / 1000
+	return abs(y) + abs(u) + abs(v)
+}
+
+func xbrEq(a, b uint32) bool {
+	return xbrDist(a, b) < 155
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// xbrBlend mixes m/8 of color b into color a, on each channel
+func xbrBlend(a, b uint32, m uint) uint32 {
+	var c uint32
+	for s := uint(0); s < 32; s += 8 {
+		ca, cb := int(a>>s&0xFF), int(b>>s&0xFF)
+		c |= uint32(ca+(cb-ca)*int(m)/8) << s
+	}
+	return c
+}
+
+// xbrCorner runs the 2xBR edge detection for the bottom-right corner of the
+// pixel pe; the other corners are handled by rotating the neighborhood:
+//
+//	   a1 b1 c1
+//	a0 pa pb pc c4
+//	d0 pd pe pf f4
+//	g0 pg ph pi i4
+//	   g5 h5 i5
+//
+// n3 points to the output pixel at the corner, n2 and n1 to the pixels beside
+// it horizontally and vertically.
+func xbrCorner(pe, pi, ph, pf, pg, pc, pd, pb, f4, i4, h5, i5 uint32, n1, n2, n3 *uint32) {
+	if pe == ph || pe == pf {
+		return
+	}
+	e := xbrDist(pe, pc) + xbrDist(pe, pg) + xbrDist(pi, h5) + xbrDist(pi, f4) + xbrDist(ph, pf)*4
+	i := xbrDist(ph, pd) + xbrDist(ph, i5) + xbrDist(pf, i4) + xbrDist(pf, pb) + xbrDist(pe, pi)*4
+	if e > i {
+		return
+	}
+
+	px := ph
+	if xbrDist(pe, pf) <= xbrDist(pe, ph) {
+		px = pf
+	}
+	if e == i || !((!xbrEq(pf, pb) && !xbrEq(ph, pd)) ||
+		(xbrEq(pe, pi) && !xbrEq(pf, i4) && !xbrEq(ph, i5)) ||
+		xbrEq(pe, pg) || xbrEq(pe, pc)) {
+		*n3 = xbrBlend(*n3, px, 4)
+		return
+	}
+
+	// Shallow edges (mostly horizontal or vertical) cross two output pixels
+	ke, ki := xbrDist(pf, pg), xbrDist(ph, pc)
+	left := ke*2 <= ki && pe != pg && pd != pg
+	up := ke >= ki*2 && pe != pc && pb != pc
+	switch {
+	case left && up:
+		*n3 = xbrBlend(*n3, px, 7)
+		*n2 = xbrBlend(*n2, px, 2)
+		*n1 = *n2
+	case left:
+		*n3 = xbrBlend(*n3, px, 6)
+		*n2 = xbrBlend(*n2, px, 2)
+	case up:
+		*n3 = xbrBlend(*n3, px, 6)
+		*n1 = xbrBlend(*n1, px, 2)
+	default:
+		*n3 = xbrBlend(*n3, px, 4)
+	}
+}
+
+func (Xbr2x) Apply(dst, src Buffer) {
+	w := src.Width
+	at := func(l Line, x int) uint32 {
+		if x < 0 {
+			x = 0
+		} else if x >= w {
+			x = w - 1
+		}
+		return l.Get32(x)
+	}
+
+	for y := 0; y < src.Height; y++ {
+		l0 := src.clampLine(y - 2)
+		l1 := src.clampLine(y - 1)
+		l2 := src.Line(y)
+		l3 := src.clampLine(y + 1)
+		l4 := src.clampLine(y + 2)
+		o0 := dst.Line(y * 2)
+		o1 := dst.Line(y*2 + 1)
+
+		for x := 0; x < w; x++ {
+			a1, b1, c1 := at(l0, x-1), at(l0, x), at(l0, x+1)
+			a0, pa, pb, pc, c4 := at(l1, x-2), at(l1, x-1), at(l1, x), at(l1, x+1), at(l1, x+2)
+			d0, pd, pe, pf, f4 := at(l2, x-2), at(l2, x-1), at(l2, x), at(l2, x+1), at(l2, x+2)
+			g0, pg, ph, pi, i4 := at(l3, x-2), at(l3, x-1), at(l3, x), at(l3, x+1), at(l3, x+2)
+			g5, h5, i5 := at(l4, x-1), at(l4, x), at(l4, x+1)
+
+			// Output pixels: 0 top-left, 1 top-right, 2 bottom-left, 3 bottom-right
+			var e [4]uint32
+			e[0], e[1], e[2], e[3] = pe, pe, pe, pe
+			xbrCorner(pe, pi, ph, pf, pg, pc, pd, pb, f4, i4, h5, i5, &e[1], &e[2], &e[3])
+			xbrCorner(pe, pc, pf, pb, pi, pa, ph, pd, b1, c1, f4, c4, &e[0], &e[3], &e[1])
+			xbrCorner(pe, pa, pb, pd, pc, pg, pf, ph, d0, a0, b1, a1, &e[2], &e[1], &e[0])
+			xbrCorner(pe, pg, pd, ph, pa, pi, pb, pf, h5, g5, d0, g0, &e[3], &e[0], &e[2])
+
+			o0.Set32(x*2, e[0])
+			o0.Set32(x*2+1, e[1])
+			o1.Set32(x*2, e[2])
+			o1.Set32(x*2+1, e[3])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package gfx
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// rotate returns a copy of a square buffer rotated by 90 degrees clockwise
+func rotate(src Buffer) Buffer {
+	n := src.Width
+	dst := NewBufferMem(n, n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			dst.Line(y).Set32(x, src.Line(n-1-x).Get32(y))
+		}
+	}
+	return dst
+}
+
+func equalBuffers(a, b Buffer) bool {
+	for y := 0; y < a.Height; y++ {
+		for x := 0; x < a.Width; x++ {
+			if a.Line(y).Get32(x) != b.Line(y).Get32(x) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestXbr2x(t *testing.T) {
+	const n = 16
+	apply := func(src Buffer) Buffer {
+		dst := NewBufferMem(n*2, n*2)
+		Xbr2x{}.Apply(dst, src)
+		return dst
+	}
+
+	// Flat areas are left untouched
+	src := NewBufferMem(n, n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			src.Line(y).Set32(x, 0xFF336699)
+		}
+	}
+	dst := apply(src)
+	for y := 0; y < n*2; y++ {
+		for x := 0; x < n*2; x++ {
+			if c := dst.Line(y).Get32(x); c != 0xFF336699 {
+				t.Fatalf("flat image: pixel (%d,%d) = %08x", x, y, c)
+			}
+		}
+	}
+
+	// A 45-degree edge is antialiased: on the black pixels along the
+	// diagonal, the top-right corner (crossed by the edge) is blended with
+	// white, while the bottom-left one stays black
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			c := uint32(0xFF000000)
+			if x > y {
+				c = 0xFFFFFFFF
+			}
+			src.Line(y).Set32(x, c)
+		}
+	}
+	dst = apply(src)
+	if c := dst.Line(8).Get32(9); c != 0xFF7F7F7F {
+		t.Errorf("edge: pixel (9,8) = %08x, want half blend", c)
+	}
+	if c := dst.Line(9).Get32(8); c != 0xFF000000 {
+		t.Errorf("edge: pixel (8,9) = %08x, want black", c)
+	}
+
+	// Each corner is handled by rotating the neighborhood, so the filter
+	// must commute with rotations
+	rnd := rand.New(rand.NewSource(1))
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			c := uint32(0xFF000000)
+			if rnd.Intn(2) == 0 {
+				c = 0xFFFFFFFF
+			}
+			src.Line(y).Set32(x, c)
+		}
+	}
+	if !equalBuffers(apply(rotate(src)), rotate(apply(src))) {
+		t.Errorf("filter does not commute with rotation")
+	}
+}
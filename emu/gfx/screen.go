@@ -92,3 +92,13 @@ func (buf *Buffer) LineAsSlice(y int) []uint8 {
 	}
 	return nil
 }
+
+// SubBuffer returns a buffer that refers to the specified rectangle within
+// buf (sharing the same memory)
+func (buf *Buffer) SubBuffer(x, y, w, h int) Buffer {
+	if x < 0 || y < 0 || w < 0 || h < 0 || x+w > buf.Width || y+h > buf.Height {
+		panic("invalid sub-buffer")
+	}
+	ptr := unsafe.Pointer(uintptr(buf.ptr) + uintptr(y*buf.pitch+x*4))
+	return NewBuffer(ptr, w, h, buf.pitch)
+}
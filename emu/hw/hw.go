@@ -2,7 +2,12 @@ package hw
 
 import (
 	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -13,19 +18,99 @@ import (
 
 const (
 	kHwAudioBuffers = 3
+
+	// Amount of audio (in frames) that we try to keep queued in the audio
+	// device. A small margin absorbs scheduling jitter without adding
+	// noticeable latency.
+	kAudioTargetFrames = 2
+
+	// Maximum deviation of the audio resampling ratio used to correct drift
+	// when pacing on vsync (0.5% is not perceivable as a pitch change).
+	kAudioMaxDrift = 0.005
+
+	// Frame rate used when throttling emulation in background
+	kBackgroundThrottleFps = 10
+
+	// Maximum time spent on Close waiting for the queued audio to play
+	kAudioDrainTimeout = 500 * time.Millisecond
+)
+
+// PacingMode selects the clock used to throttle emulation to real speed
+type PacingMode int
+
+const (
+	// Throttle on the audio device clock: each frame waits until the audio
+	// queue drains down to the target level. This is the default, and gives
+	// perfect audio, at the cost of an occasional repeated/skipped frame on
+	// displays whose refresh rate doesn't match the emulated one.
+	PacingAudio PacingMode = iota
+
+	// Throttle on the display vsync: presentation blocks until the next
+	// refresh, and audio is slightly resampled to compensate the drift
+	// between the emulated and the host refresh rate. This gives smooth
+	// video on 60Hz displays.
+	PacingVsync
+)
+
+// BackgroundMode selects what happens to emulation while the window
+// doesn't have the focus.
+type BackgroundMode int
+
+const (
+	// Keep running at full speed, as if the window was focused
+	BackgroundRun BackgroundMode = iota
+
+	// Run at a reduced frame rate (kBackgroundThrottleFps) to save battery,
+	// with audio muted
+	BackgroundThrottle
+
+	// Pause emulation (see Output.Paused), with audio muted
+	BackgroundPause
 )
 
 type OutputConfig struct {
-	Title             string // Name of the window (displayed in titlebar)
-	Width, Height     int    // Size of the window in pixels
-	FramePerSecond    int    // Number of frames per second when running at full speed
-	EnforceSpeed      bool   // True if we want to block to enforce the requested FramePerSecond / Audio.Frequency
-	NumBackBuffers    int    // Number of back buffers used; more buffers means smoother but laggier (default=2)
-	AudioFrequency    int    // Audio frequency in hertz
-	AudioChannels     int    // Number of output channels (1 or 2)
-	AudioSampleSigned bool   // True if samples are signed, False if unsigned
+	Title             string         // Name of the window (displayed in titlebar)
+	Width, Height     int            // Size of the window in pixels
+	FramePerSecond    int            // Number of frames per second when running at full speed
+	EnforceSpeed      bool           // True if we want to block to enforce the requested FramePerSecond / Audio.Frequency
+	NumBackBuffers    int            // Number of back buffers used; more buffers means smoother but laggier (default=2)
+	AudioFrequency    int            // Audio frequency in hertz
+	AudioChannels     int            // Number of output channels (1 or 2)
+	AudioSampleSigned bool           // True if samples are signed, False if unsigned
+	Pacing            PacingMode     // Clock used to enforce speed (if EnforceSpeed is true)
+	Filters           []string       // Software post-processing filters applied to each frame (see gfx.FilterNames)
+	Bilinear          bool           // Use bilinear filtering when scaling to window size (otherwise nearest)
+	Rotation          int            // Clockwise rotation of the output, in degrees (multiple of 90)
+	Background        BackgroundMode // Behavior when the window loses focus
+	Recover           func()         // Deferred by the output goroutines, to handle panics (optional)
 }
 
+// ParseFilterSpec parses a comma-separated list of output filters. The
+// list can be terminated by "nearest" or "bilinear" to select how the final
+// image is scaled to the window size.
+func ParseFilterSpec(spec string) (filters []string, bilinear bool, err error) {
+	if spec == "" {
+		return nil, false, nil
+	}
+	for _, name := range strings.Split(spec, ",") {
+		switch name {
+		case "nearest":
+			bilinear = false
+		case "bilinear":
+			bilinear = true
+		default:
+			if _, ok := gfx.FilterByName(name); !ok {
+				return nil, false, fmt.Errorf("unknown filter %q (available: nearest, bilinear, %s)",
+					name, strings.Join(gfx.FilterNames(), ", "))
+			}
+			filters = append(filters, name)
+		}
+	}
+	return
+}
+
+// A frame sent to the render goroutine: either the video or the audio of an
+// emulated frame
 type frame struct {
 	video gfx.Buffer
 	audio AudioBuffer
@@ -34,33 +119,53 @@ type frame struct {
 type Output struct {
 	cfg OutputConfig
 
-	quit    bool
-	framech chan frame
+	quit      bool
+	unfocused int32 // 1 while the window is in background (atomic, see poll)
+	framech   chan frame
+	dropch    chan string
+	rendered  chan struct{} // closed when render() exits
 
 	mouse struct {
 		x, y    int
 		buttons MouseButtons
+		wheel   int32 // vertical scroll accumulated since the last MouseWheel
 	}
+	touch *TouchWindow // secondary window for touch input (see NewTouchWindow)
 
 	screen      *sdl.Window
 	renderer    *sdl.Renderer
 	frame       *sdl.Texture
+	filter      *gfx.FilterChain
 	framebuf    [][]byte
 	framebufidx int
 
 	videoEnabled bool
 	audioEnabled bool
-	framecounter int
 	fpscounter   int
 	fpsclock     uint32
 	fpsticks     []time.Time
 	fpsticksidx  int
 
-	audioDev sdl.AudioDeviceID
-	audiobuf []AudioBuffer
+	audioDev    sdl.AudioDeviceID
+	audiobuf    []AudioBuffer
+	audiobufidx int
+	resampled   AudioBuffer
+}
+
+// Size of the output in window (logical) coordinates, after rotation
+func (cfg *OutputConfig) logicalSize() (int, int) {
+	if cfg.Rotation%180 != 0 {
+		return cfg.Height, cfg.Width
+	}
+	return cfg.Width, cfg.Height
 }
 
 func NewOutput(cfg OutputConfig) *Output {
+	cfg.Rotation = ((cfg.Rotation % 360) + 360) % 360
+	if cfg.Rotation%90 != 0 {
+		panic("output rotation must be a multiple of 90 degrees")
+	}
+
 	sdl.Do(func() {
 		if sdl.WasInit(sdl.INIT_VIDEO|sdl.INIT_AUDIO) == 0 {
 			sdl.Init(sdl.INIT_VIDEO | sdl.INIT_AUDIO)
@@ -86,6 +191,8 @@ func NewOutput(cfg OutputConfig) *Output {
 		framebuf: framebuf,
 		audiobuf: audiobuf,
 		framech:  make(chan frame, cfg.NumBackBuffers-2),
+		dropch:   make(chan string, 1),
+		rendered: make(chan struct{}),
 		fpsticks: make([]time.Time, cfg.FramePerSecond),
 	}
 	go out.render()
@@ -98,28 +205,27 @@ func (out *Output) EnableVideo(enable bool) {
 		if enable && !out.videoEnabled {
 			var err error
 
+			lw, lh := out.cfg.logicalSize()
 			out.screen, err = sdl.CreateWindow(out.cfg.Title,
 				sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED,
-				int32(out.cfg.Width*4/2), int32(out.cfg.Height*4/2), sdl.WINDOW_RESIZABLE)
+				int32(lw*4/2), int32(lh*4/2), sdl.WINDOW_RESIZABLE)
 			if err != nil {
 				panic(err)
 			}
 
-			// Create a renderer than never sync with vsync.
-			// Syncing is always done with audio, not vsync,
-			out.renderer, err = sdl.CreateRenderer(out.screen, -1, 0)
+			// Create a renderer that syncs with vsync only if requested;
+			// otherwise, syncing is done with audio.
+			var flags uint32
+			if out.cfg.EnforceSpeed && out.cfg.Pacing == PacingVsync {
+				flags |= sdl.RENDERER_PRESENTVSYNC
+			}
+			out.renderer, err = sdl.CreateRenderer(out.screen, -1, flags)
 			if err != nil {
 				panic(err)
 			}
-			out.renderer.SetLogicalSize(int32(out.cfg.Width), int32(out.cfg.Height))
-
-			// make the scaled rendering look smoother.
-			sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "nearest")
+			out.renderer.SetLogicalSize(int32(lw), int32(lh))
 
-			out.frame, err = out.renderer.CreateTexture(
-				sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING,
-				int32(out.cfg.Width), int32(out.cfg.Height))
-			if err != nil {
+			if err := out.setFilters(out.cfg.Filters, out.cfg.Bilinear); err != nil {
 				panic(err)
 			}
 		} else {
@@ -135,6 +241,50 @@ func (out *Output) EnableVideo(enable bool) {
 	})
 }
 
+// SetFilters changes the post-processing filters applied to the output.
+// It can be called at any time, eg: when switching screen layout.
+func (out *Output) SetFilters(filters []string, bilinear bool) (err error) {
+	sdl.Do(func() {
+		if !out.videoEnabled {
+			// Applied when video is enabled
+			out.cfg.Filters, out.cfg.Bilinear = filters, bilinear
+			return
+		}
+		err = out.setFilters(filters, bilinear)
+	})
+	return
+}
+
+func (out *Output) setFilters(filters []string, bilinear bool) error {
+	chain, err := gfx.ParseFilterChain(out.cfg.Width, out.cfg.Height, filters)
+	if err != nil {
+		return err
+	}
+
+	if bilinear {
+		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "linear")
+	} else {
+		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "nearest")
+	}
+
+	// The texture must be recreated, both because its size depends on the
+	// filters, and because SDL applies the scale quality hint at creation.
+	if out.frame != nil {
+		out.frame.Destroy()
+	}
+	scale := chain.Scale()
+	out.frame, err = out.renderer.CreateTexture(
+		sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING,
+		int32(out.cfg.Width*scale), int32(out.cfg.Height*scale))
+	if err != nil {
+		return err
+	}
+
+	out.filter = chain
+	out.cfg.Filters, out.cfg.Bilinear = filters, bilinear
+	return nil
+}
+
 func (out *Output) EnableAudio(enable bool) {
 	if !enable {
 		panic("unimplemented")
@@ -166,59 +316,77 @@ func (out *Output) EnableAudio(enable bool) {
 	})
 }
 
-func (out *Output) BeginFrame() (gfx.Buffer, AudioBuffer) {
+// VideoBuffer returns the back buffer where the next frame must be drawn,
+// before passing it to SubmitVideo.
+func (out *Output) VideoBuffer() gfx.Buffer {
 	out.framebufidx++
 	if out.framebufidx == out.cfg.NumBackBuffers {
 		out.framebufidx = 0
 	}
-	fbuf := gfx.NewBuffer(unsafe.Pointer(&out.framebuf[out.framebufidx][0]),
+	return gfx.NewBuffer(unsafe.Pointer(&out.framebuf[out.framebufidx][0]),
 		out.cfg.Width, out.cfg.Height, out.cfg.Width*4)
-	abuf := out.audiobuf[out.framebufidx]
-
-	fc := out.framecounter % out.cfg.FramePerSecond
-	nsamples0 := (out.cfg.AudioFrequency * fc) / out.cfg.FramePerSecond
-	nsamples1 := (out.cfg.AudioFrequency * (fc + 1)) / out.cfg.FramePerSecond
-	ns := nsamples1 - nsamples0
-	return fbuf, abuf[:ns*out.cfg.AudioChannels]
 }
 
-func (out *Output) EndFrame(screen gfx.Buffer, audio AudioBuffer) {
-	out.framecounter++
+// SubmitVideo presents a frame drawn into the buffer returned by VideoBuffer.
+// If audio is disabled, it also enforces the emulation speed.
+func (out *Output) SubmitVideo(screen gfx.Buffer) {
 	// Send the frame to the render() goroutine; this normally avoids blocking unless we're going
 	// too fast, in which case the channel buffer would be full and the call would block.
-	out.framech <- frame{screen, audio}
+	out.framech <- frame{video: screen}
+}
+
+// SubmitAudio queues the audio of a frame (interleaved samples), enforcing
+// the emulation speed. Samples are copied, so the caller can reuse the buffer.
+func (out *Output) SubmitAudio(audio AudioBuffer) {
+	out.audiobufidx++
+	if out.audiobufidx == out.cfg.NumBackBuffers {
+		out.audiobufidx = 0
+	}
+	abuf := append(out.audiobuf[out.audiobufidx][:0], audio...)
+	out.audiobuf[out.audiobufidx] = abuf
+	out.framech <- frame{audio: abuf}
 }
 
 func (out *Output) render() {
+	defer close(out.rendered)
+	if out.cfg.Recover != nil {
+		defer out.cfg.Recover()
+	}
 	for f := range out.framech {
 		sdl.Do(func() {
+			if f.audio != nil {
+				// When throttling in background, audio is muted.
+				// When audio is enabled, we use it to enforce the correct speed
+				// instead of using a timer. This avoids sound cracks.
+				if out.audioEnabled && !out.throttled() && len(f.audio) > 0 {
+					if out.cfg.EnforceSpeed {
+						out.paceAudio(f.audio)
+					} else {
+						// If speed is not enforced, we want to avoid queueing too much audio
+						// as it would desync from video. So send audio only if
+						// there's less than a frame's worth in the buffer.
+						if sdl.GetQueuedAudioSize(out.audioDev) < uint32(len(f.audio)*2) {
+							out.renderAudio(f.audio)
+						}
+					}
+				}
+				return
+			}
+
 			if out.videoEnabled {
 				out.renderVideo(f.video)
 			}
 
-			// When audio is enabled, we use it to enforce the correct speed
-			// instead of using a timer. This avoids sound cracks.
-			if out.audioEnabled {
-				if out.cfg.EnforceSpeed {
-					// Wait until there is no more audio buffered. This means that all the audio
-					// has been sent to the kernel and is being played.
-					for sdl.GetQueuedAudioSize(out.audioDev) > uint32(len(f.audio)*2) {
-						time.Sleep(100 * time.Microsecond)
-					}
-					out.renderAudio(f.audio)
-				} else {
-					// If speed is not enforced, we want to avoid queueing too much audio
-					// as it would desync from video. So send audio only if
-					// there's less than a frame's worth in the buffer.
-					if sdl.GetQueuedAudioSize(out.audioDev) < uint32(len(f.audio)*2) {
-						out.renderAudio(f.audio)
-					}
-				}
-			} else {
+			// When throttling in background, we just sleep to run at the
+			// reduced frame rate.
+			if out.throttled() {
+				time.Sleep(time.Second / kBackgroundThrottleFps)
+			} else if !out.audioEnabled {
 				// If there's no audio, enforce speed using timers. We save the time at which
 				// we rendered each frame in the last second, so that we sleep only averaging
 				// the frame rate over a window of one second (it's smoother).
-				if out.cfg.EnforceSpeed {
+				// When pacing on vsync, Present() already blocked for us.
+				if out.cfg.EnforceSpeed && out.cfg.Pacing != PacingVsync {
 					since := time.Since(out.fpsticks[out.fpsticksidx])
 					if since < time.Second {
 						time.Sleep(time.Second - since)
@@ -244,12 +412,90 @@ func (out *Output) render() {
 }
 
 func (out *Output) renderVideo(video gfx.Buffer) {
-	out.frame.Update(nil, video.Pointer(), out.cfg.Width*4)
+	if out.filter.Len() > 0 {
+		video = out.filter.Apply(video)
+	}
+	out.frame.Update(nil, video.Pointer(), video.Width*4)
 	out.renderer.Clear()
-	out.renderer.Copy(out.frame, nil, nil)
+	if out.cfg.Rotation == 0 {
+		out.renderer.Copy(out.frame, nil, nil)
+	} else {
+		// The destination rect is specified before rotation (which happens
+		// around its center), so center it within the logical area.
+		lw, lh := out.cfg.logicalSize()
+		dst := sdl.Rect{
+			X: int32((lw - out.cfg.Width) / 2),
+			Y: int32((lh - out.cfg.Height) / 2),
+			W: int32(out.cfg.Width),
+			H: int32(out.cfg.Height),
+		}
+		out.renderer.CopyEx(out.frame, nil, &dst, float64(out.cfg.Rotation), nil, sdl.FLIP_NONE)
+	}
 	out.renderer.Present()
 }
 
+// Queue the audio of a frame, enforcing the emulation speed
+func (out *Output) paceAudio(audio AudioBuffer) {
+	queued := sdl.GetQueuedAudioSize(out.audioDev)
+	target := uint32(len(audio) * 2 * kAudioTargetFrames)
+
+	switch out.cfg.Pacing {
+	case PacingVsync:
+		// Video is clocked by the display, so we can't wait on audio.
+		// Stretch or shrink the audio a bit to keep the queue around the
+		// target level.
+		drift := (float64(target) - float64(queued)) / float64(target) * kAudioMaxDrift
+		if drift > kAudioMaxDrift {
+			drift = kAudioMaxDrift
+		} else if drift < -kAudioMaxDrift {
+			drift = -kAudioMaxDrift
+		}
+		out.renderAudio(out.resampleAudio(audio, 1+drift))
+
+	default:
+		// Sleep exactly for the time needed to play the audio in excess
+		// of the target. If we're late (queue below target), don't wait
+		// at all, so that we catch up.
+		if queued > target {
+			bps := out.cfg.AudioFrequency * out.cfg.AudioChannels * 2
+			time.Sleep(time.Duration(queued-target) * time.Second / time.Duration(bps))
+		}
+		out.renderAudio(audio)
+	}
+}
+
+// Resample the audio of a frame by the specified ratio, using linear
+// interpolation.
+func (out *Output) resampleAudio(audio AudioBuffer, ratio float64) AudioBuffer {
+	nch := out.cfg.AudioChannels
+	nin := len(audio) / nch
+	nout := int(float64(nin)*ratio + 0.5)
+	if nin < 2 || nout == nin {
+		return audio
+	}
+
+	if cap(out.resampled) < nout*nch {
+		out.resampled = make(AudioBuffer, nout*nch)
+	}
+	res := out.resampled[:nout*nch]
+
+	step := float64(nin-1) / float64(nout-1)
+	for i := 0; i < nout; i++ {
+		pos := float64(i) * step
+		idx := int(pos)
+		if idx >= nin-1 {
+			idx = nin - 2
+		}
+		frac := pos - float64(idx)
+		for c := 0; c < nch; c++ {
+			s0 := float64(audio[idx*nch+c])
+			s1 := float64(audio[(idx+1)*nch+c])
+			res[i*nch+c] = int16(s0 + (s1-s0)*frac)
+		}
+	}
+	return res
+}
+
 func (out *Output) renderAudio(audio AudioBuffer) {
 	buf := (*[100000]uint8)(unsafe.Pointer(&audio[0]))
 	sdl.QueueAudio(out.audioDev, (*buf)[:len(audio)*2])
@@ -267,6 +513,12 @@ func (out *Output) GetMouseState() (int, int, MouseButtons) {
 	return out.mouse.x, out.mouse.y, out.mouse.buttons
 }
 
+// MouseWheel returns the number of steps the mouse wheel was scrolled since
+// the last call (positive: away from the user).
+func (out *Output) MouseWheel() int {
+	return int(atomic.SwapInt32(&out.mouse.wheel, 0))
+}
+
 var kstate []uint8
 var kstateOnce sync.Once
 
@@ -280,31 +532,38 @@ func GetKeyboardState() []uint8 {
 }
 
 func (out *Output) poll() {
+	if out.cfg.Recover != nil {
+		defer out.cfg.Recover()
+	}
 	for !out.quit {
 		time.Sleep(16 * time.Millisecond)
 
 		sdl.Do(func() {
-			x, y, state := sdl.GetMouseState()
+			// Without a window (audio-only output), there's no mouse. When
+			// the pointer is within the touch window, the mouse state is
+			// reported by the touch window instead.
+			if out.touch != nil {
+				out.touch.poll()
+			}
+			if out.videoEnabled && (out.touch == nil || sdl.GetMouseFocus() == out.screen) {
+				wx, wy, state := sdl.GetMouseState()
+				x, y := out.windowToFrame(int(wx), int(wy))
 
-			// Scale back to logical size
-			w, h := out.screen.GetSize()
-			x = x * int32(out.cfg.Width) / w
-			y = y * int32(out.cfg.Height) / h
+				var buttons MouseButtons
+				if state&sdl.BUTTON_LEFT != 0 {
+					buttons |= MouseButtonLeft
+				}
+				if state&sdl.BUTTON_RIGHT != 0 {
+					buttons |= MouseButtonRight
+				}
+				if state&sdl.BUTTON_MIDDLE != 0 {
+					buttons |= MouseButtonMiddle
+				}
 
-			var buttons MouseButtons
-			if state&sdl.BUTTON_LEFT != 0 {
-				buttons |= MouseButtonLeft
-			}
-			if state&sdl.BUTTON_RIGHT != 0 {
-				buttons |= MouseButtonRight
+				out.mouse.x = x
+				out.mouse.y = y
+				out.mouse.buttons = buttons
 			}
-			if state&sdl.BUTTON_MIDDLE != 0 {
-				buttons |= MouseButtonMiddle
-			}
-
-			out.mouse.x = int(x)
-			out.mouse.y = int(y)
-			out.mouse.buttons = buttons
 
 			for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 				switch t := event.(type) {
@@ -316,28 +575,143 @@ func (out *Output) poll() {
 						out.quit = true
 						return
 					}
+				case *sdl.WindowEvent:
+					switch t.Event {
+					case sdl.WINDOWEVENT_FOCUS_LOST:
+						atomic.StoreInt32(&out.unfocused, 1)
+						if out.cfg.Background != BackgroundRun && out.audioEnabled {
+							// Mute immediately, dropping the audio still queued
+							sdl.ClearQueuedAudio(out.audioDev)
+						}
+					case sdl.WINDOWEVENT_FOCUS_GAINED:
+						atomic.StoreInt32(&out.unfocused, 0)
+					}
+				case *sdl.TouchFingerEvent:
+					if out.touch != nil {
+						out.touch.finger(t)
+					}
+				case *sdl.MouseWheelEvent:
+					y := t.Y
+					if t.Direction == sdl.MOUSEWHEEL_FLIPPED {
+						y = -y
+					}
+					atomic.AddInt32(&out.mouse.wheel, y)
+				case *sdl.DropEvent:
+					if t.Type == sdl.DROPFILE {
+						select {
+						case out.dropch <- t.File:
+						default:
+						}
+					}
 				}
 			}
 		})
 	}
 }
 
+// Convert window coordinates into framebuffer coordinates, accounting for
+// the letterboxing done by SDL to preserve the aspect ratio, and for the
+// output rotation.
+func (out *Output) windowToFrame(wx, wy int) (int, int) {
+	lw, lh := out.cfg.logicalSize()
+	w, h := out.screen.GetSize()
+	lx, ly := letterbox(wx, wy, int(w), int(h), lw, lh)
+
+	switch out.cfg.Rotation {
+	case 90:
+		return ly, out.cfg.Height - 1 - lx
+	case 180:
+		return out.cfg.Width - 1 - lx, out.cfg.Height - 1 - ly
+	case 270:
+		return out.cfg.Width - 1 - ly, lx
+	default:
+		return lx, ly
+	}
+}
+
+// letterbox converts window coordinates into coordinates within the logical
+// area of a renderer: SDL scales the logical area by the largest factor that
+// fits within the window, and centers it.
+func letterbox(wx, wy, w, h, lw, lh int) (int, int) {
+	scale := float64(w) / float64(lw)
+	if s := float64(h) / float64(lh); s < scale {
+		scale = s
+	}
+	ox := (float64(w) - float64(lw)*scale) / 2
+	oy := (float64(h) - float64(lh)*scale) / 2
+	return int((float64(wx) - ox) / scale), int((float64(wy) - oy) / scale)
+}
+
 func (out *Output) Poll() bool {
 	return !out.quit
 }
 
-func (out *Output) Screenshot(fn string) (gerr error) {
-	sdl.Do(func() {
-		surf, err := sdl.CreateRGBSurfaceFrom(
-			unsafe.Pointer(&out.framebuf[0]),
-			int32(out.cfg.Width), int32(out.cfg.Height), 32, out.cfg.Width*4,
-			0x00000FF, 0x0000FF00, 0x00FF0000, 0)
-		if err != nil {
-			gerr = err
-			return
+// Close shuts down the output: the frames already sent with EndFrame are
+// rendered, and the audio still queued is played (for a limited time) before
+// closing the audio device, so that the last samples are not cut. No frame
+// can be produced after Close.
+func (out *Output) Close() {
+	close(out.framech)
+	<-out.rendered
+	out.quit = true
+
+	if !out.audioEnabled {
+		return
+	}
+	deadline := time.Now().Add(kAudioDrainTimeout)
+	for time.Now().Before(deadline) {
+		var queued uint32
+		sdl.Do(func() { queued = sdl.GetQueuedAudioSize(out.audioDev) })
+		if queued == 0 {
+			break
 		}
-		surf.SaveBMP(fn)
-		surf.Free()
+		time.Sleep(10 * time.Millisecond)
+	}
+	sdl.Do(func() {
+		sdl.CloseAudioDevice(out.audioDev)
+		out.audioEnabled = false
 	})
-	return
+}
+
+// DroppedFile returns the path of a file that was dragged and dropped onto
+// the window since the last call, if any.
+func (out *Output) DroppedFile() (string, bool) {
+	select {
+	case fn := <-out.dropch:
+		return fn, true
+	default:
+		return "", false
+	}
+}
+
+// Paused reports whether emulation should be paused because the window
+// is in background. While paused, the caller should keep calling Poll()
+// (with a small sleep), but not produce new frames.
+func (out *Output) Paused() bool {
+	return out.cfg.Background == BackgroundPause && atomic.LoadInt32(&out.unfocused) != 0
+}
+
+func (out *Output) throttled() bool {
+	return out.cfg.Background == BackgroundThrottle && atomic.LoadInt32(&out.unfocused) != 0
+}
+
+// Screenshot saves the last frame passed to EndFrame as a PNG file, at the
+// native resolution (that is, before filters and rotation are applied).
+func (out *Output) Screenshot(fn string) error {
+	buf := out.framebuf[out.framebufidx]
+	img := image.NewRGBA(image.Rect(0, 0, out.cfg.Width, out.cfg.Height))
+	for i := 0; i < len(buf); i += 4 {
+		copy(img.Pix[i:i+3], buf[i:i+3])
+		img.Pix[i+3] = 0xFF
+	}
+
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
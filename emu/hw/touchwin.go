@@ -0,0 +1,199 @@
+package hw
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"ndsemu/emu/gfx"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// TouchWindowConfig describes the placement of a TouchWindow
+type TouchWindowConfig struct {
+	Width, Height int  // Size of the screen shown in the window, in pixels
+	WinW, WinH    int  // Size of the window (0: twice the screen size)
+	X, Y          int  // Position of the window (if Positioned)
+	Positioned    bool // False to let the window manager place the window
+}
+
+// ParseTouchWindowGeometry parses the geometry of a touch window, in the
+// form "WxH[+X+Y]" (size and optional position of the window, in pixels).
+// An empty string selects the default size, placed by the window manager.
+func ParseTouchWindowGeometry(spec string) (cfg TouchWindowConfig, err error) {
+	if spec == "" {
+		return
+	}
+	invalid := fmt.Errorf("invalid touch window geometry: %q (use WxH or WxH+X+Y)", spec)
+	parts := strings.Split(spec, "+")
+	if len(parts) != 1 && len(parts) != 3 {
+		return cfg, invalid
+	}
+	size := strings.Split(parts[0], "x")
+	if len(size) != 2 {
+		return cfg, invalid
+	}
+	if cfg.WinW, err = strconv.Atoi(size[0]); err != nil || cfg.WinW <= 0 {
+		return cfg, invalid
+	}
+	if cfg.WinH, err = strconv.Atoi(size[1]); err != nil || cfg.WinH <= 0 {
+		return cfg, invalid
+	}
+	if len(parts) == 3 {
+		if cfg.X, err = strconv.Atoi(parts[1]); err != nil {
+			return cfg, invalid
+		}
+		if cfg.Y, err = strconv.Atoi(parts[2]); err != nil {
+			return cfg, invalid
+		}
+		cfg.Positioned = true
+	}
+	return cfg, nil
+}
+
+// TouchWindow is a secondary borderless window that shows a single screen,
+// meant to be placed on a touchscreen monitor (or onto the area mapped to a
+// drawing tablet): the pointer within the window maps to the whole screen,
+// independently of the scaling and layout of the main window. Touch
+// (finger) events also report the pressure of the contact.
+//
+// The window is created by an Output, which polls its events.
+type TouchWindow struct {
+	out      *Output
+	cfg      TouchWindowConfig
+	window   *sdl.Window
+	renderer *sdl.Renderer
+	tex      *sdl.Texture
+	buf      []byte
+
+	mu       sync.Mutex
+	x, y     int
+	down     bool
+	pressure float32 // pressure of the last finger contact (or -1)
+}
+
+// NewTouchWindow opens a TouchWindow. Only one touch window per Output
+// is supported.
+func (out *Output) NewTouchWindow(cfg TouchWindowConfig) (tw *TouchWindow, err error) {
+	if cfg.WinW == 0 || cfg.WinH == 0 {
+		cfg.WinW, cfg.WinH = cfg.Width*2, cfg.Height*2
+	}
+	tw = &TouchWindow{
+		out:      out,
+		cfg:      cfg,
+		buf:      make([]byte, cfg.Width*cfg.Height*4),
+		pressure: -1,
+	}
+
+	sdl.Do(func() {
+		x, y := int32(sdl.WINDOWPOS_UNDEFINED), int32(sdl.WINDOWPOS_UNDEFINED)
+		if cfg.Positioned {
+			x, y = int32(cfg.X), int32(cfg.Y)
+		}
+		tw.window, err = sdl.CreateWindow(out.cfg.Title+" - touch", x, y,
+			int32(cfg.WinW), int32(cfg.WinH), sdl.WINDOW_BORDERLESS|sdl.WINDOW_RESIZABLE)
+		if err != nil {
+			return
+		}
+		if tw.renderer, err = sdl.CreateRenderer(tw.window, -1, 0); err != nil {
+			return
+		}
+		tw.renderer.SetLogicalSize(int32(cfg.Width), int32(cfg.Height))
+		tw.tex, err = tw.renderer.CreateTexture(
+			sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING,
+			int32(cfg.Width), int32(cfg.Height))
+		if err == nil {
+			out.touch = tw
+		}
+	})
+	if err != nil {
+		tw.Close()
+		return nil, err
+	}
+	return tw, nil
+}
+
+// Present shows a frame of the screen in the window. The screen is copied,
+// so the caller can reuse the buffer.
+func (tw *TouchWindow) Present(screen gfx.Buffer) {
+	pitch := tw.cfg.Width * 4
+	for y := 0; y < tw.cfg.Height && y < screen.Height; y++ {
+		copy(tw.buf[y*pitch:(y+1)*pitch], screen.LineAsSlice(y))
+	}
+	sdl.Do(func() {
+		tw.tex.Update(nil, tw.buf, pitch)
+		tw.renderer.Clear()
+		tw.renderer.Copy(tw.tex, nil, nil)
+		tw.renderer.Present()
+	})
+}
+
+// State returns the state of the pointer within the window, in screen
+// coordinates. The pressure is in the range 0-1, or -1 if the input device
+// doesn't report it (eg: mouse or tablet).
+func (tw *TouchWindow) State() (x, y int, down bool, pressure float32) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.x, tw.y, tw.down, tw.pressure
+}
+
+func (tw *TouchWindow) Close() {
+	sdl.Do(func() {
+		if tw.out.touch == tw {
+			tw.out.touch = nil
+		}
+		if tw.tex != nil {
+			tw.tex.Destroy()
+		}
+		if tw.renderer != nil {
+			tw.renderer.Destroy()
+		}
+		if tw.window != nil {
+			tw.window.Destroy()
+		}
+	})
+}
+
+// poll updates the pointer state, if the mouse (or the emulated mouse of a
+// touch contact, or a tablet) is within the window. It must be called
+// within sdl.Do.
+func (tw *TouchWindow) poll() {
+	if sdl.GetMouseFocus() != tw.window {
+		tw.mu.Lock()
+		tw.down = false
+		tw.mu.Unlock()
+		return
+	}
+	wx, wy, state := sdl.GetMouseState()
+	w, h := tw.window.GetSize()
+	x, y := letterbox(int(wx), int(wy), int(w), int(h), tw.cfg.Width, tw.cfg.Height)
+	if x < 0 {
+		x = 0
+	} else if x >= tw.cfg.Width {
+		x = tw.cfg.Width - 1
+	}
+	if y < 0 {
+		y = 0
+	} else if y >= tw.cfg.Height {
+		y = tw.cfg.Height - 1
+	}
+
+	tw.mu.Lock()
+	tw.x, tw.y = x, y
+	tw.down = state&sdl.BUTTON_LEFT != 0
+	tw.mu.Unlock()
+}
+
+// finger records the pressure of a touch contact. SDL also emulates mouse
+// events for touches, which are used for the position (see poll).
+func (tw *TouchWindow) finger(ev *sdl.TouchFingerEvent) {
+	tw.mu.Lock()
+	if ev.Type == sdl.FINGERUP {
+		tw.pressure = -1
+	} else {
+		tw.pressure = ev.Pressure
+	}
+	tw.mu.Unlock()
+}
@@ -0,0 +1,126 @@
+package hwio
+
+import (
+	"fmt"
+)
+
+type bankRange struct {
+	begin, end uint32
+}
+
+// BankSwitch multiplexes multiple alternative register banks over the same
+// address window, exposing only one of them at a time. This is useful for
+// hardware where the register layout visible at some addresses depends on
+// the state of a control register (eg: a window that can be owned by either
+// CPU, or a mirrored layout that is selected at runtime).
+//
+// Each alternative is a private Table which is populated at initialization
+// time through MapBank/MapMem, using the same absolute addresses at which the
+// window is visible on the bus. The BankSwitch is then mapped into the bus
+// with Table.MapBankSwitch, which covers all the addresses used by any of the
+// alternatives. After that, changing the active alternative with Switch is
+// just a pointer swap, and doesn't require any bus remapping.
+//
+// An alternative in which nothing is mapped can be used to make the whole
+// window unmapped; accesses will be reported as unmapped, with the name of
+// the BankSwitch.
+type BankSwitch struct {
+	Name string
+
+	banks  []*Table
+	ranges []bankRange
+	active *Table
+	cur    int
+}
+
+// NewBankSwitch creates a BankSwitch with the specified number of
+// alternatives. The first alternative is initially active.
+func NewBankSwitch(name string, nalt int) *BankSwitch {
+	s := &BankSwitch{Name: name}
+	for i := 0; i < nalt; i++ {
+		s.banks = append(s.banks, NewTable(fmt.Sprintf("%s/%d", name, i)))
+	}
+	s.active = s.banks[0]
+	return s
+}
+
+func (s *BankSwitch) addRange(begin, size uint32) {
+	end := begin + size - 1
+	for i := range s.ranges {
+		r := &s.ranges[i]
+		if begin <= r.end+1 && end+1 >= r.begin {
+			if begin < r.begin {
+				r.begin = begin
+			}
+			if end > r.end {
+				r.end = end
+			}
+			return
+		}
+	}
+	s.ranges = append(s.ranges, bankRange{begin, end})
+}
+
+// MapBank maps a register bank (see Table.MapBank) into the specified
+// alternative. It must be called before the BankSwitch is mapped into
+// a bus.
+func (s *BankSwitch) MapBank(alt int, addr uint32, bank interface{}, bankNum int) {
+	regs, err := bankGetRegs(bank, bankNum)
+	if err != nil {
+		panic(err)
+	}
+	s.banks[alt].MapBank(addr, bank, bankNum)
+	for _, reg := range regs {
+		s.addRange(addr+reg.offset, regSize(reg.regPtr))
+	}
+}
+
+// MapMem maps a memory area into the specified alternative. It must be
+// called before the BankSwitch is mapped into a bus.
+func (s *BankSwitch) MapMem(alt int, addr uint32, mem *Mem) {
+	s.banks[alt].MapMem(addr, mem)
+	s.addRange(addr, uint32(mem.VSize))
+}
+
+// Switch activates the specified alternative
+func (s *BankSwitch) Switch(alt int) {
+	s.active = s.banks[alt]
+	s.cur = alt
+}
+
+// Active returns the index of the currently active alternative
+func (s *BankSwitch) Active() int {
+	return s.cur
+}
+
+func (s *BankSwitch) Read8(addr uint32) uint8          { return s.active.Read8(addr) }
+func (s *BankSwitch) Write8(addr uint32, val uint8)    { s.active.Write8(addr, val) }
+func (s *BankSwitch) Read16(addr uint32) uint16        { return s.active.Read16(addr) }
+func (s *BankSwitch) Write16(addr uint32, val uint16)  { s.active.Write16(addr, val) }
+func (s *BankSwitch) Read32(addr uint32) uint32        { return s.active.Read32(addr) }
+func (s *BankSwitch) Write32(addr uint32, val uint32)  { s.active.Write32(addr, val) }
+func (s *BankSwitch) FetchPointer(addr uint32) []uint8 { return s.active.FetchPointer(addr) }
+
+// MapBankSwitch maps a BankSwitch into the table. All addresses used by
+// any of its alternatives are routed to the BankSwitch, that forwards them
+// to the active alternative.
+//
+// 16-bit and 32-bit accesses are routed for all the halfwords/words that
+// are partially covered by an alternative, so those must not be shared with
+// other registers mapped outside of the BankSwitch.
+func (t *Table) MapBankSwitch(s *BankSwitch) {
+	for _, r := range s.ranges {
+		t.mapBus8(r.begin, r.end-r.begin+1, s, false)
+		t.mapBus16(r.begin&^1, (r.end|1)-(r.begin&^1)+1, s, false)
+		t.mapBus32(r.begin&^3, (r.end|3)-(r.begin&^3)+1, s, false)
+	}
+}
+
+// UnmapBankSwitch removes a BankSwitch previously mapped with MapBankSwitch
+func (t *Table) UnmapBankSwitch(s *BankSwitch) {
+	for _, r := range s.ranges {
+		t.table8.RemoveRange(r.begin, r.end)
+		t.table16.RemoveRange(r.begin&^1, r.end|1)
+		t.table32.RemoveRange(r.begin&^3, r.end|3)
+	}
+}
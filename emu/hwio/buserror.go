@@ -0,0 +1,56 @@
+package hwio
+
+import "fmt"
+
+type BusErrorKind int
+
+const (
+	BusErrorUnmapped BusErrorKind = iota // access to an address where nothing is mapped
+	BusErrorReadOnly                     // write to read-only memory
+)
+
+func (k BusErrorKind) String() string {
+	switch k {
+	case BusErrorUnmapped:
+		return "unmapped access"
+	case BusErrorReadOnly:
+		return "write to ROM"
+	default:
+		return fmt.Sprintf("BusErrorKind(%d)", int(k))
+	}
+}
+
+// BusAccessor is implemented by objects that can tell who is currently
+// accessing a bus (typically, the scheduler knows which CPU is running).
+type BusAccessor interface {
+	BusAccessor() (name string, pc uint32)
+}
+
+// BusError describes an invalid access performed on a Table
+type BusError struct {
+	Kind  BusErrorKind
+	Bus   string // name of the table
+	Addr  uint32
+	Size  int // access size in bits
+	Write bool
+	Val   uint32 // value being written (only for writes)
+
+	Accessor string // who performed the access (if known)
+	PC       uint32 // program counter of the accessor
+}
+
+func (e *BusError) Error() string {
+	dir := "read"
+	if e.Write {
+		dir = "write"
+	}
+	msg := fmt.Sprintf("%s: %s%d at %08x", e.Kind, dir, e.Size, e.Addr)
+	if e.Write {
+		msg += fmt.Sprintf(" (val=%0*x)", e.Size/4, e.Val)
+	}
+	msg += " on " + e.Bus
+	if e.Accessor != "" {
+		msg += fmt.Sprintf(" by %s (pc=%08x)", e.Accessor, e.PC)
+	}
+	return msg
+}
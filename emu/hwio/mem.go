@@ -166,6 +166,36 @@ func (m *memByteSwappedLE) Write32(addr uint32, val uint32) {
 	(*memUnalignedLE)(m).Write32(addr&^3, val)
 }
 
+// Access to memory where writes can be dynamically discarded by a filter
+// function (see Mem.WriteFilter). This is kept separate from memUnalignedLE
+// so that the fast-path for normal memory is not affected.
+type memFilteredLE struct {
+	mem    *memUnalignedLE
+	filter func(uint32, int) bool
+}
+
+func (m *memFilteredLE) Read8(addr uint32) uint8   { return m.mem.Read8(addr) }
+func (m *memFilteredLE) Read16(addr uint32) uint16 { return m.mem.Read16(addr) }
+func (m *memFilteredLE) Read32(addr uint32) uint32 { return m.mem.Read32(addr) }
+
+func (m *memFilteredLE) Write8(addr uint32, val uint8) {
+	if m.filter(addr, 1) {
+		m.mem.Write8(addr, val)
+	}
+}
+
+func (m *memFilteredLE) Write16(addr uint32, val uint16) {
+	if m.filter(addr, 2) {
+		m.mem.Write16(addr, val)
+	}
+}
+
+func (m *memFilteredLE) Write32(addr uint32, val uint32) {
+	if m.filter(addr, 4) {
+		m.mem.Write32(addr, val)
+	}
+}
+
 type MemFlags int
 
 const (
@@ -198,6 +228,19 @@ type Mem struct {
 	VSize   int               // virtual size of the memory (can be bigger than physical size)
 	Flags   MemFlags          // flags determining how the memory can be accessed
 	WriteCb func(uint32, int) // optional write callback (receives full address and number of bytes written)
+
+	// Optional filter invoked before each write (with the full address and
+	// the number of bytes): if it returns false, the write is discarded.
+	// It is only honored for 8-bit and unaligned 16/32-bit accesses.
+	WriteFilter func(uint32, int) bool
+}
+
+// filtered wraps the memory adaptor with the write filter, if any
+func (mem *Mem) filtered(smem *memUnalignedLE) BankIO {
+	if mem.WriteFilter != nil {
+		return &memFilteredLE{smem, mem.WriteFilter}
+	}
+	return smem
 }
 
 func (mem *Mem) roFlag(robit MemFlags) uint8 {
@@ -217,14 +260,14 @@ func (mem *Mem) BankIO8() BankIO8 {
 		return nil
 	}
 	roflag := mem.roFlag(MemFlag8ReadOnly)
-	return newMemUnalignedLE(mem.Data, mem.WriteCb, roflag)
+	return mem.filtered(newMemUnalignedLE(mem.Data, mem.WriteCb, roflag))
 }
 
 func (mem *Mem) BankIO16() BankIO16 {
 	roflag := mem.roFlag(MemFlag16ReadOnly)
 	smem := newMemUnalignedLE(mem.Data, mem.WriteCb, roflag)
 	if mem.Flags&MemFlag16Unaligned != 0 {
-		return smem
+		return mem.filtered(smem)
 	}
 	if mem.Flags&MemFlag16ForceAlign != 0 {
 		return (*memForceAlignLE)(smem)
@@ -239,7 +282,7 @@ func (mem *Mem) BankIO32() BankIO32 {
 	roflag := mem.roFlag(MemFlag32ReadOnly)
 	smem := newMemUnalignedLE(mem.Data, mem.WriteCb, roflag)
 	if mem.Flags&MemFlag32Unaligned != 0 {
-		return smem
+		return mem.filtered(smem)
 	}
 	if mem.Flags&MemFlag32ForceAlign != 0 {
 		return (*memForceAlignLE)(smem)
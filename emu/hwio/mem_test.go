@@ -63,6 +63,30 @@ func TestMemReadonly(t *testing.T) {
 	}
 }
 
+func TestMemWriteFilter(t *testing.T) {
+	allow := false
+	buf := Mem{
+		Data:        make([]byte, 0x200),
+		Flags:       MemFlag8 | MemFlag16Unaligned | MemFlag32Unaligned,
+		WriteFilter: func(addr uint32, size int) bool { return allow },
+	}
+
+	buf.BankIO8().Write8(0x0, 0xFF)
+	buf.BankIO16().Write16(0x2, 0xFFFF)
+	buf.BankIO32().Write32(0x4, 0xFFFFFFFF)
+	for i := 0; i < 8; i++ {
+		if buf.Data[i] != 0 {
+			t.Errorf("filtered data written at offset %d", i)
+		}
+	}
+
+	allow = true
+	buf.BankIO32().Write32(0x4, 0x12345678)
+	if val := buf.BankIO32().Read32(0x4); val != 0x12345678 {
+		t.Errorf("invalid rd32 after write, got:%x, want:%x", val, 0x12345678)
+	}
+}
+
 func TestMemAlign16(t *testing.T) {
 	var buf Mem
 	buf.Data = make([]byte, 0x200)
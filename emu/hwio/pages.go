@@ -0,0 +1,131 @@
+package hwio
+
+import "unsafe"
+
+// Direct page tables: plain RAM/ROM mapped into the low part of the address
+// space (where all the memories of the NDS live) is also recorded in two flat
+// arrays of pointers (one for reads, one for writes), indexed by page number.
+// An access to a page found in the table is a single array lookup followed by
+// a load/store, without interface dispatch, so that it can be fully inlined
+// into the caller; everything else (I/O registers, memories with callbacks,
+// write filters, non-linear access modes, ...) goes through the radix trees.
+const (
+	cPageShift = 12
+	cPageSize  = 1 << cPageShift
+	cPageMask  = cPageSize - 1
+	cPageLimit = 0x10000000 // addresses covered by the page tables
+	cNumPages  = cPageLimit >> cPageShift
+)
+
+type pageTable [cNumPages]unsafe.Pointer
+
+// mapPages records a memory in the page tables, if it is eligible. Only the
+// pages fully covered by the mapping are recorded; the rest of the range is
+// still served by the radix trees.
+func (t *Table) mapPages(addr uint32, mem *Mem) {
+	const linear = MemFlag8 | MemFlag16Unaligned | MemFlag32Unaligned
+	if mem.Flags&linear != linear || mem.WriteFilter != nil || len(mem.Data) < cPageSize {
+		return
+	}
+	writable := mem.Flags&MemFlagReadOnly == 0 && mem.WriteCb == nil
+
+	begin := (uint64(addr) + cPageMask) &^ cPageMask
+	end := (uint64(addr) + uint64(mem.VSize)) &^ cPageMask
+	if end > cPageLimit {
+		end = cPageLimit
+	}
+	mask := uint32(len(mem.Data) - 1)
+	for pa := begin; pa < end; pa += cPageSize {
+		ptr := unsafe.Pointer(&mem.Data[uint32(pa)&mask])
+		t.rpages[pa>>cPageShift] = ptr
+		if writable {
+			t.wpages[pa>>cPageShift] = ptr
+		}
+	}
+}
+
+// unmapPages removes all the pages touched by the specified range from the
+// page tables.
+func (t *Table) unmapPages(begin uint32, end uint32) {
+	if begin >= cPageLimit {
+		return
+	}
+	if end >= cPageLimit {
+		end = cPageLimit - 1
+	}
+	for p := begin >> cPageShift; p <= end>>cPageShift; p++ {
+		t.rpages[p] = nil
+		t.wpages[p] = nil
+	}
+}
+
+func (t *Table) readPage(addr uint32) unsafe.Pointer {
+	if addr < cPageLimit {
+		if p := t.rpages[addr>>cPageShift]; p != nil {
+			return unsafe.Pointer(uintptr(p) + uintptr(addr&cPageMask))
+		}
+	}
+	return nil
+}
+
+func (t *Table) writePage(addr uint32) unsafe.Pointer {
+	if addr < cPageLimit {
+		if p := t.wpages[addr>>cPageShift]; p != nil {
+			return unsafe.Pointer(uintptr(p) + uintptr(addr&cPageMask))
+		}
+	}
+	return nil
+}
+
+// FastRead8 reads from the page tables only: it returns false if the address
+// is not mapped there, in which case the caller must fall back to Read8.
+// The FastRead*/FastWrite* functions are small enough to be inlined, so that
+// hot callers (like the CPU cores) can serve most memory accesses without
+// any function call.
+func (t *Table) FastRead8(addr uint32) (uint8, bool) {
+	if p := t.readPage(addr); p != nil {
+		return *(*uint8)(p), true
+	}
+	return 0, false
+}
+
+func (t *Table) FastRead16(addr uint32) (uint16, bool) {
+	if p := t.readPage(addr); p != nil {
+		return *(*uint16)(p), true
+	}
+	return 0, false
+}
+
+func (t *Table) FastRead32(addr uint32) (uint32, bool) {
+	if p := t.readPage(addr); p != nil {
+		return *(*uint32)(p), true
+	}
+	return 0, false
+}
+
+// FastWrite8 writes through the page tables only: it returns false if the
+// address is not mapped there (or it is mapped read-only, or with a write
+// callback), in which case the caller must fall back to Write8.
+func (t *Table) FastWrite8(addr uint32, val uint8) bool {
+	if p := t.writePage(addr); p != nil {
+		*(*uint8)(p) = val
+		return true
+	}
+	return false
+}
+
+func (t *Table) FastWrite16(addr uint32, val uint16) bool {
+	if p := t.writePage(addr); p != nil {
+		*(*uint16)(p) = val
+		return true
+	}
+	return false
+}
+
+func (t *Table) FastWrite32(addr uint32, val uint32) bool {
+	if p := t.writePage(addr); p != nil {
+		*(*uint32)(p) = val
+		return true
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package hwio
+
+// Peek and poke are debugging accessors: they read and write the bus without
+// emulating the access, so that debuggers and external tools can inspect the
+// whole address space without perturbing emulation. In particular:
+//
+//   * read callbacks are never invoked, so FIFOs are not popped, and
+//     registers computed at read time (eg: ADC values, counters) return
+//     their last stored value;
+//   * write callbacks of registers are not invoked, and read-only bits can be
+//     modified (the value is stored as-is);
+//   * writes to read-only memory are allowed (eg: to patch a ROM image);
+//   * unmapped addresses read as zero, and are not reported as bus errors.
+//
+// Write callbacks of memory areas are still invoked, as they are used to
+// keep caches in sync with memory contents, not to emulate side effects.
+
+// peekByte reads a single byte without side effects, and reports whether
+// the address was mapped. All the access-size tables are looked up, as some
+// areas (eg: wifi RAM) are not accessible through 8-bit accesses.
+func (t *Table) peekByte(addr uint32) (uint8, bool) {
+	for _, tree := range [...]*radixTree{&t.table8, &t.table16, &t.table32} {
+		switch io := tree.Search(addr).(type) {
+		case *memUnalignedLE:
+			return io.Read8(addr), true
+		case *memFilteredLE:
+			return io.mem.Read8(addr), true
+		case *memForceAlignLE:
+			return (*memUnalignedLE)(io).Read8(addr), true
+		case *memByteSwappedLE:
+			return (*memUnalignedLE)(io).Read8(addr), true
+		case *Reg8:
+			return io.Value, true
+		case *Reg16:
+			return uint8(io.Value >> (8 * (addr & 1))), true
+		case *Reg32:
+			return uint8(io.Value >> (8 * (addr & 3))), true
+		case *Reg64:
+			return uint8(io.Value >> (8 * (addr & 7))), true
+		case *BankSwitch:
+			return io.active.peekByte(addr)
+		}
+	}
+	return 0, false
+}
+
+func (t *Table) pokeByte(addr uint32, val uint8) bool {
+	for _, tree := range [...]*radixTree{&t.table8, &t.table16, &t.table32} {
+		var mem *memUnalignedLE
+		switch io := tree.Search(addr).(type) {
+		case *memUnalignedLE:
+			mem = io
+		case *memFilteredLE:
+			mem = io.mem
+		case *memForceAlignLE:
+			mem = (*memUnalignedLE)(io)
+		case *memByteSwappedLE:
+			mem = (*memUnalignedLE)(io)
+		case *Reg8:
+			io.Value = val
+			return true
+		case *Reg16:
+			sh := 8 * (addr & 1)
+			io.Value = io.Value&^(0xFF<<sh) | uint16(val)<<sh
+			return true
+		case *Reg32:
+			sh := 8 * (addr & 3)
+			io.Value = io.Value&^(0xFF<<sh) | uint32(val)<<sh
+			return true
+		case *Reg64:
+			sh := 8 * (addr & 7)
+			io.Value = io.Value&^(0xFF<<sh) | uint64(val)<<sh
+			return true
+		case *BankSwitch:
+			return io.active.pokeByte(addr, val)
+		default:
+			continue
+		}
+
+		mem.FetchPointer(addr)[0] = val
+		if mem.wcb != nil {
+			mem.wcb(addr, 1)
+		}
+		return true
+	}
+	return false
+}
+
+// Peek8 reads a byte without side effects (see above)
+func (t *Table) Peek8(addr uint32) uint8 {
+	val, _ := t.peekByte(addr)
+	return val
+}
+
+// Peek16 reads a little-endian halfword without side effects (see above)
+func (t *Table) Peek16(addr uint32) uint16 {
+	return uint16(t.Peek8(addr)) | uint16(t.Peek8(addr+1))<<8
+}
+
+// Peek32 reads a little-endian word without side effects (see above)
+func (t *Table) Peek32(addr uint32) uint32 {
+	return uint32(t.Peek16(addr)) | uint32(t.Peek16(addr+2))<<16
+}
+
+// Poke8 writes a byte without side effects (see above). It returns false
+// if the address is unmapped.
+func (t *Table) Poke8(addr uint32, val uint8) bool {
+	return t.pokeByte(addr, val)
+}
+
+// Poke16 writes a little-endian halfword without side effects (see above)
+func (t *Table) Poke16(addr uint32, val uint16) bool {
+	ok1 := t.Poke8(addr, uint8(val))
+	ok2 := t.Poke8(addr+1, uint8(val>>8))
+	return ok1 && ok2
+}
+
+// Poke32 writes a little-endian word without side effects (see above)
+func (t *Table) Poke32(addr uint32, val uint32) bool {
+	ok1 := t.Poke16(addr, uint16(val))
+	ok2 := t.Poke16(addr+2, uint16(val>>16))
+	return ok1 && ok2
+}
+
+// ReadSensitive reports whether reading the specified address (with any
+// access size) has side effects, because it hits a register flagged with
+// RegFlagReadSensitive. Tools that need to read I/O registers outside of
+// emulation (debuggers, state snapshots) must use the peek functions for
+// these addresses.
+func (t *Table) ReadSensitive(addr uint32) bool {
+	for _, tree := range [...]*radixTree{&t.table8, &t.table16, &t.table32} {
+		var flags RegFlags
+		switch io := tree.Search(addr).(type) {
+		case *Reg8:
+			flags = io.Flags
+		case *Reg16:
+			flags = io.Flags
+		case *Reg32:
+			flags = io.Flags
+		case *Reg64:
+			flags = io.Flags
+		case *BankSwitch:
+			return io.active.ReadSensitive(addr)
+		}
+		if flags&RegFlagReadSensitive != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,9 @@ package hwio
 
 import (
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -103,6 +105,13 @@ func MustInitRegs(data interface{}) {
 //    writeonly       the register is write-only; any attempt to read from it
 //                    will be ignored and logged as errors.
 //
+//    readsensitive   reading the register has side effects (eg: it pops a
+//                    FIFO); see RegFlagReadSensitive. Reads narrower than the
+//                    register are logged, as each of them triggers the side
+//                    effect.
+//
+// Once all registers are initialized, the layout of the banks is verified
+// (see checkBanks), to catch copy-paste errors in offsets.
 func InitRegs(data interface{}) error {
 	val := reflect.ValueOf(data).Elem()
 
@@ -113,6 +122,9 @@ func InitRegs(data interface{}) error {
 		if tag == "" {
 			continue
 		}
+		if varField.Type == bankSizeType {
+			continue
+		}
 
 		// Set the register name with its name in the structure
 		valueField.FieldByName("Name").SetString(varField.Name)
@@ -257,11 +269,86 @@ func InitRegs(data interface{}) error {
 			}
 			flags |= RegFlagWriteOnly
 		}
+		if rs := tag.Get("readsensitive"); rs != "" {
+			flags |= RegFlagReadSensitive
+		}
 		if flags != 0 {
 			valueField.FieldByName("Flags").SetUint(uint64(flags))
 		}
 	}
 
+	return checkBanks(data)
+}
+
+// BankSize declares the size in bytes of a register bank, so that InitRegs can
+// verify that all the registers of the bank fit into it. It is meant to be
+// used as a blank field, for instance:
+//
+//    _ hwio.BankSize `hwio:"bank=1,size=0x40"`
+//
+type BankSize struct{}
+
+var bankSizeType = reflect.TypeOf(BankSize{})
+
+// checkBanks verifies the layout of the register banks declared in a
+// structure: registers of the same bank must not overlap, and must fit the
+// size of the bank (if declared with BankSize).
+func checkBanks(data interface{}) error {
+	type span struct {
+		name       string
+		begin, end uint64
+	}
+	banks := make(map[int][]span)
+	sizes := make(map[int]uint64)
+
+	val := reflect.ValueOf(data).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		varField := val.Type().Field(i)
+		tag := parseTag(varField.Tag)
+		if tag == "" {
+			continue
+		}
+		bank := 0
+		if sbank := tag.Get("bank"); sbank != "" {
+			b, err := strconv.ParseUint(sbank, 0, 32)
+			if err != nil {
+				return fmt.Errorf("%s: invalid bank: %q", varField.Name, sbank)
+			}
+			bank = int(b)
+		}
+
+		if varField.Type == bankSizeType {
+			size, err := strconv.ParseUint(tag.Get("size"), 0, 32)
+			if err != nil {
+				return fmt.Errorf("invalid size for bank %d: %q", bank, tag.Get("size"))
+			}
+			sizes[bank] = size
+			continue
+		}
+
+		soff := tag.Get("offset")
+		if soff == "" {
+			continue
+		}
+		offset, err := strconv.ParseUint(soff, 0, 32)
+		if err != nil {
+			return fmt.Errorf("%s: invalid offset: %q", varField.Name, soff)
+		}
+		size := uint64(regSize(val.Field(i).Addr().Interface()))
+		banks[bank] = append(banks[bank], span{varField.Name, offset, offset + size})
+	}
+
+	for bank, regs := range banks {
+		sort.Slice(regs, func(i, j int) bool { return regs[i].begin < regs[j].begin })
+		for i, r := range regs {
+			if i > 0 && r.begin < regs[i-1].end {
+				return fmt.Errorf("bank %d: %s (offset 0x%x) overlaps %s", bank, r.name, r.begin, regs[i-1].name)
+			}
+			if size, ok := sizes[bank]; ok && r.end > size {
+				return fmt.Errorf("bank %d: %s (offset 0x%x) exceeds bank size 0x%x", bank, r.name, r.begin, size)
+			}
+		}
+	}
 	return nil
 }
 
@@ -315,3 +402,227 @@ func bankGetRegs(data interface{}, bankNum int) ([]bankRegInfo, error) {
 
 	return regs, nil
 }
+
+// RegInfo describes a register declared in a structure, with its current value
+type RegInfo struct {
+	Name   string
+	Bank   int
+	Offset uint32
+	Size   int // in bits
+	Value  uint64
+}
+
+// RegList returns the current value of all the registers declared in a
+// structure (as initialized by InitRegs) as part of a bank. Values are read
+// directly from the register storage, so read callbacks are not invoked and
+// there are no side effects; for registers whose value is computed by a read
+// callback, the value is the last one stored. Memory areas are skipped.
+func RegList(data interface{}) []RegInfo {
+	val := reflect.ValueOf(data).Elem()
+
+	var regs []RegInfo
+	for i := 0; i < val.NumField(); i++ {
+		valueField := val.Field(i)
+		varField := val.Type().Field(i)
+		tag := parseTag(varField.Tag)
+		if tag == "" || tag.Get("offset") == "" {
+			continue
+		}
+
+		info := RegInfo{Name: varField.Name}
+		offset, _ := strconv.ParseUint(tag.Get("offset"), 0, 32)
+		info.Offset = uint32(offset)
+		if sbank := tag.Get("bank"); sbank != "" {
+			bank, _ := strconv.ParseUint(sbank, 0, 32)
+			info.Bank = int(bank)
+		}
+
+		switch reg := valueField.Addr().Interface().(type) {
+		case *Reg8:
+			info.Size, info.Value = 8, uint64(reg.Value)
+		case *Reg16:
+			info.Size, info.Value = 16, uint64(reg.Value)
+		case *Reg32:
+			info.Size, info.Value = 32, uint64(reg.Value)
+		case *Reg64:
+			info.Size, info.Value = 64, reg.Value
+		default:
+			continue
+		}
+		regs = append(regs, info)
+	}
+	return regs
+}
+
+// RegValues returns the current value of all the registers declared in a
+// structure, indexed by field name. Unlike RegList, registers that are not
+// part of a bank (and are mapped individually) are included too. Like
+// RegList, values are read directly from the register storage.
+func RegValues(data interface{}) map[string]uint64 {
+	val := reflect.ValueOf(data).Elem()
+
+	regs := make(map[string]uint64)
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		name := field.Name
+		switch reg := val.Field(i).Addr().Interface().(type) {
+		case *Reg8:
+			regs[name] = uint64(reg.Value)
+		case *Reg16:
+			regs[name] = uint64(reg.Value)
+		case *Reg32:
+			regs[name] = uint64(reg.Value)
+		case *Reg64:
+			regs[name] = reg.Value
+		}
+	}
+	return regs
+}
+
+// SetRegValues stores values (as returned by RegValues) into the registers
+// declared in a structure. Values are written directly into the register
+// storage: write callbacks are not invoked, and read-only bits are written
+// too. Registers not found in values are left unchanged, and names that
+// don't match any register are ignored.
+func SetRegValues(data interface{}, values map[string]uint64) {
+	val := reflect.ValueOf(data).Elem()
+
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		v, ok := values[field.Name]
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+		switch reg := val.Field(i).Addr().Interface().(type) {
+		case *Reg8:
+			reg.Value = uint8(v)
+		case *Reg16:
+			reg.Value = uint16(v)
+		case *Reg32:
+			reg.Value = uint32(v)
+		case *Reg64:
+			reg.Value = v
+		}
+	}
+}
+
+// RegDesc describes the layout of a register (or memory area) declared in a
+// structure as part of a bank, as configured by InitRegs.
+type RegDesc struct {
+	Name    string
+	Addr    uint32 // absolute address (only for Table.RegMap)
+	Bank    int
+	Offset  uint32
+	Size    int    // in bits (for memory areas: size of the area)
+	RwMask  uint64 // writable bits (zero for memory areas)
+	ReadCb  string // name of the read callback method, if any
+	WriteCb string // name of the write callback method, if any
+	Flags   RegFlags
+	Mem     bool
+}
+
+// RegMap describes all the registers (and memory areas) declared in a
+// structure as part of a bank, sorted by bank and offset.
+func RegMap(data interface{}) []RegDesc {
+	val := reflect.ValueOf(data).Elem()
+
+	var regs []RegDesc
+	for i := 0; i < val.NumField(); i++ {
+		valueField := val.Field(i)
+		varField := val.Type().Field(i)
+		tag := parseTag(varField.Tag)
+		if tag == "" || tag.Get("offset") == "" {
+			continue
+		}
+
+		desc := RegDesc{Name: varField.Name}
+		offset, _ := strconv.ParseUint(tag.Get("offset"), 0, 32)
+		desc.Offset = uint32(offset)
+		if sbank := tag.Get("bank"); sbank != "" {
+			bank, _ := strconv.ParseUint(sbank, 0, 32)
+			desc.Bank = int(bank)
+		}
+		if rcb := tag.Get("rcb"); rcb != "" {
+			if rcb == "true" {
+				rcb = "Read" + strings.ToUpper(varField.Name)
+			}
+			desc.ReadCb = rcb
+		}
+		if wcb := tag.Get("wcb"); wcb != "" {
+			if wcb == "true" {
+				wcb = "Write" + strings.ToUpper(varField.Name)
+			}
+			desc.WriteCb = wcb
+		}
+
+		switch reg := valueField.Addr().Interface().(type) {
+		case *Mem:
+			desc.Size, desc.Mem = reg.VSize*8, true
+		case *Reg8:
+			desc.Size, desc.RwMask, desc.Flags = 8, uint64(^reg.RoMask), reg.Flags
+		case *Reg16:
+			desc.Size, desc.RwMask, desc.Flags = 16, uint64(^reg.RoMask), reg.Flags
+		case *Reg32:
+			desc.Size, desc.RwMask, desc.Flags = 32, uint64(^reg.RoMask), reg.Flags
+		case *Reg64:
+			desc.Size, desc.RwMask, desc.Flags = 64, ^reg.RoMask, reg.Flags
+		default:
+			continue
+		}
+		regs = append(regs, desc)
+	}
+
+	sort.SliceStable(regs, func(i, j int) bool {
+		if regs[i].Bank != regs[j].Bank {
+			return regs[i].Bank < regs[j].Bank
+		}
+		return regs[i].Offset < regs[j].Offset
+	})
+	return regs
+}
+
+// DumpRegMap writes a register map in human-readable format, one register
+// per line: address (or bank and offset), name, size, writable bits,
+// flags and callbacks.
+func DumpRegMap(w io.Writer, regs []RegDesc) {
+	for _, reg := range regs {
+		if reg.Addr != 0 {
+			fmt.Fprintf(w, "%08x  ", reg.Addr)
+		} else {
+			fmt.Fprintf(w, "bank=%d offset=0x%-4x  ", reg.Bank, reg.Offset)
+		}
+		if reg.Mem {
+			fmt.Fprintf(w, "%-16s mem  size=0x%x", reg.Name, reg.Size/8)
+		} else {
+			fmt.Fprintf(w, "%-16s %-4d rwmask=%0*x", reg.Name, reg.Size, reg.Size/4, reg.RwMask)
+		}
+		if reg.Flags&RegFlagReadOnly != 0 {
+			fmt.Fprint(w, " readonly")
+		}
+		if reg.Flags&RegFlagWriteOnly != 0 {
+			fmt.Fprint(w, " writeonly")
+		}
+		if reg.Flags&RegFlagReadSensitive != 0 {
+			fmt.Fprint(w, " readsensitive")
+		}
+		if reg.ReadCb != "" {
+			fmt.Fprintf(w, " rcb=%s", reg.ReadCb)
+		}
+		if reg.WriteCb != "" {
+			fmt.Fprintf(w, " wcb=%s", reg.WriteCb)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// DumpRegs writes the current value of all the registers declared in a
+// structure, in human-readable format (see RegList).
+func DumpRegs(w io.Writer, data interface{}) {
+	for _, reg := range RegList(data) {
+		fmt.Fprintf(w, "  bank=%d offset=0x%-4x %-16s %0*x\n",
+			reg.Bank, reg.Offset, reg.Name, reg.Size/4, reg.Value)
+	}
+}
@@ -130,3 +130,105 @@ func TestValuesTooBig(t *testing.T) {
 		t.Fatal("initregs should fail")
 	}
 }
+
+type testOverlap struct {
+	Reg1 Reg32 `hwio:"offset=0x0"`
+	Reg2 Reg16 `hwio:"offset=0x2"`
+}
+
+type testBankSize struct {
+	_    BankSize `hwio:"bank=1,size=0x4"`
+	Reg1 Reg32    `hwio:"offset=0x4"`
+	Reg2 Reg32    `hwio:"offset=0x4,bank=1"`
+}
+
+func TestCheckBanks(t *testing.T) {
+	if err := InitRegs(&testOverlap{}); err == nil {
+		t.Error("overlapping registers not detected")
+	}
+	if err := InitRegs(&testBankSize{}); err == nil {
+		t.Error("register outside of bank not detected")
+	}
+}
+
+func TestRegMap(t *testing.T) {
+	ts := &test1{}
+	MustInitRegs(ts)
+
+	regs := RegMap(ts)
+	if len(regs) != 2 {
+		t.Fatal("wrong number of regs:", len(regs))
+	}
+	if r := regs[0]; r.Name != "Reg1" || r.Size != 16 || r.RwMask != 0x1 || r.WriteCb != "WriteREG1" || r.ReadCb != "" {
+		t.Errorf("invalid desc: %+v", r)
+	}
+	if r := regs[1]; r.Name != "Reg2" || r.Bank != 1 || r.RwMask != 0xFFFFFFFF || r.ReadCb != "ReadREG2" {
+		t.Errorf("invalid desc: %+v", r)
+	}
+
+	table := NewTable("t1")
+	table.MapBank(0x4000000, ts, 1)
+	if regs := table.RegMap(); len(regs) != 1 || regs[0].Addr != 0x4000444 {
+		t.Errorf("invalid table map: %+v", regs)
+	}
+	table.UnmapBank(0x4000000, ts, 1)
+	if regs := table.RegMap(); len(regs) != 0 {
+		t.Errorf("bank not removed from map: %+v", regs)
+	}
+}
+
+type testReadSensitive struct {
+	Fifo Reg32 `hwio:"offset=0x0,readonly,rcb,readsensitive"`
+	Cnt  Reg16 `hwio:"offset=0x4"`
+	pops int
+}
+
+func (t *testReadSensitive) ReadFIFO(val uint32) uint32 {
+	t.pops++
+	return val
+}
+
+func TestReadSensitive(t *testing.T) {
+	ts := &testReadSensitive{}
+	MustInitRegs(ts)
+	if ts.Fifo.Flags != RegFlagReadOnly|RegFlagReadSensitive {
+		t.Errorf("invalid flags: %x", ts.Fifo.Flags)
+	}
+
+	table := NewTable("t1")
+	table.MapBank(0x4100000, ts, 0)
+	if !table.ReadSensitive(0x4100002) || table.ReadSensitive(0x4100004) {
+		t.Errorf("invalid read-sensitive lookup")
+	}
+	table.Peek32(0x4100000)
+	table.Read32(0x4100000)
+	if ts.pops != 1 {
+		t.Errorf("invalid number of pops: %d", ts.pops)
+	}
+}
+
+func TestRegValues(t *testing.T) {
+	type test3 struct {
+		Reg1 Reg16 `hwio:"offset=0x0,rwmask=0x1"`
+		Reg2 Reg8  `hwio:"readonly"`
+		Reg3 Reg64
+		foo  int
+	}
+	ts := &test3{}
+	MustInitRegs(ts)
+	ts.Reg1.Value, ts.Reg2.Value, ts.Reg3.Value = 0x1234, 0x56, 0x789ABCDEF
+
+	vals := RegValues(ts)
+	if len(vals) != 3 || vals["Reg1"] != 0x1234 || vals["Reg2"] != 0x56 || vals["Reg3"] != 0x789ABCDEF {
+		t.Fatalf("invalid values: %x", vals)
+	}
+
+	// Masks are bypassed
+	other := &test3{}
+	MustInitRegs(other)
+	vals["Unknown"] = 1
+	SetRegValues(other, vals)
+	if other.Reg1.Value != 0x1234 || other.Reg2.Value != 0x56 || other.Reg3.Value != 0x789ABCDEF {
+		t.Errorf("values not restored: %+v", other)
+	}
+}
@@ -11,8 +11,26 @@ type RegFlags uint8
 const (
 	RegFlagReadOnly RegFlags = (1 << iota)
 	RegFlagWriteOnly
+
+	// RegFlagReadSensitive marks registers whose reads have side effects
+	// (eg: popping a FIFO, or acknowledging an event): tools must use the
+	// peek functions to inspect them (see Table.Peek8), and reads must not
+	// be repeated.
+	RegFlagReadSensitive
 )
 
+// partialRead logs a partial read of a read-sensitive register: the read
+// callback runs for each partial access, so its side effect happens more than
+// once for what is probably meant to be a single read (eg: a 16-bit DMA
+// reading from a 32-bit FIFO).
+func partialRead(name string, addr uint32, size int) {
+	log.ModHwIo.WithFields(log.Fields{
+		"name": name,
+		"addr": emu.Hex32(addr),
+		"size": size,
+	}).Warn("partial read of read-sensitive reg")
+}
+
 type Reg64 struct {
 	Name   string
 	Value  uint64
@@ -89,6 +107,9 @@ func (reg *Reg64) Read32(addr uint32) uint32 {
 		}).Error("invalid Read32 from writeonly reg")
 		return 0
 	}
+	if reg.Flags&RegFlagReadSensitive != 0 {
+		partialRead(reg.Name, addr, 32)
+	}
 	shift := ((addr & 4) * 8)
 	return uint32(reg.Read64(addr) >> shift)
 }
@@ -114,6 +135,9 @@ func (reg *Reg64) Read16(addr uint32) uint16 {
 		}).Error("invalid Read16 from writeonly reg")
 		return 0
 	}
+	if reg.Flags&RegFlagReadSensitive != 0 {
+		partialRead(reg.Name, addr, 16)
+	}
 	shift := ((addr & 6) * 8)
 	return uint16(reg.Read64(addr) >> shift)
 }
@@ -139,6 +163,9 @@ func (reg *Reg64) Read8(addr uint32) uint8 {
 		}).Error("invalid Read8 from writeonly reg")
 		return 0
 	}
+	if reg.Flags&RegFlagReadSensitive != 0 {
+		partialRead(reg.Name, addr, 8)
+	}
 	shift := ((addr & 7) * 8)
 	return uint8(reg.Read64(addr) >> shift)
 }
@@ -219,6 +246,9 @@ func (reg *Reg32) Read16(addr uint32) uint16 {
 		}).Error("invalid Read16 from writeonly reg")
 		return 0
 	}
+	if reg.Flags&RegFlagReadSensitive != 0 {
+		partialRead(reg.Name, addr, 16)
+	}
 	shift := ((addr & 2) * 8)
 	return uint16(reg.Read32(addr) >> shift)
 }
@@ -244,6 +274,9 @@ func (reg *Reg32) Read8(addr uint32) uint8 {
 		}).Error("invalid Read8 from writeonly reg")
 		return 0
 	}
+	if reg.Flags&RegFlagReadSensitive != 0 {
+		partialRead(reg.Name, addr, 8)
+	}
 	shift := ((addr & 3) * 8)
 	return uint8(reg.Read32(addr) >> shift)
 }
@@ -324,6 +357,9 @@ func (reg *Reg16) Read8(addr uint32) uint8 {
 		}).Error("invalid Read8 from writeonly reg")
 		return 0
 	}
+	if reg.Flags&RegFlagReadSensitive != 0 {
+		partialRead(reg.Name, addr, 8)
+	}
 	shift := ((addr & 1) * 8)
 	return uint8(reg.Read16(addr) >> shift)
 }
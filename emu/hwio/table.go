@@ -3,6 +3,7 @@ package hwio
 import (
 	"fmt"
 	log "ndsemu/emu/logger"
+	"sort"
 )
 
 type BankIO8 interface {
@@ -26,13 +27,44 @@ type BankIO interface {
 	BankIO32
 }
 
+// UnmappedHandler is implemented by devices that want to handle accesses to
+// unpopulated addresses within their address window, for instance to log
+// them with the device's own logging module (see Table.MapFallback).
+// size is the access size in bits; for reads, the returned value is the
+// value read from the bus (val is zero), while it's ignored for writes.
+type UnmappedHandler interface {
+	Unmapped(addr uint32, size int, write bool, val uint32) uint32
+}
+
 type Table struct {
 	Name string
 	ws   int
 
-	table8  radixTree
-	table16 radixTree
-	table32 radixTree
+	// Accessor (optional) identifies who is accessing the bus, so that
+	// bus errors can report it.
+	Accessor BusAccessor
+
+	// ErrorFunc (optional) is invoked after a bus error has been logged,
+	// for instance to break into a debugger.
+	ErrorFunc func(err *BusError)
+
+	table8   radixTree
+	table16  radixTree
+	table32  radixTree
+	fallback radixTree
+
+	// Direct page tables for linear memories (see pages.go)
+	rpages pageTable
+	wpages pageTable
+
+	// Banks mapped through MapBank (see RegMap)
+	banks []mappedBank
+}
+
+type mappedBank struct {
+	addr uint32
+	data interface{}
+	num  int
 }
 
 type io32to16 Table
@@ -76,6 +108,10 @@ func (t *Table) Reset() {
 	t.table8 = radixTree{}
 	t.table16 = radixTree{}
 	t.table32 = radixTree{}
+	t.fallback = radixTree{}
+	t.rpages = pageTable{}
+	t.wpages = pageTable{}
+	t.banks = nil
 }
 
 // Map a register bank (that is, a structure containing mulitple IoReg* fields).
@@ -114,6 +150,7 @@ func (t *Table) MapBank(addr uint32, bank interface{}, bankNum int) {
 			panic(fmt.Errorf("invalid reg type: %T", r))
 		}
 	}
+	t.banks = append(t.banks, mappedBank{addr, bank, bankNum})
 }
 
 func (t *Table) UnmapBank(addr uint32, bank interface{}, bankNum int) {
@@ -123,21 +160,48 @@ func (t *Table) UnmapBank(addr uint32, bank interface{}, bankNum int) {
 	}
 
 	for _, reg := range regs {
-		switch r := reg.regPtr.(type) {
-		case *Mem:
-			t.Unmap(addr+reg.offset, addr+reg.offset+uint32(r.VSize)-1)
-		case *Reg64:
-			t.Unmap(addr+reg.offset, addr+reg.offset+7)
-		case *Reg32:
-			t.Unmap(addr+reg.offset, addr+reg.offset+3)
-		case *Reg16:
-			t.Unmap(addr+reg.offset, addr+reg.offset+1)
-		case *Reg8:
-			t.Unmap(addr+reg.offset, addr+reg.offset+0)
-		default:
-			panic(fmt.Errorf("invalid reg type: %T", r))
+		t.Unmap(addr+reg.offset, addr+reg.offset+regSize(reg.regPtr)-1)
+	}
+	for i, b := range t.banks {
+		if b == (mappedBank{addr, bank, bankNum}) {
+			t.banks = append(t.banks[:i], t.banks[i+1:]...)
+			break
+		}
+	}
+}
+
+// RegMap describes all the registers currently mapped on the bus through
+// MapBank, sorted by address.
+func (t *Table) RegMap() []RegDesc {
+	var regs []RegDesc
+	for _, b := range t.banks {
+		for _, reg := range RegMap(b.data) {
+			if reg.Bank == b.num {
+				reg.Addr = b.addr + reg.Offset
+				regs = append(regs, reg)
+			}
 		}
 	}
+	sort.SliceStable(regs, func(i, j int) bool { return regs[i].Addr < regs[j].Addr })
+	return regs
+}
+
+// regSize returns the number of bytes spanned by a register
+func regSize(reg interface{}) uint32 {
+	switch r := reg.(type) {
+	case *Mem:
+		return uint32(r.VSize)
+	case *Reg64:
+		return 8
+	case *Reg32:
+		return 4
+	case *Reg16:
+		return 2
+	case *Reg8:
+		return 1
+	default:
+		panic(fmt.Errorf("invalid reg type: %T", r))
+	}
 }
 
 func (t *Table) mapBus32(addr uint32, size uint32, io BankIO32, allowremap bool) {
@@ -215,6 +279,8 @@ func (t *Table) MapMem(addr uint32, mem *Mem) {
 	if b32 != nil {
 		t.mapBus32(addr, uint32(mem.VSize), b32, false)
 	}
+
+	t.mapPages(addr, mem)
 }
 
 func (t *Table) MapMemorySlice(addr uint32, end uint32, mem []uint8, readonly bool) {
@@ -229,20 +295,81 @@ func (t *Table) MapMemorySlice(addr uint32, end uint32, mem []uint8, readonly bo
 	})
 }
 
+// MapFallback registers a catch-all handler for the specified address range.
+// It has lower priority than anything mapped through the other Map* functions,
+// so it is only invoked for accesses to addresses where nothing else is
+// mapped; typically, a device registers it over its whole window to get
+// notified of accesses to unimplemented registers, instead of letting them
+// go through the generic unmapped-access error.
+//
+// Fallback ranges can't overlap each other, and are not affected by Unmap;
+// use UnmapFallback to remove them.
+func (t *Table) MapFallback(begin uint32, end uint32, h UnmappedHandler) {
+	if err := t.fallback.InsertRange(begin, end, h); err != nil {
+		panic(err)
+	}
+}
+
+func (t *Table) UnmapFallback(begin uint32, end uint32) {
+	t.fallback.RemoveRange(begin, end)
+}
+
 func (t *Table) Unmap(begin uint32, end uint32) {
 	t.table8.RemoveRange(begin, end)
 	t.table16.RemoveRange(begin, end)
 	t.table32.RemoveRange(begin, end)
+	t.unmapPages(begin, end)
+}
+
+// unmapped handles an access to an address where nothing is mapped, by
+// either invoking the fallback handler for it (if any), or reporting a
+// bus error.
+func (t *Table) unmapped(addr uint32, size int, write bool, val uint32) uint32 {
+	if h, ok := t.fallback.Search(addr).(UnmappedHandler); ok {
+		return h.Unmapped(addr, size, write, val)
+	}
+	t.busError(BusErrorUnmapped, addr, size, write, val)
+	return 0
+}
+
+func (t *Table) busError(kind BusErrorKind, addr uint32, size int, write bool, val uint32) {
+	err := &BusError{
+		Kind:  kind,
+		Bus:   t.Name,
+		Addr:  addr,
+		Size:  size,
+		Write: write,
+		Val:   val,
+	}
+	if t.Accessor != nil {
+		err.Accessor, err.PC = t.Accessor.BusAccessor()
+	}
+
+	z := log.ModHwIo.ErrorZ(err.Kind.String()).
+		String("bus", err.Bus).
+		Hex32("addr", err.Addr).
+		Int("size", err.Size).
+		Bool("write", err.Write)
+	if err.Write {
+		z = z.Hex32("val", err.Val)
+	}
+	if err.Accessor != "" {
+		z = z.String("by", err.Accessor).Hex32("pc", err.PC)
+	}
+	z.End()
+
+	if t.ErrorFunc != nil {
+		t.ErrorFunc(err)
+	}
 }
 
 func (t *Table) Read8(addr uint32) uint8 {
+	if val, ok := t.FastRead8(addr); ok {
+		return val
+	}
 	io := t.table8.Search(addr)
 	if io == nil {
-		log.ModHwIo.ErrorZ("unmapped Read8").
-			String("name", t.Name).
-			Hex32("addr", addr).
-			End()
-		return 0
+		return uint8(t.unmapped(addr, 8, false, 0))
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.Read8(addr)
@@ -251,13 +378,12 @@ func (t *Table) Read8(addr uint32) uint8 {
 }
 
 func (t *Table) Write8(addr uint32, val uint8) {
+	if t.FastWrite8(addr, val) {
+		return
+	}
 	io := t.table8.Search(addr)
 	if io == nil {
-		log.ModHwIo.ErrorZ("unmapped Write8").
-			String("name", t.Name).
-			Hex32("addr", addr).
-			Hex8("val", val).
-			End()
+		t.unmapped(addr, 8, true, uint32(val))
 		return
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
@@ -266,11 +392,7 @@ func (t *Table) Write8(addr uint32, val uint8) {
 		// requires no function call.
 		ok := mem.Write8CheckRO(addr, val)
 		if !ok {
-			log.ModHwIo.ErrorZ("Write8 to ROM").
-				String("name", t.Name).
-				Hex32("addr", addr).
-				Hex8("val", val).
-				End()
+			t.busError(BusErrorReadOnly, addr, 8, true, uint32(val))
 		}
 		return
 	}
@@ -278,13 +400,12 @@ func (t *Table) Write8(addr uint32, val uint8) {
 }
 
 func (t *Table) Read16(addr uint32) uint16 {
+	if val, ok := t.FastRead16(addr); ok {
+		return val
+	}
 	io := t.table16.Search(addr)
 	if io == nil {
-		log.ModHwIo.ErrorZ("unmapped Read16").
-			String("name", t.Name).
-			Hex32("addr", addr).
-			End()
-		return 0
+		return uint16(t.unmapped(addr, 16, false, 0))
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.Read16(addr)
@@ -293,13 +414,12 @@ func (t *Table) Read16(addr uint32) uint16 {
 }
 
 func (t *Table) Write16(addr uint32, val uint16) {
+	if t.FastWrite16(addr, val) {
+		return
+	}
 	io := t.table16.Search(addr)
 	if io == nil {
-		log.ModHwIo.ErrorZ("unmapped Write16").
-			String("name", t.Name).
-			Hex32("addr", addr).
-			Hex16("val", val).
-			End()
+		t.unmapped(addr, 16, true, uint32(val))
 		return
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
@@ -308,11 +428,7 @@ func (t *Table) Write16(addr uint32, val uint16) {
 		// requires no function call.
 		ok := mem.Write16CheckRO(addr, val)
 		if !ok {
-			log.ModHwIo.ErrorZ("Write16 to ROM").
-				String("name", t.Name).
-				Hex32("addr", addr).
-				Hex16("val", val).
-				End()
+			t.busError(BusErrorReadOnly, addr, 16, true, uint32(val))
 		}
 		return
 	}
@@ -320,13 +436,12 @@ func (t *Table) Write16(addr uint32, val uint16) {
 }
 
 func (t *Table) Read32(addr uint32) uint32 {
+	if val, ok := t.FastRead32(addr); ok {
+		return val
+	}
 	io := t.table32.Search(addr)
 	if io == nil {
-		log.ModHwIo.ErrorZ("unmapped Read32").
-			String("name", t.Name).
-			Hex32("addr", addr).
-			End()
-		return 0
+		return t.unmapped(addr, 32, false, 0)
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.Read32(addr)
@@ -335,13 +450,12 @@ func (t *Table) Read32(addr uint32) uint32 {
 }
 
 func (t *Table) Write32(addr uint32, val uint32) {
+	if t.FastWrite32(addr, val) {
+		return
+	}
 	io := t.table32.Search(addr)
 	if io == nil {
-		log.ModHwIo.ErrorZ("unmapped Write32").
-			String("name", t.Name).
-			Hex32("addr", addr).
-			Hex32("val", val).
-			End()
+		t.unmapped(addr, 32, true, val)
 		return
 	}
 	if mem, ok := io.(*memUnalignedLE); ok {
@@ -350,11 +464,7 @@ func (t *Table) Write32(addr uint32, val uint32) {
 		// requires no function call.
 		ok := mem.Write32CheckRO(addr, val)
 		if !ok {
-			log.ModHwIo.ErrorZ("Write32 to ROM").
-				String("name", t.Name).
-				Hex32("addr", addr).
-				Hex32("val", val).
-				End()
+			t.busError(BusErrorReadOnly, addr, 32, true, val)
 		}
 		return
 	}
@@ -374,6 +484,9 @@ func (t *Table) FetchPointer(addr uint32) []uint8 {
 	if mem, ok := io.(*memUnalignedLE); ok {
 		return mem.FetchPointer(addr)
 	}
+	if bs, ok := io.(*BankSwitch); ok {
+		return bs.FetchPointer(addr)
+	}
 	return nil
 }
 
@@ -174,3 +174,213 @@ func TestTableWrite(t *testing.T) {
 		t.Error("invalid regs after write32", r1, r2, r3, r4, r5, r6, r7, r8, r9)
 	}
 }
+
+type testSwitchBank struct {
+	Reg1 Reg16 `hwio:"offset=0x0"`
+	Reg2 Reg8  `hwio:"offset=0x2"`
+}
+
+func TestBankSwitch(t *testing.T) {
+	b1 := &testSwitchBank{}
+	b2 := &testSwitchBank{}
+	MustInitRegs(b1)
+	MustInitRegs(b2)
+	b1.Reg1.Value, b1.Reg2.Value = 0x1111, 0x11
+	b2.Reg1.Value, b2.Reg2.Value = 0x2222, 0x22
+
+	sw := NewBankSwitch("sw", 3)
+	sw.MapBank(0, 0x400010, b1, 0)
+	sw.MapBank(1, 0x400010, b2, 0)
+
+	r3 := Reg8{Value: 0x33}
+	table := Table{Name: "t1"}
+	table.Reset()
+	table.MapBankSwitch(sw)
+	table.MapReg8(0x400014, &r3)
+
+	if got := table.Read32(0x400010); got != 0x00111111 {
+		t.Errorf("invalid read32 on bank 0: got:%x", got)
+	}
+
+	sw.Switch(1)
+	if got := table.Read16(0x400010); got != 0x2222 {
+		t.Errorf("invalid read16 on bank 1: got:%x", got)
+	}
+	table.Write8(0x400012, 0x44)
+	if b2.Reg2.Value != 0x44 || b1.Reg2.Value != 0x11 {
+		t.Errorf("invalid write8 on bank 1: %v %v", b1.Reg2, b2.Reg2)
+	}
+
+	// Empty alternative: the window is unmapped
+	sw.Switch(2)
+	if got := table.Read8(0x400010); got != 0 {
+		t.Errorf("invalid read8 on empty bank: got:%x", got)
+	}
+	if got := table.Read8(0x400014); got != 0x33 {
+		t.Errorf("register outside window not accessible: got:%x", got)
+	}
+
+	table.UnmapBankSwitch(sw)
+	sw.Switch(0)
+	if got := table.Read16(0x400010); got != 0 {
+		t.Errorf("bank switch still mapped: got:%x", got)
+	}
+}
+
+type testFallback struct {
+	reads, writes int
+}
+
+func (f *testFallback) Unmapped(addr uint32, size int, write bool, val uint32) uint32 {
+	if write {
+		f.writes++
+		return 0
+	}
+	f.reads++
+	return 0xFFFFFFFF
+}
+
+func TestFallback(t *testing.T) {
+	r1 := Reg16{Value: 0x1122}
+	fb := &testFallback{}
+
+	table := Table{Name: "t1"}
+	table.Reset()
+	table.MapFallback(0x400000, 0x4000FF, fb)
+	table.MapReg16(0x400010, &r1)
+
+	if got := table.Read16(0x400010); got != 0x1122 {
+		t.Errorf("fallback has priority over regs: got:%x", got)
+	}
+	if got := table.Read32(0x400020); got != 0xFFFFFFFF {
+		t.Errorf("invalid fallback read32: got:%x", got)
+	}
+	if got := table.Read32(0x400010); got != 0xFFFF1122 {
+		t.Errorf("invalid partially-mapped read32: got:%x", got)
+	}
+	table.Write8(0x400030, 0)
+	if fb.reads != 2 || fb.writes != 1 {
+		t.Errorf("invalid fallback calls: %d reads, %d writes", fb.reads, fb.writes)
+	}
+
+	table.UnmapFallback(0x400000, 0x4000FF)
+	if got := table.Read8(0x400020); got != 0 {
+		t.Errorf("fallback still mapped: got:%x", got)
+	}
+}
+
+type testAccessor struct{}
+
+func (testAccessor) BusAccessor() (string, uint32) { return "cpu", 0x1234 }
+
+func TestBusError(t *testing.T) {
+	var errs []*BusError
+
+	table := Table{Name: "t1"}
+	table.Reset()
+	table.Accessor = testAccessor{}
+	table.ErrorFunc = func(err *BusError) { errs = append(errs, err) }
+	table.MapMemorySlice(0x1000, 0x1FFF, make([]byte, 0x1000), true)
+
+	table.Write16(0x1002, 0xAABB)
+	table.Read32(0x2000)
+
+	if len(errs) != 2 {
+		t.Fatalf("invalid number of errors: %d", len(errs))
+	}
+	want := []string{
+		"write to ROM: write16 at 00001002 (val=aabb) on t1 by cpu (pc=00001234)",
+		"unmapped access: read32 at 00002000 on t1 by cpu (pc=00001234)",
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("invalid error: got:%q, want:%q", err.Error(), want[i])
+		}
+	}
+}
+
+func TestPeekPoke(t *testing.T) {
+	reads := 0
+	r1 := Reg16{Value: 0x1122, ReadCb: func(v uint16) uint16 {
+		reads++
+		return v
+	}}
+	r2 := Reg32{Value: 0xAABBCCDD, RoMask: 0xFFFFFFFF, WriteCb: func(old, val uint32) {
+		t.Error("write callback invoked by poke")
+	}}
+	mem := make([]byte, 16)
+
+	table := Table{Name: "t1"}
+	table.Reset()
+	table.MapReg16(0x400010, &r1)
+	table.MapReg32(0x400014, &r2)
+	table.MapMemorySlice(0x1000, 0x100F, mem, true)
+
+	if got := table.Peek16(0x400010); got != 0x1122 {
+		t.Errorf("invalid peek16: got:%x", got)
+	}
+	if got := table.Peek32(0x400012); got != 0xCCDD0000 {
+		t.Errorf("invalid peek32: got:%x", got)
+	}
+	if reads != 0 {
+		t.Errorf("read callback invoked by peek")
+	}
+
+	table.Poke16(0x400016, 0x1234)
+	if r2.Value != 0x1234CCDD {
+		t.Errorf("invalid poke16 on read-only reg: got:%x", r2.Value)
+	}
+	if !table.Poke32(0x1004, 0x55667788) || mem[4] != 0x88 || mem[7] != 0x55 {
+		t.Errorf("invalid poke32 on ROM: %x", mem)
+	}
+	if table.Poke8(0x2000, 1) || table.Peek8(0x2000) != 0 {
+		t.Errorf("invalid access to unmapped address")
+	}
+}
+
+func TestPageTables(t *testing.T) {
+	ram := make([]byte, 8192)
+	rom := make([]byte, 4096)
+	rom[0x10] = 0xAA
+	var cbcalls int
+
+	table := NewTable("t1")
+	table.MapMemorySlice(0x02000000, 0x02FFFFFF, ram, false)
+	table.MapMemorySlice(0x08000000, 0x08000FFF, rom, true)
+	table.MapMem(0x03000000, &Mem{
+		Data:    make([]byte, 4096),
+		VSize:   4096,
+		Flags:   MemFlag8 | MemFlag16Unaligned | MemFlag32Unaligned,
+		WriteCb: func(uint32, int) { cbcalls++ },
+	})
+
+	// Mirrored RAM is accessed through the page tables
+	if !table.FastWrite32(0x02002004, 0x11223344) {
+		t.Fatal("RAM write not served by page tables")
+	}
+	if val, ok := table.FastRead32(0x02000004); !ok || val != 0x11223344 {
+		t.Errorf("invalid mirrored read: got:%x,%v", val, ok)
+	}
+	if val := table.Read16(0x02FFE006); val != 0x1122 {
+		t.Errorf("invalid read16: got:%x", val)
+	}
+
+	// Read-only memory and memories with callbacks are readable through the
+	// page tables, but writes must go through the slow path
+	if val, ok := table.FastRead8(0x08000010); !ok || val != 0xAA {
+		t.Errorf("invalid ROM read: got:%x,%v", val, ok)
+	}
+	if table.FastWrite8(0x08000010, 0) || table.FastWrite8(0x03000000, 0) {
+		t.Errorf("write to ROM/callback memory served by page tables")
+	}
+	table.Write8(0x03000000, 1)
+	if cbcalls != 1 {
+		t.Errorf("write callback not called")
+	}
+
+	// Unmapping removes the pages
+	table.Unmap(0x02000000, 0x02FFFFFF)
+	if _, ok := table.FastRead32(0x02000004); ok {
+		t.Errorf("unmapped RAM still in page tables")
+	}
+}
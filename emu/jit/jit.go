@@ -28,16 +28,22 @@ package jit
 
 import (
 	log "ndsemu/emu/logger"
+	"ndsemu/emu/trace"
 	"runtime"
+	"strconv"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/edsrzf/mmap-go"
 )
 
 const (
-	pageSize         = 1024 * 1024 // size of mmap page that contains JIT code
-	jitCallThreshold = 255         // after how many calls the code block will be JIT'd
+	pageSize = 1024 * 1024 // size of mmap page that contains JIT code
+
+	// DefaultHotThreshold is the default number of calls after which a block
+	// is considered hot, and compiled (see Config.HotThreshold)
+	DefaultHotThreshold = 255
 )
 
 var modJit = log.NewModule("jit")
@@ -86,6 +92,35 @@ type Config struct {
 	// at X, all writes between X and X+MaxBlockSize will invalidate the
 	// function being compiled.
 	MaxBlockSize int
+
+	// Number of times a block is run in the interpreter before being
+	// compiled (1-65535, 0 means DefaultHotThreshold). Cold code is never
+	// compiled, which saves compilation time and memory; a higher threshold
+	// restricts the JIT to the hottest loops.
+	HotThreshold int
+
+	// Maximum size of the code cache, in bytes (0 = unlimited). When it's
+	// exceeded, the least recently used page of code is evicted (or the whole
+	// cache is flushed, if FlushCache is set) before compiling the next
+	// block; evicted blocks are compiled again if they are still hot. The
+	// cache is made of 1 MiB pages, and can temporarily exceed the limit
+	// by one page.
+	MaxCacheSize int
+	FlushCache   bool
+}
+
+// Stats are counters that describe the activity of the JIT
+type Stats struct {
+	Hits     uint64 // lookups that found compiled code
+	Misses   uint64 // lookups that fell back to the interpreter
+	Requests uint64 // blocks that got hot, and were queued for compilation
+	Compiled uint64 // blocks compiled successfully
+	Failed   uint64 // blocks that could not be compiled
+	CodeSize uint64 // bytes of generated code
+
+	CacheSize uint64 // bytes currently allocated for the code cache
+	Evictions uint64 // pages evicted from the code cache
+	Flushes   uint64 // whole-cache flushes
 }
 
 type block struct {
@@ -95,6 +130,15 @@ type block struct {
 	// Beginning of this jit block
 	pcstart uint32
 	size    uint32
+
+	// Page of the code cache holding jitcode
+	page *codePage
+}
+
+// codePage is a memory mapped page of the code cache
+type codePage struct {
+	mem     []byte
+	lastUse uint64 // value of Stats.Hits when a block of the page last ran
 }
 
 // Jit is a generic JIT manager, that handles common tasks that are necessary
@@ -109,9 +153,14 @@ type Jit struct {
 	bkgCompilingPc uint64      // If != notCompiling, this is the PC of the block being compiled
 
 	pageLastIdx  int                     // free index into the last page
-	pages        [][]byte                // memory mapped pages
+	pages        []*codePage             // memory mapped pages
 	blocks       [65536][]unsafe.Pointer // *block, needs unsafe.Pointer for atomic
-	blockMetrics [65536][]byte
+	blockMetrics [65536][]uint16
+	threshold    uint16
+
+	// Lookup counters are updated by the emulation thread only, the others
+	// by the background process (atomically)
+	stats Stats
 
 	HACK_OtherJit *Jit
 }
@@ -124,11 +173,56 @@ func NewJit(comp Compiler, cfg *Config) *Jit {
 		cfg:    cfg,
 		comp:   comp,
 		taskCh: make(chan uint32, 256),
+
+		bkgCompilingPc: notCompiling,
 	}
+	j.SetHotThreshold(cfg.HotThreshold)
 	go j.bkgProc()
 	return j
 }
 
+// SetHotThreshold changes the number of calls after which a block is
+// compiled (see Config.HotThreshold). Blocks that are already being counted
+// keep their current count.
+func (j *Jit) SetHotThreshold(n int) {
+	switch {
+	case n <= 0:
+		n = DefaultHotThreshold
+	case n > 0xFFFF:
+		n = 0xFFFF
+	}
+	j.cfg.HotThreshold = n
+	j.threshold = uint16(n)
+}
+
+// SetCacheLimit changes the maximum size of the code cache, and the eviction
+// policy (see Config.MaxCacheSize and Config.FlushCache).
+func (j *Jit) SetCacheLimit(size int, flush bool) {
+	j.lockBkgProc(0)
+	j.cfg.MaxCacheSize = size
+	j.cfg.FlushCache = flush
+	j.unlockBkgProc()
+}
+
+// Stats returns the current value of the JIT counters. It must be called
+// from the emulation thread (eg: the bridge executes "jitstat" between
+// frames), which owns the lookup and eviction counters; the counters updated
+// by the background compiler are read atomically, so the structure is not
+// copied as a whole.
+func (j *Jit) Stats() Stats {
+	return Stats{
+		Hits:      j.stats.Hits,
+		Misses:    j.stats.Misses,
+		Requests:  atomic.LoadUint64(&j.stats.Requests),
+		Compiled:  atomic.LoadUint64(&j.stats.Compiled),
+		Failed:    atomic.LoadUint64(&j.stats.Failed),
+		CodeSize:  atomic.LoadUint64(&j.stats.CodeSize),
+		CacheSize: atomic.LoadUint64(&j.stats.CacheSize),
+		Evictions: j.stats.Evictions,
+		Flushes:   j.stats.Flushes,
+	}
+}
+
 // Lookup checks if there is a JIT function available for this address, and
 // returns it if so.
 // If it's not available, it will keep some metrics of the most called
@@ -143,11 +237,18 @@ func (j *Jit) Lookup(pc uint32) func() {
 			// compilation failed for any reason. In this case, we correctly
 			// return nil, but don't update metrics or trigger a new compilation
 			// as it would fail as well.
+			if b.jitcode != nil {
+				j.stats.Hits++
+				b.page.lastUse = j.stats.Hits
+			} else {
+				j.stats.Misses++
+			}
 			return b.jitcode
 		}
 	}
 
 	// No code found. Bump metrics for this target
+	j.stats.Misses++
 	j.updateMetrics(pc)
 	return nil
 }
@@ -156,12 +257,15 @@ func (j *Jit) updateMetrics(pc uint32) {
 	align := j.cfg.PcAlignmentShift
 	mg := j.blockMetrics[pc>>16]
 	if mg == nil {
-		mg = make([]byte, (1<<16)>>align)
+		mg = make([]uint16, (1<<16)>>align)
 		j.blockMetrics[pc>>16] = mg
 	}
 	idx := (pc & 0xFFFF) >> align
 	mg[idx]++
-	if mg[idx] == jitCallThreshold {
+	if mg[idx] >= j.threshold {
+		// Start counting again, in case the block is invalidated later
+		mg[idx] = 0
+
 		// This call target is used a lot. We want to trigger background compilation.
 		// Before spawning the background process, allocate the block where the
 		// code will be stored. This allows Invalidate() to be called concurrently
@@ -175,6 +279,14 @@ func (j *Jit) updateMetrics(pc uint32) {
 
 		modJit.InfoZ("requesting compilation").Hex32("pc", pc).End()
 
+		if j.cfg.MaxCacheSize > 0 {
+			j.lockBkgProc(0)
+			if len(j.pages)*pageSize > j.cfg.MaxCacheSize {
+				j.evict()
+			}
+			j.unlockBkgProc()
+		}
+
 		if !atomic.CompareAndSwapPointer(bptr, nil, pendingCanary) {
 			modJit.WarnZ("requested JIT for function already compiled").Hex32("pc", pc).End()
 			return
@@ -182,10 +294,12 @@ func (j *Jit) updateMetrics(pc uint32) {
 
 		select {
 		case j.taskCh <- pc:
+			atomic.AddUint64(&j.stats.Requests, 1)
 		default:
 			// The channel is full; don't block but try again next time
 			// we call this target
-			mg[idx]--
+			atomic.CompareAndSwapPointer(bptr, pendingCanary, nil)
+			mg[idx] = j.threshold - 1
 			modJit.InfoZ("compilation queue full").Hex32("pc", pc).End()
 		}
 	}
@@ -257,34 +371,80 @@ func (j *Jit) InvalidateAll() {
 		j.blocks[i] = nil
 	}
 	// Also release all pages
-	for i := range j.pages {
-		m := mmap.MMap(j.pages[i])
+	j.freePages()
+	j.unlockBkgProc()
+}
+
+func (j *Jit) freePages() {
+	for _, p := range j.pages {
+		m := mmap.MMap(p.mem)
 		m.Unmap()
 	}
 	j.pages = nil
-	j.unlockBkgProc()
+	atomic.StoreUint64(&j.stats.CacheSize, 0)
+}
+
+// evict makes room in the code cache, by releasing the least recently used
+// page (or all of them, if so configured). It must be called from the
+// emulation thread (so that no evicted code is running), with the background
+// process locked.
+func (j *Jit) evict() {
+	if j.cfg.FlushCache || len(j.pages) < 2 {
+		for i := range j.blocks {
+			j.blocks[i] = nil
+		}
+		j.freePages()
+		j.stats.Flushes++
+		modJit.InfoZ("code cache flushed").End()
+		return
+	}
+
+	// The last page is the one being filled, so it's never evicted
+	victim := 0
+	for i, p := range j.pages[:len(j.pages)-1] {
+		if p.lastUse < j.pages[victim].lastUse {
+			victim = i
+		}
+	}
+	vp := j.pages[victim]
+	for _, bg := range j.blocks {
+		for i := range bg {
+			if b := (*block)(atomic.LoadPointer(&bg[i])); b != nil && b.page == vp {
+				atomic.StorePointer(&bg[i], nil)
+			}
+		}
+	}
+
+	m := mmap.MMap(vp.mem)
+	m.Unmap()
+	j.pages = append(j.pages[:victim], j.pages[victim+1:]...)
+	atomic.AddUint64(&j.stats.CacheSize, ^uint64(pageSize-1))
+	j.stats.Evictions++
+	modJit.InfoZ("code cache page evicted").Int("pages", len(j.pages)).End()
 }
 
 func (j *Jit) newPage() {
-	b, err := mmap.MapRegion(nil, 1024*1024, mmap.EXEC|mmap.RDWR, mmap.ANON, int64(0))
+	b, err := mmap.MapRegion(nil, pageSize, mmap.EXEC|mmap.RDWR, mmap.ANON, int64(0))
 	if err != nil {
 		panic(err)
 	}
-	j.pages = append(j.pages, b)
+	j.pages = append(j.pages, &codePage{mem: b})
 	j.pageLastIdx = 0
+	atomic.AddUint64(&j.stats.CacheSize, pageSize)
 }
 
 func (j *Jit) getFreePage() []byte {
 	if len(j.pages) == 0 {
 		j.newPage()
 	}
-	return j.pages[len(j.pages)-1][j.pageLastIdx:]
+	return j.pages[len(j.pages)-1].mem[j.pageLastIdx:]
 }
 
 // Background process that handles compilation
 func (j *Jit) bkgProc() {
 	for pc := range j.taskCh {
 		j.lockBkgProc(pc)
+		t0 := time.Now()
 
 		// Compile the new call target
 		code, insize, outsize := j.comp.JitCompileBlock(pc, j.getFreePage())
@@ -295,6 +455,12 @@ func (j *Jit) bkgProc() {
 			code, insize, outsize = j.comp.JitCompileBlock(pc, j.getFreePage())
 		}
 		j.pageLastIdx += outsize
+		if code != nil {
+			atomic.AddUint64(&j.stats.Compiled, 1)
+			atomic.AddUint64(&j.stats.CodeSize, uint64(outsize))
+		} else {
+			atomic.AddUint64(&j.stats.Failed, 1)
+		}
 
 		// Sanity check: the compiler should not create a block bigger than
 		// MaxBlockSize (otherwise InvalidateRange might fail)
@@ -303,6 +469,12 @@ func (j *Jit) bkgProc() {
 		}
 
 		modJit.InfoZ("compiled block").Hex32("pc", pc).Int("insn", insize/4).End()
+		if trace.Enabled() {
+			trace.HostComplete("jit", "compile", t0, trace.Args{
+				"pc":   strconv.FormatUint(uint64(pc), 16),
+				"insn": insize / 4,
+			})
+		}
 
 		// Prepare the new block. Notice that code could be nil (if
 		// compilation failed), but we don't care and still save the block
@@ -311,6 +483,7 @@ func (j *Jit) bkgProc() {
 		b.jitcode = code
 		b.pcstart = pc
 		b.size = uint32(insize)
+		b.page = j.pages[len(j.pages)-1]
 
 		// Store it atomically. We should find the pending canary in the slot;
 		// if we don't, it means that the block was invalidated while we were
@@ -326,7 +499,7 @@ func (j *Jit) bkgProc() {
 }
 
 func (j *Jit) lockBkgProc(pc uint32) {
-	for atomic.CompareAndSwapUint64(&j.bkgCompilingPc, notCompiling, uint64(pc)) {
+	for !atomic.CompareAndSwapUint64(&j.bkgCompilingPc, notCompiling, uint64(pc)) {
 		// TODO: check if time.Sleep(1*time.Microsecond is better)
 		runtime.Gosched()
 	}
@@ -0,0 +1,78 @@
+package jit
+
+import (
+	"testing"
+	"time"
+)
+
+// A compiler that doesn't generate any code, but fills half a page of the
+// code cache with each block
+type nullCompiler struct{}
+
+func (nullCompiler) JitCompileBlock(pc uint32, out []byte) (func(), int, int) {
+	if len(out) < pageSize/2 {
+		return nil, -1, -1
+	}
+	return func() {}, 4, pageSize / 2
+}
+
+func TestCacheEviction(t *testing.T) {
+	j := NewJit(nullCompiler{}, &Config{
+		PcAlignmentShift: 2,
+		MaxBlockSize:     1024,
+		HotThreshold:     2,
+		MaxCacheSize:     2 * pageSize,
+	})
+	defer j.InvalidateAll()
+
+	run := func(pc uint32) {
+		deadline := time.Now().Add(5 * time.Second)
+		for j.Lookup(pc) == nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("block %x not compiled", pc)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Fill three pages (exceeding the limit) with five blocks, then use
+	// the second page
+	for pc := uint32(0); pc < 20; pc += 4 {
+		run(pc)
+	}
+	run(8)
+	if st := j.Stats(); st.Evictions != 0 || st.CacheSize != 3*pageSize {
+		t.Errorf("invalid stats: %+v", st)
+	}
+
+	// The first page is the least recently used, so it must be evicted
+	// before compiling the next block
+	run(20)
+	if st := j.Stats(); st.Evictions != 1 || st.CacheSize != 2*pageSize || st.Compiled != 6 {
+		t.Errorf("invalid stats: %+v", st)
+	}
+	if j.Lookup(0) != nil || j.Lookup(4) != nil || j.Lookup(8) == nil || j.Lookup(16) == nil {
+		t.Errorf("wrong blocks evicted")
+	}
+}
+
+func TestStatsWhileCompiling(t *testing.T) {
+	j := NewJit(nullCompiler{}, &Config{
+		PcAlignmentShift: 2,
+		MaxBlockSize:     1024,
+		HotThreshold:     1,
+	})
+	defer j.InvalidateAll()
+
+	// Read the counters from the emulation thread while the background
+	// process compiles blocks (run with -race)
+	deadline := time.Now().Add(5 * time.Second)
+	for st := j.Stats(); st.Compiled < 8; st = j.Stats() {
+		if time.Now().After(deadline) {
+			t.Fatalf("blocks not compiled: %+v", st)
+		}
+		for pc := uint32(0); pc < 32; pc += 4 {
+			j.Lookup(pc)
+		}
+	}
+}
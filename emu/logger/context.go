@@ -11,3 +11,13 @@ var contexts []LogContextAdder
 func AddContext(c LogContextAdder) {
 	contexts = append(contexts, c)
 }
+
+// RemoveContext unregisters a context adder registered with AddContext
+func RemoveContext(c LogContextAdder) {
+	for i := range contexts {
+		if contexts[i] == c {
+			contexts = append(contexts[:i], contexts[i+1:]...)
+			return
+		}
+	}
+}
@@ -263,7 +263,14 @@ func (z *EntryZ) end() {
 	output.Write(z.buf.Bytes())
 	z.buf.Reset()
 
+	if z.lvl <= logrus.WarnLevel {
+		countWarning(modname, z.msg)
+	}
+
 	if z.lvl == logrus.FatalLevel {
+		for _, h := range fatalHooks {
+			h.fn("[" + modname + "] " + z.msg)
+		}
 		os.Exit(1)
 	} else if z.lvl == logrus.PanicLevel {
 		panic("raising panic in logger")
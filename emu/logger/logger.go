@@ -3,6 +3,9 @@ package logger
 import (
 	"io"
 	"os"
+	"regexp"
+	"strings"
+	"sync"
 
 	"gopkg.in/Sirupsen/logrus.v0"
 )
@@ -27,8 +30,62 @@ func Disable() {
 }
 
 func SetOutput(out io.Writer) {
-	logrus.SetOutput(out)
-	output = out
+	w := historyWriter{out}
+	logrus.SetOutput(w)
+	output = w
+}
+
+// Ring buffer of the last lines written to the log
+type logHistory struct {
+	lock  sync.Mutex
+	lines []string
+	pos   int
+}
+
+var history *logHistory
+
+type historyWriter struct {
+	io.Writer
+}
+
+func (w historyWriter) Write(p []byte) (int, error) {
+	if h := history; h != nil {
+		h.lock.Lock()
+		if len(h.lines) < cap(h.lines) {
+			h.lines = append(h.lines, string(p))
+		} else {
+			h.lines[h.pos] = string(p)
+			h.pos = (h.pos + 1) % len(h.lines)
+		}
+		h.lock.Unlock()
+	}
+	return w.Writer.Write(p)
+}
+
+// EnableHistory starts keeping track of the last n lines written to the
+// log, so that they can be retrieved with History (eg: for crash reports).
+func EnableHistory(n int) {
+	history = &logHistory{lines: make([]string, 0, n)}
+}
+
+var rxAnsiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// History returns the last lines written to the log (oldest first),
+// stripped of terminal colors.
+func History() []string {
+	h := history
+	if h == nil {
+		return nil
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	res := make([]string, 0, len(h.lines))
+	for i := range h.lines {
+		line := h.lines[(h.pos+i)%len(h.lines)]
+		res = append(res, strings.TrimRight(rxAnsiEscape.ReplaceAllString(line, ""), "\n"))
+	}
+	return res
 }
 
 func init() {
@@ -0,0 +1,68 @@
+package logger
+
+import "sync"
+
+// Counters of the warnings (and errors) written to the log, keyed by module
+// and message. Only new-style entries (WarnZ, ErrorZ...) are counted: their
+// messages are constant strings, so they identify the condition being
+// reported (eg: an unimplemented feature).
+type warnStats struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+var stats *warnStats
+
+// EnableWarningStats starts counting the warnings written to the log, so that
+// they can be retrieved with WarningStats.
+func EnableWarningStats() {
+	stats = &warnStats{counts: make(map[string]int)}
+}
+
+// WarningStats returns how many times each warning or error was logged, keyed
+// by "[module] message"
+func WarningStats() map[string]int {
+	s := stats
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := make(map[string]int, len(s.counts))
+	for k, v := range s.counts {
+		res[k] = v
+	}
+	return res
+}
+
+func countWarning(mod string, msg string) {
+	if s := stats; s != nil {
+		s.lock.Lock()
+		s.counts["["+mod+"] "+msg]++
+		s.lock.Unlock()
+	}
+}
+
+type fatalHook struct {
+	fn func(msg string)
+}
+
+var fatalHooks []*fatalHook
+
+// AddFatalHook registers a function to be called when a fatal error is
+// logged, right before the program exits. It receives the message, in the
+// same "[module] message" format used by WarningStats. The returned function
+// unregisters the hook.
+func AddFatalHook(fn func(msg string)) (remove func()) {
+	h := &fatalHook{fn}
+	fatalHooks = append(fatalHooks, h)
+	return func() {
+		for i := range fatalHooks {
+			if fatalHooks[i] == h {
+				fatalHooks = append(fatalHooks[:i:i], fatalHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
@@ -3,7 +3,11 @@ package emu
 import (
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"runtime"
 	"sort"
+	"time"
 
 	"ndsemu/emu/fixed"
 	log "ndsemu/emu/logger"
@@ -153,6 +157,10 @@ type Sync struct {
 	events      []syncEvent
 	cycles      int64
 	frames      int64
+
+	// Host time spent running each subsystem (if profiling is enabled)
+	profiling bool
+	hostTimes map[string]time.Duration
 }
 
 func NewSync(cfg *SyncConfig) (*Sync, error) {
@@ -255,6 +263,47 @@ func (s *Sync) Reset() {
 	s.cycles = 0
 }
 
+// Clear unregisters all the CPUs and subsystems, and drops all the pending
+// events, so that a new set of subsystems can be registered (eg: when the
+// emulated hardware is recreated on reset).
+func (s *Sync) Clear() {
+	s.runningSub = nil
+	s.subCpus = nil
+	s.subOthers = nil
+	s.events = nil
+	s.cycles = 0
+}
+
+// FrameCycles returns the duration of a frame, in cycles of the main clock
+func (s *Sync) FrameCycles() int64 {
+	return s.frameCycles
+}
+
+// SetCycles moves the clock to the specified instant, which must be a frame
+// boundary, dropping all the pending events. It is used to restore a
+// savestate: the registered subsystems must be brought to the same instant
+// separately, and reschedule their events.
+func (s *Sync) SetCycles(cycles int64) {
+	if cycles%s.frameCycles != 0 {
+		panic("SetCycles called with a time that is not a frame boundary")
+	}
+	s.events = nil
+	s.cycles = cycles
+	s.frames = cycles / s.frameCycles
+}
+
+// PendingEvents returns the number of pending events scheduled with a
+// callback (see ScheduleEvent); plain sync points are not counted.
+func (s *Sync) PendingEvents() int {
+	n := 0
+	for _, ev := range s.events {
+		if ev.Cb != nil {
+			n++
+		}
+	}
+	return n
+}
+
 // Return the current clock. If this function is called from within a subsystem,
 // it returns that subsystem's vision of the current timing. Especially for CPUs,
 // it is thus important that Subsytem.Cycles() calls within Subsystem.Run()
@@ -390,7 +439,7 @@ func (s *Sync) RunUntil(target int64) {
 			}
 
 			s.runningSub = &s.subCpus[idx]
-			s.runningSub.Run(next)
+			s.runSub(next)
 			s.runningSub = nil
 		}
 
@@ -400,7 +449,7 @@ func (s *Sync) RunUntil(target int64) {
 			}
 
 			s.runningSub = &s.subOthers[idx]
-			s.runningSub.Run(next)
+			s.runSub(next)
 			s.runningSub = nil
 		}
 
@@ -417,6 +466,32 @@ func (s *Sync) RunUntil(target int64) {
 	s.cycles = target
 }
 
+func (s *Sync) runSub(target int64) {
+	if !s.profiling {
+		s.runningSub.Run(target)
+		return
+	}
+	t0 := time.Now()
+	s.runningSub.Run(target)
+	s.hostTimes[s.runningSub.name] += time.Since(t0)
+}
+
+// SetProfiling enables measuring the host time spent running each
+// subsystem (see HostTimes)
+func (s *Sync) SetProfiling(enable bool) {
+	s.profiling = enable
+	s.hostTimes = make(map[string]time.Duration)
+}
+
+// HostTimes returns the host time spent running each subsystem (by name)
+// since the previous call, and resets the counters. Profiling must be enabled
+// with SetProfiling.
+func (s *Sync) HostTimes() map[string]time.Duration {
+	times := s.hostTimes
+	s.hostTimes = make(map[string]time.Duration, len(times))
+	return times
+}
+
 func (s *Sync) CurrentSubsystem() Subsystem {
 	if s.runningSub == nil {
 		return nil
@@ -445,3 +520,51 @@ func (s *Sync) AddLogContext(entry *log.EntryZ) {
 		}
 	}
 }
+
+// BusAccessor reports the subsystem currently running, and its program
+// counter if it's a CPU. It implements hwio.BusAccessor, so that invalid bus
+// accesses can be tracked down to the code performing them. Accesses made
+// by event callbacks are not attributed.
+func (s *Sync) BusAccessor() (string, uint32) {
+	cur := s.runningSub
+	if cur == nil {
+		return "", 0
+	}
+	if cpu, ok := cur.Subsystem.(Cpu); ok {
+		return cur.name, cpu.GetPC()
+	}
+	return cur.name, 0
+}
+
+// DumpStatus writes a human-readable description of the scheduler state:
+// current time, subsystems and pending events. It is meant for diagnostics
+// (eg: crash reports).
+func (s *Sync) DumpStatus(w io.Writer) {
+	x, y := s.DotPos()
+	fmt.Fprintf(w, "cycles=%d frame=%d dot=(%d,%d)\n", s.cycles, s.frames, x, y)
+	if s.runningSub != nil {
+		fmt.Fprintf(w, "running: %s\n", s.runningSub.name)
+	}
+
+	fmt.Fprintf(w, "subsystems:\n")
+	for _, subs := range [][]syncSubsystem{s.subCpus, s.subOthers} {
+		for _, sub := range subs {
+			fmt.Fprintf(w, "  %-10s cycles=%d", sub.name, sub.Cycles())
+			if cpu, ok := sub.Subsystem.(Cpu); ok {
+				fmt.Fprintf(w, " pc=%08x", cpu.GetPC())
+			}
+			fmt.Fprintf(w, "\n")
+		}
+	}
+
+	fmt.Fprintf(w, "pending events:\n")
+	for _, evt := range s.events {
+		name := "sync"
+		if evt.Cb != nil {
+			if fn := runtime.FuncForPC(reflect.ValueOf(evt.Cb).Pointer()); fn != nil {
+				name = fn.Name()
+			}
+		}
+		fmt.Fprintf(w, "  %d (+%d) %s\n", evt.When, evt.When-s.cycles, name)
+	}
+}
@@ -0,0 +1,198 @@
+// Package trace records timeline events in the Trace Event Format used by
+// Chrome (chrome://tracing) and Perfetto (https://ui.perfetto.dev), to
+// inspect the timing interactions between the emulated subsystems.
+//
+// Tracing is global and disabled by default; event producers should check
+// Enabled() before preparing an event, so that the cost is negligible when
+// tracing is off.
+//
+// Events are laid out on two timelines (shown as separate processes): the
+// emulated time, derived from the clock of the scheduler, and the host time,
+// for activities happening outside of the emulation (eg: JIT compilation in
+// background). Each timeline is split into named tracks (shown as threads).
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	pidEmulated = 1
+	pidHost     = 2
+)
+
+// Args are the arguments attached to an event, shown when selected
+type Args map[string]interface{}
+
+type event struct {
+	Name string  `json:"name"`
+	Ph   string  `json:"ph"`
+	Ts   float64 `json:"ts"`
+	Dur  float64 `json:"dur,omitempty"`
+	Pid  int     `json:"pid"`
+	Tid  int     `json:"tid"`
+	S    string  `json:"s,omitempty"`
+	Args Args    `json:"args,omitempty"`
+}
+
+type tracer struct {
+	f      *os.File
+	w      *bufio.Writer
+	clock  func() int64
+	freq   float64
+	start  time.Time
+	tracks map[[2]interface{}]int
+	nev    int
+}
+
+var (
+	enabled int32
+	mu      sync.Mutex
+	cur     *tracer
+)
+
+// Enabled reports whether tracing is active
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) != 0
+}
+
+// Start begins tracing into the specified file. clock returns the current
+// emulated time in cycles, and freq is the frequency of the clock in Hz.
+func Start(fn string, clock func() int64, freq int64) error {
+	Stop()
+
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	t := &tracer{
+		f:      f,
+		w:      bufio.NewWriter(f),
+		clock:  clock,
+		freq:   float64(freq),
+		start:  time.Now(),
+		tracks: make(map[[2]interface{}]int),
+	}
+	t.w.WriteString("[\n")
+	t.meta(pidEmulated, 0, "process_name", "emulated time")
+	t.meta(pidHost, 0, "process_name", "host time")
+
+	mu.Lock()
+	cur = t
+	mu.Unlock()
+	atomic.StoreInt32(&enabled, 1)
+	return nil
+}
+
+// Stop ends tracing, and closes the trace file
+func Stop() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if cur == nil {
+		return nil
+	}
+	atomic.StoreInt32(&enabled, 0)
+
+	t := cur
+	cur = nil
+	t.w.WriteString("\n]\n")
+	if err := t.w.Flush(); err != nil {
+		t.f.Close()
+		return err
+	}
+	return t.f.Close()
+}
+
+func (t *tracer) write(ev *event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	if t.nev > 0 {
+		t.w.WriteString(",\n")
+	}
+	t.w.Write(data)
+	t.nev++
+}
+
+func (t *tracer) meta(pid, tid int, name string, value string) {
+	t.write(&event{Name: name, Ph: "M", Pid: pid, Tid: tid, Args: Args{"name": value}})
+}
+
+// track returns the thread id of the specified track, allocating it (and
+// naming it) the first time it's used
+func (t *tracer) track(pid int, name string) int {
+	key := [2]interface{}{pid, name}
+	tid, ok := t.tracks[key]
+	if !ok {
+		tid = len(t.tracks) + 1
+		t.tracks[key] = tid
+		t.meta(pid, tid, "thread_name", name)
+	}
+	return tid
+}
+
+func (t *tracer) usec(cycles int64) float64 {
+	return float64(cycles) * 1e6 / t.freq
+}
+
+func emit(pid int, track string, ev *event) {
+	mu.Lock()
+	if cur != nil {
+		ev.Pid, ev.Tid = pid, cur.track(pid, track)
+		cur.write(ev)
+	}
+	mu.Unlock()
+}
+
+// now returns the current emulated time in microseconds
+func now() float64 {
+	mu.Lock()
+	defer mu.Unlock()
+	if cur == nil {
+		return 0
+	}
+	return cur.usec(cur.clock())
+}
+
+// Instant records an instantaneous event at the current emulated time
+func Instant(track string, name string, args Args) {
+	emit(pidEmulated, track, &event{Name: name, Ph: "i", Ts: now(), S: "t", Args: args})
+}
+
+// Complete records an event that started at the specified emulated time
+// (in cycles) and ends now
+func Complete(track string, name string, begin int64, args Args) {
+	mu.Lock()
+	if cur == nil {
+		mu.Unlock()
+		return
+	}
+	ts, end := cur.usec(begin), cur.usec(cur.clock())
+	mu.Unlock()
+	emit(pidEmulated, track, &event{Name: name, Ph: "X", Ts: ts, Dur: end - ts, Args: args})
+}
+
+// Counter records the value of a counter at the current emulated time
+func Counter(name string, value int) {
+	emit(pidEmulated, name, &event{Name: name, Ph: "C", Ts: now(), Args: Args{"value": value}})
+}
+
+// HostComplete records an event on the host timeline, that started at the
+// specified time and ends now. It can be called from any goroutine.
+func HostComplete(track string, name string, begin time.Time, args Args) {
+	mu.Lock()
+	if cur == nil {
+		mu.Unlock()
+		return
+	}
+	ts := float64(begin.Sub(cur.start).Nanoseconds()) / 1e3
+	mu.Unlock()
+	dur := float64(time.Since(begin).Nanoseconds()) / 1e3
+	emit(pidHost, track, &event{Name: name, Ph: "X", Ts: ts, Dur: dur, Args: args})
+}
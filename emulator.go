@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"ndsemu/arm"
@@ -8,11 +9,16 @@ import (
 	"ndsemu/emu"
 	"ndsemu/emu/debugger"
 	"ndsemu/emu/gfx"
+	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"ndsemu/emu/trace"
+	"ndsemu/homebrew"
+	"ndsemu/patch"
 	"ndsemu/raster3d"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -32,6 +38,13 @@ type NDSMemory struct {
 	OamRam     [2048]byte            // object attribute RAM
 }
 
+// UserSettings returns the firmware user settings, at the address where the
+// firmware (or DirectBoot) copies them in main RAM (without the trailing
+// counter and CRC)
+func (mem *NDSMemory) UserSettings() []byte {
+	return mem.Ram[0x3FFC80 : 0x3FFC80+cFwUserSettingsCrc]
+}
+
 type NDSRom struct {
 	Bios9   []byte
 	Bios7   []byte
@@ -55,9 +68,11 @@ type NDSHardware struct {
 	Pow  *HwPowerMan
 	Key  *HwKey
 	Snd  *HwSound
+	Apu  *HwGbaSound // GBA sound (only reachable in GBA mode)
 	Geom *HwGeometry
 	Bkp  *HwBackupRam
 	Sl2  *HwSlot2
+	Uart *HwUart
 }
 
 type NDSEmulator struct {
@@ -67,44 +82,123 @@ type NDSEmulator struct {
 	Sync *emu.Sync
 	Mode EmuMode
 
+	// Cheats for the current game (nil if none loaded)
+	Cheats *CheatList
+
+	// Patches of guest code for the current game (nil if none loaded)
+	HotPatches *HotPatchSet
+
+	// Memory locations frozen to a fixed value
+	Freeze FreezeList
+
+	// Detection of guest hangs
+	Watchdog Watchdog
+
 	dbg        *debugger.Debugger
+	rewind     *Rewind // history for reverse execution (nil if disabled)
+	layout     ScreenLayout
 	screen     gfx.Buffer
-	audio      []int16
+	native     gfx.Buffer // native-resolution screens, for non-direct layouts
 	framecount int
 	powcnt     uint32
+	sleeping   bool // see Sleep
+	lidClosed  bool // lid state at the end of last frame
 
 	switchingToGba bool
+
+	// Configuration re-applied when the hardware is recreated on reset
+	jit           bool
+	jitThreshold  int // see SetJitThreshold
+	jitCacheSize  int // see SetJitCacheLimit
+	jitCacheFlush bool
+	busErrorBreak bool
+	ideasDebug    bool
+	bios7Hle      bool
+	soundQuality  SoundQuality
+	touchRaw      bool
+	consoleType   ConsoleType // see SetConsoleType
+	audioDump     *AudioDump
+	gameDb        GameDb
+	forcedQuirks  Quirks
+	forcedChipID  *[4]byte // see SetChipID
+	quirks        Quirks   // quirks enabled for the current game
+	palDir        string   // palette exports (see SetPaletteDir)
+	directBoot    bool     // see SetBootMode
+	bootFirmware  string
+	arm7HleGames  string
+
+	// Host time spent in 2D (nil if not measured, see PerfHud)
+	perf *PerfCounters
+
+	// Current scanline, and when it started (for tracing)
+	traceLine      int
+	traceLineStart int64
 }
 
 var Emu *NDSEmulator
 
-func NewNDSHardware(mem *NDSMemory, firmware string, dojit bool) *NDSHardware {
+func NewNDSHardware(mem *NDSMemory, sync *emu.Sync, firmware string, dojit bool) *NDSHardware {
+	return newNDSHardware(mem, NewNDSRom(), sync, dojit, nil)
+}
+
+// newNDSHardware creates all the devices in their power-on state. If old is
+// not nil, the devices that represent external media (cartridges, save
+// memory, firmware flash) and the battery-backed RTC are taken over from it
+// rather than created anew, so that they survive a console reset. Devices get
+// their dependencies (main RAM, the syncing system, the CPUs) from here,
+// rather than through the global Emu instance.
+func newNDSHardware(mem *NDSMemory, rom *NDSRom, sync *emu.Sync, dojit bool, old *NDSHardware) *NDSHardware {
 	hw := new(NDSHardware)
-	bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
 
-	nds9 = NewNDS9(dojit)
-	nds7 = NewNDS7(dojit)
+	nds9 = NewNDS9(dojit, sync)
+	nds7 = NewNDS7(dojit, sync)
 	hw.Mc = NewMemoryController(nds9, nds7, mem.Vram[:])
 	hw.E3d = raster3d.NewHwEngine3d()
+	if old != nil {
+		hw.E3d.TakeTexturePack(old.E3d)
+	}
 	hw.E2d[0] = e2d.NewHwEngine2d(0, hw.Mc, gfx.LayerFunc{Func: hw.E3d.Draw3D})
 	hw.E2d[1] = e2d.NewHwEngine2d(1, hw.Mc, nil)
-	hw.Lcd9 = NewHwLcd(nds9.Irq, &NdsLcdConfig)
-	hw.Lcd7 = NewHwLcd(nds7.Irq, &NdsLcdConfig)
-	hw.Ipc = NewHwIpc(nds9.Irq, nds7.Irq)
+	if old != nil {
+		hw.E2d[0].TakeSettings(old.E2d[0])
+		hw.E2d[1].TakeSettings(old.E2d[1])
+	}
+	hw.Lcd9 = NewHwLcd(nds9.Irq, &NdsLcdConfig, sync)
+	hw.Lcd7 = NewHwLcd(nds7.Irq, &NdsLcdConfig, sync)
+	hw.Ipc = NewHwIpc(nds9.Irq, nds7.Irq, sync)
 	hw.Div = NewHwDivisor()
-	hw.Rtc = NewHwRtc()
-	hw.Wifi = NewHwWifi()
-	hw.Bkp = NewHwBackupRam()
-	hw.Gc = NewGamecard(filepath.Join(bindir, "bios/biosnds7.rom"), hw.Bkp)
-	hw.Tsc = NewHwTouchScreen()
+	hw.Wifi = NewHwWifi(nds7.Irq)
+	hw.Uart = NewHwUart(nds7.Irq, &nds7.misc7.Rcnt.Value)
+	if old != nil {
+		hw.Rtc, hw.Bkp, hw.Ff, hw.Sl2 = old.Rtc, old.Bkp, old.Ff, old.Sl2
+		hw.Rtc.Reset()
+		hw.Bkp.Reset()
+		hw.Ff.Reset()
+		hw.Sl2.Reset()
+		hw.Wifi.Link = old.Wifi.Link
+		hw.Wifi.FakeAps = old.Wifi.FakeAps
+		hw.Wifi.DlHost = old.Wifi.DlHost
+		hw.Uart.Host = old.Uart.Host
+	} else {
+		hw.Rtc = NewHwRtc()
+		hw.Bkp = NewHwBackupRam(sync)
+		hw.Ff = NewHwFirmwareFlash()
+		hw.Sl2 = NewHwSlot2()
+	}
+	hw.Gc = NewGamecard(rom.Bios7, hw.Bkp, sync)
+	if old != nil {
+		hw.Gc.TakeCart(old.Gc)
+	}
+	hw.Mc.SetGamecard(hw.Gc)
+	hw.Tsc = NewHwTouchScreen(mem, sync)
 	hw.Key = NewHwKey()
-	hw.Snd = NewHwSound(nds7.Bus)
-	hw.Geom = NewHwGeometry(nds9.Irq, hw.E3d)
-	hw.Sl2 = NewHwSlot2()
+	hw.Snd = NewHwSound(nds7.Bus, sync)
+	hw.Apu = NewHwGbaSound(nds7.Dma[1:3], sync)
+	hw.Geom = NewHwGeometry(nds9, hw.E3d, sync)
 
 	hw.Spi = NewHwSpiBus()
-	hw.Ff = NewHwFirmwareFlash()
 	hw.Pow = NewHwPowerMan()
+	hw.Snd.Pow = hw.Pow
 	hw.Spi.AddDevice(0, hw.Pow)
 	hw.Spi.AddDevice(1, hw.Ff)
 	hw.Spi.AddDevice(2, hw.Tsc)
@@ -150,28 +244,32 @@ func NewNDSRom() *NDSRom {
 	return rom
 }
 
+// Sync registered as log context by the last emulator created
+var logSync *emu.Sync
+
 func NewNDSEmulator(firmware string, dojit bool) *NDSEmulator {
+	return newNDSEmulator(NewNDSRom(), dojit)
+}
+
+// newNDSEmulator creates an emulator running the specified BIOS images (tests
+// use it with synthetic ones)
+func newNDSEmulator(rom *NDSRom, dojit bool) *NDSEmulator {
 	mem := new(NDSMemory)
-	rom := NewNDSRom()
-	hw := NewNDSHardware(mem, firmware, dojit)
 
 	// Initialize syncing system
 	sync, err := emu.NewSync(NdsSyncConfig)
 	if err != nil {
 		panic(err)
 	}
-	sync.AddCpu(nds9, "arm9")
-	sync.AddCpu(nds7, "arm7")
-	sync.AddSubsystem(nds9.Timers, "timers9")
-	sync.AddSubsystem(nds7.Timers, "timers7")
-	sync.AddSubsystem(hw.Geom, "gx")
 
 	e := &NDSEmulator{
 		Mem:  mem,
-		Hw:   hw,
 		Rom:  rom,
 		Sync: sync,
 		Mode: ModeNds,
+		jit:  dojit,
+
+		layout: ScreenLayout{Gap: cScreenGapDefault},
 	}
 
 	// Set the hsync callback to this instance's function
@@ -179,18 +277,310 @@ func NewNDSEmulator(firmware string, dojit bool) *NDSEmulator {
 
 	// Register the syncer's logger as global logging function,
 	// so that everything will also log the current subsystem
-	// status (eg: CPU program counter)
+	// status (eg: CPU program counter). It replaces the one of the previous
+	// emulator, if any (eg: regress creates one per test).
+	if logSync != nil {
+		log.RemoveContext(logSync)
+	}
 	log.AddContext(e.Sync)
+	logSync = e.Sync
 
 	emu.BreakFunc = e.DebugBreak
 
+	e.initHardware(nil)
+	return e
+}
+
+// initHardware creates all the devices (see newNDSHardware), registers them
+// with the syncing system, initializes the memory map, and resets the CPUs.
+func (e *NDSEmulator) initHardware(old *NDSHardware) {
+	e.Hw = newNDSHardware(e.Mem, e.Rom, e.Sync, e.jit, old)
+	e.registerSubsystems()
+
+	// Let bus errors report which CPU (and PC) performed the access
+	for _, bus := range e.buses() {
+		bus.Accessor = e.Sync
+	}
+	e.SetBreakOnBusError(e.busErrorBreak)
+	e.SetJitThreshold(e.jitThreshold)
+	e.SetJitCacheLimit(e.jitCacheSize, e.jitCacheFlush)
+	e.Hw.Snd.Quality = e.soundQuality
+	e.Hw.Snd.Dump = e.audioDump
+	e.Hw.Tsc.Raw = e.touchRaw
+	e.Hw.Pow.Lite = e.consoleType.Lite()
+	e.applyQuirks()
+	e.applyPalettePatch()
+
+	if e.ideasDebug {
+		homebrew.ActivateIdeasDebug(nds9.Cpu)
+		homebrew.ActivateIdeasDebug(nds7.Cpu)
+	}
+	if e.bios7Hle {
+		ActivateBios7Hle(nds7.Cpu, ModeNds)
+	}
+
 	// Initialize the memory map and reset the CPUs
 	nds9.InitBus(e)
 	nds7.InitBus(e)
 	nds9.Reset()
 	nds7.Reset()
+}
 
-	return e
+// registerSubsystems (re)registers all the CPUs and subsystems with the
+// syncing system
+func (e *NDSEmulator) registerSubsystems() {
+	e.Sync.Clear()
+	if e.Mode == ModeGba {
+		// The ARM9 stays halted in GBA mode, and only the GBA devices
+		// are reachable by the ARM7
+		e.Sync.AddCpu(nds7, "arm7")
+		e.Sync.AddSubsystem(nds7.Timers, "timers7")
+		e.Sync.AddSubsystem(e.Hw.Apu, "apu")
+		return
+	}
+	e.Sync.AddCpu(nds9, "arm9")
+	if e.Hw.Ipc.Hle7 == nil {
+		e.Sync.AddCpu(nds7, "arm7")
+	}
+	e.Sync.AddSubsystem(nds9.Timers, "timers9")
+	e.Sync.AddSubsystem(nds7.Timers, "timers7")
+	e.Sync.AddSubsystem(e.Hw.Geom, "gx")
+	e.Sync.AddSubsystem(e.Hw.Snd, "spu")
+}
+
+// EnableArm7Hle replaces the emulation of the ARM7 with a high-level
+// emulation of the SDK running on it (see Arm7Hle). It must be called before
+// starting emulation, after the game was directly booted.
+func (e *NDSEmulator) EnableArm7Hle() {
+	e.Hw.Ipc.Hle7 = NewArm7Hle(e.Hw, e.Mem.Ram[:])
+	e.registerSubsystems()
+	log.ModEmu.WarnZ("EXPERIMENTAL: ARM7 HLE enabled").End()
+}
+
+// Reset restarts the console from the BIOS (and thus the firmware), without
+// restarting the host process. All the devices are recreated in their power-on
+// state and the memory is remapped; inserted cartridges, save memory, the
+// firmware flash and the RTC are kept, with their interface logic reset.
+//
+// A soft reset is equivalent to the reset combination / return to firmware,
+// and preserves the contents of memory. A hard reset is a power cycle, and
+// also clears memory.
+func (emu *NDSEmulator) Reset(hard bool) {
+	if hard {
+		*emu.Mem = NDSMemory{}
+	}
+
+	emu.Mode = ModeNds
+	emu.switchingToGba = false
+	emu.powcnt = 0
+	emu.sleeping = false
+	emu.lidClosed = false
+	emu.Watchdog.Reset()
+	if emu.HotPatches != nil {
+		emu.HotPatches.Reset()
+	}
+	if emu.Hw.Ipc.Hle7 != nil {
+		// The game must be directly booted for HLE, while reset goes
+		// through the BIOS
+		log.ModEmu.WarnZ("ARM7 HLE disabled by reset").End()
+	}
+
+	emu.Sync.SetConfig(NdsSyncConfig)
+	emu.initHardware(emu.Hw)
+
+	if emu.dbg != nil {
+		emu.dbg.SetCpus([]debugger.Cpu{nds7.Cpu, nds9.Cpu})
+		emu.dbg.SetIrqPending(0, nds7.Irq.Pending)
+		emu.dbg.SetIrqPending(1, nds9.Irq.Pending)
+	}
+
+	log.ModEmu.WarnZ("console reset").Bool("hard", hard).End()
+}
+
+// RemoveCart pulls out the cartridge inserted in the specified slot (1 or 2)
+// while the console is running, to test how games react to it.
+func (emu *NDSEmulator) RemoveCart(slot int) error {
+	switch slot {
+	case 1:
+		emu.Hw.Gc.RemoveCart()
+	case 2:
+		emu.Hw.Sl2.RemoveCart()
+	default:
+		return fmt.Errorf("invalid slot: %d", slot)
+	}
+	return nil
+}
+
+// Shutdown must be called when the emulator exits, after the last frame was
+// emulated: it flushes the save memory and the firmware settings to disk, and
+// finalizes the audio dump (if any).
+func (emu *NDSEmulator) Shutdown() {
+	emu.StopAudioDump()
+	emu.Hw.Bkp.Close()
+	emu.Hw.Ff.Close()
+}
+
+// LoadRom switches to another ROM at runtime, without restarting the host
+// process: the current cartridges are removed (flushing their save memory),
+// the new ROM is inserted, and the console is hard-reset to boot it. NDS ROMs
+// are inserted in slot-1 (with their save file), homebrew ROMs in both slots
+// (like PassMe does), and GBA ROMs in slot-2 (booting directly in GBA mode).
+// A patch next to a NDS ROM (see patch.Find) is applied automatically, and so
+// are hot-patches (see FindHotPatches).
+//
+// Global settings (layout, input, debugging options) are preserved; cheats
+// and hot-patches are dropped, as they are specific to the previous game.
+func (emu *NDSEmulator) LoadRom(fn string) error {
+	return emu.loadRom(fn, fn+".sav")
+}
+
+// loadRom is like LoadRom, but uses the specified save file for NDS ROMs
+func (emu *NDSEmulator) loadRom(fn string, savefn string) error {
+	hbrew, err := homebrew.Detect(fn)
+	if err != nil {
+		return err
+	}
+	ext := filepath.Ext(fn)
+	isNds, isGba := strings.EqualFold(ext, ".nds"), strings.EqualFold(ext, ".gba")
+	if !hbrew && !isNds && !isGba {
+		return fmt.Errorf("unrecognized ROM type: %q", fn)
+	}
+
+	// Remove the current cartridges
+	emu.Hw.Bkp.Close()
+	emu.Hw.Bkp = NewHwBackupRam(emu.Sync)
+	emu.Hw.Gc.UnmapCart()
+	emu.Hw.Sl2.UnmapCart()
+	emu.ideasDebug = false
+	if emu.Cheats != nil {
+		log.ModEmu.WarnZ("cheats disabled after ROM switch").End()
+		emu.Cheats = nil
+	}
+	emu.HotPatches = nil
+
+	switch {
+	case hbrew:
+		if err = emu.Hw.Sl2.MapCartFile(fn); err == nil {
+			err = emu.Hw.Gc.MapCartFile(fn)
+		}
+		emu.ideasDebug = true
+	case isNds:
+		var patches []string
+		if p := patch.Find(fn); p != "" {
+			patches = append(patches, p)
+		}
+		if err = emu.Hw.Gc.MapCartPatchedFile(fn, patches); err == nil {
+			err = emu.Hw.Bkp.MapSaveFile(savefn)
+		}
+	default:
+		err = emu.Hw.Sl2.MapCartFile(fn)
+	}
+
+	// Reboot even if the ROM could not be inserted, so that the console is
+	// left in a consistent (empty) state
+	emu.Reset(true)
+	if err != nil {
+		return err
+	}
+	if err := emu.Boot(isGba && !hbrew); err != nil {
+		return err
+	}
+	if hpfn := FindHotPatches(fn); hpfn != "" && isNds {
+		hs, err := LoadHotPatches(hpfn)
+		if err == nil {
+			err = emu.SetHotPatches(hs)
+		}
+		if err != nil {
+			log.ModEmu.WarnZ("cannot load hot-patches").Error("err", err).End()
+		}
+	}
+	log.ModEmu.WarnZ("ROM loaded").String("rom", fn).End()
+	return nil
+}
+
+// SetBootMode configures how games are started by Boot: with direct boot,
+// the BIOS and the firmware menu are skipped (see DirectBoot, which also
+// needs the firmware file). arm7Hle selects the games whose ARM7 is emulated
+// at high level (see MatchArm7Hle); it requires direct boot.
+func (emu *NDSEmulator) SetBootMode(direct bool, firmware string, arm7Hle string) {
+	emu.directBoot = direct
+	emu.bootFirmware = firmware
+	emu.arm7HleGames = arm7Hle
+}
+
+// Boot starts the game just inserted (in slot 2 if gba is true, otherwise
+// in slot 1), after a hard reset, according to the boot mode (see
+// SetBootMode). It's used both at startup and when switching ROM, so that
+// they behave the same.
+func (emu *NDSEmulator) Boot(gba bool) error {
+	if gba {
+		// GBA ROMs boot straight into GBA mode (through the GBA BIOS,
+		// unless skipped), without going through the firmware menu
+		return emu.BootGba(emu.directBoot)
+	}
+	if emu.directBoot {
+		if err := emu.DirectBoot(emu.bootFirmware); err != nil {
+			return err
+		}
+	}
+	if emu.arm7HleGames != "" {
+		var gamecode [4]byte
+		emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+		if MatchArm7Hle(emu.arm7HleGames, string(gamecode[:])) {
+			if !emu.directBoot {
+				return errors.New("ARM7 HLE requires direct boot (-s)")
+			}
+			emu.EnableArm7Hle()
+		}
+	}
+	return nil
+}
+
+// ActivateIdeasDebug enables the IDEAS-compatible debug output on both CPUs,
+// used by homebrew ROMs (see homebrew.ActivateIdeasDebug).
+func (emu *NDSEmulator) ActivateIdeasDebug() {
+	emu.ideasDebug = true
+	homebrew.ActivateIdeasDebug(nds9.Cpu)
+	homebrew.ActivateIdeasDebug(nds7.Cpu)
+}
+
+// ActivateBios7Hle replaces the sound-related ARM7 BIOS calls with a
+// high-level emulation (listed in the ActivateBios7Hle function), for the
+// current mode. The setting survives resets, and the calls are switched to
+// their GBA numbers when the console enters GBA mode.
+func (emu *NDSEmulator) ActivateBios7Hle() {
+	emu.bios7Hle = true
+	ActivateBios7Hle(nds7.Cpu, emu.Mode)
+}
+
+// SetLayout changes the placement of the screens within the framebuffer
+// passed to RunOneFrame.
+func (emu *NDSEmulator) SetLayout(l ScreenLayout) {
+	emu.layout = l
+	if !l.Direct() && emu.native.Width == 0 {
+		emu.native = gfx.NewBufferMem(cScreenWidth, cScreenHeight*2)
+	}
+}
+
+func (emu *NDSEmulator) Layout() ScreenLayout {
+	return emu.layout
+}
+
+// SetTouchRaw disables the interpolation of the pen position between frames
+// (see HwTouchScreen).
+func (emu *NDSEmulator) SetTouchRaw(raw bool) {
+	emu.touchRaw = raw
+	emu.Hw.Tsc.Raw = raw
+}
+
+// Screens returns the two screens drawn in the last frame, at native
+// resolution. The buffers refer to the memory where the screens are drawn,
+// so they are only valid until the next frame is run.
+func (emu *NDSEmulator) Screens() (top, bottom gfx.Buffer) {
+	if emu.layout.Direct() {
+		return emu.layout.Screens(emu.screen)
+	}
+	return NativeScreens(emu.native)
 }
 
 func (emu *NDSEmulator) SwitchToGba() {
@@ -198,16 +588,22 @@ func (emu *NDSEmulator) SwitchToGba() {
 }
 
 func (emu *NDSEmulator) switchToGba() {
-	// Create new sync with GBA timings and without ARM9
 	emu.Mode = ModeGba
 	nds7.InitBusGba(emu)
 	emu.Hw.Lcd7.Cfg = &GbaLcdConfig
+	if emu.bios7Hle {
+		ActivateBios7Hle(nds7.Cpu, ModeGba)
+	}
 
-	// Reconfigure sync
+	// Reconfigure sync, with GBA timings and without ARM9
 	emu.Sync.SetConfig(GbaSyncConfig)
 	GbaSyncConfig.HSync = emu.hsync
+	emu.registerSubsystems()
 	emu.Sync.Reset()
 
+	// Timers 0 and 1 clock the sound FIFOs
+	nds7.Timers.Overflow = emu.Hw.Apu.TimerOverflow
+
 	// Reconfigure graphic engine
 	mc := &GbaMemCnt{Bus: nds7.Bus}
 	emu.Hw.E2d[0].SetHwType(e2d.HwGba, mc)
@@ -219,12 +615,29 @@ func (emu *NDSEmulator) switchToGba() {
 	log.ModEmu.WarnZ("switched to GBA").End()
 }
 
-func (emu *NDSEmulator) StartDebugger() {
+// StartDebugger starts the interactive debugger. If heatmap is not empty,
+// memory accesses are also profiled, and saved to the specified CSV file.
+// Similarly, if coverage is not empty, code/data coverage is tracked and
+// saved to files with the specified name (and CPU name as suffix).
+func (emu *NDSEmulator) StartDebugger(heatmap string, coverage string) {
+	cpunames := []string{"arm7", "arm9"}
 	emu.dbg = debugger.New([]debugger.Cpu{nds7.Cpu, nds9.Cpu}, emu.Sync)
+	emu.dbg.SetIrqPending(0, nds7.Irq.Pending)
+	emu.dbg.SetIrqPending(1, nds9.Irq.Pending)
+	if heatmap != "" {
+		emu.dbg.EnableHeatmap(heatmap, cpunames)
+	}
+	if coverage != "" {
+		emu.dbg.EnableCoverage(coverage, cpunames)
+	}
 
 	type DebugConfig struct {
 		Breakpoints []string
 		Watchpoints []string
+		Freeze      []string
+		Symbols     map[string]string // cpu name => no$gba .sym file
+		Catch       []string          // exceptions to break on (see parseCatchpoint)
+		CatchLog    []string          // exceptions to log (see parseCatchpoint)
 	}
 
 	cfg := &DebugConfig{}
@@ -247,11 +660,82 @@ func (emu *NDSEmulator) StartDebugger() {
 				log.ModEmu.WithField("watch", fmt.Sprintf("0x%08x", uint32(b))).Warnf("add watchpoint")
 			}
 		}
+		for _, spec := range cfg.Freeze {
+			if fe, err := ParseFreezeEntry(spec); err != nil {
+				log.ModEmu.WithField("error", err).Fatalf("invalid freeze %q", spec)
+			} else {
+				emu.Freeze.Add(fe)
+			}
+		}
+		for i, specs := range [][]string{cfg.Catch, cfg.CatchLog} {
+			for _, spec := range specs {
+				if c, err := parseCatchpoint(spec); err != nil {
+					log.ModEmu.WithField("error", err).Fatalf("invalid catchpoint %q", spec)
+				} else {
+					c.LogOnly = i == 1
+					emu.dbg.AddCatchpoint(c)
+				}
+			}
+		}
+		for idx, name := range cpunames {
+			if fn, ok := cfg.Symbols[name]; ok {
+				if err := emu.dbg.LoadSymbols(idx, fn); err != nil {
+					log.ModEmu.WithField("error", err).Warnf("error loading symbols")
+				}
+			}
+		}
 	}
 
 	go emu.dbg.Run()
 }
 
+// EnableRewind enables reverse execution in the debugger, keeping the
+// specified number of frames of history (see Rewind). It must be called
+// after StartDebugger.
+func (emu *NDSEmulator) EnableRewind(frames int) {
+	emu.rewind = NewRewind(emu.dbg, frames)
+	emu.Hw.Rtc.Clock = emu.rewind.rtcClock
+	emu.dbg.SetRewinder(emu.rewind)
+}
+
+// parseCatchpoint parses a catchpoint specification, in the format
+// EXCEPTION[:FILTER]. For SWIs, the filter is the SWI number; for IRQs, it is
+// the IRQ source (name or mask, see ParseIrqType). Examples: "swi:0x0b",
+// "irq:vblank", "undefined".
+func parseCatchpoint(spec string) (debugger.Catchpoint, error) {
+	c := debugger.Catchpoint{Swi: -1}
+	name, filter := spec, ""
+	if idx := strings.IndexByte(spec, ':'); idx >= 0 {
+		name, filter = spec[:idx], spec[idx+1:]
+	}
+
+	var err error
+	if c.Exc, err = debugger.ParseExcKind(name); err != nil {
+		return c, err
+	}
+	if filter == "" {
+		return c, nil
+	}
+
+	switch c.Exc {
+	case debugger.ExcSwi:
+		num, err := strconv.ParseUint(filter, 0, 8)
+		if err != nil {
+			return c, fmt.Errorf("invalid SWI number: %q", filter)
+		}
+		c.Swi = int(num)
+	case debugger.ExcIrq:
+		irq, err := ParseIrqType(filter)
+		if err != nil {
+			return c, err
+		}
+		c.Irq = uint32(irq)
+	default:
+		return c, fmt.Errorf("filter not supported for %v", c.Exc)
+	}
+	return c, nil
+}
+
 func (emu *NDSEmulator) DebugBreak(msg string) {
 	if emu.dbg != nil {
 		emu.dbg.Break(msg)
@@ -261,17 +745,69 @@ func (emu *NDSEmulator) DebugBreak(msg string) {
 	}
 }
 
-func (emu *NDSEmulator) eaOn() bool       { return emu.powcnt&(1<<1) != 0 }
-func (emu *NDSEmulator) ebOn() bool       { return emu.powcnt&(1<<9) != 0 }
+func (emu *NDSEmulator) buses() []*hwio.Table {
+	return []*hwio.Table{nds9.Bus, nds7.Bus, emu.Hw.Mc.GpuBus}
+}
+
+// SetBreakOnBusError configures whether invalid bus accesses (unmapped
+// addresses, writes to ROM) break into the debugger, in addition to
+// being logged.
+func (emu *NDSEmulator) SetBreakOnBusError(enable bool) {
+	emu.busErrorBreak = enable
+	var fn func(err *hwio.BusError)
+	if enable {
+		fn = func(err *hwio.BusError) { emu.DebugBreak(err.Error()) }
+	}
+	for _, bus := range emu.buses() {
+		bus.ErrorFunc = fn
+	}
+}
+
+// SetJitThreshold configures how many times a block of code is interpreted
+// before being compiled by the JIT, on both CPUs (0 restores the default).
+// It has no effect if the JIT is disabled.
+func (emu *NDSEmulator) SetJitThreshold(n int) {
+	emu.jitThreshold = n
+	for _, cpu := range []*arm.Cpu{nds9.Cpu, nds7.Cpu} {
+		if jit := cpu.Jit(); jit != nil {
+			jit.SetHotThreshold(n)
+		}
+	}
+}
+
+// SetJitCacheLimit configures the maximum size in bytes of the JIT code cache
+// of each CPU (0 = unlimited), and whether the whole cache is flushed when
+// it's full, instead of evicting the least recently used code.
+func (emu *NDSEmulator) SetJitCacheLimit(size int, flush bool) {
+	emu.jitCacheSize, emu.jitCacheFlush = size, flush
+	for _, cpu := range []*arm.Cpu{nds9.Cpu, nds7.Cpu} {
+		if jit := cpu.Jit(); jit != nil {
+			jit.SetCacheLimit(size, flush)
+		}
+	}
+}
+
+// In GBA mode, only engine B is connected to the ARM7 (see InitBusGba), and
+// it is always drawing
+func (emu *NDSEmulator) eaOn() bool       { return emu.Mode == ModeNds && emu.powcnt&(1<<1) != 0 }
+func (emu *NDSEmulator) ebOn() bool       { return emu.Mode == ModeGba || emu.powcnt&(1<<9) != 0 }
 func (emu *NDSEmulator) lcdSwapped() bool { return emu.powcnt&(1<<15) != 0 }
 
 func (emu *NDSEmulator) hsync(x, y int) {
+	emu.Watchdog.Sample(nds9.Cpu, nds7.Cpu)
 	emu.Hw.Lcd9.SyncEvent(x, y)
 	emu.Hw.Lcd7.SyncEvent(x, y)
 
 	cfg := emu.Hw.Lcd7.Cfg
 
+	if x == 0 && trace.Enabled() {
+		emu.traceScanline(y)
+	}
+
 	if y == 0 && x == 0 {
+		if emu.Mode == ModeGba {
+			emu.clearScreens()
+		}
 		if emu.eaOn() {
 			emu.Hw.E2d[0].BeginFrame()
 		}
@@ -282,9 +818,13 @@ func (emu *NDSEmulator) hsync(x, y int) {
 
 	if y < cfg.VBlankFirstLine {
 		if x == 0 {
+			t0 := emu.perf.start()
 			emu.beginLine(y)
+			emu.perf.stop(perf2d, t0)
 		} else if x == cfg.HBlankFirstDot {
+			t0 := emu.perf.start()
 			emu.endLine(y)
+			emu.perf.stop(perf2d, t0)
 
 			// Trigger the DMA hblank event (only in visible part of screen)
 			if emu.Mode == ModeNds {
@@ -308,6 +848,9 @@ func (emu *NDSEmulator) hsync(x, y int) {
 			emu.Hw.E2d[1].EndFrame()
 		}
 		emu.Hw.E3d.EndFrame()
+		if emu.Hw.Ipc.Hle7 != nil {
+			emu.Hw.Ipc.Hle7.VBlank()
+		}
 	}
 
 	// 3D starts at scanline 214, before VBlank end. This is useful for us too, as we
@@ -316,24 +859,13 @@ func (emu *NDSEmulator) hsync(x, y int) {
 		emu.Hw.E3d.SetVram(emu.Hw.Mc.VramTextureBank(), emu.Hw.Mc.VramTexturePaletteBank())
 		emu.Hw.E3d.BeginFrame()
 	}
-
-	// Per-line audio emulation
-	if x == 0 && emu.Hw.Pow.AudioEnabled() && emu.Mode == ModeNds {
-		nsamples := len(emu.audio) / 2
-		n0 := (nsamples * y)
-		n1 := (nsamples * (y + 1))
-		if emu.Mode == ModeNds {
-			n0 /= 263
-			n1 /= 263
-		} else {
-			n0 /= 228
-			n1 /= 228
-		}
-		emu.Hw.Snd.RunOneFrame(emu.audio[n0*2 : n1*2])
-	}
 }
 
 func (emu *NDSEmulator) RunOneFrame(screen gfx.Buffer, audio []int16) bool {
+	if emu.rewind != nil {
+		emu.rewind.BeginFrame(emu)
+	}
+
 	// Save powcnt for this frame; letting it change within a frame isn't
 	// really necessary and it's hard to handle with our parallel system
 	emu.powcnt = nds9.misc.PowCnt.Value
@@ -346,9 +878,32 @@ func (emu *NDSEmulator) RunOneFrame(screen gfx.Buffer, audio []int16) bool {
 	log.ModGfx.InfoZ("begin frame").String("up", up).String("down", down).End()
 
 	emu.screen = screen
-	emu.audio = audio
-	emu.Sync.RunOneFrame()
-	emu.audio = nil
+	if emu.Mode == ModeNds {
+		emu.pollExternalIrqs()
+	}
+	sleeping := emu.sleeping && emu.sleepFrame(audio)
+	if !sleeping {
+		emu.runFrame(audio)
+	}
+	if emu.Cheats != nil && emu.Mode == ModeNds {
+		emu.Cheats.Run(nds9.Bus)
+	}
+	if emu.HotPatches != nil && emu.Mode == ModeNds {
+		emu.HotPatches.Apply()
+	}
+	if emu.Mode == ModeNds {
+		emu.Freeze.Apply(nds9.Bus)
+	} else {
+		emu.Freeze.Apply(nds7.Bus)
+	}
+	if !emu.layout.Direct() {
+		emu.layout.Compose(screen, emu.native)
+	}
+	emu.Hw.Wifi.Poll()
+	emu.Hw.Uart.Poll()
+	if !sleeping {
+		emu.Watchdog.EndFrame(emu)
+	}
 	emu.framecount++
 
 	if emu.switchingToGba {
@@ -356,22 +911,94 @@ func (emu *NDSEmulator) RunOneFrame(screen gfx.Buffer, audio []int16) bool {
 		emu.switchToGba()
 		emu.switchingToGba = false
 	}
+	if emu.rewind != nil {
+		emu.rewind.EndFrame(emu)
+	}
 
 	return emu.Hw.Pow.PowerOff()
 }
 
+// runFrame emulates the hardware for one frame
+func (emu *NDSEmulator) runFrame(audio []int16) {
+	if emu.Mode == ModeNds {
+		cfg := NdsSyncConfig
+		emu.Hw.Snd.BeginFrame(audio, int64(cfg.HDots*cfg.VDots*cfg.DotClockDivider))
+	} else {
+		cfg := GbaSyncConfig
+		emu.Hw.Apu.BeginFrame(audio, int64(cfg.HDots*cfg.VDots*cfg.DotClockDivider))
+	}
+	start := emu.Sync.Cycles()
+	emu.Sync.RunOneFrame()
+	emu.Hw.Snd.EndFrame()
+	emu.Hw.Apu.EndFrame()
+	if emu.dbg != nil {
+		emu.dbg.AddAudioFrame(audio)
+	}
+	if trace.Enabled() {
+		trace.Complete("frames", fmt.Sprintf("frame %d", emu.framecount), start, nil)
+	}
+}
+
+// traceScanline records the scanline that just ended in the trace, together
+// with a sample of the level of the FIFOs
+func (emu *NDSEmulator) traceScanline(y int) {
+	now := emu.Sync.Cycles()
+	if now > emu.traceLineStart {
+		trace.Complete("scanlines", fmt.Sprintf("line %d", emu.traceLine), emu.traceLineStart, nil)
+	}
+	emu.traceLine, emu.traceLineStart = y, now
+
+	trace.Counter("gxfifo", emu.Hw.Geom.fifo.Len())
+	trace.Counter("ipcfifo9", len(emu.Hw.Ipc.data[CpuNds9].fifo))
+	trace.Counter("ipcfifo7", len(emu.Hw.Ipc.data[CpuNds7].fifo))
+}
+
+// screenLine returns the line of the frame where line y of the specified
+// screen must be drawn
+func (emu *NDSEmulator) screenLine(bottom bool, y int) gfx.Line {
+	if emu.layout.Direct() {
+		return emu.layout.Line(emu.screen, bottom, y)
+	}
+	return NativeLine(emu.native, bottom, y)
+}
+
+// Position of the GBA screen, centered within a NDS screen
+const (
+	cGbaScreenX = (cScreenWidth - 240) / 2
+	cGbaScreenY = (cScreenHeight - 160) / 2
+)
+
+// clearScreens blanks both screens. In GBA mode, only the middle of one
+// screen is drawn: the other one is turned off, and the border is black.
+func (emu *NDSEmulator) clearScreens() {
+	for _, bottom := range []bool{false, true} {
+		for y := 0; y < cScreenHeight; y++ {
+			line := emu.screenLine(bottom, y)
+			for x := 0; x < cScreenWidth; x++ {
+				line.Set32(x, 0)
+			}
+		}
+	}
+}
+
 func (emu *NDSEmulator) beginLine(y int) {
-	ya := y + 192 + 90
-	yb := y
-	if emu.lcdSwapped() {
-		ya, yb = yb, ya
+	// Engine A is on the bottom screen, unless swapped
+	abottom := !emu.lcdSwapped()
+
+	if emu.Mode == ModeGba {
+		// The GBA screen takes the place of engine A, as selected by the
+		// firmware (see BootGba)
+		line := emu.screenLine(abottom, y+cGbaScreenY)
+		line.Add32(cGbaScreenX)
+		emu.Hw.E2d[1].BeginLine(y, line)
+		return
 	}
 
 	if emu.eaOn() {
-		emu.Hw.E2d[0].BeginLine(y, emu.screen.Line(ya))
+		emu.Hw.E2d[0].BeginLine(y, emu.screenLine(abottom, y))
 	}
 	if emu.ebOn() {
-		emu.Hw.E2d[1].BeginLine(y, emu.screen.Line(yb))
+		emu.Hw.E2d[1].BeginLine(y, emu.screenLine(!abottom, y))
 	}
 }
 
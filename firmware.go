@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/binary"
 	log "ndsemu/emu/logger"
 	"ndsemu/emu/spi"
 	"os"
+
+	"github.com/howeyc/crc16"
 )
 
 var modFw = log.NewModule("firmware")
@@ -22,6 +25,11 @@ type HwFirmwareFlash struct {
 
 	wbuf []byte
 	addr uint32
+
+	// Language that replaces the one in the user settings as they are
+	// read (see SetLanguage)
+	lang    Language
+	hasLang bool
 }
 
 func NewHwFirmwareFlash() *HwFirmwareFlash {
@@ -37,6 +45,71 @@ func (ff *HwFirmwareFlash) MapFirmwareFile(fn string) error {
 	return nil
 }
 
+// SetLanguage changes the language of the user settings seen through the
+// flash, without modifying the firmware file: the valid copies of the
+// settings are patched (and their CRCs recomputed) each time they are read.
+// Settings written by the firmware menu are still stored in the file, but
+// the language they contain is overridden as well.
+func (ff *HwFirmwareFlash) SetLanguage(lang Language) {
+	ff.lang, ff.hasLang = lang, true
+}
+
+// ReadAt reads the contents of the flash, including the changes made by
+// SetLanguage
+func (ff *HwFirmwareFlash) ReadAt(buf []byte, off int64) (int, error) {
+	n, err := ff.f.ReadAt(buf, off)
+	if !ff.hasLang {
+		return n, err
+	}
+	offs, oerr := fwUserSettingsOffsets(ff.f)
+	if oerr != nil {
+		return n, err
+	}
+	end := off + int64(n)
+	for _, uoff := range offs {
+		if uoff >= end || uoff+cFwUserSettingsSize <= off {
+			continue
+		}
+		data := make([]byte, cFwUserSettingsSize)
+		if _, err := ff.f.ReadAt(data, uoff); err != nil {
+			continue
+		}
+		if ^crc16.ChecksumIBM(data[:cFwUserSettingsCrc]) != binary.LittleEndian.Uint16(data[0x72:]) {
+			continue
+		}
+		us := DecodeUserSettings(data)
+		us.Language = ff.lang
+		us.Encode(data)
+
+		lo, hi := uoff, uoff+cFwUserSettingsSize
+		if lo < off {
+			lo = off
+		}
+		if hi > end {
+			hi = end
+		}
+		copy(buf[lo-off:hi-off], data[lo-uoff:])
+	}
+	return n, err
+}
+
+// Close releases the firmware file. A write command still in progress is
+// discarded, like a power loss in the middle of a page program would do.
+func (ff *HwFirmwareFlash) Close() {
+	if ff.f != nil {
+		ff.f.Close()
+		ff.f = nil
+	}
+	ff.wbuf = nil
+}
+
+// Reset aborts any pending command, as happens when the console is reset
+func (ff *HwFirmwareFlash) Reset() {
+	ff.wen = false
+	ff.wbuf = nil
+	ff.addr = 0
+}
+
 func (ff *HwFirmwareFlash) SpiBegin() {
 	ff.addr = 0
 	ff.wbuf = nil
@@ -60,7 +133,7 @@ func (ff *HwFirmwareFlash) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 		}
 
 		buf := make([]byte, 1024)
-		ff.f.ReadAt(buf, int64(ff.addr))
+		ff.ReadAt(buf, int64(ff.addr))
 		ff.addr += 1024
 		return buf, spi.ReqContinue
 	case FFCodeRdsr:
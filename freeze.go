@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"ndsemu/emu/hwio"
+	"strconv"
+	"strings"
+)
+
+// FreezeEntry describes a memory location that is forced to a fixed value
+type FreezeEntry struct {
+	Addr  uint32
+	Size  int // 8, 16 or 32
+	Value uint32
+}
+
+func (fe FreezeEntry) String() string {
+	return fmt.Sprintf("%08X/%d=%X", fe.Addr, fe.Size, fe.Value)
+}
+
+// ParseFreezeEntry parses a freeze specification in the form
+// "ADDR[/SIZE]=VALUE", where ADDR and VALUE are hexadecimal and SIZE is
+// the access size in bits (8, 16 or 32; default is 32).
+func ParseFreezeEntry(spec string) (FreezeEntry, error) {
+	fe := FreezeEntry{Size: 32}
+
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return fe, fmt.Errorf("invalid freeze specification %q (use ADDR[/SIZE]=VALUE)", spec)
+	}
+	addr := parts[0]
+	if i := strings.IndexByte(addr, '/'); i >= 0 {
+		size, err := strconv.Atoi(addr[i+1:])
+		if err != nil || (size != 8 && size != 16 && size != 32) {
+			return fe, fmt.Errorf("invalid freeze size in %q", spec)
+		}
+		fe.Size = size
+		addr = addr[:i]
+	}
+
+	a, err := strconv.ParseUint(strings.TrimPrefix(addr, "0x"), 16, 32)
+	if err != nil {
+		return fe, fmt.Errorf("invalid freeze address in %q", spec)
+	}
+	v, err := strconv.ParseUint(strings.TrimPrefix(parts[1], "0x"), 16, fe.Size)
+	if err != nil {
+		return fe, fmt.Errorf("invalid freeze value in %q", spec)
+	}
+	fe.Addr, fe.Value = uint32(a), uint32(v)
+	return fe, nil
+}
+
+// FreezeList is a list of memory locations that are rewritten with a fixed
+// value after each frame. It is a quick alternative to AR codes for
+// experiments, and is configured via command line, debug.ini or the control
+// socket (see Control).
+type FreezeList struct {
+	entries []FreezeEntry
+}
+
+// Add adds a location to the list, replacing any previous entry for the
+// same address.
+func (fl *FreezeList) Add(fe FreezeEntry) {
+	fl.Remove(fe.Addr)
+	fl.entries = append(fl.entries, fe)
+	modCheat.InfoZ("freeze address").Stringer("entry", fe).End()
+}
+
+// Remove removes the entry for the specified address, and reports whether
+// it was found.
+func (fl *FreezeList) Remove(addr uint32) bool {
+	for i, fe := range fl.entries {
+		if fe.Addr == addr {
+			fl.entries = append(fl.entries[:i], fl.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Entries returns the current list of frozen locations
+func (fl *FreezeList) Entries() []FreezeEntry {
+	return fl.entries
+}
+
+// Parse adds all the entries in a comma-separated list of specifications
+func (fl *FreezeList) Parse(specs string) error {
+	for _, spec := range strings.Split(specs, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		fe, err := ParseFreezeEntry(spec)
+		if err != nil {
+			return err
+		}
+		fl.Add(fe)
+	}
+	return nil
+}
+
+// Apply writes the frozen values into memory
+func (fl *FreezeList) Apply(bus *hwio.Table) {
+	for _, fe := range fl.entries {
+		Poke(bus, fe.Addr, fe.Size, fe.Value)
+	}
+}
+
+// Poke writes a single value to memory with the specified access size
+func Poke(bus *hwio.Table, addr uint32, size int, val uint32) {
+	switch size {
+	case 8:
+		bus.Write8(addr, uint8(val))
+	case 16:
+		bus.Write16(addr, uint16(val))
+	default:
+		bus.Write32(addr, val)
+	}
+}
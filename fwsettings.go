@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/howeyc/crc16"
+)
+
+// TouchCalibration is the touchscreen calibration stored in the firmware
+// user settings: two points, each with the ADC values measured when touching
+// it, and its screen coordinates.
+type TouchCalibration struct {
+	AdcX1, AdcY1 uint16
+	ScrX1, ScrY1 uint8
+	AdcX2, AdcY2 uint16
+	ScrX2, ScrY2 uint8
+}
+
+func (c TouchCalibration) String() string {
+	return fmt.Sprintf("%d,%d,%d,%d,%d,%d,%d,%d",
+		c.AdcX1, c.AdcY1, c.ScrX1, c.ScrY1, c.AdcX2, c.AdcY2, c.ScrX2, c.ScrY2)
+}
+
+// UserSettings are the fields of the firmware user settings that can be
+// changed in the firmware menu (see ReadFirmwareUserSettings).
+type UserSettings struct {
+	Nickname    string
+	Message     string
+	Color       uint8 // favorite color (0-15)
+	BirthMonth  uint8
+	BirthDay    uint8
+	Language    Language
+	Calibration TouchCalibration
+
+	// Languages supported by the console (0: unknown), only stored in the
+	// extended settings (see Encode)
+	Region Region
+}
+
+var userColorNames = [16]string{
+	"gray", "brown", "red", "pink", "orange", "yellow", "lime", "green",
+	"darkgreen", "seagreen", "turquoise", "blue", "darkblue", "purple", "violet", "magenta",
+}
+
+const (
+	cUserNicknameLen = 10
+	cUserMessageLen  = 26
+
+	cUserExtVersion = 1 // version of the extended settings (byte 0x74)
+
+	// The extended settings (0x74-0xFD, unused bytes set to 0xFF) are
+	// followed by their CRC
+	cFwUserSettingsExtCrc = 0xFE
+)
+
+func decodeUserString(data []byte, maxlen int, length uint8) string {
+	n := int(length)
+	if n > maxlen {
+		n = maxlen
+	}
+	chars := make([]uint16, n)
+	for i := range chars {
+		chars[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	return string(utf16.Decode(chars))
+}
+
+func encodeUserString(data []byte, maxlen int, s string) uint8 {
+	chars := utf16.Encode([]rune(s))
+	for i := 0; i < maxlen; i++ {
+		var c uint16
+		if i < len(chars) {
+			c = chars[i]
+		}
+		binary.LittleEndian.PutUint16(data[i*2:], c)
+	}
+	return uint8(len(chars))
+}
+
+// DecodeUserSettings extracts the user settings from a copy of the settings
+// block
+func DecodeUserSettings(data []byte) UserSettings {
+	us := UserSettings{
+		Color:      data[0x02] & 0xF,
+		BirthMonth: data[0x03],
+		BirthDay:   data[0x04],
+		Nickname:   decodeUserString(data[0x06:], cUserNicknameLen, data[0x1A]),
+		Message:    decodeUserString(data[0x1C:], cUserMessageLen, data[0x50]),
+		Language:   Language(data[0x64] & 7),
+		Calibration: TouchCalibration{
+			AdcX1: binary.LittleEndian.Uint16(data[0x58:]),
+			AdcY1: binary.LittleEndian.Uint16(data[0x5A:]),
+			ScrX1: data[0x5C],
+			ScrY1: data[0x5D],
+			AdcX2: binary.LittleEndian.Uint16(data[0x5E:]),
+			AdcY2: binary.LittleEndian.Uint16(data[0x60:]),
+			ScrX2: data[0x62],
+			ScrY2: data[0x63],
+		},
+	}
+	if len(data) >= cFwUserSettingsSize && data[0x74] == cUserExtVersion {
+		us.Language = Language(data[0x75] & 7)
+		us.Region = Region(binary.LittleEndian.Uint16(data[0x76:]))
+	}
+	return us
+}
+
+// Encode stores the user settings into a copy of the settings block, and
+// updates its CRC
+func (us *UserSettings) Encode(data []byte) {
+	data[0x02] = us.Color & 0xF
+	data[0x03] = us.BirthMonth
+	data[0x04] = us.BirthDay
+	data[0x1A] = encodeUserString(data[0x06:], cUserNicknameLen, us.Nickname)
+	data[0x50] = encodeUserString(data[0x1C:], cUserMessageLen, us.Message)
+	data[0x64] = data[0x64]&^7 | uint8(us.Language)
+
+	// Newer firmwares have extended settings, with their own CRC, where
+	// the language can also be chinese or korean (in which case the main
+	// field is set to english); they're created only when needed.
+	if data[0x74] == cUserExtVersion || us.Language > LangSpanish || us.Region != 0 {
+		if data[0x74] != cUserExtVersion {
+			data[0x74] = cUserExtVersion
+			binary.LittleEndian.PutUint16(data[0x76:], uint16(RegionWorld|1<<us.Language))
+			for i := 0x78; i < cFwUserSettingsExtCrc; i++ {
+				data[i] = 0xFF
+			}
+		}
+		data[0x75] = uint8(us.Language)
+		if us.Region != 0 {
+			binary.LittleEndian.PutUint16(data[0x76:], uint16(us.Region))
+		}
+		if us.Language > LangSpanish {
+			data[0x64] = data[0x64]&^7 | uint8(LangEnglish)
+		}
+		binary.LittleEndian.PutUint16(data[cFwUserSettingsExtCrc:], ^crc16.ChecksumIBM(data[0x74:cFwUserSettingsExtCrc]))
+	}
+
+	c := &us.Calibration
+	binary.LittleEndian.PutUint16(data[0x58:], c.AdcX1)
+	binary.LittleEndian.PutUint16(data[0x5A:], c.AdcY1)
+	data[0x5C], data[0x5D] = c.ScrX1, c.ScrY1
+	binary.LittleEndian.PutUint16(data[0x5E:], c.AdcX2)
+	binary.LittleEndian.PutUint16(data[0x60:], c.AdcY2)
+	data[0x62], data[0x63] = c.ScrX2, c.ScrY2
+
+	binary.LittleEndian.PutUint16(data[0x72:], ^crc16.ChecksumIBM(data[:cFwUserSettingsCrc]))
+}
+
+// Set changes a field of the user settings, parsing its value from a string
+// (in the same format used by "ndsemu settings edit"). Fields are nickname,
+// message, color, birthday, language and calibration.
+func (us *UserSettings) Set(field, value string) error {
+	switch field {
+	case "nickname", "message":
+		max := cUserNicknameLen
+		if field == "message" {
+			max = cUserMessageLen
+		}
+		if len(utf16.Encode([]rune(value))) > max {
+			return fmt.Errorf("%s too long (max %d characters)", field, max)
+		}
+		if field == "nickname" {
+			us.Nickname = value
+		} else {
+			us.Message = value
+		}
+
+	case "color":
+		value = strings.ToLower(value)
+		for i, name := range userColorNames {
+			if value == name || value == strconv.Itoa(i) {
+				us.Color = uint8(i)
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid color: %q (use 0-15 or one of: %s)", value, strings.Join(userColorNames[:], ", "))
+
+	case "birthday":
+		var m, d int
+		if n, _ := fmt.Sscanf(value, "%d-%d", &m, &d); n != 2 || m < 1 || m > 12 || d < 1 || d > 31 {
+			return fmt.Errorf("invalid birthday: %q (use MM-DD)", value)
+		}
+		us.BirthMonth, us.BirthDay = uint8(m), uint8(d)
+
+	case "language":
+		lang, err := ParseLanguage(value)
+		if err != nil {
+			return err
+		}
+		us.Language = lang
+
+	case "calibration":
+		var v [8]int
+		fields := strings.Split(value, ",")
+		invalid := fmt.Errorf("invalid calibration: %q (use adcx1,adcy1,scrx1,scry1,adcx2,adcy2,scrx2,scry2)", value)
+		if len(fields) != len(v) {
+			return invalid
+		}
+		for i, f := range fields {
+			n, err := strconv.Atoi(strings.TrimSpace(f))
+			if err != nil || n < 0 {
+				return invalid
+			}
+			v[i] = n
+		}
+		for _, i := range []int{0, 1, 4, 5} {
+			if v[i] > 0xFFF {
+				return invalid
+			}
+		}
+		if v[2] > 255 || v[3] > 191 || v[6] > 255 || v[7] > 191 || v[2] == v[6] || v[3] == v[7] {
+			return invalid
+		}
+		us.Calibration = TouchCalibration{
+			uint16(v[0]), uint16(v[1]), uint8(v[2]), uint8(v[3]),
+			uint16(v[4]), uint16(v[5]), uint8(v[6]), uint8(v[7]),
+		}
+
+	default:
+		return fmt.Errorf("unknown setting: %q", field)
+	}
+	return nil
+}
+
+// Get returns the value of a field of the user settings, in the format
+// accepted by Set
+func (us *UserSettings) Get(field string) string {
+	switch field {
+	case "nickname":
+		return us.Nickname
+	case "message":
+		return us.Message
+	case "color":
+		return userColorNames[us.Color&0xF]
+	case "birthday":
+		return fmt.Sprintf("%02d-%02d", us.BirthMonth, us.BirthDay)
+	case "language":
+		return us.Language.String()
+	case "calibration":
+		return us.Calibration.String()
+	}
+	return ""
+}
+
+var userSettingsFields = []string{"nickname", "message", "color", "birthday", "language", "calibration"}
+
+// EditFirmwareUserSettings changes the user settings stored in a file, as
+// if they were changed in the firmware menu. The file can be a firmware
+// image (both valid copies of the settings are updated, see
+// SetFirmwareLanguage), or a bare settings block (256 bytes).
+func EditFirmwareUserSettings(fn string, edit func(us *UserSettings) error) error {
+	f, err := os.OpenFile(fn, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var offs []int64
+	if fi, err := f.Stat(); err != nil {
+		return err
+	} else if fi.Size() == cFwUserSettingsSize {
+		offs = []int64{0}
+	} else {
+		fwoffs, err := fwUserSettingsOffsets(f)
+		if err != nil {
+			return err
+		}
+		offs = fwoffs[:]
+	}
+
+	// Edit the current settings, then apply the same values to all the
+	// valid copies
+	var cur []byte
+	if len(offs) == 1 {
+		cur = make([]byte, cFwUserSettingsSize)
+		if _, err := f.ReadAt(cur, 0); err != nil {
+			return err
+		}
+	} else if cur, err = ReadFirmwareUserSettings(f); err != nil {
+		return err
+	}
+	us := DecodeUserSettings(cur)
+	if err := edit(&us); err != nil {
+		return err
+	}
+
+	for _, off := range offs {
+		data := make([]byte, cFwUserSettingsSize)
+		if _, err := f.ReadAt(data, off); err != nil {
+			return err
+		}
+		if len(offs) > 1 && ^crc16.ChecksumIBM(data[:cFwUserSettingsCrc]) != binary.LittleEndian.Uint16(data[0x72:]) {
+			// Leave invalid copies alone, or the firmware would start
+			// trusting them
+			continue
+		}
+		us.Encode(data)
+		if _, err := f.WriteAt(data, off); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firmwareCopy returns the path of the writable copy of a firmware file
+// (the original file is never modified), creating it if it doesn't exist
+// yet. It also returns true if the copy was just created.
+func firmwareCopy(fn string) (string, bool, error) {
+	fwsav := fn + ".sav"
+	if _, err := os.Stat(fwsav); err == nil {
+		return fwsav, false, nil
+	}
+	fw, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return "", false, err
+	}
+	if err := ioutil.WriteFile(fwsav, fw, 0777); err != nil {
+		return "", false, err
+	}
+	return fwsav, true, nil
+}
+
+// settingsMain implements "ndsemu settings edit", which changes the user
+// settings without booting the firmware menu. Settings given as options are
+// changed directly; without options, each setting is prompted for
+// interactively.
+func settingsMain(args []string) int {
+	if len(args) == 0 || args[0] != "edit" {
+		fmt.Fprintf(os.Stderr, "usage: %s settings edit [options] [file]\n", os.Args[0])
+		return 2
+	}
+
+	fs := flag.NewFlagSet("settings edit", flag.ExitOnError)
+	values := make(map[string]*string)
+	for _, field := range userSettingsFields {
+		values[field] = fs.String(field, "", "set the "+field)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s settings edit [options] [file]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Edits the user settings in the writable copy of the default firmware\n")
+		fmt.Fprintf(os.Stderr, "(%s.sav), or in the specified firmware image or 256-byte settings\n", cFirmwareDefault)
+		fmt.Fprintf(os.Stderr, "file. Without options, each setting is prompted for.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args[1:])
+
+	fn := fs.Arg(0)
+	if fn == "" {
+		bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+		var err error
+		if fn, _, err = firmwareCopy(filepath.Join(bindir, cFirmwareDefault)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	err := EditFirmwareUserSettings(fn, func(us *UserSettings) error {
+		if fs.NFlag() == 0 {
+			return promptUserSettings(us, os.Stdin, os.Stdout)
+		}
+		for _, field := range userSettingsFields {
+			if v := *values[field]; v != "" {
+				if err := us.Set(field, v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// promptUserSettings asks for the value of each setting, showing the
+// current one (which is kept by entering an empty line)
+func promptUserSettings(us *UserSettings, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for _, field := range userSettingsFields {
+		for {
+			fmt.Fprintf(out, "%s [%s]: ", field, us.Get(field))
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return err
+				}
+				return io.ErrUnexpectedEOF
+			}
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				break
+			}
+			if err := us.Set(field, line); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			break
+		}
+	}
+	return nil
+}
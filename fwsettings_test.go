@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/howeyc/crc16"
+)
+
+func TestUserSettingsEdit(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "settings.bin")
+	data := make([]byte, cFwUserSettingsSize)
+	data[0x64] = 0xF8 | uint8(LangJapanese) // other flags must be preserved
+	if err := ioutil.WriteFile(fn, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	err := EditFirmwareUserSettings(fn, func(us *UserSettings) error {
+		for _, s := range [][2]string{
+			{"nickname", "Nintendo"},
+			{"color", "darkblue"},
+			{"birthday", "12-25"},
+			{"language", "it"},
+			{"calibration", "592,559,32,32,3425,3318,224,160"},
+		} {
+			if err := us.Set(s[0], s[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ = ioutil.ReadFile(fn)
+	if crc := ^crc16.ChecksumIBM(data[:cFwUserSettingsCrc]); crc != binary.LittleEndian.Uint16(data[0x72:]) {
+		t.Errorf("invalid CRC")
+	}
+	if data[0x64] != 0xF8|uint8(LangItalian) {
+		t.Errorf("invalid language byte: %02x", data[0x64])
+	}
+	us := DecodeUserSettings(data)
+	for field, want := range map[string]string{
+		"nickname":    "Nintendo",
+		"color":       "darkblue",
+		"birthday":    "12-25",
+		"language":    "italian",
+		"calibration": "592,559,32,32,3425,3318,224,160",
+	} {
+		if got := us.Get(field); got != want {
+			t.Errorf("%s: got %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestUserSettingsInvalid(t *testing.T) {
+	var us UserSettings
+	for _, s := range [][2]string{
+		{"nickname", "ABCDEFGHIJK"},
+		{"color", "16"},
+		{"color", "black"},
+		{"birthday", "13-01"},
+		{"birthday", "today"},
+		{"language", "klingon"},
+		{"calibration", "1,2,3"},
+		{"calibration", "592,559,32,32,3425,3318,32,160"},
+		{"volume", "10"},
+	} {
+		if err := us.Set(s[0], s[1]); err == nil {
+			t.Errorf("%s=%q accepted", s[0], s[1])
+		}
+	}
+}
+
+func TestUserSettingsPrompt(t *testing.T) {
+	us := UserSettings{Nickname: "old", BirthMonth: 1, BirthDay: 1}
+	in := strings.NewReader("new\n\n99\n3\n\n\n\n")
+	if err := promptUserSettings(&us, in, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if us.Nickname != "new" || us.Message != "" || us.Color != 3 || us.Language != LangJapanese {
+		t.Errorf("unexpected settings: %+v", us)
+	}
+
+	// Input ending before all the settings were asked
+	if err := promptUserSettings(&us, strings.NewReader("x\n"), ioutil.Discard); err == nil {
+		t.Errorf("truncated input accepted")
+	}
+}
+
+func TestUserSettingsExtended(t *testing.T) {
+	data := make([]byte, cFwUserSettingsSize)
+	data[0x64] = uint8(LangFrench)
+
+	// Chinese is stored in the extended settings, which are created
+	us := DecodeUserSettings(data)
+	us.Language = LangChinese
+	us.Encode(data)
+	if data[0x64] != uint8(LangEnglish) || data[0x74] != cUserExtVersion || data[0x75] != uint8(LangChinese) {
+		t.Errorf("invalid language bytes: %02x %02x %02x", data[0x64], data[0x74], data[0x75])
+	}
+
+	// Layout of the extended settings in the firmware of a DS Lite: version,
+	// language, supported languages, 0xFF padding and the CRC of 0x74-0xFD
+	exp := make([]byte, 0x8C)
+	exp[0], exp[1] = cUserExtVersion, uint8(LangChinese)
+	binary.LittleEndian.PutUint16(exp[2:], uint16(RegionWorld|1<<LangChinese))
+	for i := 4; i < 0x8A; i++ {
+		exp[i] = 0xFF
+	}
+	binary.LittleEndian.PutUint16(exp[0x8A:], firmwareCrc16(exp[:0x8A]))
+	if !bytes.Equal(data[0x74:], exp) {
+		t.Errorf("invalid extended settings:\n% x\nwant:\n% x", data[0x74:], exp)
+	}
+	if us = DecodeUserSettings(data); us.Language != LangChinese || !us.Region.Supports(LangChinese) {
+		t.Errorf("unexpected settings: %+v", us)
+	}
+
+	// Once they exist, they're kept in sync
+	us.Language, us.Region = LangGerman, RegionWorld
+	us.Encode(data)
+	if data[0x64] != uint8(LangGerman) || data[0x75] != uint8(LangGerman) {
+		t.Errorf("invalid language bytes: %02x %02x", data[0x64], data[0x75])
+	}
+	if us = DecodeUserSettings(data); us.Region != RegionWorld {
+		t.Errorf("invalid region: %v", us.Region)
+	}
+}
+
+// firmwareCrc16 is the CRC used by the firmware (as computed by the BIOS
+// GetCRC16 function), with initial value 0xFFFF
+func firmwareCrc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+func TestFirmwareFlashLanguage(t *testing.T) {
+	// Minimal firmware: header declaring a 1 KiB area, whose last two blocks
+	// are the copies of the user settings
+	fw := make([]byte, 0x400)
+	binary.LittleEndian.PutUint16(fw[0x20:], 0x400/8)
+	for i, off := range []int{0x200, 0x300} {
+		us := UserSettings{Nickname: "ndsemu", Language: LangFrench}
+		fw[off+0x70] = uint8(i)
+		us.Encode(fw[off : off+cFwUserSettingsSize])
+	}
+	fn := filepath.Join(t.TempDir(), "firmware.bin")
+	if err := ioutil.WriteFile(fn, fw, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	ff := NewHwFirmwareFlash()
+	if err := ff.MapFirmwareFile(fn); err != nil {
+		t.Fatal(err)
+	}
+	defer ff.Close()
+	ff.SetLanguage(LangGerman)
+
+	data, err := ReadFirmwareUserSettings(ff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if us := DecodeUserSettings(data); us.Language != LangGerman || us.Nickname != "ndsemu" {
+		t.Errorf("unexpected settings: %+v", us)
+	}
+
+	// Reads not aligned to the blocks see the same bytes
+	buf := make([]byte, 0x180)
+	if _, err := ff.ReadAt(buf, 0x280); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf[0x80:0x180], data) {
+		t.Errorf("unaligned read differs from the settings")
+	}
+
+	if got, _ := ioutil.ReadFile(fn); !bytes.Equal(got, fw) {
+		t.Errorf("firmware file modified")
+	}
+}
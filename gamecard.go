@@ -1,13 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"encoding/binary"
 	"io"
+	"io/ioutil"
 	"ndsemu/emu"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
 	"ndsemu/emu/spi"
-	"os"
+	"ndsemu/patch"
 
 	"golang.org/x/exp/mmap"
 )
@@ -23,9 +25,15 @@ const (
 	gcStatusKey2
 )
 
+// Duration of an AUXSPI bit (in bus cycles) at the fastest SPI clock (4MHz);
+// the baudrate bits of AUXSPICNT halve the clock at each step, down to 512KHz.
+const cAuxSpiBitCycles = 8
+
 type Gamecard struct {
 	io.ReaderAt
-	Irq     *HwIrq
+	Irq     [2]*HwIrq     // IRQ controllers of both CPUs (see SetGamecard)
+	Owner   func() CpuNum // CPU owning the slot, which gets the IRQs
+	Dma     []DmaTrigger  // CPUs notified when a word is ready (see SetGamecard)
 	closecb func()
 	Size    uint64
 
@@ -38,17 +46,22 @@ type Gamecard struct {
 	KeySeed0H  hwio.Reg16 `hwio:"bank=0,offset=0x18,rwmask=0x7f,writeonly"`
 	KeySeed1H  hwio.Reg16 `hwio:"bank=0,offset=0x1A,rwmask=0x7f,writeonly"`
 
-	CardData hwio.Reg32 `hwio:"bank=1,offset=0x0,readonly,rcb"`
+	CardData hwio.Reg32 `hwio:"bank=1,offset=0x0,readonly,rcb,readsensitive"`
+
+	// Emulate the exact transfer timing (see QuirkCardTiming)
+	AccurateTiming bool
 
-	chipid     [4]byte
+	chipid     [4]byte // see SetChipID
 	stat       gcStatus
 	buf        []byte
+	xferPos    int // bytes transferred in the current block transfer
 	key1Tables [(18 + 1024) * 4]byte
 	key2       Key2
 	secAreaOff int
 
-	spi spi.Bus
-	bkp *HwBackupRam
+	spi   spi.Bus
+	bkp   *HwBackupRam
+	sched Scheduler
 }
 
 type noCartridgeReader struct{}
@@ -60,9 +73,10 @@ func (nc noCartridgeReader) ReadAt(buf []byte, off int64) (n int, err error) {
 	return len(buf), nil
 }
 
-func NewGamecard(biosfn string, bkp *HwBackupRam) *Gamecard {
+func NewGamecard(bios7 []byte, bkp *HwBackupRam, sched Scheduler) *Gamecard {
 	gc := &Gamecard{
-		key2: NewKey2(),
+		key2:  NewKey2(),
+		sched: sched,
 	}
 	hwio.MustInitRegs(gc)
 	gc.RomCtrl.WriteCb = gc.WriteROMCTRL
@@ -73,12 +87,10 @@ func NewGamecard(biosfn string, bkp *HwBackupRam) *Gamecard {
 	gc.spi.AddDevice(0, bkp)
 	gc.bkp = bkp
 
-	f, err := os.Open(biosfn)
-	if err != nil {
-		panic(err)
+	// The KEY1 tables are stored in the ARM7 BIOS
+	if len(bios7) > 0x30 {
+		copy(gc.key1Tables[:], bios7[0x30:])
 	}
-	f.ReadAt(gc.key1Tables[:], 0x30)
-	f.Close()
 
 	gc.chipid[0] = 0xFF
 	gc.chipid[1] = 0xFF
@@ -97,24 +109,127 @@ func (gc *Gamecard) MapCart(data io.ReaderAt) {
 	gc.ReaderAt = data
 }
 
+// MapCartFile inserts a ROM file. The file is memory-mapped rather than
+// loaded, and an encrypted secure area is decrypted on demand (see cartRom).
 func (gc *Gamecard) MapCartFile(fn string) error {
 	f, err := mmap.Open(fn)
 	if err != nil {
 		return err
 	}
 
-	gc.Size = uint64(f.Len())
-
-	gc.MapCart(f)
+	gc.MapCart(newCartRom(f, gc.key1Tables[:]))
 	gc.closecb = func() { f.Close() }
+	gc.insert(uint64(f.Len()))
+	return nil
+}
+
+// MapCartPatchedFile loads a ROM file in memory, applies the specified
+// patches to it (in order), and inserts the result. The file itself is not
+// modified. Without patches, it is equivalent to MapCartFile.
+func (gc *Gamecard) MapCartPatchedFile(fn string, patches []string) error {
+	if len(patches) == 0 {
+		return gc.MapCartFile(fn)
+	}
+
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+	for _, p := range patches {
+		if data, err = patch.ApplyFile(data, p); err != nil {
+			return err
+		}
+		modGamecard.WarnZ("ROM patched").String("patch", p).End()
+	}
+
+	gc.MapCart(newCartRom(bytes.NewReader(data), gc.key1Tables[:]))
+	gc.insert(uint64(len(data)))
+	return nil
+}
+
+func (gc *Gamecard) insert(size uint64) {
+	gc.Size = size
 
 	// Inititalize chip id
-	gc.chipid[0] = 0xC2 // manufacturer (?)
-	gc.chipid[1] = 0x7F // ROM size (Mbytes - 1)
+	var capacity [1]byte
+	gc.ReadAt(capacity[:], 0x14)
+	gc.chipid[0] = 0xC2 // manufacturer (Macronix)
+	gc.chipid[1] = chipIDSize(capacity[0], size)
 	gc.chipid[2] = 0x00 // flags
 	gc.chipid[3] = 0x80 // flags
+}
 
-	return nil
+// chipIDSize returns the chip size field of the chip ID: 00h-7Fh for chips of
+// 1-128 MiB ((N+1) MiB), and F0h-FFh for bigger ones ((100h-N)*256 MiB). Some
+// games check it against the capacity declared in the header (128 KiB << N),
+// which is used unless the ROM doesn't fit (eg: homebrew with an invalid
+// header); the ROM size is used in that case.
+func chipIDSize(capacity byte, romSize uint64) byte {
+	size := uint64(128*1024) << capacity
+	if capacity > 15 || size < romSize {
+		size = romSize
+	}
+	mb := uint64(1)
+	for mb<<20 < size {
+		mb <<= 1
+	}
+	if mb <= 128 {
+		return byte(mb - 1)
+	}
+	return byte(0x100 - mb/256)
+}
+
+// ChipID returns the chip ID of the inserted cartridge (see SetChipID)
+func (gc *Gamecard) ChipID() [4]byte {
+	return gc.chipid
+}
+
+// SetChipID overrides the chip ID of the inserted cartridge, until another
+// one is inserted. The chip ID is returned by the ID commands in all modes
+// (raw, KEY1 and KEY2): the firmware reads it at boot, and games read it
+// again later, to check it or to detect the removal of the card. Its bytes
+// are the manufacturer, the chip size (see chipIDSize) and two bytes of
+// flags. By default, it describes a Macronix chip with the capacity of the
+// ROM.
+func (gc *Gamecard) SetChipID(id [4]byte) {
+	gc.chipid = id
+}
+
+// UnmapCart removes the cartridge from the slot
+func (gc *Gamecard) UnmapCart() {
+	gc.MapCart(noCartridgeReader{})
+	gc.Size = 0
+	gc.chipid = [4]byte{0xFF, 0xFF, 0xFF, 0xFF}
+}
+
+// RemoveCart simulates pulling out the cartridge while the console is
+// running: from now on, the card returns 0xFF for all data, including the
+// chip ID that games poll to detect the removal, and the CPU owning the
+// slot gets the card eject IRQ.
+func (gc *Gamecard) RemoveCart() {
+	gc.UnmapCart()
+	if irq := gc.irq(); irq != nil {
+		irq.Raise(IrqGameCardEject)
+	}
+	modGamecard.WarnZ("cartridge removed").End()
+}
+
+// irq returns the IRQ controller of the CPU owning the slot, as selected by
+// EXMEMCNT (nil if the gamecard is not connected to the CPUs)
+func (gc *Gamecard) irq() *HwIrq {
+	if gc.Owner == nil {
+		return nil
+	}
+	return gc.Irq[gc.Owner()]
+}
+
+// TakeCart moves the cartridge inserted into another gamecard slot into this
+// one (used when the hardware is recreated on reset).
+func (gc *Gamecard) TakeCart(old *Gamecard) {
+	gc.MapCart(old.ReaderAt)
+	gc.closecb, old.closecb = old.closecb, nil
+	gc.Size = old.Size
+	gc.chipid = old.chipid
 }
 
 func (gc *Gamecard) WriteAUXSPICNT(old, value uint16) {
@@ -138,17 +253,29 @@ func (gc *Gamecard) WriteAUXSPIDATA(_, value uint16) {
 		return
 	}
 
-	if gc.AuxSpiCnt.Value&3 != 0 {
-		modGamecard.WarnZ("AUXSPIDATA written, but wrong SPI frequency").End()
-		return
+	if gc.AuxSpiCnt.Value&(1<<7) != 0 {
+		modGamecard.WarnZ("AUXSPIDATA written while busy").End()
 	}
 
 	// Do the SPI transfer. Send one byte, get one byte.
 	log.ModSpi.DebugZ("xfer send").Hex8("val", uint8(value)).End()
 	read := gc.spi.Transfer(uint8(value))
-	gc.AuxSpiData.Value = uint16(read)
 	log.ModSpi.DebugZ("xfer recv").Hex8("val", read).End()
 
+	if gc.bkp.AccurateTiming {
+		// The byte is shifted out at the SPI clock: the busy flag is set
+		// for the duration of the transfer, and the received byte is
+		// only visible at the end of it.
+		cycles := int64(8*cAuxSpiBitCycles) << (gc.AuxSpiCnt.Value & 3)
+		gc.AuxSpiCnt.Value |= 1 << 7
+		gc.sched.ScheduleEvent(gc.sched.Cycles()+cycles, func() {
+			gc.AuxSpiData.Value = uint16(read)
+			gc.AuxSpiCnt.Value &^= 1 << 7
+		})
+	} else {
+		gc.AuxSpiData.Value = uint16(read)
+	}
+
 	// If chispselect is off, this is the last trasnfer byte,
 	// so reset the write buffer to discard current command and restart
 	// new one
@@ -224,6 +351,7 @@ func (gc *Gamecard) WriteROMCTRL(_, value uint32) {
 		}
 
 		gc.buf = buf
+		gc.xferPos = 0
 		gc.xferByte(true)
 	}
 }
@@ -400,7 +528,7 @@ func (gc *Gamecard) xferByte(first bool) {
 		gc.RomCtrl.Value &^= (1 << 31)
 		gc.RomCtrl.Value &^= (1 << 23)
 		if gc.AuxSpiCnt.Value&(1<<14) != 0 {
-			gc.Irq.Raise(IrqGameCardData)
+			gc.irq().Raise(IrqGameCardData)
 		}
 		return
 	}
@@ -412,21 +540,35 @@ func (gc *Gamecard) xferByte(first bool) {
 	if gc.RomCtrl.Value&(1<<27) != 0 {
 		clkrate = int64(8)
 	}
-	if gc.stat == gcStatusKey2 {
-		clkrate *= int64((gc.RomCtrl.Value>>16)&0x3F) + 4
+	gap1 := int64(gc.RomCtrl.Value & 0x1FFF)
+	gap2 := int64((gc.RomCtrl.Value >> 16) & 0x3F)
+	if gc.AccurateTiming {
+		// The 8-byte command and the initial latency (gap1) precede the
+		// first word; gap2 is inserted between 0x200-byte blocks.
+		nbytes := int64(4)
+		if first {
+			nbytes += 8 + gap1
+		} else if gc.xferPos&0x1FF == 0 {
+			nbytes += gap2
+		}
+		clkrate *= nbytes
+	} else if gc.stat == gcStatusKey2 {
+		clkrate *= gap2 + 4
 	} else {
-		clkrate *= int64(gc.RomCtrl.Value&0x1FFF) + 4 + 4
+		clkrate *= gap1 + 4 + 4
 	}
 
-	cycles := Emu.Sync.Cycles()
-	Emu.Sync.ScheduleEvent(cycles+clkrate, func() {
+	cycles := gc.sched.Cycles()
+	gc.sched.ScheduleEvent(cycles+clkrate, func() {
 		data := binary.LittleEndian.Uint32(gc.buf[0:4])
 		gc.buf = gc.buf[4:]
+		gc.xferPos += 4
 		gc.CardData.Value = data
 
 		gc.RomCtrl.Value |= (1 << 23) // signal data available
-		nds9.TriggerDmaEvent(DmaEventGamecard)
-		nds7.TriggerDmaEvent(DmaEventGamecard)
+		for _, dma := range gc.Dma {
+			dma.TriggerDmaEvent(DmaEventGamecard)
+		}
 	})
 }
 
@@ -1,10 +1,62 @@
 package main
 
 import (
+	"errors"
+	"ndsemu/arm"
 	"ndsemu/e2d"
 	"ndsemu/emu"
+	log "ndsemu/emu/logger"
 )
 
+// Initial stack pointers set by the GBA BIOS before jumping to the cartridge
+var gbaBootStacks = struct{ sys, irq, svc uint32 }{0x03007F00, 0x03007FA0, 0x03007FE0}
+
+// BootGba switches the console to GBA mode to run the cartridge inserted in
+// slot 2, like the firmware does when selecting it from the menu. The GBA
+// image is shown on the screen selected in the firmware user settings.
+// If skipBios is true, the cartridge is started directly, skipping the
+// GBA BIOS intro.
+func (emu *NDSEmulator) BootGba(skipBios bool) error {
+	if emu.Hw.Sl2.Rom == nil {
+		return errors.New("no cartridge in slot 2")
+	}
+	if emu.Rom.BiosGba == nil {
+		return errors.New("GBA BIOS not available")
+	}
+
+	// The firmware routes the engine used in GBA mode to the screen
+	// selected in the user settings (byte 0x64, bit 3: 0=upper)
+	bottom := true
+	if emu.Hw.Ff.f != nil {
+		us, err := ReadFirmwareUserSettings(emu.Hw.Ff)
+		if err != nil {
+			log.ModEmu.WarnZ("cannot read firmware user settings").Error("err", err).End()
+		} else {
+			bottom = us[0x64]&(1<<3) != 0
+		}
+	}
+	nds9.misc.PowCnt.Value = 0x0003
+	if !bottom {
+		nds9.misc.PowCnt.Value |= 0x8000
+	}
+
+	nds9.Cpu.SetLine(arm.LineHalt, true)
+	emu.switchingToGba = false
+	emu.switchToGba()
+
+	if skipBios {
+		cpu := nds7.Cpu
+		cpu.Cpsr.SetMode(arm.CpuModeIrq, cpu)
+		cpu.SetReg(13, gbaBootStacks.irq)
+		cpu.Cpsr.SetMode(arm.CpuModeSupervisor, cpu)
+		cpu.SetReg(13, gbaBootStacks.svc)
+		cpu.Cpsr.SetMode(arm.CpuModeSystem, cpu)
+		cpu.SetReg(13, gbaBootStacks.sys)
+		cpu.SetPC(0x08000000)
+	}
+	return nil
+}
+
 type GbaMemCnt struct {
 	Bus emu.Bus
 }
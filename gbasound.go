@@ -0,0 +1,609 @@
+package main
+
+import (
+	"ndsemu/emu/fixed"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+)
+
+var modGbaSound = log.NewModule("gbasound")
+
+// In GBA mode, the emulation runs at the clock of the GBA CPU, which is half
+// of the bus clock. Timings below are expressed in these cycles.
+const (
+	cGbaSeqCycles = 32768 // frame sequencer step (512 Hz)
+	cGbaFifoSize  = 32    // bytes of each Direct Sound FIFO
+)
+
+// Duty cycles of the square channels (one bit for each of the 8 steps)
+var gbaSquareDuty = [4]uint8{0x01, 0x81, 0x87, 0x7E}
+
+type gbaPsgChannel struct {
+	on       bool
+	timer    int64 // cycles to the next step of the waveform
+	pos      uint  // position within the waveform, or noise LFSR
+	high     bool  // current output of the noise channel
+	length   int   // length counter (256 Hz steps left)
+	vol      int   // current envelope volume (0-15)
+	envTimer int   // envelope steps (64 Hz) to the next volume change
+
+	// Frequency sweep (channel 1 only)
+	sweepTimer int
+}
+
+type gbaFifo struct {
+	buf   [cGbaFifoSize]int8
+	rd, n int
+	cur   int8 // sample being played
+}
+
+// HwGbaSound emulates the sound hardware used in GBA mode: the four PSG
+// channels inherited from the Game Boy (two square waves, one with frequency
+// sweep, a programmable wave and noise), and the two Direct Sound channels,
+// that play 8-bit PCM samples streamed through FIFOs. FIFOs are refilled by
+// DMA 1/2, and each of them is clocked by the overflows of timer 0 or 1 (see
+// TimerOverflow).
+//
+// Like HwSound, it runs in lockstep with the other subsystems, producing the
+// output samples of each frame at their exact time (see BeginFrame).
+type HwGbaSound struct {
+	Dma   []*HwDmaChannel // DMA channels that can refill the FIFOs
+	sched Scheduler
+
+	ch   [4]gbaPsgChannel
+	fifo [2]gbaFifo
+	wave [2][16]byte // wave RAM banks (the one accessible by the CPU is in WaveRam)
+
+	seqTimer int64
+	seqStep  int
+
+	// Output of the current frame (see HwSound)
+	cycles    int64
+	lastTick  int64
+	out       []int16
+	outPos    int
+	outStart  int64
+	outCycles int64
+
+	Snd1CntL hwio.Reg16 `hwio:"offset=0x00,rwmask=0x7F,wcb"`
+	Snd1CntH hwio.Reg16 `hwio:"offset=0x02,wcb"`
+	Snd1CntX hwio.Reg16 `hwio:"offset=0x04,rwmask=0xC7FF,wcb"`
+	Snd2CntL hwio.Reg16 `hwio:"offset=0x08,wcb"`
+	Snd2CntH hwio.Reg16 `hwio:"offset=0x0C,rwmask=0xC7FF,wcb"`
+	Snd3CntL hwio.Reg16 `hwio:"offset=0x10,rwmask=0xE0,wcb"`
+	Snd3CntH hwio.Reg16 `hwio:"offset=0x12,rwmask=0xE0FF,wcb"`
+	Snd3CntX hwio.Reg16 `hwio:"offset=0x14,rwmask=0xC7FF,wcb"`
+	Snd4CntL hwio.Reg16 `hwio:"offset=0x18,rwmask=0xFF3F,wcb"`
+	Snd4CntH hwio.Reg16 `hwio:"offset=0x1C,rwmask=0xC0FF,wcb"`
+	SndCntL  hwio.Reg16 `hwio:"offset=0x20,rwmask=0xFF77,wcb"`
+	SndCntH  hwio.Reg16 `hwio:"offset=0x22,rwmask=0xFF0F,wcb"`
+	SndCntX  hwio.Reg16 `hwio:"offset=0x24,rwmask=0x80,rcb,wcb"`
+	SndBias  hwio.Reg32 `hwio:"offset=0x28,reset=0x200,rwmask=0xC3FF"`
+	WaveRam  hwio.Mem   `hwio:"offset=0x30,size=0x10"`
+	FifoA    hwio.Mem   `hwio:"offset=0x40,size=4,rw8=off,wcb"`
+	FifoB    hwio.Mem   `hwio:"offset=0x44,size=4,rw8=off,wcb"`
+}
+
+func NewHwGbaSound(dma []*HwDmaChannel, sched Scheduler) *HwGbaSound {
+	snd := &HwGbaSound{Dma: dma, sched: sched}
+	hwio.MustInitRegs(snd)
+	snd.Reset()
+	return snd
+}
+
+// BeginFrame starts producing the output of a frame, lasting the specified
+// number of cycles, into buf (interleaved stereo samples)
+func (snd *HwGbaSound) BeginFrame(buf []int16, frameCycles int64) {
+	snd.out = buf
+	snd.outPos = 0
+	snd.outStart = snd.cycles
+	snd.outCycles = frameCycles
+}
+
+// EndFrame completes the output of the current frame
+func (snd *HwGbaSound) EndFrame() {
+	if snd.out == nil {
+		return
+	}
+	snd.Run(snd.outStart + snd.outCycles)
+	for i := snd.outPos * 2; i < len(snd.out); i++ {
+		snd.out[i] = 0
+	}
+	snd.out = nil
+}
+
+func (snd *HwGbaSound) Frequency() fixed.F8 {
+	// The GBA CPU clock, that is the main clock in GBA mode (see GbaSyncConfig)
+	return fixed.NewF8(cGbaClock)
+}
+
+func (snd *HwGbaSound) Reset() {
+	snd.cycles = 0
+	snd.lastTick = 0
+	snd.out = nil
+	snd.seqTimer = cGbaSeqCycles
+	snd.seqStep = 0
+	snd.ch = [4]gbaPsgChannel{}
+	snd.fifo = [2]gbaFifo{}
+}
+
+func (snd *HwGbaSound) Cycles() int64 {
+	return snd.cycles
+}
+
+func (snd *HwGbaSound) Run(target int64) {
+	if target <= snd.cycles {
+		return
+	}
+	snd.cycles = target
+	if snd.out == nil {
+		snd.lastTick = target
+		return
+	}
+
+	nsamples := int64(len(snd.out) / 2)
+	for snd.outPos < int(nsamples) {
+		when := snd.outStart + (int64(snd.outPos)+1)*snd.outCycles/nsamples
+		if when > target {
+			break
+		}
+		snd.step(when - snd.lastTick)
+		snd.lastTick = when
+
+		l, r := snd.mix()
+		snd.out[snd.outPos*2] = l
+		snd.out[snd.outPos*2+1] = r
+		snd.outPos++
+	}
+}
+
+// sync brings the emulation up to the current time, before a register
+// changes the output
+func (snd *HwGbaSound) sync() {
+	if snd.sched != nil {
+		snd.Run(snd.sched.Cycles())
+	}
+}
+
+func (snd *HwGbaSound) enabled() bool { return snd.SndCntX.Value&0x80 != 0 }
+
+// TimerOverflow must be called at each overflow of the timers of the ARM7:
+// timers 0 and 1 clock the Direct Sound FIFOs. when is expressed in cycles of
+// the timers, which run at the bus clock.
+func (snd *HwGbaSound) TimerOverflow(timer int, when int64) {
+	if timer > 1 {
+		return
+	}
+	snd.Run(when * cGbaClock / cTimerClock)
+	for i := range snd.fifo {
+		if int(snd.SndCntH.Value>>(10+4*uint(i)))&1 == timer {
+			snd.popFifo(i)
+		}
+	}
+}
+
+func (snd *HwGbaSound) popFifo(idx int) {
+	f := &snd.fifo[idx]
+	if f.n > 0 {
+		f.cur = f.buf[f.rd]
+		f.rd = (f.rd + 1) % cGbaFifoSize
+		f.n--
+	}
+
+	// Request a refill when the FIFO is half empty, to the DMA channel
+	// that has it as destination
+	if f.n <= cGbaFifoSize/2 {
+		addr := uint32(0x40000A0) + uint32(idx)*4
+		for _, dma := range snd.Dma {
+			if dma != nil && dma.DmaDad.Value&0x0FFFFFFF == addr {
+				dma.TriggerEvent(DmaEventGbaSoundFifo)
+			}
+		}
+	}
+}
+
+func (snd *HwGbaSound) pushFifo(idx int, data []byte) {
+	f := &snd.fifo[idx]
+	for _, b := range data {
+		if f.n == cGbaFifoSize {
+			modGbaSound.InfoZ("FIFO overflow").Int("fifo", idx).End()
+			return
+		}
+		f.buf[(f.rd+f.n)%cGbaFifoSize] = int8(b)
+		f.n++
+	}
+}
+
+func (snd *HwGbaSound) WriteFIFOA(addr uint32, n int) {
+	snd.pushFifo(0, snd.FifoA.Data[addr&3:int(addr&3)+n])
+}
+
+func (snd *HwGbaSound) WriteFIFOB(addr uint32, n int) {
+	snd.pushFifo(1, snd.FifoB.Data[addr&3:int(addr&3)+n])
+}
+
+// envReg returns the register holding length, duty and envelope of a PSG
+// channel (not used by the wave channel), and ctlReg the register with
+// frequency, length enable and restart
+func (snd *HwGbaSound) envReg(idx int) uint16 {
+	switch idx {
+	case 0:
+		return snd.Snd1CntH.Value
+	case 1:
+		return snd.Snd2CntL.Value
+	default:
+		return snd.Snd4CntL.Value
+	}
+}
+
+func (snd *HwGbaSound) ctlReg(idx int) *hwio.Reg16 {
+	switch idx {
+	case 0:
+		return &snd.Snd1CntX
+	case 1:
+		return &snd.Snd2CntH
+	case 2:
+		return &snd.Snd3CntX
+	default:
+		return &snd.Snd4CntH
+	}
+}
+
+// period returns the number of cycles of each step of the waveform of a
+// PSG channel
+func (snd *HwGbaSound) period(idx int) int64 {
+	switch idx {
+	case 0, 1:
+		return 16 * (2048 - int64(snd.ctlReg(idx).Value&0x7FF))
+	case 2:
+		return 8 * (2048 - int64(snd.Snd3CntX.Value&0x7FF))
+	default:
+		cnt := snd.Snd4CntH.Value
+		div := int64(cnt&7) * 64
+		if div == 0 {
+			div = 32
+		}
+		return div << (cnt >> 4 & 0xF)
+	}
+}
+
+func (snd *HwGbaSound) reloadLength(idx int) {
+	switch idx {
+	case 2:
+		snd.ch[2].length = 256 - int(snd.Snd3CntH.Value&0xFF)
+	default:
+		snd.ch[idx].length = 64 - int(snd.envReg(idx)&0x3F)
+	}
+}
+
+// restart (re)starts the playback of a PSG channel, when bit 15 of its
+// control register is written
+func (snd *HwGbaSound) restart(idx int) {
+	c := &snd.ch[idx]
+	c.on = snd.enabled()
+	c.timer = snd.period(idx)
+	c.pos = 0
+	if c.length == 0 {
+		snd.reloadLength(idx)
+	}
+
+	switch idx {
+	case 2:
+		if snd.Snd3CntL.Value&0x80 == 0 {
+			c.on = false
+		}
+		return
+	case 3:
+		c.pos = 0x4000
+		if snd.Snd4CntH.Value&8 != 0 {
+			c.pos = 0x40
+		}
+	case 0:
+		c.sweepTimer = int(snd.Snd1CntL.Value >> 4 & 7)
+	}
+
+	env := snd.envReg(idx)
+	c.vol = int(env >> 12)
+	c.envTimer = int(env >> 8 & 7)
+	if env&0xF800 == 0 {
+		// Volume 0 and decreasing: the DAC is off
+		c.on = false
+	}
+}
+
+func (snd *HwGbaSound) step(ticks int64) {
+	for ticks > 0 {
+		n := ticks
+		if n > snd.seqTimer {
+			n = snd.seqTimer
+		}
+		for i := range snd.ch {
+			snd.stepChannel(i, n)
+		}
+		ticks -= n
+		snd.seqTimer -= n
+		if snd.seqTimer == 0 {
+			snd.seqTimer = cGbaSeqCycles
+			snd.sequencer()
+		}
+	}
+}
+
+func (snd *HwGbaSound) stepChannel(idx int, ticks int64) {
+	c := &snd.ch[idx]
+	if !c.on {
+		return
+	}
+	period := snd.period(idx)
+	for c.timer -= ticks; c.timer <= 0; c.timer += period {
+		switch idx {
+		case 0, 1:
+			c.pos = (c.pos + 1) & 7
+		case 2:
+			c.pos = (c.pos + 1) & 63
+		default:
+			c.high = c.pos&1 != 0
+			c.pos >>= 1
+			if c.high {
+				if snd.Snd4CntH.Value&8 != 0 {
+					c.pos ^= 0x60
+				} else {
+					c.pos ^= 0x6000
+				}
+			}
+		}
+	}
+}
+
+// sequencer performs a step of the frame sequencer, which clocks the
+// length counters (256 Hz), the sweep (128 Hz) and the envelopes (64 Hz)
+func (snd *HwGbaSound) sequencer() {
+	step := snd.seqStep
+	snd.seqStep = (snd.seqStep + 1) & 7
+
+	if step&1 == 0 {
+		for i := range snd.ch {
+			c := &snd.ch[i]
+			if c.on && snd.ctlReg(i).Value&0x4000 != 0 && c.length > 0 {
+				c.length--
+				if c.length == 0 {
+					c.on = false
+				}
+			}
+		}
+	}
+
+	if step == 2 || step == 6 {
+		snd.sweep()
+	}
+
+	if step == 7 {
+		for _, i := range []int{0, 1, 3} {
+			c := &snd.ch[i]
+			env := snd.envReg(i)
+			steps := int(env >> 8 & 7)
+			if !c.on || steps == 0 {
+				continue
+			}
+			if c.envTimer--; c.envTimer <= 0 {
+				c.envTimer = steps
+				if env&0x800 != 0 && c.vol < 15 {
+					c.vol++
+				} else if env&0x800 == 0 && c.vol > 0 {
+					c.vol--
+				}
+			}
+		}
+	}
+}
+
+func (snd *HwGbaSound) sweep() {
+	c := &snd.ch[0]
+	cnt := snd.Snd1CntL.Value
+	pace := int(cnt >> 4 & 7)
+	if !c.on || pace == 0 {
+		return
+	}
+	if c.sweepTimer--; c.sweepTimer > 0 {
+		return
+	}
+	c.sweepTimer = pace
+
+	freq := int(snd.Snd1CntX.Value & 0x7FF)
+	delta := freq >> (cnt & 7)
+	if cnt&8 != 0 {
+		freq -= delta
+	} else {
+		freq += delta
+	}
+	if freq > 0x7FF {
+		c.on = false
+	} else if freq >= 0 && cnt&7 != 0 {
+		snd.Snd1CntX.Value = snd.Snd1CntX.Value&^0x7FF | uint16(freq)
+	}
+}
+
+// waveSample returns a 4-bit sample of the wave RAM, from the bank selected
+// for playback and then (in 64-sample mode) the other one. The CPU accesses
+// the bank which is not selected, through WaveRam.
+func (snd *HwGbaSound) waveSample(pos uint) int {
+	cnt := snd.Snd3CntL.Value
+	if cnt&0x20 == 0 {
+		pos &= 31
+	}
+	bank := (uint(cnt>>6) + pos/32) & 1
+	data := snd.wave[bank][:]
+	if bank != uint(cnt>>6&1) {
+		data = snd.WaveRam.Data
+	}
+	b := data[(pos&31)/2]
+	if pos&1 == 0 {
+		return int(b >> 4)
+	}
+	return int(b & 0xF)
+}
+
+// amplitude returns the current output of a PSG channel, in the range
+// -15..15
+func (snd *HwGbaSound) amplitude(idx int) int {
+	c := &snd.ch[idx]
+	switch idx {
+	case 0, 1:
+		duty := gbaSquareDuty[snd.envReg(idx)>>6&3]
+		if duty>>c.pos&1 != 0 {
+			return c.vol
+		}
+		return -c.vol
+	case 2:
+		s := snd.waveSample(c.pos)*2 - 15
+		switch cnt := snd.Snd3CntH.Value; {
+		case cnt&0x8000 != 0:
+			return s * 3 / 4
+		case cnt>>13&3 == 0:
+			return 0
+		default:
+			return s >> (cnt>>13&3 - 1)
+		}
+	default:
+		if c.high {
+			return c.vol
+		}
+		return -c.vol
+	}
+}
+
+// mix returns the current output (left and right)
+func (snd *HwGbaSound) mix() (int16, int16) {
+	if !snd.enabled() {
+		return 0, 0
+	}
+	cntl, cnth := snd.SndCntL.Value, snd.SndCntH.Value
+
+	// PSG channels, with master volume (1-8) and ratio (25/50/100%)
+	var out [2]int // right, left
+	for i := range snd.ch {
+		if !snd.ch[i].on {
+			continue
+		}
+		amp := snd.amplitude(i)
+		if cntl&(0x100<<uint(i)) != 0 {
+			out[0] += amp
+		}
+		if cntl&(0x1000<<uint(i)) != 0 {
+			out[1] += amp
+		}
+	}
+	shift := uint(2)
+	if ratio := uint(cnth & 3); ratio < 3 {
+		shift = 2 - ratio
+	}
+	out[0] = out[0] * (int(cntl&7) + 1) >> shift
+	out[1] = out[1] * (int(cntl>>4&7) + 1) >> shift
+
+	// Direct Sound (50/100%)
+	for i := range snd.fifo {
+		s := int(snd.fifo[i].cur) * 2
+		if cnth&(4<<uint(i)) != 0 {
+			s *= 2
+		}
+		if cnth&(0x100<<(4*uint(i))) != 0 {
+			out[0] += s
+		}
+		if cnth&(0x200<<(4*uint(i))) != 0 {
+			out[1] += s
+		}
+	}
+
+	// The mixer output is 10-bit
+	return int16(clampInt16(int64(out[1]) << 6)), int16(clampInt16(int64(out[0]) << 6))
+}
+
+func (snd *HwGbaSound) WriteSND1CNTL(old, val uint16) { snd.writeReg(&snd.Snd1CntL, old, val) }
+func (snd *HwGbaSound) WriteSND1CNTH(old, val uint16) {
+	snd.writeReg(&snd.Snd1CntH, old, val)
+	snd.reloadLength(0)
+}
+func (snd *HwGbaSound) WriteSND1CNTX(old, val uint16) { snd.writeCtl(0, old, val) }
+func (snd *HwGbaSound) WriteSND2CNTL(old, val uint16) {
+	snd.writeReg(&snd.Snd2CntL, old, val)
+	snd.reloadLength(1)
+}
+func (snd *HwGbaSound) WriteSND2CNTH(old, val uint16) { snd.writeCtl(1, old, val) }
+func (snd *HwGbaSound) WriteSND3CNTH(old, val uint16) {
+	snd.writeReg(&snd.Snd3CntH, old, val)
+	snd.reloadLength(2)
+}
+func (snd *HwGbaSound) WriteSND3CNTX(old, val uint16) { snd.writeCtl(2, old, val) }
+func (snd *HwGbaSound) WriteSND4CNTL(old, val uint16) {
+	snd.writeReg(&snd.Snd4CntL, old, val)
+	snd.reloadLength(3)
+}
+func (snd *HwGbaSound) WriteSND4CNTH(old, val uint16) { snd.writeCtl(3, old, val) }
+func (snd *HwGbaSound) WriteSNDCNTL(old, val uint16)  { snd.writeReg(&snd.SndCntL, old, val) }
+
+func (snd *HwGbaSound) WriteSND3CNTL(old, val uint16) {
+	snd.writeReg(&snd.Snd3CntL, old, val)
+
+	// The bank accessible by the CPU is the one not selected for playback
+	if (old^val)&0x40 != 0 {
+		oldcpu, newcpu := (old>>6&1)^1, (val>>6&1)^1
+		copy(snd.wave[oldcpu][:], snd.WaveRam.Data)
+		copy(snd.WaveRam.Data, snd.wave[newcpu][:])
+	}
+	if val&0x80 == 0 {
+		snd.ch[2].on = false
+	}
+}
+
+func (snd *HwGbaSound) WriteSNDCNTH(old, val uint16) {
+	snd.writeReg(&snd.SndCntH, old, val)
+
+	// Bits 11 and 15 reset the FIFOs, and always read as zero
+	for i := range snd.fifo {
+		if val&(0x800<<(4*uint(i))) != 0 {
+			snd.fifo[i] = gbaFifo{}
+		}
+	}
+	snd.SndCntH.Value &^= 0x8800
+}
+
+func (snd *HwGbaSound) WriteSNDCNTX(old, val uint16) {
+	snd.writeReg(&snd.SndCntX, old, val)
+	if val&0x80 == 0 {
+		for i := range snd.ch {
+			snd.ch[i].on = false
+		}
+	}
+}
+
+// ReadSNDCNTX returns the master enable, and whether each PSG channel is
+// playing
+func (snd *HwGbaSound) ReadSNDCNTX(val uint16) uint16 {
+	snd.sync()
+	val &= 0x80
+	for i := range snd.ch {
+		if snd.ch[i].on {
+			val |= 1 << uint(i)
+		}
+	}
+	return val
+}
+
+// writeReg brings the emulation up to date before a register write takes
+// effect
+func (snd *HwGbaSound) writeReg(reg *hwio.Reg16, old, val uint16) {
+	reg.Value = old
+	snd.sync()
+	reg.Value = val
+}
+
+// writeCtl handles writes to the control register of a PSG channel, where
+// bit 15 (write-only) restarts the channel
+func (snd *HwGbaSound) writeCtl(idx int, old, val uint16) {
+	reg := snd.ctlReg(idx)
+	snd.writeReg(reg, old, val)
+	reg.Value &^= 0x8000
+	if val&0x8000 != 0 {
+		snd.restart(idx)
+	}
+}
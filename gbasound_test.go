@@ -0,0 +1,115 @@
+package main
+
+import (
+	"ndsemu/emu"
+	"testing"
+)
+
+func TestGbaSoundFifo(t *testing.T) {
+	snd := NewHwGbaSound(nil, nil)
+	snd.SndCntX.Write16(0, 0x80)
+	snd.SndCntH.Write16(0, 0x4000|0x0300|0x4) // A: timer 0, both sides, 100%; B: timer 1
+
+	snd.pushFifo(0, []byte{0x40, 0xC0})
+	snd.pushFifo(1, []byte{0x10})
+
+	snd.TimerOverflow(0, 0)
+	if l, r := snd.mix(); l != 256<<6 || r != 256<<6 {
+		t.Errorf("first sample: got %d/%d", l, r)
+	}
+	if snd.fifo[1].n != 1 {
+		t.Errorf("FIFO B clocked by the wrong timer")
+	}
+
+	// Timers above 1 are not connected to the FIFOs
+	snd.TimerOverflow(2, 0)
+	snd.TimerOverflow(0, 0)
+	if l, _ := snd.mix(); l != -256<<6 {
+		t.Errorf("second sample: got %d", l)
+	}
+
+	// The last sample is repeated when the FIFO is empty
+	snd.TimerOverflow(0, 0)
+	if l, _ := snd.mix(); l != -256<<6 {
+		t.Errorf("underflow: got %d", l)
+	}
+}
+
+func TestGbaSoundSquareFrequency(t *testing.T) {
+	snd := NewHwGbaSound(nil, nil)
+	snd.SndCntX.Write16(0, 0x80)
+	snd.SndCntL.Write16(0, 0x1177) // channel 1 on both sides, max volume
+	snd.SndCntH.Write16(0, 2)      // PSG at 100%
+	snd.Snd1CntH.Write16(0, 0xF080)
+	snd.Snd1CntX.Write16(0, 0x8000|(2048-64)) // 8192 cycles per wave
+
+	buf := make([]int16, 546*2)
+	snd.BeginFrame(buf, 280896)
+	snd.EndFrame()
+
+	changes := 0
+	for i := 2; i < len(buf); i += 2 {
+		if (buf[i] > 0) != (buf[i-2] > 0) {
+			changes++
+		}
+	}
+	// 280896/8192 = 34.3 waves per frame, each with two transitions
+	if changes < 67 || changes > 70 {
+		t.Errorf("got %d transitions, want ~68", changes)
+	}
+}
+
+func TestGbaSoundFrameSync(t *testing.T) {
+	cfg := *GbaSyncConfig
+	cfg.HSync, cfg.VSync = nil, nil
+	sync, err := emu.NewSync(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snd := NewHwGbaSound(nil, sync)
+	sync.AddSubsystem(snd, "apu")
+
+	snd.SndCntX.Write16(0, 0x80)
+	snd.SndCntL.Write16(0, 0x1077)     // channel 1 on the left, max volume
+	snd.SndCntH.Write16(0, 0x0100|0x6) // PSG at 100%, FIFO A on the right at 100%
+	snd.Snd1CntH.Write16(0, 0xF080)
+	snd.Snd1CntX.Write16(0, 0x8000|(2048-64)) // 8192 cycles per wave
+	snd.pushFifo(0, []byte{0x7F})
+
+	// Clock FIFO A at 3/4 of the frame; the timers count at the bus clock
+	frameCycles := sync.FrameCycles()
+	sync.ScheduleEvent(frameCycles*3/4, func() {
+		snd.TimerOverflow(0, frameCycles*3/4*cTimerClock/cGbaClock)
+	})
+
+	buf := make([]int16, 546*2)
+	snd.BeginFrame(buf, frameCycles)
+	sync.RunOneFrame()
+	if snd.outPos != len(buf)/2 {
+		t.Errorf("got %d samples, want %d", snd.outPos, len(buf)/2)
+	}
+	snd.EndFrame()
+
+	changes := 0
+	for i := 2; i < len(buf); i += 2 {
+		if (buf[i] > 0) != (buf[i-2] > 0) {
+			changes++
+		}
+	}
+	// 280896/8192 = 34.3 waves per frame, each with two transitions
+	if changes < 67 || changes > 70 {
+		t.Errorf("got %d transitions, want ~68", changes)
+	}
+
+	// The FIFO sample is output on the right from 3/4 of the frame
+	first := -1
+	for i := 1; i < len(buf); i += 2 {
+		if buf[i] != 0 {
+			first = i / 2
+			break
+		}
+	}
+	if want := len(buf) / 2 * 3 / 4; first < want-1 || first > want+1 {
+		t.Errorf("FIFO sample output from sample %d, want %d", first, want)
+	}
+}
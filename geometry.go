@@ -5,6 +5,7 @@ import (
 	"ndsemu/emu/fixed"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"ndsemu/emu/trace"
 	"ndsemu/raster3d"
 )
 
@@ -25,8 +26,18 @@ type GxCmdDesc struct {
 	exec    func(*GeometryEngine, []GxCmd)
 }
 
+const (
+	cGxFifoSize = 256 // entries of the FIFO
+	cGxPipeSize = 4   // entries of the PIPE, filled before the FIFO
+)
+
+// GxFifo holds the commands waiting to be executed by the geometry engine.
+// On hardware, they go through a 4-entry PIPE, which is filled first, and
+// then through the 256-entry FIFO; the flow control (GXSTAT, FIFO IRQ and
+// DMA) only looks at the FIFO, so it's not empty until the PIPE is full.
+// Here, both are a single queue, whose first entries are the PIPE.
 type GxFifo struct {
-	cmds [256]GxCmd
+	cmds [512]GxCmd
 	r    int64
 	w    int64
 }
@@ -40,7 +51,7 @@ func (f *GxFifo) Push(cmd GxCmd) {
 	if f.Full() {
 		panic("gxfifo push full")
 	}
-	f.cmds[f.w&255] = cmd
+	f.cmds[f.w&511] = cmd
 	f.w++
 }
 
@@ -48,21 +59,21 @@ func (f *GxFifo) Pop() GxCmd {
 	if f.r >= f.w {
 		panic("gxfifo pop empty")
 	}
-	cmd := f.cmds[f.r&255]
+	cmd := f.cmds[f.r&511]
 	f.r++
 	return cmd
 }
 
 func (f *GxFifo) Top() *GxCmd {
-	if f.Empty() {
+	if f.r >= f.w {
 		return nil
 	}
-	return &f.cmds[f.r&255]
+	return &f.cmds[f.r&511]
 }
 
 func (f *GxFifo) HasCmdTest() bool {
 	for i := f.r; i < f.w; i++ {
-		cmd := &f.cmds[i&255]
+		cmd := &f.cmds[i&511]
 		if cmd.code == GX_VEC_TEST || cmd.code == GX_POS_TEST || cmd.code == GX_BOX_TEST {
 			return true
 		}
@@ -72,7 +83,7 @@ func (f *GxFifo) HasCmdTest() bool {
 
 func (f *GxFifo) HasCmdMatrix() bool {
 	for i := f.r; i < f.w; i++ {
-		cmd := &f.cmds[i&255]
+		cmd := &f.cmds[i&511]
 		if cmd.code == GX_MTX_PUSH || cmd.code == GX_MTX_POP {
 			return true
 		}
@@ -80,12 +91,20 @@ func (f *GxFifo) HasCmdMatrix() bool {
 	return false
 }
 
-// FIXME: these function don't include the 4-slot pipe into account.
-// We should probably increase the fifo by 4 entries
-func (f *GxFifo) Len() int               { return int(f.w - f.r) }
-func (f *GxFifo) Empty() bool            { return f.r >= f.w }
-func (f *GxFifo) Full() bool             { return f.Len() >= 256 }
-func (f *GxFifo) LessThanHalfFull() bool { return f.Len() < 128 }
+// Len returns the number of queued commands, including the PIPE
+func (f *GxFifo) Len() int { return int(f.w - f.r) }
+
+// FifoLen returns the number of commands in the FIFO, excluding the PIPE
+func (f *GxFifo) FifoLen() int {
+	if n := f.Len() - cGxPipeSize; n > 0 {
+		return n
+	}
+	return 0
+}
+
+func (f *GxFifo) Empty() bool            { return f.FifoLen() == 0 }
+func (f *GxFifo) Full() bool             { return f.FifoLen() >= cGxFifoSize }
+func (f *GxFifo) LessThanHalfFull() bool { return f.FifoLen() < cGxFifoSize/2 }
 
 type HwGeometry struct {
 	// Bank 0 (0x4000400). Main geometry FIFO
@@ -104,6 +123,7 @@ type HwGeometry struct {
 	VecResultX hwio.Reg16 `hwio:"bank=1,offset=0x30,readonly,rcb"`
 	VecResultY hwio.Reg16 `hwio:"bank=1,offset=0x32,readonly,rcb"`
 	VecResultZ hwio.Reg16 `hwio:"bank=1,offset=0x34,readonly,rcb"`
+	Disp1Dot   hwio.Reg16 `hwio:"bank=1,offset=0x10,rwmask=0x7FFF,reset=0x7FFF,writeonly,wcb"`
 
 	// Bank 2 (0x4000300). Various tables and parameters
 	Edge0 hwio.Reg16 `hwio:"bank=2,offset=0x30,writeonly"`
@@ -119,6 +139,8 @@ type HwGeometry struct {
 	fifoRegCnt int
 
 	irq    *HwIrq
+	cpu    *NDS9
+	sched  Scheduler
 	gx     GeometryEngine
 	busy   bool
 	cycles int64
@@ -136,9 +158,11 @@ type HwGeometry struct {
 	}
 }
 
-func NewHwGeometry(irq *HwIrq, e3d *raster3d.HwEngine3d) *HwGeometry {
+func NewHwGeometry(cpu *NDS9, e3d *raster3d.HwEngine3d, sched Scheduler) *HwGeometry {
 	g := new(HwGeometry)
-	g.irq = irq
+	g.irq = cpu.Irq
+	g.cpu = cpu
+	g.sched = sched
 	g.gx.e3d = e3d
 	hwio.MustInitRegs(g)
 	// FIXME: these callbacks were already populated through reflection, but the resulting
@@ -154,15 +178,17 @@ func NewHwGeometry(irq *HwIrq, e3d *raster3d.HwEngine3d) *HwGeometry {
 func (g *HwGeometry) ReadGXSTAT(val uint32) uint32 {
 	// Sync to the current CPU cycle, so that we return an accurate
 	// value
-	g.Run(Emu.Sync.Cycles())
+	g.Run(g.sched.Cycles())
 
 	// Bit 0: true if there is a box/pos/vec test pending
 	if g.fifo.HasCmdTest() {
 		val |= (1 << 0)
 	}
 
-	// FIXME: for now, always return OK to "box test" command (not implemented)
-	val |= 1 << 1
+	// Bit 1: result of the last box test (1=visible)
+	if g.gx.boxTestResult {
+		val |= 1 << 1
+	}
 
 	if g.fifo.LessThanHalfFull() {
 		val |= (1 << 25)
@@ -174,8 +200,8 @@ func (g *HwGeometry) ReadGXSTAT(val uint32) uint32 {
 		val |= (1 << 27) // busy bit
 	}
 
-	// Bits 16-24: Entries in the FIFO
-	val |= uint32(g.fifo.Len()&0x1ff) << 16
+	// Bits 16-24: Entries in the FIFO (the PIPE is not counted)
+	val |= uint32(g.fifo.FifoLen()&0x1ff) << 16
 
 	// Bits 8-12: Position matrix stack (only 5 bits)
 	val |= (uint32(g.gx.mtxStackPosPtr) & 0x1F) << 8
@@ -198,22 +224,18 @@ func (g *HwGeometry) ReadGXSTAT(val uint32) uint32 {
 }
 
 func (g *HwGeometry) ReadRAMCOUNT(_ uint32) uint32 {
-	vtx := Emu.Hw.E3d.NumVertices()
-	poly := Emu.Hw.E3d.NumPolygons()
-
-	if vtx > 2048 {
-		vtx = 2048
-	}
-	if poly > 6144 {
-		poly = 6144
-	}
-
-	// FIXME: used by zelda-hourglass, but we can't
-	// implement it properly until clipping is implemented,
-	// otherwise the returned value makes the game hang
-	//
-	// modGxFifo.Warnf("RAMCOUNT: %d,%d", vtx, poly)
-	return 0 //uint32(vtx)<<16 | uint32(poly)
+	// Sync to the current CPU cycle, so that all the geometry
+	// submitted so far is accounted
+	g.Run(g.sched.Cycles())
+
+	// The 3D engine counts polygons and vertices the same way the
+	// hardware stores them (after culling and clipping), and never
+	// exceeds the size of the polygon/vertex RAM (2048/6144). RAMCOUNT used
+	// to read as zero because, before clipping was implemented, the counts
+	// made games that poll it (eg: Zelda Phantom Hourglass) hang.
+	vtx := g.gx.e3d.NumVertices()
+	poly := g.gx.e3d.NumPolygons()
+	return uint32(vtx)<<16 | uint32(poly)
 }
 
 func (g *HwGeometry) readVecResult(vec fixed.F12) uint16 {
@@ -243,15 +265,23 @@ func (g *HwGeometry) ReadPOSRESULTY(_ uint32) uint32 { return uint32(g.gx.posTes
 func (g *HwGeometry) ReadPOSRESULTZ(_ uint32) uint32 { return uint32(g.gx.posTestResult[2].V) }
 func (g *HwGeometry) ReadPOSRESULTW(_ uint32) uint32 { return uint32(g.gx.posTestResult[3].V) }
 
+func (g *HwGeometry) WriteDISP1DOT(old, val uint16) {
+	// Polygons already in the FIFO are processed with the previous value
+	g.Run(g.sched.Cycles())
+	g.gx.e3d.SetOneDotDepth(val)
+}
+
 func (g *HwGeometry) WriteGXSTAT(old, val uint32) {
+	// Bit 15 is computed on read from the geometry engine status
 	g.GxStat.Value &^= 0x8000
 	if val&0x8000 != 0 {
-		// turn off overflow flag
-		old &^= 0x8000
-		// Also reset matrix stat pointer
+		// Acknowledge the matrix stack error. This also resets the
+		// projection and texture stack pointers (but not the position one)
+		g.Run(g.sched.Cycles())
+		g.gx.mtxStackOverflow = false
 		g.gx.mtxStackProjPtr = 0
+		g.gx.mtxStackTexPtr = 0
 	}
-	g.GxStat.Value |= old & 0x8000
 }
 
 func (g *HwGeometry) WriteGXFIFO(addr uint32, bytes int) {
@@ -260,7 +290,7 @@ func (g *HwGeometry) WriteGXFIFO(addr uint32, bytes int) {
 		modGxFifo.ErrorZ("non 32-bit write to GXFIFO").End()
 	}
 
-	now := Emu.Sync.Cycles()
+	now := g.sched.Cycles()
 	val := binary.LittleEndian.Uint32(g.GxFifo.Data[0:4])
 	modGxFifo.DebugZ("write to GXFIFO").
 		Hex32("val", val).
@@ -330,13 +360,14 @@ func (g *HwGeometry) fifoPush(when int64, code uint8, parm uint32) {
 	// up to the current timestamp. This might be enough to flush
 	// the FIFO a little bit and make room for the new command.
 	if g.fifo.Full() {
-		g.Run(Emu.Sync.Cycles())
+		g.Run(g.sched.Cycles())
 	}
 
 	// If the FIFO is still full, it means that the CPU is
 	// really writing to a full FIFO. The CPU will be blocked
 	// until the FIFO frees up a space.
 	panicCount := 0
+	stall := g.sched.Cycles()
 	for g.fifo.Full() {
 		// Burn CPU cycles that should be enough to execute
 		// the next FIFO command.
@@ -349,30 +380,37 @@ func (g *HwGeometry) fifoPush(when int64, code uint8, parm uint32) {
 			cycles = 1
 		}
 		// It could be that the FIFO is full but the geometry processor
-		// just swallowed a SwapBuffer and is waiting for VBlank; in this
-		// case, a write will block the CPU until VBlank as well; so
-		// make sure to align the Cpu clock to the Geometry clock if this
-		// is far in the future. Necessary for eledees (title screen).
-		if g.cycles*2 > nds9.Cpu.Clock {
-			nds9.Cpu.Clock = g.cycles * 2
+		// is stalled on a SwapBuffer waiting for VBlank; in this case, a
+		// write will block the CPU until VBlank as well; so make sure
+		// to align the Cpu clock to the VBlank (or to the Geometry clock,
+		// if it's in the future). Necessary for eledees (title screen).
+		wait := g.cycles
+		if g.fifo.Top().code == GX_SWAP_BUFFERS {
+			wait = g.nextVBlank(g.cycles)
+		}
+		if wait*2 > g.cpu.Cpu.Clock {
+			g.cpu.Cpu.Clock = wait * 2
 		}
-		nds9.Cpu.Clock += cycles * 2
+		g.cpu.Cpu.Clock += cycles * 2
 
 		// Now synchronize the geometry engine. Since the CPU has
 		// burnt some cycles, this should allow us to run a little
 		// bit and consume the FIFO.
-		g.Run(Emu.Sync.Cycles())
+		g.Run(g.sched.Cycles())
 
 		// Debug counter to avoid infinite loop: if the FIFO is not
 		// consumed, it's a bug in our code, just abort.
 		panicCount++
 		if panicCount > 128 {
-			modGxFifo.InfoZ("stalled geometry engine").Hex8("top", uint8(g.fifo.Top().code)).Int64("cycles", nds9.Cpu.Clock).End()
+			modGxFifo.InfoZ("stalled geometry engine").Hex8("top", uint8(g.fifo.Top().code)).Int64("cycles", g.cpu.Cpu.Clock).End()
 		}
 		if panicCount == 1024 {
 			modGxFifo.PanicZ("stalled geometry engine").Hex8("top", uint8(g.fifo.Top().code)).End()
 		}
 	}
+	if panicCount > 0 && trace.Enabled() {
+		trace.Complete("gxfifo", "cpu stall (fifo full)", stall, nil)
+	}
 
 	cmd := GxCmd{
 		when: when,
@@ -392,7 +430,7 @@ func (g *HwGeometry) WriteGXCMD(addr uint32, bytes int) {
 	val := binary.LittleEndian.Uint32(g.GxCmd.Data[0:4])
 	// modGxFifo.WithField("val", emu.Hex32(val)).WithField("addr", emu.Hex32(addr)).Infof("Write GXCMD")
 	cmd := uint8((addr-0x4000440)/4 + 0x10)
-	now := Emu.Sync.Cycles()
+	now := g.sched.Cycles()
 	g.fifoPush(now, cmd, val)
 	g.updateIrq()
 }
@@ -411,6 +449,20 @@ func (g *HwGeometry) Cycles() int64 {
 	return g.cycles
 }
 
+// nextVBlank returns the instant at which the first VBlank at or after the
+// specified instant begins
+func (g *HwGeometry) nextVBlank(when int64) int64 {
+	frame := int64(NdsSyncConfig.HDots * NdsSyncConfig.VDots * NdsSyncConfig.DotClockDivider)
+	vblank := g.sched.Cycles() + g.sched.DotPosDistance(0, NdsLcdConfig.VBlankFirstLine)
+	for vblank-frame >= when {
+		vblank -= frame
+	}
+	for vblank < when {
+		vblank += frame
+	}
+	return vblank
+}
+
 func (g *HwGeometry) nextCmdCycles() int64 {
 	if cmd := g.fifo.Top(); cmd != nil {
 		return g.gx.CalcCmdCycles(cmd.code)
@@ -421,7 +473,7 @@ func (g *HwGeometry) nextCmdCycles() int64 {
 func (g *HwGeometry) Run(target int64) {
 	if g.fifo.LessThanHalfFull() {
 		// modGxFifo.WithField("fifolen", len(g.fifo)).Info("trigger GXFIFO DMA")
-		nds9.TriggerDmaEvent(DmaEventGxFifo)
+		g.cpu.TriggerDmaEvent(DmaEventGxFifo)
 	}
 
 	for g.cycles < target {
@@ -449,6 +501,31 @@ func (g *HwGeometry) Run(target int64) {
 			break
 		}
 
+		// SwapBuffers is special, because it is executed at the next VBlank
+		// (plus its own 392 cycles); until then, the geometry engine stalls
+		// with the command in the PIPE. Its parameter is latched as well,
+		// and only applied to the scene at the swap: this matters for
+		// RAMCOUNT, whose counters are reset by the swap.
+		if cmd.code == GX_SWAP_BUFFERS {
+			if g.cycles < cmd.when {
+				g.cycles = cmd.when
+			}
+			vblank := g.nextVBlank(g.cycles)
+			if vblank > target {
+				g.cycles = target
+				g.busy = true
+				break
+			}
+			x, y := g.sched.DotPos()
+			modGx.Infof("SwapBuffers: %d cmd, total:%d; waited until (%d,%d) for: %d",
+				g.framestats.numcmd, g.cycles-g.framestats.start, x, y, vblank-g.cycles)
+			g.cycles = vblank
+			g.framestats.numcmd = 0
+			g.framestats.start = g.cycles + cycles
+		} else {
+			g.framestats.numcmd++
+		}
+
 		// Extract parameters
 		for i := 0; i < nparms+1; i++ {
 			g.curcmd[i] = g.fifo.Pop()
@@ -469,26 +546,11 @@ func (g *HwGeometry) Run(target int64) {
 			desc.exec(&g.gx, g.curcmd[:nparms+1])
 		}
 
-		// SwapBuffer is special, because it always waits for a VBlank before
-		// beginning execution, plus its own 392 cycles. So move the timing
-		// forward until we reach the next vblank point, so that we simulate
-		// having waited until then.
-		if cmd.code == GX_SWAP_BUFFERS {
-			x, y := Emu.Sync.DotPos()
-			dpd := Emu.Sync.DotPosDistance(0, 192)
-			modGx.Infof("SwapBuffers: %d cmd, total:%d; now at (%d,%d) to vsync: %d",
-				g.framestats.numcmd, g.cycles-g.framestats.start, x, y, dpd)
-			g.cycles += dpd
-			g.framestats.numcmd = 0
-			g.framestats.start = g.cycles + cycles
-		} else {
-			g.framestats.numcmd++
-		}
 		g.cycles += cycles
 
 		if g.fifo.LessThanHalfFull() {
 			// modGxFifo.WithField("fifolen", len(g.fifo)).Info("trigger GXFIFO DMA")
-			nds9.TriggerDmaEvent(DmaEventGxFifo)
+			g.cpu.TriggerDmaEvent(DmaEventGxFifo)
 		}
 		g.busy = true
 	}
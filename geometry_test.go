@@ -0,0 +1,116 @@
+package main
+
+import (
+	"ndsemu/raster3d"
+	"testing"
+)
+
+// gxTestScheduler is a Scheduler following the clock of the ARM9, like the
+// real one does while the ARM9 is running. The frame begins at cycle 0.
+type gxTestScheduler struct{ nds9 *NDS9 }
+
+func (s *gxTestScheduler) Cycles() int64 { return s.nds9.Cpu.Clock / 2 }
+
+func (s *gxTestScheduler) DotPos() (int, int) {
+	cfg := NdsSyncConfig
+	line := int64(cfg.HDots * cfg.DotClockDivider)
+	clk := s.Cycles() % (line * int64(cfg.VDots))
+	return int(clk % line / int64(cfg.DotClockDivider)), int(clk / line)
+}
+
+func (s *gxTestScheduler) DotPosDistance(x, y int) int64 {
+	cfg := NdsSyncConfig
+	cx, cy := s.DotPos()
+	dist := int64(((y-cy)*cfg.HDots + x - cx) * cfg.DotClockDivider)
+	if dist < 0 {
+		dist += int64(cfg.HDots * cfg.VDots * cfg.DotClockDivider)
+	}
+	return dist
+}
+
+func (s *gxTestScheduler) ScheduleSync(when int64)     {}
+func (s *gxTestScheduler) CancelSync(when int64)       {}
+func (s *gxTestScheduler) ScheduleEvent(int64, func()) {}
+
+func newTestGeometry() (*HwGeometry, *NDS9) {
+	sched := new(gxTestScheduler)
+	sched.nds9 = NewNDS9(false, sched)
+	return NewHwGeometry(sched.nds9, raster3d.NewHwEngine3d(), sched), sched.nds9
+}
+
+func TestGxFifoPipe(t *testing.T) {
+	var f GxFifo
+	for _, tc := range []struct {
+		len         int
+		fifolen     int
+		empty, full bool
+		half        bool // less than half full
+	}{
+		{0, 0, true, false, true},
+		{4, 0, true, false, true}, // the PIPE is full, the FIFO is empty
+		{5, 1, false, false, true},
+		{131, 127, false, false, true},
+		{132, 128, false, false, false},
+		{259, 255, false, false, false},
+		{260, 256, false, true, false},
+	} {
+		for f.Len() < tc.len {
+			f.Push(GxCmd{})
+		}
+		if f.FifoLen() != tc.fifolen || f.Empty() != tc.empty || f.Full() != tc.full || f.LessThanHalfFull() != tc.half {
+			t.Errorf("%d commands: fifolen=%d empty=%v full=%v half=%v", tc.len,
+				f.FifoLen(), f.Empty(), f.Full(), f.LessThanHalfFull())
+		}
+	}
+}
+
+func TestGxFifoFullStall(t *testing.T) {
+	g, nds9 := newTestGeometry()
+	cycles := g.gx.CalcCmdCycles(GX_MTX_IDENTITY)
+
+	// The geometry engine doesn't run until the CPU clock advances, so the
+	// first 260 commands fill the PIPE and the FIFO; each further write
+	// stalls the ARM9 until the engine has executed a command.
+	for i := 0; i < 300; i++ {
+		g.fifoPush(g.sched.Cycles(), uint8(GX_MTX_IDENTITY), 0)
+		if g.fifo.FifoLen() > cGxFifoSize {
+			t.Fatalf("FIFO overflow: %d entries", g.fifo.FifoLen())
+		}
+	}
+	if want := 40 * cycles * 2; nds9.Cpu.Clock != want {
+		t.Errorf("ARM9 stalled for %d cycles, want %d", nds9.Cpu.Clock, want)
+	}
+}
+
+func TestGxSwapBuffers(t *testing.T) {
+	vblank := int64(NdsLcdConfig.VBlankFirstLine * NdsSyncConfig.HDots * NdsSyncConfig.DotClockDivider)
+
+	// SwapBuffers is executed at the next VBlank; until then, the engine
+	// stalls with the command in the PIPE
+	g, _ := newTestGeometry()
+	swap := g.gx.CalcCmdCycles(GX_SWAP_BUFFERS)
+	g.fifoPush(0, uint8(GX_SWAP_BUFFERS), 0)
+	g.fifoPush(0, uint8(GX_MTX_IDENTITY), 0)
+	if v := g.nextVBlank(0); v != vblank {
+		t.Errorf("next VBlank at %d, want %d", v, vblank)
+	}
+	g.Run(vblank - 1)
+	if g.fifo.Top().code != GX_SWAP_BUFFERS || g.cycles != vblank-1 {
+		t.Errorf("before VBlank: top=%v, cycles=%d", g.fifo.Top().code, g.cycles)
+	}
+	g.Run(vblank + swap)
+	if g.fifo.Top().code != GX_MTX_IDENTITY || g.cycles != vblank+swap {
+		t.Errorf("after VBlank: top=%v, cycles=%d, want %d", g.fifo.Top().code, g.cycles, vblank+swap)
+	}
+
+	// Writing to the full FIFO while the engine waits for VBlank stalls the
+	// ARM9 until VBlank, and then for the duration of SwapBuffers
+	g, nds9 := newTestGeometry()
+	g.fifoPush(0, uint8(GX_SWAP_BUFFERS), 0)
+	for i := 0; i < 260; i++ {
+		g.fifoPush(g.sched.Cycles(), uint8(GX_MTX_IDENTITY), 0)
+	}
+	if want := (vblank + g.gx.CalcCmdCycles(GX_SWAP_BUFFERS)) * 2; nds9.Cpu.Clock != want {
+		t.Errorf("ARM9 stalled until %d, want %d", nds9.Cpu.Clock, want)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"ndsemu/emu/hw"
+	"strconv"
+	"strings"
+)
+
+// GestureKind is a stylus motion that can be synthesized (see Gesture)
+type GestureKind int
+
+const (
+	GestureSpinCW GestureKind = iota
+	GestureSpinCCW
+	GestureFlickUp
+	GestureFlickDown
+	GestureFlickLeft
+	GestureFlickRight
+)
+
+var gestureNames = map[string]GestureKind{
+	"spin-cw": GestureSpinCW, "spin-ccw": GestureSpinCCW,
+	"flick-up": GestureFlickUp, "flick-down": GestureFlickDown,
+	"flick-left": GestureFlickLeft, "flick-right": GestureFlickRight,
+}
+
+// Pseudo-scancodes used as triggers for the mouse wheel
+const (
+	gestureWheelUp   = -1
+	gestureWheelDown = -2
+)
+
+// Gesture is a stylus motion, triggered by a key or by the mouse wheel, that
+// is synthesized on the touchscreen: spins (a full circle around the pointer)
+// and flicks (a quick straight stroke starting at the pointer, followed by
+// the stylus being lifted). Keys repeat the gesture while held; each step of
+// the mouse wheel performs it once.
+type Gesture struct {
+	Kind    GestureKind
+	Trigger int // scancode of the key, or gestureWheelUp/Down
+	Size    int // radius of the spin, or length of the flick (in pixels)
+	Frames  int // duration of the gesture (a full turn for spins)
+}
+
+func (g *Gesture) spin() bool {
+	return g.Kind == GestureSpinCW || g.Kind == GestureSpinCCW
+}
+
+// offset returns the position of the stylus relative to the starting point
+// of the gesture, after the specified number of frames
+func (g *Gesture) offset(frame int) (int, int) {
+	t := float64(frame) / float64(g.Frames)
+	size := float64(g.Size)
+	switch g.Kind {
+	case GestureSpinCW, GestureSpinCCW:
+		// Screen coordinates grow downward, so increasing angles are
+		// clockwise. The stylus starts on the right of the center.
+		a := 2 * math.Pi * t
+		if g.Kind == GestureSpinCCW {
+			a = -a
+		}
+		return int(math.Floor(size*math.Cos(a) + 0.5)), int(math.Floor(size*math.Sin(a) + 0.5))
+	case GestureFlickUp:
+		return 0, -int(size * t)
+	case GestureFlickDown:
+		return 0, int(size * t)
+	case GestureFlickLeft:
+		return -int(size * t), 0
+	default:
+		return int(size * t), 0
+	}
+}
+
+// parseGestureTrigger parses the name of a trigger: "wheel-up",
+// "wheel-down", or a letter or digit key
+func parseGestureTrigger(name string) (int, bool) {
+	switch {
+	case name == "wheel-up":
+		return gestureWheelUp, true
+	case name == "wheel-down":
+		return gestureWheelDown, true
+	case len(name) != 1:
+		return 0, false
+	case name[0] >= 'a' && name[0] <= 'z':
+		return hw.SCANCODE_A + int(name[0]-'a'), true
+	case name[0] == '0':
+		return hw.SCANCODE_0, true
+	case name[0] >= '1' && name[0] <= '9':
+		return hw.SCANCODE_1 + int(name[0]-'1'), true
+	}
+	return 0, false
+}
+
+// ParseGestures parses a comma-separated list of gestures in the form
+// "trigger=gesture[:size[:frames]]" (eg: "wheel-up=spin-cw,g=flick-up:80:3"),
+// where trigger is "wheel-up", "wheel-down", or a letter or digit key, and
+// gesture is one of spin-cw, spin-ccw, flick-up, flick-down, flick-left and
+// flick-right.
+func ParseGestures(spec string) ([]Gesture, error) {
+	var gestures []Gesture
+	for _, s := range strings.Split(spec, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		parts := strings.SplitN(strings.ToLower(s), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid gesture: %q (use trigger=gesture)", s)
+		}
+		trigger, ok := parseGestureTrigger(parts[0])
+		if !ok {
+			return nil, fmt.Errorf("invalid gesture trigger: %q", parts[0])
+		}
+
+		fields := strings.Split(parts[1], ":")
+		kind, ok := gestureNames[fields[0]]
+		if !ok || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid gesture: %q", parts[1])
+		}
+		g := Gesture{Kind: kind, Trigger: trigger, Size: 64, Frames: 4}
+		if g.spin() {
+			g.Size, g.Frames = 32, 12
+		}
+		for i, f := range fields[1:] {
+			n, err := strconv.Atoi(f)
+			if err != nil || n <= 0 || n > 255 {
+				return nil, fmt.Errorf("invalid gesture parameter: %q", f)
+			}
+			if i == 0 {
+				g.Size = n
+			} else {
+				g.Frames = n
+			}
+		}
+		gestures = append(gestures, g)
+	}
+	return gestures, nil
+}
+
+// Maximum number of mouse wheel steps queued for each gesture
+const cGestureMaxQueued = 4
+
+// GestureSynth performs the configured gestures, producing the position of
+// the stylus for each frame.
+type GestureSynth struct {
+	Gestures []Gesture
+
+	cur    int // index of the gesture being performed, or -1
+	frame  int // frames elapsed since the beginning of the gesture
+	x0, y0 int // starting point (center for spins)
+	lift   bool
+	queued []int // wheel steps still to be performed, for each gesture
+}
+
+func NewGestureSynth(gestures []Gesture) *GestureSynth {
+	return &GestureSynth{
+		Gestures: gestures,
+		cur:      -1,
+		queued:   make([]int, len(gestures)),
+	}
+}
+
+func (gs *GestureSynth) triggered(idx int) bool {
+	g := &gs.Gestures[idx]
+	if g.Trigger < 0 {
+		return gs.queued[idx] > 0
+	}
+	return g.Trigger < len(KeyState) && KeyState[g.Trigger] != 0
+}
+
+// Update computes the state of the stylus for the next frame, given the
+// mouse wheel steps since the previous frame and the position of the pointer
+// on the touchscreen. It returns false if no gesture is being performed, in
+// which case the stylus follows the mouse.
+func (gs *GestureSynth) Update(wheel int, x, y int, inside bool) (int, int, bool) {
+	for i := range gs.Gestures {
+		switch gs.Gestures[i].Trigger {
+		case gestureWheelUp:
+			gs.queued[i] += wheel
+		case gestureWheelDown:
+			gs.queued[i] -= wheel
+		default:
+			continue
+		}
+		if gs.queued[i] < 0 {
+			gs.queued[i] = 0
+		} else if gs.queued[i] > cGestureMaxQueued {
+			gs.queued[i] = cGestureMaxQueued
+		}
+	}
+
+	// Let the stylus up for a frame after a flick, so that the next one is
+	// seen as a new stroke
+	if gs.lift {
+		gs.lift = false
+		return 0, 0, false
+	}
+
+	// At the end of a turn, a spin that is still triggered keeps going
+	// around the same center
+	if gs.cur >= 0 && gs.Gestures[gs.cur].spin() && gs.frame == gs.Gestures[gs.cur].Frames {
+		if gs.triggered(gs.cur) {
+			gs.start(gs.cur)
+		} else {
+			gs.cur = -1
+		}
+	}
+
+	if gs.cur < 0 {
+		for i := range gs.Gestures {
+			if gs.triggered(i) {
+				gs.start(i)
+				gs.x0, gs.y0 = 256/2, 192/2
+				if inside {
+					gs.x0, gs.y0 = x, y
+				}
+				break
+			}
+		}
+		if gs.cur < 0 {
+			return 0, 0, false
+		}
+	}
+
+	g := &gs.Gestures[gs.cur]
+	dx, dy := g.offset(gs.frame)
+	if !g.spin() && gs.frame == g.Frames {
+		gs.cur = -1
+		gs.lift = true
+	}
+	gs.frame++
+	return clampInt(gs.x0+dx, 0, 255), clampInt(gs.y0+dy, 0, 191), true
+}
+
+// start begins performing the specified gesture
+func (gs *GestureSynth) start(idx int) {
+	gs.cur = idx
+	gs.frame = 0
+	if gs.Gestures[idx].Trigger < 0 {
+		gs.queued[idx]--
+	}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	} else if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"ndsemu/emu/hw"
+	"testing"
+)
+
+func TestParseGestures(t *testing.T) {
+	gs, err := ParseGestures("wheel-up=spin-cw, G=flick-up:80:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Gesture{
+		{Kind: GestureSpinCW, Trigger: gestureWheelUp, Size: 32, Frames: 12},
+		{Kind: GestureFlickUp, Trigger: hw.SCANCODE_G, Size: 80, Frames: 3},
+	}
+	if len(gs) != len(want) || gs[0] != want[0] || gs[1] != want[1] {
+		t.Errorf("got %+v", gs)
+	}
+
+	for _, bad := range []string{"spin-cw", "wheel=spin-cw", "g=spin", "g=flick-up:0", "g=flick-up:1:2:3"} {
+		if _, err := ParseGestures(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestGestureFlick(t *testing.T) {
+	gs := NewGestureSynth([]Gesture{{Kind: GestureFlickRight, Trigger: gestureWheelDown, Size: 30, Frames: 3}})
+
+	type pen struct {
+		x, y int
+		down bool
+	}
+	var got []pen
+	for i, wheel := range []int{-2, 0, 0, 0, 0, 0, 0, 0, 0, 0} {
+		x, y, down := gs.Update(wheel, 100+i, 50, true)
+		got = append(got, pen{x, y, down})
+	}
+
+	// Two strokes from the pointer, separated by the stylus being lifted
+	want := []pen{
+		{100, 50, true}, {110, 50, true}, {120, 50, true}, {130, 50, true}, {0, 0, false},
+		{105, 50, true}, {115, 50, true}, {125, 50, true}, {135, 50, true}, {0, 0, false},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -219,7 +219,8 @@ type GeometryEngine struct {
 	}
 	vcnt int
 
-	// Pos/Vec test results
+	// Box/Pos/Vec test results
+	boxTestResult bool
 	posTestResult vector
 	vecTestResult vector
 
@@ -392,23 +393,27 @@ func (gx *GeometryEngine) cmdMtxPush(parms []GxCmd) {
 	modGx.InfoZ("mtx push").Int("mode", int(gx.mtxmode)).End()
 	switch gx.mtxmode {
 	case MtxProjection:
+		// The projection stack has a single entry: pushing when it's
+		// already full sets the error flag, and the push is discarded.
 		if gx.mtxStackProjPtr > 0 {
 			gx.mtxStackOverflow = true
+			return
 		}
-
-		// The "1" entry is a mirror of "0", so always access 0
 		gx.mtxStackProj[0] = gx.mtx[MtxProjection]
 		gx.mtxStackProjPtr++
-		gx.mtxStackProjPtr &= 1
 	case MtxTexture:
+		// Same as projection stack, but the pointer is not visible in GXSTAT
 		if gx.mtxStackTexPtr > 0 {
 			gx.mtxStackOverflow = true
+			return
 		}
-		// The "1" entry is a mirror of "0", so always access 0
 		gx.mtxStackTex[0] = gx.mtx[MtxTexture]
 		gx.mtxStackTexPtr++
-		gx.mtxStackTexPtr &= 1
 	case MtxPosition, MtxDirection:
+		// The position/vector stack has 31 entries (0-30), but the pointer
+		// is 6-bit. Overflowing sets the error flag, but the push still
+		// happens (into the mirrored entries) and the pointer keeps
+		// incrementing and wrapping.
 		if gx.mtxStackPosPtr > 30 {
 			gx.mtxStackOverflow = true
 		}
@@ -426,27 +431,22 @@ func (gx *GeometryEngine) cmdMtxPop(parms []GxCmd) {
 	modGx.InfoZ("mtx pop").Int("mode", int(gx.mtxmode)).End()
 	switch gx.mtxmode {
 	case MtxProjection:
-		// NOTE: the offset parameter is ignored
-		gx.mtxStackProjPtr -= 1
-		gx.mtxStackProjPtr &= 1
-
-		if gx.mtxStackProjPtr > 0 {
+		// NOTE: the offset parameter is ignored. Popping from an empty
+		// stack sets the error flag, and the pop is discarded.
+		if gx.mtxStackProjPtr == 0 {
 			gx.mtxStackOverflow = true
+			return
 		}
-
-		// The "1" entry is a mirror of "0", so always access 0
+		gx.mtxStackProjPtr--
 		gx.mtx[MtxProjection] = gx.mtxStackProj[0]
 		gx.recalcClipMtx()
 	case MtxTexture:
 		// NOTE: the offset parameter is ignored
-		gx.mtxStackTexPtr -= 1
-		gx.mtxStackTexPtr &= 1
-
-		if gx.mtxStackTexPtr > 0 {
+		if gx.mtxStackTexPtr == 0 {
 			gx.mtxStackOverflow = true
+			return
 		}
-
-		// The "1" entry is a mirror of "0", so always access 0
+		gx.mtxStackTexPtr--
 		gx.mtx[MtxTexture] = gx.mtxStackTex[0]
 	case MtxPosition, MtxDirection:
 		// 6-bit signed offset, -30 / +31
@@ -466,39 +466,41 @@ func (gx *GeometryEngine) cmdMtxPop(parms []GxCmd) {
 	}
 }
 
+// MTX_STORE and MTX_RESTORE access the stacks by index, without changing the
+// stack pointers. For the projection and texture stacks, the index is ignored.
+// For the position/vector stack, index 31 is out of range: it sets the error
+// flag, but the access still happens (on the hidden 32nd entry).
 func (gx *GeometryEngine) cmdMtxStore(parms []GxCmd) {
 	switch gx.mtxmode {
-	case 0:
-		gx.mtxStackProj[0] = gx.mtx[0]
-	case 1, 2:
+	case MtxProjection:
+		gx.mtxStackProj[0] = gx.mtx[MtxProjection]
+	case MtxPosition, MtxDirection:
 		idx := int(parms[0].parm & 0x1F)
 		if idx > 30 {
-			// OVERFLOW FLAG
-			modGx.FatalZ("MTX_STORE caused overflow in pos stack").End()
+			gx.mtxStackOverflow = true
 		}
-		gx.mtxStackPos[idx] = gx.mtx[1]
-		gx.mtxStackDir[idx] = gx.mtx[2]
-	case 3:
-		gx.mtxStackTex[0] = gx.mtx[3]
+		gx.mtxStackPos[idx] = gx.mtx[MtxPosition]
+		gx.mtxStackDir[idx] = gx.mtx[MtxDirection]
+	case MtxTexture:
+		gx.mtxStackTex[0] = gx.mtx[MtxTexture]
 	}
 }
 
 func (gx *GeometryEngine) cmdMtxRestore(parms []GxCmd) {
 	switch gx.mtxmode {
-	case 0:
-		gx.mtx[0] = gx.mtxStackProj[0]
+	case MtxProjection:
+		gx.mtx[MtxProjection] = gx.mtxStackProj[0]
 		gx.recalcClipMtx()
-	case 1, 2:
+	case MtxPosition, MtxDirection:
 		idx := int(parms[0].parm & 0x1F)
 		if idx > 30 {
-			// OVERFLOW FLAG
-			modGx.FatalZ("MTX_RESTORE caused overflow in pos stack").End()
+			gx.mtxStackOverflow = true
 		}
-		gx.mtx[1] = gx.mtxStackPos[idx]
-		gx.mtx[2] = gx.mtxStackDir[idx]
+		gx.mtx[MtxPosition] = gx.mtxStackPos[idx]
+		gx.mtx[MtxDirection] = gx.mtxStackDir[idx]
 		gx.recalcClipMtx()
-	case 3:
-		gx.mtx[3] = gx.mtxStackTex[0]
+	case MtxTexture:
+		gx.mtx[MtxTexture] = gx.mtxStackTex[0]
 	}
 }
 
@@ -904,9 +906,99 @@ func (gx *GeometryEngine) cmdVecTest(parms []GxCmd) {
 	// modGx.Warnf("n:%v res:%v mtx:%v", n, gx.vecTestResult, gx.mtx[MtxDirection])
 }
 
+// Faces of the box tested by BOX_TEST, as indices of its corners
+// (bit 0: X+width, bit 1: Y+height, bit 2: Z+depth)
+var boxTestFaces = [6][4]int{
+	{0, 1, 3, 2}, {4, 5, 7, 6}, // front, back
+	{0, 1, 5, 4}, {2, 3, 7, 6}, // bottom, top
+	{0, 2, 6, 4}, {1, 3, 7, 5}, // left, right
+}
+
+func (gx *GeometryEngine) cmdBoxTest(parms []GxCmd) {
+	x := int32(int16(parms[0].parm & 0xFFFF))
+	y := int32(int16(parms[0].parm >> 16))
+	z := int32(int16(parms[1].parm & 0xFFFF))
+	w := int32(int16(parms[1].parm >> 16))
+	h := int32(int16(parms[2].parm & 0xFFFF))
+	d := int32(int16(parms[2].parm >> 16))
+
+	var corners [8]vector
+	for i := range corners {
+		var v vector
+		v[0].V, v[1].V, v[2].V, v[3].V = x, y, z, 1<<12
+		if i&1 != 0 {
+			v[0].V += w
+		}
+		if i&2 != 0 {
+			v[1].V += h
+		}
+		if i&4 != 0 {
+			v[2].V += d
+		}
+		corners[i] = gx.clipmtx.VecMul(v)
+	}
+
+	// The box is visible if any of its faces, clipped against the view
+	// volume, is not empty. Check first the common case of a corner
+	// within the view volume.
+	gx.boxTestResult = false
+	for _, c := range corners {
+		if clipInside(c, 0) && clipInside(c, 1) && clipInside(c, 2) {
+			gx.boxTestResult = true
+			return
+		}
+	}
+	for _, face := range boxTestFaces {
+		poly := []vector{corners[face[0]], corners[face[1]], corners[face[2]], corners[face[3]]}
+		if len(clipPolyVolume(poly)) != 0 {
+			gx.boxTestResult = true
+			return
+		}
+	}
+}
+
+// clipInside returns true if the vector (in clip space) is within the view
+// volume, along the specified axis
+func clipInside(v vector, axis int) bool {
+	return v[axis].V >= -v[3].V && v[axis].V <= v[3].V
+}
+
+// clipPolyVolume clips a polygon (in clip space) against the six planes
+// of the view volume, and returns the resulting polygon (possibly empty)
+func clipPolyVolume(poly []vector) []vector {
+	for plane := 0; plane < 6; plane++ {
+		axis, sign := plane/2, int64(1-2*(plane&1))
+
+		// Distance from the plane (positive: inside)
+		dist := func(v vector) int64 { return int64(v[3].V) - sign*int64(v[axis].V) }
+
+		var out []vector
+		last := poly[len(poly)-1]
+		for _, v := range poly {
+			d0, d1 := dist(last), dist(v)
+			if (d0 >= 0) != (d1 >= 0) {
+				var iv vector
+				for k := range iv {
+					iv[k].V = last[k].V + int32(int64(v[k].V-last[k].V)*d0/(d0-d1))
+				}
+				out = append(out, iv)
+			}
+			if d1 >= 0 {
+				out = append(out, v)
+			}
+			last = v
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		poly = out
+	}
+	return poly
+}
+
 func (gx *GeometryEngine) cmdSwapBuffers(parms []GxCmd) {
 	gx.e3d.CmdSwapBuffers(raster3d.Primitive_SwapBuffers{
-		AlphaYSort: parms[0].parm&1 != 0,
+		AlphaYSort: parms[0].parm&1 == 0, // 0=auto-sort, 1=manual-sort
 		WBuffering: parms[0].parm&2 != 0,
 	})
 	gx.vcnt = 0
@@ -1019,7 +1111,7 @@ var gxCmdDescs = []GxCmdDesc{
 	// 0x6C
 	{0, 0, nil}, {0, 0, nil}, {0, 0, nil}, {0, 0, nil},
 	// 0x70
-	{0, 0, nil}, {2, 9, (*GeometryEngine).cmdPosTest}, {1, 5, (*GeometryEngine).cmdVecTest}, {0, 0, nil},
+	{3, 103, (*GeometryEngine).cmdBoxTest}, {2, 9, (*GeometryEngine).cmdPosTest}, {1, 5, (*GeometryEngine).cmdVecTest}, {0, 0, nil},
 	// 0x74
 	{0, 0, nil}, {0, 0, nil}, {0, 0, nil}, {0, 0, nil},
 	// 0x78
@@ -0,0 +1,105 @@
+package main
+
+import "testing"
+
+func TestBoxTest(t *testing.T) {
+	var gx GeometryEngine
+	for i := 0; i < 4; i++ {
+		gx.clipmtx[i][i].V = 1 << 12
+	}
+
+	box := func(x, y, z, w, h, d float64) bool {
+		f := func(v float64) uint32 { return uint32(uint16(int16(v * 4096))) }
+		gx.cmdBoxTest([]GxCmd{
+			{parm: f(x) | f(y)<<16},
+			{parm: f(z) | f(w)<<16},
+			{parm: f(h) | f(d)<<16},
+		})
+		return gx.boxTestResult
+	}
+
+	for _, tc := range []struct {
+		x, y, z, w, h, d float64
+		visible          bool
+	}{
+		{-0.5, -0.5, -0.5, 1, 1, 1, true},        // corners inside
+		{2, 2, 2, 1, 1, 1, false},                // outside
+		{-4, -0.5, -0.5, 7, 1, 1, true},          // crossing the volume, no corners inside
+		{-4, 1.5, -0.5, 7, 1, 1, false},          // above the volume
+		{-3, -3, -3, 6, 6, 6, false},             // surrounding the volume
+		{-0.5, -0.5, -3, 1, 1, 6, true},          // crossing near and far planes
+		{-1.5, -1.5, -0.25, 1.2, 1.2, 0.5, true}, // crossing a corner of the volume
+	} {
+		if got := box(tc.x, tc.y, tc.z, tc.w, tc.h, tc.d); got != tc.visible {
+			t.Errorf("box %v: got %v", tc, got)
+		}
+	}
+}
+
+func TestMtxStack(t *testing.T) {
+	var gx GeometryEngine
+	mode := func(m int) { gx.cmdMtxMode([]GxCmd{{parm: uint32(m)}}) }
+	mark := func(v int32) { gx.mtx[gx.mtxmode][0][0].V = v }
+	push := func() { gx.cmdMtxPush(nil) }
+	pop := func(n int) { gx.cmdMtxPop([]GxCmd{{parm: uint32(n) & 0x3F}}) }
+	check := func(step string, ptr int, overflow bool, v int32) {
+		t.Helper()
+		p := gx.mtxStackPosPtr
+		switch gx.mtxmode {
+		case MtxProjection:
+			p = gx.mtxStackProjPtr
+		case MtxTexture:
+			p = gx.mtxStackTexPtr
+		}
+		if p != ptr || gx.mtxStackOverflow != overflow || gx.mtx[gx.mtxmode][0][0].V != v {
+			t.Errorf("mode %d, %s: ptr=%d overflow=%v mtx=%d, want %d %v %d",
+				gx.mtxmode, step, p, gx.mtxStackOverflow, gx.mtx[gx.mtxmode][0][0].V, ptr, overflow, v)
+		}
+		gx.mtxStackOverflow = false
+	}
+
+	// Projection and texture stacks have a single entry; overflowing and
+	// underflowing set the error flag, and the command is discarded
+	for _, m := range []int{MtxProjection, MtxTexture} {
+		mode(m)
+		mark(1)
+		push()
+		check("push", 1, false, 1)
+		mark(2)
+		push()
+		check("push (full)", 1, true, 2)
+		pop(1)
+		check("pop", 0, false, 1)
+		mark(3)
+		pop(1)
+		check("pop (empty)", 0, true, 3)
+	}
+
+	// The position stack has 31 entries; overflowing sets the error flag,
+	// but the pointer keeps moving
+	mode(MtxPosition)
+	for i := 0; i < 31; i++ {
+		mark(int32(i))
+		push()
+	}
+	check("31 pushes", 31, false, 30)
+	mark(31)
+	push()
+	check("32nd push", 32, true, 31)
+	pop(2)
+	check("pop 2", 30, false, 30)
+	pop(-5)
+	check("pop -5", 35, true, 3)
+	pop(31)
+	check("pop 31", 4, false, 4)
+
+	// STORE/RESTORE don't move the pointer; index 31 is out of range
+	mark(100)
+	gx.cmdMtxStore([]GxCmd{{parm: 31}})
+	check("store 31", 4, true, 100)
+	mark(0)
+	gx.cmdMtxRestore([]GxCmd{{parm: 31}})
+	check("restore 31", 4, true, 100)
+	gx.cmdMtxRestore([]GxCmd{{parm: 7}})
+	check("restore 7", 4, false, 7)
+}
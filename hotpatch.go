@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"ndsemu/arm"
+	"ndsemu/arm/asm"
+	"ndsemu/arm/disasm"
+	log "ndsemu/emu/logger"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var modHotPatch = log.NewModule("hotpatch")
+
+// HotPatchSet is a list of patches of guest code, applied in memory while the
+// game runs (unlike ROM patches, see package patch), for instance to bypass
+// anti-piracy checks or to fix bugs. It is loaded from a TOML file:
+//
+//	game = "ABCE"                  # optional: NDS game code the patches are for
+//
+//	[[patch]]
+//	name = "AP bypass"
+//	cpu = "arm9"                   # arm9 (default) or arm7
+//	addr = "02012340"              # hex
+//	original = "04 00 a0 e3"       # bytes expected at addr, as in memory
+//	replace = "00 00 a0 e3"        # replacement bytes...
+//
+//	[[patch]]
+//	name = "Fix crash in level 3"
+//	addr = "020C0010"
+//	original = "00 00 91 e5 1e ff 2f e1"
+//	asm = ["mov r0, #0", "bx lr"]  # ...or replacement code (see package asm)
+//	thumb = false
+//	when = "always"
+//
+// A patch is applied only when the original bytes are found in memory,
+// which is checked at the end of each frame; this way, patches can target
+// code that is loaded at runtime (like overlays), and are never applied to
+// the wrong code. "when" selects how many times a patch is applied:
+//
+//	load             only once after boot (default)
+//	always           every time the original bytes appear (eg: for
+//	                 overlays that are loaded more than once)
+//	ADDR[/SIZE]=VAL  while the memory location has the specified value
+//	                 (same syntax as -freeze); when it doesn't, the
+//	                 original bytes are restored
+type HotPatchSet struct {
+	Game    string      `toml:"game"`
+	Patches []*HotPatch `toml:"patch"`
+}
+
+// HotPatch is a patch of guest code (see HotPatchSet)
+type HotPatch struct {
+	Name     string   `toml:"name"`
+	Cpu      string   `toml:"cpu"`
+	Addr     string   `toml:"addr"`
+	Original string   `toml:"original"`
+	Replace  string   `toml:"replace"`
+	Asm      []string `toml:"asm"`
+	Thumb    bool     `toml:"thumb"`
+	When     string   `toml:"when"`
+
+	addr    uint32
+	orig    []byte
+	code    []byte
+	always  bool
+	cond    *FreezeEntry
+	applied bool
+}
+
+// LoadHotPatches loads a set of patches from a file (see HotPatchSet)
+func LoadHotPatches(fn string) (*HotPatchSet, error) {
+	var hs HotPatchSet
+	md, err := toml.DecodeFile(fn, &hs)
+	if err != nil {
+		return nil, err
+	}
+	if undec := md.Undecoded(); len(undec) > 0 {
+		return nil, fmt.Errorf("%s: unknown key %q", fn, undec[0].String())
+	}
+	for i, p := range hs.Patches {
+		if err := p.compile(); err != nil {
+			name := p.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			return nil, fmt.Errorf("%s: patch %s: %v", fn, name, err)
+		}
+	}
+	return &hs, nil
+}
+
+// FindHotPatches looks for a patch file next to a ROM file, with the same
+// name and extension .hotpatch (eg: "game.hotpatch" or "game.nds.hotpatch"
+// for "game.nds"). It returns an empty string if there is none.
+func FindHotPatches(romfn string) string {
+	base := romfn
+	if idx := strings.LastIndexByte(romfn, '.'); idx >= 0 && !strings.ContainsAny(romfn[idx:], `/\`) {
+		base = romfn[:idx]
+	}
+	for _, b := range []string{base, romfn} {
+		if fi, err := os.Stat(b + ".hotpatch"); err == nil && fi.Mode().IsRegular() {
+			return b + ".hotpatch"
+		}
+	}
+	return ""
+}
+
+func parseHexBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.Join(strings.Fields(s), ""))
+}
+
+// compile validates the patch, and computes the bytes to write
+func (p *HotPatch) compile() error {
+	arch := disasm.ARMv5
+	switch p.Cpu {
+	case "", "arm9":
+		p.Cpu = "arm9"
+	case "arm7":
+		arch = disasm.ARMv4
+	default:
+		return fmt.Errorf("invalid cpu: %q (use arm9 or arm7)", p.Cpu)
+	}
+
+	addr, err := strconv.ParseUint(strings.TrimPrefix(p.Addr, "0x"), 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid address: %q", p.Addr)
+	}
+	p.addr = uint32(addr)
+
+	if p.orig, err = parseHexBytes(p.Original); err != nil || len(p.orig) == 0 {
+		return fmt.Errorf("invalid original bytes: %q", p.Original)
+	}
+
+	switch {
+	case p.Replace != "" && len(p.Asm) != 0:
+		return fmt.Errorf("both replace and asm specified")
+	case p.Replace != "":
+		if p.code, err = parseHexBytes(p.Replace); err != nil || len(p.code) == 0 {
+			return fmt.Errorf("invalid replacement bytes: %q", p.Replace)
+		}
+	case len(p.Asm) != 0:
+		pc := p.addr
+		for _, text := range p.Asm {
+			buf, err := asm.Assemble(text, pc, p.Thumb, arch)
+			if err != nil {
+				return fmt.Errorf("%q: %v", text, err)
+			}
+			p.code = append(p.code, buf...)
+			pc += uint32(len(buf))
+		}
+	default:
+		return fmt.Errorf("no replacement specified (replace or asm)")
+	}
+
+	switch p.When {
+	case "", "load":
+	case "always":
+		p.always = true
+	default:
+		cond, err := ParseFreezeEntry(p.When)
+		if err != nil {
+			return fmt.Errorf("invalid condition: %v", err)
+		}
+		p.cond = &cond
+	}
+	return nil
+}
+
+func (p *HotPatch) String() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return fmt.Sprintf("%s:%08x", p.Cpu, p.addr)
+}
+
+// matches checks whether the memory at the patch address contains data
+func (p *HotPatch) matches(cpu *arm.Cpu, data []byte) bool {
+	for i, b := range data {
+		if cpu.Peek8(p.addr+uint32(i)) != b {
+			return false
+		}
+	}
+	return true
+}
+
+// condition evaluates the condition of the patch (true if there is none)
+func (p *HotPatch) condition(cpu *arm.Cpu) bool {
+	if p.cond == nil {
+		return true
+	}
+	var val uint32
+	for i := 0; i < p.cond.Size/8; i++ {
+		val |= uint32(cpu.Peek8(p.cond.Addr+uint32(i))) << (8 * uint(i))
+	}
+	return val == p.cond.Value
+}
+
+func (p *HotPatch) write(cpu *arm.Cpu, data []byte, msg string) {
+	if err := cpu.Patch(p.addr, data); err != nil {
+		modHotPatch.ErrorZ("cannot write patch").Stringer("patch", p).Error("err", err).End()
+		return
+	}
+	modHotPatch.InfoZ(msg).Stringer("patch", p).Hex32("addr", p.addr).End()
+}
+
+// Reset makes all patches applicable again, after the console is reset
+func (hs *HotPatchSet) Reset() {
+	for _, p := range hs.Patches {
+		p.applied = false
+	}
+}
+
+// Apply applies the patches whose original bytes are in memory (see
+// HotPatchSet). It must be called between frames.
+func (hs *HotPatchSet) Apply() {
+	for _, p := range hs.Patches {
+		if p.applied && !p.always && p.cond == nil {
+			continue
+		}
+		cpu, err := bridgeCpu(p.Cpu)
+		if err != nil {
+			continue
+		}
+
+		if !p.condition(cpu) {
+			if p.applied && p.matches(cpu, p.code) {
+				p.write(cpu, p.orig, "patch reverted")
+			}
+			p.applied = false
+			continue
+		}
+		if p.matches(cpu, p.orig) {
+			p.write(cpu, p.code, "patch applied")
+			p.applied = true
+		}
+	}
+}
+
+// SetHotPatches installs the patches to apply to guest code (nil removes
+// them). Patches written for another game (see HotPatchSet) are rejected.
+func (emu *NDSEmulator) SetHotPatches(hs *HotPatchSet) error {
+	if hs != nil && hs.Game != "" {
+		var gamecode [4]byte
+		emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+		if !strings.EqualFold(hs.Game, string(gamecode[:])) {
+			return fmt.Errorf("patches are for game %s, not %s", hs.Game, gamecode[:])
+		}
+	}
+	emu.HotPatches = hs
+	return nil
+}
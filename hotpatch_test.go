@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadHotPatches(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "game.hotpatch")
+	ioutil.WriteFile(fn, []byte(`
+game = "ABCE"
+
+[[patch]]
+name = "bytes"
+addr = "02000100"
+original = "04 00 a0 e3"
+replace = "00 00 a0 e3"
+
+[[patch]]
+cpu = "arm7"
+addr = "0x037F8000"
+original = "0000 0000"
+asm = ["movs r0, #1", "bx lr"]
+thumb = true
+when = "027FFC00/8=1"
+`), 0644)
+
+	hs, err := LoadHotPatches(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hs.Game != "ABCE" || len(hs.Patches) != 2 {
+		t.Fatalf("invalid set: %+v", hs)
+	}
+
+	p := hs.Patches[0]
+	if p.Cpu != "arm9" || p.addr != 0x02000100 || p.cond != nil || p.always {
+		t.Errorf("invalid patch: %+v", p)
+	}
+	if !bytes.Equal(p.code, []byte{0x00, 0x00, 0xa0, 0xe3}) {
+		t.Errorf("invalid code: % x", p.code)
+	}
+
+	p = hs.Patches[1]
+	if p.String() != "arm7:037f8000" {
+		t.Errorf("invalid name: %q", p.String())
+	}
+	if !bytes.Equal(p.code, []byte{0x01, 0x20, 0x70, 0x47}) {
+		t.Errorf("invalid code: % x", p.code)
+	}
+	if p.cond == nil || p.cond.Addr != 0x027FFC00 || p.cond.Size != 8 || p.cond.Value != 1 {
+		t.Errorf("invalid condition: %v", p.cond)
+	}
+
+	if fhp := FindHotPatches(filepath.Join(dir, "game.nds")); fhp != fn {
+		t.Errorf("hot-patches not found: %q", fhp)
+	}
+}
+
+func TestLoadHotPatchesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		patch string
+		err   string
+	}{
+		{`addr = "zz"`, "invalid address"},
+		{`addr = "0"` + "\n" + `original = "0"`, "invalid original bytes"},
+		{`addr = "0"` + "\n" + `original = "00"`, "no replacement"},
+		{`addr = "0"` + "\n" + `original = "00"` + "\n" + `replace = "00"` + "\n" + `asm = ["nop"]`, "both"},
+		{`addr = "0"` + "\n" + `original = "00"` + "\n" + `asm = ["foo r0"]`, "foo"},
+		{`addr = "0"` + "\n" + `original = "00"` + "\n" + `replace = "00"` + "\n" + `when = "never"`, "invalid condition"},
+		{`cpu = "arm11"`, "invalid cpu"},
+		{`address = "0"`, "unknown key"},
+	} {
+		fn := filepath.Join(t.TempDir(), "game.hotpatch")
+		ioutil.WriteFile(fn, []byte("[[patch]]\n"+tc.patch+"\n"), 0644)
+		if _, err := LoadHotPatches(fn); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%q: unexpected error: %v", tc.patch, err)
+		}
+	}
+}
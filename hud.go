@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"ndsemu/emu/gfx"
+	"strings"
+	"time"
+)
+
+// Host time spent in the parts of the emulation that are not subsystems of
+// the scheduler (they run in the hsync callback). See NDSEmulator.perf.
+const (
+	perf2d = iota
+	perfNumCounters
+)
+
+type PerfCounters [perfNumCounters]time.Duration
+
+func (p *PerfCounters) start() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (p *PerfCounters) stop(idx int, t0 time.Time) {
+	if p != nil {
+		p[idx] += time.Since(t0)
+	}
+}
+
+// Number of frames over which HUD statistics are averaged
+const cHudFrames = 30
+
+// PerfHud is an overlay showing the emulation speed, and how the host time
+// of each frame is spent:
+//
+//	FPS  emulated frames per second
+//	EMU  time spent emulating a frame; HOST is the rest of the frame time
+//	     (presentation, audio output, and waiting to run at normal speed)
+//	ARM9, ARM7, GX, TMR, AUD: time spent in each subsystem of the scheduler
+//	2D   time spent drawing lines
+//	3D   time spent rasterizing the last scene (in parallel with emulation)
+//	JIT  share of the CPU cycles executed by JIT-compiled code
+//
+// All times are in milliseconds per frame.
+type PerfHud struct {
+	Enabled bool
+
+	perf    PerfCounters
+	frames  int
+	window  time.Time
+	emuTime time.Duration
+	subs    map[string]time.Duration
+	jit     [2]int64 // JIT cycles at the beginning of the window
+	clk     [2]int64 // CPU cycles at the beginning of the window
+	text    []string
+}
+
+func NewPerfHud() *PerfHud {
+	return &PerfHud{subs: make(map[string]time.Duration)}
+}
+
+// SetEnabled shows or hides the HUD. Counters are only collected while the
+// HUD is visible, so that they cost nothing otherwise.
+func (h *PerfHud) SetEnabled(emu *NDSEmulator, enable bool) {
+	h.Enabled = enable
+	emu.Sync.SetProfiling(enable)
+	if enable {
+		emu.perf = &h.perf
+	} else {
+		emu.perf = nil
+	}
+	h.resetWindow(emu)
+	h.text = []string{"..."}
+}
+
+func (h *PerfHud) resetWindow(emu *NDSEmulator) {
+	h.perf = PerfCounters{}
+	h.frames = 0
+	h.window = time.Now()
+	h.emuTime = 0
+	h.subs = make(map[string]time.Duration)
+	emu.Sync.HostTimes()
+	h.jit[0], h.clk[0] = nds9.Cpu.JitCycles, nds9.Cpu.Clock
+	h.jit[1], h.clk[1] = nds7.Cpu.JitCycles, nds7.Cpu.Clock
+}
+
+// EndFrame accounts a frame that took emutime to emulate
+func (h *PerfHud) EndFrame(emu *NDSEmulator, emutime time.Duration) {
+	if !h.Enabled {
+		return
+	}
+	h.emuTime += emutime
+	for name, t := range emu.Sync.HostTimes() {
+		h.subs[name] += t
+	}
+	if h.frames++; h.frames < cHudFrames {
+		return
+	}
+
+	n := time.Duration(h.frames)
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.2f", float64(d/n)/float64(time.Millisecond))
+	}
+	elapsed := time.Since(h.window)
+	fps := float64(h.frames) / elapsed.Seconds()
+
+	jit := "JIT OFF"
+	if nds9.Cpu.Jit() != nil {
+		share := func(jitc, clk, jit0, clk0 int64) int {
+			if clk <= clk0 || jitc < jit0 {
+				return 0
+			}
+			return int((jitc - jit0) * 100 / (clk - clk0))
+		}
+		jit = fmt.Sprintf("JIT ARM9 %d%% ARM7 %d%%",
+			share(nds9.Cpu.JitCycles, nds9.Cpu.Clock, h.jit[0], h.clk[0]),
+			share(nds7.Cpu.JitCycles, nds7.Cpu.Clock, h.jit[1], h.clk[1]))
+	}
+
+	h.text = []string{
+		fmt.Sprintf("FPS %.1f EMU %s HOST %s", fps, ms(h.emuTime), ms(elapsed-h.emuTime)),
+		fmt.Sprintf("ARM9 %s ARM7 %s GX %s TMR %s", ms(h.subs["arm9"]), ms(h.subs["arm7"]),
+			ms(h.subs["gx"]), ms(h.subs["timers9"]+h.subs["timers7"])),
+		fmt.Sprintf("2D %s 3D %.2f AUD %s", ms(h.perf[perf2d]),
+			float64(emu.Hw.E3d.DrawTime())/float64(time.Millisecond), ms(h.subs["spu"]+h.subs["apu"])),
+		jit,
+	}
+	h.resetWindow(emu)
+}
+
+// Draw renders the HUD in the top-left corner of the screen
+func (h *PerfHud) Draw(screen gfx.Buffer) {
+	if !h.Enabled {
+		return
+	}
+	drawTextBox(screen, h.text, false)
+}
+
+// How long OSD messages stay on screen
+const cOsdDuration = 2 * time.Second
+
+// Osd shows short notifications (eg: the effect of a debugging hotkey) in
+// the bottom-left corner of the screen, with the HUD font.
+type Osd struct {
+	text  string
+	until time.Time
+}
+
+// Show displays a message, replacing the current one
+func (o *Osd) Show(format string, args ...interface{}) {
+	o.text = fmt.Sprintf(format, args...)
+	o.until = time.Now().Add(cOsdDuration)
+}
+
+// Draw renders the current message, if any
+func (o *Osd) Draw(screen gfx.Buffer) {
+	if o.text == "" || time.Now().After(o.until) {
+		return
+	}
+	drawTextBox(screen, []string{o.text}, true)
+}
+
+// drawTextBox draws lines of text over a darkened box, in the top-left
+// corner of the screen (or bottom-left, if bottom is true)
+func drawTextBox(screen gfx.Buffer, text []string, bottom bool) {
+	const cw, ch = 4, 6 // size of a character cell (including spacing)
+
+	w := 0
+	for _, s := range text {
+		if len(s) > w {
+			w = len(s)
+		}
+	}
+	w, hh := w*cw+3, len(text)*ch+3
+	if w > screen.Width {
+		w = screen.Width
+	}
+	if hh > screen.Height {
+		hh = screen.Height
+	}
+	top := 0
+	if bottom {
+		top = screen.Height - hh
+	}
+
+	// Darken the background to make text readable
+	for y := 0; y < hh; y++ {
+		line := screen.Line(top + y)
+		for x := 0; x < w; x++ {
+			line.Set32(x, (line.Get32(x)>>2)&0x3F3F3F)
+		}
+	}
+
+	for i, s := range text {
+		for j, c := range strings.ToUpper(s) {
+			g, ok := hudFont[c]
+			if !ok {
+				continue
+			}
+			x0, y0 := 2+j*cw, 2+i*ch
+			if y0+5 > hh {
+				break
+			}
+			for y := 0; y < 5; y++ {
+				line := screen.Line(top + y0 + y)
+				for x := 0; x < 3; x++ {
+					if g[y]&(4>>uint(x)) != 0 && x0+x < w {
+						line.Set32(x0+x, 0xFFFFFF)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Tiny 3x5 font, one row per byte (bit 2 is the leftmost pixel)
+var hudFont = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 3, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 2, 2},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7},
+	'A': {2, 5, 7, 5, 5}, 'B': {6, 5, 6, 5, 6}, 'C': {3, 4, 4, 4, 3}, 'D': {6, 5, 5, 5, 6},
+	'E': {7, 4, 6, 4, 7}, 'F': {7, 4, 6, 4, 4}, 'G': {3, 4, 5, 5, 3}, 'H': {5, 5, 7, 5, 5},
+	'I': {7, 2, 2, 2, 7}, 'J': {1, 1, 1, 5, 2}, 'K': {5, 5, 6, 5, 5}, 'L': {4, 4, 4, 4, 7},
+	'M': {5, 7, 7, 5, 5}, 'N': {6, 5, 5, 5, 5}, 'O': {2, 5, 5, 5, 2}, 'P': {6, 5, 6, 4, 4},
+	'Q': {2, 5, 5, 6, 3}, 'R': {6, 5, 6, 5, 5}, 'S': {3, 4, 2, 1, 6}, 'T': {7, 2, 2, 2, 2},
+	'U': {5, 5, 5, 5, 7}, 'V': {5, 5, 5, 5, 2}, 'W': {5, 5, 7, 7, 5}, 'X': {5, 5, 2, 5, 5},
+	'Y': {5, 5, 2, 2, 2}, 'Z': {7, 1, 2, 4, 7},
+	'.': {0, 0, 0, 0, 2}, '%': {5, 1, 2, 4, 5}, ':': {0, 2, 0, 2, 0}, '/': {1, 1, 2, 4, 4},
+	'-': {0, 0, 7, 0, 0},
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"ndsemu/e2d"
+	"ndsemu/emu/hw"
+	log "ndsemu/emu/logger"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Buttons is a bitmask of the DS buttons pressed in a frame. The lower 10
+// bits match the layout of KEYIN, while the upper bits match the layout of
+// the buttons in EXTKEYIN (X, Y and the debug button of development units).
+type Buttons uint16
+
+const (
+	ButtonA Buttons = 1 << iota
+	ButtonB
+	ButtonSelect
+	ButtonStart
+	ButtonRight
+	ButtonLeft
+	ButtonUp
+	ButtonDown
+	ButtonR
+	ButtonL
+	ButtonX
+	ButtonY
+	_
+	ButtonDebug
+)
+
+var buttonNames = map[string]Buttons{
+	"a": ButtonA, "b": ButtonB, "x": ButtonX, "y": ButtonY,
+	"l": ButtonL, "r": ButtonR, "start": ButtonStart, "select": ButtonSelect,
+	"up": ButtonUp, "down": ButtonDown, "left": ButtonLeft, "right": ButtonRight,
+	"debug": ButtonDebug,
+}
+
+// Keyboard mapping of the DS buttons
+var keyboardButtons = []struct {
+	scancode int
+	button   Buttons
+}{
+	{hw.SCANCODE_Z, ButtonA},
+	{hw.SCANCODE_X, ButtonB},
+	{hw.SCANCODE_RSHIFT, ButtonSelect},
+	{hw.SCANCODE_RETURN, ButtonStart},
+	{hw.SCANCODE_RIGHT, ButtonRight},
+	{hw.SCANCODE_LEFT, ButtonLeft},
+	{hw.SCANCODE_UP, ButtonUp},
+	{hw.SCANCODE_DOWN, ButtonDown},
+	{hw.SCANCODE_A, ButtonR},
+	{hw.SCANCODE_S, ButtonL},
+	{hw.SCANCODE_D, ButtonX},
+	{hw.SCANCODE_C, ButtonY},
+	{hw.SCANCODE_Q, ButtonDebug},
+}
+
+// Hotkeys for macros: Fn plays macro n, CTRL+Fn starts/stops recording it.
+var macroKeys = []int{hw.SCANCODE_F1, hw.SCANCODE_F2, hw.SCANCODE_F3, hw.SCANCODE_F4}
+
+const cMacroKeyRecord = hw.SCANCODE_LCTRL
+
+// Hotkey that enables/disables turbo buttons
+const cTurboKeyToggle = hw.SCANCODE_T
+
+// Hotkey that closes/opens the lid (hinge)
+const cLidKeyToggle = hw.SCANCODE_F8
+
+// Hotkeys toggling the layers of the 2D engines (see e2d.Layer*); SHIFT
+// selects engine B
+var layerHotkeys = [e2d.NumLayers]int{
+	hw.SCANCODE_INSERT, hw.SCANCODE_HOME, hw.SCANCODE_PAGEUP,
+	hw.SCANCODE_DELETE, hw.SCANCODE_END, hw.SCANCODE_PAGEDOWN,
+}
+
+func keyboardButtonState() Buttons {
+	var btn Buttons
+	for _, kb := range keyboardButtons {
+		if KeyState[kb.scancode] != 0 {
+			btn |= kb.button
+		}
+	}
+	return btn
+}
+
+// Turbo describes a button that auto-fires while being held
+type Turbo struct {
+	Button Buttons
+	Rate   int // presses per second
+}
+
+// ParseTurbo parses a comma-separated list of turbo buttons in the form
+// "button[=rate]" (eg: "a=15,b"), where rate is the number of presses per
+// second (default: 10).
+func ParseTurbo(spec string) ([]Turbo, error) {
+	var turbos []Turbo
+	for _, s := range strings.Split(spec, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		parts := strings.SplitN(s, "=", 2)
+		btn, ok := buttonNames[strings.ToLower(parts[0])]
+		if !ok {
+			return nil, fmt.Errorf("invalid turbo button: %q", parts[0])
+		}
+		t := Turbo{Button: btn, Rate: 10}
+		if len(parts) > 1 {
+			rate, err := strconv.Atoi(parts[1])
+			if err != nil || rate <= 0 || rate > 30 {
+				return nil, fmt.Errorf("invalid turbo rate for %s: %q (use 1-30)", parts[0], parts[1])
+			}
+			t.Rate = rate
+		}
+		turbos = append(turbos, t)
+	}
+	return turbos, nil
+}
+
+// Macro is a recorded sequence of button states, one per frame
+type Macro []Buttons
+
+// InputProcessor computes the final state of the DS buttons for each frame,
+// applying turbo buttons and macros on top of the keyboard state. This
+// happens before the keypad registers are synthesized, so the emulated
+// game can't tell the difference with manual input.
+type InputProcessor struct {
+	Turbo        []Turbo
+	TurboEnabled bool
+
+	macros    []Macro
+	macroFile string
+	recording int // index of macro being recorded, or -1
+	playing   int // index of macro being played, or -1
+	playPos   int
+
+	frame    int
+	lastKeys [256]uint8
+}
+
+func NewInputProcessor() *InputProcessor {
+	return &InputProcessor{
+		TurboEnabled: true,
+		macros:       make([]Macro, len(macroKeys)),
+		recording:    -1,
+		playing:      -1,
+	}
+}
+
+// LoadMacros loads macros from a JSON file. The same file will be updated
+// every time a macro is recorded. It's not an error if it doesn't exist.
+func (ip *InputProcessor) LoadMacros(fn string) error {
+	ip.macroFile = fn
+	data, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var macros []Macro
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return fmt.Errorf("%s: %v", fn, err)
+	}
+	copy(ip.macros, macros)
+	return nil
+}
+
+func (ip *InputProcessor) saveMacros() {
+	if ip.macroFile == "" {
+		return
+	}
+	data, err := json.Marshal(ip.macros)
+	if err == nil {
+		err = ioutil.WriteFile(ip.macroFile, data, 0666)
+	}
+	if err != nil {
+		log.ModInput.ErrorZ("cannot save macros").Error("err", err).End()
+	}
+}
+
+func (ip *InputProcessor) pressed(scancode int) bool {
+	return KeyState[scancode] != 0 && ip.lastKeys[scancode] == 0
+}
+
+func (ip *InputProcessor) hotkeys() {
+	if ip.pressed(cTurboKeyToggle) {
+		ip.TurboEnabled = !ip.TurboEnabled
+		log.ModInput.InfoZ("turbo toggled").Bool("enabled", ip.TurboEnabled).End()
+	}
+
+	for i, key := range macroKeys {
+		if !ip.pressed(key) {
+			continue
+		}
+		if KeyState[cMacroKeyRecord] != 0 {
+			if ip.recording == i {
+				ip.recording = -1
+				ip.saveMacros()
+				log.ModInput.InfoZ("macro recorded").Int("slot", i+1).Int("frames", len(ip.macros[i])).End()
+			} else {
+				ip.recording = i
+				ip.macros[i] = nil
+				log.ModInput.InfoZ("recording macro").Int("slot", i+1).End()
+			}
+		} else if ip.recording < 0 && len(ip.macros[i]) > 0 {
+			ip.playing, ip.playPos = i, 0
+		}
+	}
+}
+
+// Update computes the state of the buttons for the next frame, given the
+// buttons pressed by the user.
+func (ip *InputProcessor) Update(btn Buttons) Buttons {
+	ip.hotkeys()
+	copy(ip.lastKeys[:], KeyState)
+	ip.frame++
+
+	if ip.TurboEnabled {
+		for _, t := range ip.Turbo {
+			// Each press lasts half of the period
+			period := 60 / t.Rate
+			if btn&t.Button != 0 && (ip.frame%period) >= (period+1)/2 {
+				btn &^= t.Button
+			}
+		}
+	}
+
+	// Macros are recorded after turbo, so that they replay exactly what
+	// the game saw.
+	if ip.recording >= 0 {
+		ip.macros[ip.recording] = append(ip.macros[ip.recording], btn)
+	}
+
+	if ip.playing >= 0 {
+		m := ip.macros[ip.playing]
+		btn |= m[ip.playPos]
+		if ip.playPos++; ip.playPos == len(m) {
+			ip.playing = -1
+		}
+	}
+	return btn
+}
@@ -41,24 +41,34 @@ func (ipc *ipcFifo) Pop() uint32 {
 type HwIpc struct {
 	HwIrq [2]*HwIrq
 
-	Ipc9Sync     hwio.Reg16 `hwio:"bank=0,offset=0x0,rwmask=0xFF00,wcb"`
-	Ipc7Sync     hwio.Reg16 `hwio:"bank=2,offset=0x0,rwmask=0xFF00,wcb"`
-	Ipc9FifoCnt  hwio.Reg16 `hwio:"bank=0,offset=0x4,rcb,wcb"`
-	Ipc7FifoCnt  hwio.Reg16 `hwio:"bank=2,offset=0x4,rcb,wcb"`
-	Ipc9FifoSend hwio.Reg32 `hwio:"bank=0,offset=0x8,writeonly,wcb"`
-	Ipc7FifoSend hwio.Reg32 `hwio:"bank=2,offset=0x8,writeonly,wcb"`
-	Ipc9FifoRecv hwio.Reg32 `hwio:"bank=1,offset=0x0,readonly,rcb"`
-	Ipc7FifoRecv hwio.Reg32 `hwio:"bank=3,offset=0x0,readonly,rcb"`
+	_            hwio.BankSize `hwio:"bank=0,size=0xC"`
+	_            hwio.BankSize `hwio:"bank=1,size=0x4"`
+	_            hwio.BankSize `hwio:"bank=2,size=0xC"`
+	_            hwio.BankSize `hwio:"bank=3,size=0x4"`
+	Ipc9Sync     hwio.Reg16    `hwio:"bank=0,offset=0x0,rwmask=0xFF00,wcb"`
+	Ipc7Sync     hwio.Reg16    `hwio:"bank=2,offset=0x0,rwmask=0xFF00,wcb"`
+	Ipc9FifoCnt  hwio.Reg16    `hwio:"bank=0,offset=0x4,rcb,wcb"`
+	Ipc7FifoCnt  hwio.Reg16    `hwio:"bank=2,offset=0x4,rcb,wcb"`
+	Ipc9FifoSend hwio.Reg32    `hwio:"bank=0,offset=0x8,writeonly,wcb"`
+	Ipc7FifoSend hwio.Reg32    `hwio:"bank=2,offset=0x8,writeonly,wcb"`
+	Ipc9FifoRecv hwio.Reg32    `hwio:"bank=1,offset=0x0,readonly,rcb,readsensitive"`
+	Ipc7FifoRecv hwio.Reg32    `hwio:"bank=3,offset=0x0,readonly,rcb,readsensitive"`
 
 	data         [2]ipcFifo
 	enable       [2]bool
 	err          [2]bool
 	irqEmptyFlag [2]bool
 	irqDataFlag  [2]bool
+
+	// High-level emulation of the ARM7 side (nil if disabled)
+	Hle7 *Arm7Hle
+
+	sched Scheduler
 }
 
-func NewHwIpc(irq9 *HwIrq, irq7 *HwIrq) *HwIpc {
+func NewHwIpc(irq9 *HwIrq, irq7 *HwIrq, sched Scheduler) *HwIpc {
 	ipc := new(HwIpc)
+	ipc.sched = sched
 	ipc.HwIrq[CpuNds9] = irq9
 	ipc.HwIrq[CpuNds7] = irq7
 
@@ -94,7 +104,7 @@ func (ipc *HwIpc) updateIrqFlags() {
 
 func (ipc *HwIpc) WriteIPC7SYNC(_, value uint16) {
 	// See WriteIPC9SYNC comment for why this is required
-	Emu.Sync.ScheduleSync(Emu.Sync.Cycles())
+	ipc.sched.ScheduleSync(ipc.sched.Cycles())
 
 	ipc.Ipc9Sync.Value &^= 0xF
 	ipc.Ipc9Sync.Value |= (value >> 8) & 0xF
@@ -111,13 +121,16 @@ func (ipc *HwIpc) WriteIPC9SYNC(_, value uint16) {
 	// making it jump elsewhere; immediatley after, the ARM7 memcpy's
 	// over the ARM9 tight loop, assuming that it has already jumped away.
 	// This breaks emulation if we don't sync between the CPUs quick enough.
-	Emu.Sync.ScheduleSync(Emu.Sync.Cycles())
+	ipc.sched.ScheduleSync(ipc.sched.Cycles())
 
 	ipc.Ipc7Sync.Value &^= 0xF
 	ipc.Ipc7Sync.Value |= (value >> 8) & 0xF
 	if value&(1<<13) != 0 && ipc.Ipc7Sync.Value&(1<<14) != 0 {
 		ipc.HwIrq[CpuNds7].Raise(IrqIpcSync)
 	}
+	if ipc.Hle7 != nil {
+		ipc.Hle7.IpcSync(uint8(value >> 8))
+	}
 }
 
 func (ipc *HwIpc) ReadIPC9FIFOCNT(_ uint16) uint16 { return ipc.readIPCFIFOCNT(CpuNds9) }
@@ -183,6 +196,13 @@ func (ipc *HwIpc) writeIPCFIFOSEND(cpunum CpuNum, val uint32) {
 		send.Push(val)
 	}
 	modIpc.InfoZ("FIFO push").Hex32("val", val).End()
+	if cpunum == CpuNds9 && ipc.Hle7 != nil {
+		// The HLE consumes messages immediately
+		send := &ipc.data[CpuNds9]
+		for !send.Empty() {
+			ipc.Hle7.IpcRecv(send.Pop())
+		}
+	}
 	ipc.updateIrqFlags()
 }
 
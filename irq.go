@@ -1,23 +1,33 @@
 package main
 
 import (
+	"fmt"
 	"ndsemu/arm"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
+	"ndsemu/emu/trace"
+	"strconv"
+	"strings"
 )
 
 type HwIrq struct {
 	Name string
 	Cpu  *arm.Cpu
 
-	Ime hwio.Reg32 `hwio:"offset=0x08,rwmask=0x1,wcb"`
-	Ie  hwio.Reg32 `hwio:"offset=0x10,wcb"`
-	If  hwio.Reg32 `hwio:"offset=0x14,wcb"`
+	_   hwio.BankSize `hwio:"size=0x18"`
+	Ime hwio.Reg32    `hwio:"offset=0x08,rwmask=0x1,wcb"`
+	Ie  hwio.Reg32    `hwio:"offset=0x10,wcb"`
+	If  hwio.Reg32    `hwio:"offset=0x14,wcb"`
 
 	// Mask of level-triggerd IRQs (can't be asserted by CPU)
 	lvlirq uint32
 }
 
+// Synchronization delay of the interrupt controllers, in bus cycles: the
+// time between an IRQ being raised (and IME/IE/IF allowing it) and the CPU
+// taking it. It's only emulated with QuirkIrqTiming.
+const cIrqLatency = 3
+
 type IrqType uint32
 
 const (
@@ -35,6 +45,8 @@ const (
 	IrqDma2 IrqType = (1 << 10)
 	IrqDma3 IrqType = (1 << 11)
 
+	IrqSlot2 IrqType = (1 << 13) // GBA slot (cartridge removal)
+
 	IrqIpcSync     IrqType = (1 << 16)
 	IrqIpcSendFifo IrqType = (1 << 17)
 	IrqIpcRecvFifo IrqType = (1 << 18)
@@ -44,9 +56,67 @@ const (
 
 	IrqGxFifo IrqType = (1 << 21)
 
+	IrqLidOpen IrqType = (1 << 22) // nds7 only
+
+	IrqWifi IrqType = (1 << 24) // nds7 only
+
 	IrqTimers IrqType = (IrqTimer0 | IrqTimer1 | IrqTimer2 | IrqTimer3)
 )
 
+var irqNames = map[string]IrqType{
+	"vblank":      IrqVBlank,
+	"hblank":      IrqHBlank,
+	"vmatch":      IrqVMatch,
+	"timer0":      IrqTimer0,
+	"timer1":      IrqTimer1,
+	"timer2":      IrqTimer2,
+	"timer3":      IrqTimer3,
+	"rtc":         IrqRtc,
+	"dma0":        IrqDma0,
+	"dma1":        IrqDma1,
+	"dma2":        IrqDma2,
+	"dma3":        IrqDma3,
+	"slot2":       IrqSlot2,
+	"ipcsync":     IrqIpcSync,
+	"ipcsendfifo": IrqIpcSendFifo,
+	"ipcrecvfifo": IrqIpcRecvFifo,
+	"gamecard":    IrqGameCardData,
+	"cardeject":   IrqGameCardEject,
+	"gxfifo":      IrqGxFifo,
+	"lid":         IrqLidOpen,
+	"wifi":        IrqWifi,
+	"timers":      IrqTimers,
+}
+
+// ParseIrqType parses the name of an IRQ source (eg: "vblank"), or a
+// numeric mask of IRQ sources.
+func ParseIrqType(s string) (IrqType, error) {
+	if irq, ok := irqNames[strings.ToLower(s)]; ok {
+		return irq, nil
+	}
+	mask, err := strconv.ParseUint(s, 0, 32)
+	if err != nil || mask == 0 {
+		return 0, fmt.Errorf("invalid IRQ source: %q", s)
+	}
+	return IrqType(mask), nil
+}
+
+// String returns the name of the IRQ source (or the mask, in hex, if it's a
+// combination of sources)
+func (t IrqType) String() string {
+	for name, irq := range irqNames {
+		if irq == t && t != IrqTimers {
+			return name
+		}
+	}
+	return fmt.Sprintf("%08x", uint32(t))
+}
+
+// Pending returns the mask of IRQs which are both enabled and requested
+func (irq *HwIrq) Pending() uint32 {
+	return irq.Ie.Value & irq.If.Value
+}
+
 func NewHwIrq(name string, cpu *arm.Cpu) *HwIrq {
 	irq := &HwIrq{Name: name, Cpu: cpu}
 	hwio.MustInitRegs(irq)
@@ -96,6 +166,9 @@ func (irq *HwIrq) WriteIF(old, ifx uint32) {
 func (irq *HwIrq) Raise(irqtype IrqType) {
 	irq.If.Value |= uint32(irqtype)
 	// irq.Log().Info("raise", irq.If)
+	if trace.Enabled() {
+		trace.Instant(irq.Name, irqtype.String(), nil)
+	}
 	irq.updateLineStatus()
 }
 
@@ -104,6 +177,9 @@ func (irq *HwIrq) Raise(irqtype IrqType) {
 // only by a subsequent call to Assert().
 func (irq *HwIrq) Assert(irqtype IrqType, set bool) {
 	if set {
+		if trace.Enabled() && irq.lvlirq&uint32(irqtype) == 0 {
+			trace.Instant(irq.Name, irqtype.String(), nil)
+		}
 		irq.lvlirq |= uint32(irqtype)
 		irq.If.Value |= uint32(irqtype)
 	} else {
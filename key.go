@@ -1,7 +1,6 @@
 package main
 
 import (
-	"ndsemu/emu/hw"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
 )
@@ -11,7 +10,9 @@ type HwKey struct {
 	KeyCnt   hwio.Reg16 `hwio:"bank=0,offset=0x2,wcb"`
 	ExtKeyIn hwio.Reg16 `hwio:"bank=1,offset=0x6,reset=0x7F,readonly,rcb"`
 
-	penDown bool
+	buttons   Buttons
+	penDown   bool
+	lidClosed bool
 }
 
 func NewHwKey() *HwKey {
@@ -20,10 +21,25 @@ func NewHwKey() *HwKey {
 	return key
 }
 
+// SetButtons sets the state of the buttons, as seen by the keypad registers
+func (key *HwKey) SetButtons(btn Buttons) {
+	key.buttons = btn
+}
+
 func (key *HwKey) SetPenDown(value bool) {
 	key.penDown = value
 }
 
+// SetLidClosed sets the state of the hinge, as seen by EXTKEYIN
+func (key *HwKey) SetLidClosed(value bool) {
+	key.lidClosed = value
+}
+
+// LidClosed returns true if the lid is closed
+func (key *HwKey) LidClosed() bool {
+	return key.lidClosed
+}
+
 func (key *HwKey) WriteKEYCNT(_, val uint16) {
 	if val&(1<<14) != 0 {
 		log.ModInput.FatalZ("key interrupt not implemented").End()
@@ -31,51 +47,22 @@ func (key *HwKey) WriteKEYCNT(_, val uint16) {
 }
 
 func (key *HwKey) ReadKEYIN(val uint16) uint16 {
-	if KeyState[hw.SCANCODE_Z] != 0 {
-		val &^= 1 << 0
-	}
-	if KeyState[hw.SCANCODE_X] != 0 {
-		val &^= 1 << 1
-	}
-	if KeyState[hw.SCANCODE_RSHIFT] != 0 {
-		val &^= 1 << 2
-	}
-	if KeyState[hw.SCANCODE_RETURN] != 0 {
-		val &^= 1 << 3
-	}
-	if KeyState[hw.SCANCODE_RIGHT] != 0 {
-		val &^= 1 << 4
-	}
-	if KeyState[hw.SCANCODE_LEFT] != 0 {
-		val &^= 1 << 5
-	}
-	if KeyState[hw.SCANCODE_UP] != 0 {
-		val &^= 1 << 6
-	}
-	if KeyState[hw.SCANCODE_DOWN] != 0 {
-		val &^= 1 << 7
-	}
-	if KeyState[hw.SCANCODE_A] != 0 {
-		val &^= 1 << 8
-	}
-	if KeyState[hw.SCANCODE_S] != 0 {
-		val &^= 1 << 9
-	}
+	val &^= uint16(key.buttons) & 0x3FF
 	log.ModInput.InfoZ("read KEYIN").Hex16("val", val).End()
 	return val
 }
 
+// ReadEXTKEYIN returns the state of the ARM7-only inputs: X, Y and debug
+// buttons (bits 0, 1, 3) and pen down (bit 6) are low when pressed, while
+// the hinge (bit 7) is high when the lid is closed.
 func (key *HwKey) ReadEXTKEYIN(val uint16) uint16 {
-
-	if KeyState[hw.SCANCODE_D] != 0 {
-		val &^= 1 << 0
-	}
-	if KeyState[hw.SCANCODE_C] != 0 {
-		val &^= 1 << 1
-	}
+	val &^= uint16(key.buttons>>10) & 0xB
 	if key.penDown {
 		val &^= 1 << 6
 	}
+	if key.lidClosed {
+		val |= 1 << 7
+	}
 	log.ModInput.InfoZ("read EXTKEYIN").Hex16("val", val).End()
 	return val
 }
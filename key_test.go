@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestExtKeyIn(t *testing.T) {
+	key := NewHwKey()
+	read := func() uint16 { return key.ReadEXTKEYIN(key.ExtKeyIn.Value) }
+
+	if v := read(); v != 0x7F {
+		t.Errorf("idle: got %02x", v)
+	}
+	key.SetButtons(ButtonX | ButtonDebug | ButtonA)
+	if v := read(); v != 0x76 {
+		t.Errorf("X+debug: got %02x", v)
+	}
+	key.SetButtons(ButtonY)
+	key.SetPenDown(true)
+	key.SetLidClosed(true)
+	if v := read(); v != 0xBD {
+		t.Errorf("Y+pen+lid: got %02x", v)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	log "ndsemu/emu/logger"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/howeyc/crc16"
+)
+
+// Language is the language selected in the firmware user settings. Games
+// read it (from the copy of the settings the firmware leaves in RAM) to
+// pick the language of their texts.
+type Language uint8
+
+const (
+	LangJapanese Language = iota
+	LangEnglish
+	LangFrench
+	LangGerman
+	LangItalian
+	LangSpanish
+	LangChinese
+	LangKorean
+)
+
+var languageNames = [...]string{"japanese", "english", "french", "german", "italian", "spanish", "chinese", "korean"}
+
+func (l Language) String() string {
+	if int(l) < len(languageNames) {
+		return languageNames[l]
+	}
+	return "lang" + strconv.Itoa(int(l))
+}
+
+// ParseLanguage parses a language name (eg: "french"), its two-letter code
+// (eg: "fr"), or its index in the firmware settings (0-7)
+func ParseLanguage(s string) (Language, error) {
+	s = strings.ToLower(s)
+	codes := [...]string{"ja", "en", "fr", "de", "it", "es", "zh", "ko"}
+	for i := range languageNames {
+		if s == languageNames[i] || s == codes[i] || s == strconv.Itoa(i) {
+			return Language(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid language: %q (use one of: %s)", s, strings.Join(languageNames[:], ", "))
+}
+
+const (
+	cFwUserSettingsSize = 0x100
+	cFwUserSettingsCrc  = 0x70 // size of the area covered by the CRC
+)
+
+// fwUserSettingsOffsets returns the offsets of the two copies of the user
+// settings in the firmware flash. They are stored in the last two 256-byte
+// blocks before the end of the area declared in the firmware header.
+func fwUserSettingsOffsets(fw io.ReaderAt) ([2]int64, error) {
+	var buf [2]byte
+	if _, err := fw.ReadAt(buf[:], 0x20); err != nil {
+		return [2]int64{}, err
+	}
+	end := int64(binary.LittleEndian.Uint16(buf[:])) * 8
+	if end < 2*cFwUserSettingsSize {
+		return [2]int64{}, errors.New("invalid firmware header")
+	}
+	return [2]int64{end - 2*cFwUserSettingsSize, end - cFwUserSettingsSize}, nil
+}
+
+// ReadFirmwareUserSettings returns the current user settings stored in the
+// firmware flash, that is the valid copy with the highest update counter.
+func ReadFirmwareUserSettings(fw io.ReaderAt) ([]byte, error) {
+	offs, err := fwUserSettingsOffsets(fw)
+	if err != nil {
+		return nil, err
+	}
+
+	var best []byte
+	for _, off := range offs {
+		data := make([]byte, cFwUserSettingsSize)
+		if _, err := fw.ReadAt(data, off); err != nil {
+			return nil, err
+		}
+		if ^crc16.ChecksumIBM(data[:cFwUserSettingsCrc]) != binary.LittleEndian.Uint16(data[0x72:]) {
+			continue
+		}
+		// The counter is 7-bit and wraps around
+		if best == nil || (data[0x70]-best[0x70])&0x7F == 1 {
+			best = data
+		}
+	}
+	if best == nil {
+		return nil, errors.New("no valid user settings in firmware")
+	}
+	return best, nil
+}
+
+// FirmwareLanguage returns the language selected in the user settings of
+// the specified firmware file
+func FirmwareLanguage(fn string) (Language, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	us, err := ReadFirmwareUserSettings(f)
+	if err != nil {
+		return 0, err
+	}
+	return DecodeUserSettings(us).Language, nil
+}
+
+// SetFirmwareLanguage changes the language in the user settings of the
+// specified firmware file, as if it was selected in the firmware menu.
+// Both copies of the settings are updated, so that the firmware keeps using
+// the one it would select anyway.
+func SetFirmwareLanguage(fn string, lang Language) error {
+	return EditFirmwareUserSettings(fn, func(us *UserSettings) error {
+		us.Language = lang
+		return nil
+	})
+}
+
+// SetLanguage switches the language while the console is running: both the
+// user settings seen through the firmware flash and their copy in main RAM
+// are updated, so that games see the new language the next time they read
+// it. Games that read it only at boot need a reset. The firmware file is not
+// modified (see HwFirmwareFlash.SetLanguage).
+func (emu *NDSEmulator) SetLanguage(lang Language) error {
+	if emu.Hw.Ff.f == nil {
+		return errors.New("no firmware loaded")
+	}
+	emu.Hw.Ff.SetLanguage(lang)
+
+	// The copy in RAM doesn't include the extended settings
+	main := lang
+	if lang > LangSpanish {
+		main = LangEnglish
+	}
+	us := emu.Mem.UserSettings()
+	us[0x64] = us[0x64]&^7 | uint8(main)
+	log.ModEmu.WarnZ("language switched").Stringer("lang", lang).End()
+	return nil
+}
+
+// BannerTitle returns the title of a NDS ROM in the specified language, as
+// found in its banner. The lines of the title (usually name, subtitle and
+// publisher) are joined with " - ". If the banner has no title in that
+// language, the English one is returned.
+func BannerTitle(rom io.ReaderAt, lang Language) (string, error) {
+	var buf [4]byte
+	if _, err := rom.ReadAt(buf[:], 0x68); err != nil {
+		return "", err
+	}
+	off := int64(binary.LittleEndian.Uint32(buf[:]))
+	if off == 0 {
+		return "", errors.New("ROM has no banner")
+	}
+	if _, err := rom.ReadAt(buf[:2], off); err != nil {
+		return "", err
+	}
+
+	// Chinese and Korean titles were added in version 2 and 3
+	version := binary.LittleEndian.Uint16(buf[:])
+	if (lang == LangChinese && version < 2) || (lang == LangKorean && version < 3) || lang > LangKorean {
+		lang = LangEnglish
+	}
+
+	raw := make([]byte, 0x100)
+	if _, err := rom.ReadAt(raw, off+0x240+int64(lang)*0x100); err != nil {
+		return "", err
+	}
+	chars := make([]uint16, 0, len(raw)/2)
+	for i := 0; i < len(raw); i += 2 {
+		c := binary.LittleEndian.Uint16(raw[i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+
+	var lines []string
+	for _, l := range strings.Split(string(utf16.Decode(chars)), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, " - "), nil
+}
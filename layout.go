@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"ndsemu/emu/gfx"
+	"ndsemu/emu/hw"
+	"strings"
+)
+
+const (
+	cScreenWidth  = 256
+	cScreenHeight = 192
+
+	// Gap between the two screens on real hardware, in pixels
+	cScreenGapDefault = 90
+)
+
+type LayoutMode int
+
+const (
+	// Screens on top of each other, as on real hardware
+	LayoutVertical LayoutMode = iota
+
+	// One screen enlarged (2x), and the other one at native size beside it
+	LayoutHybrid
+)
+
+func ParseLayoutMode(name string) (LayoutMode, error) {
+	switch name {
+	case "vertical":
+		return LayoutVertical, nil
+	case "hybrid":
+		return LayoutHybrid, nil
+	default:
+		return 0, fmt.Errorf("invalid layout: %q (use vertical or hybrid)", name)
+	}
+}
+
+// LayoutFilters selects the output filters (see hw.ParseFilterSpec) for a
+// layout mode. The specification can list different filters for each layout,
+// as semicolon-separated LAYOUT=FILTERS entries; an entry without layout
+// applies to the layouts that are not listed (eg: "hybrid=2xbr;bilinear").
+// All the entries are validated, not only the selected one.
+func LayoutFilters(spec string, mode LayoutMode) (filters []string, bilinear bool, err error) {
+	var selected, fallback string
+	found := false
+	for _, entry := range strings.Split(spec, ";") {
+		eq := strings.IndexByte(entry, '=')
+		if _, _, err := hw.ParseFilterSpec(entry[eq+1:]); err != nil {
+			return nil, false, err
+		}
+		if eq < 0 {
+			fallback = entry
+			continue
+		}
+		layout, err := ParseLayoutMode(entry[:eq])
+		if err != nil {
+			return nil, false, err
+		}
+		if layout == mode {
+			selected, found = entry[eq+1:], true
+		}
+	}
+	if !found {
+		selected = fallback
+	}
+	return hw.ParseFilterSpec(selected)
+}
+
+// ScreenLayout describes how the two screens are placed within the output
+// framebuffer. Rotation and scaling to window size are handled by the output.
+type ScreenLayout struct {
+	Mode   LayoutMode
+	Gap    int  // gap between screens, in pixels
+	Border int  // border around the screens, in pixels
+	BigTop bool // in hybrid mode, enlarge the top screen rather than the bottom one
+}
+
+// Size returns the size of the framebuffer required by the layout
+func (l ScreenLayout) Size() (int, int) {
+	switch l.Mode {
+	case LayoutHybrid:
+		return cScreenWidth*3 + l.Gap + l.Border*2, cScreenHeight*2 + l.Border*2
+	default:
+		return cScreenWidth + l.Border*2, cScreenHeight*2 + l.Gap + l.Border*2
+	}
+}
+
+// Direct reports whether screens can be drawn directly into the framebuffer,
+// using Line(). Otherwise, screens must be drawn at native resolution into a
+// separate buffer, and then composed into the framebuffer with Compose().
+func (l ScreenLayout) Direct() bool {
+	return l.Mode == LayoutVertical
+}
+
+// Return the position of a screen within the framebuffer, and its scale
+func (l ScreenLayout) rect(bottom bool) (x, y, scale int) {
+	switch l.Mode {
+	case LayoutHybrid:
+		big := bottom != l.BigTop
+		if big {
+			return l.Border, l.Border, 2
+		}
+		// Small screen is placed beside the big one, aligned to the top
+		// or bottom edge depending on whether it's the top or bottom screen.
+		x = l.Border + cScreenWidth*2 + l.Gap
+		if bottom {
+			return x, l.Border + cScreenHeight, 1
+		}
+		return x, l.Border, 1
+	default:
+		if bottom {
+			return l.Border, l.Border + cScreenHeight + l.Gap, 1
+		}
+		return l.Border, l.Border, 1
+	}
+}
+
+// Line returns the framebuffer line where line y of the specified screen
+// must be drawn. It can only be used for direct layouts.
+func (l ScreenLayout) Line(buf gfx.Buffer, bottom bool, y int) gfx.Line {
+	x0, y0, _ := l.rect(bottom)
+	line := buf.Line(y0 + y)
+	line.Add32(x0)
+	return line
+}
+
+// NativeLine returns the line of the native buffer (both screens on top of
+// each other, without gap) where line y of the specified screen must be drawn.
+func NativeLine(native gfx.Buffer, bottom bool, y int) gfx.Line {
+	if bottom {
+		y += cScreenHeight
+	}
+	return native.Line(y)
+}
+
+// NativeScreens returns the two screens within the native buffer
+func NativeScreens(native gfx.Buffer) (top, bottom gfx.Buffer) {
+	return native.SubBuffer(0, 0, cScreenWidth, cScreenHeight),
+		native.SubBuffer(0, cScreenHeight, cScreenWidth, cScreenHeight)
+}
+
+// Screens returns the two screens within the framebuffer. It can only be
+// used for direct layouts.
+func (l ScreenLayout) Screens(buf gfx.Buffer) (top, bottom gfx.Buffer) {
+	x0, y0, _ := l.rect(false)
+	x1, y1, _ := l.rect(true)
+	return buf.SubBuffer(x0, y0, cScreenWidth, cScreenHeight),
+		buf.SubBuffer(x1, y1, cScreenWidth, cScreenHeight)
+}
+
+// Compose draws the screens from the native buffer into the framebuffer
+func (l ScreenLayout) Compose(dst, native gfx.Buffer) {
+	top, bottom := NativeScreens(native)
+	l.ComposeScreens(dst, top, bottom)
+}
+
+// ComposeScreens draws the two screens (at native resolution) into the
+// framebuffer
+func (l ScreenLayout) ComposeScreens(dst, top, bottom gfx.Buffer) {
+	for i, screen := range []gfx.Buffer{top, bottom} {
+		x0, y0, scale := l.rect(i == 1)
+		for y := 0; y < cScreenHeight; y++ {
+			src := screen.Line(y)
+			for sy := 0; sy < scale; sy++ {
+				d := dst.Line(y0 + y*scale + sy)
+				d.Add32(x0)
+				for x := 0; x < cScreenWidth; x++ {
+					px := src.Get32(x)
+					for sx := 0; sx < scale; sx++ {
+						d.Set32(x*scale+sx, px)
+					}
+				}
+			}
+		}
+	}
+
+	// In hybrid mode, the slot beside the small screen is empty; clear it,
+	// as it still holds the small screen drawn before the last swap.
+	if l.Mode == LayoutHybrid {
+		x0 := l.Border + cScreenWidth*2 + l.Gap
+		y0 := l.Border + cScreenHeight
+		if l.BigTop {
+			y0 = l.Border
+		}
+		for y := 0; y < cScreenHeight; y++ {
+			d := dst.Line(y0 + y)
+			d.Add32(x0)
+			for x := 0; x < cScreenWidth; x++ {
+				d.Set32(x, 0)
+			}
+		}
+	}
+}
+
+// TouchPoint converts a point in framebuffer coordinates into touchscreen
+// coordinates. It also reports whether the point lies within the touch
+// screen: clicks in the gap or border must not be sent as pen input.
+func (l ScreenLayout) TouchPoint(x, y int) (int, int, bool) {
+	x0, y0, scale := l.rect(true)
+	x, y = x-x0, y-y0
+	inside := x >= 0 && x < cScreenWidth*scale && y >= 0 && y < cScreenHeight*scale
+	return x / scale, y / scale, inside
+}
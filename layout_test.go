@@ -0,0 +1,67 @@
+package main
+
+import (
+	"ndsemu/emu/gfx"
+	"reflect"
+	"testing"
+)
+
+func TestLayoutHybridSwap(t *testing.T) {
+	l := ScreenLayout{Mode: LayoutHybrid, Gap: 4, Border: 2}
+	dst := gfx.NewBufferMem(l.Size())
+	native := gfx.NewBufferMem(cScreenWidth, cScreenHeight*2)
+	top, bottom := NativeScreens(native)
+	for y := 0; y < cScreenHeight; y++ {
+		for x := 0; x < cScreenWidth; x++ {
+			top.Line(y).Set32(x, 0x111111)
+			bottom.Line(y).Set32(x, 0x222222)
+		}
+	}
+
+	// The small screen moves from the top-right slot to the bottom-right
+	// one: nothing must be left in the slot it was drawn into
+	x := l.Border + cScreenWidth*2 + l.Gap + 10
+	for _, tc := range []struct {
+		bigTop       bool
+		slot0, slot1 uint32 // top-right and bottom-right slots
+	}{
+		{false, 0x111111, 0},
+		{true, 0, 0x222222},
+		{false, 0x111111, 0},
+	} {
+		l.BigTop = tc.bigTop
+		l.Compose(dst, native)
+		s0 := dst.Line(l.Border + 10).Get32(x)
+		s1 := dst.Line(l.Border + cScreenHeight + 10).Get32(x)
+		if s0 != tc.slot0 || s1 != tc.slot1 {
+			t.Errorf("bigtop=%v: slots %06x/%06x, want %06x/%06x", tc.bigTop, s0, s1, tc.slot0, tc.slot1)
+		}
+	}
+}
+
+func TestLayoutFilters(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		mode     LayoutMode
+		filters  []string
+		bilinear bool
+		err      bool
+	}{
+		{"scale2x,bilinear", LayoutHybrid, []string{"scale2x"}, true, false},
+		{"hybrid=2xbr;bilinear", LayoutHybrid, []string{"2xbr"}, false, false},
+		{"hybrid=2xbr;bilinear", LayoutVertical, nil, true, false},
+		{"bilinear;vertical=scale3x", LayoutVertical, []string{"scale3x"}, false, false},
+		{"vertical=nearest", LayoutHybrid, nil, false, false},
+		{"vertical=nearest;hybrid=hq9x", LayoutVertical, nil, false, true},
+		{"diagonal=2xbr", LayoutVertical, nil, false, true},
+	} {
+		filters, bilinear, err := LayoutFilters(tc.spec, tc.mode)
+		if (err != nil) != tc.err {
+			t.Errorf("%q: unexpected error: %v", tc.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(filters, tc.filters) || bilinear != tc.bilinear {
+			t.Errorf("%q (mode %d): got %v/%v, want %v/%v", tc.spec, tc.mode, filters, bilinear, tc.filters, tc.bilinear)
+		}
+	}
+}
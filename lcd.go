@@ -23,22 +23,23 @@ type HwLcdConfig struct {
 }
 
 type HwLcd struct {
-	Irq *HwIrq
-	Cfg *HwLcdConfig
+	Irq   *HwIrq
+	Cfg   *HwLcdConfig
+	sched Scheduler
 
 	DispStat hwio.Reg16 `hwio:"offset=4,rwmask=0xFFF8,rcb"`
 	VCount   hwio.Reg16 `hwio:"offset=6,readonly,rcb"`
 }
 
-func NewHwLcd(irq *HwIrq, cfg *HwLcdConfig) *HwLcd {
-	lcd := &HwLcd{Irq: irq, Cfg: cfg}
+func NewHwLcd(irq *HwIrq, cfg *HwLcdConfig, sched Scheduler) *HwLcd {
+	lcd := &HwLcd{Irq: irq, Cfg: cfg, sched: sched}
 	hwio.MustInitRegs(lcd)
 	lcd.VCount.ReadCb = lcd.ReadVCOUNT // speedup - abused by megamanzero
 	return lcd
 }
 
 func (lcd *HwLcd) ReadDISPSTAT(stat uint16) uint16 {
-	x, y := Emu.Sync.DotPos()
+	x, y := lcd.sched.DotPos()
 
 	// VBlank: not set on line 227
 	if y >= lcd.Cfg.VBlankFirstLine && y <= lcd.Cfg.VBlankLastLine {
@@ -61,7 +62,7 @@ func (lcd *HwLcd) ReadDISPSTAT(stat uint16) uint16 {
 }
 
 func (lcd *HwLcd) ReadVCOUNT(_ uint16) uint16 {
-	_, y := Emu.Sync.DotPos()
+	_, y := lcd.sched.DotPos()
 	return uint16(y) & 0x1FF
 }
 
@@ -139,6 +139,9 @@ type HwMemoryController struct {
 
 	wram [32 * 1024]byte
 
+	// Gamecard registers, as seen by each CPU (see SetGamecard)
+	gcSwitch [2]*hwio.BankSwitch
+
 	// VRAM banks
 	vram [9][]byte
 
@@ -293,6 +296,28 @@ func NewMemoryController(nds9 *NDS9, nds7 *NDS7, vram []byte) *HwMemoryControlle
 	return mc
 }
 
+// SetGamecard configures the mapping of the gamecard registers. They can
+// be accessed by a single CPU at a time, as selected by EXMEMCNT bit 11
+// (by default, NDS9); the other CPU sees them as unmapped. Each CPU has
+// a BankSwitch with two alternatives (indexed by owner CPU) that must be
+// mapped into its bus.
+func (mc *HwMemoryController) SetGamecard(gc *Gamecard) {
+	for cpu := range mc.gcSwitch {
+		sw := hwio.NewBankSwitch(fmt.Sprintf("gamecard%d", 9-cpu*2), 2)
+		sw.MapBank(cpu, 0x40001A0, gc, 0)
+		sw.MapBank(cpu, 0x4100010, gc, 1)
+		mc.gcSwitch[cpu] = sw
+	}
+	gc.Irq = [2]*HwIrq{mc.Nds9.Irq, mc.Nds7.Irq}
+	gc.Owner = mc.GamecardOwner
+	gc.Dma = []DmaTrigger{mc.Nds9, mc.Nds7}
+}
+
+// GamecardOwner returns the CPU that owns the gamecard slot (EXMEMCNT bit 11)
+func (mc *HwMemoryController) GamecardOwner() CpuNum {
+	return CpuNum((mc.ExMemCnt.Value >> 11) & 1)
+}
+
 func (mc *HwMemoryController) WriteWRAMCNT(_, val uint8) {
 	mc.Nds9.Bus.Unmap(0x03000000, 0x03FFFFFF)
 	mc.Nds7.Bus.Unmap(0x03000000, 0x037FFFFF)
@@ -347,19 +372,12 @@ func (mc *HwMemoryController) WriteEXMEMCNT(old, val uint16) {
 
 	// Bit 11 changed: gamecard nds9/nds7 mapping
 	if (old^val)&(1<<11) != 0 {
-		if val&(1<<11) != 0 {
-			nds9.Bus.UnmapBank(0x40001A0, Emu.Hw.Gc, 0)
-			nds9.Bus.UnmapBank(0x4100010, Emu.Hw.Gc, 1)
-			nds7.Bus.MapBank(0x40001A0, Emu.Hw.Gc, 0)
-			nds7.Bus.MapBank(0x4100010, Emu.Hw.Gc, 1)
-			Emu.Hw.Gc.Irq = nds7.Irq
+		owner := CpuNum((val >> 11) & 1)
+		mc.gcSwitch[CpuNds9].Switch(int(owner))
+		mc.gcSwitch[CpuNds7].Switch(int(owner))
+		if owner == CpuNds7 {
 			modMemCnt.InfoZ("mapped gamecard to NDS7").End()
 		} else {
-			nds7.Bus.UnmapBank(0x40001A0, Emu.Hw.Gc, 0)
-			nds7.Bus.UnmapBank(0x4100010, Emu.Hw.Gc, 1)
-			nds9.Bus.MapBank(0x40001A0, Emu.Hw.Gc, 0)
-			nds9.Bus.MapBank(0x4100010, Emu.Hw.Gc, 1)
-			Emu.Hw.Gc.Irq = nds9.Irq
 			modMemCnt.InfoZ("mapped gamecard to NDS9").End()
 		}
 	}
@@ -370,8 +388,9 @@ func (mc *HwMemoryController) WriteEXMEMCNT(old, val uint16) {
 			// GBA slot mapped to NDS7. Since we don't emulate it yet, when
 			// there is no card in the slot, 0xFF is returned
 			nds7.Bus.Unmap(0x8000000, 0xAFFFFFF)
-			nds7.Bus.MapMemorySlice(0x8000000, 0x9FFFFFF, Emu.Hw.Sl2.Rom[:], true)
-			nds7.Bus.MapMemorySlice(0xA000000, 0xAFFFFFF, Emu.Hw.Sl2.Ram[:], false)
+			Emu.Hw.Sl2.MapRom(nds7.Bus, 0x8000000, 0x9FFFFFF)
+			Emu.Hw.Sl2.MapRam(nds7.Bus, 0xA000000, 0xAFFFFFF)
+			Emu.Hw.Sl2.Irq = nds7.Irq
 
 			// NDS9 sees a zero-filled region
 			nds9.Bus.Unmap(0x8000000, 0xAFFFFFF)
@@ -379,8 +398,9 @@ func (mc *HwMemoryController) WriteEXMEMCNT(old, val uint16) {
 		} else {
 			// GBA slot mapped to NDS9. Same as above, reversing roles
 			nds9.Bus.Unmap(0x8000000, 0xAFFFFFF)
-			nds9.Bus.MapMemorySlice(0x8000000, 0x9FFFFFF, Emu.Hw.Sl2.Rom[:], true)
-			nds9.Bus.MapMemorySlice(0xA000000, 0xAFFFFFF, Emu.Hw.Sl2.Ram[:], false)
+			Emu.Hw.Sl2.MapRom(nds9.Bus, 0x8000000, 0x9FFFFFF)
+			Emu.Hw.Sl2.MapRam(nds9.Bus, 0xA000000, 0xAFFFFFF)
+			Emu.Hw.Sl2.Irq = nds9.Irq
 
 			nds7.Bus.Unmap(0x8000000, 0xAFFFFFF)
 			nds7.Bus.MapMemorySlice(0x8000000, 0xAFFFFFF, zero[:], true)
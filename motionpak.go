@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"ndsemu/emu/hw"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+)
+
+var modMotion = log.NewModule("motion")
+
+const (
+	// The accelerometer and gyroscope outputs are sampled by a 12-bit ADC;
+	// 0x800 is the reading for "no acceleration" / "no rotation".
+	cMotionAdcZero = 0x800
+	cMotionAdcMax  = 0xFFF
+
+	// ADC counts per 1g of acceleration, and per degree/second of rotation
+	cMotionCountsPerG   = 372
+	cMotionCountsPerDps = 2.48
+)
+
+// Commands written to the motion pak to select the channel to sample
+const (
+	motionCmdAccelX = 0x04
+	motionCmdAccelY = 0x05
+	motionCmdAccelZ = 0x06
+	motionCmdGyro   = 0x07
+)
+
+// MotionInput is a source of motion data for the motion pak. Accelerations
+// are expressed in g, and the rotation speed (around the Z axis) in degrees
+// per second.
+type MotionInput interface {
+	Motion() (ax, ay, az, gyro float64)
+}
+
+// HwMotionPak emulates the DS Motion Pak, a slot-2 cartridge containing a
+// 3-axis accelerometer and a Z-axis gyroscope. It is accessed through the
+// slot-2 SRAM area: writing a command byte selects a channel and starts a
+// conversion, and the 12-bit result can then be read back as a little-endian
+// halfword (mirrored over the whole area).
+type HwMotionPak struct {
+	Input MotionInput
+
+	data [2]byte
+}
+
+func NewHwMotionPak(input MotionInput) *HwMotionPak {
+	return &HwMotionPak{Input: input}
+}
+
+func motionToAdc(val float64, scale float64) uint16 {
+	v := int(cMotionAdcZero + val*scale)
+	if v < 0 {
+		v = 0
+	}
+	if v > cMotionAdcMax {
+		v = cMotionAdcMax
+	}
+	return uint16(v)
+}
+
+func (mp *HwMotionPak) command(addr uint32, _ int) {
+	cmd := mp.data[addr&1]
+	ax, ay, az, gyro := mp.Input.Motion()
+
+	var val uint16
+	switch cmd {
+	case motionCmdAccelX:
+		val = motionToAdc(ax, cMotionCountsPerG)
+	case motionCmdAccelY:
+		val = motionToAdc(ay, cMotionCountsPerG)
+	case motionCmdAccelZ:
+		val = motionToAdc(az, cMotionCountsPerG)
+	case motionCmdGyro:
+		val = motionToAdc(gyro, cMotionCountsPerDps)
+	default:
+		modMotion.ErrorZ("unknown command").Hex8("cmd", cmd).End()
+		return
+	}
+
+	mp.data[0] = uint8(val)
+	mp.data[1] = uint8(val >> 8)
+	modMotion.DebugZ("sample").Hex8("cmd", cmd).Hex16("val", val).End()
+}
+
+// Mem returns the memory area that must be mapped into slot-2 SRAM region
+// to access the motion pak.
+func (mp *HwMotionPak) Mem(size int) *hwio.Mem {
+	return &hwio.Mem{
+		Name:    "motionpak",
+		Data:    mp.data[:],
+		VSize:   size,
+		Flags:   hwio.MemFlag8 | hwio.MemFlag16ForceAlign,
+		WriteCb: mp.command,
+	}
+}
+
+// keyboardMotion simulates the motion pak axes using the numeric keypad:
+// 4/6 tilt along X, 8/2 tilt along Y, 7/9 rotate.
+type keyboardMotion struct {
+	Tilt float64 // tilt angle in degrees
+	Rot  float64 // rotation speed in degrees/second
+}
+
+func (km keyboardMotion) Motion() (ax, ay, az, gyro float64) {
+	var tx, ty float64
+	if KeyState[hw.SCANCODE_KP_4] != 0 {
+		tx -= km.Tilt
+	}
+	if KeyState[hw.SCANCODE_KP_6] != 0 {
+		tx += km.Tilt
+	}
+	if KeyState[hw.SCANCODE_KP_8] != 0 {
+		ty -= km.Tilt
+	}
+	if KeyState[hw.SCANCODE_KP_2] != 0 {
+		ty += km.Tilt
+	}
+	if KeyState[hw.SCANCODE_KP_7] != 0 {
+		gyro -= km.Rot
+	}
+	if KeyState[hw.SCANCODE_KP_9] != 0 {
+		gyro += km.Rot
+	}
+
+	// Project gravity over the tilted axes
+	tx, ty = tx*math.Pi/180, ty*math.Pi/180
+	ax = math.Sin(tx)
+	ay = math.Sin(ty)
+	az = math.Cos(tx) * math.Cos(ty)
+	return
+}
@@ -4,7 +4,6 @@ import (
 	"ndsemu/arm"
 	"ndsemu/emu/fixed"
 	"ndsemu/emu/hwio"
-	log "ndsemu/emu/logger"
 )
 
 type NDS7 struct {
@@ -18,7 +17,7 @@ type NDS7 struct {
 	miscgba miscRegsGba
 }
 
-func NewNDS7(dojit bool) *NDS7 {
+func NewNDS7(dojit bool, sched Scheduler) *NDS7 {
 	bus := hwio.NewTable("bus7")
 	bus.SetWaitStates(0)
 
@@ -30,7 +29,7 @@ func NewNDS7(dojit bool) *NDS7 {
 	}
 
 	nds7.Irq = NewHwIrq("irq7", cpu)
-	nds7.Timers = NewHWTimers("t7", nds7.Irq)
+	nds7.Timers = NewHWTimers("t7", nds7.Irq, sched)
 	for i := 0; i < 4; i++ {
 		nds7.Dma[i] = NewHwDmaChannel(CpuNds7, i, nds7.Bus, nds7.Irq)
 	}
@@ -56,13 +55,14 @@ func (n *NDS7) InitBus(emu *NDSEmulator) {
 	n.Bus.MapBank(0x4000104, &n.Timers.Timers[1], 0)
 	n.Bus.MapBank(0x4000108, &n.Timers.Timers[2], 0)
 	n.Bus.MapBank(0x400010C, &n.Timers.Timers[3], 0)
+	n.Bus.MapBank(0x4000120, emu.Hw.Uart, 0)
 	n.Bus.MapBank(0x4000130, emu.Hw.Key, 0)
 	n.Bus.MapBank(0x4000130, emu.Hw.Key, 1)
 	n.Bus.MapReg16(0x4000134, &n.misc7.Rcnt)
 	n.Bus.MapReg8(0x4000138, &emu.Hw.Rtc.Serial)
 	n.Bus.MapReg8(0x4000139, &n.misc7.Dummy8)
 	n.Bus.MapBank(0x4000180, emu.Hw.Ipc, 2)
-	// n.Bus.MapBank(0x40001A0, emu.Hw.Gc, 0)  mapped by memcnt
+	n.Bus.MapBankSwitch(emu.Hw.Mc.gcSwitch[CpuNds7]) // gamecard, owned by memcnt
 	n.Bus.MapBank(0x40001C0, emu.Hw.Spi, 0)
 	n.Bus.MapBank(0x4000200, n.Irq, 0)
 	n.Bus.MapReg16(0x4000204, &emu.Hw.Mc.ExMemStat)
@@ -72,8 +72,15 @@ func (n *NDS7) InitBus(emu *NDSEmulator) {
 		n.Bus.MapBank(0x4000400+uint32(i)*0x10, &emu.Hw.Snd.Ch[i], 0)
 	}
 	n.Bus.MapBank(0x4000500, emu.Hw.Snd, 1)
+
+	// Sound engines stream samples into looping voice buffers (and read
+	// back capture buffers) with timer-triggered DMAs: bring the SPU up to
+	// date, so that it doesn't play (or capture) the new data before the
+	// transfer actually happens.
+	for _, dma := range n.Dma {
+		dma.BeforeXfer = emu.Hw.Snd.sync
+	}
 	n.Bus.MapBank(0x4100000, emu.Hw.Ipc, 3)
-	// n.Bus.MapBank(0x4100010, emu.Hw.Gc, 1)  mapped by memcnt
 
 	// Setup all wifi mirrors
 	n.Bus.MapBank(0x4800000, emu.Hw.Wifi, 0)
@@ -87,25 +94,31 @@ func (n *NDS7) InitBus(emu *NDSEmulator) {
 	n.Bus.MapBank(0x480C000, emu.Hw.Wifi, 1)
 	n.Bus.MapBank(0x480E000, emu.Hw.Wifi, 0)
 	n.Bus.MapBank(0x480F000, emu.Hw.Wifi, 0)
+	n.Bus.MapFallback(0x4800000, 0x480FFFF, emu.Hw.Wifi)
 }
 
 func (n *NDS7) InitBusGba(emu *NDSEmulator) {
 	n.Bus.Unmap(0x0, 0xFFFFFFFF)
 
 	n.Bus.MapMemorySlice(0x00000000, 0x00003FFF, emu.Rom.BiosGba, true)
-	n.Bus.MapMemorySlice(0x02000000, 0x0203FFFF, emu.Mem.Ram[:], false)
+	n.Bus.MapMemorySlice(0x02000000, 0x02FFFFFF, emu.Mem.Ram[:256*1024], false)
 	n.Bus.MapMemorySlice(0x03000000, 0x03FFFFFF, emu.Mem.Wram[:32*1024], false)
 	n.Bus.MapMemorySlice(0x05000000, 0x050003FF, emu.Mem.PaletteRam[:], false)
 	n.Bus.MapMemorySlice(0x06000000, 0x06017FFF, emu.Mem.Vram[256*1024:256*1024+128*1024], false)
 	n.Bus.MapMemorySlice(0x07000000, 0x070003FF, emu.Mem.OamRam[:], false)
-	n.Bus.MapMemorySlice(0x08000000, 0x09FFFFFF, Emu.Hw.Sl2.Rom[:], true)
-	n.Bus.MapMemorySlice(0x0A000000, 0x0BFFFFFF, Emu.Hw.Sl2.Rom[:], true)
-	n.Bus.MapMemorySlice(0x0C000000, 0x0DFFFFFF, Emu.Hw.Sl2.Rom[:], true)
+	Emu.Hw.Sl2.MapRom(n.Bus, 0x08000000, 0x09FFFFFF)
+	Emu.Hw.Sl2.MapRom(n.Bus, 0x0A000000, 0x0BFFFFFF)
+	Emu.Hw.Sl2.MapRom(n.Bus, 0x0C000000, 0x0DFFFFFF)
+	Emu.Hw.Sl2.MapRam(n.Bus, 0x0E000000, 0x0FFFFFFF)
+	Emu.Hw.Sl2.Irq = n.Irq
 
 	n.Bus.MapBank(0x4000000, emu.Hw.Lcd7, 0)
 	n.Bus.MapBank(0x4000000, emu.Hw.E2d[1], 0)
 
-	n.Bus.MapReg32(0x4000088, &n.miscgba.SndBias)
+	n.Bus.MapBank(0x4000060, emu.Hw.Apu, 0)
+	for _, dma := range n.Dma {
+		dma.BeforeXfer = nil
+	}
 	n.Bus.MapBank(0x40000B0, n.Dma[0], 0)
 	n.Bus.MapBank(0x40000BC, n.Dma[1], 0)
 	n.Bus.MapBank(0x40000C8, n.Dma[2], 0)
@@ -123,6 +136,34 @@ func (n *NDS7) Frequency() fixed.F8 {
 	return fixed.NewF8(cNds7Clock)
 }
 
+// SetFetchTiming enables the emulation of the wait states of code fetches
+// (see QuirkFetchTiming). Main RAM sits on a 16-bit bus, so ARM opcodes take
+// two accesses; VRAM (mapped as work RAM) is 16-bit with no wait states. BIOS
+// and WRAM are 32-bit with no wait states. Values are in ARM7 cycles.
+func (n *NDS7) SetFetchTiming(enable bool) {
+	n.Cpu.ResetFetchWaitStates()
+	if !enable {
+		return
+	}
+	n.Cpu.SetFetchWaitStates(0x02000000, 0x02FFFFFF,
+		arm.FetchWait{N: 9, S: 3}, arm.FetchWait{N: 7, S: 1})
+	n.Cpu.SetFetchWaitStates(0x06000000, 0x06FFFFFF,
+		arm.FetchWait{N: 1, S: 1}, arm.FetchWait{})
+}
+
+// SetFifoTiming enables the CPU stalls during the DMA transfers that refill a
+// FIFO (see QuirkFifoTiming), estimated at two bus cycles per unit like on
+// the ARM9 (see NDS9.SetFifoTiming).
+func (n *NDS7) SetFifoTiming(enable bool) {
+	var stall func(units uint32)
+	if enable {
+		stall = func(units uint32) { n.Cpu.Clock += int64(units) * 2 }
+	}
+	for _, dma := range n.Dma {
+		dma.Stall = stall
+	}
+}
+
 func (n *NDS7) GetPC() uint32 {
 	return uint32(n.Cpu.GetPC())
 }
@@ -174,13 +215,12 @@ func (m *miscRegs7) WriteHALT7(_, val uint8) {
 		nds7.Cpu.SetLine(arm.LineHalt, true)
 
 	case 3: // sleep
-		log.ModEmu.FatalZ("unimplemented sleep mode").End()
+		Emu.Sleep()
 	}
 }
 
 type miscRegsGba struct {
-	SndBias hwio.Reg32 // FIXME: remove once we emulate gba sound
-	HaltCnt hwio.Reg8  `hwio:"wcb"`
+	HaltCnt hwio.Reg8 `hwio:"wcb"`
 }
 
 func (m *miscRegsGba) WriteHALTCNT(_, _ uint8) {
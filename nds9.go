@@ -20,7 +20,7 @@ type NDS9 struct {
 const cItcmPhysicalSize = 32 * 1024
 const cDtcmPhysicalSize = 16 * 1024
 
-func NewNDS9(dojit bool) *NDS9 {
+func NewNDS9(dojit bool, sched Scheduler) *NDS9 {
 	bus := hwio.NewTable("bus9")
 	bus.SetWaitStates(7)
 
@@ -36,9 +36,10 @@ func NewNDS9(dojit bool) *NDS9 {
 	}
 
 	nds9.Irq = NewHwIrq("irq9", cpu)
-	nds9.Timers = NewHWTimers("t9", nds9.Irq)
+	nds9.Timers = NewHWTimers("t9", nds9.Irq, sched)
 	for i := 0; i < 4; i++ {
 		nds9.Dma[i] = NewHwDmaChannel(CpuNds9, i, nds9.Bus, nds9.Irq)
+		nds9.Dma[i].Cp15 = cp15
 	}
 	nds9.DmaFill = NewHwDmaFill()
 	hwio.MustInitRegs(&nds9.misc)
@@ -50,7 +51,7 @@ func (n *NDS9) InitBus(emu *NDSEmulator) {
 
 	n.Bus.MapMemorySlice(0x02000000, 0x02FFFFFF, emu.Mem.Ram[:], false)
 	n.Bus.MapMemorySlice(0x05000000, 0x05FFFFFF, emu.Mem.PaletteRam[:], false)
-	n.Bus.MapMemorySlice(0x07000000, 0x07FFFFFF, emu.Mem.OamRam[:], false)
+	// OAM is mapped by SetVideoTiming
 	n.Bus.MapMemorySlice(0xFFFF0000, 0xFFFF7FFF, emu.Rom.Bios9, true)
 
 	n.Bus.MapReg8(0x4000300, &n.misc.PostFlg)
@@ -64,7 +65,7 @@ func (n *NDS9) InitBus(emu *NDSEmulator) {
 	n.Bus.MapBank(0x4000108, &n.Timers.Timers[2], 0)
 	n.Bus.MapBank(0x400010C, &n.Timers.Timers[3], 0)
 	n.Bus.MapBank(0x4000130, emu.Hw.Key, 0)
-	// n.Bus.MapBank(0x40001A0, emu.Hw.Gc, 0)  mapped by memcnt
+	n.Bus.MapBankSwitch(emu.Hw.Mc.gcSwitch[CpuNds9]) // gamecard, owned by memcnt
 	n.Bus.MapReg16(0x4000204, &emu.Hw.Mc.ExMemCnt)
 	n.Bus.MapBank(0x4000200, n.Irq, 0)
 	n.Bus.MapBank(0x4000240, emu.Hw.Mc, 0)
@@ -82,13 +83,85 @@ func (n *NDS9) InitBus(emu *NDSEmulator) {
 	n.Bus.MapBank(0x4001000, emu.Hw.E2d[1], 0)
 
 	n.Bus.MapBank(0x4100000, emu.Hw.Ipc, 1)
-	// n.Bus.MapBank(0x4100010, emu.Hw.Gc, 1)  mapped by memcnt
 }
 
 func (n *NDS9) Frequency() fixed.F8 {
 	return fixed.NewF8(cNds9Clock)
 }
 
+// SetFetchTiming enables the emulation of the wait states of code fetches
+// (see QuirkFetchTiming). They only matter for code running from main RAM
+// with the instruction cache disabled (eg: the boot stages before the cache
+// is configured); the ARM9 always fetches 32 bits at a time. Values are in
+// ARM9 cycles, that is twice the bus cycles.
+func (n *NDS9) SetFetchTiming(enable bool) {
+	n.Cpu.ResetFetchWaitStates()
+	if !enable {
+		return
+	}
+	ram := arm.FetchWait{N: 19, S: 7}
+	n.Cpu.SetFetchWaitStates(0x02000000, 0x02FFFFFF, ram, ram)
+}
+
+// SetFifoTiming enables the CPU stalls during the DMA transfers that refill a
+// FIFO (see QuirkFifoTiming). Each unit is assumed to take two bus cycles (a
+// read and a write), which is an estimate: the actual cost depends on the
+// source memory.
+func (n *NDS9) SetFifoTiming(enable bool) {
+	var stall func(units uint32)
+	if enable {
+		stall = func(units uint32) { n.Cpu.Clock += int64(units) * 2 * 2 }
+	}
+	for _, dma := range n.Dma {
+		dma.Stall = stall
+	}
+}
+
+// SetVideoTiming enables the emulation of the OAM access restrictions (see
+// QuirkVideoTiming): OAM writes are discarded while the 2D engines are
+// reading it. When disabled, OAM is mapped without a write filter, so that it
+// can be accessed through the fast path.
+func (n *NDS9) SetVideoTiming(emu *NDSEmulator, enable bool) {
+	oam := &hwio.Mem{
+		Data:  emu.Mem.OamRam[:],
+		Flags: hwio.MemFlag8 | hwio.MemFlag16Unaligned | hwio.MemFlag32Unaligned,
+		VSize: 0x1000000,
+	}
+	if enable {
+		oam.WriteFilter = emu.oamWriteFilter
+	}
+	n.Bus.Unmap(0x07000000, 0x07FFFFFF)
+	n.Bus.MapMem(0x07000000, oam)
+}
+
+// SetMemTiming configures the penalties of the data accesses: vram enables
+// the contention with the video hardware on palette, VRAM and OAM (see
+// QuirkVramTiming), while cache makes the accesses to main RAM as fast as
+// cache hits when the data cache is enabled (see QuirkCacheTiming). The data
+// cache is not emulated, so all accesses are assumed to hit (100% hit rate):
+// misses and line fills are never billed.
+func (n *NDS9) SetMemTiming(emu *NDSEmulator, vram, cache bool) {
+	if !vram && !cache {
+		n.Cpu.SetMemPenalty(nil)
+		return
+	}
+	// A cache hit takes a single ARM9 cycle
+	hit := 1 - int64(n.Bus.WaitStates()+1)
+	n.Cpu.SetMemPenalty(func(addr uint32) int64 {
+		switch addr >> 24 {
+		case 0x02:
+			if cache && n.Cp15.DCacheEnabled() {
+				return hit
+			}
+		case 0x05, 0x06, 0x07:
+			if vram {
+				return emu.videoPenalty(addr)
+			}
+		}
+		return 0
+	})
+}
+
 func (n *NDS9) GetPC() uint32 {
 	return uint32(n.Cpu.GetPC())
 }
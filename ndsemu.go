@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"ndsemu/e2d"
+	"ndsemu/emu/config"
+	"ndsemu/emu/gfx"
 	"ndsemu/emu/hw"
+	"ndsemu/emu/hwio"
+	"ndsemu/emu/jit"
 	log "ndsemu/emu/logger"
+	"ndsemu/emu/trace"
 	"ndsemu/homebrew"
+	"ndsemu/patch"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -33,16 +39,87 @@ const (
  */
 
 const cFirmwareDefault = "bios/firmware.bin"
+const cGameDbDefault = "bios/gamedb.txt"
+
+// Names of the 2D engine layers, as shown when toggled with hotkeys
+var layerNames = [e2d.NumLayers]string{"BG0", "BG1", "BG2", "BG3", "OBJ", "3D"}
 
 var (
 	skipBiosArg  = flag.Bool("s", false, "skip bios and run immediately")
 	flagDebug    = flag.Bool("debug", false, "run with debugger")
+	flagHeatmap  = flag.String("heatmap", "", "with -debug, profile memory accesses per page (h: view in debugger, H: save to the specified CSV file)")
+	flagCoverage = flag.String("coverage", "", "with -debug, track executed/read/written addresses and export them as ranges (C: save to the specified file, suffixed with CPU name)")
+	flagRewind   = flag.Int("rewind", 0, "with -debug, keep the specified seconds of history for reverse execution (p: step back; commands back and rcont); 0 = disable")
+	flagBridge   = flag.String("bridge", "", "listen on the specified TCP address (eg: localhost:7777) for JSON-RPC requests from external tools")
+	flagControl  = flag.String("control", "", "listen on the specified unix socket for remote-control commands from scripts (pause, screenshot, press...)")
+	flagCodecs   = flag.String("save-codecs", "", "directory with the Lua save codecs (GAMECODE.lua), used by the saveexport/saveimport control commands to convert the save from and to JSON")
 	cpuprofile   = flag.String("cpuprofile", "", "write cpu profile to file")
 	flagLogging  = flag.String("log", "", "enable logging for specified modules")
 	flagJit      = flag.Bool("jit", false, "use JIT for emulation (unstable, eats memory)")
+	flagJitCache = flag.Int("jit-cache", 0, "maximum size of the JIT code cache of each CPU, in MiB (0 = unlimited); the least recently used code is evicted when full")
+	flagJitFlush = flag.Bool("jit-cache-flush", false, "flush the whole JIT code cache when full, instead of evicting the least recently used code")
+	flagJitHot   = flag.Int("jit-threshold", jit.DefaultHotThreshold, "number of times a block of code is interpreted before being compiled by the JIT")
 	flagVsync    = flag.Bool("vsync", true, "run at normal speed (60 FPS)")
+	flagPacing   = flag.String("pacing", "audio", "clock used to run at normal speed (audio, vsync)")
+	flagBgMode   = flag.String("background", "run", "behavior when the window loses focus (run, throttle, pause)")
+	flagFilter   = flag.String("filter", "nearest", "comma-separated list of output filters (nearest, bilinear, scale2x, scale3x, 2xbr; eg: 2xbr,bilinear); use LAYOUT=FILTERS entries separated by ';' to select them per layout (eg: hybrid=nearest;2xbr)")
+	flagLayout   = flag.String("layout", "vertical", "screen layout (vertical, hybrid); in hybrid mode, TAB swaps the enlarged screen")
+	flagGap      = flag.Int("screen-gap", cScreenGapDefault, "gap between screens, in pixels")
+	flagBorder   = flag.Int("screen-border", 0, "border around screens, in pixels")
+	flagRotate   = flag.Int("rotate", 0, "rotate the display clockwise by the specified degrees (0, 90, 180, 270)")
 	flagFirmware = flag.String("firmware", cFirmwareDefault, "specify the firwmare file to use")
+	flagLanguage = flag.String("language", "", "set the language in the firmware user settings for this session (japanese, english, french, german, italian, spanish, chinese, korean); by default, the current setting is kept")
+	flagLangSave = flag.Bool("language-save", false, "store the language selected with -language in the firmware save file, instead of applying it only for this session")
+	flagConsole  = flag.String("console", "", "set the console type in the firmware (ds, dslite, dsi, ique, iquelite); by default, the current setting is kept")
+	flagRegion   = flag.String("region", "", "set the languages supported by the console in the firmware user settings (world, china, korea, or a bitmask of languages); by default, the current setting is kept")
+	flagGameDb   = flag.String("game-db", cGameDbDefault, "game database, listing the quirks and the chip ID overrides required by specific games")
+	flagChipID   = flag.String("chip-id", "", "override the chip ID returned by the gamecard, as 4 hex bytes (eg: C2FF0080); by default, it matches the capacity of the ROM, unless the game database overrides it")
+	flagQuirks   = flag.String("quirks", "", "comma-separated list of quirks to enable, in addition to those from the game database and the accuracy preset (card-timing, save-timing, video-timing, fetch-timing, irq-timing, obj-limit, vram-timing, cache-timing, fifo-timing, line-latch)")
+	flagAccuracy = flag.String("accuracy", "speed", "accuracy preset, enabling quirks for all games (speed, balanced, accuracy)")
+	flagPatch    = flag.String("patch", "", "comma-separated list of patches (IPS, UPS, BPS, xdelta) to apply to the NDS ROM in memory; by default, a patch with the same name as the ROM is applied if present (\"none\" disables it)")
 	flagHbrewFat = flag.String("homebrew-fat", "", "FAT image to be mounted for homebrew ROM")
+	flagSolar    = flag.Int("solar", -1, "attach a Boktai solar sensor to slot-2 with the specified light level (0-10)")
+	flagMotion   = flag.Bool("motionpak", false, "attach a DS Motion Pak to slot-2 (controlled with numeric keypad)")
+	flagWifiLink = flag.String("exp-wifi-link", "", "EXPERIMENTAL: link wifi with other local instances (port:peer1,peer2,...)")
+	flagDlHost   = flag.String("exp-dlplay-host", "", "EXPERIMENTAL: serve the download play binary embedded in the ROM to the instances on the wifi link (path in the ROM filesystem, or \"auto\" for the first .srl file)")
+	flagWifiAps  = flag.String("wifi-aps", "", "inject beacons of fake access points listed in the specified file (CHANNEL [BSSID] SSID per line), to test connection setup screens")
+	flagUart     = flag.String("uart", "", "connect the ARM7 serial port (UART mode) to a TCP port (tcp:ADDRESS) or a host device (eg: a pty)")
+	flagTrace    = flag.String("trace", "", "write a timeline of emulation events (frames, scanlines, DMA, IRQs, FIFOs, JIT) to the specified file, in Chrome/Perfetto trace format")
+	flagHud      = flag.Bool("hud", false, "show a performance overlay (F10 toggles it)")
+	flagTexDump  = flag.String("texture-dump", "", "dump all 3D textures as PNG files into the specified directory")
+	flagTexPack  = flag.String("texture-pack", "", "load replacement 3D textures from the specified directory")
+	flagPalDir   = flag.String("palette-dir", "", "directory for palette exports (F9); default: ndsemu/palettes in the user config directory (palette patches are configured per game in the game database)")
+	flagHotPatch = flag.String("hotpatch", "", "load patches of guest code from a TOML file; by default, a file with the same name as the ROM and extension .hotpatch is loaded if present (\"none\" disables it)")
+	flagCheats   = flag.String("cheats", "", "load cheats for the game from a database (usrcheat.dat or cheats.xml)")
+	flagCheatOn  = flag.String("cheat-enable", "", "comma-separated list of cheats to enable (indices or names)")
+	flagCheatLs  = flag.Bool("cheat-list", false, "print the list of cheats loaded with -cheats, and exit")
+	flagTurbo    = flag.String("turbo", "", "comma-separated list of auto-fire buttons, with optional presses per second (eg: a=15,b); T toggles turbo")
+	flagState    = flag.String("state", "", "savestate file used by the hotkeys (F6 saves, F7 loads, CTRL+F7 loads the autosave); default: the ROM file name with extension .state")
+	flagAutoSave = flag.Int("autosave", 0, "save the state every specified minutes (0 = disable) into the savestate file with extension .auto, so that a crash doesn't lose the session")
+	flagMacros   = flag.String("macros", "", "file where input macros are stored (F1-F4 play, CTRL+F1-F4 record)")
+	flagWatchdog = flag.Int("watchdog", 0, "report a possible hang after the specified seconds without progress (0 = disable)")
+	flagWdBreak  = flag.Bool("watchdog-break", false, "on possible hang, break into the debugger (requires -debug)")
+	flagVidTime  = flag.Bool("video-timing", false, "emulate OAM access restrictions while the screen is being drawn (same as -quirks=video-timing)")
+	flagAudInt   = flag.String("audio-interp", "none", "audio sample interpolation (none, linear, cosine, cubic)")
+	flagAudLpf   = flag.Bool("audio-lowpass", false, "filter audio output with a low-pass filter, approximating the DS speakers")
+	flagAudOut   = flag.String("audio-output", "headphone", "audio output model (headphone: stereo, speaker: mono with the frequency response of the DS speakers)")
+	flagAudDump  = flag.String("audio-dump", "", "dump the audio output of the session to the specified WAV file")
+	flagAudChDir = flag.String("audio-dump-channels", "", "dump each sound channel of the session to a separate WAV file in the specified directory")
+	flagGestures = flag.String("gestures", "wheel-up=spin-cw,wheel-down=spin-ccw", "comma-separated list of stylus gestures, as trigger=gesture[:size[:frames]]; triggers: wheel-up, wheel-down or a letter/digit key; gestures: spin-cw, spin-ccw, flick-up, flick-down, flick-left, flick-right (eg: g=flick-up:80:3)")
+	flagTouchWin = flag.String("touch-window", "", "show the touchscreen in a separate borderless window that receives touch input (for touchscreen monitors and drawing tablets): on, WxH or WxH+X+Y")
+	flagTouchRaw = flag.Bool("touch-raw", false, "pass the mouse position to the touchscreen as-is, instead of interpolating drags between frames")
+	flagBusBreak = flag.Bool("bus-error-break", false, "on unmapped or read-only memory accesses, break into the debugger (or abort with a crash report)")
+	flagCompat   = flag.String("compat-report", "", "at the end of the session, write a compatibility report (boot, frames, warnings, FPS) into the specified directory")
+	flagCrashDir = flag.String("crash-dir", ".", "directory where crash reports are written")
+	flagArm7Hle  = flag.String("arm7-hle", "", "EXPERIMENTAL: don't emulate the ARM7, replacing the SDK running on it with HLE (requires -s; only the RTC, touch panel and power management services are implemented, and sound is silent); \"all\" or comma-separated list of game codes")
+	flagBiosHle  = flag.Bool("bios7-hle", false, "replace the sound-related ARM7 BIOS calls (SoundBias, sound tables, MidiKey2Freq, RegisterRamReset) with faster equivalents")
+	flagConfig   = flag.String(config.FileFlag, "", "load options from the specified TOML file (keys are option names; default: ndsemu/ndsemu.toml in the user config directory)")
+	flagRegMap   = flag.Bool("regmap", false, "print the map of the I/O registers of both CPUs (address, name, writable bits, callbacks), and exit")
+	flagFreeze   = flag.String("freeze", "", "comma-separated list of memory locations to freeze after each frame (ADDR[/SIZE]=VALUE, hex)")
+	flagHashes   = flag.String("state-hashes", "", "determinism audit: hash the full machine state after every frame, and write the hashes to the specified file at the end of the session")
+	flagHashRef  = flag.String("state-hashes-check", "", "determinism audit: compare the state hashes with those recorded (with -state-hashes) in the specified file, and report the first divergent frame")
+	flagVideo    = flag.String("video-sink", "window", "where frames are presented: window, null (headless run), png:DIR (one PNG file per frame, for analysis)")
+	flagAudio    = flag.String("audio-sink", "sdl", "where audio is played: sdl, null (headless run), wav:FILE (write the whole session to a WAV file)")
 
 	nds7     *NDS7
 	nds9     *NDS9
@@ -61,11 +138,95 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "regress" {
+		os.Exit(regressMain(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "settings" {
+		os.Exit(settingsMain(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "disasm" {
+		os.Exit(disasmMain(os.Args[2:]))
+	}
+
+	// The main thread runs the functions passed to sdl.Do by the output
+	// goroutines (video presentation and audio queueing)
+	defer recoverCrash()
 	sdl.Main(main1)
 }
 
+// loadConfig parses the command line, and completes it with the configuration
+// file and the environment (NDSEMU_* variables). If the command line begins
+// with "config dump", the final configuration is printed and the program exits.
+func loadConfig() {
+	args := os.Args[1:]
+	dump := len(args) >= 2 && args[0] == "config" && args[1] == "dump"
+	if dump {
+		args = args[2:]
+	}
+
+	cfg := config.New(flag.CommandLine, "NDSEMU_")
+	if dir, err := os.UserConfigDir(); err == nil {
+		cfg.DefaultFile = filepath.Join(dir, "ndsemu", "ndsemu.toml")
+	}
+	oneOf := func(valid ...string) func(string) error {
+		return func(v string) error {
+			for _, s := range valid {
+				if v == s {
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of: %s", strings.Join(valid, ", "))
+		}
+	}
+	cfg.Check("pacing", oneOf("audio", "vsync"))
+	cfg.Check("background", oneOf("run", "throttle", "pause"))
+	cfg.Check("rotate", oneOf("0", "90", "180", "270"))
+	cfg.Check("layout", func(v string) error { _, err := ParseLayoutMode(v); return err })
+	cfg.Check("filter", func(v string) error { _, _, err := LayoutFilters(v, LayoutVertical); return err })
+	cfg.Check("quirks", func(v string) error { _, err := ParseQuirks(v); return err })
+	cfg.Check("accuracy", func(v string) error { _, err := ParseAccuracyPreset(v); return err })
+	cfg.Check("language", func(v string) error {
+		if v == "" {
+			return nil
+		}
+		_, err := ParseLanguage(v)
+		return err
+	})
+	cfg.Check("console", func(v string) error {
+		if v == "" {
+			return nil
+		}
+		_, err := ParseConsoleType(v)
+		return err
+	})
+	cfg.Check("region", func(v string) error {
+		if v == "" {
+			return nil
+		}
+		_, err := ParseRegion(v)
+		return err
+	})
+	cfg.Check("audio-interp", func(v string) error { _, err := ParseSoundInterp(v); return err })
+	cfg.Check("audio-output", func(v string) error { _, err := ParseSoundOutput(v); return err })
+
+	if err := cfg.Load(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if dump {
+		cfg.Dump(os.Stdout)
+		os.Exit(0)
+	}
+
+	// The JIT might have been enabled by the configuration file or the
+	// environment, which init() can't see
+	if *flagJit {
+		debug.SetGCPercent(-1)
+	}
+}
+
 func main1() {
-	flag.Parse()
+	loadConfig()
 
 	// Check whether there is a local firmware copy, otherwise
 	// create one (to handle read/write)
@@ -78,24 +239,69 @@ func main1() {
 		log.ModEmu.FatalZ("cannot open firmware").Error("err", err).End()
 	}
 
-	firstboot := false
-	fwsav := *flagFirmware + ".sav"
-	if _, err := os.Stat(fwsav); err != nil {
-		fw, err := ioutil.ReadFile(*flagFirmware)
-		if err != nil {
-			log.ModEmu.FatalZ("cannot load firwmare:").Error("err", err).End()
+	fwsav, firstboot, err := firmwareCopy(*flagFirmware)
+	if err != nil {
+		log.ModEmu.FatalZ("cannot create firmware copy").Error("err", err).End()
+	}
+
+	// Unless asked to store it, the language is only applied to the flash
+	// mapped in memory (see below), and the save file is left alone
+	var overrideLang bool
+	language, err := FirmwareLanguage(fwsav)
+	if err != nil {
+		log.ModEmu.WarnZ("cannot read firmware language").Error("err", err).End()
+		language = LangEnglish
+	}
+	if *flagLanguage != "" {
+		language, _ = ParseLanguage(*flagLanguage)
+		if *flagLangSave {
+			if err := SetFirmwareLanguage(fwsav, language); err != nil {
+				log.ModEmu.FatalZ("cannot set firmware language").Error("err", err).End()
+			}
+		} else {
+			overrideLang = true
 		}
-		err = ioutil.WriteFile(fwsav, fw, 0777)
+	}
+	log.ModEmu.InfoZ("firmware language").Stringer("lang", language).End()
+
+	if *flagConsole != "" {
+		console, _ := ParseConsoleType(*flagConsole)
+		if err := SetFirmwareConsoleType(fwsav, console); err != nil {
+			log.ModEmu.FatalZ("cannot set console type").Error("err", err).End()
+		}
+	}
+	console, err := FirmwareConsoleType(fwsav)
+	if err != nil {
+		log.ModEmu.WarnZ("cannot read console type").Error("err", err).End()
+		console = ConsoleDS
+	}
+	if *flagRegion != "" {
+		region, _ := ParseRegion(*flagRegion)
+		err := EditFirmwareUserSettings(fwsav, func(us *UserSettings) error {
+			us.Region = region
+			return nil
+		})
 		if err != nil {
-			log.ModEmu.FatalZ("cannot save firwmare:").Error("err", err).End()
+			log.ModEmu.FatalZ("cannot set region").Error("err", err).End()
 		}
-		firstboot = true
 	}
 
 	Emu = NewNDSEmulator(fwsav, *flagJit)
+	defer Emu.Shutdown()
+	Emu.SetConsoleType(console)
+	Emu.SetJitThreshold(*flagJitHot)
+	Emu.SetJitCacheLimit(*flagJitCache*1024*1024, *flagJitFlush)
+	if *flagRegMap {
+		for _, bus := range []*hwio.Table{nds9.Bus, nds7.Bus} {
+			fmt.Printf("%s:\n", bus.Name)
+			hwio.DumpRegMap(os.Stdout, bus.RegMap())
+		}
+		return
+	}
 
 	// Check if the NDS ROM is homebrew. If so, directly load it into slot2
 	// like PassMe does.
+	gbaRom := false
 	if len(flag.Args()) > 0 {
 
 		if hbrew, _ := homebrew.Detect(flag.Arg(0)); hbrew {
@@ -126,11 +332,20 @@ func main1() {
 
 			// Activate IDEAS-compatibile debug output on both CPUs
 			// (use a special SWI to write messages in console)
-			homebrew.ActivateIdeasDebug(nds9.Cpu)
-			homebrew.ActivateIdeasDebug(nds7.Cpu)
+			Emu.ActivateIdeasDebug()
 		} else if strings.HasSuffix(flag.Arg(0), ".nds") {
-			// Map Slot1 cart file (NDS ROM)
-			if err := Emu.Hw.Gc.MapCartFile(flag.Arg(0)); err != nil {
+			// Map Slot1 cart file (NDS ROM), soft-patching it if requested
+			var patches []string
+			switch *flagPatch {
+			case "":
+				if p := patch.Find(flag.Arg(0)); p != "" {
+					patches = append(patches, p)
+				}
+			case "none":
+			default:
+				patches = strings.Split(*flagPatch, ",")
+			}
+			if err := Emu.Hw.Gc.MapCartPatchedFile(flag.Arg(0), patches); err != nil {
 				log.ModEmu.FatalZ(err.Error()).End()
 			}
 
@@ -159,136 +374,221 @@ func main1() {
 			if *flagHbrewFat != "" {
 				log.ModEmu.FatalZ("cannot specify -homebrew-fat for non-homebrew ROM").End()
 			}
+			gbaRom = true
 		} else {
 			log.ModEmu.FatalZ("unrecognized ROM type").String("rom", flag.Arg(0)).End()
 		}
 	}
 
-	if err := Emu.Hw.Ff.MapFirmwareFile(fwsav); err != nil {
-		log.ModEmu.FatalZ(err.Error()).End()
+	if *flagSolar >= 0 {
+		Emu.Hw.Sl2.AttachSolarSensor(*flagSolar)
 	}
-	if firstboot {
-		Emu.Hw.Rtc.ResetDefaults()
+	if *flagMotion {
+		Emu.Hw.Sl2.AttachMotionPak(keyboardMotion{Tilt: 30, Rot: 90})
 	}
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		<-c
-		f, err := os.Create("ram.dump")
-		if err == nil {
-			f.Write(Emu.Mem.Ram[:])
-			f.Close()
-		}
-		f, err = os.Create("wram.dump")
-		if err == nil {
-			f.Write(Emu.Hw.Mc.wram[:])
-			f.Write(Emu.Mem.Wram[:])
-			f.Close()
-		}
-		for i := 0; i < len(Emu.Hw.Mc.vram); i++ {
-			char := 'a' + i
-			f, err = os.Create(fmt.Sprintf("vram-%c.dump", char))
-			if err == nil {
-				f.Write(Emu.Hw.Mc.vram[i][:])
-				f.Close()
-			}
+	if *flagWifiLink != "" {
+		link, err := NewWifiLink(*flagWifiLink)
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
 		}
-		f, err = os.Create("vram-bg-a.dump")
-		if err == nil {
-			v := Emu.Hw.Mc.VramLinearBank(0, e2d.VramLinearBG, 0)
-			v.Dump(f)
-			v = Emu.Hw.Mc.VramLinearBank(0, e2d.VramLinearBG, 256*1024)
-			v.Dump(f)
-			f.Close()
+		Emu.Hw.Wifi.Link = link
+	}
+
+	if *flagDlHost != "" {
+		if *flagWifiLink == "" {
+			log.ModEmu.FatalZ("-exp-dlplay-host requires -exp-wifi-link").End()
 		}
-		f, err = os.Create("vram-bg-b.dump")
-		if err == nil {
-			v := Emu.Hw.Mc.VramLinearBank(1, e2d.VramLinearBG, 0)
-			v.Dump(f)
-			f.Truncate(128 * 1024)
-			f.Close()
+		path := *flagDlHost
+		if path == "auto" {
+			path = ""
 		}
-		f, err = os.Create("vram-bgextpal-a.dump")
-		if err == nil {
-			v := Emu.Hw.Mc.VramLinearBank(0, e2d.VramLinearBGExtPal, 0)
-			v.Dump(f)
-			f.Close()
+		host, err := NewDlPlayHost(Emu.Hw.Gc, path)
+		if err != nil {
+			log.ModEmu.FatalZ("cannot start download play host").Error("err", err).End()
 		}
-		f, err = os.Create("vram-bgextpal-b.dump")
-		if err == nil {
-			v := Emu.Hw.Mc.VramLinearBank(1, e2d.VramLinearBGExtPal, 0)
-			v.Dump(f)
-			f.Close()
+		Emu.Hw.Wifi.DlHost = host
+	}
+
+	if *flagWifiAps != "" {
+		f, err := os.Open(*flagWifiAps)
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
 		}
+		aps, err := ParseFakeAps(f)
+		f.Close()
+		if err != nil {
+			log.ModEmu.FatalZ("invalid fake AP list").String("file", *flagWifiAps).Error("err", err).End()
+		}
+		Emu.Hw.Wifi.FakeAps = aps
+	}
 
-		f, err = os.Create("oam.dump")
-		if err == nil {
-			f.Write(Emu.Mem.OamRam[:])
-			f.Close()
+	if *flagUart != "" {
+		host, err := NewUartHost(*flagUart)
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
 		}
+		Emu.Hw.Uart.Host = host
+	}
 
-		f, err = os.Create("texture.dump")
-		if err == nil {
-			texbank := Emu.Hw.Mc.VramTextureBank()
-			for i := 0; i < 16; i++ {
-				f.Write(texbank.Slots[i])
-			}
-			f.Close()
+	// Enable the accurate behaviors required by the game (if any)
+	dbfn := *flagGameDb
+	if dbfn != "" && !filepath.IsAbs(dbfn) {
+		bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+		dbfn = filepath.Join(bindir, dbfn)
+	}
+	gamedb, err := LoadGameDb(dbfn)
+	if err != nil && (*flagGameDb != cGameDbDefault || !os.IsNotExist(err)) {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	quirks, err := ParseQuirks(*flagQuirks)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	preset, err := ParseAccuracyPreset(*flagAccuracy)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	if *flagVidTime {
+		quirks |= QuirkVideoTiming
+	}
+	Emu.SetGameDb(gamedb, quirks|preset)
+	if *flagChipID != "" {
+		id, err := ParseChipID(*flagChipID)
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
 		}
+		Emu.SetChipID(&id)
+	}
+
+	paldir := *flagPalDir
+	if dir, err := os.UserConfigDir(); err == nil && paldir == "" {
+		paldir = filepath.Join(dir, "ndsemu", "palettes")
+	}
+	Emu.SetPaletteDir(paldir)
 
-		f, err = os.Create("texpal.dump")
-		if err == nil {
-			texbank := Emu.Hw.Mc.VramTexturePaletteBank()
-			for i := 0; i < 8; i++ {
-				f.Write(texbank.Slots[i])
+	if *flagCheats != "" {
+		header := make([]byte, 0x200)
+		if _, err := Emu.Hw.Gc.ReadAt(header, 0); err != nil {
+			log.ModEmu.FatalZ("cannot read ROM header for cheats").Error("err", err).End()
+		}
+		cheats, err := LoadCheatDatabase(*flagCheats, header)
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+		if *flagCheatOn != "" {
+			if err := cheats.Enable(*flagCheatOn); err != nil {
+				log.ModEmu.FatalZ(err.Error()).End()
 			}
-			f.Close()
 		}
-
-		if *cpuprofile != "" {
-			pprof.StopCPUProfile()
+		if *flagCheatLs {
+			fmt.Print(cheats.Dump())
+			return
 		}
-		os.Exit(1)
-	}()
+		Emu.Cheats = cheats
+	}
+	if hpfn := *flagHotPatch; hpfn != "none" && strings.HasSuffix(flag.Arg(0), ".nds") {
+		if hpfn == "" {
+			hpfn = FindHotPatches(flag.Arg(0))
+		}
+		if hpfn != "" {
+			hs, err := LoadHotPatches(hpfn)
+			if err == nil {
+				err = Emu.SetHotPatches(hs)
+			}
+			if err != nil {
+				log.ModEmu.FatalZ(err.Error()).End()
+			}
+		}
+	}
+	if *flagWdBreak && !*flagDebug {
+		log.ModEmu.FatalZ("-watchdog-break requires the debugger (-debug)").End()
+	}
+	Emu.Watchdog.Timeout = *flagWatchdog * 60
+	Emu.Watchdog.Break = *flagWdBreak
+	Emu.SetBreakOnBusError(*flagBusBreak)
+	if *flagBiosHle {
+		Emu.ActivateBios7Hle()
+	}
+	interp, err := ParseSoundInterp(*flagAudInt)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	output, err := ParseSoundOutput(*flagAudOut)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	Emu.SetSoundQuality(SoundQuality{Interp: interp, LowPass: *flagAudLpf, Output: output})
+	Emu.SetTouchRaw(*flagTouchRaw)
 
-	if *skipBiosArg {
-		if err := InjectGamecard(Emu.Hw.Gc, Emu.Mem); err != nil {
-			fmt.Println(err)
-			return
+	if *flagFreeze != "" {
+		if err := Emu.Freeze.Parse(*flagFreeze); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
 		}
+	}
 
-		// Shared wram: map everything to ARM7
-		Emu.Hw.Mc.WramCnt.Write8(0, 3)
+	if *flagAudDump != "" || *flagAudChDir != "" {
+		if err := Emu.StartAudioDump(*flagAudDump, *flagAudChDir); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+	}
 
-		// Set post-boot flag to 1
-		nds9.misc.PostFlg.Value = 1
-		nds7.misc7.PostFlg.Value = 1
+	if *flagTrace != "" {
+		if err := trace.Start(*flagTrace, Emu.Sync.Cycles, cEmuClock); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+		defer trace.Stop()
+	}
 
-		nds9.Irq.Ime.Value = 0x1
-		nds7.Irq.Ime.Value = 0x1
-		nds9.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
-		nds7.Irq.Ie.Value = uint32(IrqIpcRecvFifo | IrqTimers | IrqVBlank)
+	if *flagTexDump != "" {
+		if err := Emu.Hw.E3d.SetTextureDump(*flagTexDump); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+	}
+	if *flagTexPack != "" {
+		if err := Emu.Hw.E3d.LoadTexturePack(*flagTexPack); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+	}
 
-		// VRAM: map everything in "LCDC mode"
-		Emu.Hw.Mc.VramCntA.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntB.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntC.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntD.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntE.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntF.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntG.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntH.Write8(0, 0x80)
-		Emu.Hw.Mc.VramCntI.Write8(0, 0x80)
+	if err := Emu.Hw.Ff.MapFirmwareFile(fwsav); err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	if overrideLang {
+		Emu.Hw.Ff.SetLanguage(language)
+	}
+	if firstboot {
+		Emu.Hw.Rtc.ResetDefaults()
+	}
 
-		// Gamecard: skip directly to key2 status
-		Emu.Hw.Gc.stat = gcStatusKey2
+	// The main loop runs until ctx is cancelled (by SIGINT or by a bridge or
+	// control request) or the window is closed; it always stops between frames, and
+	// the deferred cleanups then flush saves, audio and traces. A second
+	// SIGINT exits immediately, in case the emulation is stuck in a frame.
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	interrupted := make(chan struct{})
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt)
+	go func() {
+		<-sigint
+		close(interrupted)
+		stop()
+		<-sigint
+		os.Exit(1)
+	}()
 
-		nds9.Cp15.ConfigureControlReg(0x52078, 0x00FF085)
+	// ROMs loaded later (eg: dropped onto the window) are booted the same way
+	Emu.SetBootMode(*skipBiosArg, fwsav, *flagArm7Hle)
+	if err := Emu.Boot(gbaRom); err != nil {
+		log.ModEmu.FatalZ("cannot boot ROM").Error("err", err).End()
 	}
 
 	if *flagDebug {
-		Emu.StartDebugger()
+		Emu.StartDebugger(*flagHeatmap, *flagCoverage)
+		if *flagRewind > 0 {
+			Emu.EnableRewind(*flagRewind * 60)
+		}
 	}
 
 	if *cpuprofile != "" {
@@ -314,25 +614,302 @@ func main1() {
 		log.EnableDebugModules(modmask)
 	}
 
-	hwout := hw.NewOutput(hw.OutputConfig{
-		Title:             "NDSEmu - Nintendo DS Emulator",
-		Width:             256,
-		Height:            192 + 90 + 192,
-		FramePerSecond:    60,
-		NumBackBuffers:    3,
-		EnforceSpeed:      *flagVsync,
-		AudioFrequency:    cAudioFreq,
-		AudioChannels:     2,
-		AudioSampleSigned: true,
-	})
-	hwout.EnableVideo(true)
-	hwout.EnableAudio(true)
+	var pacing hw.PacingMode
+	switch *flagPacing {
+	case "audio":
+		pacing = hw.PacingAudio
+	case "vsync":
+		pacing = hw.PacingVsync
+	default:
+		log.ModEmu.FatalZ("invalid pacing mode").String("pacing", *flagPacing).End()
+	}
+
+	var background hw.BackgroundMode
+	switch *flagBgMode {
+	case "run":
+		background = hw.BackgroundRun
+	case "throttle":
+		background = hw.BackgroundThrottle
+	case "pause":
+		background = hw.BackgroundPause
+	default:
+		log.ModEmu.FatalZ("invalid background mode").String("background", *flagBgMode).End()
+	}
+
+	layoutMode, err := ParseLayoutMode(*flagLayout)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	filters, bilinear, err := LayoutFilters(*flagFilter, layoutMode)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	layout := ScreenLayout{Mode: layoutMode, Gap: *flagGap, Border: *flagBorder}
+	Emu.SetLayout(layout)
+	width, height := layout.Size()
+
+	title, banner := "NDSEmu - Nintendo DS Emulator", ""
+	if Emu.Hw.Gc.Size != 0 {
+		if banner, err = BannerTitle(Emu.Hw.Gc, language); err == nil && banner != "" {
+			title = "NDSEmu - " + banner
+		}
+	}
+
+	hud := NewPerfHud()
+	hud.SetEnabled(Emu, *flagHud)
+	osd := new(Osd)
+
+	// SDL is only used if frames or audio are presented there: with the
+	// other sinks, the emulator runs headless, as fast as possible.
+	videoSink := strings.SplitN(*flagVideo, ":", 2)
+	audioSink := strings.SplitN(*flagAudio, ":", 2)
+	window := videoSink[0] == "window" && len(videoSink) == 1
+	sdlAudio := audioSink[0] == "sdl" && len(audioSink) == 1
+
+	var hwout *hw.Output
+	if window || sdlAudio {
+		hwout = hw.NewOutput(hw.OutputConfig{
+			Title:             title,
+			Width:             width,
+			Height:            height,
+			FramePerSecond:    60,
+			NumBackBuffers:    3,
+			EnforceSpeed:      *flagVsync,
+			AudioFrequency:    cAudioFreq,
+			AudioChannels:     2,
+			AudioSampleSigned: true,
+			Pacing:            pacing,
+			Filters:           filters,
+			Bilinear:          bilinear,
+			Rotation:          *flagRotate,
+			Background:        background,
+			Recover:           recoverCrash,
+		})
+		if window {
+			hwout.EnableVideo(true)
+		}
+		if sdlAudio {
+			hwout.EnableAudio(true)
+		}
+		defer hwout.Close()
+	}
+
+	var touchwin *hw.TouchWindow
+	if *flagTouchWin != "" && window {
+		spec := *flagTouchWin
+		if spec == "on" {
+			spec = ""
+		}
+		cfg, err := hw.ParseTouchWindowGeometry(spec)
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+		cfg.Width, cfg.Height = 256, 192
+		if touchwin, err = hwout.NewTouchWindow(cfg); err != nil {
+			log.ModEmu.FatalZ("cannot open touch window").Error("err", err).End()
+		}
+		defer touchwin.Close()
+	}
+
+	var video VideoSink
+	switch {
+	case window:
+		video = &WindowSink{Out: hwout, Emu: Emu, Hud: hud, Osd: osd}
+	case videoSink[0] == "null" && len(videoSink) == 1:
+		video = NullVideoSink{}
+	case videoSink[0] == "png" && len(videoSink) == 2:
+		if video, err = NewPngVideoSink(videoSink[1]); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+	default:
+		log.ModEmu.FatalZ("invalid video sink").String("video-sink", *flagVideo).End()
+	}
+
+	var audio AudioSink
+	switch {
+	case sdlAudio:
+		audio = &SdlAudioSink{Out: hwout}
+	case audioSink[0] == "null" && len(audioSink) == 1:
+		audio = NullAudioSink{}
+	case audioSink[0] == "wav" && len(audioSink) == 2:
+		wav, err := NewWavAudioSink(audioSink[1])
+		if err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+		defer func() {
+			if err := wav.Close(); err != nil {
+				log.ModEmu.ErrorZ("cannot write audio").Error("err", err).End()
+			}
+		}()
+		audio = wav
+	default:
+		log.ModEmu.FatalZ("invalid audio sink").String("audio-sink", *flagAudio).End()
+	}
 
 	var fprof *os.File
 	profiling := 0
+	var solarKeys [2]bool
+	var swapKey bool
+	var resetKey bool
+	var hudKey bool
+	var layerKeys [e2d.NumLayers]bool
+	var palKey bool
+	var lidKey bool
+	var stateKeys [2]bool
+	var stateSave bool // save requested, retried until the hardware isn't busy
+
+	input := NewInputProcessor()
+	if input.Turbo, err = ParseTurbo(*flagTurbo); err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	gestureList, err := ParseGestures(*flagGestures)
+	if err != nil {
+		log.ModEmu.FatalZ(err.Error()).End()
+	}
+	gestures := NewGestureSynth(gestureList)
+	if *flagMacros != "" {
+		if err := input.LoadMacros(*flagMacros); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+	}
+
+	var compat *CompatTracker
+	writeCompat := func(exit string, msg string) {}
+	if *flagCompat != "" && len(flag.Args()) > 0 {
+		compat = NewCompatTracker(Emu, flag.Arg(0), banner)
+		writeCompat = func(exit string, msg string) {
+			if fn, err := compat.Write(*flagCompat, exit, msg); err != nil {
+				log.ModEmu.ErrorZ("cannot write compatibility report").Error("err", err).End()
+			} else {
+				fmt.Fprintf(os.Stderr, "compatibility report written to %s\n", fn)
+			}
+		}
+		log.AddFatalHook(func(msg string) { writeCompat("fatal", msg) })
+	}
 
-	KeyState = hw.GetKeyboardState()
-	for hwout.Poll() {
+	var audit *StateAudit
+	if *flagHashes != "" || *flagHashRef != "" {
+		audit = new(StateAudit)
+		if *flagHashRef != "" {
+			if err := audit.LoadReference(*flagHashRef); err != nil {
+				log.ModEmu.FatalZ(err.Error()).End()
+			}
+		}
+		defer func() {
+			if *flagHashes != "" {
+				if err := audit.Save(*flagHashes); err != nil {
+					log.ModEmu.ErrorZ("cannot write state hashes").Error("err", err).End()
+				}
+			}
+			if *flagHashRef != "" {
+				n := len(audit.Hashes)
+				if n > len(audit.Reference) {
+					n = len(audit.Reference)
+				}
+				if audit.Diverged != 0 {
+					fmt.Fprintf(os.Stderr, "state diverged from reference at frame %d\n", audit.Diverged)
+				} else {
+					fmt.Fprintf(os.Stderr, "state matches reference (%d frames checked)\n", n)
+				}
+			}
+		}()
+	}
+
+	// In case of crash, write a diagnostic bundle before exiting
+	log.EnableHistory(cCrashLogLines)
+	crashHandler = func(r interface{}) {
+		if dir, err := Emu.WriteCrashReport(*flagCrashDir, fmt.Sprint(r), debug.Stack()); err == nil {
+			fmt.Fprintf(os.Stderr, "crash report written to %s\n", dir)
+		}
+		writeCompat("crash", fmt.Sprint(r))
+	}
+	defer recoverCrash()
+
+	var bridge *Bridge
+	if *flagBridge != "" {
+		if bridge, err = NewBridge(*flagBridge); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+		bridge.Stop = stop
+		defer bridge.Close()
+	}
+
+	var control *Control
+	if *flagControl != "" {
+		if control, err = NewControl(*flagControl); err != nil {
+			log.ModEmu.FatalZ(err.Error()).End()
+		}
+		control.Stop = stop
+		control.SaveCodecs = *flagCodecs
+		defer control.Close()
+	}
+
+	// Frames are rendered into our own buffers, and then passed to the sinks
+	screen := gfx.NewBufferMem(width, height)
+	samples := make([]int16, (cAudioFreq/60+1)*2)
+	if control != nil {
+		control.Screenshot = func(fn string) error {
+			return writePng(fn, screenshotImage(screen, width, height))
+		}
+		if window {
+			control.SetFilters = func(spec string) error {
+				filters, bilinear, err := LayoutFilters(spec, layout.Mode)
+				if err != nil {
+					return err
+				}
+				return hwout.SetFilters(filters, bilinear)
+			}
+		}
+	}
+
+	// Savestates (hotkeys and autosave) are stored next to the ROM by default.
+	// Saving might have to be retried for a few frames (see SaveState).
+	stateFn := *flagState
+	if stateFn == "" {
+		stateFn = flag.Arg(0) + ".state"
+	}
+	loadState := func(fn string) error {
+		if err := Emu.LoadStateFile(fn); err != nil {
+			return err
+		}
+		if audit != nil {
+			audit.Restart()
+		}
+		return nil
+	}
+	if control != nil {
+		control.LoadState = loadState
+	}
+	trySaveState := func(fn string, msg string) bool {
+		err := Emu.SaveStateFile(fn)
+		switch {
+		case err == errStateBusy:
+			return false
+		case err != nil:
+			log.ModEmu.ErrorZ("cannot save state").String("file", fn).Error("err", err).End()
+			osd.Show("STATE SAVE FAILED")
+		case msg != "":
+			osd.Show("%s", msg)
+		}
+		return true
+	}
+	autoSave := time.Duration(*flagAutoSave) * time.Minute
+	nextAutoSave := time.Now().Add(autoSave)
+
+	if window {
+		KeyState = hw.GetKeyboardState()
+	}
+	for ctx.Err() == nil && (hwout == nil || hwout.Poll()) {
+		if bridge != nil {
+			bridge.Poll(Emu)
+		}
+		if control != nil {
+			control.Poll(Emu)
+		}
+		if (hwout != nil && hwout.Paused()) || (control != nil && control.Paused()) {
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
 		if KeyState[hw.SCANCODE_P] != 0 {
 			time.Sleep(1 * time.Second)
 		}
@@ -349,18 +926,182 @@ func main1() {
 			log.ModEmu.Warnf("profile dumped")
 		}
 
-		x, y, btn := hwout.GetMouseState()
-		y -= 192 + 90
-		pendown := btn&hw.MouseButtonLeft != 0
-		Emu.Hw.Key.SetPenDown(pendown)
-		Emu.Hw.Tsc.SetPen(pendown, x, y)
+		if sol := Emu.Hw.Sl2.Solar; sol != nil {
+			// Use [ and ] as a slider for the sunlight level
+			dark := KeyState[hw.SCANCODE_LEFTBRACKET] != 0
+			bright := KeyState[hw.SCANCODE_RIGHTBRACKET] != 0
+			if dark && !solarKeys[0] {
+				sol.SetLevel(sol.Level() - 1)
+			}
+			if bright && !solarKeys[1] {
+				sol.SetLevel(sol.Level() + 1)
+			}
+			solarKeys[0], solarKeys[1] = dark, bright
+		}
 
-		v, a := hwout.BeginFrame()
-		exit := Emu.RunOneFrame(v, ([]int16)(a))
-		hwout.EndFrame(v, a)
+		if layout.Mode == LayoutHybrid {
+			// TAB swaps the enlarged screen
+			tab := KeyState[hw.SCANCODE_TAB] != 0
+			if tab && !swapKey {
+				layout.BigTop = !layout.BigTop
+				Emu.SetLayout(layout)
+			}
+			swapKey = tab
+		}
+
+		// A ROM dropped onto the window replaces the current one
+		if window {
+			if fn, ok := hwout.DroppedFile(); ok {
+				if err := Emu.LoadRom(fn); err != nil {
+					log.ModEmu.ErrorZ("cannot load ROM").String("rom", fn).Error("err", err).End()
+				}
+			}
+		}
+
+		// F5 soft-resets the console, CTRL+F5 hard-resets it
+		reset := KeyState[hw.SCANCODE_F5] != 0
+		if reset && !resetKey {
+			Emu.Reset(KeyState[hw.SCANCODE_LCTRL] != 0)
+		}
+		resetKey = reset
+
+		// F10 toggles the performance HUD
+		hudToggle := KeyState[hw.SCANCODE_F10] != 0
+		if hudToggle && !hudKey {
+			hud.SetEnabled(Emu, !hud.Enabled)
+		}
+		hudKey = hudToggle
+
+		// The six keys above the arrows (INS, HOME, PGUP, DEL, END, PGDN)
+		// toggle the BG0-3, OBJ and 3D layers of engine A (engine B with
+		// SHIFT); digits and keypad are taken by the sound channel solo
+		// (see HwSound.step) and the motion pak
+		for i := range layerKeys {
+			pressed := KeyState[layerHotkeys[i]] != 0
+			if pressed && !layerKeys[i] {
+				eng := 0
+				if KeyState[hw.SCANCODE_LSHIFT] != 0 {
+					eng = 1
+				}
+				if eng == 0 || i != e2d.Layer3D {
+					e := Emu.Hw.E2d[eng]
+					e.SetLayerHidden(i, !e.LayerHidden(i))
+					state := "ON"
+					if e.LayerHidden(i) {
+						state = "OFF"
+					}
+					osd.Show("%c %s %s", 'A'+eng, layerNames[i], state)
+				}
+			}
+			layerKeys[i] = pressed
+		}
+
+		// F9 exports the current palettes
+		palExport := KeyState[hw.SCANCODE_F9] != 0
+		if palExport && !palKey {
+			if err := Emu.ExportPalettes(); err != nil {
+				log.ModEmu.ErrorZ("cannot export palettes").Error("err", err).End()
+				osd.Show("PALETTE EXPORT FAILED")
+			} else {
+				osd.Show("PALETTES EXPORTED")
+			}
+		}
+		palKey = palExport
+
+		// F8 closes/opens the lid
+		lid := KeyState[cLidKeyToggle] != 0
+		if lid && !lidKey {
+			Emu.Hw.Key.SetLidClosed(!Emu.Hw.Key.LidClosed())
+			if Emu.Hw.Key.LidClosed() {
+				osd.Show("LID CLOSED")
+			} else {
+				osd.Show("LID OPEN")
+			}
+		}
+		lidKey = lid
+
+		// F6 saves the state, F7 loads it (CTRL+F7 loads the autosave)
+		save := KeyState[hw.SCANCODE_F6] != 0
+		if save && !stateKeys[0] {
+			stateSave = true
+		}
+		stateKeys[0] = save
+		if stateSave && trySaveState(stateFn, "STATE SAVED") {
+			stateSave = false
+		}
+		load := KeyState[hw.SCANCODE_F7] != 0
+		if load && !stateKeys[1] {
+			fn := stateFn
+			if KeyState[hw.SCANCODE_LCTRL] != 0 {
+				fn += ".auto"
+			}
+			if err := loadState(fn); err != nil {
+				log.ModEmu.ErrorZ("cannot load state").String("file", fn).Error("err", err).End()
+				osd.Show("STATE LOAD FAILED")
+			} else {
+				osd.Show("STATE LOADED")
+			}
+		}
+		stateKeys[1] = load
+		if autoSave > 0 && time.Now().After(nextAutoSave) && trySaveState(stateFn+".auto", "") {
+			nextAutoSave = time.Now().Add(autoSave)
+		}
+
+		buttons := keyboardButtonState()
+		if control != nil {
+			buttons |= control.Buttons()
+		}
+		Emu.Hw.Key.SetButtons(input.Update(buttons))
+
+		if window {
+			x, y, btn := hwout.GetMouseState()
+			x, y, inside := layout.TouchPoint(x, y)
+			pendown := inside && btn&hw.MouseButtonLeft != 0
+			pressure := cTouchDefaultPressure
+			if touchwin != nil {
+				if tx, ty, down, p := touchwin.State(); down {
+					x, y, pendown = tx, ty, true
+					if p >= 0 {
+						pressure = float64(p)
+					}
+				}
+			}
+			if gx, gy, ok := gestures.Update(hwout.MouseWheel(), x, y, inside); ok {
+				x, y, pendown = gx, gy, true
+			}
+			Emu.Hw.Key.SetPenDown(pendown)
+			Emu.Hw.Tsc.SetPen(pendown, x, y)
+			Emu.Hw.Tsc.SetPressure(pressure)
+		}
+
+		a := samples[:audioFrameSamples(Emu.framecount)*2]
+		t0 := time.Now()
+		exit := Emu.RunOneFrame(screen, a)
+		hud.EndFrame(Emu, time.Since(t0))
+		video.SubmitFrame(Emu.Screens())
+		if touchwin != nil {
+			_, bottom := Emu.Screens()
+			touchwin.Present(bottom)
+		}
+		audio.SubmitAudio(a)
+		if compat != nil {
+			compat.Frame(Emu)
+		}
+		if audit != nil {
+			audit.Frame(Emu)
+		}
 		if exit {
 			fmt.Println("System was powered off")
-			break
+			writeCompat("poweroff", "")
+			return
 		}
 	}
+
+	select {
+	case <-interrupted:
+		Emu.DumpMemory(".")
+		writeCompat("interrupt", "")
+	default:
+		writeCompat("quit", "")
+	}
 }
@@ -1,16 +1,19 @@
 package main
 
 import (
+	"encoding/binary"
 	"io/ioutil"
 	"ndsemu/emu/gfx"
 	log "ndsemu/emu/logger"
 	"os"
+	"path/filepath"
 
 	"testing"
 )
 
 func BenchmarkCpuSpeed(b *testing.B) {
 	screen := gfx.NewBufferMem(256, 192+90+192)
+	samples := make([]int16, (cAudioFreq/60+1)*2)
 	log.Disable()
 
 	f, err := ioutil.TempFile("", "")
@@ -21,13 +24,55 @@ func BenchmarkCpuSpeed(b *testing.B) {
 	defer os.Remove(f.Name())
 
 	for i := 0; i < b.N; i++ {
-		Emu = NewNDSEmulator(f.Name())
+		Emu = NewNDSEmulator(f.Name(), false)
 		Emu.Hw.Gc.MapCartFile("roms/phoenixwright.nds")
 		Emu.Hw.Ff.MapFirmwareFile("bios/firmware.bin")
 		Emu.Hw.Rtc.ResetDefaults()
 
 		for j := 0; j < 300; j++ {
-			Emu.RunOneFrame(screen)
+			Emu.RunOneFrame(screen, samples[:audioFrameSamples(Emu.framecount)*2])
 		}
 	}
 }
+
+func TestLoadRomBootMode(t *testing.T) {
+	// A ROM with a single word of code for each CPU, right after the header
+	rom := make([]byte, 0x208)
+	copy(rom[0xC:], "TEST")
+	for i, v := range []uint32{
+		0x200, 0x02000000, 0x02000000, 4, // ARM9 offset, entry, RAM, size
+		0x204, 0x02380000, 0x02380000, 4, // ARM7 offset, entry, RAM, size
+	} {
+		binary.LittleEndian.PutUint32(rom[0x20+i*4:], v)
+	}
+	fn := filepath.Join(t.TempDir(), "test.nds")
+	if err := ioutil.WriteFile(fn, rom, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, direct := range []bool{false, true} {
+		emu := newTestEmulator(t)
+		emu.SetBootMode(direct, "", "")
+		if err := emu.LoadRom(fn); err != nil {
+			t.Fatal(err)
+		}
+		defer emu.Hw.Bkp.Close()
+
+		// With direct boot, the ROM is started skipping the BIOS, like
+		// at startup: the post-boot flag is set, and R12 points to the
+		// entry point
+		r9, r7 := uint32(nds9.Cpu.Regs[12]), uint32(nds7.Cpu.Regs[12])
+		booted := nds9.misc.PostFlg.Value == 1 && r9 == 0x02000000 && r7 == 0x02380000
+		if booted != direct {
+			t.Errorf("direct=%v: booted=%v (r12=%08x/%08x)", direct, booted, r9, r7)
+		}
+	}
+
+	// ARM7 HLE can only be enabled with direct boot
+	emu := newTestEmulator(t)
+	emu.SetBootMode(false, "", "TEST")
+	if err := emu.LoadRom(fn); err == nil {
+		t.Errorf("ARM7 HLE enabled without direct boot")
+	}
+	emu.Hw.Bkp.Close()
+}
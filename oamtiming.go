@@ -0,0 +1,67 @@
+package main
+
+// Emulation of the video memory access restrictions during rendering
+// (optional, as it costs a bit of performance and almost no game depends on
+// it; see QuirkVideoTiming and QuirkVramTiming).
+//
+// Each 2D engine reads its own OAM while drawing a line, and CPU writes
+// performed in the meantime are ignored by the hardware. OAM is available
+// to the CPU during VBlank, during forced blank or when OBJs are disabled,
+// and during HBlank if DISPCNT bit 23 ("H-Blank Interval Free") is set.
+//
+// Palette, VRAM and OAM are also shared with the engines while a line is
+// being drawn, and the CPU waits for them to release the memory. The actual
+// delay depends on the layers being rendered; we use a fixed penalty of one
+// bus cycle per access, which is an estimate.
+
+// cVideoPenalty is the extra cost of an ARM9 access to the video memories
+// while they are in use, in ARM9 cycles
+const cVideoPenalty = 2
+
+// oamWriteFilter is installed as write filter of the OAM memory area, and
+// discards writes when the video hardware owns it
+func (emu *NDSEmulator) oamWriteFilter(addr uint32, size int) bool {
+	return !emu.oamBusy(addr)
+}
+
+// videoPenalty returns the extra cycles of the accesses to palette, VRAM and OAM while a line is being
+// drawn by at least one of the 2D engines
+func (emu *NDSEmulator) videoPenalty(addr uint32) int64 {
+	if emu.Mode != ModeNds {
+		return 0
+	}
+
+	x, y := emu.Sync.DotPos()
+	cfg := emu.Hw.Lcd9.Cfg
+	if y >= cfg.VBlankFirstLine || x > cfg.HBlankFirstDot {
+		return 0
+	}
+	if emu.Hw.E2d[0].DispCnt.Value&(1<<7) != 0 && emu.Hw.E2d[1].DispCnt.Value&(1<<7) != 0 {
+		// Both engines in forced blank
+		return 0
+	}
+	return cVideoPenalty
+}
+
+func (emu *NDSEmulator) oamBusy(addr uint32) bool {
+	if emu.Mode != ModeNds {
+		return false
+	}
+
+	x, y := emu.Sync.DotPos()
+	cfg := emu.Hw.Lcd9.Cfg
+	if y >= cfg.VBlankFirstLine {
+		return false
+	}
+
+	// OAM is 1K per engine (A then B), mirrored every 2K
+	dispcnt := emu.Hw.E2d[(addr>>10)&1].DispCnt.Value
+	if dispcnt&(1<<7) != 0 || dispcnt&(1<<12) == 0 {
+		// Forced blank, or OBJ disabled
+		return false
+	}
+	if x > cfg.HBlankFirstDot && dispcnt&(1<<23) != 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	imgcolor "image/color"
+	"image/png"
+	"io"
+	"ndsemu/e2d"
+	log "ndsemu/emu/logger"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Names of the palettes, as used in palette patches and exported files
+var paletteNames = [...]string{"A-bg", "A-obj", "B-bg", "B-obj"}
+
+// ParsePalettePatch parses a palette patch, returning the patches for the
+// two 2D engines (nil if an engine is not patched). Each line overrides a
+// palette entry, or replaces a color in all the palettes; values are in
+// hex, colors are RGB555, and "#" starts a comment. For instance:
+//
+//	A-bg   0C  7FFF   # entry 0x0C of the BG palette of engine A is white
+//	B-obj  01  001F   # entry 0x01 of the OBJ palette of engine B is red
+//	*      001F 7C00  # red is replaced with blue everywhere
+func ParsePalettePatch(r io.Reader) ([2]*e2d.PalettePatch, error) {
+	var pp [2]*e2d.PalettePatch
+	patch := func(eng int) *e2d.PalettePatch {
+		if pp[eng] == nil {
+			pp[eng] = &e2d.PalettePatch{
+				Remap: make(map[uint16]uint16),
+				Bg:    make(map[int]uint16),
+				Obj:   make(map[int]uint16),
+			}
+		}
+		return pp[eng]
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return pp, fmt.Errorf("line %d: invalid line", lineno)
+		}
+		arg, err1 := strconv.ParseUint(fields[1], 16, 16)
+		col, err2 := strconv.ParseUint(fields[2], 16, 16)
+		if err1 != nil || err2 != nil || col > 0x7FFF {
+			return pp, fmt.Errorf("line %d: invalid value", lineno)
+		}
+
+		if fields[0] == "*" {
+			if arg > 0x7FFF {
+				return pp, fmt.Errorf("line %d: invalid color: %s", lineno, fields[1])
+			}
+			patch(0).Remap[uint16(arg)] = uint16(col)
+			patch(1).Remap[uint16(arg)] = uint16(col)
+			continue
+		}
+		if arg > 0xFF {
+			return pp, fmt.Errorf("line %d: invalid palette entry: %s", lineno, fields[1])
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "A-BG":
+			patch(0).Bg[int(arg)] = uint16(col)
+		case "A-OBJ":
+			patch(0).Obj[int(arg)] = uint16(col)
+		case "B-BG":
+			patch(1).Bg[int(arg)] = uint16(col)
+		case "B-OBJ":
+			patch(1).Obj[int(arg)] = uint16(col)
+		default:
+			return pp, fmt.Errorf("line %d: invalid palette: %q", lineno, fields[0])
+		}
+	}
+	return pp, scanner.Err()
+}
+
+// SetPaletteDir configures the directory where palettes are exported (see
+// ExportPalettes).
+func (emu *NDSEmulator) SetPaletteDir(dir string) {
+	emu.palDir = dir
+}
+
+// applyPalettePatch applies the palette patch configured for the game in
+// slot 1 in the game database (see LoadGameDb), if any. Like the quirks, it's
+// looked up again every time the hardware is reset, so that it follows ROM
+// switches.
+func (emu *NDSEmulator) applyPalettePatch() {
+	var pp [2]*e2d.PalettePatch
+	var gamecode [4]byte
+	emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+	if fn := emu.gameDb[string(gamecode[:])].Palette; fn != "" {
+		f, err := os.Open(fn)
+		if err == nil {
+			pp, err = ParsePalettePatch(f)
+			f.Close()
+		}
+		if err != nil {
+			log.ModEmu.ErrorZ("invalid palette patch").String("file", fn).Error("err", err).End()
+			pp = [2]*e2d.PalettePatch{}
+		} else {
+			log.ModEmu.InfoZ("palette patch applied").String("file", fn).End()
+		}
+	}
+	emu.Hw.E2d[0].SetPalettePatch(pp[0])
+	emu.Hw.E2d[1].SetPalettePatch(pp[1])
+}
+
+// fileGameCode returns the game code of the cartridge in slot 1, in a form
+// that can be used in file names: the header is not validated, so bytes that
+// are not letters or digits (eg: "/" or control characters in homebrew and
+// corrupted ROMs) are replaced with "_".
+func (emu *NDSEmulator) fileGameCode() string {
+	var gamecode [4]byte
+	emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+	for i, c := range gamecode {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			gamecode[i] = '_'
+		}
+	}
+	return string(gamecode[:])
+}
+
+// ExportPalettes saves the current BG and OBJ palettes of both engines (as
+// found in palette RAM, without patches) into the palette directory, each one
+// both as a JASC palette (.pal) and as a PNG swatch of 16x16 colors. Files
+// are named after the game code and the frame number.
+func (emu *NDSEmulator) ExportPalettes() error {
+	if emu.palDir == "" {
+		return fmt.Errorf("palette directory not configured")
+	}
+	if err := os.MkdirAll(emu.palDir, 0777); err != nil {
+		return err
+	}
+
+	gamecode := emu.fileGameCode()
+	for i, name := range paletteNames {
+		pram := emu.Hw.Mc.VramPalette(i / 2)[(i%2)*512:]
+		var pal [256]imgcolor.RGBA
+		for j := range pal {
+			c := uint16(pram[j*2]) | uint16(pram[j*2+1])<<8
+			r, g, b := uint8(c&0x1F), uint8(c>>5&0x1F), uint8(c>>10&0x1F)
+			pal[j] = imgcolor.RGBA{r<<3 | r>>2, g<<3 | g>>2, b<<3 | b>>2, 0xFF}
+		}
+
+		base := filepath.Join(emu.palDir, fmt.Sprintf("%s-%06d-%s", gamecode, emu.framecount, name))
+		if err := writeJascPalette(base+".pal", pal[:]); err != nil {
+			return err
+		}
+		if err := writePaletteSwatch(base+".png", pal[:]); err != nil {
+			return err
+		}
+	}
+	log.ModEmu.WarnZ("palettes exported").String("dir", emu.palDir).End()
+	return nil
+}
+
+func writeJascPalette(fn string, pal []imgcolor.RGBA) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "JASC-PAL\r\n0100\r\n%d\r\n", len(pal))
+	for _, c := range pal {
+		fmt.Fprintf(w, "%d %d %d\r\n", c.R, c.G, c.B)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Size of each color in PNG swatches, in pixels
+const cSwatchCell = 8
+
+func writePaletteSwatch(fn string, pal []imgcolor.RGBA) error {
+	img := image.NewRGBA(image.Rect(0, 0, 16*cSwatchCell, 16*cSwatchCell))
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			img.SetRGBA(x, y, pal[(y/cSwatchCell)*16+x/cSwatchCell])
+		}
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePalettePatch(t *testing.T) {
+	pp, err := ParsePalettePatch(strings.NewReader(`
+# comment
+A-bg   0C   7FFF
+b-obj  01   001F  # case-insensitive
+*      001F 7C00
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp[0].Bg[0x0C] != 0x7FFF || len(pp[0].Obj) != 0 {
+		t.Errorf("invalid engine A patch: %+v", pp[0])
+	}
+	if pp[1].Obj[0x01] != 0x001F || len(pp[1].Bg) != 0 {
+		t.Errorf("invalid engine B patch: %+v", pp[1])
+	}
+	for i := range pp {
+		if pp[i].Remap[0x001F] != 0x7C00 {
+			t.Errorf("missing remap in engine %d", i)
+		}
+	}
+
+	for _, s := range []string{
+		"A-bg 100 7FFF",  // entry out of range
+		"A-bg 0C 8000",   // invalid color
+		"C-bg 0C 7FFF",   // invalid palette
+		"A-bg 0C",        // missing field
+		"* 8000 0000",    // invalid color
+		"A-obj 0C 7FFFF", // invalid color
+	} {
+		if _, err := ParsePalettePatch(strings.NewReader(s)); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
+
+func TestExportPalettes(t *testing.T) {
+	emu := newTestEmulator(t)
+	emu.SetPaletteDir(t.TempDir())
+	if err := emu.ExportPalettes(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a cartridge, the game code reads as 0xFF bytes, which must
+	// not end up in file names
+	for _, name := range paletteNames {
+		for _, ext := range []string{".pal", ".png"} {
+			fn := filepath.Join(emu.palDir, "____-000000-"+name+ext)
+			if _, err := os.Stat(fn); err != nil {
+				t.Errorf("missing export: %v", err)
+			}
+		}
+	}
+}
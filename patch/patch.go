@@ -0,0 +1,309 @@
+// Package patch applies ROM patches (as commonly used for translations and
+// hacks) in memory, so that patched copies of ROMs are not required. The
+// supported formats are IPS, UPS, BPS and VCDIFF (as produced by xdelta3,
+// without secondary compression).
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Extensions of patch files, in the order in which they are looked for by Find
+var Extensions = []string{".ips", ".ups", ".bps", ".xdelta", ".vcdiff"}
+
+var errTruncated = errors.New("truncated patch")
+
+// cMaxSize is the largest patched ROM accepted (the size of the biggest NDS
+// cartridges); sizes declared by patches are checked against it before
+// allocating the output
+const cMaxSize = 512 << 20
+
+var errTooLarge = errors.New("invalid patch (patched ROM too large)")
+
+// Apply applies the patch to src, and returns the patched data (src is not
+// modified). The format of the patch is detected from its contents.
+func Apply(src []byte, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, []byte("PATCH")):
+		return applyIps(src, patch)
+	case bytes.HasPrefix(patch, []byte("UPS1")):
+		return applyUps(src, patch)
+	case bytes.HasPrefix(patch, []byte("BPS1")):
+		return applyBps(src, patch)
+	case bytes.HasPrefix(patch, vcdiffMagic):
+		return applyVcdiff(src, patch)
+	}
+	return nil, errors.New("unknown patch format")
+}
+
+// ApplyFile applies the patch stored in the specified file
+func ApplyFile(src []byte, fn string) ([]byte, error) {
+	patch, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := Apply(src, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn, err)
+	}
+	return dst, nil
+}
+
+// Find looks for a patch next to a ROM file, with the same name and one of
+// the known extensions (eg: "game.ips" or "game.nds.ips" for "game.nds"). It
+// returns an empty string if there is none.
+func Find(romfn string) string {
+	base := strings.TrimSuffix(romfn, ".nds")
+	for _, b := range []string{base, romfn} {
+		for _, ext := range Extensions {
+			if fi, err := os.Stat(b + ext); err == nil && fi.Mode().IsRegular() {
+				return b + ext
+			}
+		}
+	}
+	return ""
+}
+
+// grow returns buf extended with zeros to at least size bytes
+func grow(buf []byte, size int) []byte {
+	if size > len(buf) {
+		buf = append(buf, make([]byte, size-len(buf))...)
+	}
+	return buf
+}
+
+func applyIps(src []byte, patch []byte) ([]byte, error) {
+	dst := append([]byte(nil), src...)
+	p := patch[5:]
+	for {
+		if len(p) < 3 {
+			return nil, errTruncated
+		}
+		if string(p[:3]) == "EOF" {
+			p = p[3:]
+			break
+		}
+		if len(p) < 5 {
+			return nil, errTruncated
+		}
+		off := int(p[0])<<16 | int(p[1])<<8 | int(p[2])
+		size := int(p[3])<<8 | int(p[4])
+		p = p[5:]
+
+		if size == 0 {
+			// RLE record
+			if len(p) < 3 {
+				return nil, errTruncated
+			}
+			size = int(p[0])<<8 | int(p[1])
+			dst = grow(dst, off+size)
+			for i := 0; i < size; i++ {
+				dst[off+i] = p[2]
+			}
+			p = p[3:]
+		} else {
+			if len(p) < size {
+				return nil, errTruncated
+			}
+			dst = grow(dst, off+size)
+			copy(dst[off:], p[:size])
+			p = p[size:]
+		}
+	}
+
+	// Optional extension: size of the patched file
+	if len(p) == 3 {
+		size := int(p[0])<<16 | int(p[1])<<8 | int(p[2])
+		if size < len(dst) {
+			dst = dst[:size]
+		}
+	}
+	return dst, nil
+}
+
+// reader decodes the variable-length integers used by UPS and BPS
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) byte() byte {
+	if len(r.buf) == 0 {
+		r.err = errTruncated
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *reader) bytes(n int) []byte {
+	if n < 0 || n > len(r.buf) {
+		r.err = errTruncated
+		n = len(r.buf)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) varint() int {
+	var data, shift uint64 = 0, 1
+	for r.err == nil {
+		x := r.byte()
+		data += uint64(x&0x7F) * shift
+		if x&0x80 != 0 {
+			break
+		}
+		shift <<= 7
+		data += shift
+		if data > 1<<40 {
+			r.err = errors.New("invalid integer")
+		}
+	}
+	return int(data)
+}
+
+func le32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+// checkFooter verifies the checksums at the end of UPS and BPS patches, and
+// returns the patch without the footer
+func checkFooter(src []byte, patch []byte) ([]byte, uint32, error) {
+	if len(patch) < 12 {
+		return nil, 0, errTruncated
+	}
+	footer := patch[len(patch)-12:]
+	if crc32.ChecksumIEEE(patch[:len(patch)-4]) != le32(footer[8:]) {
+		return nil, 0, errors.New("corrupted patch (checksum mismatch)")
+	}
+	if crc32.ChecksumIEEE(src) != le32(footer[0:]) {
+		return nil, 0, errors.New("patch does not apply to this ROM (checksum mismatch)")
+	}
+	return patch[:len(patch)-12], le32(footer[4:]), nil
+}
+
+func applyUps(src []byte, patch []byte) ([]byte, error) {
+	body, dstcrc, err := checkFooter(src, patch)
+	if err != nil {
+		return nil, err
+	}
+	r := &reader{buf: body[4:]}
+	srcsize, dstsize := r.varint(), r.varint()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if srcsize != len(src) {
+		return nil, errors.New("patch does not apply to this ROM (size mismatch)")
+	}
+	if dstsize < 0 || dstsize > cMaxSize {
+		return nil, errTooLarge
+	}
+
+	// Records are a relative offset, followed by a zero-terminated run of
+	// bytes to XOR with the source
+	dst := grow(append([]byte(nil), src...), dstsize)[:dstsize]
+	pos := 0
+	for r.err == nil && len(r.buf) > 0 {
+		// Bytes past the end are ignored, so there's no need to track the
+		// position any further
+		if pos += r.varint(); pos > len(dst) {
+			pos = len(dst)
+		}
+		for r.err == nil {
+			x := r.byte()
+			if x == 0 {
+				pos++
+				break
+			}
+			if pos < len(dst) {
+				dst[pos] ^= x
+			}
+			pos++
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if crc32.ChecksumIEEE(dst) != dstcrc {
+		return nil, errors.New("invalid patched ROM (checksum mismatch)")
+	}
+	return dst, nil
+}
+
+func applyBps(src []byte, patch []byte) ([]byte, error) {
+	body, dstcrc, err := checkFooter(src, patch)
+	if err != nil {
+		return nil, err
+	}
+	r := &reader{buf: body[4:]}
+	srcsize, dstsize := r.varint(), r.varint()
+	r.bytes(r.varint()) // metadata
+	if r.err != nil {
+		return nil, r.err
+	}
+	if srcsize != len(src) {
+		return nil, errors.New("patch does not apply to this ROM (size mismatch)")
+	}
+	if dstsize < 0 || dstsize > cMaxSize {
+		return nil, errTooLarge
+	}
+
+	dst := make([]byte, dstsize)
+	var out, srcrel, dstrel int
+	for r.err == nil && len(r.buf) > 0 {
+		data := r.varint()
+		if r.err != nil {
+			break
+		}
+		cmd, length := data&3, (data>>2)+1
+		if length <= 0 || length > len(dst)-out {
+			return nil, errors.New("invalid patch (write out of bounds)")
+		}
+
+		switch cmd {
+		case 0: // SourceRead
+			if length > len(src)-out {
+				return nil, errors.New("invalid patch (read out of bounds)")
+			}
+			copy(dst[out:], src[out:out+length])
+		case 1: // TargetRead
+			copy(dst[out:], r.bytes(length))
+		case 2, 3: // SourceCopy, TargetCopy
+			d := r.varint()
+			rel, from := &srcrel, src
+			if cmd == 3 {
+				rel, from = &dstrel, dst
+			}
+			if d&1 != 0 {
+				*rel -= d >> 1
+			} else {
+				*rel += d >> 1
+			}
+			// TargetCopy can only read what has already been written
+			if *rel < 0 || *rel > len(from) || length > len(from)-*rel || (cmd == 3 && *rel >= out) {
+				return nil, errors.New("invalid patch (read out of bounds)")
+			}
+			// Byte by byte, as TargetCopy can overlap with the output
+			for i := 0; i < length; i++ {
+				dst[out+i] = from[*rel+i]
+			}
+			*rel += length
+		}
+		out += length
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if crc32.ChecksumIEEE(dst) != dstcrc {
+		return nil, errors.New("invalid patched ROM (checksum mismatch)")
+	}
+	return dst, nil
+}
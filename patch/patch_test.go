@@ -0,0 +1,152 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"strings"
+	"testing"
+)
+
+func upsVarint(v int) []byte {
+	var b []byte
+	for {
+		x := byte(v & 0x7F)
+		v >>= 7
+		if v == 0 {
+			return append(b, x|0x80)
+		}
+		b = append(b, x)
+		v--
+	}
+}
+
+// withFooter appends the UPS/BPS footer (checksums) to a patch
+func withFooter(p []byte, src, dst string) []byte {
+	var footer [4]byte
+	binary.LittleEndian.PutUint32(footer[:], crc32.ChecksumIEEE([]byte(src)))
+	p = append(p, footer[:]...)
+	binary.LittleEndian.PutUint32(footer[:], crc32.ChecksumIEEE([]byte(dst)))
+	p = append(p, footer[:]...)
+	binary.LittleEndian.PutUint32(footer[:], crc32.ChecksumIEEE(p))
+	return append(p, footer[:]...)
+}
+
+func cat(parts ...[]byte) []byte {
+	var res []byte
+	for _, p := range parts {
+		res = append(res, p...)
+	}
+	return res
+}
+
+func TestApply(t *testing.T) {
+	const src = "hello world"
+
+	ips := cat([]byte("PATCH"),
+		[]byte{0, 0, 0, 0, 1, 'j'},
+		[]byte{0, 0, 11, 0, 0, 0, 3, '!'},
+		[]byte("EOF"))
+
+	ups := withFooter(cat([]byte("UPS1"), upsVarint(11), upsVarint(12),
+		upsVarint(0), []byte{'h' ^ 'j', 0},
+		upsVarint(9), []byte{'!', 0}), src, "jello world!")
+
+	bps := withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(18), upsVarint(0),
+		upsVarint(5<<2|0),                   // SourceRead "hello "
+		upsVarint(5<<2|1), []byte("there "), // TargetRead "there "
+		upsVarint(4<<2|2), upsVarint(6<<1), // SourceCopy "world"
+		upsVarint(0<<2|1), []byte("!")), src, "hello there world!")
+
+	vcdiff := cat(vcdiffMagic, []byte{0},
+		[]byte{vcdSource, 11, 0, 21, 25, 0, 7, 6, 3},
+		[]byte("there !"),
+		[]byte{22, 7, 21, 0, 4, 36},
+		[]byte{0, 6, 2})
+
+	for _, tc := range []struct {
+		name  string
+		patch []byte
+		exp   string
+	}{
+		{"ips", ips, "jello world!!!"},
+		{"ups", ups, "jello world!"},
+		{"bps", bps, "hello there world!"},
+		{"vcdiff", vcdiff, "hello there world!!!!!!!!"},
+	} {
+		dst, err := Apply([]byte(src), tc.patch)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if string(dst) != tc.exp {
+			t.Errorf("%s: got %q, want %q", tc.name, dst, tc.exp)
+		}
+	}
+
+	// Patches with checksums must be rejected for other ROMs
+	if _, err := Apply([]byte("other"), bps); err == nil {
+		t.Errorf("bps: patch applied to wrong ROM")
+	}
+
+	// Oversized outputs must be rejected before being allocated
+	for _, tc := range []struct {
+		name  string
+		patch []byte
+	}{
+		{"ups", withFooter(cat([]byte("UPS1"), upsVarint(11), upsVarint(1<<40)), src, "")},
+		{"bps", withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(1<<40), upsVarint(0)), src, "")},
+		{"vcdiff", cat(vcdiffMagic, []byte{0}, []byte{0, 0, 0x83, 0x80, 0x80, 0x80, 0})},
+	} {
+		if _, err := Apply([]byte(src), tc.patch); err != errTooLarge {
+			t.Errorf("%s: unexpected error for oversized output: %v", tc.name, err)
+		}
+	}
+}
+
+func TestApplyMalformed(t *testing.T) {
+	const src = "hello world"
+
+	// A varint that doesn't terminate within 64 bits
+	huge := append(bytes.Repeat([]byte{0x7F}, 10), 0xFF)
+
+	for _, tc := range []struct {
+		name  string
+		patch []byte
+		err   string
+	}{
+		{"ips truncated", []byte("PATCH\x00\x00\x00\x00\x05ab"), "truncated patch"},
+		{"ups truncated", withFooter(cat([]byte("UPS1"), upsVarint(11), upsVarint(11), upsVarint(0), []byte{1}), src, src), "truncated patch"},
+		{"ups huge size", withFooter(cat([]byte("UPS1"), upsVarint(11), huge), src, src), "invalid integer"},
+		{"ups huge offset", withFooter(cat([]byte("UPS1"), upsVarint(11), upsVarint(11), huge, []byte{1, 0}), src, src), "invalid integer"},
+		{"bps huge command", withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(11), upsVarint(0), huge), src, src), "invalid integer"},
+		{"bps write past end", withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(11), upsVarint(0),
+			upsVarint(11<<2|0)), src, src), "write out of bounds"},
+		{"bps source copy before start", withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(11), upsVarint(0),
+			upsVarint(0<<2|2), upsVarint(1<<1|1)), src, src), "read out of bounds"},
+		{"bps source copy past end", withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(11), upsVarint(0),
+			upsVarint(0<<2|2), upsVarint(1<<39)), src, src), "read out of bounds"},
+		{"bps target copy ahead", withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(11), upsVarint(0),
+			upsVarint(0<<2|3), upsVarint(0)), src, src), "read out of bounds"},
+		{"vcdiff huge window", cat(vcdiffMagic, []byte{0, 0, 0}, bytes.Repeat([]byte{0xFF}, 10), []byte{0}), "invalid integer"},
+	} {
+		if _, err := Apply([]byte(src), tc.patch); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func FuzzApply(f *testing.F) {
+	const src = "hello world"
+	f.Add([]byte("PATCH\x00\x00\x00\x00\x01jEOF"))
+	f.Add(withFooter(cat([]byte("UPS1"), upsVarint(11), upsVarint(11), upsVarint(0), []byte{2, 0}), src, "jello world"))
+	f.Add(withFooter(cat([]byte("BPS1"), upsVarint(11), upsVarint(11), upsVarint(0), upsVarint(10<<2|0)), src, src))
+	f.Add(cat(vcdiffMagic, []byte{0, vcdSource, 11, 0, 0, 11, 0, 0, 0, 0}))
+
+	f.Fuzz(func(t *testing.T, patch []byte) {
+		// Any patch must either apply or be rejected, without panicking
+		if dst, err := Apply([]byte(src), patch); err == nil && len(dst) > cMaxSize {
+			t.Errorf("patched ROM too large: %d bytes", len(dst))
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package patch
+
+import (
+	"errors"
+	"hash/adler32"
+)
+
+// VCDIFF (RFC 3284) decoder, for patches created by xdelta3. Secondary
+// compression and custom code tables are not supported (xdelta3 must be run
+// with -S none, which is the default for recent versions).
+
+var vcdiffMagic = []byte{0xD6, 0xC3, 0xC4, 0x00}
+
+const (
+	vcdDecompress = 1 << 0
+	vcdCodeTable  = 1 << 1
+	vcdAppHeader  = 1 << 2 // xdelta3 extension
+
+	vcdSource  = 1 << 0
+	vcdTarget  = 1 << 1
+	vcdAdler32 = 1 << 2 // xdelta3 extension
+)
+
+const (
+	vcdNoop = iota
+	vcdAdd
+	vcdRun
+	vcdCopy
+)
+
+type vcdInst struct {
+	typ, size, mode uint8
+}
+
+// Default code table (RFC 3284, section 5.6); each entry is a pair of
+// instructions
+var vcdDefaultTable [256][2]vcdInst
+
+func init() {
+	i := 0
+	vcdDefaultTable[i][0] = vcdInst{vcdRun, 0, 0}
+	i++
+	for size := 0; size <= 17; size++ {
+		vcdDefaultTable[i][0] = vcdInst{vcdAdd, uint8(size), 0}
+		i++
+	}
+	for mode := 0; mode <= 8; mode++ {
+		vcdDefaultTable[i][0] = vcdInst{vcdCopy, 0, uint8(mode)}
+		i++
+		for size := 4; size <= 18; size++ {
+			vcdDefaultTable[i][0] = vcdInst{vcdCopy, uint8(size), uint8(mode)}
+			i++
+		}
+	}
+	for mode := 0; mode <= 8; mode++ {
+		maxcopy := 6
+		if mode >= 6 {
+			maxcopy = 4
+		}
+		for add := 1; add <= 4; add++ {
+			for size := 4; size <= maxcopy; size++ {
+				vcdDefaultTable[i] = [2]vcdInst{{vcdAdd, uint8(add), 0}, {vcdCopy, uint8(size), uint8(mode)}}
+				i++
+			}
+		}
+	}
+	for mode := 0; mode <= 8; mode++ {
+		vcdDefaultTable[i] = [2]vcdInst{{vcdCopy, 4, uint8(mode)}, {vcdAdd, 1, 0}}
+		i++
+	}
+}
+
+// vcdReader decodes the big-endian variable-length integers used by VCDIFF
+type vcdReader struct {
+	reader
+}
+
+func (r *vcdReader) varint() int {
+	var v uint64
+	for r.err == nil {
+		x := r.byte()
+		v = v<<7 | uint64(x&0x7F)
+		if x&0x80 == 0 {
+			break
+		}
+		if v > 1<<40 {
+			r.err = errors.New("invalid integer")
+		}
+	}
+	return int(v)
+}
+
+// Cache of recent addresses, used to encode COPY addresses compactly
+type vcdAddrCache struct {
+	near     [4]int
+	nextSlot int
+	same     [3 * 256]int
+}
+
+func (c *vcdAddrCache) update(addr int) {
+	c.near[c.nextSlot] = addr
+	c.nextSlot = (c.nextSlot + 1) % len(c.near)
+	c.same[addr%len(c.same)] = addr
+}
+
+func (c *vcdAddrCache) decode(r *vcdReader, here int, mode uint8) int {
+	var addr int
+	switch {
+	case mode == 0: // VCD_SELF
+		addr = r.varint()
+	case mode == 1: // VCD_HERE
+		addr = here - r.varint()
+	case mode < 6:
+		addr = c.near[mode-2] + r.varint()
+	default:
+		addr = c.same[int(mode-6)*256+int(r.byte())]
+	}
+	// Invalid (negative) addresses are rejected by the caller
+	if addr >= 0 {
+		c.update(addr)
+	}
+	return addr
+}
+
+func applyVcdiff(src []byte, patch []byte) ([]byte, error) {
+	r := &vcdReader{reader{buf: patch[4:]}}
+
+	hdr := r.byte()
+	if hdr&(vcdDecompress|vcdCodeTable) != 0 {
+		return nil, errors.New("unsupported VCDIFF features (secondary compression or custom code table)")
+	}
+	if hdr&vcdAppHeader != 0 {
+		r.bytes(r.varint())
+	}
+
+	var dst []byte
+	for r.err == nil && len(r.buf) > 0 {
+		// Window header
+		win := r.byte()
+		var seg []byte
+		if win&(vcdSource|vcdTarget) != 0 {
+			size, pos := r.varint(), r.varint()
+			from := src
+			if win&vcdTarget != 0 {
+				from = dst
+			}
+			if r.err == nil && (pos < 0 || size < 0 || pos+size > len(from)) {
+				return nil, errors.New("invalid VCDIFF window (source segment out of bounds)")
+			}
+			if r.err == nil {
+				seg = from[pos : pos+size]
+			}
+		}
+		r.varint() // length of the delta encoding
+		winsize := r.varint()
+		if r.err != nil {
+			return nil, r.err
+		}
+		if winsize < 0 || winsize > cMaxSize {
+			return nil, errTooLarge
+		}
+		if r.byte() != 0 {
+			return nil, errors.New("unsupported VCDIFF features (compressed sections)")
+		}
+		datalen, instlen, addrlen := r.varint(), r.varint(), r.varint()
+		var checksum []byte
+		if win&vcdAdler32 != 0 {
+			checksum = r.bytes(4)
+		}
+		data := &vcdReader{reader{buf: r.bytes(datalen)}}
+		inst := &vcdReader{reader{buf: r.bytes(instlen)}}
+		addrs := &vcdReader{reader{buf: r.bytes(addrlen)}}
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		// Decode the instructions into the target window
+		out := make([]byte, 0, winsize)
+		var cache vcdAddrCache
+		for len(inst.buf) > 0 && inst.err == nil {
+			for _, in := range vcdDefaultTable[inst.byte()] {
+				if in.typ == vcdNoop {
+					continue
+				}
+				size := int(in.size)
+				if size == 0 {
+					size = inst.varint()
+				}
+				if len(out)+size > winsize {
+					return nil, errors.New("invalid VCDIFF window (target overflow)")
+				}
+				switch in.typ {
+				case vcdAdd:
+					out = append(out, data.bytes(size)...)
+				case vcdRun:
+					b := data.byte()
+					for i := 0; i < size; i++ {
+						out = append(out, b)
+					}
+				case vcdCopy:
+					addr := cache.decode(addrs, len(seg)+len(out), in.mode)
+					if addr < 0 || addr >= len(seg)+len(out) {
+						return nil, errors.New("invalid VCDIFF copy address")
+					}
+					// Byte by byte, as copies from the target window
+					// can overlap with the output
+					for i := 0; i < size; i++ {
+						if a := addr + i; a < len(seg) {
+							out = append(out, seg[a])
+						} else {
+							out = append(out, out[a-len(seg)])
+						}
+					}
+				}
+			}
+		}
+		for _, err := range []error{inst.err, data.err, addrs.err} {
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(out) != winsize {
+			return nil, errors.New("invalid VCDIFF window (size mismatch)")
+		}
+		if checksum != nil && adler32.Checksum(out) != uint32(checksum[0])<<24|uint32(checksum[1])<<16|uint32(checksum[2])<<8|uint32(checksum[3]) {
+			return nil, errors.New("invalid patched ROM (checksum mismatch)")
+		}
+		dst = append(dst, out...)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return dst, nil
+}
@@ -10,9 +10,13 @@ import (
 var modPower = log.NewModule("powerman")
 
 type HwPowerMan struct {
-	cntrl   uint8
-	mic     bool
-	micgain int
+	// Lite enables the register of the DS Lite (see ConsoleType.Lite)
+	Lite bool
+
+	cntrl     uint8
+	mic       bool
+	micgain   int
+	backlight uint8 // DS Lite only
 }
 
 func NewHwPowerMan() *HwPowerMan {
@@ -45,6 +49,13 @@ func (ff *HwPowerMan) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 		case 3:
 			ff.micgain = 20 * int((val&3)+1)
 			modPower.InfoZ("set microphone gain").Int("gain", ff.micgain).End()
+		case 4:
+			if !ff.Lite {
+				modPower.WarnZ("write DS Lite reg on DS").Hex8("val", val).End()
+				break
+			}
+			ff.backlight = val & 7
+			modPower.InfoZ("set backlight").Hex8("val", val).End()
 		default:
 			modPower.WarnZ("write unknown reg").Uint8("reg", index&0x7F).Hex8("val", val).End()
 		}
@@ -67,6 +78,14 @@ func (ff *HwPowerMan) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 				val |= 1
 			}
 			return []byte{val}, spi.ReqFinish
+		case 4:
+			if !ff.Lite {
+				modPower.WarnZ("read DS Lite reg on DS").End()
+				return nil, spi.ReqFinish
+			}
+			// Bits 0-1: backlight level, bit 2: max level on external
+			// power, bit 3: external power present (never)
+			return []byte{ff.backlight}, spi.ReqFinish
 		default:
 			modPower.WarnZ("read unknown reg").Uint8("reg", index&0x7F).End()
 			return nil, spi.ReqFinish
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	log "ndsemu/emu/logger"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Quirks are accurate behaviors that are disabled by default (because they
+// are slower, or less tested), but are required by specific games, usually
+// as part of their anti-piracy checks.
+type Quirks uint32
+
+const (
+	// QuirkCardTiming emulates the exact gamecard transfer timing: the
+	// initial latency is only paid on the first word of a transfer, and
+	// the gap between 0x200-byte blocks is added at block boundaries.
+	QuirkCardTiming Quirks = 1 << iota
+
+	// QuirkSaveTiming emulates the write cycle of the save memory: after
+	// a write, the status register reports "write in progress" for a few
+	// milliseconds, and the write enable latch is then cleared. AUXSPI
+	// transfers also take time, during which AUXSPICNT reports busy.
+	QuirkSaveTiming
+
+	// QuirkVideoTiming emulates the OAM access restrictions while the
+	// screen is being drawn (see oamWriteFilter).
+	QuirkVideoTiming
+
+	// QuirkFetchTiming emulates the wait states of code fetches, which
+	// depend on the memory region and on whether the fetch is sequential
+	// (see NDS7.SetFetchTiming and NDS9.SetFetchTiming).
+	QuirkFetchTiming
+
+	// QuirkIrqTiming emulates the synchronization delay between an IRQ
+	// being raised and the CPU taking it (see cIrqLatency).
+	QuirkIrqTiming
+
+	// QuirkObjLimit emulates the per-scanline OBJ cycle budget: sprites
+	// that exceed it are not drawn (see e2d.HwEngine2d.SetObjLineLimit).
+	QuirkObjLimit
+
+	// QuirkVramTiming emulates the contention between the ARM9 and the
+	// video hardware: palette, VRAM and OAM accesses are slower while the
+	// screen is being drawn (see videoPenalty).
+	QuirkVramTiming
+
+	// QuirkCacheTiming makes the ARM9 accesses to main RAM as fast as cache
+	// hits while the data cache is enabled (see NDS9.SetMemTiming). The
+	// cache itself is not emulated, so this assumes a 100% hit rate: code
+	// that misses often (eg: walking large buffers) runs faster than on
+	// hardware, where each miss fills a 32-byte line from main RAM.
+	QuirkCacheTiming
+
+	// QuirkFifoTiming makes the DMA transfers that feed a FIFO (geometry
+	// commands, GBA sound) stall the CPU, instead of being instantaneous
+	// (see HwDmaChannel.Stall).
+	QuirkFifoTiming
+
+	// QuirkLineLatch emulates the latching of the reference points of the
+	// affine BGs at each scanline: any write to BGxX/BGxY reloads them,
+	// even with the same value (see e2d.HwEngine2d.SetRefPointLatch).
+	QuirkLineLatch
+)
+
+var quirkNames = []struct {
+	q    Quirks
+	name string
+}{
+	{QuirkCardTiming, "card-timing"},
+	{QuirkSaveTiming, "save-timing"},
+	{QuirkVideoTiming, "video-timing"},
+	{QuirkFetchTiming, "fetch-timing"},
+	{QuirkIrqTiming, "irq-timing"},
+	{QuirkObjLimit, "obj-limit"},
+	{QuirkVramTiming, "vram-timing"},
+	{QuirkCacheTiming, "cache-timing"},
+	{QuirkFifoTiming, "fifo-timing"},
+	{QuirkLineLatch, "line-latch"},
+}
+
+// Accuracy presets are named sets of quirks, to be enabled for all games
+// (with -accuracy) or for specific games (in the game database, in place
+// of a quirk name). The quirks listed in the game database for a game are
+// always enabled, whatever the preset, as the game requires them.
+var accuracyPresets = []struct {
+	name string
+	q    Quirks
+}{
+	{"speed", 0},
+	{"balanced", QuirkCardTiming | QuirkSaveTiming},
+	{"accuracy", QuirkCardTiming | QuirkSaveTiming | QuirkVideoTiming | QuirkFetchTiming | QuirkIrqTiming |
+		QuirkObjLimit | QuirkVramTiming | QuirkCacheTiming | QuirkFifoTiming | QuirkLineLatch},
+}
+
+// ParseAccuracyPreset returns the quirks enabled by the specified preset
+func ParseAccuracyPreset(name string) (Quirks, error) {
+	var valid []string
+	for _, p := range accuracyPresets {
+		if p.name == name {
+			return p.q, nil
+		}
+		valid = append(valid, p.name)
+	}
+	return 0, fmt.Errorf("unknown accuracy preset: %q (must be one of: %s)", name, strings.Join(valid, ", "))
+}
+
+func (q Quirks) String() string {
+	var names []string
+	for _, qn := range quirkNames {
+		if q&qn.q != 0 {
+			names = append(names, qn.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseQuirks parses a comma-separated list of quirk (or preset) names
+func ParseQuirks(s string) (Quirks, error) {
+	var q Quirks
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, qn := range quirkNames {
+			if qn.name == name {
+				q |= qn.q
+				found = true
+			}
+		}
+		if pq, err := ParseAccuracyPreset(name); err == nil {
+			q |= pq
+			found = true
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown quirk: %q", name)
+		}
+	}
+	return q, nil
+}
+
+// GameDbEntry contains the settings required by a game
+type GameDbEntry struct {
+	Quirks  Quirks
+	ChipID  *[4]byte // gamecard chip ID (nil: default, see Gamecard.SetChipID)
+	Palette string   // palette patch file (see ParsePalettePatch)
+}
+
+// GameDb associates game codes with the settings they need
+type GameDb map[string]GameDbEntry
+
+// LoadGameDb loads a game database from a text file. Each line contains a
+// game code followed by a comma-separated list of quirks or accuracy
+// presets, and/or by the chip ID the gamecard must return (as hex bytes,
+// in the order they are returned), and/or by a palette patch to apply (the
+// path is relative to the database); "#" starts a comment. For instance:
+//
+//	ABCE  card-timing,save-timing  # Some Game (USA)
+//	ABCP  accuracy                 # Some Other Game (EUR)
+//	ABCJ  chipid=C2FF0080          # Some Big Game (JPN)
+//	ABCD  palette=colorblind.txt   # Some Colorful Game (GER)
+func LoadGameDb(fn string) (GameDb, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db := make(GameDb)
+	scanner := bufio.NewScanner(f)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || len(fields[0]) != 4 {
+			return nil, fmt.Errorf("%s:%d: invalid line", fn, lineno)
+		}
+		var entry GameDbEntry
+		for _, f := range fields[1:] {
+			if hexid := strings.TrimPrefix(f, "chipid="); hexid != f {
+				id, err := ParseChipID(hexid)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %v", fn, lineno, err)
+				}
+				entry.ChipID = &id
+				continue
+			}
+			if pal := strings.TrimPrefix(f, "palette="); pal != f {
+				if pal == "" {
+					return nil, fmt.Errorf("%s:%d: missing palette patch file", fn, lineno)
+				}
+				if !filepath.IsAbs(pal) {
+					pal = filepath.Join(filepath.Dir(fn), pal)
+				}
+				entry.Palette = pal
+				continue
+			}
+			q, err := ParseQuirks(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", fn, lineno, err)
+			}
+			entry.Quirks |= q
+		}
+		db[strings.ToUpper(fields[0])] = entry
+	}
+	return db, scanner.Err()
+}
+
+// ParseChipID parses a gamecard chip ID, as 4 hex bytes (eg: "C2FF0080")
+func ParseChipID(s string) ([4]byte, error) {
+	var id [4]byte
+	buf, err := parseHexBytes(s)
+	if err != nil || len(buf) != len(id) {
+		return id, fmt.Errorf("invalid chip ID: %q (must be 4 hex bytes)", s)
+	}
+	copy(id[:], buf)
+	return id, nil
+}
+
+// SetGameDb configures the quirks to enable: those listed in the database for
+// the game inserted in slot 1, plus the forced ones. The game is looked up
+// again every time the hardware is reset, so that the setting survives ROM
+// switches; the same goes for the chip ID of the gamecard and for the palette
+// patch, if the database specifies them.
+func (emu *NDSEmulator) SetGameDb(db GameDb, forced Quirks) {
+	emu.gameDb = db
+	emu.forcedQuirks = forced
+	emu.applyQuirks()
+	emu.applyPalettePatch()
+}
+
+// SetChipID forces the chip ID of the gamecard for all games, overriding the
+// game database (nil: no override). Like the database, it's applied again at
+// every reset, so that it survives ROM switches.
+func (emu *NDSEmulator) SetChipID(id *[4]byte) {
+	emu.forcedChipID = id
+	emu.applyQuirks()
+}
+
+func (emu *NDSEmulator) applyQuirks() {
+	var gamecode [4]byte
+	emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+	entry := emu.gameDb[string(gamecode[:])]
+	q := entry.Quirks | emu.forcedQuirks
+
+	chipid := entry.ChipID
+	if emu.forcedChipID != nil {
+		chipid = emu.forcedChipID
+	}
+	if chipid != nil && emu.Hw.Gc.Size != 0 && emu.Hw.Gc.ChipID() != *chipid {
+		emu.Hw.Gc.SetChipID(*chipid)
+		log.ModEmu.InfoZ("chip ID overridden").String("game", string(gamecode[:])).Blob("id", chipid[:]).End()
+	}
+
+	emu.Hw.Gc.AccurateTiming = q&QuirkCardTiming != 0
+	emu.Hw.Bkp.AccurateTiming = q&QuirkSaveTiming != 0
+	nds9.SetVideoTiming(emu, q&QuirkVideoTiming != 0)
+	nds9.SetMemTiming(emu, q&QuirkVramTiming != 0, q&QuirkCacheTiming != 0)
+	nds9.SetFifoTiming(q&QuirkFifoTiming != 0)
+	nds7.SetFifoTiming(q&QuirkFifoTiming != 0)
+	emu.Hw.E2d[0].SetObjLineLimit(q&QuirkObjLimit != 0)
+	emu.Hw.E2d[1].SetObjLineLimit(q&QuirkObjLimit != 0)
+	emu.Hw.E2d[0].SetRefPointLatch(q&QuirkLineLatch != 0)
+	emu.Hw.E2d[1].SetRefPointLatch(q&QuirkLineLatch != 0)
+	nds9.SetFetchTiming(q&QuirkFetchTiming != 0)
+	nds7.SetFetchTiming(q&QuirkFetchTiming != 0)
+	if q&QuirkIrqTiming != 0 {
+		nds9.Cpu.SetIrqLatency(cIrqLatency * 2)
+		nds7.Cpu.SetIrqLatency(cIrqLatency)
+	} else {
+		nds9.Cpu.SetIrqLatency(0)
+		nds7.Cpu.SetIrqLatency(0)
+	}
+	if q != 0 && q != emu.quirks {
+		log.ModEmu.InfoZ("quirks enabled").String("game", string(gamecode[:])).Stringer("quirks", q).End()
+	}
+	emu.quirks = q
+}
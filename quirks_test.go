@@ -0,0 +1,114 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadGameDb(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "gamedb.txt")
+	ioutil.WriteFile(fn, []byte(`
+# comment
+abce  card-timing,save-timing  # Some Game (USA)
+ABCP  accuracy
+ABCJ  chipid=C2FF0080 video-timing
+ABCD  palette=pal/abcd.txt
+`), 0644)
+
+	db, err := LoadGameDb(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db) != 4 {
+		t.Fatalf("invalid number of games: %d", len(db))
+	}
+	if e := db["ABCE"]; e.Quirks != QuirkCardTiming|QuirkSaveTiming || e.ChipID != nil {
+		t.Errorf("invalid entry for ABCE: %+v", e)
+	}
+	if e := db["ABCJ"]; e.Quirks != QuirkVideoTiming || e.ChipID == nil || *e.ChipID != [4]byte{0xC2, 0xFF, 0x00, 0x80} {
+		t.Errorf("invalid entry for ABCJ: %+v", e)
+	}
+	if e := db["ABCD"]; e.Palette != filepath.Join(filepath.Dir(fn), "pal", "abcd.txt") {
+		t.Errorf("invalid entry for ABCD: %+v", e)
+	}
+
+	for _, bad := range []string{"ABCE", "ABC card-timing", "ABCE foo", "ABCE chipid=C2FF", "ABCE chipid=zz", "ABCE palette="} {
+		ioutil.WriteFile(fn, []byte(bad+"\n"), 0644)
+		if _, err := LoadGameDb(fn); err == nil || !strings.Contains(err.Error(), fn+":1:") {
+			t.Errorf("%q: unexpected error: %v", bad, err)
+		}
+	}
+}
+
+func TestMemTiming(t *testing.T) {
+	emu := newTestEmulator(t)
+	runFrames(emu, 1)
+
+	// At the start of the frame, engine A is drawing line 0 with OBJs enabled
+	emu.Hw.E2d[0].DispCnt.Value = 1 << 12
+	nds9.Cp15.ConfigureControlReg(1<<2, 0) // data cache enabled
+	ws := int64(nds9.Bus.WaitStates() + 1)
+	for _, tc := range []struct {
+		quirks  Quirks
+		oam     uint16
+		penalty int64
+		ram     int64
+	}{
+		{0, 0x1234, 0, ws},
+		{QuirkVideoTiming, 0, 0, ws},
+		{QuirkVramTiming, 0x1234, cVideoPenalty, ws},
+		{QuirkCacheTiming, 0x1234, 0, 1},
+		{QuirkVramTiming | QuirkCacheTiming, 0x1234, cVideoPenalty, 1},
+	} {
+		emu.SetGameDb(nil, tc.quirks)
+		emu.Mem.OamRam[0], emu.Mem.OamRam[1] = 0, 0
+		nds9.Cpu.Write16(0x07000000, 0x1234)
+		if v := nds9.Bus.Read16(0x07000000); v != tc.oam {
+			t.Errorf("%v: OAM after write: %04x, want %04x", tc.quirks, v, tc.oam)
+		}
+
+		for _, addr := range []uint32{0x02000000, 0x05000000, 0x06000000, 0x07000000} {
+			want := ws + tc.penalty
+			if addr == 0x02000000 {
+				want = tc.ram
+			}
+			clk := nds9.Cpu.Clock
+			nds9.Cpu.Read32(addr)
+			if d := nds9.Cpu.Clock - clk; d != want {
+				t.Errorf("%v: read at %08x took %d cycles, want %d", tc.quirks, addr, d, want)
+			}
+		}
+	}
+}
+
+func TestFifoTiming(t *testing.T) {
+	emu := newTestEmulator(t)
+	runFrames(emu, 1)
+
+	for _, tc := range []struct {
+		quirks Quirks
+		stall  int64
+	}{
+		{0, 0},
+		{QuirkFifoTiming, 16 * 4},
+	} {
+		emu.SetGameDb(nil, tc.quirks)
+
+		// 16 words of NOPs to GXFIFO, started by the geometry engine as
+		// soon as the FIFO is less than half full (that is, right away)
+		nds9.Bus.Write32(0x40000B0, 0x02100000)
+		nds9.Bus.Write32(0x40000B4, 0x04000400)
+		nds9.Bus.Write16(0x40000B8, 16)
+		clk := nds9.Cpu.Clock
+		nds9.Bus.Write16(0x40000BA, 1<<15|7<<11|1<<10|2<<5)
+		emu.Hw.Geom.Run(emu.Sync.Cycles())
+		if d := nds9.Cpu.Clock - clk; d != tc.stall {
+			t.Errorf("%v: CPU stalled for %d cycles, want %d", tc.quirks, d, tc.stall)
+		}
+		if nds9.Dma[0].DmaCntrl.Value&(1<<15) != 0 {
+			t.Errorf("%v: DMA not completed", tc.quirks)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"ndsemu/emu/fixed"
 	"ndsemu/emu/gfx"
-	"ndsemu/emu/hw"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
 	"ndsemu/raster3d/fillerconfig"
@@ -17,23 +16,44 @@ import (
 
 var mod3d = log.NewModule("e3d")
 
+// Size of the hardware polygon and vertex RAM. This is the maximum amount of
+// geometry that can be submitted for a single frame; further polygons are
+// dropped, and the overflow flag is set in DISP3DCNT.
+const (
+	cMaxPolygons = 2048
+	cMaxVertices = 6144
+)
+
 type buffer3d struct {
 	Pram     []Polygon
 	Vram     []Vertex
 	ClipVram []Vertex
+
+	// Number of polygons and vertices, as they would be stored in the
+	// hardware polygon/vertex RAM. This doesn't match the length of
+	// Pram/Vram: we split polygons into triangles, and keep track of
+	// vertices that were submitted but never used by a visible polygon.
+	NumHwPolys int
+	NumHwVtxs  int
+
+	// Depth buffer mode selected by SwapBuffers (W or Z)
+	WBuffering bool
 }
 
 func (b *buffer3d) Reset() {
 	b.Pram = b.Pram[:0]
 	b.Vram = b.Vram[:0]
 	b.ClipVram = b.ClipVram[:0]
+	b.NumHwPolys = 0
+	b.NumHwVtxs = 0
+	b.WBuffering = false
 }
 
 type HwEngine3d struct {
-	Disp3dCnt  hwio.Reg32 `hwio:"offset=0,rwmask=0x7FFF"`
+	Disp3dCnt  hwio.Reg32 `hwio:"offset=0,rwmask=0x7FFF,wcb"`
 	ToonTable  hwio.Mem   `hwio:"bank=1,offset=0x80,size=0x40,writeonly"`
 	ClearColor hwio.Reg32 `hwio:"bank=1,offset=0x50,writeonly"`
-	ClearDepth hwio.Reg32 `hwio:"bank=1,offset=0x54,writeonly"`
+	ClearDepth hwio.Reg32 `hwio:"bank=1,offset=0x54,writeonly"` // includes CLEAR_IMAGE_OFFSET
 	FogColor   hwio.Reg32 `hwio:"bank=1,offset=0x58,writeonly"`
 	FogOffset  hwio.Reg32 `hwio:"bank=1,offset=0x5C,rwmask=0x7FFF,writeonly"`
 	FogTable   hwio.Mem   `hwio:"bank=1,offset=0x60,size=0x20,writeonly"`
@@ -46,6 +66,10 @@ type HwEngine3d struct {
 	// Current viewport (last received viewport command)
 	viewport Primitive_SetViewport
 
+	// Polygons that are reduced to a single dot are hidden when their W
+	// coordinate (12.3 fixed point) is beyond this (DISP_1DOT_DEPTH)
+	oneDotDepth int32
+
 	pool sync.Pool
 
 	// Current vram/pram (being drawn)
@@ -64,9 +88,24 @@ type HwEngine3d struct {
 	// the compressed format.
 	texCache texCache
 
+	// Texture dumping and replacement (nil if disabled)
+	texPack *texturePack
+
 	backbuf [256 * 192 * 4]uint8
 	backY   int32
 
+	// Host time spent drawing the last scene (nanoseconds, atomic)
+	drawTime int64
+
+	// Per-line buffers used by polyfillers for shadow polygons: the
+	// stencil buffer, and the polygon ID of the opaque pixels
+	lineStencil [256]uint8
+	lineIds     [256]uint8
+
+	// Tolerance of the "equal" depth test, which depends on
+	// the depth buffer mode
+	depthEqualTol int32
+
 	framecnt int
 }
 
@@ -82,6 +121,7 @@ func NewHwEngine3d() *HwEngine3d {
 	}
 	e3d.next = e3d.pool.Get().(buffer3d)
 	e3d.nextCh = make(chan buffer3d, 1)
+	e3d.oneDotDepth = 0x7FFF
 
 	return e3d
 }
@@ -117,6 +157,12 @@ func (e3d *HwEngine3d) CmdViewport(cmd Primitive_SetViewport) {
 	e3d.viewport = cmd
 }
 
+// SetOneDotDepth sets the value of DISP_1DOT_DEPTH, for the polygons
+// received from now on
+func (e3d *HwEngine3d) SetOneDotDepth(val uint16) {
+	e3d.oneDotDepth = int32(val & 0x7FFF)
+}
+
 func (e3d *HwEngine3d) CmdVertex(cmd Primitive_Vertex) {
 	r, g, b := int32(cmd.C[0]), int32(cmd.C[1]), int32(cmd.C[2])
 
@@ -185,7 +231,15 @@ func (e3d *HwEngine3d) CmdPolygon(cmd Primitive_Polygon) {
 		e3d.vtxTransform(vtx)
 	}
 
+	// Distant geometry can be reduced to single dots, which are hidden
+	// beyond the 1-dot depth, unless the polygon asks to render them
+	if flags&PFRender1Dot == 0 && isOneDot(vtxs) && vtxs[0].cw.V>>9 > e3d.oneDotDepth {
+		return
+	}
+
 	// Split the clipped polygon into triangles
+	var tribuf [16]Polygon
+	tris := tribuf[:0]
 	for i := 1; i < len(vtxs)-1; i++ {
 		trivtxs := [3]*Vertex{vtxs[0], vtxs[i], vtxs[i+1]}
 
@@ -208,13 +262,64 @@ func (e3d *HwEngine3d) CmdPolygon(cmd Primitive_Polygon) {
 			}
 		}
 
-		poly := Polygon{
+		tris = append(tris, Polygon{
 			flags: flags,
 			tex:   cmd.Tex,
 			vtx:   trivtxs,
+		})
+	}
+	if len(tris) == 0 {
+		return
+	}
+
+	// Account for the polygon in the hardware polygon/vertex RAM. The whole
+	// polygon takes a single entry in polygon RAM. Vertices shared with
+	// previous polygons (eg: in strips) are stored only once, unless the
+	// polygon was clipped, in which case all its vertices are new.
+	nvtx := len(vtxs)
+	if clipany == 0 {
+		nvtx = 0
+		for _, vtx := range vtxs {
+			if vtx.flags&RVFStored == 0 {
+				nvtx++
+			}
+		}
+	}
+	if e3d.next.NumHwPolys+1 > cMaxPolygons || e3d.next.NumHwVtxs+nvtx > cMaxVertices {
+		// RAM is full: the polygon is dropped
+		e3d.Disp3dCnt.Value |= 1 << 13
+		return
+	}
+	e3d.next.NumHwPolys++
+	e3d.next.NumHwVtxs += nvtx
+	if clipany == 0 {
+		for _, vtx := range vtxs {
+			vtx.flags |= RVFStored
 		}
-		e3d.next.Pram = append(e3d.next.Pram, poly)
 	}
+
+	e3d.next.Pram = append(e3d.next.Pram, tris...)
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// isOneDot returns true if all the vertices of the polygon are on the same
+// screen pixel
+func isOneDot(vtxs []*Vertex) bool {
+	for _, v := range vtxs[1:] {
+		if v.x != vtxs[0].x || v.y != vtxs[0].y {
+			return false
+		}
+	}
+	return true
 }
 
 func (v0 *Vertex) Lerp(v1 *Vertex, ratio fixed.F12, vout *Vertex) {
@@ -330,8 +435,12 @@ func (e3d *HwEngine3d) vtxTransform(vtx *Vertex) {
 		return
 	}
 
-	viewwidth := fixed.NewF12(int32(e3d.viewport.VX1 - e3d.viewport.VX0))
-	viewheight := fixed.NewF12(int32(e3d.viewport.VY1 - e3d.viewport.VY0))
+	// The viewport coordinates are inclusive, and the Y axis goes upward,
+	// so (0,0) is the bottom-left pixel of the screen
+	vx0, vx1 := e3d.viewport.VX0, e3d.viewport.VX1
+	vy0, vy1 := 191-e3d.viewport.VY1, 191-e3d.viewport.VY0
+	viewwidth := fixed.NewF12(int32(vx1 - vx0 + 1))
+	viewheight := fixed.NewF12(int32(vy1 - vy0 + 1))
 
 	if vtx.cw.V == 0 {
 		vtx.cw.V = 1
@@ -347,14 +456,17 @@ func (e3d *HwEngine3d) vtxTransform(vtx *Vertex) {
 
 	// sx = (v.x + v.w) * viewwidth / (2*v.w) + viewx0
 	// sy = (v.y + v.w) * viewheight / (2*v.w) + viewy0
-	vtx.x = vtx.cx.AddFixed(vtx.cw).MulFixed(dx).Add(int32(e3d.viewport.VX0)).Round()
-	vtx.y = mirror.SubFixed(vtx.cy.AddFixed(vtx.cw)).MulFixed(dy).Add(int32(e3d.viewport.VY0)).Round()
+	vtx.x = vtx.cx.AddFixed(vtx.cw).MulFixed(dx).Add(int32(vx0)).Round()
+	vtx.y = mirror.SubFixed(vtx.cy.AddFixed(vtx.cw)).MulFixed(dy).Add(int32(vy0)).Round()
 
-	// Clamp screen coord. This is only required because clipping in clip-space
+	// Clamp screen coord. This is required because clipping in clip-space
 	// cannot be accurate with fixed point coordinates (at least not with 12 bit),
-	// and thus it can generate coordinates that are slightly out
-	vtx.x = vtx.x.Clamp(fixed.NewF12(int32(e3d.viewport.VX0)), fixed.NewF12(int32(e3d.viewport.VX1)))
-	vtx.y = vtx.y.Clamp(fixed.NewF12(int32(e3d.viewport.VY0)), fixed.NewF12(int32(e3d.viewport.VY1)))
+	// and thus it can generate coordinates that are slightly out; moreover,
+	// the right/bottom edges of the viewport are one pixel beyond it (because
+	// the coordinates are inclusive). Viewports larger than the screen are
+	// clipped to the screen as well.
+	vtx.x = vtx.x.Clamp(fixed.NewF12(int32(clampInt(vx0, 0, 255))), fixed.NewF12(int32(clampInt(vx1, 0, 255))))
+	vtx.y = vtx.y.Clamp(fixed.NewF12(int32(clampInt(vy0, 0, 191))), fixed.NewF12(int32(clampInt(vy1, 0, 191))))
 
 	vtx.flags |= RVFTransformed
 }
@@ -534,9 +646,17 @@ func (e3d *HwEngine3d) sortPolys(alphaYSort bool) {
 		// Polygons have the same translucent / solid status
 
 		// Second: sort by "y" solid polygons (or all polygons if
-		// alphaYSort is true)
+		// alphaYSort is true). The hardware sorts by the bottom-most
+		// Y coordinate first, and then by the top-most one.
 		if isolid || alphaYSort {
 			// vtx[0] is always the one with lowest y (after preparePolys())
+			ibot, jbot := pi.bottomY(), pj.bottomY()
+			if ibot < jbot {
+				return true
+			} else if jbot < ibot {
+				return false
+			}
+
 			iy := pi.vtx[0].y.V
 			jy := pj.vtx[0].y.V
 
@@ -639,6 +759,7 @@ func (e3d *HwEngine3d) CmdSwapBuffers(cmd Primitive_SwapBuffers) {
 
 	// Turn on wbuffering instead of zbuffering, if requested
 	e3d.polysSetDepth(cmd.WBuffering)
+	e3d.next.WBuffering = cmd.WBuffering
 
 	// Computer interpolators/slopes for all polygons
 	e3d.preparePolys()
@@ -666,10 +787,26 @@ func (e3d *HwEngine3d) CmdSwapBuffers(cmd Primitive_SwapBuffers) {
 }
 
 func (e3d *HwEngine3d) drawScene() {
+	t0 := time.Now()
+	defer func() { atomic.StoreInt64(&e3d.drawTime, int64(time.Since(t0))) }()
+
 	texMappingEnabled := e3d.Disp3dCnt.Value&(1<<0) != 0
 	highlightEnabled := e3d.Disp3dCnt.Value&(1<<1) != 0
 	alphaBlendingEnabled := e3d.Disp3dCnt.Value&(1<<3) != 0
 
+	// The "equal" depth test is not exact: it accepts a range of
+	// values around the one in the depth buffer, which is larger
+	// when using the Z buffer
+	e3d.depthEqualTol = 0x200
+	if e3d.cur.WBuffering {
+		e3d.depthEqualTol = 0xFF
+	}
+
+	// Substitute replaced textures before polyfillers are selected
+	if e3d.texPack != nil && texMappingEnabled {
+		e3d.texPack.Apply(e3d.cur.Pram, e3d)
+	}
+
 	// Initialize rasterizer.
 	var polyPerLine [192][]uint16
 	for idx := range e3d.cur.Pram {
@@ -773,31 +910,45 @@ func (e3d *HwEngine3d) drawScene() {
 	e3d.texCache.Update(e3d.cur.Pram, e3d)
 
 	for y := 0; y < 192; y++ {
-		if e3d.Disp3dCnt.Value&(1<<14) != 0 {
-			panic("bitmap")
-		}
-
 		line := gfx.NewLine(e3d.backbuf[4*256*y:])
 
 		clearColor := (e3d.ClearColor.Value & 0x7FFF) | 0x80000000
 		clearAlpha := uint8(e3d.ClearColor.Value>>16) & 0x1F
-		clearDepth := uint32(e3d.ClearDepth.Value)
+		clearDepth := uint32(e3d.ClearDepth.Value & 0x7FFF)
 		clearDepth = (clearDepth * 0x200) + 0x1FF // gbatek is wrong
 
+		clearId := uint8(e3d.ClearColor.Value>>24) & 0x3F
+
 		var abuf [256]byte
 		var zbuf [256 * 4]byte
 		zbuffer := gfx.NewLine(zbuf[:])
 		abuffer := gfx.NewLine(abuf[:])
+		if e3d.Disp3dCnt.Value&(1<<14) != 0 {
+			e3d.clearLineBitmap(y, line, zbuffer, abuffer)
+		} else {
+			for i := 0; i < 256; i++ {
+				line.Set32(i, clearColor)
+				abuffer.Set8(i, clearAlpha)
+				zbuffer.Set32(i, clearDepth)
+			}
+		}
 		for i := 0; i < 256; i++ {
-			line.Set32(i, clearColor)
-			abuffer.Set8(i, clearAlpha)
-			zbuffer.Set32(i, clearDepth)
+			e3d.lineIds[i] = clearId
 		}
 
 		// Draw polygons that are visibile in this line
+		prevMask := false
 		for _, idx := range polyPerLine[y] {
 			poly := &e3d.cur.Pram[idx]
 
+			// The stencil buffer is cleared at the beginning of each
+			// group of shadow mask polygons
+			mask := poly.IsShadowMask()
+			if mask && !prevMask {
+				e3d.lineStencil = [256]uint8{}
+			}
+			prevMask = mask
+
 			x0, x1 := poly.left[LerpX].Cur().NearInt32(), poly.right[LerpX].Cur().NearInt32()
 			if x0 < 0 || x1 >= 256 || x1 < x0 {
 				fmt.Printf("%v,%v\n", poly.vtx[0].x.TruncInt32(), poly.vtx[0].y.TruncInt32())
@@ -840,11 +991,44 @@ func (e3d *HwEngine3d) drawScene() {
 	}
 }
 
+// clearLineBitmap initializes the rear plane of a line from the clear image,
+// rather than from the clear color/depth registers. The clear image is made
+// of two 256x256 bitmaps, stored in texture slots 2 (color) and 3 (depth),
+// and can be scrolled through CLEAR_IMAGE_OFFSET.
+func (e3d *HwEngine3d) clearLineBitmap(y int, line, zbuffer, abuffer gfx.Line) {
+	const colorBase = 0x40000
+	const depthBase = 0x60000
+
+	ofs := e3d.ClearDepth.Value >> 16
+	by := (uint32(y) + ofs>>8) & 0xFF
+	for i := 0; i < 256; i++ {
+		bx := (uint32(i) + ofs) & 0xFF
+		off := (by*256 + bx) * 2
+
+		col, depth := uint16(0), uint16(0)
+		if e3d.texVram.Slots[(colorBase+off)>>14] != nil {
+			col = e3d.texVram.Get16(colorBase + off)
+		}
+		if e3d.texVram.Slots[(depthBase+off)>>14] != nil {
+			depth = e3d.texVram.Get16(depthBase + off)
+		}
+
+		// Bit 15 of the color is the alpha (either fully opaque or
+		// transparent), while bit 15 of depth is the fog flag (ignored
+		// because fog is not implemented).
+		line.Set32(i, uint32(col&0x7FFF)|0x80000000)
+		if col&0x8000 != 0 {
+			abuffer.Set8(i, 31)
+		} else {
+			abuffer.Set8(i, 0)
+		}
+		zbuffer.Set32(i, uint32(depth&0x7FFF)*0x200+0x1FF)
+	}
+}
+
 func (e3d *HwEngine3d) Draw3D(lidx int) func(gfx.Line) {
 	y := int32(0)
 
-	keystate := hw.GetKeyboardState()
-
 	return func(out gfx.Line) {
 		xofs := int(*e3d.bg0xofs & 511)
 		pri := uint32(*e3d.bg0cnt&3) << 29
@@ -854,13 +1038,10 @@ func (e3d *HwEngine3d) Draw3D(lidx int) func(gfx.Line) {
 			time.Sleep(10 * time.Microsecond)
 		}
 
-		// Check if layer 0 is enabled, otherwise ignore
-		if *e3d.dispcnt&(1<<8) == 0 {
-			y++
-			return
-		}
-
-		if keystate[hw.SCANCODE_5] != 0 {
+		// Check if layer 0 is enabled, otherwise ignore. When the 3D layer
+		// is not mapped to BG0 (lidx != 0), it's only used as a source for
+		// display capture, so it must always be drawn.
+		if lidx == 0 && *e3d.dispcnt&(1<<8) == 0 {
 			y++
 			return
 		}
@@ -889,6 +1070,12 @@ func (e3d *HwEngine3d) SetVram(tex VramTextureBank, pal VramTexturePaletteBank)
 	e3d.palVram = pal
 }
 
+// DrawTime returns the host time spent rasterizing the last 3D frame. The
+// rasterizer runs in background, in parallel with emulation.
+func (e3d *HwEngine3d) DrawTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&e3d.drawTime))
+}
+
 func (e3d *HwEngine3d) BeginFrame() {
 	e3d.backY = -1
 	go e3d.drawScene()
@@ -912,10 +1099,20 @@ func (e3d *HwEngine3d) EndFrame() {
 	}
 }
 
+// NumVertices returns the number of vertices stored in the hardware vertex
+// RAM for the frame being currently submitted
 func (e3d *HwEngine3d) NumVertices() int {
-	return len(e3d.cur.Vram)
+	return e3d.next.NumHwVtxs
 }
 
+// NumPolygons returns the number of polygons stored in the hardware polygon
+// RAM for the frame being currently submitted
 func (e3d *HwEngine3d) NumPolygons() int {
-	return len(e3d.cur.Pram)
+	return e3d.next.NumHwPolys
+}
+
+func (e3d *HwEngine3d) WriteDISP3DCNT(old, val uint32) {
+	// Bits 12-13 are error flags (color buffer underflow, and
+	// polygon/vertex RAM overflow): writing 1 acknowledges them
+	e3d.Disp3dCnt.Value = val&^0x3000 | old&0x3000&^val
 }
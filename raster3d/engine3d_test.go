@@ -0,0 +1,156 @@
+package raster3d
+
+import (
+	"image"
+	"ndsemu/emu/fixed"
+	"testing"
+)
+
+func TestViewportTransform(t *testing.T) {
+	e3d := NewHwEngine3d()
+	f := func(v float64) fixed.F12 { return fixed.F12{V: int32(v * 4096)} }
+
+	for _, tc := range []struct {
+		vp     Primitive_SetViewport
+		cx, cy float64
+		x, y   int32
+	}{
+		// Full screen: (-1,-1) is the bottom-left pixel
+		{Primitive_SetViewport{0, 0, 255, 191}, -1, -1, 0, 191},
+		{Primitive_SetViewport{0, 0, 255, 191}, 0, 0, 128, 96},
+		{Primitive_SetViewport{0, 0, 255, 191}, 1, 1, 255, 0},
+		{Primitive_SetViewport{0, 0, 255, 191}, 0.5, -0.5, 192, 144},
+		// Bottom-left quarter of the screen
+		{Primitive_SetViewport{0, 0, 127, 95}, -1, -1, 0, 191},
+		{Primitive_SetViewport{0, 0, 127, 95}, 0, 0, 64, 144},
+		{Primitive_SetViewport{0, 0, 127, 95}, 1, 1, 127, 96},
+		// Top-right quarter of the screen
+		{Primitive_SetViewport{128, 96, 255, 191}, -1, -1, 128, 95},
+		{Primitive_SetViewport{128, 96, 255, 191}, 1, 1, 255, 0},
+		// Larger than the screen: clamped to it
+		{Primitive_SetViewport{0, 0, 511, 383}, 1, 1, 255, 0},
+	} {
+		e3d.CmdViewport(tc.vp)
+		vtx := Vertex{cx: f(tc.cx), cy: f(tc.cy), cw: f(1)}
+		e3d.vtxTransform(&vtx)
+		if x, y := vtx.x.TruncInt32(), vtx.y.TruncInt32(); x != tc.x || y != tc.y {
+			t.Errorf("viewport %v, (%v,%v): got (%d,%d), want (%d,%d)", tc.vp, tc.cx, tc.cy, x, y, tc.x, tc.y)
+		}
+	}
+}
+
+func TestOneDotDepth(t *testing.T) {
+	for _, tc := range []struct {
+		depth   uint16
+		w       int32 // 20.12
+		flags   PolygonFlags
+		visible bool
+	}{
+		{0x7FFF, 0x10000, 0, true},
+		{8, 0x1000, 0, true}, // W=1.0 is 8 in 12.3
+		{7, 0x1000, 0, false},
+		{7, 0x1000, PFRender1Dot, true},
+		{7, 0x0FFF, 0, true},
+	} {
+		e3d := NewHwEngine3d()
+		e3d.CmdViewport(Primitive_SetViewport{0, 0, 255, 191})
+		e3d.SetOneDotDepth(tc.depth)
+		for i := 0; i < 3; i++ {
+			e3d.CmdVertex(Primitive_Vertex{W: fixed.F12{V: tc.w}})
+		}
+		e3d.CmdPolygon(Primitive_Polygon{
+			Vtx:  [4]int{0, 1, 2},
+			Attr: uint32(tc.flags | PFRenderBack | PFRenderFront),
+		})
+		if visible := e3d.NumPolygons() == 1; visible != tc.visible {
+			t.Errorf("depth=%x w=%x flags=%x: visible=%v", tc.depth, tc.w, tc.flags, visible)
+		}
+	}
+}
+
+func TestPolyRamCount(t *testing.T) {
+	f := func(v float64) fixed.F12 { return fixed.F12{V: int32(v * 4096)} }
+	tri := func(e3d *HwEngine3d, base int) {
+		e3d.CmdPolygon(Primitive_Polygon{
+			Vtx:  [4]int{base, base + 1, base + 2},
+			Attr: uint32(PFRenderBack | PFRenderFront),
+		})
+	}
+
+	for _, tc := range []struct {
+		name       string
+		x          [4]float64 // X of the vertices (the 4th one is for strips)
+		strip      bool
+		polys, vtx int
+	}{
+		{"inside", [4]float64{-0.5, 0.5, 0}, false, 1, 3},
+		{"strip", [4]float64{-0.5, 0.5, 0, 0.8}, true, 2, 4},
+		{"clipped", [4]float64{-0.5, 1.5, 0}, false, 1, 4},
+		{"outside", [4]float64{1.5, 2.5, 2}, false, 0, 0},
+	} {
+		e3d := NewHwEngine3d()
+		e3d.CmdViewport(Primitive_SetViewport{0, 0, 255, 191})
+		ys := [4]float64{-0.5, -0.5, 0.5, 0.5}
+		for i := range tc.x {
+			e3d.CmdVertex(Primitive_Vertex{X: f(tc.x[i]), Y: f(ys[i]), W: f(1)})
+		}
+		tri(e3d, 0)
+		if tc.strip {
+			tri(e3d, 1)
+		}
+		if p, v := e3d.NumPolygons(), e3d.NumVertices(); p != tc.polys || v != tc.vtx {
+			t.Errorf("%s: %d polygons, %d vertices; want %d, %d", tc.name, p, v, tc.polys, tc.vtx)
+		}
+	}
+}
+
+func TestTexPackScale(t *testing.T) {
+	// Replacements are used at the largest power-of-two multiple of the
+	// original size that they can fill, up to 8x
+	for _, tc := range []struct {
+		repl, orig int
+		shift      uint
+	}{
+		{8, 8, 0}, {4, 8, 0}, {16, 8, 1}, {24, 8, 1}, {32, 8, 2}, {1024, 8, 3},
+	} {
+		if got := replScale(tc.repl, tc.orig); got != tc.shift {
+			t.Errorf("replScale(%d, %d) = %d, want %d", tc.repl, tc.orig, got, tc.shift)
+		}
+	}
+
+	// A 8x16 texture (S repeat and flip, T clamp) replaced by a 32x64 image
+	tp := newTexturePack()
+	tp.repl[1] = image.NewNRGBA(image.Rect(0, 0, 32, 64))
+	poly := Polygon{tex: Texture{
+		Width: 8, Height: 16, PitchShift: 3,
+		SFlipMask: 8, TClampMask: ^uint32(15),
+	}}
+	poly.left[LerpS] = newLerp(fixed.F32{V: 3 << 32}, fixed.F32{V: 1 << 30}, fixed.F32{V: -1 << 30})
+	poly.right[LerpT] = newLerp(fixed.F32{V: 5 << 32}, fixed.F32{V: 0}, fixed.F32{V: 0})
+
+	r, ok := tp.replacement(1, 8, 16)
+	if !ok || r.sshift != 2 || r.tshift != 2 {
+		t.Fatalf("replacement: got %+v, %v", r, ok)
+	}
+	if n := len(tp.Buffer(texReplacedFlag | r.buf)); n != 32*64*2 {
+		t.Errorf("replacement buffer size: %d", n)
+	}
+	scaleTexture(&poly, r.sshift, r.tshift)
+
+	want := Texture{
+		Width: 32, Height: 64, PitchShift: 5,
+		SFlipMask: 32, TClampMask: ^uint32(63),
+	}
+	if poly.tex != want {
+		t.Errorf("texture: got %+v, want %+v", poly.tex, want)
+	}
+	poly.left[LerpS].Reset()
+	poly.right[LerpT].Reset()
+	poly.left[LerpS].Next(1)
+	if s := poly.left[LerpS].Cur(); s.V != 11<<32 {
+		t.Errorf("S after one step: got %v, want 11", s)
+	}
+	if tt := poly.right[LerpT].Cur().TruncInt32(); tt != 20 {
+		t.Errorf("T: got %d, want 20", tt)
+	}
+}
@@ -68,7 +68,10 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	if cfg.FillMode == fillerconfig.FillModeAlpha {
 		fmt.Fprintf(g, "polyalpha := uint8(poly.flags.Alpha())<<1\n")
 	}
-	fmt.Fprintf(g, "zalpha := e3d.Disp3dCnt.Value & (1<<11) != 0\n")
+	fmt.Fprintf(g, "zalpha := poly.flags&(1<<11) != 0\n")
+	fmt.Fprintf(g, "zequal := poly.flags&(1<<14) != 0\n")
+	fmt.Fprintf(g, "ztol := e3d.depthEqualTol\n")
+	fmt.Fprintf(g, "polyid := poly.flags.ID()\n")
 
 	// Pre pixel loop
 	switch cfg.TexFormat {
@@ -112,7 +115,24 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	const zshift = 32 - 12
 	fmt.Fprintf(g, "// zbuffer check\n")
 	fmt.Fprintf(g, "z := d0.Inv()\n")
-	fmt.Fprintf(g, "if int32(z.V>>%d) >= int32(zbuf.Get32(0)) { goto next }\n", zshift)
+	if cfg.ColorMode == fillerconfig.ColorModeShadow {
+		// Shadow polygons with ID 0 are masks: they're not drawn, but
+		// set the stencil buffer where they fail the depth test. Other
+		// shadow polygons are drawn only where the stencil is set, and
+		// not over opaque pixels of the same polygon ID (the object
+		// casting the shadow).
+		fmt.Fprintf(g, "if polyid == 0 {\n")
+		fmt.Fprintf(g, "if int32(z.V>>%d) >= int32(zbuf.Get32(0)) { e3d.lineStencil[x] = 1 }\n", zshift)
+		fmt.Fprintf(g, "goto next\n")
+		fmt.Fprintf(g, "}\n")
+		fmt.Fprintf(g, "if int32(z.V>>%d) >= int32(zbuf.Get32(0)) { goto next }\n", zshift)
+		fmt.Fprintf(g, "if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid { goto next }\n")
+	} else {
+		// Depth test can either be "less" or "equal" (with tolerance)
+		fmt.Fprintf(g, "if zequal {\n")
+		fmt.Fprintf(g, "if dz := int32(z.V>>%d) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol { goto next }\n", zshift)
+		fmt.Fprintf(g, "} else if int32(z.V>>%d) >= int32(zbuf.Get32(0)) { goto next }\n", zshift)
+	}
 
 	if cfg.TexFormat > 0 {
 		// texture coords
@@ -268,7 +288,11 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 		fmt.Fprintf(g, "if bkga != 0 { px = rgbAlphaMix(px, bkg, pxa) }\n")
 		fmt.Fprintf(g, "if pxa < bkga { pxa = bkga }\n")
 		fmt.Fprintf(g, "drawz = zalpha\n")
+		fmt.Fprintf(g, "} else {\n")
+		fmt.Fprintf(g, "e3d.lineIds[x] = polyid\n")
 		fmt.Fprintf(g, "}\n")
+	} else {
+		fmt.Fprintf(g, "e3d.lineIds[x] = polyid\n")
 	}
 
 	// draw pixel
@@ -294,6 +318,8 @@ func (g *Generator) genFiller(cfg *fillerconfig.FillerConfig) {
 	fmt.Fprintf(g, "}\n")
 	fmt.Fprintf(g, "_=px0\n")
 	fmt.Fprintf(g, "_=zalpha\n")
+	fmt.Fprintf(g, "_=zequal\n")
+	fmt.Fprintf(g, "_=ztol\n")
 }
 
 func (g *Generator) Run() {
@@ -52,6 +52,14 @@ func (l *lerp) Next(didx int) {
 	l.cur = l.cur + l.delta[didx]
 }
 
+// Shl multiplies all the values of the interpolator by 1<<shift
+func (l *lerp) Shl(shift uint) {
+	l.cur <<= shift
+	l.start <<= shift
+	l.delta[0] <<= shift
+	l.delta[1] <<= shift
+}
+
 func (l lerp) String() string {
 	return fmt.Sprintf("lerp(%v (%v,%v) [%v])",
 		fixed.F32{V: l.cur}, fixed.F32{V: l.delta[0]}, fixed.F32{V: l.delta[1]}, fixed.F32{V: l.start})
@@ -1,4 +1,4 @@
-// Generated on 2017-05-28 00:53:35.604936721 +0200 CEST
+// Generated on 2026-10-16 12:26:00.394852896 +0000 UTC m=+0.000940440
 package raster3d
 
 import "ndsemu/emu/gfx"
@@ -22,7 +22,10 @@ func (e3d *HwEngine3d) filler_000(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	dg := g1.SubFixed(g0).Div(nx)
 	b0, b1 := poly.left[LerpB].Cur(), poly.right[LerpB].Cur()
 	db := b1.SubFixed(b0).Div(nx)
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	out.Add32(int(x0))
@@ -34,7 +37,11 @@ func (e3d *HwEngine3d) filler_000(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		px = uint16(r0.TruncInt32()>>1) | uint16(g0.TruncInt32()>>1)<<5 | uint16(b0.TruncInt32()>>1)<<10
@@ -43,6 +50,7 @@ func (e3d *HwEngine3d) filler_000(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -60,6 +68,8 @@ func (e3d *HwEngine3d) filler_000(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_001 skipped, because of identical polyfiller:
@@ -97,7 +107,10 @@ func (e3d *HwEngine3d) filler_003(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -111,7 +124,11 @@ func (e3d *HwEngine3d) filler_003(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -143,6 +160,7 @@ func (e3d *HwEngine3d) filler_003(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -162,6 +180,8 @@ func (e3d *HwEngine3d) filler_003(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_004(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -190,7 +210,10 @@ func (e3d *HwEngine3d) filler_004(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -203,7 +226,11 @@ func (e3d *HwEngine3d) filler_004(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -231,6 +258,7 @@ func (e3d *HwEngine3d) filler_004(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -250,6 +278,8 @@ func (e3d *HwEngine3d) filler_004(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_005(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -277,7 +307,10 @@ func (e3d *HwEngine3d) filler_005(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -290,7 +323,11 @@ func (e3d *HwEngine3d) filler_005(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -316,6 +353,7 @@ func (e3d *HwEngine3d) filler_005(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -335,6 +373,8 @@ func (e3d *HwEngine3d) filler_005(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_006(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -364,7 +404,10 @@ func (e3d *HwEngine3d) filler_006(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -379,7 +422,11 @@ func (e3d *HwEngine3d) filler_006(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -410,6 +457,7 @@ func (e3d *HwEngine3d) filler_006(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -429,6 +477,8 @@ func (e3d *HwEngine3d) filler_006(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_007(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -457,7 +507,10 @@ func (e3d *HwEngine3d) filler_007(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -471,7 +524,11 @@ func (e3d *HwEngine3d) filler_007(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -498,6 +555,7 @@ func (e3d *HwEngine3d) filler_007(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -517,6 +575,8 @@ func (e3d *HwEngine3d) filler_007(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_008(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -544,7 +604,10 @@ func (e3d *HwEngine3d) filler_008(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -558,7 +621,11 @@ func (e3d *HwEngine3d) filler_008(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -583,6 +650,7 @@ func (e3d *HwEngine3d) filler_008(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -602,6 +670,8 @@ func (e3d *HwEngine3d) filler_008(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_009(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -631,7 +701,10 @@ func (e3d *HwEngine3d) filler_009(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -646,7 +719,11 @@ func (e3d *HwEngine3d) filler_009(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -677,6 +754,7 @@ func (e3d *HwEngine3d) filler_009(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -696,6 +774,8 @@ func (e3d *HwEngine3d) filler_009(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_00a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -724,7 +804,10 @@ func (e3d *HwEngine3d) filler_00a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -738,7 +821,11 @@ func (e3d *HwEngine3d) filler_00a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -765,6 +852,7 @@ func (e3d *HwEngine3d) filler_00a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -784,6 +872,8 @@ func (e3d *HwEngine3d) filler_00a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_00b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -811,7 +901,10 @@ func (e3d *HwEngine3d) filler_00b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -825,7 +918,11 @@ func (e3d *HwEngine3d) filler_00b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -850,6 +947,7 @@ func (e3d *HwEngine3d) filler_00b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -869,6 +967,8 @@ func (e3d *HwEngine3d) filler_00b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_00c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -898,7 +998,10 @@ func (e3d *HwEngine3d) filler_00c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -912,7 +1015,11 @@ func (e3d *HwEngine3d) filler_00c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -941,6 +1048,7 @@ func (e3d *HwEngine3d) filler_00c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -960,6 +1068,8 @@ func (e3d *HwEngine3d) filler_00c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_00d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -988,7 +1098,10 @@ func (e3d *HwEngine3d) filler_00d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -1001,7 +1114,11 @@ func (e3d *HwEngine3d) filler_00d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1026,6 +1143,7 @@ func (e3d *HwEngine3d) filler_00d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1045,6 +1163,8 @@ func (e3d *HwEngine3d) filler_00d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_00e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1072,7 +1192,10 @@ func (e3d *HwEngine3d) filler_00e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -1085,7 +1208,11 @@ func (e3d *HwEngine3d) filler_00e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1108,6 +1235,7 @@ func (e3d *HwEngine3d) filler_00e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1127,6 +1255,8 @@ func (e3d *HwEngine3d) filler_00e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_00f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1155,7 +1285,10 @@ func (e3d *HwEngine3d) filler_00f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -1171,7 +1304,11 @@ func (e3d *HwEngine3d) filler_00f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1203,6 +1340,7 @@ func (e3d *HwEngine3d) filler_00f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1222,6 +1360,8 @@ func (e3d *HwEngine3d) filler_00f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_010(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1249,7 +1389,10 @@ func (e3d *HwEngine3d) filler_010(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -1264,7 +1407,11 @@ func (e3d *HwEngine3d) filler_010(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1292,6 +1439,7 @@ func (e3d *HwEngine3d) filler_010(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1311,6 +1459,8 @@ func (e3d *HwEngine3d) filler_010(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_011(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1337,7 +1487,10 @@ func (e3d *HwEngine3d) filler_011(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -1352,7 +1505,11 @@ func (e3d *HwEngine3d) filler_011(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1378,6 +1535,7 @@ func (e3d *HwEngine3d) filler_011(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1397,6 +1555,8 @@ func (e3d *HwEngine3d) filler_011(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_012(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1426,7 +1586,10 @@ func (e3d *HwEngine3d) filler_012(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -1440,7 +1603,11 @@ func (e3d *HwEngine3d) filler_012(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1472,6 +1639,7 @@ func (e3d *HwEngine3d) filler_012(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1491,6 +1659,8 @@ func (e3d *HwEngine3d) filler_012(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_013(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1519,7 +1689,10 @@ func (e3d *HwEngine3d) filler_013(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -1532,7 +1705,11 @@ func (e3d *HwEngine3d) filler_013(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1560,6 +1737,7 @@ func (e3d *HwEngine3d) filler_013(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1579,6 +1757,8 @@ func (e3d *HwEngine3d) filler_013(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_014(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1606,7 +1786,10 @@ func (e3d *HwEngine3d) filler_014(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -1619,7 +1802,11 @@ func (e3d *HwEngine3d) filler_014(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1645,6 +1832,7 @@ func (e3d *HwEngine3d) filler_014(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1664,6 +1852,8 @@ func (e3d *HwEngine3d) filler_014(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_015(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1692,7 +1882,10 @@ func (e3d *HwEngine3d) filler_015(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -1707,7 +1900,11 @@ func (e3d *HwEngine3d) filler_015(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1739,6 +1936,7 @@ func (e3d *HwEngine3d) filler_015(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1758,6 +1956,8 @@ func (e3d *HwEngine3d) filler_015(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_016(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1785,7 +1985,10 @@ func (e3d *HwEngine3d) filler_016(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -1799,7 +2002,11 @@ func (e3d *HwEngine3d) filler_016(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1827,6 +2034,7 @@ func (e3d *HwEngine3d) filler_016(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1846,6 +2054,8 @@ func (e3d *HwEngine3d) filler_016(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_017(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -1872,7 +2082,10 @@ func (e3d *HwEngine3d) filler_017(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -1886,7 +2099,11 @@ func (e3d *HwEngine3d) filler_017(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -1912,6 +2129,7 @@ func (e3d *HwEngine3d) filler_017(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -1931,6 +2149,8 @@ func (e3d *HwEngine3d) filler_017(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_018 skipped, because of identical polyfiller:
@@ -1984,7 +2204,10 @@ func (e3d *HwEngine3d) filler_01e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -1999,7 +2222,11 @@ func (e3d *HwEngine3d) filler_01e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2034,6 +2261,7 @@ func (e3d *HwEngine3d) filler_01e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2053,6 +2281,8 @@ func (e3d *HwEngine3d) filler_01e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_01f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2081,7 +2311,10 @@ func (e3d *HwEngine3d) filler_01f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -2095,7 +2328,11 @@ func (e3d *HwEngine3d) filler_01f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2126,6 +2363,7 @@ func (e3d *HwEngine3d) filler_01f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2145,6 +2383,8 @@ func (e3d *HwEngine3d) filler_01f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_020(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2172,7 +2412,10 @@ func (e3d *HwEngine3d) filler_020(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -2186,7 +2429,11 @@ func (e3d *HwEngine3d) filler_020(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2215,6 +2462,7 @@ func (e3d *HwEngine3d) filler_020(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2234,6 +2482,8 @@ func (e3d *HwEngine3d) filler_020(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_021(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2263,7 +2513,10 @@ func (e3d *HwEngine3d) filler_021(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -2278,7 +2531,11 @@ func (e3d *HwEngine3d) filler_021(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2313,6 +2570,7 @@ func (e3d *HwEngine3d) filler_021(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2332,6 +2590,8 @@ func (e3d *HwEngine3d) filler_021(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_022(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2360,7 +2620,10 @@ func (e3d *HwEngine3d) filler_022(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -2374,7 +2637,11 @@ func (e3d *HwEngine3d) filler_022(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2405,6 +2672,7 @@ func (e3d *HwEngine3d) filler_022(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2424,6 +2692,8 @@ func (e3d *HwEngine3d) filler_022(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_023(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2451,7 +2721,10 @@ func (e3d *HwEngine3d) filler_023(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -2465,7 +2738,11 @@ func (e3d *HwEngine3d) filler_023(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2494,6 +2771,7 @@ func (e3d *HwEngine3d) filler_023(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2513,6 +2791,8 @@ func (e3d *HwEngine3d) filler_023(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_024(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2542,7 +2822,10 @@ func (e3d *HwEngine3d) filler_024(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -2556,7 +2839,11 @@ func (e3d *HwEngine3d) filler_024(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2588,6 +2875,7 @@ func (e3d *HwEngine3d) filler_024(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2607,6 +2895,8 @@ func (e3d *HwEngine3d) filler_024(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_025(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2635,7 +2925,10 @@ func (e3d *HwEngine3d) filler_025(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -2648,7 +2941,11 @@ func (e3d *HwEngine3d) filler_025(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2676,6 +2973,7 @@ func (e3d *HwEngine3d) filler_025(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2695,6 +2993,8 @@ func (e3d *HwEngine3d) filler_025(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_026(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -2722,7 +3022,10 @@ func (e3d *HwEngine3d) filler_026(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -2735,7 +3038,11 @@ func (e3d *HwEngine3d) filler_026(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2761,6 +3068,7 @@ func (e3d *HwEngine3d) filler_026(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -2780,6 +3088,8 @@ func (e3d *HwEngine3d) filler_026(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_027 skipped, because of identical polyfiller:
@@ -2837,7 +3147,10 @@ func (e3d *HwEngine3d) filler_030(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	b0, b1 := poly.left[LerpB].Cur(), poly.right[LerpB].Cur()
 	db := b1.SubFixed(b0).Div(nx)
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	out.Add32(int(x0))
@@ -2849,7 +3162,11 @@ func (e3d *HwEngine3d) filler_030(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		px = uint16(r0.TruncInt32()>>1) | uint16(g0.TruncInt32()>>1)<<5 | uint16(b0.TruncInt32()>>1)<<10
@@ -2869,6 +3186,8 @@ func (e3d *HwEngine3d) filler_030(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -2887,6 +3206,8 @@ func (e3d *HwEngine3d) filler_030(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_031 skipped, because of identical polyfiller:
@@ -2925,7 +3246,10 @@ func (e3d *HwEngine3d) filler_033(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -2939,7 +3263,11 @@ func (e3d *HwEngine3d) filler_033(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -2982,6 +3310,8 @@ func (e3d *HwEngine3d) filler_033(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3002,6 +3332,8 @@ func (e3d *HwEngine3d) filler_033(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_034(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3031,7 +3363,10 @@ func (e3d *HwEngine3d) filler_034(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -3044,7 +3379,11 @@ func (e3d *HwEngine3d) filler_034(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3083,6 +3422,8 @@ func (e3d *HwEngine3d) filler_034(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3103,6 +3444,8 @@ func (e3d *HwEngine3d) filler_034(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_035(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3131,7 +3474,10 @@ func (e3d *HwEngine3d) filler_035(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -3144,7 +3490,11 @@ func (e3d *HwEngine3d) filler_035(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3181,6 +3531,8 @@ func (e3d *HwEngine3d) filler_035(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3201,6 +3553,8 @@ func (e3d *HwEngine3d) filler_035(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_036(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3231,7 +3585,10 @@ func (e3d *HwEngine3d) filler_036(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -3246,7 +3603,11 @@ func (e3d *HwEngine3d) filler_036(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3288,6 +3649,8 @@ func (e3d *HwEngine3d) filler_036(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3308,6 +3671,8 @@ func (e3d *HwEngine3d) filler_036(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_037(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3337,7 +3702,10 @@ func (e3d *HwEngine3d) filler_037(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -3351,7 +3719,11 @@ func (e3d *HwEngine3d) filler_037(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3389,6 +3761,8 @@ func (e3d *HwEngine3d) filler_037(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3409,6 +3783,8 @@ func (e3d *HwEngine3d) filler_037(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_038(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3437,7 +3813,10 @@ func (e3d *HwEngine3d) filler_038(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -3451,7 +3830,11 @@ func (e3d *HwEngine3d) filler_038(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3487,6 +3870,8 @@ func (e3d *HwEngine3d) filler_038(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3507,6 +3892,8 @@ func (e3d *HwEngine3d) filler_038(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_039(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3537,7 +3924,10 @@ func (e3d *HwEngine3d) filler_039(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -3552,7 +3942,11 @@ func (e3d *HwEngine3d) filler_039(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3594,6 +3988,8 @@ func (e3d *HwEngine3d) filler_039(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3614,6 +4010,8 @@ func (e3d *HwEngine3d) filler_039(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_03a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3643,7 +4041,10 @@ func (e3d *HwEngine3d) filler_03a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -3657,7 +4058,11 @@ func (e3d *HwEngine3d) filler_03a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3695,6 +4100,8 @@ func (e3d *HwEngine3d) filler_03a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3715,6 +4122,8 @@ func (e3d *HwEngine3d) filler_03a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_03b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3743,7 +4152,10 @@ func (e3d *HwEngine3d) filler_03b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -3757,7 +4169,11 @@ func (e3d *HwEngine3d) filler_03b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3793,6 +4209,8 @@ func (e3d *HwEngine3d) filler_03b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3813,6 +4231,8 @@ func (e3d *HwEngine3d) filler_03b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_03c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3843,7 +4263,10 @@ func (e3d *HwEngine3d) filler_03c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -3857,7 +4280,11 @@ func (e3d *HwEngine3d) filler_03c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3897,6 +4324,8 @@ func (e3d *HwEngine3d) filler_03c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -3917,6 +4346,8 @@ func (e3d *HwEngine3d) filler_03c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_03d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -3946,7 +4377,10 @@ func (e3d *HwEngine3d) filler_03d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -3959,7 +4393,11 @@ func (e3d *HwEngine3d) filler_03d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -3995,6 +4433,8 @@ func (e3d *HwEngine3d) filler_03d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4015,6 +4455,8 @@ func (e3d *HwEngine3d) filler_03d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_03e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4043,7 +4485,10 @@ func (e3d *HwEngine3d) filler_03e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -4056,7 +4501,11 @@ func (e3d *HwEngine3d) filler_03e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4090,6 +4539,8 @@ func (e3d *HwEngine3d) filler_03e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4110,6 +4561,8 @@ func (e3d *HwEngine3d) filler_03e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_03f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4139,7 +4592,10 @@ func (e3d *HwEngine3d) filler_03f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -4155,7 +4611,11 @@ func (e3d *HwEngine3d) filler_03f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4198,6 +4658,8 @@ func (e3d *HwEngine3d) filler_03f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4218,6 +4680,8 @@ func (e3d *HwEngine3d) filler_03f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_040(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4246,7 +4710,10 @@ func (e3d *HwEngine3d) filler_040(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -4261,7 +4728,11 @@ func (e3d *HwEngine3d) filler_040(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4300,6 +4771,8 @@ func (e3d *HwEngine3d) filler_040(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4320,6 +4793,8 @@ func (e3d *HwEngine3d) filler_040(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_041(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4347,7 +4822,10 @@ func (e3d *HwEngine3d) filler_041(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -4362,7 +4840,11 @@ func (e3d *HwEngine3d) filler_041(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4399,6 +4881,8 @@ func (e3d *HwEngine3d) filler_041(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4419,6 +4903,8 @@ func (e3d *HwEngine3d) filler_041(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_042(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4449,7 +4935,10 @@ func (e3d *HwEngine3d) filler_042(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -4463,7 +4952,11 @@ func (e3d *HwEngine3d) filler_042(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4506,6 +4999,8 @@ func (e3d *HwEngine3d) filler_042(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4526,6 +5021,8 @@ func (e3d *HwEngine3d) filler_042(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_043(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4555,7 +5052,10 @@ func (e3d *HwEngine3d) filler_043(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -4568,7 +5068,11 @@ func (e3d *HwEngine3d) filler_043(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4607,6 +5111,8 @@ func (e3d *HwEngine3d) filler_043(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4627,6 +5133,8 @@ func (e3d *HwEngine3d) filler_043(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_044(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4655,7 +5163,10 @@ func (e3d *HwEngine3d) filler_044(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -4668,7 +5179,11 @@ func (e3d *HwEngine3d) filler_044(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4705,6 +5220,8 @@ func (e3d *HwEngine3d) filler_044(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4725,6 +5242,8 @@ func (e3d *HwEngine3d) filler_044(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_045(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4754,7 +5273,10 @@ func (e3d *HwEngine3d) filler_045(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -4769,7 +5291,11 @@ func (e3d *HwEngine3d) filler_045(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4812,6 +5338,8 @@ func (e3d *HwEngine3d) filler_045(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4832,6 +5360,8 @@ func (e3d *HwEngine3d) filler_045(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_046(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4860,7 +5390,10 @@ func (e3d *HwEngine3d) filler_046(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -4874,7 +5407,11 @@ func (e3d *HwEngine3d) filler_046(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -4913,6 +5450,8 @@ func (e3d *HwEngine3d) filler_046(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -4933,6 +5472,8 @@ func (e3d *HwEngine3d) filler_046(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_047(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -4960,7 +5501,10 @@ func (e3d *HwEngine3d) filler_047(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -4974,7 +5518,11 @@ func (e3d *HwEngine3d) filler_047(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5011,6 +5559,8 @@ func (e3d *HwEngine3d) filler_047(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5031,6 +5581,8 @@ func (e3d *HwEngine3d) filler_047(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_048 skipped, because of identical polyfiller:
@@ -5085,7 +5637,10 @@ func (e3d *HwEngine3d) filler_04e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -5100,7 +5655,11 @@ func (e3d *HwEngine3d) filler_04e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5146,6 +5705,8 @@ func (e3d *HwEngine3d) filler_04e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5166,6 +5727,8 @@ func (e3d *HwEngine3d) filler_04e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_04f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5195,7 +5758,10 @@ func (e3d *HwEngine3d) filler_04f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -5209,7 +5775,11 @@ func (e3d *HwEngine3d) filler_04f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5251,6 +5821,8 @@ func (e3d *HwEngine3d) filler_04f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5271,6 +5843,8 @@ func (e3d *HwEngine3d) filler_04f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_050(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5299,7 +5873,10 @@ func (e3d *HwEngine3d) filler_050(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -5313,7 +5890,11 @@ func (e3d *HwEngine3d) filler_050(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5353,6 +5934,8 @@ func (e3d *HwEngine3d) filler_050(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5373,6 +5956,8 @@ func (e3d *HwEngine3d) filler_050(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_051(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5403,7 +5988,10 @@ func (e3d *HwEngine3d) filler_051(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -5418,7 +6006,11 @@ func (e3d *HwEngine3d) filler_051(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5464,6 +6056,8 @@ func (e3d *HwEngine3d) filler_051(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5484,6 +6078,8 @@ func (e3d *HwEngine3d) filler_051(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_052(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5513,7 +6109,10 @@ func (e3d *HwEngine3d) filler_052(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -5527,7 +6126,11 @@ func (e3d *HwEngine3d) filler_052(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5569,6 +6172,8 @@ func (e3d *HwEngine3d) filler_052(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5589,6 +6194,8 @@ func (e3d *HwEngine3d) filler_052(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_053(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5617,7 +6224,10 @@ func (e3d *HwEngine3d) filler_053(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -5631,7 +6241,11 @@ func (e3d *HwEngine3d) filler_053(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5671,6 +6285,8 @@ func (e3d *HwEngine3d) filler_053(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5691,6 +6307,8 @@ func (e3d *HwEngine3d) filler_053(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_054(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5721,7 +6339,10 @@ func (e3d *HwEngine3d) filler_054(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -5735,7 +6356,11 @@ func (e3d *HwEngine3d) filler_054(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5778,6 +6403,8 @@ func (e3d *HwEngine3d) filler_054(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5798,6 +6425,8 @@ func (e3d *HwEngine3d) filler_054(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_055(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5827,7 +6456,10 @@ func (e3d *HwEngine3d) filler_055(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -5840,7 +6472,11 @@ func (e3d *HwEngine3d) filler_055(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5879,6 +6515,8 @@ func (e3d *HwEngine3d) filler_055(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5899,6 +6537,8 @@ func (e3d *HwEngine3d) filler_055(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_056(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -5927,7 +6567,10 @@ func (e3d *HwEngine3d) filler_056(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -5940,7 +6583,11 @@ func (e3d *HwEngine3d) filler_056(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -5977,6 +6624,8 @@ func (e3d *HwEngine3d) filler_056(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -5997,6 +6646,8 @@ func (e3d *HwEngine3d) filler_056(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_057 skipped, because of identical polyfiller:
@@ -6458,7 +7109,10 @@ func (e3d *HwEngine3d) filler_0c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -6472,7 +7126,11 @@ func (e3d *HwEngine3d) filler_0c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -6502,6 +7160,7 @@ func (e3d *HwEngine3d) filler_0c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -6521,6 +7180,8 @@ func (e3d *HwEngine3d) filler_0c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -6549,7 +7210,10 @@ func (e3d *HwEngine3d) filler_0c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -6562,7 +7226,11 @@ func (e3d *HwEngine3d) filler_0c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -6588,6 +7256,7 @@ func (e3d *HwEngine3d) filler_0c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -6607,6 +7276,8 @@ func (e3d *HwEngine3d) filler_0c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -6634,7 +7305,10 @@ func (e3d *HwEngine3d) filler_0c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -6647,7 +7321,11 @@ func (e3d *HwEngine3d) filler_0c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -6671,6 +7349,7 @@ func (e3d *HwEngine3d) filler_0c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -6690,6 +7369,8 @@ func (e3d *HwEngine3d) filler_0c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -6719,7 +7400,10 @@ func (e3d *HwEngine3d) filler_0c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -6734,7 +7418,11 @@ func (e3d *HwEngine3d) filler_0c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -6763,6 +7451,7 @@ func (e3d *HwEngine3d) filler_0c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -6782,6 +7471,8 @@ func (e3d *HwEngine3d) filler_0c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -6810,7 +7501,10 @@ func (e3d *HwEngine3d) filler_0c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -6824,7 +7518,11 @@ func (e3d *HwEngine3d) filler_0c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -6849,6 +7547,7 @@ func (e3d *HwEngine3d) filler_0c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -6868,6 +7567,8 @@ func (e3d *HwEngine3d) filler_0c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0c8(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -6895,7 +7596,10 @@ func (e3d *HwEngine3d) filler_0c8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -6909,7 +7613,11 @@ func (e3d *HwEngine3d) filler_0c8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -6932,6 +7640,7 @@ func (e3d *HwEngine3d) filler_0c8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -6951,6 +7660,8 @@ func (e3d *HwEngine3d) filler_0c8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0c9(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -6980,7 +7691,10 @@ func (e3d *HwEngine3d) filler_0c9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -6995,7 +7709,11 @@ func (e3d *HwEngine3d) filler_0c9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7024,6 +7742,7 @@ func (e3d *HwEngine3d) filler_0c9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7043,6 +7762,8 @@ func (e3d *HwEngine3d) filler_0c9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0ca(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7071,7 +7792,10 @@ func (e3d *HwEngine3d) filler_0ca(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -7085,7 +7809,11 @@ func (e3d *HwEngine3d) filler_0ca(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7110,6 +7838,7 @@ func (e3d *HwEngine3d) filler_0ca(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7129,6 +7858,8 @@ func (e3d *HwEngine3d) filler_0ca(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0cb(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7156,7 +7887,10 @@ func (e3d *HwEngine3d) filler_0cb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -7170,7 +7904,11 @@ func (e3d *HwEngine3d) filler_0cb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7193,6 +7931,7 @@ func (e3d *HwEngine3d) filler_0cb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7212,6 +7951,8 @@ func (e3d *HwEngine3d) filler_0cb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0cc(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7241,7 +7982,10 @@ func (e3d *HwEngine3d) filler_0cc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -7255,7 +7999,11 @@ func (e3d *HwEngine3d) filler_0cc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7282,6 +8030,7 @@ func (e3d *HwEngine3d) filler_0cc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7301,6 +8050,8 @@ func (e3d *HwEngine3d) filler_0cc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0cd(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7329,7 +8080,10 @@ func (e3d *HwEngine3d) filler_0cd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -7342,7 +8096,11 @@ func (e3d *HwEngine3d) filler_0cd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7365,6 +8123,7 @@ func (e3d *HwEngine3d) filler_0cd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7384,6 +8143,8 @@ func (e3d *HwEngine3d) filler_0cd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7411,7 +8172,10 @@ func (e3d *HwEngine3d) filler_0ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -7424,7 +8188,11 @@ func (e3d *HwEngine3d) filler_0ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7445,6 +8213,7 @@ func (e3d *HwEngine3d) filler_0ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7464,6 +8233,8 @@ func (e3d *HwEngine3d) filler_0ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7492,7 +8263,10 @@ func (e3d *HwEngine3d) filler_0cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -7508,7 +8282,11 @@ func (e3d *HwEngine3d) filler_0cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7538,6 +8316,7 @@ func (e3d *HwEngine3d) filler_0cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7557,6 +8336,8 @@ func (e3d *HwEngine3d) filler_0cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7584,7 +8365,10 @@ func (e3d *HwEngine3d) filler_0d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -7599,7 +8383,11 @@ func (e3d *HwEngine3d) filler_0d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7625,6 +8413,7 @@ func (e3d *HwEngine3d) filler_0d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7644,6 +8433,8 @@ func (e3d *HwEngine3d) filler_0d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7670,7 +8461,10 @@ func (e3d *HwEngine3d) filler_0d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -7685,7 +8479,11 @@ func (e3d *HwEngine3d) filler_0d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7709,6 +8507,7 @@ func (e3d *HwEngine3d) filler_0d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7728,6 +8527,8 @@ func (e3d *HwEngine3d) filler_0d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7757,7 +8558,10 @@ func (e3d *HwEngine3d) filler_0d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -7771,7 +8575,11 @@ func (e3d *HwEngine3d) filler_0d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7801,6 +8609,7 @@ func (e3d *HwEngine3d) filler_0d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7820,6 +8629,8 @@ func (e3d *HwEngine3d) filler_0d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7848,7 +8659,10 @@ func (e3d *HwEngine3d) filler_0d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -7861,7 +8675,11 @@ func (e3d *HwEngine3d) filler_0d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7887,6 +8705,7 @@ func (e3d *HwEngine3d) filler_0d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7906,6 +8725,8 @@ func (e3d *HwEngine3d) filler_0d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -7933,7 +8754,10 @@ func (e3d *HwEngine3d) filler_0d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -7946,7 +8770,11 @@ func (e3d *HwEngine3d) filler_0d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -7970,6 +8798,7 @@ func (e3d *HwEngine3d) filler_0d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -7989,6 +8818,8 @@ func (e3d *HwEngine3d) filler_0d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8017,7 +8848,10 @@ func (e3d *HwEngine3d) filler_0d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -8032,7 +8866,11 @@ func (e3d *HwEngine3d) filler_0d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8062,6 +8900,7 @@ func (e3d *HwEngine3d) filler_0d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8081,6 +8920,8 @@ func (e3d *HwEngine3d) filler_0d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8108,7 +8949,10 @@ func (e3d *HwEngine3d) filler_0d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -8122,7 +8966,11 @@ func (e3d *HwEngine3d) filler_0d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8148,6 +8996,7 @@ func (e3d *HwEngine3d) filler_0d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8167,6 +9016,8 @@ func (e3d *HwEngine3d) filler_0d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0d7(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8193,7 +9044,10 @@ func (e3d *HwEngine3d) filler_0d7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -8207,7 +9061,11 @@ func (e3d *HwEngine3d) filler_0d7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8231,6 +9089,7 @@ func (e3d *HwEngine3d) filler_0d7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8250,6 +9109,8 @@ func (e3d *HwEngine3d) filler_0d7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_0d8 skipped, because of identical polyfiller:
@@ -8303,7 +9164,10 @@ func (e3d *HwEngine3d) filler_0de(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -8318,7 +9182,11 @@ func (e3d *HwEngine3d) filler_0de(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8351,6 +9219,7 @@ func (e3d *HwEngine3d) filler_0de(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8370,6 +9239,8 @@ func (e3d *HwEngine3d) filler_0de(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0df(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8398,7 +9269,10 @@ func (e3d *HwEngine3d) filler_0df(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -8412,7 +9286,11 @@ func (e3d *HwEngine3d) filler_0df(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8441,6 +9319,7 @@ func (e3d *HwEngine3d) filler_0df(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8460,6 +9339,8 @@ func (e3d *HwEngine3d) filler_0df(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e0(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8487,7 +9368,10 @@ func (e3d *HwEngine3d) filler_0e0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -8501,7 +9385,11 @@ func (e3d *HwEngine3d) filler_0e0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8528,6 +9416,7 @@ func (e3d *HwEngine3d) filler_0e0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8547,6 +9436,8 @@ func (e3d *HwEngine3d) filler_0e0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e1(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8576,7 +9467,10 @@ func (e3d *HwEngine3d) filler_0e1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -8591,7 +9485,11 @@ func (e3d *HwEngine3d) filler_0e1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8624,6 +9522,7 @@ func (e3d *HwEngine3d) filler_0e1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8643,6 +9542,8 @@ func (e3d *HwEngine3d) filler_0e1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e2(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8671,7 +9572,10 @@ func (e3d *HwEngine3d) filler_0e2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -8685,7 +9589,11 @@ func (e3d *HwEngine3d) filler_0e2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8714,6 +9622,7 @@ func (e3d *HwEngine3d) filler_0e2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8733,6 +9642,8 @@ func (e3d *HwEngine3d) filler_0e2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e3(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8760,7 +9671,10 @@ func (e3d *HwEngine3d) filler_0e3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -8774,7 +9688,11 @@ func (e3d *HwEngine3d) filler_0e3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8801,6 +9719,7 @@ func (e3d *HwEngine3d) filler_0e3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8820,6 +9739,8 @@ func (e3d *HwEngine3d) filler_0e3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8849,7 +9770,10 @@ func (e3d *HwEngine3d) filler_0e4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -8863,7 +9787,11 @@ func (e3d *HwEngine3d) filler_0e4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8893,6 +9821,7 @@ func (e3d *HwEngine3d) filler_0e4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8912,6 +9841,8 @@ func (e3d *HwEngine3d) filler_0e4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -8940,7 +9871,10 @@ func (e3d *HwEngine3d) filler_0e5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -8953,7 +9887,11 @@ func (e3d *HwEngine3d) filler_0e5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -8979,6 +9917,7 @@ func (e3d *HwEngine3d) filler_0e5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -8998,6 +9937,8 @@ func (e3d *HwEngine3d) filler_0e5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0e6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9025,7 +9966,10 @@ func (e3d *HwEngine3d) filler_0e6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -9038,7 +9982,11 @@ func (e3d *HwEngine3d) filler_0e6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9062,6 +10010,7 @@ func (e3d *HwEngine3d) filler_0e6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -9081,6 +10030,8 @@ func (e3d *HwEngine3d) filler_0e6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_0e7 skipped, because of identical polyfiller:
@@ -9159,7 +10110,10 @@ func (e3d *HwEngine3d) filler_0f3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -9173,7 +10127,11 @@ func (e3d *HwEngine3d) filler_0f3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9214,6 +10172,8 @@ func (e3d *HwEngine3d) filler_0f3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9234,6 +10194,8 @@ func (e3d *HwEngine3d) filler_0f3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0f4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9263,7 +10225,10 @@ func (e3d *HwEngine3d) filler_0f4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -9276,7 +10241,11 @@ func (e3d *HwEngine3d) filler_0f4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9313,6 +10282,8 @@ func (e3d *HwEngine3d) filler_0f4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9333,6 +10304,8 @@ func (e3d *HwEngine3d) filler_0f4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0f5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9361,7 +10334,10 @@ func (e3d *HwEngine3d) filler_0f5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -9374,7 +10350,11 @@ func (e3d *HwEngine3d) filler_0f5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9409,6 +10389,8 @@ func (e3d *HwEngine3d) filler_0f5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9429,6 +10411,8 @@ func (e3d *HwEngine3d) filler_0f5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0f6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9459,7 +10443,10 @@ func (e3d *HwEngine3d) filler_0f6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -9474,7 +10461,11 @@ func (e3d *HwEngine3d) filler_0f6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9514,6 +10505,8 @@ func (e3d *HwEngine3d) filler_0f6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9534,6 +10527,8 @@ func (e3d *HwEngine3d) filler_0f6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0f7(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9563,7 +10558,10 @@ func (e3d *HwEngine3d) filler_0f7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -9577,7 +10575,11 @@ func (e3d *HwEngine3d) filler_0f7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9613,6 +10615,8 @@ func (e3d *HwEngine3d) filler_0f7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9633,6 +10637,8 @@ func (e3d *HwEngine3d) filler_0f7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0f8(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9661,7 +10667,10 @@ func (e3d *HwEngine3d) filler_0f8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -9675,7 +10684,11 @@ func (e3d *HwEngine3d) filler_0f8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9709,6 +10722,8 @@ func (e3d *HwEngine3d) filler_0f8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9729,6 +10744,8 @@ func (e3d *HwEngine3d) filler_0f8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0f9(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9759,7 +10776,10 @@ func (e3d *HwEngine3d) filler_0f9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -9774,7 +10794,11 @@ func (e3d *HwEngine3d) filler_0f9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9814,6 +10838,8 @@ func (e3d *HwEngine3d) filler_0f9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9834,6 +10860,8 @@ func (e3d *HwEngine3d) filler_0f9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0fa(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9863,7 +10891,10 @@ func (e3d *HwEngine3d) filler_0fa(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -9877,7 +10908,11 @@ func (e3d *HwEngine3d) filler_0fa(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -9913,6 +10948,8 @@ func (e3d *HwEngine3d) filler_0fa(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -9933,6 +10970,8 @@ func (e3d *HwEngine3d) filler_0fa(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0fb(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -9961,7 +11000,10 @@ func (e3d *HwEngine3d) filler_0fb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -9975,7 +11017,11 @@ func (e3d *HwEngine3d) filler_0fb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10009,6 +11055,8 @@ func (e3d *HwEngine3d) filler_0fb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10029,6 +11077,8 @@ func (e3d *HwEngine3d) filler_0fb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0fc(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10059,7 +11109,10 @@ func (e3d *HwEngine3d) filler_0fc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -10073,7 +11126,11 @@ func (e3d *HwEngine3d) filler_0fc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10111,6 +11168,8 @@ func (e3d *HwEngine3d) filler_0fc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10131,6 +11190,8 @@ func (e3d *HwEngine3d) filler_0fc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0fd(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10160,7 +11221,10 @@ func (e3d *HwEngine3d) filler_0fd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -10173,7 +11237,11 @@ func (e3d *HwEngine3d) filler_0fd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10207,6 +11275,8 @@ func (e3d *HwEngine3d) filler_0fd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10227,6 +11297,8 @@ func (e3d *HwEngine3d) filler_0fd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0fe(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10255,7 +11327,10 @@ func (e3d *HwEngine3d) filler_0fe(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -10268,7 +11343,11 @@ func (e3d *HwEngine3d) filler_0fe(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10300,6 +11379,8 @@ func (e3d *HwEngine3d) filler_0fe(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10320,6 +11401,8 @@ func (e3d *HwEngine3d) filler_0fe(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_0ff(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10349,7 +11432,10 @@ func (e3d *HwEngine3d) filler_0ff(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -10365,7 +11451,11 @@ func (e3d *HwEngine3d) filler_0ff(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10406,6 +11496,8 @@ func (e3d *HwEngine3d) filler_0ff(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10426,6 +11518,8 @@ func (e3d *HwEngine3d) filler_0ff(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_100(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10454,7 +11548,10 @@ func (e3d *HwEngine3d) filler_100(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -10469,7 +11566,11 @@ func (e3d *HwEngine3d) filler_100(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10506,6 +11607,8 @@ func (e3d *HwEngine3d) filler_100(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10526,6 +11629,8 @@ func (e3d *HwEngine3d) filler_100(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_101(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10553,7 +11658,10 @@ func (e3d *HwEngine3d) filler_101(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -10568,7 +11676,11 @@ func (e3d *HwEngine3d) filler_101(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10603,6 +11715,8 @@ func (e3d *HwEngine3d) filler_101(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10623,6 +11737,8 @@ func (e3d *HwEngine3d) filler_101(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_102(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10653,7 +11769,10 @@ func (e3d *HwEngine3d) filler_102(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -10667,7 +11786,11 @@ func (e3d *HwEngine3d) filler_102(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10708,6 +11831,8 @@ func (e3d *HwEngine3d) filler_102(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10728,6 +11853,8 @@ func (e3d *HwEngine3d) filler_102(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_103(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10757,7 +11884,10 @@ func (e3d *HwEngine3d) filler_103(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -10770,7 +11900,11 @@ func (e3d *HwEngine3d) filler_103(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10807,6 +11941,8 @@ func (e3d *HwEngine3d) filler_103(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10827,6 +11963,8 @@ func (e3d *HwEngine3d) filler_103(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_104(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10855,7 +11993,10 @@ func (e3d *HwEngine3d) filler_104(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -10868,7 +12009,11 @@ func (e3d *HwEngine3d) filler_104(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -10903,6 +12048,8 @@ func (e3d *HwEngine3d) filler_104(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -10923,6 +12070,8 @@ func (e3d *HwEngine3d) filler_104(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_105(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -10952,7 +12101,10 @@ func (e3d *HwEngine3d) filler_105(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -10967,7 +12119,11 @@ func (e3d *HwEngine3d) filler_105(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11008,6 +12164,8 @@ func (e3d *HwEngine3d) filler_105(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11028,6 +12186,8 @@ func (e3d *HwEngine3d) filler_105(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_106(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11056,7 +12216,10 @@ func (e3d *HwEngine3d) filler_106(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -11070,7 +12233,11 @@ func (e3d *HwEngine3d) filler_106(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11107,6 +12274,8 @@ func (e3d *HwEngine3d) filler_106(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11127,6 +12296,8 @@ func (e3d *HwEngine3d) filler_106(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_107(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11154,7 +12325,10 @@ func (e3d *HwEngine3d) filler_107(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -11168,7 +12342,11 @@ func (e3d *HwEngine3d) filler_107(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11203,6 +12381,8 @@ func (e3d *HwEngine3d) filler_107(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11223,6 +12403,8 @@ func (e3d *HwEngine3d) filler_107(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_108 skipped, because of identical polyfiller:
@@ -11277,7 +12459,10 @@ func (e3d *HwEngine3d) filler_10e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -11292,7 +12477,11 @@ func (e3d *HwEngine3d) filler_10e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11336,6 +12525,8 @@ func (e3d *HwEngine3d) filler_10e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11356,6 +12547,8 @@ func (e3d *HwEngine3d) filler_10e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_10f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11385,7 +12578,10 @@ func (e3d *HwEngine3d) filler_10f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -11399,7 +12595,11 @@ func (e3d *HwEngine3d) filler_10f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11439,6 +12639,8 @@ func (e3d *HwEngine3d) filler_10f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11459,6 +12661,8 @@ func (e3d *HwEngine3d) filler_10f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_110(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11487,7 +12691,10 @@ func (e3d *HwEngine3d) filler_110(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -11501,7 +12708,11 @@ func (e3d *HwEngine3d) filler_110(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11539,6 +12750,8 @@ func (e3d *HwEngine3d) filler_110(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11559,6 +12772,8 @@ func (e3d *HwEngine3d) filler_110(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_111(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11589,7 +12804,10 @@ func (e3d *HwEngine3d) filler_111(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -11604,7 +12822,11 @@ func (e3d *HwEngine3d) filler_111(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11648,6 +12870,8 @@ func (e3d *HwEngine3d) filler_111(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11668,6 +12892,8 @@ func (e3d *HwEngine3d) filler_111(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_112(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11697,7 +12923,10 @@ func (e3d *HwEngine3d) filler_112(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -11711,7 +12940,11 @@ func (e3d *HwEngine3d) filler_112(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11751,6 +12984,8 @@ func (e3d *HwEngine3d) filler_112(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11771,6 +13006,8 @@ func (e3d *HwEngine3d) filler_112(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_113(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11799,7 +13036,10 @@ func (e3d *HwEngine3d) filler_113(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -11813,7 +13053,11 @@ func (e3d *HwEngine3d) filler_113(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11851,6 +13095,8 @@ func (e3d *HwEngine3d) filler_113(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11871,6 +13117,8 @@ func (e3d *HwEngine3d) filler_113(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_114(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -11901,7 +13149,10 @@ func (e3d *HwEngine3d) filler_114(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -11915,7 +13166,11 @@ func (e3d *HwEngine3d) filler_114(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -11956,6 +13211,8 @@ func (e3d *HwEngine3d) filler_114(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -11976,6 +13233,8 @@ func (e3d *HwEngine3d) filler_114(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_115(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -12005,7 +13264,10 @@ func (e3d *HwEngine3d) filler_115(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -12018,7 +13280,11 @@ func (e3d *HwEngine3d) filler_115(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -12055,6 +13321,8 @@ func (e3d *HwEngine3d) filler_115(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -12075,6 +13343,8 @@ func (e3d *HwEngine3d) filler_115(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_116(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -12103,7 +13373,10 @@ func (e3d *HwEngine3d) filler_116(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -12116,7 +13389,11 @@ func (e3d *HwEngine3d) filler_116(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -12151,6 +13428,8 @@ func (e3d *HwEngine3d) filler_116(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -12171,6 +13450,8 @@ func (e3d *HwEngine3d) filler_116(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_117 skipped, because of identical polyfiller:
@@ -12632,7 +13913,10 @@ func (e3d *HwEngine3d) filler_183(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -12646,7 +13930,11 @@ func (e3d *HwEngine3d) filler_183(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -12679,6 +13967,7 @@ func (e3d *HwEngine3d) filler_183(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -12698,6 +13987,8 @@ func (e3d *HwEngine3d) filler_183(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_184(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -12726,7 +14017,10 @@ func (e3d *HwEngine3d) filler_184(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -12739,7 +14033,11 @@ func (e3d *HwEngine3d) filler_184(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -12768,6 +14066,7 @@ func (e3d *HwEngine3d) filler_184(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -12787,6 +14086,8 @@ func (e3d *HwEngine3d) filler_184(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_185(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -12814,7 +14115,10 @@ func (e3d *HwEngine3d) filler_185(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -12827,7 +14131,11 @@ func (e3d *HwEngine3d) filler_185(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -12854,6 +14162,7 @@ func (e3d *HwEngine3d) filler_185(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -12873,6 +14182,8 @@ func (e3d *HwEngine3d) filler_185(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_186(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -12902,7 +14213,10 @@ func (e3d *HwEngine3d) filler_186(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -12917,7 +14231,11 @@ func (e3d *HwEngine3d) filler_186(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -12949,6 +14267,7 @@ func (e3d *HwEngine3d) filler_186(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -12968,6 +14287,8 @@ func (e3d *HwEngine3d) filler_186(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_187(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -12996,7 +14317,10 @@ func (e3d *HwEngine3d) filler_187(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -13010,7 +14334,11 @@ func (e3d *HwEngine3d) filler_187(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13038,6 +14366,7 @@ func (e3d *HwEngine3d) filler_187(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13057,6 +14386,8 @@ func (e3d *HwEngine3d) filler_187(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_188(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13084,7 +14415,10 @@ func (e3d *HwEngine3d) filler_188(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -13098,7 +14432,11 @@ func (e3d *HwEngine3d) filler_188(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13124,6 +14462,7 @@ func (e3d *HwEngine3d) filler_188(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13143,6 +14482,8 @@ func (e3d *HwEngine3d) filler_188(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_189(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13172,7 +14513,10 @@ func (e3d *HwEngine3d) filler_189(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -13187,7 +14531,11 @@ func (e3d *HwEngine3d) filler_189(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13219,6 +14567,7 @@ func (e3d *HwEngine3d) filler_189(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13238,6 +14587,8 @@ func (e3d *HwEngine3d) filler_189(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_18a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13266,7 +14617,10 @@ func (e3d *HwEngine3d) filler_18a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -13280,7 +14634,11 @@ func (e3d *HwEngine3d) filler_18a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13308,6 +14666,7 @@ func (e3d *HwEngine3d) filler_18a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13327,6 +14686,8 @@ func (e3d *HwEngine3d) filler_18a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_18b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13354,7 +14715,10 @@ func (e3d *HwEngine3d) filler_18b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -13368,7 +14732,11 @@ func (e3d *HwEngine3d) filler_18b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13394,6 +14762,7 @@ func (e3d *HwEngine3d) filler_18b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13413,6 +14782,8 @@ func (e3d *HwEngine3d) filler_18b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_18c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13442,7 +14813,10 @@ func (e3d *HwEngine3d) filler_18c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -13456,7 +14830,11 @@ func (e3d *HwEngine3d) filler_18c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13486,6 +14864,7 @@ func (e3d *HwEngine3d) filler_18c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13505,6 +14884,8 @@ func (e3d *HwEngine3d) filler_18c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_18d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13533,7 +14914,10 @@ func (e3d *HwEngine3d) filler_18d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -13546,7 +14930,11 @@ func (e3d *HwEngine3d) filler_18d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13572,6 +14960,7 @@ func (e3d *HwEngine3d) filler_18d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13591,6 +14980,8 @@ func (e3d *HwEngine3d) filler_18d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_18e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13618,7 +15009,10 @@ func (e3d *HwEngine3d) filler_18e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -13631,7 +15025,11 @@ func (e3d *HwEngine3d) filler_18e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13655,6 +15053,7 @@ func (e3d *HwEngine3d) filler_18e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13674,6 +15073,8 @@ func (e3d *HwEngine3d) filler_18e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_18f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13702,7 +15103,10 @@ func (e3d *HwEngine3d) filler_18f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -13718,7 +15122,11 @@ func (e3d *HwEngine3d) filler_18f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13751,6 +15159,7 @@ func (e3d *HwEngine3d) filler_18f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13770,6 +15179,8 @@ func (e3d *HwEngine3d) filler_18f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_190(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13797,7 +15208,10 @@ func (e3d *HwEngine3d) filler_190(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -13812,7 +15226,11 @@ func (e3d *HwEngine3d) filler_190(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13841,6 +15259,7 @@ func (e3d *HwEngine3d) filler_190(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13860,6 +15279,8 @@ func (e3d *HwEngine3d) filler_190(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_191(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13886,7 +15307,10 @@ func (e3d *HwEngine3d) filler_191(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -13901,7 +15325,11 @@ func (e3d *HwEngine3d) filler_191(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -13928,6 +15356,7 @@ func (e3d *HwEngine3d) filler_191(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -13947,6 +15376,8 @@ func (e3d *HwEngine3d) filler_191(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_192(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -13976,7 +15407,10 @@ func (e3d *HwEngine3d) filler_192(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -13990,7 +15424,11 @@ func (e3d *HwEngine3d) filler_192(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14023,6 +15461,7 @@ func (e3d *HwEngine3d) filler_192(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14042,6 +15481,8 @@ func (e3d *HwEngine3d) filler_192(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_193(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14070,7 +15511,10 @@ func (e3d *HwEngine3d) filler_193(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -14083,7 +15527,11 @@ func (e3d *HwEngine3d) filler_193(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14112,6 +15560,7 @@ func (e3d *HwEngine3d) filler_193(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14131,6 +15580,8 @@ func (e3d *HwEngine3d) filler_193(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_194(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14158,7 +15609,10 @@ func (e3d *HwEngine3d) filler_194(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -14171,7 +15625,11 @@ func (e3d *HwEngine3d) filler_194(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14198,6 +15656,7 @@ func (e3d *HwEngine3d) filler_194(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14217,6 +15676,8 @@ func (e3d *HwEngine3d) filler_194(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_195(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14245,7 +15706,10 @@ func (e3d *HwEngine3d) filler_195(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -14260,7 +15724,11 @@ func (e3d *HwEngine3d) filler_195(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14293,6 +15761,7 @@ func (e3d *HwEngine3d) filler_195(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14312,6 +15781,8 @@ func (e3d *HwEngine3d) filler_195(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_196(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14339,7 +15810,10 @@ func (e3d *HwEngine3d) filler_196(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -14353,7 +15827,11 @@ func (e3d *HwEngine3d) filler_196(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14382,6 +15860,7 @@ func (e3d *HwEngine3d) filler_196(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14401,6 +15880,8 @@ func (e3d *HwEngine3d) filler_196(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_197(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14427,7 +15908,10 @@ func (e3d *HwEngine3d) filler_197(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -14441,7 +15925,11 @@ func (e3d *HwEngine3d) filler_197(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14468,6 +15956,7 @@ func (e3d *HwEngine3d) filler_197(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14487,6 +15976,8 @@ func (e3d *HwEngine3d) filler_197(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_198 skipped, because of identical polyfiller:
@@ -14540,7 +16031,10 @@ func (e3d *HwEngine3d) filler_19e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -14555,7 +16049,11 @@ func (e3d *HwEngine3d) filler_19e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14591,6 +16089,7 @@ func (e3d *HwEngine3d) filler_19e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14610,6 +16109,8 @@ func (e3d *HwEngine3d) filler_19e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_19f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14638,7 +16139,10 @@ func (e3d *HwEngine3d) filler_19f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -14652,7 +16156,11 @@ func (e3d *HwEngine3d) filler_19f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14684,6 +16192,7 @@ func (e3d *HwEngine3d) filler_19f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14703,6 +16212,8 @@ func (e3d *HwEngine3d) filler_19f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a0(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14730,7 +16241,10 @@ func (e3d *HwEngine3d) filler_1a0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -14744,7 +16258,11 @@ func (e3d *HwEngine3d) filler_1a0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14774,6 +16292,7 @@ func (e3d *HwEngine3d) filler_1a0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14793,6 +16312,8 @@ func (e3d *HwEngine3d) filler_1a0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a1(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14822,7 +16343,10 @@ func (e3d *HwEngine3d) filler_1a1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -14837,7 +16361,11 @@ func (e3d *HwEngine3d) filler_1a1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14873,6 +16401,7 @@ func (e3d *HwEngine3d) filler_1a1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14892,6 +16421,8 @@ func (e3d *HwEngine3d) filler_1a1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a2(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -14920,7 +16451,10 @@ func (e3d *HwEngine3d) filler_1a2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -14934,7 +16468,11 @@ func (e3d *HwEngine3d) filler_1a2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -14966,6 +16504,7 @@ func (e3d *HwEngine3d) filler_1a2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -14985,6 +16524,8 @@ func (e3d *HwEngine3d) filler_1a2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a3(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15012,7 +16553,10 @@ func (e3d *HwEngine3d) filler_1a3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -15026,7 +16570,11 @@ func (e3d *HwEngine3d) filler_1a3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15056,6 +16604,7 @@ func (e3d *HwEngine3d) filler_1a3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -15075,6 +16624,8 @@ func (e3d *HwEngine3d) filler_1a3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15104,7 +16655,10 @@ func (e3d *HwEngine3d) filler_1a4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -15118,7 +16672,11 @@ func (e3d *HwEngine3d) filler_1a4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15151,6 +16709,7 @@ func (e3d *HwEngine3d) filler_1a4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -15170,6 +16729,8 @@ func (e3d *HwEngine3d) filler_1a4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15198,7 +16759,10 @@ func (e3d *HwEngine3d) filler_1a5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -15211,7 +16775,11 @@ func (e3d *HwEngine3d) filler_1a5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15240,6 +16808,7 @@ func (e3d *HwEngine3d) filler_1a5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -15259,6 +16828,8 @@ func (e3d *HwEngine3d) filler_1a5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1a6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15286,7 +16857,10 @@ func (e3d *HwEngine3d) filler_1a6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -15299,7 +16873,11 @@ func (e3d *HwEngine3d) filler_1a6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15326,6 +16904,7 @@ func (e3d *HwEngine3d) filler_1a6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -15345,6 +16924,8 @@ func (e3d *HwEngine3d) filler_1a6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_1a7 skipped, because of identical polyfiller:
@@ -15423,7 +17004,10 @@ func (e3d *HwEngine3d) filler_1b3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -15437,7 +17021,11 @@ func (e3d *HwEngine3d) filler_1b3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15481,6 +17069,8 @@ func (e3d *HwEngine3d) filler_1b3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -15501,6 +17091,8 @@ func (e3d *HwEngine3d) filler_1b3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1b4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15530,7 +17122,10 @@ func (e3d *HwEngine3d) filler_1b4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -15543,7 +17138,11 @@ func (e3d *HwEngine3d) filler_1b4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15583,6 +17182,8 @@ func (e3d *HwEngine3d) filler_1b4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -15603,6 +17204,8 @@ func (e3d *HwEngine3d) filler_1b4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1b5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15631,7 +17234,10 @@ func (e3d *HwEngine3d) filler_1b5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -15644,7 +17250,11 @@ func (e3d *HwEngine3d) filler_1b5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15682,6 +17292,8 @@ func (e3d *HwEngine3d) filler_1b5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -15702,6 +17314,8 @@ func (e3d *HwEngine3d) filler_1b5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1b6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15732,7 +17346,10 @@ func (e3d *HwEngine3d) filler_1b6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -15747,7 +17364,11 @@ func (e3d *HwEngine3d) filler_1b6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15790,6 +17411,8 @@ func (e3d *HwEngine3d) filler_1b6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -15810,6 +17433,8 @@ func (e3d *HwEngine3d) filler_1b6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1b7(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15839,7 +17464,10 @@ func (e3d *HwEngine3d) filler_1b7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -15853,7 +17481,11 @@ func (e3d *HwEngine3d) filler_1b7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15892,6 +17524,8 @@ func (e3d *HwEngine3d) filler_1b7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -15912,6 +17546,8 @@ func (e3d *HwEngine3d) filler_1b7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1b8(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -15940,7 +17576,10 @@ func (e3d *HwEngine3d) filler_1b8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -15954,7 +17593,11 @@ func (e3d *HwEngine3d) filler_1b8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -15991,6 +17634,8 @@ func (e3d *HwEngine3d) filler_1b8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16011,6 +17656,8 @@ func (e3d *HwEngine3d) filler_1b8(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1b9(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16041,7 +17688,10 @@ func (e3d *HwEngine3d) filler_1b9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -16056,7 +17706,11 @@ func (e3d *HwEngine3d) filler_1b9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16099,6 +17753,8 @@ func (e3d *HwEngine3d) filler_1b9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16119,6 +17775,8 @@ func (e3d *HwEngine3d) filler_1b9(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1ba(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16148,7 +17806,10 @@ func (e3d *HwEngine3d) filler_1ba(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -16162,7 +17823,11 @@ func (e3d *HwEngine3d) filler_1ba(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16201,6 +17866,8 @@ func (e3d *HwEngine3d) filler_1ba(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16221,6 +17888,8 @@ func (e3d *HwEngine3d) filler_1ba(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1bb(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16249,7 +17918,10 @@ func (e3d *HwEngine3d) filler_1bb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -16263,7 +17935,11 @@ func (e3d *HwEngine3d) filler_1bb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16300,6 +17976,8 @@ func (e3d *HwEngine3d) filler_1bb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16320,6 +17998,8 @@ func (e3d *HwEngine3d) filler_1bb(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1bc(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16350,7 +18030,10 @@ func (e3d *HwEngine3d) filler_1bc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -16364,7 +18047,11 @@ func (e3d *HwEngine3d) filler_1bc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16405,6 +18092,8 @@ func (e3d *HwEngine3d) filler_1bc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16425,6 +18114,8 @@ func (e3d *HwEngine3d) filler_1bc(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1bd(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16454,7 +18145,10 @@ func (e3d *HwEngine3d) filler_1bd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -16467,7 +18161,11 @@ func (e3d *HwEngine3d) filler_1bd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16504,6 +18202,8 @@ func (e3d *HwEngine3d) filler_1bd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16524,6 +18224,8 @@ func (e3d *HwEngine3d) filler_1bd(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1be(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16552,7 +18254,10 @@ func (e3d *HwEngine3d) filler_1be(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -16565,7 +18270,11 @@ func (e3d *HwEngine3d) filler_1be(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16600,6 +18309,8 @@ func (e3d *HwEngine3d) filler_1be(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16620,6 +18331,8 @@ func (e3d *HwEngine3d) filler_1be(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1bf(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16649,7 +18362,10 @@ func (e3d *HwEngine3d) filler_1bf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -16665,7 +18381,11 @@ func (e3d *HwEngine3d) filler_1bf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16709,6 +18429,8 @@ func (e3d *HwEngine3d) filler_1bf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16729,6 +18451,8 @@ func (e3d *HwEngine3d) filler_1bf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c0(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16757,7 +18481,10 @@ func (e3d *HwEngine3d) filler_1c0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -16772,7 +18499,11 @@ func (e3d *HwEngine3d) filler_1c0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16812,6 +18543,8 @@ func (e3d *HwEngine3d) filler_1c0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16832,6 +18565,8 @@ func (e3d *HwEngine3d) filler_1c0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c1(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16859,7 +18594,10 @@ func (e3d *HwEngine3d) filler_1c1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -16874,7 +18612,11 @@ func (e3d *HwEngine3d) filler_1c1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -16912,6 +18654,8 @@ func (e3d *HwEngine3d) filler_1c1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -16932,6 +18676,8 @@ func (e3d *HwEngine3d) filler_1c1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c2(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -16962,7 +18708,10 @@ func (e3d *HwEngine3d) filler_1c2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -16976,7 +18725,11 @@ func (e3d *HwEngine3d) filler_1c2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17020,6 +18773,8 @@ func (e3d *HwEngine3d) filler_1c2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17040,6 +18795,8 @@ func (e3d *HwEngine3d) filler_1c2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17069,7 +18826,10 @@ func (e3d *HwEngine3d) filler_1c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -17082,7 +18842,11 @@ func (e3d *HwEngine3d) filler_1c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17122,6 +18886,8 @@ func (e3d *HwEngine3d) filler_1c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17142,6 +18908,8 @@ func (e3d *HwEngine3d) filler_1c3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17170,7 +18938,10 @@ func (e3d *HwEngine3d) filler_1c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -17183,7 +18954,11 @@ func (e3d *HwEngine3d) filler_1c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17221,6 +18996,8 @@ func (e3d *HwEngine3d) filler_1c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17241,6 +19018,8 @@ func (e3d *HwEngine3d) filler_1c4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17270,7 +19049,10 @@ func (e3d *HwEngine3d) filler_1c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -17285,7 +19067,11 @@ func (e3d *HwEngine3d) filler_1c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17329,6 +19115,8 @@ func (e3d *HwEngine3d) filler_1c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17349,6 +19137,8 @@ func (e3d *HwEngine3d) filler_1c5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17377,7 +19167,10 @@ func (e3d *HwEngine3d) filler_1c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -17391,7 +19184,11 @@ func (e3d *HwEngine3d) filler_1c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17431,6 +19228,8 @@ func (e3d *HwEngine3d) filler_1c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17451,6 +19250,8 @@ func (e3d *HwEngine3d) filler_1c6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17478,7 +19279,10 @@ func (e3d *HwEngine3d) filler_1c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -17492,7 +19296,11 @@ func (e3d *HwEngine3d) filler_1c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17530,6 +19338,8 @@ func (e3d *HwEngine3d) filler_1c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17550,6 +19360,8 @@ func (e3d *HwEngine3d) filler_1c7(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_1c8 skipped, because of identical polyfiller:
@@ -17604,7 +19416,10 @@ func (e3d *HwEngine3d) filler_1ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -17619,7 +19434,11 @@ func (e3d *HwEngine3d) filler_1ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17666,6 +19485,8 @@ func (e3d *HwEngine3d) filler_1ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17686,6 +19507,8 @@ func (e3d *HwEngine3d) filler_1ce(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17715,7 +19538,10 @@ func (e3d *HwEngine3d) filler_1cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -17729,7 +19555,11 @@ func (e3d *HwEngine3d) filler_1cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17772,6 +19602,8 @@ func (e3d *HwEngine3d) filler_1cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17792,6 +19624,8 @@ func (e3d *HwEngine3d) filler_1cf(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17820,7 +19654,10 @@ func (e3d *HwEngine3d) filler_1d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -17834,7 +19671,11 @@ func (e3d *HwEngine3d) filler_1d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17875,6 +19716,8 @@ func (e3d *HwEngine3d) filler_1d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -17895,6 +19738,8 @@ func (e3d *HwEngine3d) filler_1d0(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -17925,7 +19770,10 @@ func (e3d *HwEngine3d) filler_1d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -17940,7 +19788,11 @@ func (e3d *HwEngine3d) filler_1d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -17987,6 +19839,8 @@ func (e3d *HwEngine3d) filler_1d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -18007,6 +19861,8 @@ func (e3d *HwEngine3d) filler_1d1(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -18036,7 +19892,10 @@ func (e3d *HwEngine3d) filler_1d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -18050,7 +19909,11 @@ func (e3d *HwEngine3d) filler_1d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -18093,6 +19956,8 @@ func (e3d *HwEngine3d) filler_1d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -18113,6 +19978,8 @@ func (e3d *HwEngine3d) filler_1d2(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -18141,7 +20008,10 @@ func (e3d *HwEngine3d) filler_1d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -18155,7 +20025,11 @@ func (e3d *HwEngine3d) filler_1d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -18196,6 +20070,8 @@ func (e3d *HwEngine3d) filler_1d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -18216,6 +20092,8 @@ func (e3d *HwEngine3d) filler_1d3(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -18246,7 +20124,10 @@ func (e3d *HwEngine3d) filler_1d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -18260,7 +20141,11 @@ func (e3d *HwEngine3d) filler_1d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -18304,6 +20189,8 @@ func (e3d *HwEngine3d) filler_1d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -18324,6 +20211,8 @@ func (e3d *HwEngine3d) filler_1d4(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -18353,7 +20242,10 @@ func (e3d *HwEngine3d) filler_1d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -18366,7 +20258,11 @@ func (e3d *HwEngine3d) filler_1d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -18406,6 +20302,8 @@ func (e3d *HwEngine3d) filler_1d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -18426,6 +20324,8 @@ func (e3d *HwEngine3d) filler_1d5(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_1d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -18454,7 +20354,10 @@ func (e3d *HwEngine3d) filler_1d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -18467,7 +20370,11 @@ func (e3d *HwEngine3d) filler_1d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -18505,6 +20412,8 @@ func (e3d *HwEngine3d) filler_1d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -18525,6 +20434,8 @@ func (e3d *HwEngine3d) filler_1d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_1d7 skipped, because of identical polyfiller:
@@ -18947,17 +20858,86 @@ func (e3d *HwEngine3d) filler_1d6(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 //     23f -> {TexFormat:7 ColorKey:1 FillMode:3 ColorMode:2 TexCoords:2}
 //     197 -> {TexFormat:7 ColorKey:0 FillMode:0 ColorMode:2 TexCoords:2}
 
-// filler_240 skipped, because of identical polyfiller:
-//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+func (e3d *HwEngine3d) filler_240(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
+	// {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:2}
+	x0, x1 := poly.left[LerpX].Cur().NearInt32(), poly.right[LerpX].Cur().NearInt32()
+	nx := x1 - x0
+	if nx == 0 {
+		return
+	}
+	if poly.UseAlpha() {
+		x1 -= 1
+	}
+	d0, d1 := poly.left[LerpD].Cur(), poly.right[LerpD].Cur()
+	dd := d1.SubFixed(d0).Div(nx)
+	r0, r1 := poly.left[LerpR].Cur(), poly.right[LerpR].Cur()
+	dr := r1.SubFixed(r0).Div(nx)
+	g0, g1 := poly.left[LerpG].Cur(), poly.right[LerpG].Cur()
+	dg := g1.SubFixed(g0).Div(nx)
+	b0, b1 := poly.left[LerpB].Cur(), poly.right[LerpB].Cur()
+	db := b1.SubFixed(b0).Div(nx)
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
+	var px uint16
+	var px0 uint8
+	out.Add32(int(x0))
+	zbuf.Add32(int(x0))
+	abuf.Add8(int(x0))
+	for x := x0; x <= x1; x++ {
+		drawz := true
+		var pxa uint8
+		pxa = 63
+		// zbuffer check
+		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
+		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+			goto next
+		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
+		px = uint16(r0.TruncInt32()>>1) | uint16(g0.TruncInt32()>>1)<<5 | uint16(b0.TruncInt32()>>1)<<10
+		// alpha blending with background
+		if pxa == 0 {
+			goto next
+		}
+		pxa >>= 1
+		e3d.lineIds[x] = polyid
+		// draw color and alpha
+		out.Set32(0, uint32(px)|0x80000000)
+		abuf.Set8(0, pxa)
+		if drawz {
+			zbuf.Set32(0, uint32(z.V>>20))
+		}
+	next:
+		out.Add32(1)
+		zbuf.Add32(1)
+		abuf.Add8(1)
+		d0 = d0.AddFixed(dd)
+		r0 = r0.AddFixed(dr)
+		g0 = g0.AddFixed(dg)
+		b0 = b0.AddFixed(db)
+	}
+	_ = px0
+	_ = zalpha
+	_ = zequal
+	_ = ztol
+}
 
 // filler_241 skipped, because of identical polyfiller:
 //     241 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_242 skipped, because of identical polyfiller:
 //     242 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 func (e3d *HwEngine3d) filler_243(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
 	// {TexFormat:1 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
@@ -18986,7 +20966,10 @@ func (e3d *HwEngine3d) filler_243(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -19000,9 +20983,18 @@ func (e3d *HwEngine3d) filler_243(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19027,6 +21019,7 @@ func (e3d *HwEngine3d) filler_243(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19046,6 +21039,8 @@ func (e3d *HwEngine3d) filler_243(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_244(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19074,7 +21069,10 @@ func (e3d *HwEngine3d) filler_244(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -19087,9 +21085,18 @@ func (e3d *HwEngine3d) filler_244(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19110,6 +21117,7 @@ func (e3d *HwEngine3d) filler_244(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19129,6 +21137,8 @@ func (e3d *HwEngine3d) filler_244(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_245(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19156,7 +21166,10 @@ func (e3d *HwEngine3d) filler_245(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -19169,9 +21182,18 @@ func (e3d *HwEngine3d) filler_245(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -19190,6 +21212,7 @@ func (e3d *HwEngine3d) filler_245(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19209,6 +21232,8 @@ func (e3d *HwEngine3d) filler_245(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_246(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19238,7 +21263,10 @@ func (e3d *HwEngine3d) filler_246(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -19253,9 +21281,18 @@ func (e3d *HwEngine3d) filler_246(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19279,6 +21316,7 @@ func (e3d *HwEngine3d) filler_246(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19298,6 +21336,8 @@ func (e3d *HwEngine3d) filler_246(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_247(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19326,7 +21366,10 @@ func (e3d *HwEngine3d) filler_247(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -19340,9 +21383,18 @@ func (e3d *HwEngine3d) filler_247(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19362,6 +21414,7 @@ func (e3d *HwEngine3d) filler_247(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19381,6 +21434,8 @@ func (e3d *HwEngine3d) filler_247(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_248(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19408,7 +21463,10 @@ func (e3d *HwEngine3d) filler_248(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -19422,9 +21480,18 @@ func (e3d *HwEngine3d) filler_248(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -19442,6 +21509,7 @@ func (e3d *HwEngine3d) filler_248(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19461,6 +21529,8 @@ func (e3d *HwEngine3d) filler_248(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_249(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19490,7 +21560,10 @@ func (e3d *HwEngine3d) filler_249(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -19505,9 +21578,18 @@ func (e3d *HwEngine3d) filler_249(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19531,6 +21613,7 @@ func (e3d *HwEngine3d) filler_249(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19550,6 +21633,8 @@ func (e3d *HwEngine3d) filler_249(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_24a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19578,7 +21663,10 @@ func (e3d *HwEngine3d) filler_24a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -19592,9 +21680,18 @@ func (e3d *HwEngine3d) filler_24a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19614,6 +21711,7 @@ func (e3d *HwEngine3d) filler_24a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19633,6 +21731,8 @@ func (e3d *HwEngine3d) filler_24a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_24b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19660,7 +21760,10 @@ func (e3d *HwEngine3d) filler_24b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -19674,9 +21777,18 @@ func (e3d *HwEngine3d) filler_24b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -19694,6 +21806,7 @@ func (e3d *HwEngine3d) filler_24b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19713,6 +21826,8 @@ func (e3d *HwEngine3d) filler_24b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_24c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19742,7 +21857,10 @@ func (e3d *HwEngine3d) filler_24c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -19756,9 +21874,18 @@ func (e3d *HwEngine3d) filler_24c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19780,6 +21907,7 @@ func (e3d *HwEngine3d) filler_24c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19799,6 +21927,8 @@ func (e3d *HwEngine3d) filler_24c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_24d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19827,7 +21957,10 @@ func (e3d *HwEngine3d) filler_24d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -19840,9 +21973,18 @@ func (e3d *HwEngine3d) filler_24d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -19860,6 +22002,7 @@ func (e3d *HwEngine3d) filler_24d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19879,6 +22022,8 @@ func (e3d *HwEngine3d) filler_24d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_24e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19906,7 +22051,10 @@ func (e3d *HwEngine3d) filler_24e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -19919,9 +22067,18 @@ func (e3d *HwEngine3d) filler_24e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -19937,6 +22094,7 @@ func (e3d *HwEngine3d) filler_24e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -19956,6 +22114,8 @@ func (e3d *HwEngine3d) filler_24e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_24f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -19984,7 +22144,10 @@ func (e3d *HwEngine3d) filler_24f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -20000,9 +22163,18 @@ func (e3d *HwEngine3d) filler_24f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20027,6 +22199,7 @@ func (e3d *HwEngine3d) filler_24f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20046,6 +22219,8 @@ func (e3d *HwEngine3d) filler_24f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_250(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20073,7 +22248,10 @@ func (e3d *HwEngine3d) filler_250(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -20088,9 +22266,18 @@ func (e3d *HwEngine3d) filler_250(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20111,6 +22298,7 @@ func (e3d *HwEngine3d) filler_250(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20130,6 +22318,8 @@ func (e3d *HwEngine3d) filler_250(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_251(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20156,7 +22346,10 @@ func (e3d *HwEngine3d) filler_251(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -20171,9 +22364,18 @@ func (e3d *HwEngine3d) filler_251(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -20192,6 +22394,7 @@ func (e3d *HwEngine3d) filler_251(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20211,6 +22414,8 @@ func (e3d *HwEngine3d) filler_251(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_252(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20240,7 +22445,10 @@ func (e3d *HwEngine3d) filler_252(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -20254,9 +22462,18 @@ func (e3d *HwEngine3d) filler_252(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20281,6 +22498,7 @@ func (e3d *HwEngine3d) filler_252(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20300,6 +22518,8 @@ func (e3d *HwEngine3d) filler_252(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_253(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20328,7 +22548,10 @@ func (e3d *HwEngine3d) filler_253(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -20341,9 +22564,18 @@ func (e3d *HwEngine3d) filler_253(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20364,6 +22596,7 @@ func (e3d *HwEngine3d) filler_253(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20383,6 +22616,8 @@ func (e3d *HwEngine3d) filler_253(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_254(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20410,7 +22645,10 @@ func (e3d *HwEngine3d) filler_254(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -20423,9 +22661,18 @@ func (e3d *HwEngine3d) filler_254(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -20444,6 +22691,7 @@ func (e3d *HwEngine3d) filler_254(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20463,6 +22711,8 @@ func (e3d *HwEngine3d) filler_254(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_255(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20491,7 +22741,10 @@ func (e3d *HwEngine3d) filler_255(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -20506,9 +22759,18 @@ func (e3d *HwEngine3d) filler_255(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20533,6 +22795,7 @@ func (e3d *HwEngine3d) filler_255(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20552,6 +22815,8 @@ func (e3d *HwEngine3d) filler_255(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_256(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20579,7 +22844,10 @@ func (e3d *HwEngine3d) filler_256(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -20593,9 +22861,18 @@ func (e3d *HwEngine3d) filler_256(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20616,6 +22893,7 @@ func (e3d *HwEngine3d) filler_256(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20635,6 +22913,8 @@ func (e3d *HwEngine3d) filler_256(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_257(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20661,7 +22941,10 @@ func (e3d *HwEngine3d) filler_257(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -20675,9 +22958,18 @@ func (e3d *HwEngine3d) filler_257(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -20696,6 +22988,7 @@ func (e3d *HwEngine3d) filler_257(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20715,19 +23008,21 @@ func (e3d *HwEngine3d) filler_257(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_258 skipped, because of identical polyfiller:
 //     258 -> {TexFormat:0 ColorKey:1 FillMode:0 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_259 skipped, because of identical polyfiller:
 //     259 -> {TexFormat:0 ColorKey:1 FillMode:0 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_25a skipped, because of identical polyfiller:
 //     25a -> {TexFormat:0 ColorKey:1 FillMode:0 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_25b skipped, because of identical polyfiller:
 //     25b -> {TexFormat:1 ColorKey:1 FillMode:0 ColorMode:3 TexCoords:0}
@@ -20768,7 +23063,10 @@ func (e3d *HwEngine3d) filler_25e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -20783,9 +23081,18 @@ func (e3d *HwEngine3d) filler_25e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20813,6 +23120,7 @@ func (e3d *HwEngine3d) filler_25e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20832,6 +23140,8 @@ func (e3d *HwEngine3d) filler_25e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_25f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20860,7 +23170,10 @@ func (e3d *HwEngine3d) filler_25f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -20874,9 +23187,18 @@ func (e3d *HwEngine3d) filler_25f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -20900,6 +23222,7 @@ func (e3d *HwEngine3d) filler_25f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -20919,6 +23242,8 @@ func (e3d *HwEngine3d) filler_25f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_260(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -20946,7 +23271,10 @@ func (e3d *HwEngine3d) filler_260(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -20960,9 +23288,18 @@ func (e3d *HwEngine3d) filler_260(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -20984,6 +23321,7 @@ func (e3d *HwEngine3d) filler_260(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21003,6 +23341,8 @@ func (e3d *HwEngine3d) filler_260(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_261(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21032,7 +23372,10 @@ func (e3d *HwEngine3d) filler_261(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -21047,9 +23390,18 @@ func (e3d *HwEngine3d) filler_261(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21077,6 +23429,7 @@ func (e3d *HwEngine3d) filler_261(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21096,6 +23449,8 @@ func (e3d *HwEngine3d) filler_261(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_262(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21124,7 +23479,10 @@ func (e3d *HwEngine3d) filler_262(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -21138,9 +23496,18 @@ func (e3d *HwEngine3d) filler_262(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21164,6 +23531,7 @@ func (e3d *HwEngine3d) filler_262(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21183,6 +23551,8 @@ func (e3d *HwEngine3d) filler_262(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_263(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21210,7 +23580,10 @@ func (e3d *HwEngine3d) filler_263(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -21224,9 +23597,18 @@ func (e3d *HwEngine3d) filler_263(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -21248,6 +23630,7 @@ func (e3d *HwEngine3d) filler_263(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21267,6 +23650,8 @@ func (e3d *HwEngine3d) filler_263(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_264(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21296,7 +23681,10 @@ func (e3d *HwEngine3d) filler_264(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -21310,9 +23698,18 @@ func (e3d *HwEngine3d) filler_264(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21337,6 +23734,7 @@ func (e3d *HwEngine3d) filler_264(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21356,6 +23754,8 @@ func (e3d *HwEngine3d) filler_264(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_265(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21384,7 +23784,10 @@ func (e3d *HwEngine3d) filler_265(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -21397,9 +23800,18 @@ func (e3d *HwEngine3d) filler_265(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21420,6 +23832,7 @@ func (e3d *HwEngine3d) filler_265(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21439,6 +23852,8 @@ func (e3d *HwEngine3d) filler_265(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_266(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21466,7 +23881,10 @@ func (e3d *HwEngine3d) filler_266(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -21479,9 +23897,18 @@ func (e3d *HwEngine3d) filler_266(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -21500,6 +23927,7 @@ func (e3d *HwEngine3d) filler_266(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -21519,6 +23947,8 @@ func (e3d *HwEngine3d) filler_266(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_267 skipped, because of identical polyfiller:
@@ -21557,17 +23987,100 @@ func (e3d *HwEngine3d) filler_266(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 //     26f -> {TexFormat:7 ColorKey:1 FillMode:0 ColorMode:3 TexCoords:2}
 //     257 -> {TexFormat:7 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:2}
 
-// filler_270 skipped, because of identical polyfiller:
-//     270 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:2}
-//     030 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:0 TexCoords:0}
+func (e3d *HwEngine3d) filler_270(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
+	// {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:2}
+	x0, x1 := poly.left[LerpX].Cur().NearInt32(), poly.right[LerpX].Cur().NearInt32()
+	nx := x1 - x0
+	if nx == 0 {
+		return
+	}
+	if poly.UseAlpha() {
+		x1 -= 1
+	}
+	d0, d1 := poly.left[LerpD].Cur(), poly.right[LerpD].Cur()
+	dd := d1.SubFixed(d0).Div(nx)
+	r0, r1 := poly.left[LerpR].Cur(), poly.right[LerpR].Cur()
+	dr := r1.SubFixed(r0).Div(nx)
+	g0, g1 := poly.left[LerpG].Cur(), poly.right[LerpG].Cur()
+	dg := g1.SubFixed(g0).Div(nx)
+	b0, b1 := poly.left[LerpB].Cur(), poly.right[LerpB].Cur()
+	db := b1.SubFixed(b0).Div(nx)
+	polyalpha := uint8(poly.flags.Alpha()) << 1
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
+	var px uint16
+	var px0 uint8
+	out.Add32(int(x0))
+	zbuf.Add32(int(x0))
+	abuf.Add8(int(x0))
+	for x := x0; x <= x1; x++ {
+		drawz := true
+		var pxa uint8
+		pxa = 63
+		// zbuffer check
+		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
+		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+			goto next
+		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
+		px = uint16(r0.TruncInt32()>>1) | uint16(g0.TruncInt32()>>1)<<5 | uint16(b0.TruncInt32()>>1)<<10
+		pxa = polyalpha
+		// alpha blending with background
+		if pxa == 0 {
+			goto next
+		}
+		pxa >>= 1
+		if pxa != 31 {
+			bkg := uint16(out.Get32(0))
+			bkga := abuf.Get8(0)
+			if bkga != 0 {
+				px = rgbAlphaMix(px, bkg, pxa)
+			}
+			if pxa < bkga {
+				pxa = bkga
+			}
+			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
+		}
+		// draw color and alpha
+		out.Set32(0, uint32(px)|0x80000000)
+		abuf.Set8(0, pxa)
+		if drawz {
+			zbuf.Set32(0, uint32(z.V>>20))
+		}
+	next:
+		out.Add32(1)
+		zbuf.Add32(1)
+		abuf.Add8(1)
+		d0 = d0.AddFixed(dd)
+		r0 = r0.AddFixed(dr)
+		g0 = g0.AddFixed(dg)
+		b0 = b0.AddFixed(db)
+	}
+	_ = px0
+	_ = zalpha
+	_ = zequal
+	_ = ztol
+}
 
 // filler_271 skipped, because of identical polyfiller:
 //     271 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:2}
-//     030 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:0 TexCoords:0}
+//     270 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:0}
 
 // filler_272 skipped, because of identical polyfiller:
 //     272 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:2}
-//     030 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:0 TexCoords:0}
+//     270 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:0}
 
 func (e3d *HwEngine3d) filler_273(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
 	// {TexFormat:1 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:0}
@@ -21597,7 +24110,10 @@ func (e3d *HwEngine3d) filler_273(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -21611,9 +24127,18 @@ func (e3d *HwEngine3d) filler_273(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21649,6 +24174,8 @@ func (e3d *HwEngine3d) filler_273(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -21669,6 +24196,8 @@ func (e3d *HwEngine3d) filler_273(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_274(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21698,7 +24227,10 @@ func (e3d *HwEngine3d) filler_274(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -21711,9 +24243,18 @@ func (e3d *HwEngine3d) filler_274(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21745,6 +24286,8 @@ func (e3d *HwEngine3d) filler_274(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -21765,6 +24308,8 @@ func (e3d *HwEngine3d) filler_274(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_275(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21793,7 +24338,10 @@ func (e3d *HwEngine3d) filler_275(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -21806,9 +24354,18 @@ func (e3d *HwEngine3d) filler_275(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -21838,6 +24395,8 @@ func (e3d *HwEngine3d) filler_275(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -21858,6 +24417,8 @@ func (e3d *HwEngine3d) filler_275(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_276(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21888,7 +24449,10 @@ func (e3d *HwEngine3d) filler_276(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -21903,9 +24467,18 @@ func (e3d *HwEngine3d) filler_276(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -21940,6 +24513,8 @@ func (e3d *HwEngine3d) filler_276(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -21960,6 +24535,8 @@ func (e3d *HwEngine3d) filler_276(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_277(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -21989,7 +24566,10 @@ func (e3d *HwEngine3d) filler_277(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -22003,9 +24583,18 @@ func (e3d *HwEngine3d) filler_277(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22036,6 +24625,8 @@ func (e3d *HwEngine3d) filler_277(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22056,6 +24647,8 @@ func (e3d *HwEngine3d) filler_277(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_278(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22084,7 +24677,10 @@ func (e3d *HwEngine3d) filler_278(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -22098,9 +24694,18 @@ func (e3d *HwEngine3d) filler_278(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -22129,6 +24734,8 @@ func (e3d *HwEngine3d) filler_278(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22149,6 +24756,8 @@ func (e3d *HwEngine3d) filler_278(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_279(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22179,7 +24788,10 @@ func (e3d *HwEngine3d) filler_279(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -22194,9 +24806,18 @@ func (e3d *HwEngine3d) filler_279(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22231,6 +24852,8 @@ func (e3d *HwEngine3d) filler_279(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22251,6 +24874,8 @@ func (e3d *HwEngine3d) filler_279(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_27a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22280,7 +24905,10 @@ func (e3d *HwEngine3d) filler_27a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -22294,9 +24922,18 @@ func (e3d *HwEngine3d) filler_27a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22327,6 +24964,8 @@ func (e3d *HwEngine3d) filler_27a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22347,6 +24986,8 @@ func (e3d *HwEngine3d) filler_27a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_27b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22375,7 +25016,10 @@ func (e3d *HwEngine3d) filler_27b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -22389,9 +25033,18 @@ func (e3d *HwEngine3d) filler_27b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -22420,6 +25073,8 @@ func (e3d *HwEngine3d) filler_27b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22440,6 +25095,8 @@ func (e3d *HwEngine3d) filler_27b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_27c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22470,7 +25127,10 @@ func (e3d *HwEngine3d) filler_27c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -22484,9 +25144,18 @@ func (e3d *HwEngine3d) filler_27c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22519,6 +25188,8 @@ func (e3d *HwEngine3d) filler_27c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22539,6 +25210,8 @@ func (e3d *HwEngine3d) filler_27c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_27d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22568,7 +25241,10 @@ func (e3d *HwEngine3d) filler_27d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -22581,9 +25257,18 @@ func (e3d *HwEngine3d) filler_27d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22612,6 +25297,8 @@ func (e3d *HwEngine3d) filler_27d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22632,6 +25319,8 @@ func (e3d *HwEngine3d) filler_27d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_27e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22660,7 +25349,10 @@ func (e3d *HwEngine3d) filler_27e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -22673,9 +25365,18 @@ func (e3d *HwEngine3d) filler_27e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -22702,6 +25403,8 @@ func (e3d *HwEngine3d) filler_27e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22722,6 +25425,8 @@ func (e3d *HwEngine3d) filler_27e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_27f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22751,7 +25456,10 @@ func (e3d *HwEngine3d) filler_27f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -22767,9 +25475,18 @@ func (e3d *HwEngine3d) filler_27f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22805,6 +25522,8 @@ func (e3d *HwEngine3d) filler_27f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22825,6 +25544,8 @@ func (e3d *HwEngine3d) filler_27f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_280(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22853,7 +25574,10 @@ func (e3d *HwEngine3d) filler_280(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -22868,9 +25592,18 @@ func (e3d *HwEngine3d) filler_280(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -22902,6 +25635,8 @@ func (e3d *HwEngine3d) filler_280(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -22922,6 +25657,8 @@ func (e3d *HwEngine3d) filler_280(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_281(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -22949,7 +25686,10 @@ func (e3d *HwEngine3d) filler_281(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -22964,9 +25704,18 @@ func (e3d *HwEngine3d) filler_281(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -22996,6 +25745,8 @@ func (e3d *HwEngine3d) filler_281(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23016,6 +25767,8 @@ func (e3d *HwEngine3d) filler_281(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_282(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23046,7 +25799,10 @@ func (e3d *HwEngine3d) filler_282(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -23060,9 +25816,18 @@ func (e3d *HwEngine3d) filler_282(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -23098,6 +25863,8 @@ func (e3d *HwEngine3d) filler_282(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23118,6 +25885,8 @@ func (e3d *HwEngine3d) filler_282(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_283(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23147,7 +25916,10 @@ func (e3d *HwEngine3d) filler_283(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -23160,9 +25932,18 @@ func (e3d *HwEngine3d) filler_283(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -23194,6 +25975,8 @@ func (e3d *HwEngine3d) filler_283(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23214,6 +25997,8 @@ func (e3d *HwEngine3d) filler_283(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_284(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23242,7 +26027,10 @@ func (e3d *HwEngine3d) filler_284(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -23255,9 +26043,18 @@ func (e3d *HwEngine3d) filler_284(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -23287,6 +26084,8 @@ func (e3d *HwEngine3d) filler_284(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23307,6 +26106,8 @@ func (e3d *HwEngine3d) filler_284(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_285(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23336,7 +26137,10 @@ func (e3d *HwEngine3d) filler_285(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -23351,9 +26155,18 @@ func (e3d *HwEngine3d) filler_285(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -23389,6 +26202,8 @@ func (e3d *HwEngine3d) filler_285(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23409,6 +26224,8 @@ func (e3d *HwEngine3d) filler_285(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_286(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23437,7 +26254,10 @@ func (e3d *HwEngine3d) filler_286(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -23451,9 +26271,18 @@ func (e3d *HwEngine3d) filler_286(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -23485,6 +26314,8 @@ func (e3d *HwEngine3d) filler_286(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23505,6 +26336,8 @@ func (e3d *HwEngine3d) filler_286(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_287(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23532,7 +26365,10 @@ func (e3d *HwEngine3d) filler_287(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -23546,9 +26382,18 @@ func (e3d *HwEngine3d) filler_287(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -23578,6 +26423,8 @@ func (e3d *HwEngine3d) filler_287(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23598,19 +26445,21 @@ func (e3d *HwEngine3d) filler_287(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_288 skipped, because of identical polyfiller:
 //     288 -> {TexFormat:0 ColorKey:1 FillMode:1 ColorMode:3 TexCoords:2}
-//     030 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:0 TexCoords:0}
+//     270 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:0}
 
 // filler_289 skipped, because of identical polyfiller:
 //     289 -> {TexFormat:0 ColorKey:1 FillMode:1 ColorMode:3 TexCoords:2}
-//     030 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:0 TexCoords:0}
+//     270 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:0}
 
 // filler_28a skipped, because of identical polyfiller:
 //     28a -> {TexFormat:0 ColorKey:1 FillMode:1 ColorMode:3 TexCoords:2}
-//     030 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:0 TexCoords:0}
+//     270 -> {TexFormat:0 ColorKey:0 FillMode:1 ColorMode:3 TexCoords:0}
 
 // filler_28b skipped, because of identical polyfiller:
 //     28b -> {TexFormat:1 ColorKey:1 FillMode:1 ColorMode:3 TexCoords:0}
@@ -23652,7 +26501,10 @@ func (e3d *HwEngine3d) filler_28e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -23667,9 +26519,18 @@ func (e3d *HwEngine3d) filler_28e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -23708,6 +26569,8 @@ func (e3d *HwEngine3d) filler_28e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23728,6 +26591,8 @@ func (e3d *HwEngine3d) filler_28e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_28f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23757,7 +26622,10 @@ func (e3d *HwEngine3d) filler_28f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -23771,9 +26639,18 @@ func (e3d *HwEngine3d) filler_28f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -23808,6 +26685,8 @@ func (e3d *HwEngine3d) filler_28f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23828,6 +26707,8 @@ func (e3d *HwEngine3d) filler_28f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_290(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23856,7 +26737,10 @@ func (e3d *HwEngine3d) filler_290(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -23870,9 +26754,18 @@ func (e3d *HwEngine3d) filler_290(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -23905,6 +26798,8 @@ func (e3d *HwEngine3d) filler_290(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -23925,6 +26820,8 @@ func (e3d *HwEngine3d) filler_290(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_291(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -23955,7 +26852,10 @@ func (e3d *HwEngine3d) filler_291(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -23970,9 +26870,18 @@ func (e3d *HwEngine3d) filler_291(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -24011,6 +26920,8 @@ func (e3d *HwEngine3d) filler_291(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -24031,6 +26942,8 @@ func (e3d *HwEngine3d) filler_291(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_292(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -24060,7 +26973,10 @@ func (e3d *HwEngine3d) filler_292(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -24074,9 +26990,18 @@ func (e3d *HwEngine3d) filler_292(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -24111,6 +27036,8 @@ func (e3d *HwEngine3d) filler_292(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -24131,6 +27058,8 @@ func (e3d *HwEngine3d) filler_292(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_293(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -24159,7 +27088,10 @@ func (e3d *HwEngine3d) filler_293(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -24173,9 +27105,18 @@ func (e3d *HwEngine3d) filler_293(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -24208,6 +27149,8 @@ func (e3d *HwEngine3d) filler_293(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -24228,6 +27171,8 @@ func (e3d *HwEngine3d) filler_293(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_294(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -24258,7 +27203,10 @@ func (e3d *HwEngine3d) filler_294(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -24272,9 +27220,18 @@ func (e3d *HwEngine3d) filler_294(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -24310,6 +27267,8 @@ func (e3d *HwEngine3d) filler_294(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -24330,6 +27289,8 @@ func (e3d *HwEngine3d) filler_294(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_295(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -24359,7 +27320,10 @@ func (e3d *HwEngine3d) filler_295(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -24372,9 +27336,18 @@ func (e3d *HwEngine3d) filler_295(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s = bool2uint32ff(s&sflip != 0) ^ s
@@ -24406,6 +27379,8 @@ func (e3d *HwEngine3d) filler_295(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -24426,6 +27401,8 @@ func (e3d *HwEngine3d) filler_295(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -24454,7 +27431,10 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -24467,9 +27447,18 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
+		if polyid == 0 {
+			if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+				e3d.lineStencil[x] = 1
+			}
+			goto next
+		}
 		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
+		if e3d.lineStencil[x] == 0 || e3d.lineIds[x] == polyid {
+			goto next
+		}
 		// texel coords
 		s, t = uint32(s0.MulFixed(z).TruncInt32()), uint32(t0.MulFixed(z).TruncInt32())
 		s, t = s&smask, t&tmask
@@ -24499,6 +27488,8 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -24519,6 +27510,8 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_297 skipped, because of identical polyfiller:
@@ -24559,15 +27552,15 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 
 // filler_2a0 skipped, because of identical polyfiller:
 //     2a0 -> {TexFormat:0 ColorKey:0 FillMode:2 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2a1 skipped, because of identical polyfiller:
 //     2a1 -> {TexFormat:0 ColorKey:0 FillMode:2 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2a2 skipped, because of identical polyfiller:
 //     2a2 -> {TexFormat:0 ColorKey:0 FillMode:2 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2a3 skipped, because of identical polyfiller:
 //     2a3 -> {TexFormat:1 ColorKey:0 FillMode:2 ColorMode:3 TexCoords:0}
@@ -24655,15 +27648,15 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 
 // filler_2b8 skipped, because of identical polyfiller:
 //     2b8 -> {TexFormat:0 ColorKey:1 FillMode:2 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2b9 skipped, because of identical polyfiller:
 //     2b9 -> {TexFormat:0 ColorKey:1 FillMode:2 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2ba skipped, because of identical polyfiller:
 //     2ba -> {TexFormat:0 ColorKey:1 FillMode:2 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2bb skipped, because of identical polyfiller:
 //     2bb -> {TexFormat:1 ColorKey:1 FillMode:2 ColorMode:3 TexCoords:0}
@@ -24751,15 +27744,15 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 
 // filler_2d0 skipped, because of identical polyfiller:
 //     2d0 -> {TexFormat:0 ColorKey:0 FillMode:3 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2d1 skipped, because of identical polyfiller:
 //     2d1 -> {TexFormat:0 ColorKey:0 FillMode:3 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2d2 skipped, because of identical polyfiller:
 //     2d2 -> {TexFormat:0 ColorKey:0 FillMode:3 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2d3 skipped, because of identical polyfiller:
 //     2d3 -> {TexFormat:1 ColorKey:0 FillMode:3 ColorMode:3 TexCoords:0}
@@ -24847,15 +27840,15 @@ func (e3d *HwEngine3d) filler_296(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 
 // filler_2e8 skipped, because of identical polyfiller:
 //     2e8 -> {TexFormat:0 ColorKey:1 FillMode:3 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2e9 skipped, because of identical polyfiller:
 //     2e9 -> {TexFormat:0 ColorKey:1 FillMode:3 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2ea skipped, because of identical polyfiller:
 //     2ea -> {TexFormat:0 ColorKey:1 FillMode:3 ColorMode:3 TexCoords:2}
-//     000 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:0 TexCoords:0}
+//     240 -> {TexFormat:0 ColorKey:0 FillMode:0 ColorMode:3 TexCoords:0}
 
 // filler_2eb skipped, because of identical polyfiller:
 //     2eb -> {TexFormat:1 ColorKey:1 FillMode:3 ColorMode:3 TexCoords:0}
@@ -24980,7 +27973,10 @@ func (e3d *HwEngine3d) filler_303(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -24994,7 +27990,11 @@ func (e3d *HwEngine3d) filler_303(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25039,6 +28039,7 @@ func (e3d *HwEngine3d) filler_303(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25058,6 +28059,8 @@ func (e3d *HwEngine3d) filler_303(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_304(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25086,7 +28089,10 @@ func (e3d *HwEngine3d) filler_304(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -25099,7 +28105,11 @@ func (e3d *HwEngine3d) filler_304(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25140,6 +28150,7 @@ func (e3d *HwEngine3d) filler_304(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25159,6 +28170,8 @@ func (e3d *HwEngine3d) filler_304(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_305(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25186,7 +28199,10 @@ func (e3d *HwEngine3d) filler_305(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -25199,7 +28215,11 @@ func (e3d *HwEngine3d) filler_305(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25238,6 +28258,7 @@ func (e3d *HwEngine3d) filler_305(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25257,6 +28278,8 @@ func (e3d *HwEngine3d) filler_305(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_306(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25286,7 +28309,10 @@ func (e3d *HwEngine3d) filler_306(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -25301,7 +28327,11 @@ func (e3d *HwEngine3d) filler_306(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25345,6 +28375,7 @@ func (e3d *HwEngine3d) filler_306(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25364,6 +28395,8 @@ func (e3d *HwEngine3d) filler_306(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_307(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25392,7 +28425,10 @@ func (e3d *HwEngine3d) filler_307(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -25406,7 +28442,11 @@ func (e3d *HwEngine3d) filler_307(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25446,6 +28486,7 @@ func (e3d *HwEngine3d) filler_307(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25465,6 +28506,8 @@ func (e3d *HwEngine3d) filler_307(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_308(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25492,7 +28535,10 @@ func (e3d *HwEngine3d) filler_308(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -25506,7 +28552,11 @@ func (e3d *HwEngine3d) filler_308(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25544,6 +28594,7 @@ func (e3d *HwEngine3d) filler_308(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25563,6 +28614,8 @@ func (e3d *HwEngine3d) filler_308(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_309(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25592,7 +28645,10 @@ func (e3d *HwEngine3d) filler_309(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -25607,7 +28663,11 @@ func (e3d *HwEngine3d) filler_309(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25651,6 +28711,7 @@ func (e3d *HwEngine3d) filler_309(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25670,6 +28731,8 @@ func (e3d *HwEngine3d) filler_309(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_30a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25698,7 +28761,10 @@ func (e3d *HwEngine3d) filler_30a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -25712,7 +28778,11 @@ func (e3d *HwEngine3d) filler_30a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25752,6 +28822,7 @@ func (e3d *HwEngine3d) filler_30a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25771,6 +28842,8 @@ func (e3d *HwEngine3d) filler_30a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_30b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25798,7 +28871,10 @@ func (e3d *HwEngine3d) filler_30b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -25812,7 +28888,11 @@ func (e3d *HwEngine3d) filler_30b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25850,6 +28930,7 @@ func (e3d *HwEngine3d) filler_30b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25869,6 +28950,8 @@ func (e3d *HwEngine3d) filler_30b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_30c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -25898,7 +28981,10 @@ func (e3d *HwEngine3d) filler_30c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -25912,7 +28998,11 @@ func (e3d *HwEngine3d) filler_30c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -25954,6 +29044,7 @@ func (e3d *HwEngine3d) filler_30c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -25973,6 +29064,8 @@ func (e3d *HwEngine3d) filler_30c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_30d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26001,7 +29094,10 @@ func (e3d *HwEngine3d) filler_30d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -26014,7 +29110,11 @@ func (e3d *HwEngine3d) filler_30d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26052,6 +29152,7 @@ func (e3d *HwEngine3d) filler_30d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26071,6 +29172,8 @@ func (e3d *HwEngine3d) filler_30d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_30e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26098,7 +29201,10 @@ func (e3d *HwEngine3d) filler_30e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -26111,7 +29217,11 @@ func (e3d *HwEngine3d) filler_30e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26147,6 +29257,7 @@ func (e3d *HwEngine3d) filler_30e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26166,6 +29277,8 @@ func (e3d *HwEngine3d) filler_30e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_30f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26194,7 +29307,10 @@ func (e3d *HwEngine3d) filler_30f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -26210,7 +29326,11 @@ func (e3d *HwEngine3d) filler_30f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26255,6 +29375,7 @@ func (e3d *HwEngine3d) filler_30f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26274,6 +29395,8 @@ func (e3d *HwEngine3d) filler_30f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_310(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26301,7 +29424,10 @@ func (e3d *HwEngine3d) filler_310(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -26316,7 +29442,11 @@ func (e3d *HwEngine3d) filler_310(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26357,6 +29487,7 @@ func (e3d *HwEngine3d) filler_310(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26376,6 +29507,8 @@ func (e3d *HwEngine3d) filler_310(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_311(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26402,7 +29535,10 @@ func (e3d *HwEngine3d) filler_311(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -26417,7 +29553,11 @@ func (e3d *HwEngine3d) filler_311(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26456,6 +29596,7 @@ func (e3d *HwEngine3d) filler_311(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26475,6 +29616,8 @@ func (e3d *HwEngine3d) filler_311(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_312(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26504,7 +29647,10 @@ func (e3d *HwEngine3d) filler_312(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -26518,7 +29664,11 @@ func (e3d *HwEngine3d) filler_312(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26563,6 +29713,7 @@ func (e3d *HwEngine3d) filler_312(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26582,6 +29733,8 @@ func (e3d *HwEngine3d) filler_312(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_313(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26610,7 +29763,10 @@ func (e3d *HwEngine3d) filler_313(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -26623,7 +29779,11 @@ func (e3d *HwEngine3d) filler_313(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26664,6 +29824,7 @@ func (e3d *HwEngine3d) filler_313(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26683,6 +29844,8 @@ func (e3d *HwEngine3d) filler_313(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_314(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26710,7 +29873,10 @@ func (e3d *HwEngine3d) filler_314(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -26723,7 +29889,11 @@ func (e3d *HwEngine3d) filler_314(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26762,6 +29932,7 @@ func (e3d *HwEngine3d) filler_314(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26781,6 +29952,8 @@ func (e3d *HwEngine3d) filler_314(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_315(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26809,7 +29982,10 @@ func (e3d *HwEngine3d) filler_315(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -26824,7 +30000,11 @@ func (e3d *HwEngine3d) filler_315(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26869,6 +30049,7 @@ func (e3d *HwEngine3d) filler_315(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26888,6 +30069,8 @@ func (e3d *HwEngine3d) filler_315(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_316(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -26915,7 +30098,10 @@ func (e3d *HwEngine3d) filler_316(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -26929,7 +30115,11 @@ func (e3d *HwEngine3d) filler_316(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -26970,6 +30160,7 @@ func (e3d *HwEngine3d) filler_316(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -26989,6 +30180,8 @@ func (e3d *HwEngine3d) filler_316(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_317(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27015,7 +30208,10 @@ func (e3d *HwEngine3d) filler_317(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -27029,7 +30225,11 @@ func (e3d *HwEngine3d) filler_317(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27068,6 +30268,7 @@ func (e3d *HwEngine3d) filler_317(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27087,6 +30288,8 @@ func (e3d *HwEngine3d) filler_317(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_318 skipped, because of identical polyfiller:
@@ -27140,7 +30343,10 @@ func (e3d *HwEngine3d) filler_31e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -27155,7 +30361,11 @@ func (e3d *HwEngine3d) filler_31e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27203,6 +30413,7 @@ func (e3d *HwEngine3d) filler_31e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27222,6 +30433,8 @@ func (e3d *HwEngine3d) filler_31e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_31f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27250,7 +30463,10 @@ func (e3d *HwEngine3d) filler_31f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -27264,7 +30480,11 @@ func (e3d *HwEngine3d) filler_31f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27308,6 +30528,7 @@ func (e3d *HwEngine3d) filler_31f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27327,6 +30548,8 @@ func (e3d *HwEngine3d) filler_31f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_320(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27354,7 +30577,10 @@ func (e3d *HwEngine3d) filler_320(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -27368,7 +30594,11 @@ func (e3d *HwEngine3d) filler_320(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27410,6 +30640,7 @@ func (e3d *HwEngine3d) filler_320(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27429,6 +30660,8 @@ func (e3d *HwEngine3d) filler_320(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_321(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27458,7 +30691,10 @@ func (e3d *HwEngine3d) filler_321(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -27473,7 +30709,11 @@ func (e3d *HwEngine3d) filler_321(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27521,6 +30761,7 @@ func (e3d *HwEngine3d) filler_321(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27540,6 +30781,8 @@ func (e3d *HwEngine3d) filler_321(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_322(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27568,7 +30811,10 @@ func (e3d *HwEngine3d) filler_322(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -27582,7 +30828,11 @@ func (e3d *HwEngine3d) filler_322(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27626,6 +30876,7 @@ func (e3d *HwEngine3d) filler_322(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27645,6 +30896,8 @@ func (e3d *HwEngine3d) filler_322(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_323(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27672,7 +30925,10 @@ func (e3d *HwEngine3d) filler_323(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -27686,7 +30942,11 @@ func (e3d *HwEngine3d) filler_323(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27728,6 +30988,7 @@ func (e3d *HwEngine3d) filler_323(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27747,6 +31008,8 @@ func (e3d *HwEngine3d) filler_323(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_324(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27776,7 +31039,10 @@ func (e3d *HwEngine3d) filler_324(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sclamp, tclamp := poly.tex.SClampMask, poly.tex.TClampMask
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -27790,7 +31056,11 @@ func (e3d *HwEngine3d) filler_324(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27835,6 +31105,7 @@ func (e3d *HwEngine3d) filler_324(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27854,6 +31125,8 @@ func (e3d *HwEngine3d) filler_324(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_325(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27882,7 +31155,10 @@ func (e3d *HwEngine3d) filler_325(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -27895,7 +31171,11 @@ func (e3d *HwEngine3d) filler_325(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -27936,6 +31216,7 @@ func (e3d *HwEngine3d) filler_325(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -27955,6 +31236,8 @@ func (e3d *HwEngine3d) filler_325(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_326(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -27982,7 +31265,10 @@ func (e3d *HwEngine3d) filler_326(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	t0, t1 := poly.left[LerpT].Cur(), poly.right[LerpT].Cur()
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -27995,7 +31281,11 @@ func (e3d *HwEngine3d) filler_326(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28034,6 +31324,7 @@ func (e3d *HwEngine3d) filler_326(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 			goto next
 		}
 		pxa >>= 1
+		e3d.lineIds[x] = polyid
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
 		abuf.Set8(0, pxa)
@@ -28053,6 +31344,8 @@ func (e3d *HwEngine3d) filler_326(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_327 skipped, because of identical polyfiller:
@@ -28131,7 +31424,10 @@ func (e3d *HwEngine3d) filler_333(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -28145,7 +31441,11 @@ func (e3d *HwEngine3d) filler_333(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28201,6 +31501,8 @@ func (e3d *HwEngine3d) filler_333(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28221,6 +31523,8 @@ func (e3d *HwEngine3d) filler_333(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_334(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28250,7 +31554,10 @@ func (e3d *HwEngine3d) filler_334(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -28263,7 +31570,11 @@ func (e3d *HwEngine3d) filler_334(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28315,6 +31626,8 @@ func (e3d *HwEngine3d) filler_334(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28335,6 +31648,8 @@ func (e3d *HwEngine3d) filler_334(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_335(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28363,7 +31678,10 @@ func (e3d *HwEngine3d) filler_335(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -28376,7 +31694,11 @@ func (e3d *HwEngine3d) filler_335(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28426,6 +31748,8 @@ func (e3d *HwEngine3d) filler_335(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28446,6 +31770,8 @@ func (e3d *HwEngine3d) filler_335(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_336(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28476,7 +31802,10 @@ func (e3d *HwEngine3d) filler_336(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -28491,7 +31820,11 @@ func (e3d *HwEngine3d) filler_336(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28546,6 +31879,8 @@ func (e3d *HwEngine3d) filler_336(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28566,6 +31901,8 @@ func (e3d *HwEngine3d) filler_336(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_337(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28595,7 +31932,10 @@ func (e3d *HwEngine3d) filler_337(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -28609,7 +31949,11 @@ func (e3d *HwEngine3d) filler_337(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28660,6 +32004,8 @@ func (e3d *HwEngine3d) filler_337(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28680,6 +32026,8 @@ func (e3d *HwEngine3d) filler_337(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_338(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28708,7 +32056,10 @@ func (e3d *HwEngine3d) filler_338(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -28722,7 +32073,11 @@ func (e3d *HwEngine3d) filler_338(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28771,6 +32126,8 @@ func (e3d *HwEngine3d) filler_338(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28791,6 +32148,8 @@ func (e3d *HwEngine3d) filler_338(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_339(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28821,7 +32180,10 @@ func (e3d *HwEngine3d) filler_339(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -28836,7 +32198,11 @@ func (e3d *HwEngine3d) filler_339(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -28891,6 +32257,8 @@ func (e3d *HwEngine3d) filler_339(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -28911,6 +32279,8 @@ func (e3d *HwEngine3d) filler_339(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_33a(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -28940,7 +32310,10 @@ func (e3d *HwEngine3d) filler_33a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -28954,7 +32327,11 @@ func (e3d *HwEngine3d) filler_33a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29005,6 +32382,8 @@ func (e3d *HwEngine3d) filler_33a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29025,6 +32404,8 @@ func (e3d *HwEngine3d) filler_33a(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_33b(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29053,7 +32434,10 @@ func (e3d *HwEngine3d) filler_33b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -29067,7 +32451,11 @@ func (e3d *HwEngine3d) filler_33b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29116,6 +32504,8 @@ func (e3d *HwEngine3d) filler_33b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29136,6 +32526,8 @@ func (e3d *HwEngine3d) filler_33b(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_33c(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29166,7 +32558,10 @@ func (e3d *HwEngine3d) filler_33c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -29180,7 +32575,11 @@ func (e3d *HwEngine3d) filler_33c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29233,6 +32632,8 @@ func (e3d *HwEngine3d) filler_33c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29253,6 +32654,8 @@ func (e3d *HwEngine3d) filler_33c(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_33d(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29282,7 +32685,10 @@ func (e3d *HwEngine3d) filler_33d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -29295,7 +32701,11 @@ func (e3d *HwEngine3d) filler_33d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29344,6 +32754,8 @@ func (e3d *HwEngine3d) filler_33d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29364,6 +32776,8 @@ func (e3d *HwEngine3d) filler_33d(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_33e(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29392,7 +32806,10 @@ func (e3d *HwEngine3d) filler_33e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -29405,7 +32822,11 @@ func (e3d *HwEngine3d) filler_33e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29452,6 +32873,8 @@ func (e3d *HwEngine3d) filler_33e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29472,6 +32895,8 @@ func (e3d *HwEngine3d) filler_33e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_33f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29501,7 +32926,10 @@ func (e3d *HwEngine3d) filler_33f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -29517,7 +32945,11 @@ func (e3d *HwEngine3d) filler_33f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29573,6 +33005,8 @@ func (e3d *HwEngine3d) filler_33f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29593,6 +33027,8 @@ func (e3d *HwEngine3d) filler_33f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_340(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29621,7 +33057,10 @@ func (e3d *HwEngine3d) filler_340(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -29636,7 +33075,11 @@ func (e3d *HwEngine3d) filler_340(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29688,6 +33131,8 @@ func (e3d *HwEngine3d) filler_340(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29708,6 +33153,8 @@ func (e3d *HwEngine3d) filler_340(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_341(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29735,7 +33182,10 @@ func (e3d *HwEngine3d) filler_341(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	decompTexBuf := e3d.texCache.Get(texoff)
 	decompTex := gfx.NewLine(decompTexBuf)
 	var px uint16
@@ -29750,7 +33200,11 @@ func (e3d *HwEngine3d) filler_341(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29800,6 +33254,8 @@ func (e3d *HwEngine3d) filler_341(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29820,6 +33276,8 @@ func (e3d *HwEngine3d) filler_341(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_342(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29850,7 +33308,10 @@ func (e3d *HwEngine3d) filler_342(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -29864,7 +33325,11 @@ func (e3d *HwEngine3d) filler_342(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -29920,6 +33385,8 @@ func (e3d *HwEngine3d) filler_342(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -29940,6 +33407,8 @@ func (e3d *HwEngine3d) filler_342(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_343(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -29969,7 +33438,10 @@ func (e3d *HwEngine3d) filler_343(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -29982,7 +33454,11 @@ func (e3d *HwEngine3d) filler_343(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30034,6 +33510,8 @@ func (e3d *HwEngine3d) filler_343(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30054,6 +33532,8 @@ func (e3d *HwEngine3d) filler_343(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_344(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30082,7 +33562,10 @@ func (e3d *HwEngine3d) filler_344(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -30095,7 +33578,11 @@ func (e3d *HwEngine3d) filler_344(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30145,6 +33632,8 @@ func (e3d *HwEngine3d) filler_344(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30165,6 +33654,8 @@ func (e3d *HwEngine3d) filler_344(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_345(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30194,7 +33685,10 @@ func (e3d *HwEngine3d) filler_345(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -30209,7 +33703,11 @@ func (e3d *HwEngine3d) filler_345(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30265,6 +33763,8 @@ func (e3d *HwEngine3d) filler_345(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30285,6 +33785,8 @@ func (e3d *HwEngine3d) filler_345(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_346(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30313,7 +33815,10 @@ func (e3d *HwEngine3d) filler_346(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -30327,7 +33832,11 @@ func (e3d *HwEngine3d) filler_346(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30379,6 +33888,8 @@ func (e3d *HwEngine3d) filler_346(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30399,6 +33910,8 @@ func (e3d *HwEngine3d) filler_346(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_347(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30426,7 +33939,10 @@ func (e3d *HwEngine3d) filler_347(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift += 1
 	var px uint16
 	var px0 uint8
@@ -30440,7 +33956,11 @@ func (e3d *HwEngine3d) filler_347(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30490,6 +34010,8 @@ func (e3d *HwEngine3d) filler_347(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30510,6 +34032,8 @@ func (e3d *HwEngine3d) filler_347(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_348 skipped, because of identical polyfiller:
@@ -30564,7 +34088,10 @@ func (e3d *HwEngine3d) filler_34e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -30579,7 +34106,11 @@ func (e3d *HwEngine3d) filler_34e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30638,6 +34169,8 @@ func (e3d *HwEngine3d) filler_34e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30658,6 +34191,8 @@ func (e3d *HwEngine3d) filler_34e(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_34f(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30687,7 +34222,10 @@ func (e3d *HwEngine3d) filler_34f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -30701,7 +34239,11 @@ func (e3d *HwEngine3d) filler_34f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30756,6 +34298,8 @@ func (e3d *HwEngine3d) filler_34f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30776,6 +34320,8 @@ func (e3d *HwEngine3d) filler_34f(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_350(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30804,7 +34350,10 @@ func (e3d *HwEngine3d) filler_350(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 2
 	var px uint16
 	var px0 uint8
@@ -30818,7 +34367,11 @@ func (e3d *HwEngine3d) filler_350(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30871,6 +34424,8 @@ func (e3d *HwEngine3d) filler_350(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -30891,6 +34446,8 @@ func (e3d *HwEngine3d) filler_350(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_351(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -30921,7 +34478,10 @@ func (e3d *HwEngine3d) filler_351(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -30936,7 +34496,11 @@ func (e3d *HwEngine3d) filler_351(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -30995,6 +34559,8 @@ func (e3d *HwEngine3d) filler_351(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -31015,6 +34581,8 @@ func (e3d *HwEngine3d) filler_351(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_352(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -31044,7 +34612,10 @@ func (e3d *HwEngine3d) filler_352(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -31058,7 +34629,11 @@ func (e3d *HwEngine3d) filler_352(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -31113,6 +34688,8 @@ func (e3d *HwEngine3d) filler_352(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -31133,6 +34710,8 @@ func (e3d *HwEngine3d) filler_352(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_353(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -31161,7 +34740,10 @@ func (e3d *HwEngine3d) filler_353(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	tshift -= 1
 	var px uint16
 	var px0 uint8
@@ -31175,7 +34757,11 @@ func (e3d *HwEngine3d) filler_353(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -31228,6 +34814,8 @@ func (e3d *HwEngine3d) filler_353(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -31248,6 +34836,8 @@ func (e3d *HwEngine3d) filler_353(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_354(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -31278,7 +34868,10 @@ func (e3d *HwEngine3d) filler_354(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -31292,7 +34885,11 @@ func (e3d *HwEngine3d) filler_354(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		var doclamps, doclampt uint32
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -31348,6 +34945,8 @@ func (e3d *HwEngine3d) filler_354(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -31368,6 +34967,8 @@ func (e3d *HwEngine3d) filler_354(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_355(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -31397,7 +34998,10 @@ func (e3d *HwEngine3d) filler_355(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	sflip, tflip := poly.tex.SFlipMask, poly.tex.TFlipMask
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -31410,7 +35014,11 @@ func (e3d *HwEngine3d) filler_355(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -31462,6 +35070,8 @@ func (e3d *HwEngine3d) filler_355(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -31482,6 +35092,8 @@ func (e3d *HwEngine3d) filler_355(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 func (e3d *HwEngine3d) filler_356(poly *Polygon, out gfx.Line, zbuf gfx.Line, abuf gfx.Line) {
@@ -31510,7 +35122,10 @@ func (e3d *HwEngine3d) filler_356(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	ds, dt := s1.SubFixed(s0).Div(nx), t1.SubFixed(t0).Div(nx)
 	smask, tmask := poly.tex.Width-1, poly.tex.Height-1
 	polyalpha := uint8(poly.flags.Alpha()) << 1
-	zalpha := e3d.Disp3dCnt.Value&(1<<11) != 0
+	zalpha := poly.flags&(1<<11) != 0
+	zequal := poly.flags&(1<<14) != 0
+	ztol := e3d.depthEqualTol
+	polyid := poly.flags.ID()
 	var px uint16
 	var px0 uint8
 	var s, t uint32
@@ -31523,7 +35138,11 @@ func (e3d *HwEngine3d) filler_356(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 		pxa = 63
 		// zbuffer check
 		z := d0.Inv()
-		if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
+		if zequal {
+			if dz := int32(z.V>>20) - int32(zbuf.Get32(0)); dz < -ztol || dz > ztol {
+				goto next
+			}
+		} else if int32(z.V>>20) >= int32(zbuf.Get32(0)) {
 			goto next
 		}
 		// texel coords
@@ -31573,6 +35192,8 @@ func (e3d *HwEngine3d) filler_356(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 				pxa = bkga
 			}
 			drawz = zalpha
+		} else {
+			e3d.lineIds[x] = polyid
 		}
 		// draw color and alpha
 		out.Set32(0, uint32(px)|0x80000000)
@@ -31593,6 +35214,8 @@ func (e3d *HwEngine3d) filler_356(poly *Polygon, out gfx.Line, zbuf gfx.Line, ab
 	}
 	_ = px0
 	_ = zalpha
+	_ = zequal
+	_ = ztol
 }
 
 // filler_357 skipped, because of identical polyfiller:
@@ -32592,9 +36215,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_195,
 	(*HwEngine3d).filler_196,
 	(*HwEngine3d).filler_197,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
 	(*HwEngine3d).filler_243,
 	(*HwEngine3d).filler_244,
 	(*HwEngine3d).filler_245,
@@ -32616,9 +36239,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_255,
 	(*HwEngine3d).filler_256,
 	(*HwEngine3d).filler_257,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
 	(*HwEngine3d).filler_243,
 	(*HwEngine3d).filler_244,
 	(*HwEngine3d).filler_245,
@@ -32640,9 +36263,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_255,
 	(*HwEngine3d).filler_256,
 	(*HwEngine3d).filler_257,
-	(*HwEngine3d).filler_030,
-	(*HwEngine3d).filler_030,
-	(*HwEngine3d).filler_030,
+	(*HwEngine3d).filler_270,
+	(*HwEngine3d).filler_270,
+	(*HwEngine3d).filler_270,
 	(*HwEngine3d).filler_273,
 	(*HwEngine3d).filler_274,
 	(*HwEngine3d).filler_275,
@@ -32664,9 +36287,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_285,
 	(*HwEngine3d).filler_286,
 	(*HwEngine3d).filler_287,
-	(*HwEngine3d).filler_030,
-	(*HwEngine3d).filler_030,
-	(*HwEngine3d).filler_030,
+	(*HwEngine3d).filler_270,
+	(*HwEngine3d).filler_270,
+	(*HwEngine3d).filler_270,
 	(*HwEngine3d).filler_273,
 	(*HwEngine3d).filler_274,
 	(*HwEngine3d).filler_275,
@@ -32688,9 +36311,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_285,
 	(*HwEngine3d).filler_286,
 	(*HwEngine3d).filler_287,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
 	(*HwEngine3d).filler_243,
 	(*HwEngine3d).filler_244,
 	(*HwEngine3d).filler_245,
@@ -32712,9 +36335,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_255,
 	(*HwEngine3d).filler_256,
 	(*HwEngine3d).filler_257,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
 	(*HwEngine3d).filler_243,
 	(*HwEngine3d).filler_244,
 	(*HwEngine3d).filler_245,
@@ -32736,9 +36359,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_255,
 	(*HwEngine3d).filler_256,
 	(*HwEngine3d).filler_257,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
 	(*HwEngine3d).filler_243,
 	(*HwEngine3d).filler_244,
 	(*HwEngine3d).filler_245,
@@ -32760,9 +36383,9 @@ var polygonFillerTable = [960]func(*HwEngine3d, *Polygon, gfx.Line, gfx.Line, gf
 	(*HwEngine3d).filler_255,
 	(*HwEngine3d).filler_256,
 	(*HwEngine3d).filler_257,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
-	(*HwEngine3d).filler_000,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
+	(*HwEngine3d).filler_240,
 	(*HwEngine3d).filler_243,
 	(*HwEngine3d).filler_244,
 	(*HwEngine3d).filler_245,
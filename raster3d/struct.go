@@ -16,6 +16,7 @@ const (
 	RVFClipFar
 	RVFTransformed // vertex has been already transformed to screen space
 	RVFDepth       // prepared for perspective correction
+	RVFStored      // vertex has been accounted in the hardware vertex RAM
 
 	RVFClipMask = (RVFClipLeft | RVFClipRight | RVFClipTop | RVFClipBottom | RVFClipNear | RVFClipFar)
 )
@@ -48,6 +49,7 @@ type PolygonFlags uint32
 const (
 	PFRenderBack  PolygonFlags = 1 << 6
 	PFRenderFront              = 1 << 7
+	PFRender1Dot               = 1 << 13 // render 1-dot polygons behind DISP_1DOT_DEPTH
 	PFQuad                     = 1 << 31
 )
 
@@ -61,6 +63,7 @@ const (
 )
 
 func (f PolygonFlags) Alpha() int                  { return int(f>>16) & 0x1F }
+func (f PolygonFlags) ID() uint8                   { return uint8(f>>24) & 0x3F }
 func (f PolygonFlags) ColorMode() PolygonColorMode { return PolygonColorMode(f>>4) & 3 }
 
 const (
@@ -141,6 +144,23 @@ type Texture struct {
 	Flags    TexFlags
 }
 
+// bottomY returns the largest Y screen coordinate of the polygon
+func (p *Polygon) bottomY() int32 {
+	y := p.vtx[0].y.V
+	for _, v := range p.vtx[1:] {
+		if v.y.V > y {
+			y = v.y.V
+		}
+	}
+	return y
+}
+
+// IsShadowMask returns true if this is a shadow polygon used to build the
+// stencil mask (polygon ID 0), rather than to draw the shadow itself.
+func (p *Polygon) IsShadowMask() bool {
+	return p.flags.ColorMode() == PCMShadow && p.flags.ID() == 0
+}
+
 func (p *Polygon) UseAlpha() bool {
 	// Check if the texture contains an alpha value (and we're using it)
 	mode := p.flags.ColorMode()
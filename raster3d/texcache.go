@@ -1,12 +1,9 @@
 package raster3d
 
 import (
-	"fmt"
 	"image"
 	icolor "image/color"
-	"image/png"
 	"ndsemu/emu"
-	"os"
 )
 
 // Cache holding decompressed textures. It is a locked dictionary,
@@ -47,6 +44,10 @@ func (cache *texCache) Update(polys []Polygon, e3d *HwEngine3d) {
 		}
 
 		off := poly.tex.VramTexOffset
+		if off&texReplacedFlag != 0 {
+			cache.Put(off, e3d.texPack.Buffer(off))
+			continue
+		}
 		if buf := cache.Get(off); buf != nil {
 			if len(buf) != int((poly.tex.Width)*(poly.tex.Height)*2) {
 				panic("different compressed texture size in same frame")
@@ -56,18 +57,6 @@ func (cache *texCache) Update(polys []Polygon, e3d *HwEngine3d) {
 
 		out := decompFunc(cache, poly, e3d)
 
-		if false {
-			f, err := os.Create(fmt.Sprintf("tex-%x.png", poly.tex.VramTexOffset))
-			if err == nil {
-				png.Encode(f, &Image555{
-					buf: out,
-					w:   int(poly.tex.Width),
-					h:   int(poly.tex.Height),
-				})
-				f.Close()
-			}
-		}
-
 		cache.Put(poly.tex.VramTexOffset, out)
 	}
 }
@@ -0,0 +1,394 @@
+package raster3d
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"image"
+	icolor "image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Flag set in Texture.VramTexOffset of polygons whose texture has been
+// substituted with a replacement. The remaining bits are an index into
+// texturePack.bufs. VRAM offsets are at most 19 bits, so there's no clash.
+const texReplacedFlag = 1 << 31
+
+// Key identifying a texture within a frame, used to avoid decoding and
+// hashing the same texture multiple times.
+type texKey struct {
+	off, paloff   uint32
+	width, height uint32
+	format        TexFormat
+	colorKey      bool
+}
+
+// Key identifying a replacement used for a texture of a specific size
+type texReplKey struct {
+	hash          uint64
+	width, height uint32
+}
+
+// A replacement converted for the rasterizer: the index of its buffer in
+// texturePack.bufs, and its size relative to the original texture (as a
+// power of two, for each axis)
+type texRepl struct {
+	buf            uint32
+	sshift, tshift uint
+}
+
+// Maximum ratio between the size of a replacement and the original texture,
+// on each axis (as a power of two). This bounds the memory used by each
+// replacement to 64 times that of the original texture.
+const cTexPackMaxShift = 3
+
+// texturePack implements dumping of the textures used by the 3D engine to
+// PNG files, and substitution with user-provided replacements at render time.
+//
+// Textures are identified by a hash of their decoded contents (texels
+// and palette), so that the same texture is recognized independently of
+// where it was uploaded in VRAM. Both dumped and replacement files are
+// named after the hash (eg: "1f3a9c0e2d4b5a68.png").
+//
+// Replacements can have a higher resolution than the original texture (eg:
+// high-res community packs), and are sampled at their own resolution: the
+// texture coordinates of polygons are scaled by the ratio between the sizes,
+// which must be a power of two on each axis. Replacements of a different
+// size are resampled to the closest smaller power-of-two multiple (up to
+// 8x; or to the original size, if smaller).
+type texturePack struct {
+	dumpDir string
+	dumped  map[uint64]bool
+
+	// Replacement images found in the pack, indexed by hash
+	repl map[uint64]image.Image
+
+	// Replacements already converted to RGB555, ready to be used in the
+	// Tex4x4 polyfiller (which reads from the texture cache). These are
+	// never freed, as they are bounded by the size of the pack.
+	bufs     [][]byte
+	bufIndex map[texReplKey]texRepl
+
+	// Per-frame cache of texture hashes
+	hashes map[texKey]uint64
+}
+
+func newTexturePack() *texturePack {
+	return &texturePack{
+		dumped:   make(map[uint64]bool),
+		repl:     make(map[uint64]image.Image),
+		bufIndex: make(map[texReplKey]texRepl),
+	}
+}
+
+// SetTextureDump enables dumping of all textures used in 3D scenes as PNG
+// files into the specified directory. Each texture is dumped only once.
+func (e3d *HwEngine3d) SetTextureDump(dir string) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if e3d.texPack == nil {
+		e3d.texPack = newTexturePack()
+	}
+	e3d.texPack.dumpDir = dir
+	return nil
+}
+
+// TakeTexturePack moves the texture dumping/replacement configuration of
+// another engine into this one (used when the hardware is recreated on reset)
+func (e3d *HwEngine3d) TakeTexturePack(old *HwEngine3d) {
+	e3d.texPack, old.texPack = old.texPack, nil
+}
+
+// LoadTexturePack loads all the replacement textures found in the
+// specified directory. Files that are not named after a texture hash
+// are ignored.
+func (e3d *HwEngine3d) LoadTexturePack(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	if e3d.texPack == nil {
+		e3d.texPack = newTexturePack()
+	}
+	tp := e3d.texPack
+
+	for _, fi := range files {
+		name := fi.Name()
+		if fi.IsDir() || strings.ToLower(filepath.Ext(name)) != ".png" {
+			continue
+		}
+		hash, err := strconv.ParseUint(strings.TrimSuffix(name, filepath.Ext(name)), 16, 64)
+		if err != nil {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		tp.repl[hash] = img
+	}
+
+	mod3d.InfoZ("texture pack loaded").String("dir", dir).Int("textures", len(tp.repl)).End()
+	return nil
+}
+
+// Apply hashes the textures used by the polygons of a scene, dumps them
+// if requested, and switches polygons whose texture has a replacement to
+// the replacement buffer. It must be called before polyfillers are
+// selected, as replaced polygons must use the Tex4x4 (decompressed) path.
+func (tp *texturePack) Apply(polys []Polygon, e3d *HwEngine3d) {
+	tp.hashes = make(map[texKey]uint64)
+
+	for idx := range polys {
+		poly := &polys[idx]
+		if poly.tex.Format == TexNone || poly.tex.VramTexOffset&texReplacedFlag != 0 {
+			// Untextured, or already replaced in a previous redraw of
+			// the same scene.
+			continue
+		}
+
+		key := texKey{
+			off:      poly.tex.VramTexOffset,
+			paloff:   poly.tex.VramPalOffset,
+			width:    poly.tex.Width,
+			height:   poly.tex.Height,
+			format:   poly.tex.Format,
+			colorKey: poly.tex.ColorKey,
+		}
+		hash, found := tp.hashes[key]
+		if !found {
+			img := decodeTexture(poly, e3d)
+			hash = hashTexture(img)
+			tp.hashes[key] = hash
+			if tp.dumpDir != "" && !tp.dumped[hash] {
+				tp.dump(hash, img)
+			}
+		}
+
+		if r, ok := tp.replacement(hash, poly.tex.Width, poly.tex.Height); ok {
+			poly.tex.VramTexOffset = texReplacedFlag | r.buf
+			poly.tex.Format = Tex4x4
+			scaleTexture(poly, r.sshift, r.tshift)
+		}
+	}
+}
+
+// scaleTexture adapts a polygon to a texture 1<<sshift times wider and
+// 1<<tshift times taller than its own. Texture coordinates are scaled too, so
+// that the polygon is mapped to the same area of the texture; wrapping,
+// flipping and clamping keep working on the new size.
+func scaleTexture(poly *Polygon, sshift, tshift uint) {
+	tex := &poly.tex
+	tex.Width <<= sshift
+	tex.Height <<= tshift
+	tex.PitchShift += sshift
+	if tex.SClampMask != 0 {
+		tex.SClampMask = ^(tex.Width - 1)
+	}
+	if tex.TClampMask != 0 {
+		tex.TClampMask = ^(tex.Height - 1)
+	}
+	if tex.SFlipMask != 0 {
+		tex.SFlipMask = tex.Width
+	}
+	if tex.TFlipMask != 0 {
+		tex.TFlipMask = tex.Height
+	}
+
+	poly.left[LerpS].Shl(sshift)
+	poly.right[LerpS].Shl(sshift)
+	poly.left[LerpT].Shl(tshift)
+	poly.right[LerpT].Shl(tshift)
+}
+
+// Buffer returns the replacement buffer referenced by a replaced polygon
+func (tp *texturePack) Buffer(off uint32) []byte {
+	return tp.bufs[off&^texReplacedFlag]
+}
+
+func (tp *texturePack) dump(hash uint64, img image.Image) {
+	tp.dumped[hash] = true
+
+	fn := filepath.Join(tp.dumpDir, fmt.Sprintf("%016x.png", hash))
+	if _, err := os.Stat(fn); err == nil {
+		// Already dumped in a previous session
+		return
+	}
+
+	f, err := os.Create(fn)
+	if err != nil {
+		mod3d.ErrorZ("cannot dump texture").Error("err", err).End()
+		return
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		mod3d.ErrorZ("cannot dump texture").Error("err", err).End()
+	}
+}
+
+func (tp *texturePack) replacement(hash uint64, w, h uint32) (texRepl, bool) {
+	rkey := texReplKey{hash, w, h}
+	if r, found := tp.bufIndex[rkey]; found {
+		return r, true
+	}
+	img := tp.repl[hash]
+	if img == nil {
+		return texRepl{}, false
+	}
+
+	r := texRepl{
+		buf:    uint32(len(tp.bufs)),
+		sshift: replScale(img.Bounds().Dx(), int(w)),
+		tshift: replScale(img.Bounds().Dy(), int(h)),
+	}
+	tp.bufs = append(tp.bufs, resampleTo555(img, int(w<<r.sshift), int(h<<r.tshift)))
+	tp.bufIndex[rkey] = r
+	return r, true
+}
+
+// replScale returns the size at which a replacement is used, for one axis: the
+// largest power-of-two multiple of the original size that is not larger than
+// the replacement (as a shift)
+func replScale(repl, orig int) uint {
+	var shift uint
+	for shift < cTexPackMaxShift && orig<<(shift+1) <= repl {
+		shift++
+	}
+	return shift
+}
+
+func hashTexture(img *image.NRGBA) uint64 {
+	var size [8]byte
+	binary.LittleEndian.PutUint32(size[0:], uint32(img.Rect.Dx()))
+	binary.LittleEndian.PutUint32(size[4:], uint32(img.Rect.Dy()))
+
+	h := fnv.New64a()
+	h.Write(size[:])
+	h.Write(img.Pix)
+	return h.Sum64()
+}
+
+func rgb555ToNRGBA(c uint16, a uint8) icolor.NRGBA {
+	r, g, b := uint8(c)&0x1F, uint8(c>>5)&0x1F, uint8(c>>10)&0x1F
+	return icolor.NRGBA{
+		R: (r << 3) | (r >> 2),
+		G: (g << 3) | (g >> 2),
+		B: (b << 3) | (b >> 2),
+		A: a,
+	}
+}
+
+// decodeTexture converts a texture in any of the hardware formats into
+// a RGBA image.
+func decodeTexture(poly *Polygon, e3d *HwEngine3d) *image.NRGBA {
+	w, h := int(poly.tex.Width), int(poly.tex.Height)
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	off := poly.tex.VramTexOffset
+	pal := e3d.palVram.Palette(int(poly.tex.VramPalOffset))
+
+	var decomp []byte
+	if poly.tex.Format == Tex4x4 {
+		decomp = e3d.texCache.decompTex4x4(poly, e3d)
+	}
+
+	get8 := func(o uint32) uint8 { return e3d.texVram.Get8((off + o) & 0x7FFFF) }
+	indexed := func(idx uint8) icolor.NRGBA {
+		if idx == 0 && poly.tex.ColorKey {
+			return icolor.NRGBA{}
+		}
+		return rgb555ToNRGBA(pal.Lookup(idx), 0xFF)
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := uint32(y*w + x)
+			var c icolor.NRGBA
+			switch poly.tex.Format {
+			case TexA3I5:
+				px := get8(i)
+				a := px >> 5
+				c = rgb555ToNRGBA(pal.Lookup(px&0x1F), a*255/7)
+			case Tex4:
+				c = indexed((get8(i/4) >> (2 * (i & 3))) & 3)
+			case Tex16:
+				c = indexed((get8(i/2) >> (4 * (i & 1))) & 0xF)
+			case Tex256:
+				c = indexed(get8(i))
+			case TexA5I3:
+				px := get8(i)
+				a := px >> 3
+				c = rgb555ToNRGBA(pal.Lookup(px&7), uint8(uint(a)*255/31))
+			case TexDirect:
+				px := uint16(get8(i*2)) | uint16(get8(i*2+1))<<8
+				if px&0x8000 != 0 {
+					c = rgb555ToNRGBA(px, 0xFF)
+				}
+			case Tex4x4:
+				px := uint16(decomp[i*2]) | uint16(decomp[i*2+1])<<8
+				if px != 0 {
+					c = rgb555ToNRGBA(px, 0xFF)
+				}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// resampleTo555 converts a replacement image into the RGB555 format used by
+// the Tex4x4 polyfiller, scaling it to the specified size (with a box filter)
+// if required. In that format, 0 means transparent; opaque pixels are
+// marked with bit 15, so that they're never confused with transparency
+// (the bit is ignored by the polyfillers when combining with vertex color).
+func resampleTo555(img image.Image, w, h int) []byte {
+	out := make([]byte, w*h*2)
+	b := img.Bounds()
+
+	for y := 0; y < h; y++ {
+		sy0 := b.Min.Y + y*b.Dy()/h
+		sy1 := b.Min.Y + (y+1)*b.Dy()/h
+		if sy1 == sy0 {
+			sy1++
+		}
+		for x := 0; x < w; x++ {
+			sx0 := b.Min.X + x*b.Dx()/w
+			sx1 := b.Min.X + (x+1)*b.Dx()/w
+			if sx1 == sx0 {
+				sx1++
+			}
+
+			var r, g, bl, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					c := icolor.NRGBAModel.Convert(img.At(sx, sy)).(icolor.NRGBA)
+					r += uint32(c.R)
+					g += uint32(c.G)
+					bl += uint32(c.B)
+					a += uint32(c.A)
+					n++
+				}
+			}
+
+			var px uint16
+			if a/n >= 0x80 {
+				px = uint16(r/n>>3) | uint16(g/n>>3)<<5 | uint16(bl/n>>3)<<10 | 0x8000
+			}
+			out[(y*w+x)*2] = uint8(px)
+			out[(y*w+x)*2+1] = uint8(px >> 8)
+		}
+	}
+	return out
+}
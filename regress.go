@@ -0,0 +1,571 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"image"
+	imgcolor "image/color"
+	"image/png"
+	"io/ioutil"
+	"ndsemu/emu/gfx"
+	log "ndsemu/emu/logger"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// RegressSuite describes a batch of regression tests, loaded from a TOML file
+// by "ndsemu regress". Each test boots a ROM, runs it for some frames with
+// scripted inputs, and compares screenshots taken at specific frames with
+// the baselines stored in a directory:
+//
+//	baselines = "baselines"   # directory of the baseline screenshots
+//	tolerance = 0.001         # fraction of pixels allowed to differ
+//	threshold = 8             # per-channel difference ignored (0-255)
+//
+//	[[test]]
+//	name = "mkds-title"
+//	rom = "roms/mkds.nds"
+//	direct-boot = true
+//	frames = 900
+//	inputs = ["400:start", "600-610:a+right"]
+//	screenshots = [300, 900]
+//
+// Paths are relative to the suite file. Inputs are in the format
+// FRAME[-LAST]:BUTTON[+BUTTON...], using the button names of macros. Instead
+// of booting, a test can start from a savestate ("state").
+type RegressSuite struct {
+	Firmware  string        `toml:"firmware"`
+	Baselines string        `toml:"baselines"`
+	Tolerance float64       `toml:"tolerance"`
+	Threshold int           `toml:"threshold"`
+	Tests     []RegressTest `toml:"test"`
+}
+
+type RegressTest struct {
+	Name        string   `toml:"name"`
+	Rom         string   `toml:"rom"`
+	State       string   `toml:"state"`
+	DirectBoot  bool     `toml:"direct-boot"`
+	Frames      int      `toml:"frames"`
+	Inputs      []string `toml:"inputs"`
+	Screenshots []int    `toml:"screenshots"`
+
+	// Overrides of the suite settings (nil: use the suite's)
+	Tolerance *float64 `toml:"tolerance"`
+	Threshold *int     `toml:"threshold"`
+}
+
+// Fixed date seen through the RTC, so that screenshots are reproducible
+var regressDate = time.Date(2010, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// LoadRegressSuite parses a suite file, resolving paths relative to it
+func LoadRegressSuite(fn string) (*RegressSuite, error) {
+	suite := &RegressSuite{Baselines: "baselines"}
+	md, err := toml.DecodeFile(fn, suite)
+	if err != nil {
+		return nil, err
+	}
+	if undec := md.Undecoded(); len(undec) > 0 {
+		return nil, fmt.Errorf("%s: unknown key %q", fn, undec[0].String())
+	}
+
+	dir := filepath.Dir(fn)
+	abs := func(fn string) string {
+		if fn == "" || filepath.IsAbs(fn) {
+			return fn
+		}
+		return filepath.Join(dir, fn)
+	}
+	suite.Firmware = abs(suite.Firmware)
+	suite.Baselines = abs(suite.Baselines)
+
+	names := make(map[string]bool)
+	for i := range suite.Tests {
+		t := &suite.Tests[i]
+		if t.Name == "" || strings.ContainsAny(t.Name, `/\`) {
+			return nil, fmt.Errorf("%s: test %d: invalid name %q", fn, i, t.Name)
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("%s: duplicated test %q", fn, t.Name)
+		}
+		names[t.Name] = true
+		if t.Rom == "" {
+			return nil, fmt.Errorf("%s: %s: missing rom", fn, t.Name)
+		}
+		t.Rom, t.State = abs(t.Rom), abs(t.State)
+		for _, in := range t.Inputs {
+			if _, _, _, err := parseRegressInput(in); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", fn, t.Name, err)
+			}
+		}
+		for _, f := range t.Screenshots {
+			if f <= 0 || f > t.Frames {
+				return nil, fmt.Errorf("%s: %s: screenshot at frame %d out of range", fn, t.Name, f)
+			}
+		}
+	}
+	return suite, nil
+}
+
+// parseRegressInput parses an input entry (FRAME[-LAST]:BUTTON[+BUTTON...])
+func parseRegressInput(in string) (first, last int, btn Buttons, err error) {
+	idx := strings.IndexByte(in, ':')
+	if idx < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid input %q", in)
+	}
+	frames := strings.SplitN(in[:idx], "-", 2)
+	if first, err = strconv.Atoi(frames[0]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid input %q: %v", in, err)
+	}
+	last = first
+	if len(frames) == 2 {
+		if last, err = strconv.Atoi(frames[1]); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid input %q: %v", in, err)
+		}
+	}
+	if first <= 0 || last < first {
+		return 0, 0, 0, fmt.Errorf("invalid input %q: bad frame range", in)
+	}
+	for _, name := range strings.Split(in[idx+1:], "+") {
+		b, found := buttonNames[strings.ToLower(name)]
+		if !found {
+			return 0, 0, 0, fmt.Errorf("invalid input %q: unknown button %q", in, name)
+		}
+		btn |= b
+	}
+	return first, last, btn, nil
+}
+
+// Result of the comparison of a screenshot with its baseline
+type regressShot struct {
+	Frame    int
+	Status   string  // "pass", "fail", "new" (no baseline)
+	Diff     float64 // fraction of different pixels
+	Baseline string  // paths relative to the report (slash-separated)
+	Actual   string
+	DiffImg  string
+}
+
+type regressResult struct {
+	Name   string
+	Status string // "pass", "fail", "error"
+	Error  string
+	Shots  []regressShot
+	Audio  string // audio output of the test (WAV), relative to the report
+}
+
+// regressRunner runs the tests of a suite on a single emulator instance,
+// which is hard-reset and fed with a new ROM for each test.
+type regressRunner struct {
+	suite  *RegressSuite
+	outdir string
+	update bool
+	tmpdir string
+
+	// Determinism audit: each test is run twice, comparing the state hashes
+	// of every frame
+	determinism bool
+	hashes      StateHashes
+
+	results []regressResult
+}
+
+func (r *regressRunner) run(t *RegressTest) (res regressResult) {
+	res = regressResult{Name: t.Name, Status: "pass"}
+	fail := func(err error) regressResult {
+		res.Status, res.Error = "error", err.Error()
+		return res
+	}
+
+	// Each test starts with a pristine copy of the firmware (settings could
+	// have been changed by the previous test), and with an empty save.
+	fw, err := ioutil.ReadFile(r.suite.Firmware)
+	if err != nil {
+		return fail(err)
+	}
+	fwsav := filepath.Join(r.tmpdir, "firmware.bin")
+	if err := ioutil.WriteFile(fwsav, fw, 0644); err != nil {
+		return fail(err)
+	}
+	savefn := filepath.Join(r.tmpdir, t.Name+".sav")
+	os.Remove(savefn)
+
+	if Emu == nil {
+		Emu = NewNDSEmulator(fwsav, false)
+	}
+	Emu.Hw.Ff.Close()
+	if err := Emu.Hw.Ff.MapFirmwareFile(fwsav); err != nil {
+		return fail(err)
+	}
+	Emu.Hw.Rtc.ResetDefaults()
+	frame := 0
+	Emu.Hw.Rtc.Clock = func() time.Time {
+		return regressDate.Add(time.Duration(frame) * time.Second / 60)
+	}
+
+	if err := Emu.loadRom(t.Rom, savefn); err != nil {
+		return fail(err)
+	}
+	if t.State != "" {
+		if err := Emu.LoadStateFile(t.State); err != nil {
+			return fail(err)
+		}
+	} else if t.DirectBoot {
+		if err := Emu.DirectBoot(fwsav); err != nil {
+			return fail(err)
+		}
+	}
+
+	var inputs []func(frame int) Buttons
+	for _, in := range t.Inputs {
+		first, last, btn, _ := parseRegressInput(in)
+		inputs = append(inputs, func(frame int) Buttons {
+			if frame >= first && frame <= last {
+				return btn
+			}
+			return 0
+		})
+	}
+
+	// The audio output is dumped through a WAV sink, so that it can be
+	// compared across runs
+	res.Audio = path.Join("audio", t.Name+".wav")
+	if err := os.MkdirAll(filepath.Join(r.outdir, "audio"), 0755); err != nil {
+		return fail(err)
+	}
+	wav, err := NewWavAudioSink(filepath.Join(r.outdir, res.Audio))
+	if err != nil {
+		return fail(err)
+	}
+	defer wav.Close()
+
+	w, h := Emu.Layout().Size()
+	screen := gfx.NewBufferMem(w, h)
+	samples := make([]int16, (cAudioFreq/60+1)*2)
+	r.hashes = nil
+	for frame = 1; frame <= t.Frames; frame++ {
+		var btn Buttons
+		for _, in := range inputs {
+			btn |= in(frame)
+		}
+		Emu.Hw.Key.SetButtons(btn)
+		audio := samples[:audioFrameSamples(frame-1)*2]
+		if Emu.RunOneFrame(screen, audio) {
+			return fail(fmt.Errorf("system powered off at frame %d", frame))
+		}
+		wav.SubmitAudio(audio)
+		if r.determinism {
+			r.hashes = append(r.hashes, Emu.StateHash())
+		}
+
+		for _, f := range t.Screenshots {
+			if f == frame {
+				shot, err := r.compare(t, frame, screenshotImage(screen, w, h))
+				if err != nil {
+					return fail(err)
+				}
+				if shot.Status != "pass" {
+					res.Status = "fail"
+				}
+				res.Shots = append(res.Shots, shot)
+			}
+		}
+	}
+	return res
+}
+
+// runTwice runs a test twice, checking that the emulation is deterministic:
+// the test fails if the state of the machine at the end of any frame differs
+// between the two runs.
+func (r *regressRunner) runTwice(t *RegressTest) regressResult {
+	res := r.run(t)
+	if res.Status == "error" {
+		return res
+	}
+	first := r.hashes
+	if res2 := r.run(t); res2.Status == "error" {
+		return res2
+	}
+	if frame := first.FirstDivergence(r.hashes); frame != 0 {
+		res.Status = "fail"
+		res.Error = fmt.Sprintf("nondeterministic: state diverged at frame %d", frame)
+	}
+	return res
+}
+
+func screenshotImage(screen gfx.Buffer, w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		line := screen.Line(y)
+		for x := 0; x < w; x++ {
+			pix := line.Get32(x)
+			img.SetRGBA(x, y, imgcolor.RGBA{uint8(pix), uint8(pix >> 8), uint8(pix >> 16), 0xFF})
+		}
+	}
+	return img
+}
+
+// compare compares a screenshot with its baseline, saving the images for
+// the report
+func (r *regressRunner) compare(t *RegressTest, frame int, img *image.RGBA) (regressShot, error) {
+	name := fmt.Sprintf("%s-%d", t.Name, frame)
+	shot := regressShot{
+		Frame:    frame,
+		Baseline: path.Join("baseline", name+".png"),
+		Actual:   path.Join("actual", name+".png"),
+	}
+	if err := writePng(filepath.Join(r.outdir, shot.Actual), img); err != nil {
+		return shot, err
+	}
+
+	basefn := filepath.Join(r.suite.Baselines, name+".png")
+	if r.update {
+		if err := writePng(basefn, img); err != nil {
+			return shot, err
+		}
+	}
+	base, err := readPng(basefn)
+	if os.IsNotExist(err) {
+		shot.Status, shot.Baseline = "new", ""
+		return shot, nil
+	} else if err != nil {
+		return shot, err
+	}
+	if err := writePng(filepath.Join(r.outdir, shot.Baseline), base); err != nil {
+		return shot, err
+	}
+
+	tolerance, threshold := r.suite.Tolerance, r.suite.Threshold
+	if t.Tolerance != nil {
+		tolerance = *t.Tolerance
+	}
+	if t.Threshold != nil {
+		threshold = *t.Threshold
+	}
+
+	diff, ndiff := diffImages(base, img, threshold)
+	shot.Diff = float64(ndiff) / float64(img.Bounds().Dx()*img.Bounds().Dy())
+	shot.Status = "pass"
+	if shot.Diff > tolerance {
+		shot.Status = "fail"
+	}
+	if ndiff > 0 {
+		shot.DiffImg = path.Join("diff", name+".png")
+		if err := writePng(filepath.Join(r.outdir, shot.DiffImg), diff); err != nil {
+			return shot, err
+		}
+	}
+	return shot, nil
+}
+
+// diffImages returns an image highlighting in red the pixels that differ by
+// more than threshold in any channel (the others are dimmed), and their count.
+// Images of different sizes are completely different.
+func diffImages(a image.Image, b *image.RGBA, threshold int) (*image.RGBA, int) {
+	bounds := b.Bounds()
+	diff := image.NewRGBA(bounds)
+	if a.Bounds() != bounds {
+		for i := 0; i < len(diff.Pix); i += 4 {
+			diff.Pix[i], diff.Pix[i+3] = 0xFF, 0xFF
+		}
+		return diff, bounds.Dx() * bounds.Dy()
+	}
+
+	abs := func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+	n := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r0, g0, b0, _ := a.At(x, y).RGBA()
+			c := b.RGBAAt(x, y)
+			d := abs(int(r0>>8) - int(c.R))
+			if v := abs(int(g0>>8) - int(c.G)); v > d {
+				d = v
+			}
+			if v := abs(int(b0>>8) - int(c.B)); v > d {
+				d = v
+			}
+			if d > threshold {
+				diff.SetRGBA(x, y, imgcolor.RGBA{0xFF, 0, 0, 0xFF})
+				n++
+			} else {
+				diff.SetRGBA(x, y, imgcolor.RGBA{c.R / 4, c.G / 4, c.B / 4, 0xFF})
+			}
+		}
+	}
+	return diff, n
+}
+
+func writePng(fn string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readPng(fn string) (image.Image, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+var regressReportTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>ndsemu regression report</title>
+<style>
+body { font-family: sans-serif; }
+td, th { padding: 4px 8px; vertical-align: top; text-align: left; }
+img { image-rendering: pixelated; }
+.pass { color: green; } .fail, .error, .new { color: red; }
+</style></head><body>
+<h1>ndsemu regression report</h1>
+<p>{{.Suite}}: {{.Passed}}/{{len .Results}} tests passed</p>
+<table>
+<tr><th>Test</th><th>Status</th><th>Frame</th><th>Diff</th><th>Baseline</th><th>Actual</th><th>Difference</th></tr>
+{{range .Results}}{{$test := .}}
+{{if .Shots}}{{range .Shots}}<tr>
+<td>{{$test.Name}}{{if $test.Audio}} (<a href="{{$test.Audio}}">audio</a>){{end}}</td><td class="{{.Status}}">{{.Status}}</td><td>{{.Frame}}</td>
+<td>{{printf "%.4f%%" .Percent}}</td>
+<td>{{if .Baseline}}<img src="{{.Baseline}}">{{else}}no baseline{{end}}</td>
+<td><img src="{{.Actual}}"></td>
+<td>{{if .DiffImg}}<img src="{{.DiffImg}}">{{end}}</td>
+</tr>{{end}}{{else}}<tr>
+<td>{{.Name}}</td><td class="{{.Status}}">{{.Status}}</td><td colspan="5">{{.Error}}</td>
+</tr>{{end}}
+{{if and .Shots .Error}}<tr><td></td><td class="error">error</td><td colspan="5">{{.Error}}</td></tr>{{end}}
+{{end}}
+</table></body></html>
+`))
+
+// Percent returns the fraction of different pixels, as a percentage
+func (s regressShot) Percent() float64 { return s.Diff * 100 }
+
+func (r *regressRunner) writeReport(suitefn string) error {
+	passed := 0
+	for _, res := range r.results {
+		if res.Status == "pass" {
+			passed++
+		}
+	}
+	f, err := os.Create(filepath.Join(r.outdir, "index.html"))
+	if err != nil {
+		return err
+	}
+	err = regressReportTmpl.Execute(f, map[string]interface{}{
+		"Suite":   suitefn,
+		"Passed":  passed,
+		"Results": r.results,
+	})
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// regressMain implements "ndsemu regress": it runs the specified suite
+// files, and returns the exit code of the program (1 if any test failed).
+func regressMain(args []string) int {
+	fs := flag.NewFlagSet("regress", flag.ExitOnError)
+	update := fs.Bool("update", false, "save the screenshots as new baselines")
+	report := fs.String("report", "regress-report", "directory where the HTML report is written")
+	determinism := fs.Bool("determinism", false, "run each test twice, and fail if the machine state diverges between the runs (reporting the first divergent frame)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s regress [options] suites.toml...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	tmpdir, err := ioutil.TempDir("", "ndsemu-regress")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(tmpdir)
+
+	exit := 0
+	for i, suitefn := range fs.Args() {
+		suite, err := LoadRegressSuite(suitefn)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		if suite.Firmware == "" {
+			bindir, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+			suite.Firmware = filepath.Join(bindir, cFirmwareDefault)
+		}
+
+		outdir := *report
+		if fs.NArg() > 1 {
+			outdir = filepath.Join(outdir, strconv.Itoa(i))
+		}
+		r := &regressRunner{suite: suite, outdir: outdir, update: *update, tmpdir: tmpdir, determinism: *determinism}
+		if err := os.MkdirAll(outdir, 0755); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		// Fatal errors in emulation exit the program: make sure the report
+		// still shows the tests run so far, and which one failed.
+		var cur *RegressTest
+		removeHook := log.AddFatalHook(func(msg string) {
+			if cur != nil {
+				r.results = append(r.results, regressResult{Name: cur.Name, Status: "error", Error: msg})
+			}
+			r.writeReport(suitefn)
+		})
+
+		for j := range suite.Tests {
+			cur = &suite.Tests[j]
+			var res regressResult
+			if r.determinism {
+				res = r.runTwice(cur)
+			} else {
+				res = r.run(cur)
+			}
+			r.results = append(r.results, res)
+			fmt.Printf("%-6s %s %s\n", strings.ToUpper(res.Status), cur.Name, res.Error)
+			if res.Status != "pass" {
+				exit = 1
+			}
+		}
+		cur = nil
+		removeHook()
+
+		if err := r.writeReport(suitefn); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("report written to %s\n", filepath.Join(outdir, "index.html"))
+	}
+
+	if Emu != nil {
+		Emu.Shutdown()
+	}
+	return exit
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"image"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegressSuite(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "suite.toml")
+	ioutil.WriteFile(fn, []byte(`
+tolerance = 0.01
+
+[[test]]
+name = "title"
+rom = "roms/game.nds"
+frames = 100
+inputs = ["10:start", "20-30:a+Right"]
+screenshots = [50, 100]
+threshold = 0
+`), 0644)
+
+	suite, err := LoadRegressSuite(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suite.Baselines != filepath.Join(dir, "baselines") {
+		t.Errorf("baselines: %q", suite.Baselines)
+	}
+	test := suite.Tests[0]
+	if test.Rom != filepath.Join(dir, "roms/game.nds") {
+		t.Errorf("rom: %q", test.Rom)
+	}
+	if test.Tolerance != nil || test.Threshold == nil || *test.Threshold != 0 {
+		t.Errorf("invalid overrides: %v %v", test.Tolerance, test.Threshold)
+	}
+
+	first, last, btn, err := parseRegressInput(test.Inputs[1])
+	if err != nil || first != 20 || last != 30 || btn != ButtonA|ButtonRight {
+		t.Errorf("input: %d %d %x %v", first, last, btn, err)
+	}
+
+	for _, bad := range []string{
+		"[[test]]\nname=\"a\"\nrom=\"x\"\ninputs=[\"5:z\"]",
+		"[[test]]\nname=\"a\"\nrom=\"x\"\nframes=10\nscreenshots=[11]",
+		"[[test]]\nname=\"a/b\"\nrom=\"x\"",
+		"[[test]]\nname=\"a\"\nrom=\"x\"\n[[test]]\nname=\"a\"\nrom=\"y\"",
+		"speed = 1",
+	} {
+		ioutil.WriteFile(fn, []byte(bad), 0644)
+		if _, err := LoadRegressSuite(fn); err == nil {
+			t.Errorf("no error for suite: %q", bad)
+		}
+	}
+}
+
+func TestRegressReport(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	b := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	b.Pix[0] = 10  // below threshold
+	b.Pix[16] = 20 // above threshold
+	if _, n := diffImages(a, b, 16); n != 1 {
+		t.Errorf("diff: %d pixels, want 1", n)
+	}
+	if _, n := diffImages(image.NewRGBA(image.Rect(0, 0, 2, 2)), b, 16); n != 16 {
+		t.Errorf("diff with size mismatch: %d pixels, want 16", n)
+	}
+
+	r := &regressRunner{outdir: t.TempDir()}
+	r.results = []regressResult{
+		{Name: "ok", Status: "pass", Shots: []regressShot{{Frame: 10, Status: "pass", Actual: "actual/ok-10.png"}}},
+		{Name: "broken", Status: "error", Error: "cannot open ROM"},
+	}
+	if err := r.writeReport("suite.toml"); err != nil {
+		t.Fatal(err)
+	}
+	html, _ := ioutil.ReadFile(filepath.Join(r.outdir, "index.html"))
+	for _, s := range []string{"1/2 tests passed", "actual/ok-10.png", "cannot open ROM"} {
+		if !strings.Contains(string(html), s) {
+			t.Errorf("report does not contain %q", s)
+		}
+	}
+}
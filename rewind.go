@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	log "ndsemu/emu/logger"
+	"time"
+)
+
+// Number of frames between the snapshots kept by Rewind. Reverse execution
+// replays up to this number of frames, so it's a trade-off between the
+// memory used by the history and the time taken by each reverse step.
+const cRewindEvery = 10
+
+// rewindDebugger is the interface to the debugger used by Rewind (see
+// debugger.Rewinder)
+type rewindDebugger interface {
+	Steps() []uint64
+	RewindRequest() (cpu int, n uint64, oldest bool, ok bool)
+	Replay(steps []uint64)
+	RewindFailed(err error)
+}
+
+type rewindSnap struct {
+	frame int
+	state []byte   // savestate (see SaveState)
+	steps []uint64 // instructions executed by each CPU (see rewindDebugger)
+}
+
+// rewindInput is the input of a frame, as set by the frontend before the
+// frame is emulated
+type rewindInput struct {
+	now          time.Time // RTC clock
+	buttons      Buttons
+	penDown      bool
+	lidClosed    bool
+	penX, penY   int
+	tscDown      bool
+	pressure     float64
+	prevX, prevY int
+	interp       bool
+	setAt        int64
+}
+
+// Rewind keeps the recent history of the emulation, for reverse execution
+// in the debugger: a savestate every cRewindEvery frames, and the input of
+// every frame since the oldest savestate. After a savestate is restored, the
+// frames up to the one where the rewind was requested are emulated again
+// with the recorded input, so that the emulation follows the same path (see
+// debugger.Rewinder). The RTC returns the time at which each frame began,
+// so that it is replayed too.
+//
+// Replay is exact as long as the emulation only depends on the recorded
+// input. Wireless, the serial port and the slot-2 sensors poll the host,
+// so a game using them might diverge; and since the save memory is not part
+// of savestates, writes to it are not undone.
+type Rewind struct {
+	dbg  rewindDebugger
+	keep int // number of snapshots kept
+
+	snaps       []rewindSnap
+	inputs      []rewindInput // input of each frame, since the oldest snapshot
+	replayUntil int           // frames before this one are replayed
+	now         time.Time     // time of the current frame (see rtcClock)
+}
+
+// NewRewind creates a history of the specified duration (in frames)
+func NewRewind(dbg rewindDebugger, frames int) *Rewind {
+	keep := frames / cRewindEvery
+	if keep < 1 {
+		keep = 1
+	}
+	return &Rewind{dbg: dbg, keep: keep}
+}
+
+// CanRewind implements debugger.Rewinder
+func (r *Rewind) CanRewind(cpu int, n uint64) bool {
+	return r.find(cpu, n) >= 0
+}
+
+// find returns the index of the latest snapshot taken before the specified
+// CPU executed its n-th instruction (-1: none)
+func (r *Rewind) find(cpu int, n uint64) int {
+	for i := len(r.snaps) - 1; i >= 0; i-- {
+		if r.snaps[i].steps[cpu] < n {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Rewind) rtcClock() time.Time {
+	return r.now
+}
+
+// BeginFrame must be called before emulating each frame. It takes the
+// snapshots, and records the input of the frame (or sets it, while
+// replaying).
+func (r *Rewind) BeginFrame(emu *NDSEmulator) {
+	frame := emu.framecount
+	if n := len(r.snaps); n == 0 || frame-r.snaps[n-1].frame >= cRewindEvery {
+		r.snapshot(emu)
+	}
+	if len(r.snaps) == 0 {
+		r.now = time.Now()
+		return
+	}
+
+	idx := frame - r.snaps[0].frame
+	if frame < r.replayUntil && idx < len(r.inputs) {
+		r.setInput(emu, &r.inputs[idx])
+		return
+	}
+	r.replayUntil = 0
+	r.now = time.Now()
+	r.inputs = append(r.inputs[:idx], r.input(emu))
+}
+
+func (r *Rewind) snapshot(emu *NDSEmulator) {
+	var buf bytes.Buffer
+	if err := emu.SaveState(&buf); err != nil {
+		// Retry at the next frame if the hardware is busy; otherwise,
+		// savestates are not supported in the current mode
+		if err != errStateBusy {
+			log.ModEmu.InfoZ("cannot take rewind snapshot").Error("err", err).End()
+		}
+		return
+	}
+	r.snaps = append(r.snaps, rewindSnap{emu.framecount, buf.Bytes(), r.dbg.Steps()})
+	if len(r.snaps) > r.keep {
+		drop := r.snaps[1].frame - r.snaps[0].frame
+		r.snaps = r.snaps[1:]
+		if drop > len(r.inputs) {
+			drop = len(r.inputs)
+		}
+		r.inputs = r.inputs[drop:]
+	}
+}
+
+func (r *Rewind) input(emu *NDSEmulator) rewindInput {
+	key, tsc := emu.Hw.Key, emu.Hw.Tsc
+	return rewindInput{
+		now:       r.now,
+		buttons:   key.buttons,
+		penDown:   key.penDown,
+		lidClosed: key.lidClosed,
+		penX:      tsc.penX,
+		penY:      tsc.penY,
+		tscDown:   tsc.penDown,
+		pressure:  tsc.pressure,
+		prevX:     tsc.prevX,
+		prevY:     tsc.prevY,
+		interp:    tsc.interp,
+		setAt:     tsc.setAt,
+	}
+}
+
+func (r *Rewind) setInput(emu *NDSEmulator, in *rewindInput) {
+	key, tsc := emu.Hw.Key, emu.Hw.Tsc
+	r.now = in.now
+	key.buttons, key.penDown, key.lidClosed = in.buttons, in.penDown, in.lidClosed
+	tsc.penX, tsc.penY, tsc.penDown = in.penX, in.penY, in.tscDown
+	tsc.pressure = in.pressure
+	tsc.prevX, tsc.prevY = in.prevX, in.prevY
+	tsc.interp, tsc.setAt = in.interp, in.setAt
+}
+
+// EndFrame must be called after emulating each frame. It serves the rewind
+// requested by the debugger, if any, restoring a snapshot.
+func (r *Rewind) EndFrame(emu *NDSEmulator) {
+	cpu, n, oldest, ok := r.dbg.RewindRequest()
+	if !ok {
+		return
+	}
+	idx := 0
+	if !oldest {
+		idx = r.find(cpu, n)
+	}
+	if idx < 0 || len(r.snaps) == 0 {
+		r.dbg.RewindFailed(errors.New("not enough history"))
+		return
+	}
+
+	// The frames up to the current one are replayed with the recorded input
+	if emu.framecount > r.replayUntil {
+		r.replayUntil = emu.framecount
+	}
+	snap := r.snaps[idx]
+	if err := emu.LoadState(bytes.NewReader(snap.state)); err != nil {
+		r.dbg.RewindFailed(err)
+		return
+	}
+	r.snaps = r.snaps[:idx+1]
+	r.dbg.Replay(snap.steps)
+}
@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+// testRewindDebugger simulates the debugger, with instruction counters that
+// advance by 100 at each frame
+type testRewindDebugger struct {
+	emu      *NDSEmulator
+	req      bool
+	oldest   bool
+	n        uint64
+	replayed []uint64
+	failed   error
+}
+
+func (d *testRewindDebugger) Steps() []uint64 {
+	n := uint64(d.emu.framecount) * 100
+	return []uint64{n, n}
+}
+
+func (d *testRewindDebugger) RewindRequest() (int, uint64, bool, bool) {
+	req := d.req
+	d.req = false
+	return 1, d.n, d.oldest, req
+}
+
+func (d *testRewindDebugger) Replay(steps []uint64)  { d.replayed = steps }
+func (d *testRewindDebugger) RewindFailed(err error) { d.failed = err }
+
+func TestRewindReplay(t *testing.T) {
+	emu := newTestEmulator(t)
+	dbg := &testRewindDebugger{emu: emu}
+	emu.rewind = NewRewind(dbg, 4*cRewindEvery)
+	emu.Hw.Rtc.Clock = emu.rewind.rtcClock
+
+	// Record 55 frames, with different input in each one
+	hashes := make(map[int]uint64)
+	for i := 0; i < 55; i++ {
+		emu.Hw.Key.SetButtons(Buttons(i))
+		emu.Hw.Tsc.SetPen(i&1 != 0, i, 2*i)
+		runFrames(emu, 1)
+		hashes[emu.framecount] = emu.StateHash()
+	}
+	if n := len(emu.rewind.snaps); n != 4 || emu.rewind.snaps[0].frame != 20 {
+		t.Fatalf("got %d snapshots, the oldest at frame %d", n, emu.rewind.snaps[0].frame)
+	}
+	if !emu.rewind.CanRewind(1, 2001) || emu.rewind.CanRewind(1, 2000) {
+		t.Errorf("invalid history limit")
+	}
+
+	// Rewind to the snapshot before instruction 3500 (frame 30), and
+	// replay: the input is ignored up to the frame of the rewind
+	dbg.req, dbg.n = true, 3500
+	emu.Hw.Key.SetButtons(0xFFF)
+	runFrames(emu, 1)
+	if emu.framecount != 30 || len(dbg.replayed) != 2 || dbg.replayed[1] != 3000 {
+		t.Fatalf("rewound to frame %d, steps %v", emu.framecount, dbg.replayed)
+	}
+	for emu.framecount < 56 {
+		emu.Hw.Key.SetButtons(0xFFF)
+		emu.Hw.Tsc.SetPen(false, 0, 0)
+		runFrames(emu, 1)
+		if want := Buttons(emu.framecount - 1); emu.framecount <= 55 && emu.Hw.Key.buttons != want {
+			t.Fatalf("frame %d: got buttons %x, want %x", emu.framecount, emu.Hw.Key.buttons, want)
+		}
+		if h, ok := hashes[emu.framecount]; ok && emu.StateHash() != h {
+			t.Fatalf("frame %d: replay diverged", emu.framecount)
+		}
+	}
+	if emu.Hw.Key.buttons != 0xFFF {
+		t.Errorf("live input not restored after replay")
+	}
+
+	// Rewinds beyond the history fail
+	dbg.req, dbg.n = true, 100
+	runFrames(emu, 1)
+	if dbg.failed == nil {
+		t.Errorf("rewind beyond the history not reported")
+	}
+}
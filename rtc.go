@@ -95,17 +95,27 @@ func (s *HwSerial3W) ReadSERIAL(_ uint8) uint8 {
 type HwRtc struct {
 	HwSerial3W
 
+	// Clock returns the current date and time (nil: host clock). It can be
+	// replaced to make runs reproducible.
+	Clock func() time.Time
+
 	regStatus1 uint8
 	regStatus2 uint8
 
 	writing bool
 	buf     []byte
 	idx     int
-	alarms  [2]struct {
-		dow       byte
-		hour      byte
-		minOrFreq byte
-	}
+	alarms  [2]rtcAlarm
+}
+
+// rtcAlarm is the configuration of an alarm. In each field, bit 7 enables
+// the comparison with the current time (otherwise, the field is ignored).
+type rtcAlarm struct {
+	dow       byte
+	hour      byte
+	minOrFreq byte
+
+	fired int64 // minute (unix time) in which the alarm last fired
 }
 
 func NewHwRtc() *HwRtc {
@@ -117,6 +127,17 @@ func NewHwRtc() *HwRtc {
 	return rtc
 }
 
+// Reset resets the serial interface of the RTC, as happens when the console
+// is reset. The date/time and the status registers are battery-backed, so
+// they are preserved.
+func (rtc *HwRtc) Reset() {
+	rtc.HwSerial3W = HwSerial3W{dev: rtc}
+	hwio.MustInitRegs(&rtc.HwSerial3W)
+	rtc.writing = false
+	rtc.buf = nil
+	rtc.idx = 0
+}
+
 func (rtc *HwRtc) ResetDefaults() {
 	rtc.regStatus1 = 0x80
 	rtc.regStatus2 = 0x00
@@ -152,6 +173,79 @@ func (rtc *HwRtc) alarm1HasFreq() bool {
 	return rtc.regStatus2&(1<<2) == 0
 }
 
+func (rtc *HwRtc) now() time.Time {
+	if rtc.Clock != nil {
+		return rtc.Clock()
+	}
+	return time.Now()
+}
+
+// DateTime returns the current date and time, as encoded in the datetime
+// register: year, month, day, weekday, hour, minute and second.
+func (rtc *HwRtc) DateTime() [7]byte {
+	now := rtc.now()
+	return [7]byte{
+		rtc.bcd(uint(now.Year() - 2000)),
+		rtc.bcd(uint(now.Month())),
+		rtc.bcd(uint(now.Day())),
+		rtc.bcd(uint(now.Weekday())),
+		rtc.hour(now),
+		rtc.bcd(uint(now.Minute())),
+		rtc.bcd(uint(now.Second())),
+	}
+}
+
+// hour returns the current hour, as encoded in the time registers
+func (rtc *HwRtc) hour(now time.Time) uint8 {
+	if rtc.regStatus1&2 != 0 {
+		// 24H mode
+		return rtc.bcd(uint(now.Hour()))
+	}
+	// 12H mode, with 12:00 that becomes 0pm instead of 12pm (as per
+	// normale human convention)
+	hour := rtc.bcd(uint(now.Hour() % 12))
+	if now.Hour() >= 12 {
+		hour |= 0x40
+	}
+	return hour
+}
+
+// PollAlarms checks the enabled alarms against the current time (alarm 1
+// is enabled in alarm mode of INT1, alarm 2 by INT2AE). It sets the
+// interrupt flags in status register 1 and returns true if an alarm fired,
+// meaning that the RTC interrupt must be raised; each alarm fires once in
+// the matching minute. It must be called periodically (eg: once per frame).
+func (rtc *HwRtc) PollAlarms() bool {
+	now := rtc.now()
+	minute := now.Unix() / 60
+	enabled := [2]bool{rtc.regStatus2&0xF == 4, rtc.regStatus2&(1<<6) != 0}
+
+	fired := false
+	for i := range rtc.alarms {
+		a := &rtc.alarms[i]
+		if !enabled[i] || a.fired == minute {
+			continue
+		}
+
+		// In 24H mode, the PM flag is ignored
+		hmask := uint8(0x7F)
+		if rtc.regStatus1&2 != 0 {
+			hmask = 0x3F
+		}
+		if (a.dow&0x80 != 0 && a.dow&7 != uint8(now.Weekday())) ||
+			(a.hour&0x80 != 0 && a.hour&hmask != rtc.hour(now)&hmask) ||
+			(a.minOrFreq&0x80 != 0 && a.minOrFreq&0x7F != rtc.bcd(uint(now.Minute()))) {
+			continue
+		}
+
+		a.fired = minute
+		rtc.regStatus1 |= 1 << uint(4+i)
+		modRtc.Infof("alarm%d fired: %x", i+1, []byte{a.dow, a.hour, a.minOrFreq})
+		fired = true
+	}
+	return fired
+}
+
 const (
 	RtcRegSr1 = iota
 	RtcRegAlarm1
@@ -188,27 +282,24 @@ func (rtc *HwRtc) writeReg(val uint8) {
 	case RtcRegSr2:
 		rtc.regStatus2 = val
 		modRtc.Infof("write sr2: %02x", val)
+		// Only the alarm mode of INT1 is implemented (see PollAlarms)
+		if mode := val & 0xF; mode != 0 && mode != 4 {
+			modRtc.Errorf("INT1 mode not implemented: %x", mode)
+		}
 	case RtcRegAlarm1:
 		if len(rtc.buf) == 1 {
 			rtc.alarms[0].minOrFreq = rtc.buf[0]
-			if rtc.buf[0] != 0 {
-				modRtc.Errorf("alarm1 set but not implemented: %x", rtc.buf)
-			}
 		} else {
 			rtc.alarms[0].dow = rtc.buf[0]
 			rtc.alarms[0].hour = rtc.buf[1]
 			rtc.alarms[0].minOrFreq = rtc.buf[2]
-			if rtc.buf[0] != 0 || rtc.buf[1] != 0 || rtc.buf[2] != 0 {
-				modRtc.Errorf("alarm1 set but not implemented: %x", rtc.buf)
-			}
 		}
+		rtc.alarms[0].fired = 0
 	case RtcRegAlarm2:
 		rtc.alarms[1].dow = rtc.buf[0]
 		rtc.alarms[1].hour = rtc.buf[1]
 		rtc.alarms[1].minOrFreq = rtc.buf[2]
-		if rtc.buf[0] != 0 || rtc.buf[1] != 0 || rtc.buf[2] != 0 {
-			modRtc.Errorf("alarm2 set but not implemented: %x", rtc.buf)
-		}
+		rtc.alarms[1].fired = 0
 	default:
 		modRtc.Warnf("unimplemented register write: %q=%x", rtcRegnames[rtc.idx], rtc.buf)
 	}
@@ -241,41 +332,18 @@ func (rtc *HwRtc) WriteData(val uint8) {
 	switch reg {
 	case RtcRegSr1:
 		rtc.buf = append(rtc.buf, rtc.regStatus1)
-		// Bit 4-7 are auto-cleared after read
-		// (though currently we don't set them in our emulation...)
+		// Bit 4-7 are auto-cleared after read (we only set the INT1/INT2
+		// flags, see PollAlarms)
 		rtc.regStatus1 &= 0x0F
 	case RtcRegSr2:
 		rtc.buf = append(rtc.buf, rtc.regStatus2)
 
-	case RtcRegDatetime, RtcRegTime:
-		now := time.Now()
-
-		var hour uint8
-		if rtc.regStatus1&2 != 0 {
-			// 24H mode
-			hour = rtc.bcd(uint(now.Hour()))
-		} else {
-			// 12H mode, with 12:00 that becomes 0pm instead of 12pm (as per
-			// normale human convention)
-			hour = rtc.bcd(uint(now.Hour() % 12))
-			if now.Hour() >= 12 {
-				hour |= 0x40
-			}
-		}
-
-		if reg == 2 { // datetime contains also the date
-			rtc.buf = append(rtc.buf,
-				rtc.bcd(uint(now.Year()-2000)),
-				rtc.bcd(uint(now.Month())),
-				rtc.bcd(uint(now.Day())),
-				rtc.bcd(uint(now.Weekday())),
-			)
-		}
-		rtc.buf = append(rtc.buf,
-			hour,
-			rtc.bcd(uint(now.Minute())),
-			rtc.bcd(uint(now.Second())),
-		)
+	case RtcRegDatetime:
+		dt := rtc.DateTime()
+		rtc.buf = append(rtc.buf, dt[:]...)
+	case RtcRegTime:
+		dt := rtc.DateTime()
+		rtc.buf = append(rtc.buf, dt[4:]...)
 
 	case RtcRegAlarm1:
 		if rtc.alarm1HasFreq() {
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRtcAlarms(t *testing.T) {
+	now := time.Date(2020, 3, 4, 15, 30, 10, 0, time.UTC) // wednesday
+	rtc := NewHwRtc()
+	rtc.Clock = func() time.Time { return now }
+
+	// Alarm 2 at 15:30 (24H mode), any day of week
+	rtc.regStatus1 = 2
+	rtc.alarms[1] = rtcAlarm{dow: 0x01, hour: 0x80 | 0x15, minOrFreq: 0x80 | 0x30}
+	if rtc.PollAlarms() {
+		t.Fatal("alarm fired while disabled")
+	}
+	rtc.regStatus2 = 1 << 6
+	if !rtc.PollAlarms() {
+		t.Fatal("alarm not fired")
+	}
+	if rtc.regStatus1&(1<<5) == 0 {
+		t.Errorf("INT2 flag not set: %02x", rtc.regStatus1)
+	}
+	if rtc.PollAlarms() {
+		t.Error("alarm fired twice in the same minute")
+	}
+	now = now.Add(time.Minute)
+	if rtc.PollAlarms() {
+		t.Error("alarm fired at the wrong time")
+	}
+
+	// Alarm 1 on wednesday at 3pm (12H mode)
+	rtc.regStatus1, rtc.regStatus2 = 0, 4
+	rtc.alarms[0] = rtcAlarm{dow: 0x80 | 3, hour: 0x80 | 0x40 | 0x03}
+	if !rtc.PollAlarms() || rtc.regStatus1&(1<<4) == 0 {
+		t.Errorf("alarm1 not fired: sr1=%02x", rtc.regStatus1)
+	}
+}
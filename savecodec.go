@@ -0,0 +1,348 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/howeyc/crc16"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// SaveCodec runs a "save codec": a Lua script that converts the contents of
+// the save memory of a game from and to a structured value, so that save
+// editors can be written as scripts and run inside the emulator (see the
+// saveexport and saveimport control commands). Scripts are per game, and
+// are looked up as GAMECODE.lua in the directory specified with -save-codecs.
+//
+// A script defines two global functions: decode(), which returns a table
+// describing the save, and encode(t), which writes back a table in the same
+// format (possibly edited). The save memory is accessed through the "save"
+// module, with offsets in bytes and little-endian integers:
+//
+//	save.size()                      size of the save memory
+//	save.read(off, n)                raw bytes (as a string)
+//	save.write(off, s)               write raw bytes
+//	save.u8(off), u16, u32           read an unsigned integer
+//	save.set_u8(off, v), set_u16...  write an unsigned integer
+//	save.str(off, n)                 read an ASCII string (up to NUL)
+//	save.set_str(off, n, s)          write an ASCII string (NUL-padded)
+//	save.utf16(off, n)               read a string of n UTF-16 units (up to NUL)
+//	save.set_utf16(off, n, s)        write a UTF-16 string (NUL-padded)
+//	save.crc16(off, n [, init])      CRC-16 as computed by the BIOS (GetCRC16;
+//	                                 init defaults to 0xFFFF)
+//
+// Values are exchanged as JSON: tables with keys 1..n become arrays, other
+// tables become objects. Only the base, table, string and math libraries
+// are available to scripts.
+type SaveCodec struct {
+	ls  *lua.LState
+	bkp *HwBackupRam
+}
+
+// SaveCodecFile returns the path of the codec script for the specified game
+func SaveCodecFile(dir string, gamecode string) string {
+	return filepath.Join(dir, gamecode+".lua")
+}
+
+// LoadSaveCodec runs the codec script in the specified file, bound to the
+// specified save memory. The codec must be released with Close.
+func LoadSaveCodec(fn string, bkp *HwBackupRam) (*SaveCodec, error) {
+	if _, err := os.Stat(fn); err != nil {
+		return nil, fmt.Errorf("no save codec: %v", err)
+	}
+
+	c := &SaveCodec{
+		ls:  lua.NewState(lua.Options{SkipOpenLibs: true}),
+		bkp: bkp,
+	}
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		c.ls.Push(c.ls.NewFunction(lib.open))
+		c.ls.Push(lua.LString(lib.name))
+		c.ls.Call(1, 0)
+	}
+	c.ls.SetGlobal("save", c.ls.SetFuncs(c.ls.NewTable(), map[string]lua.LGFunction{
+		"size":      c.luaSize,
+		"read":      c.luaRead,
+		"write":     c.luaWrite,
+		"u8":        c.luaUint(1),
+		"u16":       c.luaUint(2),
+		"u32":       c.luaUint(4),
+		"set_u8":    c.luaSetUint(1),
+		"set_u16":   c.luaSetUint(2),
+		"set_u32":   c.luaSetUint(4),
+		"str":       c.luaStr,
+		"set_str":   c.luaSetStr,
+		"utf16":     c.luaUtf16,
+		"set_utf16": c.luaSetUtf16,
+		"crc16":     c.luaCrc16,
+	}))
+
+	if err := c.ls.DoFile(fn); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close releases the Lua interpreter
+func (c *SaveCodec) Close() {
+	c.ls.Close()
+}
+
+// Decode runs the decode function of the script, and returns its result
+// converted into a value that can be encoded as JSON
+func (c *SaveCodec) Decode() (interface{}, error) {
+	if err := c.call("decode", 1); err != nil {
+		return nil, err
+	}
+	ret := c.ls.Get(-1)
+	c.ls.Pop(1)
+	return luaToGo(ret, 0)
+}
+
+// Encode runs the encode function of the script on the specified value (as
+// decoded from JSON), which updates the save memory
+func (c *SaveCodec) Encode(v interface{}) error {
+	lv, err := goToLua(c.ls, v)
+	if err != nil {
+		return err
+	}
+	return c.call("encode", 0, lv)
+}
+
+func (c *SaveCodec) call(name string, nret int, args ...lua.LValue) error {
+	fn, ok := c.ls.GetGlobal(name).(*lua.LFunction)
+	if !ok {
+		return fmt.Errorf("save codec: function %s not defined", name)
+	}
+	return c.ls.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...)
+}
+
+// bytes returns the n bytes of save memory at the offset passed as first
+// argument, raising a Lua error if they are out of bounds
+func (c *SaveCodec) bytes(L *lua.LState, n int) (int64, []byte) {
+	off := int64(L.CheckInt(1))
+	if n < 0 || off < 0 || off+int64(n) > int64(c.bkp.Size()) {
+		L.RaiseError("save access out of bounds: offset %d, size %d (save size: %d)", off, n, c.bkp.Size())
+	}
+	buf := make([]byte, n)
+	c.bkp.ReadAt(buf, off)
+	return off, buf
+}
+
+func (c *SaveCodec) store(L *lua.LState, off int64, buf []byte) {
+	if _, err := c.bkp.WriteAt(buf, off); err != nil {
+		L.RaiseError("save write: %v", err)
+	}
+}
+
+func (c *SaveCodec) luaSize(L *lua.LState) int {
+	L.Push(lua.LNumber(c.bkp.Size()))
+	return 1
+}
+
+func (c *SaveCodec) luaRead(L *lua.LState) int {
+	_, buf := c.bytes(L, L.CheckInt(2))
+	L.Push(lua.LString(buf))
+	return 1
+}
+
+func (c *SaveCodec) luaWrite(L *lua.LState) int {
+	s := L.CheckString(2)
+	off, _ := c.bytes(L, len(s))
+	c.store(L, off, []byte(s))
+	return 0
+}
+
+func (c *SaveCodec) luaUint(size int) lua.LGFunction {
+	return func(L *lua.LState) int {
+		_, buf := c.bytes(L, size)
+		var v uint32
+		for i := size - 1; i >= 0; i-- {
+			v = v<<8 | uint32(buf[i])
+		}
+		L.Push(lua.LNumber(v))
+		return 1
+	}
+}
+
+func (c *SaveCodec) luaSetUint(size int) lua.LGFunction {
+	return func(L *lua.LState) int {
+		v := uint32(L.CheckNumber(2))
+		off, buf := c.bytes(L, size)
+		for i := range buf {
+			buf[i] = uint8(v >> (8 * uint(i)))
+		}
+		c.store(L, off, buf)
+		return 0
+	}
+}
+
+func (c *SaveCodec) luaStr(L *lua.LState) int {
+	_, buf := c.bytes(L, L.CheckInt(2))
+	for i, b := range buf {
+		if b == 0 {
+			buf = buf[:i]
+			break
+		}
+	}
+	L.Push(lua.LString(buf))
+	return 1
+}
+
+func (c *SaveCodec) luaSetStr(L *lua.LState) int {
+	s := L.CheckString(3)
+	off, buf := c.bytes(L, L.CheckInt(2))
+	if len(s) > len(buf) {
+		L.RaiseError("string too long: %d bytes (max %d)", len(s), len(buf))
+	}
+	for i := range buf {
+		buf[i] = 0
+	}
+	copy(buf, s)
+	c.store(L, off, buf)
+	return 0
+}
+
+func (c *SaveCodec) luaUtf16(L *lua.LState) int {
+	_, buf := c.bytes(L, L.CheckInt(2)*2)
+	var units []uint16
+	for i := 0; i < len(buf); i += 2 {
+		u := binary.LittleEndian.Uint16(buf[i:])
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	L.Push(lua.LString(string(utf16.Decode(units))))
+	return 1
+}
+
+func (c *SaveCodec) luaSetUtf16(L *lua.LState) int {
+	units := utf16.Encode([]rune(L.CheckString(3)))
+	off, buf := c.bytes(L, L.CheckInt(2)*2)
+	if len(units)*2 > len(buf) {
+		L.RaiseError("string too long: %d units (max %d)", len(units), len(buf)/2)
+	}
+	for i := range buf {
+		buf[i] = 0
+	}
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(buf[i*2:], u)
+	}
+	c.store(L, off, buf)
+	return 0
+}
+
+func (c *SaveCodec) luaCrc16(L *lua.LState) int {
+	init := uint16(L.OptInt(3, 0xFFFF))
+	_, buf := c.bytes(L, L.CheckInt(2))
+	L.Push(lua.LNumber(^crc16.Update(^init, crc16.IBMTable, buf)))
+	return 1
+}
+
+// Maximum nesting of the values exchanged with scripts (guards against
+// tables that contain themselves)
+const cSaveCodecMaxDepth = 64
+
+// luaToGo converts a Lua value into a value that can be encoded as JSON
+func luaToGo(v lua.LValue, depth int) (interface{}, error) {
+	if depth > cSaveCodecMaxDepth {
+		return nil, errors.New("save codec: value nested too deeply")
+	}
+	switch v := v.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		return bool(v), nil
+	case lua.LNumber:
+		if f := float64(v); f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f), nil
+		}
+		return float64(v), nil
+	case lua.LString:
+		return string(v), nil
+	case *lua.LTable:
+		if n := v.Len(); n > 0 {
+			// Array, if the keys are exactly 1..n
+			count := 0
+			v.ForEach(func(lua.LValue, lua.LValue) { count++ })
+			if count == n {
+				arr := make([]interface{}, n)
+				for i := range arr {
+					e, err := luaToGo(v.RawGetInt(i+1), depth+1)
+					if err != nil {
+						return nil, err
+					}
+					arr[i] = e
+				}
+				return arr, nil
+			}
+		}
+		obj := make(map[string]interface{})
+		var err error
+		v.ForEach(func(k, e lua.LValue) {
+			if err == nil {
+				obj[k.String()], err = luaToGo(e, depth+1)
+			}
+		})
+		return obj, err
+	default:
+		return nil, fmt.Errorf("save codec: cannot convert %s to JSON", v.Type())
+	}
+}
+
+// goToLua converts a value decoded from JSON into a Lua value
+func goToLua(L *lua.LState, v interface{}) (lua.LValue, error) {
+	switch v := v.(type) {
+	case nil:
+		return lua.LNil, nil
+	case bool:
+		return lua.LBool(v), nil
+	case float64:
+		return lua.LNumber(v), nil
+	case string:
+		return lua.LString(v), nil
+	case []interface{}:
+		t := L.NewTable()
+		for _, e := range v {
+			le, err := goToLua(L, e)
+			if err != nil {
+				return nil, err
+			}
+			t.Append(le)
+		}
+		return t, nil
+	case map[string]interface{}:
+		t := L.NewTable()
+		for k, e := range v {
+			le, err := goToLua(L, e)
+			if err != nil {
+				return nil, err
+			}
+			// Keys of arrays with holes were encoded as strings by luaToGo
+			if n, err := strconv.Atoi(k); err == nil {
+				t.RawSetInt(n, le)
+			} else {
+				t.RawSetString(k, le)
+			}
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("save codec: cannot convert %T to Lua", v)
+	}
+}
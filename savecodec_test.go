@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A codec for a made-up save format: a player name (UTF-16), a number of
+// coins, a list of item IDs, and a CRC over the whole block
+const testSaveCodec = `
+function decode()
+	local items = {}
+	for i = 0, save.u8(0x16) - 1 do
+		table.insert(items, save.u16(0x18 + i*2))
+	end
+	return {
+		name = save.utf16(0, 8),
+		coins = save.u32(0x10),
+		tag = save.str(0x20, 4),
+		items = items,
+		valid = save.crc16(0, 0x3E) == save.u16(0x3E),
+	}
+end
+
+function encode(t)
+	save.set_utf16(0, 8, t.name)
+	save.set_u32(0x10, t.coins)
+	save.set_str(0x20, 4, t.tag)
+	save.set_u8(0x16, #t.items)
+	for i, id in ipairs(t.items) do
+		save.set_u16(0x18 + (i-1)*2, id)
+	end
+	save.set_u16(0x3E, save.crc16(0, 0x3E))
+end
+`
+
+func TestSaveCodec(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(SaveCodecFile(dir, "ABCE"), []byte(testSaveCodec), 0666); err != nil {
+		t.Fatal(err)
+	}
+	b := NewHwBackupRam(nil)
+	b.MapSaveFile(filepath.Join(dir, "game.sav"))
+	defer b.Close()
+	b.WriteAt(make([]byte, 0x40), 0)
+
+	codec, err := LoadSaveCodec(SaveCodecFile(dir, "ABCE"), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer codec.Close()
+
+	var v interface{}
+	json.Unmarshal([]byte(`{"name":"Rasky","coins":123456,"tag":"HERO","items":[7,300,65535]}`), &v)
+	if err := codec.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the raw layout against the format
+	raw := make([]byte, 0x40)
+	b.ReadAt(raw, 0)
+	if got := string([]byte{raw[0], raw[2], raw[4], raw[6], raw[8], raw[10]}); got != "Rasky\x00" || raw[1] != 0 {
+		t.Errorf("invalid name: % x", raw[:0x10])
+	}
+	if got := binary.LittleEndian.Uint32(raw[0x10:]); got != 123456 {
+		t.Errorf("invalid coins: %d", got)
+	}
+	if raw[0x16] != 3 || binary.LittleEndian.Uint16(raw[0x1A:]) != 300 {
+		t.Errorf("invalid items: % x", raw[0x16:0x20])
+	}
+	if crc := firmwareCrc16(raw[:0x3E]); crc != binary.LittleEndian.Uint16(raw[0x3E:]) {
+		t.Errorf("invalid crc: got %04x, want %04x", binary.LittleEndian.Uint16(raw[0x3E:]), crc)
+	}
+
+	got, err := codec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":  "Rasky",
+		"coins": int64(123456),
+		"tag":   "HERO",
+		"items": []interface{}{int64(7), int64(300), int64(65535)},
+		"valid": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %#v, want %#v", got, want)
+	}
+
+	// Accesses beyond the end of the save are reported as errors
+	if err := codec.Encode(map[string]interface{}{"name": "x", "coins": 0.0, "tag": "TOOLONG", "items": []interface{}{}}); err == nil {
+		t.Errorf("string overflow not detected")
+	}
+}
@@ -0,0 +1,528 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"ndsemu/arm"
+	"ndsemu/emu/fixed"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"ndsemu/raster3d"
+	"os"
+	"path/filepath"
+)
+
+// A savestate is made of a fixed header (magic string and format version),
+// followed by the gob encoding of saveState, compressed with gzip. The
+// version must be bumped whenever saveState changes in an incompatible way.
+const (
+	cSaveStateMagic   = "NDSEMUSS"
+	cSaveStateVersion = 1
+)
+
+// errStateBusy is returned by SaveState when the state can't be saved at
+// the current frame (see SaveState)
+var errStateBusy = errors.New("hardware busy (eg: gamecard transfer), retry later")
+
+// saveState is the state of the whole system, as stored in savestates. It
+// covers the memories, both CPUs, the I/O registers of all devices (see
+// ioDevices; they are read from their storage like the peek functions, so
+// read-sensitive registers are not triggered), and the internal state of the devices that is needed to resume
+// emulation exactly: timers, DMA channels, IPC FIFOs, the gamecard protocol,
+// the geometry engine (matrices and command FIFO) and the SPU voices.
+//
+// The remaining internal state of the devices (eg: the 3D scene being built,
+// the SPU captures, SPI transfers) is not saved, and restarts from the value
+// of the registers; this is harmless in practice, as savestates are taken at
+// frame boundaries.
+type saveState struct {
+	Game      string // game code of the inserted cartridge
+	Cycles    int64
+	Frame     int
+	PowCnt    uint32
+	Sleeping  bool
+	LidClosed bool
+
+	Ram, Vram, Wram, PaletteRam, OamRam, SharedWram []byte
+
+	Cpu9, Cpu7 *arm.CpuState
+	Regs       map[string]map[string]uint64 // device name -> field name -> value
+
+	Timers [2][4]timerState
+	Dma    [2][4]dmaState
+	LvlIrq [2]uint32
+	Ipc    ipcState
+	Card   cardState
+	Gx     gxState
+	Voices [16]voiceState
+	Bias   uint32
+}
+
+type timerState struct {
+	Counter uint16
+	Cycles  int64
+}
+
+type dmaState struct {
+	Sad, Dad, Cnt uint32
+	ReadLatch     uint32
+	PendingEvent  DmaEvent
+}
+
+type ipcState struct {
+	Fifo         [2][]uint32
+	Last         [2]uint32
+	EmptyIrq     [2]bool
+	DataIrq      [2]bool
+	Enable       [2]bool
+	Err          [2]bool
+	IrqEmptyFlag [2]bool
+	IrqDataFlag  [2]bool
+}
+
+type cardState struct {
+	Stat       int
+	Key2X      uint64
+	Key2Y      uint64
+	SecAreaOff int
+	Buf        []byte
+	XferPos    int
+}
+
+type gxFifoEntry struct {
+	When int64
+	Code uint8
+	Parm uint32
+}
+
+type gxState struct {
+	Cycles int64
+	Busy   bool
+	Fifo   []gxFifoEntry
+
+	MtxMode     int
+	Mtx         [4]matrix
+	ClipMtx     matrix
+	StackProj   [1]matrix
+	StackPos    [32]matrix
+	StackDir    [32]matrix
+	StackTex    [1]matrix
+	ProjPtr     int
+	PosPtr      int
+	TexPtr      int
+	Overflow    bool
+	Viewport    [4]int
+	Material    [4]fcolor
+	LightDir    [4]vector
+	LightHalf   [4]vector
+	LightColor  [4]fcolor
+	SpecTable   [128]fixed.F12
+	SpecTableOn bool
+	TexInfo     raster3d.Texture
+	TexTrans    int
+	PolyAttr    uint32
+}
+
+type voiceState struct {
+	Tmr        uint32
+	Pos        uint
+	On         bool
+	Delay      int
+	Hist       [4]int64
+	HPos       uint
+	AdpcmPcm   int32
+	AdpcmIndex int16
+	LoopPcm    int32
+	LoopIndex  int16
+	APos       uint
+}
+
+// SaveState writes a savestate of the whole system (see LoadState). It must
+// be called between frames. It fails while a device is waiting for a timed
+// event (eg: a gamecard transfer), as those can't be saved; the caller can
+// just retry after the next frame.
+func (emu *NDSEmulator) SaveState(w io.Writer) error {
+	switch {
+	case emu.Mode != ModeNds:
+		return errors.New("savestates not supported in GBA mode")
+	case emu.Hw.Ipc.Hle7 != nil:
+		return errors.New("savestates not supported with ARM7 HLE")
+	case emu.Sync.PendingEvents() != 0:
+		return errStateBusy
+	}
+
+	if _, err := io.WriteString(w, cSaveStateMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(cSaveStateVersion)); err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(w)
+	if err := gob.NewEncoder(zw).Encode(emu.saveState()); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// LoadState restores a savestate written by SaveState. The game it was taken
+// from must be inserted. The savestate is fully decoded and validated before
+// touching the emulator, so that the emulation is not affected if it is
+// invalid.
+func (emu *NDSEmulator) LoadState(r io.Reader) error {
+	var hdr [len(cSaveStateMagic) + 4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil || string(hdr[:len(cSaveStateMagic)]) != cSaveStateMagic {
+		return errors.New("not a savestate")
+	}
+	if ver := binary.LittleEndian.Uint32(hdr[len(cSaveStateMagic):]); ver != cSaveStateVersion {
+		return fmt.Errorf("unsupported savestate version: %d", ver)
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("invalid savestate: %v", err)
+	}
+	var st saveState
+	if err := gob.NewDecoder(zr).Decode(&st); err != nil {
+		return fmt.Errorf("invalid savestate: %v", err)
+	}
+	if err := emu.checkState(&st); err != nil {
+		return err
+	}
+
+	emu.loadState(&st)
+	log.ModEmu.InfoZ("savestate loaded").Int("frame", st.Frame).End()
+	return nil
+}
+
+// SaveStateFile writes a savestate into the specified file. The savestate is
+// written to a temporary file, which then replaces the specified one, so that
+// the previous savestate is not lost if the emulator (or the host) crashes
+// while saving.
+func (emu *NDSEmulator) SaveStateFile(fn string) error {
+	f, err := ioutil.TempFile(filepath.Dir(fn), filepath.Base(fn)+".tmp")
+	if err != nil {
+		return err
+	}
+	err = emu.SaveState(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), fn)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// LoadStateFile loads a savestate from the specified file
+func (emu *NDSEmulator) LoadStateFile(fn string) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return emu.LoadState(f)
+}
+
+// checkState checks that a decoded savestate can be restored into the
+// emulator, as loadState doesn't expect invalid values.
+func (emu *NDSEmulator) checkState(st *saveState) error {
+	switch {
+	case emu.Mode != ModeNds:
+		return errors.New("savestates not supported in GBA mode")
+	case emu.Hw.Ipc.Hle7 != nil:
+		return errors.New("savestates not supported with ARM7 HLE")
+	}
+
+	var gamecode [4]byte
+	emu.Hw.Gc.ReadAt(gamecode[:], 0xC)
+	if st.Game != string(gamecode[:]) {
+		return fmt.Errorf("savestate is for game %q, not %q", st.Game, gamecode[:])
+	}
+	if len(st.Ram) != len(emu.Mem.Ram) || len(st.Vram) != len(emu.Mem.Vram) ||
+		len(st.Wram) != len(emu.Mem.Wram) || len(st.PaletteRam) != len(emu.Mem.PaletteRam) ||
+		len(st.OamRam) != len(emu.Mem.OamRam) || len(st.SharedWram) != len(emu.Hw.Mc.wram) ||
+		st.Cpu9 == nil || st.Cpu7 == nil || st.Cpu9.Cp15 == nil {
+		return errors.New("invalid savestate: missing memory or CPU state")
+	}
+
+	fail := func(what string, val interface{}) error {
+		return fmt.Errorf("invalid savestate: invalid %s: %v", what, val)
+	}
+	if st.Cycles < 0 || st.Cycles%emu.Sync.FrameCycles() != 0 {
+		return fail("clock (not at a frame boundary)", st.Cycles)
+	}
+	if st.Frame < 0 {
+		return fail("frame", st.Frame)
+	}
+	for c := range st.Dma {
+		for i := range st.Dma[c] {
+			if ev := st.Dma[c][i].PendingEvent; ev < DmaEventInvalid || ev > DmaEventGbaVideoCapture {
+				return fail("DMA event", ev)
+			}
+		}
+	}
+	for i, fifo := range st.Ipc.Fifo {
+		if len(fifo) > 16 {
+			return fail(fmt.Sprintf("IPC FIFO %d size", i), len(fifo))
+		}
+	}
+	if c := &st.Card; c.Stat < int(gcStatusRaw) || c.Stat > int(gcStatusKey2) {
+		return fail("gamecard status", c.Stat)
+	} else if c.XferPos < 0 || c.XferPos > len(c.Buf) {
+		return fail("gamecard transfer position", c.XferPos)
+	}
+	gx := &st.Gx
+	switch {
+	case len(gx.Fifo) > len(emu.Hw.Geom.fifo.cmds):
+		return fail("geometry FIFO size", len(gx.Fifo))
+	case gx.MtxMode < 0 || gx.MtxMode > 3:
+		return fail("matrix mode", gx.MtxMode)
+	case gx.ProjPtr < 0 || gx.ProjPtr > len(gx.StackProj):
+		return fail("projection stack pointer", gx.ProjPtr)
+	case gx.TexPtr < 0 || gx.TexPtr > len(gx.StackTex):
+		return fail("texture stack pointer", gx.TexPtr)
+	case gx.PosPtr < 0 || gx.PosPtr > 63:
+		return fail("position stack pointer", gx.PosPtr)
+	}
+	return nil
+}
+
+func (emu *NDSEmulator) saveState() *saveState {
+	hw := emu.Hw
+	st := &saveState{
+		Cycles:    emu.Sync.Cycles(),
+		Frame:     emu.framecount,
+		PowCnt:    emu.powcnt,
+		Sleeping:  emu.sleeping,
+		LidClosed: emu.lidClosed,
+
+		Ram:        emu.Mem.Ram[:],
+		Vram:       emu.Mem.Vram[:],
+		Wram:       emu.Mem.Wram[:],
+		PaletteRam: emu.Mem.PaletteRam[:],
+		OamRam:     emu.Mem.OamRam[:],
+		SharedWram: hw.Mc.wram[:],
+
+		Cpu9: nds9.Cpu.State(),
+		Cpu7: nds7.Cpu.State(),
+		Regs: make(map[string]map[string]uint64),
+	}
+	var gamecode [4]byte
+	hw.Gc.ReadAt(gamecode[:], 0xC)
+	st.Game = string(gamecode[:])
+
+	for _, dev := range emu.ioDevices() {
+		st.Regs[dev.name] = hwio.RegValues(dev.regs)
+	}
+
+	for c, timers := range []*HwTimers{nds9.Timers, nds7.Timers} {
+		for i := range timers.Timers {
+			t := &timers.Timers[i]
+			st.Timers[c][i] = timerState{t.counter, t.cycles}
+		}
+	}
+	for c, dmas := range [][4]*HwDmaChannel{nds9.Dma, nds7.Dma} {
+		for i, dma := range dmas {
+			st.Dma[c][i] = dmaState{dma.sad, dma.dad, dma.cnt, dma.readLatch, dma.pendingEvent}
+		}
+	}
+	st.LvlIrq = [2]uint32{nds9.Irq.lvlirq, nds7.Irq.lvlirq}
+
+	ipc := hw.Ipc
+	for i := range ipc.data {
+		st.Ipc.Fifo[i] = ipc.data[i].fifo
+		st.Ipc.Last[i] = ipc.data[i].last
+		st.Ipc.EmptyIrq[i] = ipc.data[i].emptyIrq
+		st.Ipc.DataIrq[i] = ipc.data[i].dataIrq
+	}
+	st.Ipc.Enable, st.Ipc.Err = ipc.enable, ipc.err
+	st.Ipc.IrqEmptyFlag, st.Ipc.IrqDataFlag = ipc.irqEmptyFlag, ipc.irqDataFlag
+
+	gc := hw.Gc
+	st.Card = cardState{int(gc.stat), gc.key2.x, gc.key2.y, gc.secAreaOff, gc.buf, gc.xferPos}
+
+	st.Gx = hw.Geom.saveState()
+
+	snd := hw.Snd
+	for i := range snd.voice {
+		v := &snd.voice[i]
+		st.Voices[i] = voiceState{
+			Tmr: v.tmr, Pos: v.pos, On: v.on, Delay: v.delay,
+			Hist: v.hist, HPos: v.hpos,
+			AdpcmPcm: v.adpcm.pcm, AdpcmIndex: v.adpcm.index,
+			LoopPcm: v.adpcmLoop.pcm, LoopIndex: v.adpcmLoop.index,
+			APos: v.apos,
+		}
+	}
+	st.Bias = snd.bias
+	return st
+}
+
+// loadState restores a (validated) savestate. The console is hard-reset
+// first, so that the devices start from a known state; the registers are
+// then restored bypassing the write callbacks, and the state that callbacks
+// derive from them (like the memory mapping) is recomputed.
+func (emu *NDSEmulator) loadState(st *saveState) {
+	emu.Reset(true)
+	emu.Sync.SetCycles(st.Cycles)
+	emu.framecount = st.Frame
+	emu.powcnt = st.PowCnt
+	emu.sleeping = st.Sleeping
+	emu.lidClosed = st.LidClosed
+
+	hw := emu.Hw
+	copy(emu.Mem.Ram[:], st.Ram)
+	copy(emu.Mem.Vram[:], st.Vram)
+	copy(emu.Mem.Wram[:], st.Wram)
+	copy(emu.Mem.PaletteRam[:], st.PaletteRam)
+	copy(emu.Mem.OamRam[:], st.OamRam)
+	copy(hw.Mc.wram[:], st.SharedWram)
+
+	for _, dev := range emu.ioDevices() {
+		hwio.SetRegValues(dev.regs, st.Regs[dev.name])
+	}
+
+	// Memory mapping
+	mc := hw.Mc
+	for i, cnt := range []*hwio.Reg8{
+		&mc.VramCntA, &mc.VramCntB, &mc.VramCntC, &mc.VramCntD, &mc.VramCntE,
+		&mc.VramCntF, &mc.VramCntG, &mc.VramCntH, &mc.VramCntI,
+	} {
+		mc.writeVRAMCNT('A'+byte(i), cnt.Value)
+	}
+	mc.WriteWRAMCNT(0, mc.WramCnt.Value)
+	mc.WriteEXMEMCNT(mc.ExMemCnt.Value^(1<<11|1<<7), mc.ExMemCnt.Value)
+	hw.E2d[0].RestoreRegs()
+	hw.E2d[1].RestoreRegs()
+
+	for c, timers := range []*HwTimers{nds9.Timers, nds7.Timers} {
+		for i := range timers.Timers {
+			t := &timers.Timers[i]
+			t.counter, t.cycles = st.Timers[c][i].Counter, st.Timers[c][i].Cycles
+			t.reschedule()
+		}
+	}
+	for c, dmas := range [][4]*HwDmaChannel{nds9.Dma, nds7.Dma} {
+		for i, dma := range dmas {
+			ds := &st.Dma[c][i]
+			dma.sad, dma.dad, dma.cnt = ds.Sad, ds.Dad, ds.Cnt
+			dma.readLatch, dma.pendingEvent = ds.ReadLatch, ds.PendingEvent
+		}
+	}
+	nds9.Irq.lvlirq, nds7.Irq.lvlirq = st.LvlIrq[0], st.LvlIrq[1]
+
+	ipc := hw.Ipc
+	for i := range ipc.data {
+		ipc.data[i] = ipcFifo{
+			fifo:     st.Ipc.Fifo[i],
+			emptyIrq: st.Ipc.EmptyIrq[i],
+			dataIrq:  st.Ipc.DataIrq[i],
+			last:     st.Ipc.Last[i],
+		}
+	}
+	ipc.enable, ipc.err = st.Ipc.Enable, st.Ipc.Err
+	ipc.irqEmptyFlag, ipc.irqDataFlag = st.Ipc.IrqEmptyFlag, st.Ipc.IrqDataFlag
+
+	gc := hw.Gc
+	gc.stat = gcStatus(st.Card.Stat)
+	gc.key2.x, gc.key2.y = st.Card.Key2X, st.Card.Key2Y
+	gc.secAreaOff = st.Card.SecAreaOff
+	gc.buf, gc.xferPos = st.Card.Buf, st.Card.XferPos
+
+	hw.Geom.loadState(&st.Gx)
+
+	snd := hw.Snd
+	snd.Run(st.Cycles)
+	for i := range snd.voice {
+		if snd.Ch[i].SndCnt.Value&(1<<31) != 0 {
+			snd.startChannel(i)
+		}
+		v, vs := &snd.voice[i], &st.Voices[i]
+		v.tmr, v.pos, v.on, v.delay = vs.Tmr, vs.Pos, vs.On, vs.Delay
+		v.hist, v.hpos = vs.Hist, vs.HPos
+		v.adpcm = adpcmState{vs.AdpcmPcm, vs.AdpcmIndex}
+		v.adpcmLoop = adpcmState{vs.LoopPcm, vs.LoopIndex}
+		v.apos = vs.APos
+	}
+	snd.bias = st.Bias
+
+	// CPUs last, as restoring the devices might have touched their lines
+	nds9.Cpu.SetState(st.Cpu9)
+	nds7.Cpu.SetState(st.Cpu7)
+}
+
+func (g *HwGeometry) saveState() gxState {
+	gx := &g.gx
+	st := gxState{
+		Cycles: g.cycles,
+		Busy:   g.busy,
+
+		MtxMode:     gx.mtxmode,
+		Mtx:         gx.mtx,
+		ClipMtx:     gx.clipmtx,
+		StackProj:   gx.mtxStackProj,
+		StackPos:    gx.mtxStackPos,
+		StackDir:    gx.mtxStackDir,
+		StackTex:    gx.mtxStackTex,
+		ProjPtr:     gx.mtxStackProjPtr,
+		PosPtr:      gx.mtxStackPosPtr,
+		TexPtr:      gx.mtxStackTexPtr,
+		Overflow:    gx.mtxStackOverflow,
+		Viewport:    [4]int{gx.vx0, gx.vy0, gx.vx1, gx.vy1},
+		Material:    gx.material,
+		SpecTable:   gx.specTable,
+		SpecTableOn: gx.specTableOn,
+		TexInfo:     gx.texinfo,
+		TexTrans:    gx.textrans,
+		PolyAttr:    gx.polyattr,
+	}
+	for i := range gx.lights {
+		st.LightDir[i] = gx.lights[i].dir
+		st.LightHalf[i] = gx.lights[i].half
+		st.LightColor[i] = gx.lights[i].color
+	}
+	for i := g.fifo.r; i < g.fifo.w; i++ {
+		cmd := &g.fifo.cmds[i&511]
+		st.Fifo = append(st.Fifo, gxFifoEntry{cmd.when, uint8(cmd.code), cmd.parm})
+	}
+	return st
+}
+
+func (g *HwGeometry) loadState(st *gxState) {
+	g.cycles, g.busy = st.Cycles, st.Busy
+	g.fifo.Reset()
+	for _, cmd := range st.Fifo {
+		g.fifo.Push(GxCmd{when: cmd.When, code: GxCmdCode(cmd.Code), parm: cmd.Parm})
+	}
+
+	gx := &g.gx
+	gx.mtxmode = st.MtxMode
+	gx.mtx, gx.clipmtx = st.Mtx, st.ClipMtx
+	gx.mtxStackProj, gx.mtxStackPos = st.StackProj, st.StackPos
+	gx.mtxStackDir, gx.mtxStackTex = st.StackDir, st.StackTex
+	gx.mtxStackProjPtr, gx.mtxStackPosPtr, gx.mtxStackTexPtr = st.ProjPtr, st.PosPtr, st.TexPtr
+	gx.mtxStackOverflow = st.Overflow
+	gx.vx0, gx.vy0, gx.vx1, gx.vy1 = st.Viewport[0], st.Viewport[1], st.Viewport[2], st.Viewport[3]
+	gx.e3d.CmdViewport(raster3d.Primitive_SetViewport{
+		VX0: gx.vx0, VX1: gx.vx1, VY0: gx.vy0, VY1: gx.vy1,
+	})
+	gx.material = st.Material
+	for i := range gx.lights {
+		gx.lights[i].dir = st.LightDir[i]
+		gx.lights[i].half = st.LightHalf[i]
+		gx.lights[i].color = st.LightColor[i]
+	}
+	gx.specTable, gx.specTableOn = st.SpecTable, st.SpecTableOn
+	gx.texinfo, gx.textrans = st.TexInfo, st.TexTrans
+	gx.polyattr = st.PolyAttr
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"ndsemu/arm/asm"
+	"ndsemu/arm/disasm"
+	"ndsemu/emu/gfx"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestEmulator creates an emulator running synthetic BIOS programs, that
+// start a timer and count in memory forever on both CPUs
+func newTestEmulator(t *testing.T) *NDSEmulator {
+	bios := func(base uint32, arch disasm.Arch, counter uint32) []byte {
+		buf := make([]byte, 16*1024)
+		asmAt := func(off uint32, text string) {
+			op, err := asm.Arm(text, base+off, arch)
+			if err != nil {
+				t.Fatalf("%q: %v", text, err)
+			}
+			binary.LittleEndian.PutUint32(buf[off:], op)
+		}
+
+		// The reset vector jumps past the exception vectors; the IRQ
+		// handler loops forever
+		asmAt(0, fmt.Sprintf("b %#x", base+0x100))
+		asmAt(0x18, fmt.Sprintf("b %#x", base+0x200))
+		asmAt(0x200, fmt.Sprintf("b %#x", base+0x200))
+		for i, text := range []string{
+			"msr cpsr_c, #0x1f", // system mode, IRQs enabled
+			fmt.Sprintf("mov r0, #%#x", counter),
+			"mov r2, #0x4000000",
+			"add r2, r2, #0x100",
+			"mov r3, #0x81", // TM0CNT: start, prescaler 1/64
+			"strh r3, [r2, #2]",
+			"ldr r1, [r0]",
+			"add r1, r1, #1",
+			"str r1, [r0]",
+			fmt.Sprintf("b %#x", base+0x100+6*4),
+		} {
+			asmAt(0x100+uint32(i)*4, text)
+		}
+		return buf
+	}
+	rom := &NDSRom{
+		Bios9: bios(0xFFFF0000, disasm.ARMv5, 0x2000000)[:4096],
+		Bios7: bios(0, disasm.ARMv4, 0x3800000),
+	}
+	Emu = newNDSEmulator(rom, false)
+	return Emu
+}
+
+func runFrames(emu *NDSEmulator, n int) {
+	screen := gfx.NewBufferMem(256, 192+90+192)
+	samples := make([]int16, (cAudioFreq/60+1)*2)
+	for i := 0; i < n; i++ {
+		emu.RunOneFrame(screen, samples[:audioFrameSamples(emu.framecount)*2])
+	}
+}
+
+func TestSaveStateRoundTrip(t *testing.T) {
+	emu := newTestEmulator(t)
+	runFrames(emu, 3)
+
+	fn := filepath.Join(t.TempDir(), "game.state")
+	if err := emu.SaveStateFile(fn); err != nil {
+		t.Fatal(err)
+	}
+	saved := emu.StateHash()
+	runFrames(emu, 3)
+	after := emu.StateHash()
+	if saved == after || binary.LittleEndian.Uint32(emu.Mem.Ram[:]) == 0 {
+		t.Fatal("the test programs are not running")
+	}
+
+	if err := emu.LoadStateFile(fn); err != nil {
+		t.Fatal(err)
+	}
+	if h := emu.StateHash(); h != saved {
+		t.Errorf("state hash after load: %016x, want %016x", h, saved)
+	}
+	runFrames(emu, 3)
+	if h := emu.StateHash(); h != after {
+		t.Errorf("state hash after load and run: %016x, want %016x", h, after)
+	}
+}
+
+func TestSaveStateReadSensitive(t *testing.T) {
+	emu := newTestEmulator(t)
+	nds7.Bus.Write16(0x4000184, 0x8000)
+	nds9.Bus.Write16(0x4000184, 0x8000)
+	nds7.Bus.Write32(0x4000188, 0x11223344)
+
+	// Registers are saved through their storage (like the peek functions),
+	// so the FIFO must not be popped
+	var buf bytes.Buffer
+	if err := emu.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if v := nds9.Bus.Read32(0x4100000); v != 0x11223344 {
+		t.Errorf("FIFO popped by the savestate: read %08x", v)
+	}
+}
+
+func TestLoadStateErrors(t *testing.T) {
+	var garbage bytes.Buffer
+	garbage.WriteString(cSaveStateMagic + "\x01\x00\x00\x00")
+	zw := gzip.NewWriter(&garbage)
+	zw.Write([]byte("not a gob stream"))
+	zw.Close()
+
+	for _, tc := range []struct {
+		data string
+		err  string
+	}{
+		{"", "not a savestate"},
+		{"NDSEMU", "not a savestate"},
+		{"PK\x03\x04\x14\x00\x00\x00\x08\x00\x00\x00", "not a savestate"},
+		{cSaveStateMagic + "\x02\x00\x00\x00", "unsupported savestate version: 2"},
+		{cSaveStateMagic + "\x01\x00\x00\x00", "invalid savestate"},
+		{garbage.String(), "invalid savestate"},
+	} {
+		// The state is rejected before touching the emulator
+		emu := new(NDSEmulator)
+		err := emu.LoadState(strings.NewReader(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%q: unexpected error: %v", tc.data, err)
+		}
+	}
+}
+
+func TestLoadStateInvalid(t *testing.T) {
+	emu := newTestEmulator(t)
+	runFrames(emu, 2)
+	good := emu.saveState()
+	runFrames(emu, 1)
+	hash := emu.StateHash()
+
+	for _, tc := range []struct {
+		edit func(st *saveState)
+		err  string
+	}{
+		{func(st *saveState) { st.Cycles += 1000 }, "not at a frame boundary"},
+		{func(st *saveState) { st.Cpu9.Cp15 = nil }, "missing memory or CPU state"},
+		{func(st *saveState) { st.Ipc.Fifo[1] = make([]uint32, 17) }, "IPC FIFO 1 size"},
+		{func(st *saveState) { st.Card.XferPos = len(st.Card.Buf) + 1 }, "gamecard transfer position"},
+		{func(st *saveState) { st.Gx.PosPtr = 64 }, "position stack pointer"},
+		{func(st *saveState) { st.Dma[0][2].PendingEvent = 100 }, "DMA event"},
+	} {
+		st, cpu9 := *good, *good.Cpu9
+		st.Cpu9 = &cpu9
+		tc.edit(&st)
+
+		var buf bytes.Buffer
+		buf.WriteString(cSaveStateMagic + "\x01\x00\x00\x00")
+		zw := gzip.NewWriter(&buf)
+		if err := gob.NewEncoder(zw).Encode(&st); err != nil {
+			t.Fatal(err)
+		}
+		zw.Close()
+
+		// The emulator must be left untouched
+		if err := emu.LoadState(&buf); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: unexpected error: %v", tc.err, err)
+		}
+		if h := emu.StateHash(); h != hash {
+			t.Errorf("%s: state changed", tc.err)
+		}
+	}
+}
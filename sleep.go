@@ -0,0 +1,67 @@
+package main
+
+import (
+	"ndsemu/arm"
+	log "ndsemu/emu/logger"
+)
+
+// IRQs of the ARM7 that can wake up the console from sleep mode: they are
+// raised by events external to the main clock domain, which is stopped.
+const cSleepWakeIrqs = IrqLidOpen | IrqRtc | IrqGameCardEject | IrqSlot2
+
+// Sleep puts the console into sleep mode, as requested by the ARM7 through
+// HALTCNT (usually with the BIOS Sleep SWI, after the game has halted the
+// ARM9 and turned off the backlights). In sleep mode, the oscillator is
+// stopped: from the next frame, neither CPU runs, timers, DMA, sound and LCD
+// are frozen, and the screens are off.
+//
+// The console wakes up when one of the wake sources (lid opened, RTC alarm,
+// cartridge IRQ) is requested in IF and enabled in IE; as for halt, IME
+// doesn't matter. The IRQ is left pending in IF, so that the ARM7 takes it
+// as soon as it enables IME again, as the BIOS expects.
+func (emu *NDSEmulator) Sleep() {
+	nds7.Cpu.SetLine(arm.LineHalt, true)
+	emu.sleeping = true
+	log.ModEmu.WarnZ("entering sleep mode").Hex32("ie", nds7.Irq.Ie.Value).End()
+	if nds7.Irq.Ie.Value&uint32(cSleepWakeIrqs) == 0 {
+		log.ModEmu.WarnZ("no wake sources enabled, the console will sleep forever").End()
+	}
+}
+
+// Sleeping returns true if the console is in sleep mode (see Sleep)
+func (emu *NDSEmulator) Sleeping() bool {
+	return emu.sleeping
+}
+
+// pollExternalIrqs raises the IRQs for external events that are not
+// emulated in sync with the CPUs (lid and RTC alarms). It is called at each
+// frame, both while the console runs and while it sleeps.
+func (emu *NDSEmulator) pollExternalIrqs() {
+	lid := emu.Hw.Key.LidClosed()
+	if emu.lidClosed && !lid {
+		nds7.Irq.Raise(IrqLidOpen)
+	}
+	emu.lidClosed = lid
+
+	if emu.Hw.Rtc.PollAlarms() {
+		nds7.Irq.Raise(IrqRtc)
+	}
+}
+
+// sleepFrame emulates a frame in sleep mode, returning false (without doing
+// anything) if the console must wake up
+func (emu *NDSEmulator) sleepFrame(audio []int16) bool {
+	if wake := nds7.Irq.Pending() & uint32(cSleepWakeIrqs); wake != 0 {
+		// Raising the IRQ doesn't release the halt if IME is clear
+		nds7.Cpu.SetLine(arm.LineHalt, false)
+		emu.sleeping = false
+		log.ModEmu.WarnZ("waking up from sleep mode").Stringer("irq", IrqType(wake)).End()
+		return false
+	}
+
+	for i := range audio {
+		audio[i] = 0
+	}
+	emu.clearScreens()
+	return true
+}
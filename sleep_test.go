@@ -0,0 +1,83 @@
+package main
+
+import (
+	"ndsemu/arm"
+	"testing"
+	"time"
+)
+
+func TestSleepWake(t *testing.T) {
+	now := time.Date(2020, 3, 4, 15, 29, 50, 0, time.UTC)
+	for _, tc := range []struct {
+		name  string
+		irq   IrqType
+		setup func(emu *NDSEmulator)
+		wake  func(emu *NDSEmulator)
+	}{
+		{"lid", IrqLidOpen,
+			func(emu *NDSEmulator) { emu.Hw.Key.SetLidClosed(true) },
+			func(emu *NDSEmulator) { emu.Hw.Key.SetLidClosed(false) },
+		},
+		{"alarm", IrqRtc,
+			func(emu *NDSEmulator) {
+				// Alarm 2 at 15:30 (24H mode)
+				rtc := emu.Hw.Rtc
+				rtc.Clock = func() time.Time { return now }
+				rtc.regStatus1, rtc.regStatus2 = 2, 1<<6
+				rtc.alarms[1] = rtcAlarm{hour: 0x80 | 0x15, minOrFreq: 0x80 | 0x30}
+			},
+			func(emu *NDSEmulator) { now = now.Add(time.Minute) },
+		},
+	} {
+		emu := newTestEmulator(t)
+		halted := func() bool { return arm.Line(nds7.Cpu.State().Lines)&arm.LineHalt != 0 }
+		counter := func() uint32 { return nds7.Bus.Read32(0x3800000) }
+
+		tc.setup(emu)
+		runFrames(emu, 1)
+		nds7.Irq.Ie.Value = uint32(tc.irq)
+		emu.Sleep()
+		runFrames(emu, 1)
+		cnt := counter()
+		runFrames(emu, 2)
+		if !emu.Sleeping() || !halted() || counter() != cnt {
+			t.Fatalf("%s: console not sleeping", tc.name)
+		}
+
+		// The IRQ wakes up the console even if IME is clear, and is left
+		// pending for the BIOS
+		tc.wake(emu)
+		runFrames(emu, 1)
+		if emu.Sleeping() || halted() || counter() == cnt {
+			t.Fatalf("%s: console not woken up", tc.name)
+		}
+		if nds7.Irq.If.Value&uint32(tc.irq) == 0 {
+			t.Errorf("%s: IRQ not pending: IF=%08x", tc.name, nds7.Irq.If.Value)
+		}
+		if mode := nds7.Cpu.State().Cpsr & 0x1F; mode == 0x12 {
+			t.Errorf("%s: IRQ taken with IME clear", tc.name)
+		}
+
+		// ...and taken as soon as IME is set
+		nds7.Irq.Ime.Value = 1
+		nds7.Irq.WriteIME(0, 1)
+		runFrames(emu, 1)
+		if mode := nds7.Cpu.State().Cpsr & 0x1F; mode != 0x12 {
+			t.Errorf("%s: IRQ not taken after setting IME: mode=%02x", tc.name, mode)
+		}
+	}
+}
+
+func TestResetLidClosed(t *testing.T) {
+	emu := newTestEmulator(t)
+	nds7.Irq.Ie.Value = uint32(IrqLidOpen)
+	emu.Hw.Key.SetLidClosed(true)
+	runFrames(emu, 1)
+
+	// The lid state of the previous session must not raise an IRQ
+	emu.Reset(false)
+	emu.pollExternalIrqs()
+	if nds7.Irq.If.Value&uint32(IrqLidOpen) != 0 {
+		t.Errorf("lid open IRQ raised by reset")
+	}
+}
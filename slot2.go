@@ -3,21 +3,39 @@ package main
 import (
 	"io"
 	"io/ioutil"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
 	"ndsemu/homebrew"
 	"os"
 )
 
-var highz [16]byte = [...]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+var modSlot2 = log.NewModule("slot2")
 
 type HwSlot2 struct {
-	Rom []byte
+	Rom []byte // nil if no cartridge is inserted
 	Ram [64 * 1024]byte
+
+	// IRQ controller of the CPU that owns the slot (see EXMEMCNT), notified
+	// when the cartridge is removed
+	Irq *HwIrq
+
+	// Optional peripheral connected to the cartridge GPIO port
+	Solar *HwSolarSensor
+
+	// Optional peripheral replacing the cartridge SRAM
+	Motion *HwMotionPak
+
+	// Address ranges where the ROM is currently mapped (see MapRom)
+	romMaps []slot2Map
+}
+
+type slot2Map struct {
+	bus        *hwio.Table
+	begin, end uint32
 }
 
 func NewHwSlot2() *HwSlot2 {
-	return &HwSlot2{
-		Rom: highz[:],
-	}
+	return &HwSlot2{}
 }
 
 func roundup2(v int) int {
@@ -82,6 +100,113 @@ func (slot *HwSlot2) HomebrewMapFatFile(fn string) error {
 	return slot.mapCart(data, true)
 }
 
+// Reset disconnects the slot from the buses, as they are recreated when the
+// console is reset. The cartridge and the attached peripherals are kept.
+func (slot *HwSlot2) Reset() {
+	slot.Irq = nil
+	slot.romMaps = nil
+}
+
 func (slot *HwSlot2) UnmapCart() {
-	slot.Rom = highz[:]
+	slot.Rom = nil
+}
+
+// RemoveCart simulates pulling out the cartridge while the console is
+// running: the ROM area becomes open bus right away, and the CPU owning the
+// slot gets the slot IRQ, like on the real hardware (games use it to show
+// their "cartridge removed" error screen).
+func (slot *HwSlot2) RemoveCart() {
+	slot.UnmapCart()
+	maps := append([]slot2Map(nil), slot.romMaps...)
+	for _, m := range maps {
+		m.bus.Unmap(m.begin, m.end)
+		slot.MapRom(m.bus, m.begin, m.end)
+	}
+	if slot.Irq != nil {
+		slot.Irq.Raise(IrqSlot2)
+	}
+	modSlot2.WarnZ("cartridge removed").End()
+}
+
+// Attach a solar sensor (as found on Boktai cartridges) to the GPIO port of
+// the cartridge, with the specified initial light level.
+func (slot *HwSlot2) AttachSolarSensor(level int) *HwSolarSensor {
+	slot.Solar = NewHwSolarSensor(level)
+	return slot.Solar
+}
+
+// Map the cartridge ROM on the specified bus, within the given address range.
+// If a GPIO peripheral is attached, its registers are overlaid to the ROM
+// contents at the beginning of the range. The ROM is read-only; without a
+// cartridge, the range is open bus (see Unmapped).
+func (slot *HwSlot2) MapRom(bus *hwio.Table, begin, end uint32) {
+	slot.trackRom(bus, begin, end)
+	bus.UnmapFallback(begin, end)
+	bus.MapFallback(begin, end, slot)
+
+	if slot.Rom == nil {
+		return
+	}
+	if slot.Solar == nil {
+		bus.MapMemorySlice(begin, end, slot.Rom[:], true)
+		return
+	}
+
+	slot.Solar.rom = slot.Rom
+	bus.MapMemorySlice(begin, begin+0xC3, slot.Rom[:], true)
+	bus.MapBank(begin+0xC4, slot.Solar, 0)
+	bus.MapMemorySlice(begin+0xCC, end, slot.Rom[:], true)
+}
+
+// trackRom remembers where the ROM is mapped, so that the mapping can be
+// updated when the cartridge is removed. The slot is owned by a single CPU at
+// a time, so the mappings on a different bus are stale.
+func (slot *HwSlot2) trackRom(bus *hwio.Table, begin, end uint32) {
+	if len(slot.romMaps) > 0 && slot.romMaps[0].bus != bus {
+		slot.romMaps = nil
+	}
+	for i := range slot.romMaps {
+		if slot.romMaps[i].begin == begin {
+			slot.romMaps[i].end = end
+			return
+		}
+	}
+	slot.romMaps = append(slot.romMaps, slot2Map{bus, begin, end})
+}
+
+// Unmapped handles accesses to the ROM area when no cartridge is inserted.
+// Reads return the open bus value, that is the lower 16 bits of the halfword
+// address still latched on the multiplexed address/data lines; writes are
+// ignored.
+func (slot *HwSlot2) Unmapped(addr uint32, size int, write bool, val uint32) uint32 {
+	if write {
+		modSlot2.InfoZ("write to empty slot").Hex32("addr", addr).Hex32("val", val).End()
+		return 0
+	}
+	lo := (addr >> 1) & 0xFFFF
+	switch size {
+	case 8:
+		return (lo >> ((addr & 1) * 8)) & 0xFF
+	case 16:
+		return lo
+	default:
+		return lo | ((addr+2)>>1&0xFFFF)<<16
+	}
+}
+
+// Attach a DS Motion Pak to the slot. The motion pak replaces the cartridge
+// SRAM, so it is not possible to use it together with a GBA game.
+func (slot *HwSlot2) AttachMotionPak(input MotionInput) *HwMotionPak {
+	slot.Motion = NewHwMotionPak(input)
+	return slot.Motion
+}
+
+// Map the cartridge SRAM on the specified bus, within the given address range.
+// If the motion pak is attached, it is mapped instead.
+func (slot *HwSlot2) MapRam(bus *hwio.Table, begin, end uint32) {
+	if slot.Motion != nil {
+		bus.MapMem(begin, slot.Motion.Mem(int(end-begin+1)))
+		return
+	}
+	bus.MapMemorySlice(begin, end, slot.Ram[:], false)
 }
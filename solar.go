@@ -0,0 +1,140 @@
+package main
+
+import (
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+)
+
+var modSolar = log.NewModule("solar")
+
+const (
+	// Number of steps of the light level slider; 0 is darkness, and
+	// cSolarMaxLevel is direct sunlight.
+	cSolarMaxLevel = 10
+
+	// Counter values at which the sensor comparator triggers, for full
+	// darkness and full light. Games calibrate between these two extremes.
+	cSolarSampleDark   = 0xE8
+	cSolarSampleBright = 0x50
+)
+
+// HwSolarSensor emulates the solar sensor found on Boktai GBA cartridges
+// (also used by Lunar Knights on NDS, through slot-2). The sensor is
+// connected to the cartridge GPIO port, which is mapped within the ROM
+// header area at 0x080000C4:
+//
+//	bit 0: CLK (out)   - each rising edge increments the sensor counter
+//	bit 1: RST (out)   - resets the counter and samples the light level
+//	bit 2: CS  (out)   - chip select (active low)
+//	bit 3: FLAG (in)   - set when the counter reaches the sampled level
+//
+// The GPIO registers are only visible when GpioCnt bit 0 is set; otherwise,
+// reads return the underlying ROM contents.
+type HwSolarSensor struct {
+	GpioData hwio.Reg16 `hwio:"offset=0x0,rwmask=0xF,rcb,wcb"`
+	GpioDir  hwio.Reg16 `hwio:"offset=0x2,rwmask=0xF,rcb"`
+	GpioCnt  hwio.Reg16 `hwio:"offset=0x4,rwmask=0x1,rcb"`
+	GpioPad  hwio.Reg16 `hwio:"offset=0x6,readonly,rcb"`
+
+	rom     []byte
+	level   int
+	counter uint8
+	sample  uint8
+	edge    bool
+	flag    bool
+}
+
+func NewHwSolarSensor(level int) *HwSolarSensor {
+	sol := new(HwSolarSensor)
+	hwio.MustInitRegs(sol)
+	sol.SetLevel(level)
+	return sol
+}
+
+// Level returns the current light level (0..cSolarMaxLevel)
+func (sol *HwSolarSensor) Level() int {
+	return sol.level
+}
+
+// SetLevel changes the light level seen by the sensor. The new value is
+// sampled by the cartridge at the next counter reset.
+func (sol *HwSolarSensor) SetLevel(level int) {
+	if level < 0 {
+		level = 0
+	}
+	if level > cSolarMaxLevel {
+		level = cSolarMaxLevel
+	}
+	sol.level = level
+	modSolar.InfoZ("light level changed").Int("level", level).End()
+}
+
+func (sol *HwSolarSensor) levelSample() uint8 {
+	return uint8(cSolarSampleDark - sol.level*(cSolarSampleDark-cSolarSampleBright)/cSolarMaxLevel)
+}
+
+// romHalf reads the ROM halfword hidden by the GPIO registers. The ROM is
+// mirrored over its actual size, which is not always a power of two.
+func (sol *HwSolarSensor) romHalf(off int) uint16 {
+	n := len(sol.rom)
+	if n == 0 {
+		return 0
+	}
+	return uint16(sol.rom[off%n]) | uint16(sol.rom[(off+1)%n])<<8
+}
+
+func (sol *HwSolarSensor) ReadGPIODATA(val uint16) uint16 {
+	if sol.GpioCnt.Value&1 == 0 {
+		return sol.romHalf(0xC4)
+	}
+	// Pins configured as input reflect the sensor output
+	val &= sol.GpioDir.Value
+	if sol.flag {
+		val |= (1 << 3) &^ sol.GpioDir.Value
+	}
+	return val
+}
+
+func (sol *HwSolarSensor) ReadGPIODIR(val uint16) uint16 {
+	if sol.GpioCnt.Value&1 == 0 {
+		return sol.romHalf(0xC6)
+	}
+	return val
+}
+
+func (sol *HwSolarSensor) ReadGPIOCNT(val uint16) uint16 {
+	if sol.GpioCnt.Value&1 == 0 {
+		return sol.romHalf(0xC8)
+	}
+	return val
+}
+
+// Not part of the GPIO port, but mapped together with it to keep the 32-bit
+// bus aligned; it always reads through to the ROM.
+func (sol *HwSolarSensor) ReadGPIOPAD(_ uint16) uint16 {
+	return sol.romHalf(0xCA)
+}
+
+func (sol *HwSolarSensor) WriteGPIODATA(_, val uint16) {
+	pins := val & sol.GpioDir.Value
+
+	if pins&(1<<2) != 0 {
+		// Chip not selected
+		return
+	}
+	if pins&(1<<1) != 0 {
+		sol.counter = 0
+		sol.sample = sol.levelSample()
+	}
+	if pins&(1<<0) != 0 && sol.edge {
+		sol.counter++
+	}
+	sol.edge = pins&(1<<0) == 0
+	sol.flag = sol.counter >= sol.sample
+
+	modSolar.DebugZ("gpio write").
+		Hex16("pins", pins).
+		Uint8("counter", sol.counter).
+		Bool("flag", sol.flag).
+		End()
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestSolarRomReadThrough(t *testing.T) {
+	// Trimmed dumps have the trailing padding removed, so their size is not
+	// a power of two; the registers must still read through to the header
+	sol := NewHwSolarSensor(0)
+	sol.rom = make([]byte, 0x3B2A40)
+	copy(sol.rom[0xC4:], []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88})
+
+	for _, tc := range []struct {
+		name string
+		read func(uint16) uint16
+		exp  uint16
+	}{
+		{"data", sol.ReadGPIODATA, 0x2211},
+		{"dir", sol.ReadGPIODIR, 0x4433},
+		{"cnt", sol.ReadGPIOCNT, 0x6655},
+		{"pad", sol.ReadGPIOPAD, 0x8877},
+	} {
+		if v := tc.read(0); v != tc.exp {
+			t.Errorf("GPIO %s with port disabled: %04x, want %04x", tc.name, v, tc.exp)
+		}
+	}
+}
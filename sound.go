@@ -2,13 +2,12 @@ package main
 
 import (
 	"encoding/binary"
-	"hash/crc64"
+	"math"
 	"ndsemu/emu"
+	"ndsemu/emu/fixed"
 	"ndsemu/emu/hw"
 	"ndsemu/emu/hwio"
 	log "ndsemu/emu/logger"
-
-	"github.com/hashicorp/golang-lru/simplelru"
 )
 
 const (
@@ -24,9 +23,6 @@ const (
 	kPosNoLoop uint = ^uint(0)
 )
 
-// Checksum table used to quickly hash a voice to get a key to cache it
-var ctable = crc64.MakeTable(crc64.ECMA)
-
 type HwSoundChannel struct {
 	SndCnt hwio.Reg32 `hwio:"offset=0x00,wcb"`
 	SndSad hwio.Reg32 `hwio:"offset=0x04,rwmask=0x07FFFFFF"`
@@ -38,8 +34,18 @@ type HwSoundChannel struct {
 	idx int
 }
 
+// State of the ADPCM decoder of a voice. ADPCM samples are decoded while
+// the voice plays, directly from memory, so that streams (where the CPU keeps
+// refilling a looping buffer) are heard correctly.
+type adpcmState struct {
+	pcm   int32
+	index int16
+}
+
 type HwSound struct {
-	Bus emu.Bus
+	Bus   emu.Bus
+	Pow   *HwPowerMan
+	sched Scheduler
 
 	Ch [16]HwSoundChannel
 
@@ -51,6 +57,12 @@ type HwSound struct {
 		mode  int
 		loop  int
 		delay int
+		hist  [4]int64 // last samples played (for interpolation)
+		hpos  uint     // position of hist[3]
+
+		adpcm     adpcmState // decoder state after decoding apos samples
+		adpcmLoop adpcmState // decoder state at the loop start
+		apos      uint
 	}
 
 	capture [2]struct {
@@ -66,7 +78,25 @@ type HwSound struct {
 		reglen *uint32
 	}
 
-	cache *simplelru.LRU
+	// The SPU is a subsystem of the scheduler, clocked by the bus clock:
+	// each output sample is produced at its exact time within the frame, so
+	// that voices advance in lockstep with the CPUs and timers (which games
+	// use to refill streamed buffers), without drifting over time.
+	cycles    int64   // current time
+	lastTick  int64   // time of the last sample produced
+	out       []int16 // output buffer of the current frame (see BeginFrame)
+	outPos    int     // next sample to produce
+	outStart  int64   // time of the beginning of the frame
+	outCycles int64   // duration of the frame
+
+	// Optional enhancements of the audio output (see SoundQuality)
+	Quality SoundQuality
+	lpf     [2]int64
+	hpf     [2]int64 // speaker filter: output, previous input
+	bias    uint32   // output level of the bias (see biasRamp)
+
+	// Audio dump in progress (if any)
+	Dump *AudioDump
 
 	SndGCnt hwio.Reg32 `hwio:"bank=1,offset=0x0"`
 	// The NDS7 BIOS brings this register to 0x200 at boot, with a slow loop
@@ -81,15 +111,10 @@ type HwSound struct {
 	SndCap1Len hwio.Reg32 `hwio:"bank=1,offset=0x1C"`
 }
 
-func NewHwSound(bus emu.Bus) *HwSound {
-	cache, err := simplelru.NewLRU(128, nil)
-	if err != nil {
-		panic(err)
-	}
-
+func NewHwSound(bus emu.Bus, sched Scheduler) *HwSound {
 	snd := new(HwSound)
 	snd.Bus = bus
-	snd.cache = cache
+	snd.sched = sched
 	for i := 0; i < 16; i++ {
 		hwio.MustInitRegs(&snd.Ch[i])
 		snd.Ch[i].snd = snd
@@ -100,10 +125,27 @@ func NewHwSound(bus emu.Bus) *HwSound {
 	snd.capture[0].reglen = &snd.SndCap0Len.Value
 	snd.capture[1].reglen = &snd.SndCap1Len.Value
 	hwio.MustInitRegs(snd)
+	snd.bias = snd.SndBias.Value
 	return snd
 }
 
+// biasRamp returns the level of the output corresponding to silence. The
+// bias is removed from the output by coupling capacitors, which charge
+// slowly: changes of SOUNDBIAS (like the ramp from 0 to 0x200 performed by
+// the BIOS at power-up) are followed at about one step per sample, so that
+// they don't produce clicks or a permanent DC offset.
+func (snd *HwSound) biasRamp() uint32 {
+	switch target := snd.SndBias.Value; {
+	case snd.bias < target:
+		snd.bias++
+	case snd.bias > target:
+		snd.bias--
+	}
+	return snd.bias
+}
+
 func (ch *HwSoundChannel) WriteSNDCNT(old, new uint32) {
+	ch.snd.sync()
 	if (old^new)&(1<<31) != 0 {
 		if new&(1<<31) != 0 {
 			ch.snd.startChannel(ch.idx)
@@ -116,6 +158,7 @@ func (ch *HwSoundChannel) WriteSNDCNT(old, new uint32) {
 func (ch *HwSoundChannel) WriteSNDTMR(_, new uint16) {
 	// Å write to SNDTMR also takes effect while the voice is playing
 	// so copy the value into the latched register we increment at every tick.
+	ch.snd.sync()
 	ch.snd.voice[ch.idx].tmr = uint32(new)
 }
 
@@ -137,21 +180,22 @@ func (snd *HwSound) startChannel(idx int) {
 	v.pos = 0
 	v.delay = 3
 	v.tmr = uint32(ch.SndTmr.Value)
+	v.hist = [4]int64{}
+	v.hpos = kPosNoLoop
 	v.mode = mode
 	v.loop = loop
 
-	var sum uint64
 	switch v.mode {
 	case kModeAdpcm:
 		v.delay = 11
-		sum = crc64.Checksum(v.mem, ctable)
-		if buf, found := snd.cache.Get(sum); found {
-			v.mem = buf.([]byte)
-		} else {
-			v.mem = snd.adpcmDecompress(v.mem)
-			// go ioutil.WriteFile(fmt.Sprintf("%x.raw", sum), v.mem, 0666)
-			snd.cache.Add(sum, v.mem)
+		if len(v.mem) < 4 {
+			log.ModSound.ErrorZ("ADPCM voice without header").Int("ch", idx).End()
+			return
 		}
+		head := binary.LittleEndian.Uint32(v.mem)
+		v.adpcm = adpcmState{int32(int16(head & 0xFFFF)), int16(head>>16) & 0x7F}
+		v.adpcmLoop = v.adpcm
+		v.apos = 0
 	case kModePsgNoise:
 		v.delay = 1
 		if idx >= 8 || idx <= 13 {
@@ -173,7 +217,6 @@ func (snd *HwSound) startChannel(idx int) {
 		Hex32("rpos", ch.SndSad.Value).
 		Uint32("len", length).
 		Uint("ptlen", uint(ch.SndPnt.Value)*4).
-		Hex64("sum", sum).
 		Int("loop", loop).
 		Hex16("tmr", ch.SndTmr.Value).
 		Int64("clk", nds7.Cycles()).
@@ -188,24 +231,58 @@ func (snd *HwSound) stopChannel(idx int) {
 	log.ModSound.InfoZ("stop channel").Int("idx", idx).End()
 }
 
+// loopChannel returns the loop start of a voice, in samples (kPosNoLoop if
+// the voice doesn't loop)
 func (snd *HwSound) loopChannel(idx int) uint {
 	if snd.voice[idx].loop == kLoopInfinite {
-		off := snd.Ch[idx].SndPnt.Value * 4
+		off := uint(snd.Ch[idx].SndPnt.Value) * 4
 		switch snd.voice[idx].mode {
 		case kModeAdpcm:
-			off -= 4
-			fallthrough
+			// The loop start includes the header; each byte has two samples
+			if off < 4 {
+				off = 4
+			}
+			return (off - 4) * 2
 		case kMode16bit:
-			off /= 2
+			return off / 2
 		}
-		return uint(off)
+		return off
 	}
 	return kPosNoLoop
 }
 
+// adpcmSample decodes the ADPCM samples of a voice up to its current
+// position, and returns the sample at that position. The decoder state is
+// saved when the loop start is reached for the first time, and restored
+// when looping (see adpcmRestart).
+func (snd *HwSound) adpcmSample(idx int) int64 {
+	v := &snd.voice[idx]
+	loop := snd.loopChannel(idx)
+	for v.apos <= v.pos {
+		if v.apos == loop {
+			v.adpcmLoop = v.adpcm
+		}
+		b := v.mem[4+v.apos/2]
+		if v.apos&1 != 0 {
+			b >>= 4
+		}
+		v.adpcm.decode(b & 0xF)
+		v.apos++
+	}
+	return int64(v.adpcm.pcm)
+}
+
+// adpcmRestart rewinds the decoder of a voice to the loop start
+func (snd *HwSound) adpcmRestart(idx int) {
+	v := &snd.voice[idx]
+	v.adpcm = v.adpcmLoop
+	v.apos = snd.loopChannel(idx)
+}
+
 func (snd *HwSound) WriteSNDCAP0CNT(old, new uint8) { snd.writeSNDCAPCNT(0, old, new) }
 func (snd *HwSound) WriteSNDCAP1CNT(old, new uint8) { snd.writeSNDCAPCNT(1, old, new) }
 func (snd *HwSound) writeSNDCAPCNT(idx int, old, new uint8) {
+	snd.sync()
 	if (old^new)&(1<<7) != 0 {
 		if new&(1<<7) != 0 {
 			snd.startCapture(idx, new)
@@ -268,65 +345,146 @@ var (
 	}
 )
 
-func (snd *HwSound) adpcmDecompress(buf []byte) []byte {
-	// ioutil.WriteFile("sound.adpcm", buf, 0666)
-	head := binary.LittleEndian.Uint32(buf[:4])
-	buf = buf[4:]
-	pcm := int32(int16(head & 0xFFFF))
-	index := int16(head>>16) & 0x7F
-
-	res := make([]byte, 0, len(buf)*4)
-
-	dec := func(sample uint8) {
-		diff := adpcmTable[index] / 8
-		diff += (adpcmTable[index] / 4) * uint16((sample>>0)&1)
-		diff += (adpcmTable[index] / 2) * uint16((sample>>1)&1)
-		diff += (adpcmTable[index] / 1) * uint16((sample>>2)&1)
-		if sample&8 == 0 {
-			pcm += int32(diff)
-			if pcm > 0x7FFF {
-				pcm = 0x7FFF
-			}
-		} else {
-			pcm -= int32(diff)
-			if pcm < -0x7FFF {
-				pcm = -0x7FFF
-			}
+// decode updates the decoder state with the next ADPCM sample (4 bits)
+func (st *adpcmState) decode(sample uint8) {
+	diff := adpcmTable[st.index] / 8
+	diff += (adpcmTable[st.index] / 4) * uint16((sample>>0)&1)
+	diff += (adpcmTable[st.index] / 2) * uint16((sample>>1)&1)
+	diff += (adpcmTable[st.index] / 1) * uint16((sample>>2)&1)
+	if sample&8 == 0 {
+		st.pcm += int32(diff)
+		if st.pcm > 0x7FFF {
+			st.pcm = 0x7FFF
 		}
-
-		index += adpcmIndexTable[sample&7]
-		if index < 0 {
-			index = 0
-		} else if index > 88 {
-			index = 88
+	} else {
+		st.pcm -= int32(diff)
+		if st.pcm < -0x7FFF {
+			st.pcm = -0x7FFF
 		}
 	}
 
-	for i := range buf {
-		dec(buf[i] & 0xF)
-		res = append(res, uint8(pcm&0xFF))
-		res = append(res, uint8((pcm>>8)&0xFF))
-		dec(buf[i] >> 4)
-		res = append(res, uint8(pcm&0xFF))
-		res = append(res, uint8((pcm>>8)&0xFF))
+	st.index += adpcmIndexTable[sample&7]
+	if st.index < 0 {
+		st.index = 0
+	} else if st.index > 88 {
+		st.index = 88
 	}
+}
 
-	return res
+// BeginFrame sets the buffer that will receive the audio output (interleaved
+// stereo samples) of the frame that is about to be emulated, which lasts the
+// specified number of cycles. Samples are spread evenly over the frame, and
+// are produced while the frame is emulated.
+func (snd *HwSound) BeginFrame(buf []int16, frameCycles int64) {
+	snd.out = buf
+	snd.outPos = 0
+	snd.outStart = snd.cycles
+	snd.outCycles = frameCycles
 }
 
-func (snd *HwSound) RunOneFrame(buf []int16) {
-	for i := 0; i < len(buf); i += 2 {
-		l, r := snd.step()
+// EndFrame completes the audio output of the current frame, and detaches the
+// output buffer.
+func (snd *HwSound) EndFrame() {
+	if snd.out == nil {
+		return
+	}
+	snd.Run(snd.outStart + snd.outCycles)
+	for i := snd.outPos * 2; i < len(snd.out); i++ {
+		snd.out[i] = 0
+	}
+	if snd.Dump != nil {
+		snd.Dump.writeMix(snd.out)
+	}
+	snd.out = nil
+}
 
-		// Extend to 16-bit range
-		l = l<<6 | l>>4
-		r = r<<6 | r>>4
+func (snd *HwSound) Frequency() fixed.F8 {
+	return fixed.NewF8(cBusClock)
+}
 
-		buf[i] = int16(l - 0x8000)
-		buf[i+1] = int16(r - 0x8000)
+func (snd *HwSound) Reset() {
+	snd.cycles = 0
+	snd.lastTick = 0
+	snd.out = nil
+}
+
+func (snd *HwSound) Cycles() int64 {
+	return snd.cycles
+}
+
+// Run produces all the output samples that are due until the specified
+// time. Between two samples, voices and captures advance by the exact number
+// of timer ticks elapsed (the SPU timers run at half the bus clock).
+func (snd *HwSound) Run(target int64) {
+	if target <= snd.cycles {
+		return
+	}
+	snd.cycles = target
+	if snd.out == nil {
+		snd.lastTick = target
+		return
+	}
+
+	nsamples := int64(len(snd.out) / 2)
+	for snd.outPos < int(nsamples) {
+		when := snd.outStart + (int64(snd.outPos)+1)*snd.outCycles/nsamples
+		if when > target {
+			break
+		}
+		ticks := uint32(when/2 - snd.lastTick/2)
+		snd.lastTick = when
+
+		var l, r uint16
+		if snd.Pow == nil || snd.Pow.AudioEnabled() {
+			l, r = snd.output(ticks)
+		}
+		snd.out[snd.outPos*2] = int16(l)
+		snd.out[snd.outPos*2+1] = int16(r)
+		snd.outPos++
+	}
+}
+
+// sync brings the SPU up to date with the current time. It must be called
+// before any change to the registers affecting the audio output.
+func (snd *HwSound) sync() {
+	if snd.sched != nil {
+		snd.Run(snd.sched.Cycles())
 	}
 }
 
+// output emulates the SPU for the specified number of timer ticks, and
+// returns the resulting (signed) output sample
+func (snd *HwSound) output(ticks uint32) (uint16, uint16) {
+	l, r := snd.step(ticks)
+	if snd.Dump != nil {
+		snd.Dump.endTick()
+	}
+
+	// Extend to 16-bit range, and remove the bias
+	bias := snd.biasRamp()
+	bias = bias<<6 | bias>>4
+	l = l<<6 | l>>4
+	r = r<<6 | r>>4
+
+	ls := clampInt16(int64(l) - int64(bias))
+	rs := clampInt16(int64(r) - int64(bias))
+	if snd.Quality.Output == SoundOutputSpeaker {
+		ls, rs = snd.speaker(ls, rs)
+	} else if snd.Quality.LowPass {
+		ls, rs = snd.lowPass(ls, rs)
+	}
+	return uint16(ls), uint16(rs)
+}
+
+func clampInt16(s int64) int64 {
+	if s < math.MinInt16 {
+		return math.MinInt16
+	} else if s > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	return s
+}
+
 func mulvol64(s int64, vol int64) int64 {
 	if vol == 127 {
 		return s
@@ -334,8 +492,9 @@ func mulvol64(s int64, vol int64) int64 {
 	return (s * vol) >> 7
 }
 
-// Emulate one tick of audio, producing a couple of (unsigned) 16-bit audio samples
-func (snd *HwSound) step() (uint16, uint16) {
+// Emulate one tick of audio (the specified number of timer ticks since the
+// previous one), producing a couple of (unsigned) 16-bit audio samples
+func (snd *HwSound) step(ticks uint32) (uint16, uint16) {
 	var lmix, rmix int64
 	var chbuf [4]int64
 
@@ -382,7 +541,7 @@ func (snd *HwSound) step() (uint16, uint16) {
 			continue
 		}
 
-		voice.tmr += cTimerStepPerSample
+		voice.tmr += ticks
 		for voice.tmr >= 0x10000 {
 			if voice.delay >= 0 {
 				voice.delay--
@@ -405,7 +564,18 @@ func (snd *HwSound) step() (uint16, uint16) {
 				}
 			}
 			sample = int64(int8(voice.mem[voice.pos])) << 8
-		case kMode16bit, kModeAdpcm:
+		case kModeAdpcm:
+			total := uint(len(voice.mem)-4) * 2
+			if voice.pos >= total {
+				voice.pos = voice.pos + snd.loopChannel(i) - total
+				if voice.pos == kPosNoLoop || voice.pos >= total {
+					snd.stopChannel(i)
+					continue
+				}
+				snd.adpcmRestart(i)
+			}
+			sample = snd.adpcmSample(i)
+		case kMode16bit:
 			if int(voice.pos*2+1) >= len(voice.mem) {
 				voice.pos = voice.pos + snd.loopChannel(i) - uint(len(voice.mem)/2)
 				if voice.pos == kPosNoLoop || int(voice.pos*2+1) >= len(voice.mem) {
@@ -421,6 +591,15 @@ func (snd *HwSound) step() (uint16, uint16) {
 			sample = int64(int16(binary.LittleEndian.Uint16(voice.mem[voice.pos*2:])))
 		}
 
+		if snd.Quality.Interp != SoundInterpNone {
+			if voice.pos != voice.hpos {
+				copy(voice.hist[:3], voice.hist[1:])
+				voice.hist[3] = sample
+				voice.hpos = voice.pos
+			}
+			sample = interpolate(snd.Quality.Interp, &voice.hist, snd.voiceFrac(i))
+		}
+
 		// Convert into fixed point to keep some precision
 		sample <<= 8
 
@@ -447,6 +626,9 @@ func (snd *HwSound) step() (uint16, uint16) {
 		pan := int64((cntrl >> 16) & 127)
 		lsample := mulvol64(sample, 127-pan)
 		rsample := mulvol64(sample, pan)
+		if snd.Dump != nil {
+			snd.Dump.channel(i, lsample, rsample)
+		}
 
 		// Mix
 		lmix += int64(lsample)
@@ -477,7 +659,7 @@ func (snd *HwSound) step() (uint16, uint16) {
 				panic("capture with addition")
 			}
 
-			cap.tmr += cTimerStepPerSample
+			cap.tmr += ticks
 			for cap.tmr >= 0x10000 {
 				if cap.bit8 {
 					snd.Bus.Write8(cap.wpos, uint8(sample>>16))
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSoundAdpcmStreaming(t *testing.T) {
+	snd := NewHwSound(nil, nil)
+	mem := []byte{0x00, 0x01, 0x10, 0x00, 0x77, 0x77, 0x77, 0x77, 0x19, 0x2A, 0x3B, 0x4C}
+	snd.Ch[0].SndPnt.Value = 2 // loop at the second word (nibble 8)
+	v := &snd.voice[0]
+	v.mem = mem
+	v.mode = kModeAdpcm
+	v.loop = kLoopInfinite
+	v.adpcm = adpcmState{0x100, 0x10}
+
+	if loop := snd.loopChannel(0); loop != 8 {
+		t.Fatalf("invalid loop start: %d", loop)
+	}
+
+	var first []int64
+	for v.pos = 0; v.pos < 16; v.pos++ {
+		first = append(first, snd.adpcmSample(0))
+	}
+
+	// Refill the looping part, as a streaming engine would do
+	mem[8], mem[9], mem[10], mem[11] = 0x88, 0x88, 0x88, 0x88
+	ref := v.adpcmLoop
+	v.pos = 8
+	snd.adpcmRestart(0)
+	for ; v.pos < 16; v.pos++ {
+		ref.decode(mem[4+v.pos/2] >> (4 * (v.pos & 1)) & 0xF)
+		if s := snd.adpcmSample(0); s != int64(ref.pcm) {
+			t.Errorf("sample %d: got %d, want %d", v.pos, s, ref.pcm)
+		}
+	}
+	if first[8] == int64(ref.pcm) {
+		t.Errorf("refilled data was not decoded")
+	}
+}
+
+func TestSoundFrameTiming(t *testing.T) {
+	snd := NewHwSound(nil, nil)
+	buf := make([]int16, 10*2)
+	snd.BeginFrame(buf, 1000)
+
+	snd.Run(450)
+	if snd.outPos != 4 {
+		t.Errorf("after 450 cycles: %d samples", snd.outPos)
+	}
+	snd.Run(999)
+	if snd.outPos != 9 {
+		t.Errorf("after 999 cycles: %d samples", snd.outPos)
+	}
+	snd.EndFrame()
+	if snd.outPos != 10 || snd.lastTick != 1000 || snd.out != nil {
+		t.Errorf("invalid state at end of frame: %d samples, last tick %d", snd.outPos, snd.lastTick)
+	}
+
+	// The next frame starts where the previous one ended
+	snd.BeginFrame(buf, 1000)
+	if snd.outStart != 1000 {
+		t.Errorf("invalid frame start: %d", snd.outStart)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Optional audio enhancements, not present on the real hardware. The SPU
+// plays each voice by simply holding the current sample until the timer
+// moves to the next one (nearest-neighbour resampling), which sounds harsh
+// for low-pitched samples; voices can instead be interpolated between
+// consecutive samples. Interpolation is based on the history of the samples
+// already played, so it adds a delay of one (linear, cosine) or two (cubic)
+// samples, which is not audible.
+//
+// The DS speakers and headphone amplifier also smooth the output of the
+// PWM DAC; this can be approximated with a low-pass filter on the final mix.
+//
+// Finally, the output can be heard through the headphones (stereo, as
+// produced by the mixer) or through the speakers, which are so close
+// together that the stereo image is mostly lost, and which can't reproduce
+// low frequencies. Some games pan effects assuming the speakers, so they
+// sound odd in stereo.
+
+type SoundOutput int
+
+const (
+	SoundOutputHeadphone SoundOutput = iota
+	SoundOutputSpeaker
+)
+
+var soundOutputNames = [...]string{"headphone", "speaker"}
+
+func (o SoundOutput) String() string { return soundOutputNames[o] }
+
+// ParseSoundOutput parses the name of an output model (as returned by String)
+func ParseSoundOutput(s string) (SoundOutput, error) {
+	for i := range soundOutputNames {
+		if strings.EqualFold(s, soundOutputNames[i]) {
+			return SoundOutput(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid audio output: %q (use headphone or speaker)", s)
+}
+
+type SoundInterp int
+
+const (
+	SoundInterpNone SoundInterp = iota
+	SoundInterpLinear
+	SoundInterpCosine
+	SoundInterpCubic
+)
+
+var soundInterpNames = [...]string{"none", "linear", "cosine", "cubic"}
+
+func (i SoundInterp) String() string { return soundInterpNames[i] }
+
+// ParseSoundInterp parses the name of an interpolation mode (as returned by
+// String)
+func ParseSoundInterp(s string) (SoundInterp, error) {
+	for i := range soundInterpNames {
+		if strings.EqualFold(s, soundInterpNames[i]) {
+			return SoundInterp(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid audio interpolation: %q (use none, linear, cosine or cubic)", s)
+}
+
+type SoundQuality struct {
+	Interp  SoundInterp
+	LowPass bool
+	Output  SoundOutput
+}
+
+const (
+	// Cutoff frequency of the low-pass filter, in Hz
+	cSoundLowPassCutoff = 8000
+
+	// Cutoff frequency of the high-pass filter approximating the speakers
+	// (which are always low-passed as well), in Hz
+	cSoundSpeakerCutoff = 300
+
+	cInterpFracBits = 16
+)
+
+var (
+	// Coefficient of the one-pole low-pass filter (16.16 fixed point)
+	soundLowPassAlpha = int64((1 - math.Exp(-2*math.Pi*cSoundLowPassCutoff/cAudioFreq)) * 0x10000)
+
+	// Coefficient of the one-pole high-pass filter (16.16 fixed point)
+	soundHighPassAlpha = int64(math.Exp(-2*math.Pi*cSoundSpeakerCutoff/cAudioFreq) * 0x10000)
+
+	// Cosine interpolation curve, indexed by the top 8 bits of the fraction
+	// (16.16 fixed point)
+	cosineTable [256]int64
+)
+
+func init() {
+	for i := range cosineTable {
+		mu := float64(i) / float64(len(cosineTable))
+		cosineTable[i] = int64((1 - math.Cos(mu*math.Pi)) / 2 * 0x10000)
+	}
+}
+
+// interpolate computes the sample to output for the voice, given the
+// history of its samples (h[3] is the current one) and the position between
+// samples (16-bit fraction).
+func interpolate(mode SoundInterp, h *[4]int64, frac int64) int64 {
+	switch mode {
+	case SoundInterpLinear:
+		return h[2] + ((h[3]-h[2])*frac)>>cInterpFracBits
+	case SoundInterpCosine:
+		mu := cosineTable[frac>>(cInterpFracBits-8)]
+		return h[2] + ((h[3]-h[2])*mu)>>cInterpFracBits
+	case SoundInterpCubic:
+		// Catmull-Rom spline between h[1] and h[2]
+		t := frac
+		a := -h[0] + 3*h[1] - 3*h[2] + h[3]
+		b := 2*h[0] - 5*h[1] + 4*h[2] - h[3]
+		c := -h[0] + h[2]
+		v := (a * t) >> cInterpFracBits
+		v = ((v + b) * t) >> cInterpFracBits
+		v = ((v + c) * t) >> cInterpFracBits
+		return h[1] + v/2
+	}
+	return h[3]
+}
+
+// voiceFrac returns the position of the voice between the current sample and
+// the next one, as a 16-bit fraction
+func (snd *HwSound) voiceFrac(idx int) int64 {
+	reload := uint32(snd.Ch[idx].SndTmr.Value)
+	tmr := snd.voice[idx].tmr
+	if tmr <= reload {
+		return 0
+	}
+	return int64(tmr-reload) << cInterpFracBits / int64(0x10000-reload)
+}
+
+// lowPass applies the DAC low-pass filter to a stereo output sample
+func (snd *HwSound) lowPass(l, r int64) (int64, int64) {
+	snd.lpf[0] += ((l - snd.lpf[0]) * soundLowPassAlpha) >> 16
+	snd.lpf[1] += ((r - snd.lpf[1]) * soundLowPassAlpha) >> 16
+	return snd.lpf[0], snd.lpf[1]
+}
+
+// speaker converts a stereo output sample to what is heard through the
+// speakers: the channels are mixed, and the result is band-passed.
+func (snd *HwSound) speaker(l, r int64) (int64, int64) {
+	m := (l + r) / 2
+	snd.hpf[0] = ((snd.hpf[0] + m - snd.hpf[1]) * soundHighPassAlpha) >> 16
+	snd.hpf[1] = m
+	return snd.lowPass(snd.hpf[0], snd.hpf[0])
+}
+
+// SetSoundQuality changes the audio interpolation and filtering. It can be
+// changed at runtime (between frames), for instance through the bridge.
+func (emu *NDSEmulator) SetSoundQuality(q SoundQuality) {
+	emu.soundQuality = q
+	emu.Hw.Snd.Quality = q
+}
+
+func (emu *NDSEmulator) SoundQuality() SoundQuality {
+	return emu.soundQuality
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var stateHashTable = crc64.MakeTable(crc64.ECMA)
+
+// StateHash returns a hash of the full machine state: all memories, the
+// state of both CPUs, the scheduler clock, the I/O registers of all
+// hardware components, and the internal state of the devices that is
+// stored in savestates (timers, DMA latches, IPC FIFOs, gamecard, geometry
+// engine, SPU voices; see saveState). Two runs of the emulation that reach
+// the same state have the same hash; it is used to audit determinism (see
+// StateAudit), and to check that savestates restore the state exactly.
+//
+// The internal state of the devices that isn't saved is only covered
+// indirectly, when it affects the rest of the state.
+func (emu *NDSEmulator) StateHash() uint64 {
+	h := crc64.New(stateHashTable)
+	h.Write(emu.Mem.Ram[:])
+	h.Write(emu.Mem.Vram[:])
+	h.Write(emu.Mem.Wram[:])
+	h.Write(emu.Mem.PaletteRam[:])
+	h.Write(emu.Mem.OamRam[:])
+	h.Write(emu.Hw.Mc.wram[:])
+
+	nds9.Cpu.HashState(h)
+	nds7.Cpu.HashState(h)
+	binary.Write(h, binary.LittleEndian, emu.Sync.Cycles())
+
+	var buf [8]byte
+	for _, dev := range emu.ioDevices() {
+		for _, reg := range hwio.RegList(dev.regs) {
+			binary.LittleEndian.PutUint64(buf[:], reg.Value)
+			h.Write(buf[:])
+		}
+	}
+
+	// The rest of the savestate (memories, CPUs and registers are hashed
+	// above; the registers map would also make the encoding unstable)
+	st := emu.saveState()
+	st.Ram, st.Vram, st.Wram, st.PaletteRam, st.OamRam, st.SharedWram = nil, nil, nil, nil, nil, nil
+	st.Cpu9, st.Cpu7, st.Regs = nil, nil, nil
+	if err := gob.NewEncoder(h).Encode(st); err != nil {
+		panic(err)
+	}
+	return h.Sum64()
+}
+
+// StateHashes is a stream of state hashes, one per emulated frame
+type StateHashes []uint64
+
+// ReadStateHashes parses a stream of hashes written by Write
+func ReadStateHashes(r io.Reader) (StateHashes, error) {
+	var hashes StateHashes
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		first, rest := splitWord(line)
+		frame, err := strconv.Atoi(first)
+		if err != nil || frame != len(hashes)+1 {
+			return nil, fmt.Errorf("line %d: invalid frame number: %q", lineno, first)
+		}
+		h, err := strconv.ParseUint(rest, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid hash: %q", lineno, rest)
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, scanner.Err()
+}
+
+// Write writes the hashes in text format, one line per frame ("FRAME HASH",
+// with frames numbered from 1).
+func (hs StateHashes) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, h := range hs {
+		fmt.Fprintf(bw, "%d %016x\n", i+1, h)
+	}
+	return bw.Flush()
+}
+
+// FirstDivergence returns the first frame (numbered from 1) where the two
+// streams differ, or 0 if they are identical. If one stream is a prefix of
+// the other, the first frame past the shorter one is returned.
+func (hs StateHashes) FirstDivergence(other StateHashes) int {
+	for i := range hs {
+		if i >= len(other) || hs[i] != other[i] {
+			return i + 1
+		}
+	}
+	if len(other) > len(hs) {
+		return len(hs) + 1
+	}
+	return 0
+}
+
+// StateAudit implements the determinism audit mode: it hashes the machine
+// state at the end of every frame, recording the hash stream and comparing
+// it against a reference stream recorded by a previous run (eg: of the same
+// input replay), to report the first frame where the emulation diverged.
+type StateAudit struct {
+	Hashes    StateHashes // recorded so far
+	Reference StateHashes // expected stream (nil if none)
+	Diverged  int         // first divergent frame (0 if none so far)
+}
+
+// LoadReference loads the reference stream from a file
+func (a *StateAudit) LoadReference(fn string) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	a.Reference, err = ReadStateHashes(f)
+	return err
+}
+
+// Frame must be called at the end of each frame
+func (a *StateAudit) Frame(emu *NDSEmulator) {
+	h := emu.StateHash()
+	a.Hashes = append(a.Hashes, h)
+	frame := len(a.Hashes)
+	if a.Diverged != 0 || a.Reference == nil || frame > len(a.Reference) {
+		return
+	}
+	if want := a.Reference[frame-1]; h != want {
+		a.Diverged = frame
+		log.ModEmu.ErrorZ("state diverged from reference").
+			Int("frame", frame).
+			Hex64("hash", h).
+			Hex64("want", want).
+			End()
+	}
+}
+
+// Restart discards the hashes recorded so far, so that the stream (and the
+// comparison with the reference) starts again from the next frame. It is
+// called when a savestate is loaded, as the previous frames don't lead to
+// the restored state: streams recorded after loading the same savestate can
+// be compared.
+func (a *StateAudit) Restart() {
+	a.Hashes = nil
+	a.Diverged = 0
+	log.ModEmu.WarnZ("state hashes restarted after loading a savestate").End()
+}
+
+// Save writes the recorded hash stream to a file
+func (a *StateAudit) Save(fn string) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	if err := a.Hashes.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
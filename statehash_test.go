@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStateHashes(t *testing.T) {
+	hs := StateHashes{0x1234, 0xDEADBEEFCAFEBABE, 7}
+	var buf bytes.Buffer
+	if err := hs.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	hs2, err := ReadStateHashes(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := hs.FirstDivergence(hs2); d != 0 {
+		t.Errorf("round-trip diverged at frame %d: %x", d, hs2)
+	}
+
+	for _, tc := range []struct {
+		other StateHashes
+		frame int
+	}{
+		{StateHashes{0x1234, 0, 7}, 2},
+		{StateHashes{0x1234}, 2},
+		{StateHashes{0x1234, 0xDEADBEEFCAFEBABE, 7, 8}, 4},
+		{nil, 1},
+	} {
+		if d := hs.FirstDivergence(tc.other); d != tc.frame {
+			t.Errorf("%x: diverged at frame %d, want %d", tc.other, d, tc.frame)
+		}
+	}
+
+	for _, bad := range []string{"2 1234", "1 xyz", "1"} {
+		if _, err := ReadStateHashes(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
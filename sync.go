@@ -8,31 +8,41 @@ const (
 	cBusClock  = int64(0x1FF61FE) // 33.513982 Mhz
 	cNds7Clock = cBusClock
 	cNds9Clock = cBusClock * 2
+	cGbaClock  = cBusClock / 2
 
 	cEmuClock = cBusClock
 )
 
-const (
-	// Audio Frequency. In most games the exact frequency does not
-	// really matter (within some reasonable bounds), but a few games
-	// stream musics into a circular buffer, using VMatch IRQ to syncrhonize
-	// with audio; in those case, we need a perfect audio frequency
-	// otherwise we can hear statics in audio. Examples: Animal Crossing
-	// (title screen), Who Wants To Be A Millionaire (voices).
+// Audio Frequency of the output. This is only the rate at which the output
+// is sampled: the SPU itself is emulated in lockstep with the bus clock (see
+// HwSound.Run), so that games streaming musics into a circular buffer
+// (refilled at timer or VMatch IRQs) stay in sync with it. Examples: Animal
+// Crossing (title screen), Who Wants To Be A Millionaire (voices).
+const cAudioFreq = 32768
 
-	// This value is probably not precise, but it works for now
-	// (at least until we don't understand why the formula below
-	// requires a magic adjustment)
-	cAudioFreq = 32768
+// audioFrameSamples returns the number of audio samples (per channel)
+// produced by the specified frame: the audio frequency is not a multiple of
+// the frame rate, so frames alternate between two lengths to keep the exact
+// frequency over each second.
+func audioFrameSamples(frame int) int {
+	fc := frame % 60
+	return cAudioFreq*(fc+1)/60 - cAudioFreq*fc/60
+}
 
-	// Calculate how much the audio timers increment for every
-	// sound tick. Theoretically, the formula below is correct
-	// (modulo truncation) but we need to add a magic constant to mostly fix
-	// statics in some games, and it's still not perfect, so we're not
-	// fully understanding this yet.
-	cAudioBugAdjust     = 2
-	cTimerStepPerSample = uint32((cEmuClock / 2 / cAudioFreq) + cAudioBugAdjust)
-)
+// Scheduler gives devices access to the emulated time, and lets them request
+// synchronization points and timed events. It is implemented by emu.Sync;
+// devices receive it at construction rather than going through the global
+// emulator instance.
+type Scheduler interface {
+	Cycles() int64
+	DotPos() (int, int)
+	DotPosDistance(x, y int) int64
+	ScheduleSync(when int64)
+	CancelSync(when int64)
+	ScheduleEvent(when int64, cb func())
+}
+
+var _ Scheduler = (*emu.Sync)(nil)
 
 // NDS SYNC
 
@@ -61,7 +71,7 @@ var GbaLcdConfig = HwLcdConfig{
 }
 
 var GbaSyncConfig = &emu.SyncConfig{
-	MainClock:       cBusClock / 2, // this is what happens on NDS hardware
+	MainClock:       cGbaClock, // this is what happens on NDS hardware
 	DotClockDivider: 4,
 	HDots:           308,
 	VDots:           228,
@@ -10,8 +10,9 @@ import (
 const cTimerClock = cBusClock
 
 type HwTimer struct {
-	Reload  hwio.Reg16 `hwio:"offset=0x0,rcb,wcb"`
-	Control hwio.Reg16 `hwio:"offset=0x2,rwmask=0xC7,wcb"`
+	_       hwio.BankSize `hwio:"size=0x4"`
+	Reload  hwio.Reg16    `hwio:"offset=0x0,rcb,wcb"`
+	Control hwio.Reg16    `hwio:"offset=0x2,rwmask=0xC7,wcb"`
 	counter uint16
 
 	name   string
@@ -19,6 +20,9 @@ type HwTimer struct {
 	next   *HwTimer
 	irqt   bool
 	sync   int64
+	sched  Scheduler
+
+	onOverflow func(when int64)
 }
 
 func (t *HwTimer) running() bool { return t.Control.Value&0x80 != 0 }
@@ -41,7 +45,7 @@ func (t *HwTimer) scaler() int {
 
 func (t *HwTimer) reschedule() {
 	if t.sync != 0 {
-		Emu.Sync.CancelSync(t.sync)
+		t.sched.CancelSync(t.sync)
 		t.sync = 0
 	}
 
@@ -50,17 +54,17 @@ func (t *HwTimer) reschedule() {
 		t.sync = t.cycles + int64(ticks*t.scaler())
 		// log.ModTimer.DebugZ("schedule sync").
 		// 	String("name", t.name).
-		// 	Int64("now", Emu.Sync.Cycles()).
+		// 	Int64("now", t.sched.Cycles()).
 		// 	Int64("now2", t.cycles).
 		// 	Int64("when", t.sync).
 		// 	End()
-		Emu.Sync.ScheduleSync(t.sync)
+		t.sched.ScheduleSync(t.sync)
 	}
 }
 
 func (t *HwTimer) WriteRELOAD(old, val uint16) {
 	t.Reload.Value = old
-	t.Run(Emu.Sync.Cycles())
+	t.Run(t.sched.Cycles())
 	t.Reload.Value = val
 
 	log.ModTimer.InfoZ("write reload").
@@ -73,7 +77,7 @@ func (t *HwTimer) WriteCONTROL(old, val uint16) {
 	t.Control.Value = old
 	wasrunning := t.running()
 
-	t.Run(Emu.Sync.Cycles())
+	t.Run(t.sched.Cycles())
 
 	log.ModTimer.InfoZ("write control").
 		String("name", t.name).
@@ -90,15 +94,18 @@ func (t *HwTimer) WriteCONTROL(old, val uint16) {
 
 func (t *HwTimer) ReadRELOAD(_ uint16) uint16 {
 	// Reading reload actually accesses the current counter
-	t.Run(Emu.Sync.Cycles())
+	t.Run(t.sched.Cycles())
 	return t.counter
 }
 
-// Handle an overflow event
-func (t *HwTimer) overflow() {
+// Handle an overflow event, happened at the specified time
+func (t *HwTimer) overflow(when int64) {
 	t.counter = t.Reload.Value
+	if t.onOverflow != nil {
+		t.onOverflow(when)
+	}
 	if t.next != nil && t.next.countup() {
-		t.next.up()
+		t.next.up(when)
 	}
 	if t.irq() {
 		if t.irqt {
@@ -107,7 +114,7 @@ func (t *HwTimer) overflow() {
 				Int("scaler", t.scaler()).
 				End()
 		} else {
-			// t.log().WithField("cycles", Emu.Sync.Cycles()).Infof("overflow")
+			// t.log().WithField("cycles", t.sched.Cycles()).Infof("overflow")
 		}
 		t.irqt = true
 	}
@@ -116,7 +123,7 @@ func (t *HwTimer) overflow() {
 
 // Increment the timer by one; this is meant to be used only
 // on countup timers
-func (t *HwTimer) up() {
+func (t *HwTimer) up(when int64) {
 	if !t.running() {
 		// we don't know whether a countup timer should respect
 		// the start/stop bit. Probably it does, but we need to
@@ -128,7 +135,7 @@ func (t *HwTimer) up() {
 	}
 	t.counter++
 	if t.counter == 0 {
-		t.overflow()
+		t.overflow(when)
 	}
 }
 
@@ -172,7 +179,7 @@ func (t *HwTimer) Run(target int64) {
 			elapsed = 0
 		} else {
 			t.cycles += span * scaler
-			t.overflow()
+			t.overflow(t.cycles)
 			elapsed -= span
 		}
 	}
@@ -181,11 +188,17 @@ func (t *HwTimer) Run(target int64) {
 type HwTimers struct {
 	Irq    *HwIrq
 	Timers [4]HwTimer
+	sched  Scheduler
+
+	// Optional callback invoked at each overflow (eg: to clock the GBA
+	// sound FIFOs), with the index of the timer and the time of the overflow
+	Overflow func(timer int, when int64)
 }
 
-func NewHWTimers(name string, irq *HwIrq) *HwTimers {
+func NewHWTimers(name string, irq *HwIrq, sched Scheduler) *HwTimers {
 	t := &HwTimers{
-		Irq: irq,
+		Irq:   irq,
+		sched: sched,
 	}
 	t.Reset()
 	t.SetName(name)
@@ -200,12 +213,18 @@ func (t *HwTimers) SetName(prefix string) {
 
 func (t *HwTimers) Reset() {
 	for i := range t.Timers {
-		t.Timers[i] = HwTimer{}
+		t.Timers[i] = HwTimer{sched: t.sched}
 		hwio.MustInitRegs(&t.Timers[i])
 		t.Timers[i].Reload.WriteCb = t.Timers[i].WriteRELOAD
 		if i != 3 {
 			t.Timers[i].next = &t.Timers[i+1]
 		}
+		idx := i
+		t.Timers[i].onOverflow = func(when int64) {
+			if t.Overflow != nil {
+				t.Overflow(idx, when)
+			}
+		}
 	}
 }
 
@@ -8,26 +8,148 @@ import (
 
 var modTsc = log.NewModule("tsc")
 
+// UserSettingsSource gives access to the firmware user settings, as copied
+// into main RAM by the firmware at boot (see ReadFirmwareUserSettings for the
+// layout).
+type UserSettingsSource interface {
+	UserSettings() []byte
+}
+
+// HwTouchScreen emulates the touchscreen controller (TSC2046).
+//
+// The host pen position is sampled once per frame, while games can sample
+// the touchscreen several times per frame (drawing games do, to get smooth
+// strokes). Unless Raw is set, while the pen is dragged, the position
+// reported by the ADC moves linearly over the frame, from the previous
+// position to the new one, so that fast drags produce continuous strokes
+// instead of jumping once per frame. This delays the pen by (at most) one
+// frame.
 type HwTouchScreen struct {
-	penX, penY int
-	penDown    bool
+	Raw bool // report the host pen position as-is, without interpolation
+
+	penX, penY   int
+	penDown      bool
+	pressure     float64 // 0 (lightest) to 1 (hardest)
+	prevX, prevY int     // pen position at the beginning of the frame
+	interp       bool    // interpolate from prevX/prevY to penX/penY
+	setAt        int64   // time of the last SetPen
+	sched        Scheduler
+	settings     UserSettingsSource
 }
 
-func NewHwTouchScreen() *HwTouchScreen {
-	return &HwTouchScreen{}
+func NewHwTouchScreen(settings UserSettingsSource, sched Scheduler) *HwTouchScreen {
+	return &HwTouchScreen{settings: settings, sched: sched, pressure: cTouchDefaultPressure}
 }
 
+// Pressure reported when the input device doesn't measure it (eg: mouse)
+const cTouchDefaultPressure = 0.5
+
+// Range of the resistance of the touch, relative to the resistance of the
+// X plate, respectively at the hardest and lightest pressure
+const (
+	cTouchMinResistance = 0.05
+	cTouchMaxResistance = 0.6
+)
+
 var tscChanNames = [8]string{
 	"temp0", "touch_y", "battery", "touch_z1",
 	"touch_z2", "touch_x", "aux", "temp1",
 }
 
+// SetPen sets the state of the pen for the frame that is about to be
+// emulated
 func (ff *HwTouchScreen) SetPen(down bool, x, y int) {
+	ff.interp = !ff.Raw && ff.sched != nil && down && ff.penDown
+	ff.prevX, ff.prevY = ff.penX, ff.penY
+	if ff.sched != nil {
+		ff.setAt = ff.sched.Cycles()
+	}
 	ff.penX = x
 	ff.penY = y
 	ff.penDown = down
 }
 
+// pen returns the pen position at the current time (see HwTouchScreen)
+func (ff *HwTouchScreen) pen() (int, int) {
+	if !ff.interp {
+		return ff.penX, ff.penY
+	}
+	cfg := NdsSyncConfig
+	frame := int64(cfg.HDots * cfg.VDots * cfg.DotClockDivider)
+	t := ff.sched.Cycles() - ff.setAt
+	if t >= frame {
+		return ff.penX, ff.penY
+	}
+	x := ff.prevX + int(int64(ff.penX-ff.prevX)*t/frame)
+	y := ff.prevY + int(int64(ff.penY-ff.prevY)*t/frame)
+	return x, y
+}
+
+// Sample returns the pen position as converted by the controller (in ADC
+// units, see adcX/adcY), and whether the pen is down.
+func (ff *HwTouchScreen) Sample() (x, y uint16, down bool) {
+	if !ff.penDown {
+		return 0, 0xFFF, false
+	}
+	us := ff.settings.UserSettings()
+	penX, penY := ff.pen()
+	return ff.adcX(us, penX), ff.adcY(us, penY), true
+}
+
+// SetPressure sets the pressure of the pen, from 0 (lightest) to 1 (hardest)
+func (ff *HwTouchScreen) SetPressure(p float64) {
+	if p < 0 {
+		p = 0
+	} else if p > 1 {
+		p = 1
+	}
+	ff.pressure = p
+}
+
+// pressureZ returns the values of the Z1 and Z2 channels corresponding to
+// the current pressure, for the specified X measurement. The controller
+// measures the resistance of the touch as:
+//
+//	Rtouch = Rxplate * X/4096 * (Z2/Z1 - 1)
+//
+// so a harder pressure (lower resistance) brings Z2 closer to Z1.
+func (ff *HwTouchScreen) pressureZ(adcX uint16) (uint16, uint16) {
+	if adcX == 0 {
+		adcX = 1
+	}
+	r := cTouchMaxResistance - ff.pressure*(cTouchMaxResistance-cTouchMinResistance)
+	ratio := 1 + r*4096/float64(adcX)
+	z1 := 0x400
+	if float64(z1)*ratio > 0xFFF {
+		z1 = int(0xFFF / ratio)
+	}
+	return uint16(z1), uint16(float64(z1)*ratio + 0.5)
+}
+
+// adcX/adcY return the values of the X and Y channels for a pen position.
+//
+// FIXME: this is surely wrong. It is reading the calibration values from
+// the RAM (where the firmware stores them) and performing a reverse mapping
+// to report the ADC values that correspond to the mouse position.
+// Surely the hardware is not aware of the calibration process and always
+// returns values in a certain fixed range (that might vary across
+// different units, but anyway).
+func (ff *HwTouchScreen) adcX(us []byte, penX int) uint16 {
+	adcX1 := binary.LittleEndian.Uint16(us[0x58:])
+	scrX1 := us[0x5C]
+	adcX2 := binary.LittleEndian.Uint16(us[0x5E:])
+	scrX2 := us[0x62]
+	return uint16((penX-int(scrX1)+1)*int(adcX2-adcX1)/int(scrX2-scrX1) + int(adcX1))
+}
+
+func (ff *HwTouchScreen) adcY(us []byte, penY int) uint16 {
+	adcY1 := binary.LittleEndian.Uint16(us[0x5A:])
+	scrY1 := us[0x5D]
+	adcY2 := binary.LittleEndian.Uint16(us[0x60:])
+	scrY2 := us[0x63]
+	return uint16((penY-int(scrY1)+1)*int(adcY2-adcY1)/int(scrY2-scrY1) + int(adcY1))
+}
+
 func (ff *HwTouchScreen) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 	cmd := data[0]
 	if cmd&0x80 == 0 {
@@ -50,37 +172,30 @@ func (ff *HwTouchScreen) SpiTransfer(data []byte) ([]byte, spi.ReqStatus) {
 	// Output value is always generated in the 12-bit range, and it is then
 	// optionally truncated to 8 bit
 	var output uint16
+	us := ff.settings.UserSettings()
+	penX, penY := ff.pen()
 	switch adchan {
 	case 0:
 		output = 0x800
 	case 1: // Y coord
 		if ff.penDown {
-			// FIXME: this is surely wrong. It is reading the calibration
-			// values from the RAM (where the firmware stores them) and
-			// performing a reverse mapping to report the ADC values that
-			// correspond to the mouse position.
-			// Surely the hardware is not aware of the calibration process
-			// and always returns values in a certain fixed range (that might
-			// vary across different units, but anyway).
-			adcY1 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x5A:])
-			scrY1 := Emu.Mem.Ram[0x3FFC80+0x5D]
-			adcY2 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x60:])
-			scrY2 := Emu.Mem.Ram[0x3FFC80+0x63]
-			output = uint16((ff.penY-int(scrY1)+1)*int(adcY2-adcY1)/int(scrY2-scrY1) + int(adcY1))
-
-			// log.Infof("coord Y:%d -> Out:%x", ff.penY, output)
+			output = ff.adcY(us, penY)
 		} else {
 			output = 0xFFF
 		}
+	case 3, 4: // pressure
+		if ff.penDown {
+			z1, z2 := ff.pressureZ(ff.adcX(us, penX))
+			output = z1
+			if adchan == 4 {
+				output = z2
+			}
+		} else if adchan == 4 {
+			output = 0xFFF
+		}
 	case 5: // X coord
 		if ff.penDown {
-			adcX1 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x58:])
-			scrX1 := Emu.Mem.Ram[0x3FFC80+0x5C]
-			adcX2 := binary.LittleEndian.Uint16(Emu.Mem.Ram[0x3FFC80+0x5E:])
-			scrX2 := Emu.Mem.Ram[0x3FFC80+0x62]
-			output = uint16((ff.penX-int(scrX1)+1)*int(adcX2-adcX1)/int(scrX2-scrX1) + int(adcX1))
-
-			// log.Infof("coord :%d -> Out:%x", ff.penC, output)
+			output = ff.adcX(us, penX)
 		} else {
 			output = 0x0
 		}
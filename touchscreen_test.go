@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+type testUserSettings []byte
+
+func (us testUserSettings) UserSettings() []byte { return us }
+
+func TestTouchScreenCalibration(t *testing.T) {
+	us := make(testUserSettings, cFwUserSettingsCrc)
+	binary.LittleEndian.PutUint16(us[0x58:], 0x200) // adc x1
+	binary.LittleEndian.PutUint16(us[0x5A:], 0x300) // adc y1
+	us[0x5C], us[0x5D] = 0x20, 0x20                 // scr x1,y1
+	binary.LittleEndian.PutUint16(us[0x5E:], 0xE00) // adc x2
+	binary.LittleEndian.PutUint16(us[0x60:], 0xD00) // adc y2
+	us[0x62], us[0x63] = 0xE0, 0xA0                 // scr x2,y2
+
+	tsc := NewHwTouchScreen(us, nil)
+	read := func(ch uint8) uint16 {
+		out, _ := tsc.SpiTransfer([]byte{0x80 | ch<<4})
+		return uint16(out[0])<<5 | uint16(out[1])>>3
+	}
+
+	for _, tc := range []struct {
+		x, y       int
+		adcx, adcy uint16
+		down       bool
+	}{
+		{0x1F, 0x1F, 0x200, 0x300, true},
+		{0xDF, 0x9F, 0xE00, 0xD00, true},
+		{0x7F, 0x5F, 0x800, 0x800, true},
+		{0x7F, 0x5F, 0x000, 0xFFF, false},
+	} {
+		tsc.SetPen(tc.down, tc.x, tc.y)
+		if x, y := read(5), read(1); x != tc.adcx || y != tc.adcy {
+			t.Errorf("pen (%d,%d,%v): got adc (%x,%x), want (%x,%x)", tc.x, tc.y, tc.down, x, y, tc.adcx, tc.adcy)
+		}
+	}
+}
+
+// testScheduler is a Scheduler whose time is set by the test
+type testScheduler struct{ cycles int64 }
+
+func (s *testScheduler) Cycles() int64                 { return s.cycles }
+func (s *testScheduler) DotPos() (int, int)            { return 0, 0 }
+func (s *testScheduler) DotPosDistance(x, y int) int64 { return 0 }
+func (s *testScheduler) ScheduleSync(when int64)       {}
+func (s *testScheduler) CancelSync(when int64)         {}
+func (s *testScheduler) ScheduleEvent(int64, func())   {}
+
+func TestTouchScreenInterpolation(t *testing.T) {
+	sched := new(testScheduler)
+	tsc := NewHwTouchScreen(nil, sched)
+	frame := int64(NdsSyncConfig.HDots * NdsSyncConfig.VDots * NdsSyncConfig.DotClockDivider)
+
+	// Pen down: no interpolation from the previous (stale) position
+	tsc.SetPen(true, 100, 50)
+	if x, y := tsc.pen(); x != 100 || y != 50 {
+		t.Errorf("pen down: got (%d,%d)", x, y)
+	}
+
+	// Drag: move linearly over the frame
+	sched.cycles = frame
+	tsc.SetPen(true, 200, 10)
+	sched.cycles += frame / 2
+	if x, y := tsc.pen(); x != 150 || y != 30 {
+		t.Errorf("half frame: got (%d,%d)", x, y)
+	}
+	sched.cycles += frame / 2
+	if x, y := tsc.pen(); x != 200 || y != 10 {
+		t.Errorf("end of frame: got (%d,%d)", x, y)
+	}
+
+	// Raw passthrough
+	tsc.Raw = true
+	tsc.SetPen(true, 0, 0)
+	if x, y := tsc.pen(); x != 0 || y != 0 {
+		t.Errorf("raw: got (%d,%d)", x, y)
+	}
+}
+
+func TestTouchScreenPressure(t *testing.T) {
+	tsc := NewHwTouchScreen(nil, nil)
+	resistance := func(adcX uint16) float64 {
+		z1, z2 := tsc.pressureZ(adcX)
+		if z1 == 0 || z2 > 0xFFF {
+			t.Fatalf("invalid Z values: %x %x", z1, z2)
+		}
+		return float64(adcX) / 4096 * (float64(z2)/float64(z1) - 1)
+	}
+
+	for _, adcX := range []uint16{0x100, 0x800, 0xF00} {
+		tsc.SetPressure(0)
+		light := resistance(adcX)
+		tsc.SetPressure(1)
+		hard := resistance(adcX)
+		if light < cTouchMaxResistance*0.95 || hard > cTouchMinResistance*1.2 || hard >= light {
+			t.Errorf("adc x %x: resistance %.3f (light), %.3f (hard)", adcX, light, hard)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"ndsemu/emu/hwio"
+	log "ndsemu/emu/logger"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+var modUart = log.NewModule("uart")
+
+const cUartQueueSize = 4096
+
+// UartHost is the host side of the emulated serial port: a TCP port
+// (accepting one client at a time), or a character device like a pty
+// (eg: created with "socat -d -d pty,raw,echo=0 pty,raw,echo=0").
+type UartHost struct {
+	mu   sync.Mutex
+	conn io.ReadWriteCloser
+	rx   chan byte
+}
+
+// NewUartHost creates the host side of the serial port from a specification
+// that is either "tcp:ADDRESS" (eg: "tcp:localhost:7778") to listen for
+// connections, or the path of a device to open.
+func NewUartHost(spec string) (*UartHost, error) {
+	h := &UartHost{rx: make(chan byte, cUartQueueSize)}
+	if addr := strings.TrimPrefix(spec, "tcp:"); addr != spec {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		go h.acceptLoop(ln)
+		return h, nil
+	}
+
+	if spec == "" {
+		return nil, errors.New("invalid serial port specification (use tcp:ADDRESS or a device path)")
+	}
+	f, err := os.OpenFile(spec, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	h.conn = f
+	go h.recvLoop(f)
+	return h, nil
+}
+
+func (h *UartHost) acceptLoop(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			modUart.ErrorZ("accept error").Error("err", err).End()
+			return
+		}
+		h.mu.Lock()
+		if h.conn != nil {
+			h.conn.Close()
+		}
+		h.conn = conn
+		h.mu.Unlock()
+		modUart.InfoZ("client connected").String("addr", conn.RemoteAddr().String()).End()
+		go h.recvLoop(conn)
+	}
+}
+
+func (h *UartHost) recvLoop(r io.Reader) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			select {
+			case h.rx <- b:
+			default:
+				modUart.WarnZ("receive queue full, dropping data").End()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Send transmits a byte to the host. Data is dropped if no client is
+// connected.
+func (h *UartHost) Send(b byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conn == nil {
+		return
+	}
+	if _, err := h.conn.Write([]byte{b}); err != nil {
+		modUart.WarnZ("send error").Error("err", err).End()
+		h.conn.Close()
+		h.conn = nil
+	}
+}
+
+// Recv returns the next byte received from the host, if any. It never blocks.
+func (h *UartHost) Recv() (byte, bool) {
+	select {
+	case b := <-h.rx:
+		return b, true
+	default:
+		return 0, false
+	}
+}
+
+// Pending reports whether there is received data waiting to be read
+func (h *UartHost) Pending() bool {
+	return len(h.rx) != 0
+}
+
+// HwUart emulates the serial port of the ARM7 in UART mode (RCNT bit 15
+// clear, SIOCNT bits 12-13 set). On retail units the port is not wired to
+// anything, but it is handy as a debug console for homebrew and test ROMs.
+// Only 8-bit data is supported; baud rate, parity and flow control are
+// ignored, and transfers complete immediately.
+type HwUart struct {
+	Host *UartHost // host side of the port (optional)
+
+	SioCnt   hwio.Reg16 `hwio:"offset=0x8,rwmask=0x7F8F,rcb,wcb"`
+	SioData8 hwio.Reg8  `hwio:"offset=0xA,rcb,wcb,readsensitive"`
+
+	irq  *HwIrq
+	rcnt *uint16
+}
+
+func NewHwUart(irq *HwIrq, rcnt *uint16) *HwUart {
+	uart := &HwUart{irq: irq, rcnt: rcnt}
+	hwio.MustInitRegs(uart)
+	return uart
+}
+
+func (uart *HwUart) enabled() bool {
+	return *uart.rcnt&(1<<15) == 0 && (uart.SioCnt.Value>>12)&3 == 3
+}
+
+func (uart *HwUart) irqEnabled() bool {
+	return uart.SioCnt.Value&(1<<14) != 0
+}
+
+func (uart *HwUart) ReadSIOCNT(val uint16) uint16 {
+	// Send FIFO is never full (bit 4), as data is sent immediately
+	val &^= 1<<4 | 1<<5 | 1<<6
+	if uart.Host == nil || !uart.Host.Pending() {
+		val |= 1 << 5 // receive FIFO empty
+	}
+	return val
+}
+
+func (uart *HwUart) WriteSIOCNT(old, val uint16) {
+	if (old^val)&(3<<12) != 0 && uart.enabled() {
+		modUart.InfoZ("UART mode enabled").Hex16("siocnt", val).End()
+	}
+}
+
+func (uart *HwUart) ReadSIODATA8(val uint8) uint8 {
+	if uart.Host == nil || !uart.enabled() || uart.SioCnt.Value&(1<<11) == 0 {
+		return val
+	}
+	if b, ok := uart.Host.Recv(); ok {
+		uart.SioData8.Value = b
+		return b
+	}
+	return val
+}
+
+func (uart *HwUart) WriteSIODATA8(_, val uint8) {
+	if !uart.enabled() || uart.SioCnt.Value&(1<<10) == 0 {
+		return
+	}
+	modUart.DebugZ("send").Hex8("data", val).End()
+	if uart.Host != nil {
+		uart.Host.Send(val)
+	}
+	if uart.irqEnabled() {
+		// The SIO interrupt shares the IRQ line with the RTC
+		uart.irq.Raise(IrqRtc)
+	}
+}
+
+// Poll is called once per frame, to trigger the receive interrupt if data
+// from the host is waiting to be read
+func (uart *HwUart) Poll() {
+	if uart.Host == nil || !uart.enabled() || !uart.irqEnabled() {
+		return
+	}
+	if uart.SioCnt.Value&(1<<11) != 0 && uart.Host.Pending() {
+		uart.irq.Raise(IrqRtc)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"ndsemu/emu/gfx"
+	"ndsemu/emu/hw"
+	log "ndsemu/emu/logger"
+	"os"
+	"path/filepath"
+)
+
+// VideoSink receives the video output of the emulator: the two screens, at
+// native resolution, at the end of each frame. Buffers are only valid during
+// the call. Sinks decouple rendering from presentation, so that the same
+// emulation loop can drive a window, or run headless.
+type VideoSink interface {
+	SubmitFrame(top, bottom gfx.Buffer)
+}
+
+// NullVideoSink discards all frames, for headless runs
+type NullVideoSink struct{}
+
+func (NullVideoSink) SubmitFrame(top, bottom gfx.Buffer) {}
+
+// WindowSink presents frames in the SDL window: screens are composed
+// according to the current layout of the emulator, with the performance HUD
+// and the OSD drawn on top.
+type WindowSink struct {
+	Out *hw.Output
+	Emu *NDSEmulator
+	Hud *PerfHud
+	Osd *Osd
+}
+
+func (s *WindowSink) SubmitFrame(top, bottom gfx.Buffer) {
+	v := s.Out.VideoBuffer()
+	s.Emu.Layout().ComposeScreens(v, top, bottom)
+	s.Hud.Draw(v)
+	s.Osd.Draw(v)
+	s.Out.SubmitVideo(v)
+}
+
+// PngVideoSink writes every frame to a PNG file within a directory (named
+// frame-NNNNNN.png, with frames numbered from 1), with the two screens on top
+// of each other, for offline analysis. If a file cannot be written, the error
+// is logged and the following frames are discarded.
+type PngVideoSink struct {
+	Dir   string
+	frame int
+	img   *image.RGBA
+	err   error
+}
+
+func NewPngVideoSink(dir string) (*PngVideoSink, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &PngVideoSink{
+		Dir: dir,
+		img: image.NewRGBA(image.Rect(0, 0, cScreenWidth, cScreenHeight*2)),
+	}, nil
+}
+
+func (s *PngVideoSink) SubmitFrame(top, bottom gfx.Buffer) {
+	s.frame++
+	if s.err != nil {
+		return
+	}
+	for i, screen := range []gfx.Buffer{top, bottom} {
+		for y := 0; y < cScreenHeight; y++ {
+			src := screen.Line(y)
+			dst := s.img.Pix[s.img.PixOffset(0, i*cScreenHeight+y):]
+			for x := 0; x < cScreenWidth; x++ {
+				pix := src.Get32(x)
+				dst[x*4+0] = uint8(pix)
+				dst[x*4+1] = uint8(pix >> 8)
+				dst[x*4+2] = uint8(pix >> 16)
+				dst[x*4+3] = 0xFF
+			}
+		}
+	}
+	fn := filepath.Join(s.Dir, fmt.Sprintf("frame-%06d.png", s.frame))
+	if s.err = writePng(fn, s.img); s.err != nil {
+		log.ModEmu.ErrorZ("cannot write frame").String("file", fn).Error("err", s.err).End()
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"ndsemu/emu/gfx"
+	"path/filepath"
+	"testing"
+)
+
+func TestPngVideoSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewPngVideoSink(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	native := gfx.NewBufferMem(cScreenWidth, cScreenHeight*2)
+	top, bottom := NativeScreens(native)
+	line := bottom.Line(10)
+	line.Set32(20, 0x123456)
+	sink.SubmitFrame(top, bottom)
+	sink.SubmitFrame(top, bottom)
+
+	img, err := readPng(filepath.Join(dir, "frame-000002.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, _ := img.At(20, cScreenHeight+10).RGBA(); r>>8 != 0x56 || g>>8 != 0x34 || b>>8 != 0x12 {
+		t.Errorf("invalid pixel: %x %x %x", r, g, b)
+	}
+}
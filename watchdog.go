@@ -0,0 +1,120 @@
+package main
+
+import (
+	"ndsemu/arm"
+	log "ndsemu/emu/logger"
+)
+
+// Size of the PC window (in bytes) within which a CPU is considered stuck.
+// It is large enough to contain a typical busy-wait loop.
+const cWatchdogWindow = 0x40
+
+// Watchdog detects guest hangs: a hang is assumed when, for a configurable
+// number of frames, both CPUs keep executing within a tiny PC window (eg:
+// spinning on a register waiting for an event that never happens, or
+// halted with all IRQs disabled).
+//
+// A CPU that halts waiting for an enabled IRQ is idle, not hung: this is how
+// games wait for VBlank, and the IRQ handler is usually too short to be seen
+// by the sampling, so the PC window alone can't tell it apart from a hang.
+//
+// PCs are sampled at each hsync, so the check has virtually no overhead.
+type Watchdog struct {
+	Timeout int  // frames without progress before reporting (0 = disabled)
+	Break   bool // break into the debugger on hang (requires the debugger)
+
+	pcmin, pcmax [2]uint32
+	halted       [2]bool
+	started      bool
+	frames       int
+	fired        bool
+}
+
+// Reset restarts hang detection from scratch (eg: after a console reset)
+func (wd *Watchdog) Reset() {
+	wd.started = false
+	wd.frames = 0
+	wd.fired = false
+}
+
+// Sample records the current PC and halt status of both CPUs
+func (wd *Watchdog) Sample(cpu9, cpu7 *arm.Cpu) {
+	pcs := [2]uint32{cpu9.GetPc(), cpu7.GetPc()}
+	halted := [2]bool{cpu9.Halted(), cpu7.Halted()}
+	if !wd.started {
+		wd.pcmin, wd.pcmax = pcs, pcs
+		wd.halted = halted
+		wd.started = true
+		return
+	}
+	for i, pc := range pcs {
+		wd.halted[i] = wd.halted[i] || halted[i]
+		if pc < wd.pcmin[i] {
+			wd.pcmin[i] = pc
+		}
+		if pc > wd.pcmax[i] {
+			wd.pcmax[i] = pc
+		}
+	}
+}
+
+// stuck reports whether the specified CPU has been running within the window,
+// without halting to wait for an IRQ that can wake it up
+func (wd *Watchdog) stuck(cpu CpuNum, irq *HwIrq) bool {
+	if wd.halted[cpu] && irq.Ime.Value&1 != 0 && irq.Ie.Value != 0 {
+		return false
+	}
+	return wd.pcmax[cpu]-wd.pcmin[cpu] <= cWatchdogWindow
+}
+
+// EndFrame checks whether the guest is making progress, and reports
+// a possible hang if not.
+func (wd *Watchdog) EndFrame(emu *NDSEmulator) {
+	if wd.Timeout == 0 {
+		return
+	}
+
+	// In GBA mode, the ARM9 is not running, so only check the ARM7
+	stuck := wd.stuck(CpuNds7, nds7.Irq) && (emu.Mode != ModeNds || wd.stuck(CpuNds9, nds9.Irq))
+	if !stuck {
+		// Progress was made: restart tracking from the current position
+		wd.started = false
+		wd.frames = 0
+		wd.fired = false
+		return
+	}
+
+	wd.frames++
+	if wd.frames < wd.Timeout || wd.fired {
+		return
+	}
+	wd.fired = true
+
+	ipc := emu.Hw.Ipc
+	log.ModEmu.WarnZ("possible hang").
+		Int("frames", wd.frames).
+		Hex32("pc9", nds9.Cpu.GetPc()).
+		Hex32("pc9-min", wd.pcmin[CpuNds9]).
+		Hex32("pc9-max", wd.pcmax[CpuNds9]).
+		Hex32("pc7", nds7.Cpu.GetPc()).
+		Hex32("pc7-min", wd.pcmin[CpuNds7]).
+		Hex32("pc7-max", wd.pcmax[CpuNds7]).
+		End()
+	// Log entries have a limited number of fields, so the state that might
+	// explain the hang goes in a separate entry
+	log.ModEmu.WarnZ("possible hang: interrupts and FIFOs").
+		Bool("ime9", nds9.Irq.Ime.Value&1 != 0).
+		Hex32("ie9", nds9.Irq.Ie.Value).
+		Hex32("if9", nds9.Irq.If.Value).
+		Bool("ime7", nds7.Irq.Ime.Value&1 != 0).
+		Hex32("ie7", nds7.Irq.Ie.Value).
+		Hex32("if7", nds7.Irq.If.Value).
+		Int("ipcfifo9", len(ipc.data[CpuNds9].fifo)).
+		Int("ipcfifo7", len(ipc.data[CpuNds7].fifo)).
+		Int("gxfifo", emu.Hw.Geom.fifo.Len()).
+		End()
+
+	if wd.Break {
+		emu.DebugBreak("watchdog: possible hang")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"ndsemu/arm"
+	"testing"
+)
+
+func TestWatchdogHaltLoop(t *testing.T) {
+	// The test programs spin forever in a tiny loop: that's a hang
+	emu := newTestEmulator(t)
+	emu.Watchdog.Timeout = 2
+	runFrames(emu, 4)
+	if !emu.Watchdog.fired {
+		t.Fatal("spinning CPUs not reported as hung")
+	}
+
+	// CPUs halted waiting for an enabled IRQ are idle
+	emu = newTestEmulator(t)
+	emu.Watchdog.Timeout = 2
+	for _, irq := range []*HwIrq{nds9.Irq, nds7.Irq} {
+		irq.Ime.Value = 1
+		irq.Ie.Value = 1 << 0 // VBlank
+	}
+	halt := func() {
+		nds9.Cpu.SetLine(arm.LineHalt, true)
+		nds7.Cpu.SetLine(arm.LineHalt, true)
+	}
+	for i := 0; i < 4; i++ {
+		halt()
+		runFrames(emu, 1)
+	}
+	if emu.Watchdog.fired {
+		t.Error("halted CPUs waiting for IRQs reported as hung")
+	}
+
+	// ...but not if no IRQ can wake them up
+	nds9.Irq.Ie.Value, nds7.Irq.Ie.Value = 0, 0
+	for i := 0; i < 4; i++ {
+		halt()
+		runFrames(emu, 1)
+	}
+	if !emu.Watchdog.fired {
+		t.Error("halted CPUs with IRQs disabled not reported as hung")
+	}
+}
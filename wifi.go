@@ -10,16 +10,43 @@ import (
 var modWifi = log.NewModule("wifi")
 
 type HwWifi struct {
+	Irq    *HwIrq
+	Link   *WifiLink   // experimental HLE link for local multiplayer (optional)
+	DlHost *DlPlayHost // download play host, serving the link (optional)
+
+	FakeAps     []FakeAp // APs whose beacons are injected (optional)
+	fakeApClock uint64
+	fakeApNext  uint64
+	fakeApSeq   uint16
+
+	WId       hwio.Reg16 `hwio:"offset=0x00,readonly,reset=0x1440"`
+	WIf       hwio.Reg16 `hwio:"offset=0x10,wcb"`
+	WIe       hwio.Reg16 `hwio:"offset=0x12,wcb"`
+	WMacAddr0 hwio.Reg16 `hwio:"offset=0x18"`
+	WMacAddr1 hwio.Reg16 `hwio:"offset=0x1A"`
+	WMacAddr2 hwio.Reg16 `hwio:"offset=0x1C"`
+
 	WRxBufBegin  hwio.Reg16 `hwio:"offset=0x50"`
 	WRxBufEnd    hwio.Reg16 `hwio:"offset=0x52"`
+	WRxBufWrCsr  hwio.Reg16 `hwio:"offset=0x54,rwmask=0xFFF"`
 	WRxBufRdAddr hwio.Reg16 `hwio:"offset=0x58,rwmask=0x1FFF"`
-	WRxBufRdData hwio.Reg16 `hwio:"offset=0x60,readonly,rcb"`
+	WRxBufRdData hwio.Reg16 `hwio:"offset=0x60,readonly,rcb,readsensitive"`
 
 	WTxBufWrAddr  hwio.Reg16 `hwio:"offset=0x68,rwmask=0x1FFF"`
 	WTxBufWrData  hwio.Reg16 `hwio:"offset=0x70,writeonly,wcb"`
 	WTxBufGapTop  hwio.Reg16 `hwio:"offset=0x74,rwmask=0x1FFF"`
 	WTxBufGapDisp hwio.Reg16 `hwio:"offset=0x76,rwmask=0xFFF"`
 
+	WTxBufBeacon hwio.Reg16 `hwio:"offset=0x80"`
+	WBeaconInt   hwio.Reg16 `hwio:"offset=0x8C,rwmask=0x3FF"`
+	WTxBufLoc1   hwio.Reg16 `hwio:"offset=0xA0"`
+	WTxBufLoc2   hwio.Reg16 `hwio:"offset=0xA4"`
+	WTxBufLoc3   hwio.Reg16 `hwio:"offset=0xA8"`
+	WTxReqReset  hwio.Reg16 `hwio:"offset=0xAC,writeonly,wcb"`
+	WTxReqSet    hwio.Reg16 `hwio:"offset=0xAE,writeonly,wcb"`
+	WTxReqRead   hwio.Reg16 `hwio:"offset=0xB0,readonly"`
+	beaconClock  float64
+
 	BaseBandCnt   hwio.Reg16 `hwio:"offset=0x158,wcb"`
 	BaseBandWrite hwio.Reg16 `hwio:"offset=0x15A,writeonly"`
 	BaseBandRead  hwio.Reg16 `hwio:"offset=0x15C,readonly"`
@@ -29,14 +56,25 @@ type HwWifi struct {
 	bbRegWritable [256]bool
 	bbRegs        [256]uint8
 
-	Random hwio.Reg16 `hwio:"offset=0x044,readonly,rcb"`
+	WPowerUs    hwio.Reg16 `hwio:"offset=0x036,rwmask=0x3"`
+	WPowerState hwio.Reg16 `hwio:"offset=0x03C,rwmask=0x3,reset=0x200,wcb"`
+	WPowerForce hwio.Reg16 `hwio:"offset=0x040,rwmask=0x8001,wcb"`
+
+	RfData2 hwio.Reg16 `hwio:"offset=0x17C"`
+	RfData1 hwio.Reg16 `hwio:"offset=0x17E,wcb"`
+	RfBusy  hwio.Reg16 `hwio:"offset=0x180,readonly"`
+	RfCnt   hwio.Reg16 `hwio:"offset=0x184,rwmask=0x413F,reset=0x18"`
+	rfRegs  [32]uint32
+
+	Random hwio.Reg16 `hwio:"offset=0x044,readonly,rcb,readsensitive"`
 	rand   *rand.Rand
 
 	WifiRam hwio.Mem `hwio:"bank=1,offset=0,size=0x2000,rw8=off,rw16,rw32"`
 }
 
-func NewHwWifi() *HwWifi {
+func NewHwWifi(irq *HwIrq) *HwWifi {
 	wf := new(HwWifi)
+	wf.Irq = irq
 	hwio.MustInitRegs(wf)
 	wf.rand = rand.New(rand.NewSource(0))
 	wf.bbInit()
@@ -81,6 +119,56 @@ func (wf *HwWifi) WriteBASEBANDCNT(_, val uint16) {
 	}
 }
 
+// WriteWPOWERSTATE handles wakeup requests (bit 1): the MAC is powered up
+// immediately.
+func (wf *HwWifi) WriteWPOWERSTATE(_, val uint16) {
+	if val&2 != 0 {
+		wf.WPowerState.Value &^= 0x200
+	}
+}
+
+// WriteWPOWERFORCE forces the power state (bit 0: 1=down, 0=up) when bit 15
+// is set
+func (wf *HwWifi) WriteWPOWERFORCE(_, val uint16) {
+	if val&0x8000 != 0 {
+		wf.WPowerState.Value = wf.WPowerState.Value&^0x200 | (val&1)<<9
+	}
+}
+
+// WriteRFDATA1 starts a serial transfer to the RF chip (RF2958). The
+// command is formed by both data registers: bit 23 selects read (1) or
+// write (0), bits 18-22 are the register index, and bits 0-17 the data. The
+// transfer is completed immediately; reads return the register value in the
+// data bits.
+func (wf *HwWifi) WriteRFDATA1(_, val uint16) {
+	cmd := uint32(wf.RfData2.Value)<<16 | uint32(val)
+	idx := (cmd >> 18) & 0x1F
+	if cmd&(1<<23) != 0 {
+		data := wf.rfRegs[idx]
+		wf.RfData1.Value = uint16(data)
+		wf.RfData2.Value = wf.RfData2.Value&^3 | uint16(data>>16)&3
+		modWifi.InfoZ("RF read").Hex8("reg", uint8(idx)).Hex32("val", data).End()
+	} else {
+		wf.rfRegs[idx] = cmd & 0x3FFFF
+		modWifi.InfoZ("RF write").Hex8("reg", uint8(idx)).Hex32("val", wf.rfRegs[idx]).End()
+	}
+}
+
+// Unmapped is invoked for accesses to wifi registers that are not
+// emulated yet. Writes are ignored and reads return zero, like the unused
+// registers of the hardware; both are logged, to find out which registers
+// games need.
+func (wf *HwWifi) Unmapped(addr uint32, size int, write bool, val uint32) uint32 {
+	if write {
+		modWifi.WarnZ("write to unimplemented reg").
+			Hex32("addr", addr).Hex16("reg", uint16(addr&0xFFF)).Int("size", size).Hex32("val", val).End()
+		return 0
+	}
+	modWifi.WarnZ("read from unimplemented reg").
+		Hex32("addr", addr).Hex16("reg", uint16(addr&0xFFF)).Int("size", size).End()
+	return 0
+}
+
 func (wf *HwWifi) ReadRANDOM(_ uint16) uint16 {
 	return uint16(wf.rand.Uint32()) & 0x3FF
 }
@@ -107,3 +195,172 @@ func (wf *HwWifi) ReadWRXBUFRDDATA(_ uint16) uint16 {
 	wf.WRxBufRdAddr.Value = off
 	return val
 }
+
+// Bits of W_IF / W_IE
+const (
+	wifiIrqRxDone uint16 = 1 << 0
+	wifiIrqTxDone uint16 = 1 << 1
+)
+
+func (wf *HwWifi) raiseIrq(bits uint16) {
+	wf.WIf.Value |= bits
+	if wf.WIf.Value&wf.WIe.Value != 0 {
+		wf.Irq.Raise(IrqWifi)
+	}
+}
+
+func (wf *HwWifi) WriteWIF(old, val uint16) {
+	// Writing 1 acknowledges the interrupt
+	wf.WIf.Value = old &^ val
+}
+
+func (wf *HwWifi) WriteWIE(_, val uint16) {
+	if wf.WIf.Value&val != 0 {
+		wf.Irq.Raise(IrqWifi)
+	}
+}
+
+func (wf *HwWifi) WriteWTXREQRESET(_, val uint16) {
+	wf.WTxReqRead.Value &^= val
+}
+
+func (wf *HwWifi) WriteWTXREQSET(_, val uint16) {
+	wf.WTxReqRead.Value |= val
+	wf.txFlush()
+}
+
+// Transmit all the frames that are both requested (through W_TXREQ) and
+// enabled in their TX location register. Both the request and the enable
+// bit are cleared once the frame is sent.
+func (wf *HwWifi) txFlush() {
+	locs := [...]struct {
+		req uint16
+		reg *hwio.Reg16
+	}{
+		{1 << 0, &wf.WTxBufLoc1},
+		{1 << 2, &wf.WTxBufLoc2},
+		{1 << 3, &wf.WTxBufLoc3},
+	}
+
+	for _, l := range locs {
+		if wf.WTxReqRead.Value&l.req == 0 || l.reg.Value&0x8000 == 0 {
+			continue
+		}
+		wf.txFrame(l.reg.Value&0xFFF, true)
+		l.reg.Value &^= 0x8000
+		wf.WTxReqRead.Value &^= l.req
+		wf.raiseIrq(wifiIrqTxDone)
+	}
+}
+
+// Transmit the frame stored at the specified location (in halfwords) in
+// wifi RAM. Frames are preceded by a 12-byte TX header, that contains the
+// frame length (including FCS) at offset 0xA.
+func (wf *HwWifi) txFrame(loc uint16, status bool) {
+	ram := wf.WifiRam.Data
+	addr := int(loc) * 2
+
+	length := int(ram[(addr+0xA)&0x1FFF]) | int(ram[(addr+0xB)&0x1FFF])<<8
+	if length < 4 {
+		modWifi.ErrorZ("invalid TX frame length").Hex16("loc", loc).Int("len", length).End()
+		return
+	}
+
+	// Strip the FCS, which is computed by hardware
+	frame := make([]byte, length-4)
+	for i := range frame {
+		frame[i] = ram[(addr+12+i)&0x1FFF]
+	}
+
+	if status {
+		// Report success in the TX header
+		ram[addr&0x1FFF] = 1
+	}
+
+	modWifi.InfoZ("TX frame").Hex16("loc", loc).Int("len", len(frame)).End()
+	if wf.Link != nil && wf.DlHost == nil {
+		wf.Link.Send(frame)
+	}
+}
+
+// Store a received frame into the RX circular buffer, preceded by the
+// 12-byte RX header.
+func (wf *HwWifi) rxFrame(frame []byte) {
+	ram := wf.WifiRam.Data
+	begin := int(wf.WRxBufBegin.Value & 0x1FFE)
+	end := int(wf.WRxBufEnd.Value & 0x1FFE)
+	if begin >= end {
+		// RX buffer not configured yet
+		return
+	}
+
+	off := int(wf.WRxBufWrCsr.Value) * 2
+	if off < begin || off >= end {
+		off = begin
+	}
+	write := func(b byte) {
+		ram[off] = b
+		if off++; off >= end {
+			off = begin
+		}
+	}
+
+	var hdr [12]byte
+	binary.LittleEndian.PutUint16(hdr[6:], 0x14) // 2 Mbit/s
+	binary.LittleEndian.PutUint16(hdr[8:], uint16(len(frame)))
+	hdr[10] = 0x30 // RSSI
+	for _, b := range hdr {
+		write(b)
+	}
+	for _, b := range frame {
+		write(b)
+	}
+	for off&3 != 0 {
+		write(0)
+	}
+
+	wf.WRxBufWrCsr.Value = uint16(off / 2)
+	modWifi.InfoZ("RX frame").Int("len", len(frame)).End()
+	wf.raiseIrq(wifiIrqRxDone)
+}
+
+// Poll is called once per frame, to exchange frames with the HLE link (if
+// any) and to send beacons when configured. If the download play host is
+// active, it handles the link instead of the emulated MAC. Beacons of the fake APs (if any)
+// are injected as well.
+func (wf *HwWifi) Poll() {
+	if len(wf.FakeAps) != 0 {
+		wf.injectFakeAps()
+	}
+	if wf.Link == nil {
+		return
+	}
+	if wf.DlHost != nil {
+		for {
+			frame, ok := wf.Link.Recv()
+			if !ok {
+				break
+			}
+			wf.DlHost.Recv(frame, wf.Link.Send)
+		}
+		wf.DlHost.Poll(wf.Link.Send)
+		return
+	}
+
+	for {
+		frame, ok := wf.Link.Recv()
+		if !ok {
+			break
+		}
+		wf.rxFrame(frame)
+	}
+
+	// The beacon interval is in TU (1024 microseconds)
+	if wf.WTxBufBeacon.Value&0x8000 != 0 && wf.WBeaconInt.Value != 0 {
+		wf.beaconClock += 1000000.0 / 60
+		if wf.beaconClock >= float64(wf.WBeaconInt.Value)*1024 {
+			wf.beaconClock = 0
+			wf.txFrame(wf.WTxBufBeacon.Value&0xFFF, false)
+		}
+	}
+}
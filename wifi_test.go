@@ -0,0 +1,111 @@
+package main
+
+import (
+	"ndsemu/arm"
+	"ndsemu/emu/hwio"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestWifi() *HwWifi {
+	return NewHwWifi(NewHwIrq("irq7", arm.NewCpu(arm.ARMv4, hwio.NewTable("bus7"), false)))
+}
+
+// newTestWifiLink returns a link sending frames to the returned connection
+func newTestWifiLink(t *testing.T) (*WifiLink, *net.UDPConn) {
+	loopback := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	peer, err := net.ListenUDP("udp4", loopback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.ListenUDP("udp4", loopback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { peer.Close(); conn.Close() })
+	return &WifiLink{
+		conn:  conn,
+		peers: []*net.UDPAddr{peer.LocalAddr().(*net.UDPAddr)},
+		rx:    make(chan []byte, cWifiLinkQueueSize),
+	}, peer
+}
+
+// writeTxFrame stores a frame with its TX header at the specified location
+func writeTxFrame(wf *HwWifi, loc uint16, frame []byte) {
+	ram := wf.WifiRam.Data
+	addr := int(loc) * 2
+	ram[addr+0xA] = uint8(len(frame) + 4)
+	copy(ram[addr+12:], frame)
+}
+
+func TestWifiTxRequest(t *testing.T) {
+	wf := newTestWifi()
+	writeTxFrame(wf, 0x100, []byte("hello"))
+	wf.WTxBufLoc1.Value = 0x8000 | 0x100
+	wf.WriteWTXREQSET(0, 1<<0|1<<2)
+
+	// LOC1 was sent, LOC2 has nothing to send and stays requested
+	if wf.WTxBufLoc1.Value&0x8000 != 0 || wf.WTxReqRead.Value != 1<<2 {
+		t.Errorf("after TX: LOC1=%04x TXREQ=%04x", wf.WTxBufLoc1.Value, wf.WTxReqRead.Value)
+	}
+	if wf.WifiRam.Data[0x200] != 1 || wf.WIf.Value&wifiIrqTxDone == 0 {
+		t.Errorf("TX completion not reported")
+	}
+}
+
+func TestWifiBeaconInterval(t *testing.T) {
+	wf := newTestWifi()
+	link, peer := newTestWifiLink(t)
+	wf.Link = link
+	writeTxFrame(wf, 0x100, []byte("beacon"))
+	wf.WTxBufBeacon.Value = 0x8000 | 0x100
+	wf.WBeaconInt.Value = 100 // TU
+
+	// A beacon every 102.4 ms, that is one every 7 frames
+	for i := 0; i < 60; i++ {
+		wf.Poll()
+	}
+	beacons := 0
+	buf := make([]byte, 256)
+	for {
+		peer.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if _, _, err := peer.ReadFromUDP(buf); err != nil {
+			break
+		}
+		beacons++
+	}
+	if beacons != 60/7 {
+		t.Errorf("%d beacons sent in one second, want %d", beacons, 60/7)
+	}
+}
+
+func TestWifiRegs(t *testing.T) {
+	wf := newTestWifi()
+	bus := hwio.NewTable("bus7")
+	bus.MapBank(0x4808000, wf, 0)
+	bus.MapFallback(0x4808000, 0x4808FFF, wf)
+
+	// Registers that are not emulated ignore writes
+	bus.Write16(0x4808120, 0x1234)
+	if v := bus.Read16(0x4808120); v != 0 {
+		t.Errorf("unimplemented register: read %04x after write", v)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		addr      uint32
+		write, rd uint16
+	}{
+		{"W_ID", 0x4808000, 0xFFFF, 0x1440},
+		{"W_MACADDR_0", 0x4808018, 0x0900, 0x0900},
+		{"W_POWERSTATE", 0x480803C, 0x0000, 0x0200},
+		{"W_BEACONINT", 0x480808C, 0xFFFF, 0x03FF},
+		{"W_RF_CNT", 0x4808184, 0xFFFF, 0x413F},
+	} {
+		bus.Write16(tc.addr, tc.write)
+		if v := bus.Read16(tc.addr); v != tc.rd {
+			t.Errorf("%s: read %04x, want %04x", tc.name, v, tc.rd)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Interval between injected beacons, in microseconds (the standard interval
+// of 100 TU)
+const cFakeApBeaconInterval = 100 * 1024
+
+// FakeAp is an access point that doesn't exist: its beacons are injected
+// into the wifi RX buffer, so that the AP shows up in scan results. This
+// allows to navigate the connection setup screens of games (and of the
+// firmware) without emulating a full network stack; connecting to it will
+// obviously fail.
+type FakeAp struct {
+	Ssid    string
+	Channel int
+	Bssid   [6]byte
+}
+
+// ParseFakeAps parses a list of fake APs. Each line describes an AP as
+// "CHANNEL [BSSID] SSID", where the SSID is the rest of the line (and can
+// contain spaces); "#" at the start of a line begins a comment. If the
+// BSSID is omitted, a locally-administered address is generated. For
+// instance:
+//
+//	1  00:09:BF:12:34:56  NintendoSpot
+//	6  My Home Network
+func ParseFakeAps(r io.Reader) ([]FakeAp, error) {
+	var aps []FakeAp
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		first, rest := splitWord(line)
+		ch, err := strconv.Atoi(first)
+		if err != nil || ch < 1 || ch > 14 {
+			return nil, fmt.Errorf("line %d: invalid channel: %q", lineno, first)
+		}
+
+		ap := FakeAp{Channel: ch, Ssid: rest}
+		ap.Bssid = [6]byte{0x02, 0, 0, 0, 0, byte(len(aps) + 1)}
+		first, rest = splitWord(rest)
+		if mac, err := net.ParseMAC(first); err == nil && len(mac) == 6 {
+			copy(ap.Bssid[:], mac)
+			ap.Ssid = rest
+		}
+		if ap.Ssid == "" {
+			return nil, fmt.Errorf("line %d: missing SSID", lineno)
+		}
+		if len(ap.Ssid) > 32 {
+			return nil, fmt.Errorf("line %d: SSID too long (max 32 bytes)", lineno)
+		}
+		aps = append(aps, ap)
+	}
+	return aps, scanner.Err()
+}
+
+// splitWord splits the first word of a line from the rest of it
+func splitWord(line string) (string, string) {
+	if idx := strings.IndexAny(line, " \t"); idx >= 0 {
+		return line[:idx], strings.TrimSpace(line[idx:])
+	}
+	return line, ""
+}
+
+// BeaconFrame builds the 802.11 beacon frame (without FCS) sent by the AP,
+// with the specified sequence number and timestamp (in microseconds).
+func (ap *FakeAp) BeaconFrame(seq uint16, ts uint64) []byte {
+	frame := make([]byte, 24+12, 64)
+
+	// MAC header: broadcast from the AP
+	binary.LittleEndian.PutUint16(frame[0:], 0x0080) // management, beacon
+	copy(frame[4:10], []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	copy(frame[10:16], ap.Bssid[:])
+	copy(frame[16:22], ap.Bssid[:])
+	binary.LittleEndian.PutUint16(frame[22:], seq<<4)
+
+	// Fixed fields
+	binary.LittleEndian.PutUint64(frame[24:], ts)
+	binary.LittleEndian.PutUint16(frame[32:], cFakeApBeaconInterval/1024)
+	binary.LittleEndian.PutUint16(frame[34:], 0x0001) // ESS, open
+
+	// Information elements: SSID, supported rates (1/2 Mbit/s, basic),
+	// and DS parameter set (channel)
+	frame = append(frame, 0, byte(len(ap.Ssid)))
+	frame = append(frame, ap.Ssid...)
+	frame = append(frame, 1, 2, 0x82, 0x84)
+	frame = append(frame, 3, 1, byte(ap.Channel))
+	return frame
+}
+
+// injectFakeAps is called once per frame, and injects the beacons of all
+// the fake APs at every beacon interval. The channel the radio is tuned to
+// is not emulated, so beacons of all channels are received at once; games
+// scanning channels one by one will simply see the APs on every pass.
+func (wf *HwWifi) injectFakeAps() {
+	wf.fakeApClock += 1000000 / 60
+	if wf.fakeApClock < wf.fakeApNext {
+		return
+	}
+	wf.fakeApNext = wf.fakeApClock + cFakeApBeaconInterval
+
+	for i := range wf.FakeAps {
+		wf.rxFrame(wf.FakeAps[i].BeaconFrame(wf.fakeApSeq, wf.fakeApClock))
+		wf.fakeApSeq = (wf.fakeApSeq + 1) & 0xFFF
+	}
+}
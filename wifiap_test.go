@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseFakeAps(t *testing.T) {
+	aps, err := ParseFakeAps(strings.NewReader(`
+# comment
+1  00:09:BF:12:34:56  NintendoSpot
+6	My Home Network
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aps) != 2 {
+		t.Fatalf("got %d APs", len(aps))
+	}
+	if aps[0].Channel != 1 || aps[0].Ssid != "NintendoSpot" || aps[0].Bssid != [6]byte{0x00, 0x09, 0xBF, 0x12, 0x34, 0x56} {
+		t.Errorf("invalid AP: %+v", aps[0])
+	}
+	if aps[1].Channel != 6 || aps[1].Ssid != "My Home Network" || aps[1].Bssid != [6]byte{0x02, 0, 0, 0, 0, 2} {
+		t.Errorf("invalid AP: %+v", aps[1])
+	}
+
+	for _, bad := range []string{"0 foo", "x foo", "3", "3 00:11:22:33:44:55", "3 " + strings.Repeat("a", 33)} {
+		if _, err := ParseFakeAps(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+
+	frame := aps[1].BeaconFrame(5, 0)
+	ies := append([]byte{0, 15}, "My Home Network"...)
+	ies = append(ies, 1, 2, 0x82, 0x84, 3, 1, 6)
+	if frame[0] != 0x80 || frame[22] != 0x50 || !bytes.Equal(frame[36:], ies) {
+		t.Errorf("invalid beacon: % x", frame)
+	}
+}
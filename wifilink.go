@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Magic prefix of all datagrams exchanged over the link, used to discard
+// unrelated traffic.
+var wifiLinkMagic = []byte("NIFI")
+
+const cWifiLinkQueueSize = 64
+
+// WifiLink is a high-level shortcut for local multiplayer: instead of
+// emulating the radio, 802.11 frames transmitted by the wifi MAC are
+// forwarded as-is, over UDP on localhost, to other ndsemu instances. This
+// is enough for the NIFI protocol used by download play and multi-cart
+// games (beacons + data frames), which doesn't rely on accurate timing of
+// the air medium.
+type WifiLink struct {
+	conn  *net.UDPConn
+	peers []*net.UDPAddr
+	rx    chan []byte
+}
+
+// NewWifiLink creates a link from a specification in the form
+// "port:peer1[,peer2...]", where port is the local UDP port to listen on,
+// and peers are the UDP ports of the other instances.
+func NewWifiLink(spec string) (*WifiLink, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("invalid wifi link specification (use port:peer1,peer2,...)")
+	}
+
+	port, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid wifi link port: %q", parts[0])
+	}
+
+	link := &WifiLink{
+		rx: make(chan []byte, cWifiLinkQueueSize),
+	}
+	for _, p := range strings.Split(parts[1], ",") {
+		peer, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wifi link peer: %q", p)
+		}
+		link.peers = append(link.peers, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: peer})
+	}
+
+	link.conn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port})
+	if err != nil {
+		return nil, err
+	}
+
+	go link.recvLoop()
+	return link, nil
+}
+
+func (link *WifiLink) recvLoop() {
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := link.conn.ReadFromUDP(buf)
+		if err != nil {
+			modWifi.ErrorZ("link receive error").Error("err", err).End()
+			return
+		}
+		if n < len(wifiLinkMagic) || !bytes.Equal(buf[:len(wifiLinkMagic)], wifiLinkMagic) {
+			continue
+		}
+
+		frame := make([]byte, n-len(wifiLinkMagic))
+		copy(frame, buf[len(wifiLinkMagic):n])
+		select {
+		case link.rx <- frame:
+		default:
+			// Queue is full: the emulator is not keeping up, so just drop
+			// the frame as it would happen on air.
+			modWifi.WarnZ("link queue full, dropping frame").End()
+		}
+	}
+}
+
+// Send transmits a frame to all peers
+func (link *WifiLink) Send(frame []byte) {
+	pkt := append(append([]byte(nil), wifiLinkMagic...), frame...)
+	for _, peer := range link.peers {
+		if _, err := link.conn.WriteToUDP(pkt, peer); err != nil {
+			modWifi.WarnZ("link send error").Error("err", err).End()
+		}
+	}
+}
+
+// Recv returns the next received frame, if any. It never blocks.
+func (link *WifiLink) Recv() ([]byte, bool) {
+	select {
+	case frame := <-link.rx:
+		return frame, true
+	default:
+		return nil, false
+	}
+}